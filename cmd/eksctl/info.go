@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/utils/kubectl"
+	"github.com/weaveworks/eksctl/pkg/version"
+)
+
+// info describes this build of eksctl and its environment, for tooling
+type info struct {
+	EksctlVersion      string                       `json:"eksctlVersion"`
+	KubectlVersion     string                       `json:"kubectlVersion,omitempty"`
+	OS                 string                       `json:"os"`
+	Arch               string                       `json:"arch"`
+	KubernetesVersions api.KubernetesVersionSupport `json:"kubernetesVersions"`
+}
+
+func infoCmd(_ *cmdutils.FlagGrouping) *cobra.Command {
+	var infoOutput string
+	cmd := &cobra.Command{
+		Use:   "info",
+		Short: "Output the version of eksctl, kubectl and the supported Kubernetes versions",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return doInfo(infoOutput)
+		},
+	}
+	cmd.Flags().StringVarP(&infoOutput, "output", "o", "", "specifies the output format (valid option: json)")
+	return cmd
+}
+
+func doInfo(output string) error {
+	i := info{
+		EksctlVersion:      version.GetVersion(),
+		OS:                 runtime.GOOS,
+		Arch:               runtime.GOARCH,
+		KubernetesVersions: api.VersionSupport(),
+	}
+	if kubectlVersion, err := kubectl.ClientVersion(nil); err == nil {
+		i.KubectlVersion = kubectlVersion
+	} else {
+		logger.Debug("kubectl version: %s", err)
+	}
+
+	switch output {
+	case "":
+		kubectlVersion := i.KubectlVersion
+		if kubectlVersion == "" {
+			kubectlVersion = "not found"
+		}
+		fmt.Printf("eksctl version: %s\n", i.EksctlVersion)
+		fmt.Printf("kubectl version: %s\n", kubectlVersion)
+		fmt.Printf("OS: %s/%s\n", i.OS, i.Arch)
+		fmt.Printf("Kubernetes versions: %s (default %s, latest %s)\n", strings.Join(i.KubernetesVersions.Supported, ", "), i.KubernetesVersions.Default, i.KubernetesVersions.Latest)
+	case "json":
+		data, err := json.MarshalIndent(i, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	default:
+		return fmt.Errorf("unknown output: %s", output)
+	}
+	return nil
+}
@@ -3,8 +3,8 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 
-	"github.com/kris-nova/logger"
 	"github.com/spf13/cobra"
 	"github.com/weaveworks/eksctl/pkg/ctl/set"
 	"github.com/weaveworks/eksctl/pkg/ctl/unset"
@@ -18,9 +18,16 @@ import (
 	"github.com/weaveworks/eksctl/pkg/ctl/enable"
 	"github.com/weaveworks/eksctl/pkg/ctl/generate"
 	"github.com/weaveworks/eksctl/pkg/ctl/get"
+	"github.com/weaveworks/eksctl/pkg/ctl/replace"
+	"github.com/weaveworks/eksctl/pkg/ctl/rollback"
 	"github.com/weaveworks/eksctl/pkg/ctl/scale"
+	"github.com/weaveworks/eksctl/pkg/ctl/top"
 	"github.com/weaveworks/eksctl/pkg/ctl/update"
 	"github.com/weaveworks/eksctl/pkg/ctl/utils"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/metrics"
+	"github.com/weaveworks/eksctl/pkg/plugins"
+	"github.com/weaveworks/eksctl/pkg/utils/prompt"
 )
 
 func addCommands(rootCmd *cobra.Command, flagGrouping *cmdutils.FlagGrouping) {
@@ -28,18 +35,22 @@ func addCommands(rootCmd *cobra.Command, flagGrouping *cmdutils.FlagGrouping) {
 	rootCmd.AddCommand(get.Command(flagGrouping))
 	rootCmd.AddCommand(update.Command(flagGrouping))
 	rootCmd.AddCommand(upgrade.Command(flagGrouping))
+	rootCmd.AddCommand(rollback.Command(flagGrouping))
 	rootCmd.AddCommand(delete.Command(flagGrouping))
 	rootCmd.AddCommand(set.Command(flagGrouping))
 	rootCmd.AddCommand(unset.Command(flagGrouping))
 	rootCmd.AddCommand(scale.Command(flagGrouping))
 	rootCmd.AddCommand(drain.Command(flagGrouping))
-	if os.Getenv("EKSCTL_EXPERIMENTAL") == "true" {
+	rootCmd.AddCommand(replace.Command(flagGrouping))
+	rootCmd.AddCommand(top.Command(flagGrouping))
+	if cmdutils.ExperimentalEnabled() {
 		rootCmd.AddCommand(generate.Command(flagGrouping))
 		rootCmd.AddCommand(enable.Command(flagGrouping))
 	}
 	rootCmd.AddCommand(utils.Command(flagGrouping))
 	rootCmd.AddCommand(completion.Command(rootCmd))
 	rootCmd.AddCommand(versionCmd(flagGrouping))
+	rootCmd.AddCommand(infoCmd(flagGrouping))
 }
 
 func main() {
@@ -66,7 +77,30 @@ func main() {
 
 	colorValue := rootCmd.PersistentFlags().StringP("color", "C", "true", "toggle colorized logs (valid options: true, false, fabulous)")
 
+	rootCmd.PersistentFlags().StringVar(&logger.Format, "log-format", logger.TextFormat, "format of the logs (valid options: text, json)")
+	logLevel := rootCmd.PersistentFlags().String("log-level", "", "set log level by name, overriding --verbose (valid options: error, warning, info, debug)")
+	rootCmd.PersistentFlags().StringSliceVar(&logger.DebugScopes, "log-debug-scopes", nil, "only log debug messages of the given subsystems, e.g. cfn,nodegroup,vpc")
+	rootCmd.PersistentFlags().BoolVar(&logger.Redact, "log-redact", false, "redact AWS account IDs, including those in ARNs, from the logs")
+
+	rootCmd.PersistentFlags().BoolVarP(&prompt.Default.AssumeYes, "yes", "y", false, "answer all confirmations with yes and apply changes, as if --approve was set")
+	rootCmd.PersistentFlags().BoolVar(&prompt.Default.NonInteractive, "non-interactive", false, "fail instead of asking questions that weren't answered with --answer or --answers-file")
+	rootCmd.PersistentFlags().StringToStringVar(&prompt.Default.Answers, "answer", map[string]string{}, `answers to questions by ID (e.g. "mfa-token=123456")`)
+	answersFile := rootCmd.PersistentFlags().String("answers-file", "", "YAML or JSON file with answers to questions by ID")
+
+	metricsFile := rootCmd.PersistentFlags().String("metrics-file", "", "write a summary of the durations of operations and AWS API calls to a file, in Prometheus text format if the name ends with '.prom', otherwise as JSON")
+	otlpEndpoint := rootCmd.PersistentFlags().String("otlp-endpoint", os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"), "export traces of the operations to an OTLP/HTTP endpoint, e.g. http://localhost:4318")
+
 	cobra.OnInitialize(func() {
+		if err := configureLogging(*logLevel); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+			os.Exit(1)
+		}
+		if *answersFile != "" {
+			if err := prompt.Default.LoadAnswers(*answersFile); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+				os.Exit(1)
+			}
+		}
 		// Control colored output
 		logger.Color = *colorValue == "true"
 		logger.Fabulous = *colorValue == "fabulous"
@@ -76,9 +110,64 @@ func main() {
 
 	rootCmd.SetUsageFunc(flagGrouping.Usage)
 
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+	runPluginIfRequested(rootCmd, os.Args[1:])
+
+	metrics.Default.Start("eksctl")
+	cmd, err := rootCmd.ExecuteC()
+	metrics.Default.Finish(cmd.CommandPath(), err)
+	writeMetrics(*metricsFile, *otlpEndpoint)
+	if err != nil {
+		os.Exit(cmdutils.ExitCode(err))
+	}
+}
+
+func configureLogging(logLevel string) error {
+	if logger.Format != logger.TextFormat && logger.Format != logger.JSONFormat {
+		return fmt.Errorf("unknown log format %q, valid formats are: text, json", logger.Format)
+	}
+	if logLevel != "" {
+		level, err := logger.ParseLevel(logLevel)
+		if err != nil {
+			return err
+		}
+		logger.Level = level
+	}
+	return nil
+}
+
+// writeMetrics writes the metrics summary and exports traces, if requested;
+// failing to do so doesn't fail the command
+func writeMetrics(metricsFile, otlpEndpoint string) {
+	if metricsFile != "" {
+		if err := metrics.Default.WriteSummaryFile(metricsFile); err != nil {
+			logger.Warning(err.Error())
+		}
+	}
+	if otlpEndpoint != "" {
+		if err := metrics.Default.ExportOTLP(otlpEndpoint); err != nil {
+			logger.Warning(err.Error())
+		}
+	}
+}
+
+// runPluginIfRequested runs the plugin named by args, unless they name a
+// built-in command, and exits with the exit code of the plugin
+func runPluginIfRequested(rootCmd *cobra.Command, args []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return
+	}
+	if _, _, err := rootCmd.Find(args); err == nil {
+		return
+	}
+	plugin, pluginArgs, ok := plugins.Find(args)
+	if !ok {
+		return
+	}
+	code, err := plugin.Run(pluginArgs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: running plugin %q: %s\n", plugin.Path, err.Error())
 	}
+	os.Exit(code)
 }
 
 func checkCommand(rootCmd *cobra.Command) {
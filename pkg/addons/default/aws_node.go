@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/weaveworks/eksctl/pkg/addons"
 	appsv1 "k8s.io/api/apps/v1"
@@ -12,6 +11,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 const (
@@ -22,8 +22,8 @@ const (
 )
 
 // UpdateAWSNode will update the `aws-node` add-on and returns true
-// if an update is available.
-func UpdateAWSNode(rawClient kubernetes.RawClientInterface, region string, plan bool) (bool, error) {
+// if an update is available; the image is pulled from registryOverride when it's set
+func UpdateAWSNode(rawClient kubernetes.RawClientInterface, region, registryOverride string, plan bool) (bool, error) {
 	clusterDaemonSet, err := rawClient.ClientSet().AppsV1().DaemonSets(metav1.NamespaceSystem).Get(AWSNode, metav1.GetOptions{})
 	if err != nil {
 		if apierrs.IsNotFound(err) {
@@ -60,6 +60,7 @@ func UpdateAWSNode(rawClient kubernetes.RawClientInterface, region string, plan
 			if err := addons.UseRegionalImage(&daemonSet.Spec.Template, region); err != nil {
 				return false, err
 			}
+			addons.UseRegistryOverride(&daemonSet.Spec.Template, registryOverride)
 			tagMismatch, err = addons.ImageTagsDiffer(
 				container.Image,
 				clusterDaemonSet.Spec.Template.Spec.Containers[0].Image,
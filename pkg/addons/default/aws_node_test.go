@@ -54,7 +54,7 @@ var _ = Describe("default addons - aws-node", func() {
 		It("can update 1.12 sample to latest", func() {
 			rawClient.AssumeObjectsMissing = false
 
-			_, err := UpdateAWSNode(rawClient, "eu-west-2", false)
+			_, err := UpdateAWSNode(rawClient, "eu-west-2", "", false)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(rawClient.Collection.UpdatedItems()).To(HaveLen(4))
 			Expect(rawClient.Collection.CreatedItems()).To(HaveLen(10))
@@ -74,7 +74,7 @@ var _ = Describe("default addons - aws-node", func() {
 		It("can update 1.12 sample for different region", func() {
 			rawClient.ClientSetUseUpdatedObjects = false // must be set for subsequent UpdateAWSNode
 
-			_, err := UpdateAWSNode(rawClient, "us-east-1", false)
+			_, err := UpdateAWSNode(rawClient, "us-east-1", "", false)
 			Expect(err).ToNot(HaveOccurred())
 
 			rawClient.ClientSetUseUpdatedObjects = true // for verification of updated objects
@@ -88,7 +88,7 @@ var _ = Describe("default addons - aws-node", func() {
 		})
 		It("detects matching image version when determining plan", func() {
 			// updating from latest to latest needs no updating
-			needsUpdate, err := UpdateAWSNode(rawClient, "eu-west-2", true)
+			needsUpdate, err := UpdateAWSNode(rawClient, "eu-west-2", "", true)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(needsUpdate).To(BeFalse())
 		})
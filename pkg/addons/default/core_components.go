@@ -0,0 +1,87 @@
+package defaultaddons
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/eksaddons"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// the names of the core components when they are installed as EKS add-ons
+const (
+	KubeProxyAddon = "kube-proxy"
+	VPCCNIAddon    = "vpc-cni"
+	CoreDNSAddon   = "coredns"
+)
+
+// UpdateCoreComponents updates kube-proxy, aws-node and coredns to match controlPlaneVersion,
+// e.g. 1.14.9; each component installed as an EKS add-on is updated to the default version of
+// the add-on for the control plane, the others are updated like the update-kube-proxy,
+// update-aws-node and update-coredns utils commands do. It returns true if an update is
+// required in plan mode. Self-managed components pull their images from registryOverride when
+// it's set
+func UpdateCoreComponents(rawClient kubernetes.RawClientInterface, addonsAPI eksaddons.API, clusterName, region, registryOverride, controlPlaneVersion string, plan bool) (bool, error) {
+	v, err := semver.ParseTolerant(controlPlaneVersion)
+	if err != nil {
+		return false, errors.Wrapf(err, "parsing control plane version %q", controlPlaneVersion)
+	}
+	clusterVersion := fmt.Sprintf("%d.%d", v.Major, v.Minor)
+
+	installed, err := eksaddons.ListInstalled(addonsAPI, clusterName)
+	if err != nil {
+		return false, err
+	}
+	managed := map[string]*eksaddons.Addon{}
+	for _, addon := range installed {
+		managed[aws.StringValue(addon.AddonName)] = addon
+	}
+
+	components := []struct {
+		addonName   string
+		selfManaged func() (bool, error)
+	}{
+		{
+			addonName: KubeProxyAddon,
+			selfManaged: func() (bool, error) {
+				return UpdateKubeProxyImageTag(rawClient.ClientSet(), controlPlaneVersion, registryOverride, plan)
+			},
+		},
+		{
+			addonName: VPCCNIAddon,
+			selfManaged: func() (bool, error) {
+				return UpdateAWSNode(rawClient, region, registryOverride, plan)
+			},
+		},
+		{
+			addonName: CoreDNSAddon,
+			selfManaged: func() (bool, error) {
+				return UpdateCoreDNS(rawClient, region, registryOverride, controlPlaneVersion, plan)
+			},
+		},
+	}
+
+	updateRequired := false
+	for _, component := range components {
+		var (
+			required bool
+			err      error
+		)
+		if addon, ok := managed[component.addonName]; ok {
+			logger.Debug("%q is installed as an EKS add-on", component.addonName)
+			required, err = eksaddons.UpdateToDefaultVersion(addonsAPI, clusterName, addon, clusterVersion, plan)
+		} else {
+			logger.Debug("%q is self-managed", component.addonName)
+			required, err = component.selfManaged()
+		}
+		if err != nil {
+			return false, err
+		}
+		updateRequired = updateRequired || required
+	}
+	return updateRequired, nil
+}
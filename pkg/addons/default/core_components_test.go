@@ -0,0 +1,111 @@
+package defaultaddons_test
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/weaveworks/eksctl/pkg/addons/default"
+	"github.com/weaveworks/eksctl/pkg/eksaddons"
+)
+
+type fakeAddonsAPI struct {
+	eksaddons.API
+
+	installed      map[string]string
+	defaultVersion map[string]string
+	schemas        map[string]string
+	statuses       map[string]string
+	updates        map[string]string
+	configurations map[string]string
+	created        []*eksaddons.CreateAddonInput
+}
+
+func (f *fakeAddonsAPI) ListAddons(*eksaddons.ListAddonsInput) (*eksaddons.ListAddonsOutput, error) {
+	output := &eksaddons.ListAddonsOutput{}
+	for name := range f.installed {
+		output.Addons = append(output.Addons, aws.String(name))
+	}
+	return output, nil
+}
+
+func (f *fakeAddonsAPI) DescribeAddon(input *eksaddons.DescribeAddonInput) (*eksaddons.DescribeAddonOutput, error) {
+	return &eksaddons.DescribeAddonOutput{Addon: &eksaddons.Addon{
+		AddonName:    input.AddonName,
+		AddonVersion: aws.String(f.installed[*input.AddonName]),
+		Status:       aws.String(f.statuses[*input.AddonName]),
+	}}, nil
+}
+
+func (f *fakeAddonsAPI) DescribeAddonVersions(input *eksaddons.DescribeAddonVersionsInput) (*eksaddons.DescribeAddonVersionsOutput, error) {
+	return &eksaddons.DescribeAddonVersionsOutput{Addons: []*eksaddons.AddonInfo{{
+		AddonName: input.AddonName,
+		AddonVersions: []*eksaddons.AddonVersionInfo{{
+			AddonVersion: aws.String(f.defaultVersion[*input.AddonName]),
+			Compatibilities: []*eksaddons.Compatibility{{
+				ClusterVersion: input.KubernetesVersion,
+				DefaultVersion: aws.Bool(true),
+			}},
+		}},
+	}}}, nil
+}
+
+func (f *fakeAddonsAPI) UpdateAddon(input *eksaddons.UpdateAddonInput) (*eksaddons.UpdateAddonOutput, error) {
+	if input.AddonVersion != nil {
+		f.updates[*input.AddonName] = *input.AddonVersion
+	}
+	if input.ConfigurationValues != nil {
+		f.configurations[*input.AddonName] = *input.ConfigurationValues
+	}
+	return &eksaddons.UpdateAddonOutput{}, nil
+}
+
+func (f *fakeAddonsAPI) DescribeAddonConfiguration(input *eksaddons.DescribeAddonConfigurationInput) (*eksaddons.DescribeAddonConfigurationOutput, error) {
+	return &eksaddons.DescribeAddonConfigurationOutput{
+		AddonName:           input.AddonName,
+		ConfigurationSchema: aws.String(f.schemas[*input.AddonName]),
+	}, nil
+}
+
+func (f *fakeAddonsAPI) CreateAddon(input *eksaddons.CreateAddonInput) (*eksaddons.CreateAddonOutput, error) {
+	f.created = append(f.created, input)
+	f.installed[*input.AddonName] = *input.AddonVersion
+	return &eksaddons.CreateAddonOutput{}, nil
+}
+
+var _ = Describe("default addons - core components", func() {
+	var addonsAPI *fakeAddonsAPI
+
+	BeforeEach(func() {
+		addonsAPI = &fakeAddonsAPI{
+			installed: map[string]string{
+				KubeProxyAddon: "v1.14.9-eksbuild.1",
+				VPCCNIAddon:    "v1.6.0-eksbuild.1",
+				CoreDNSAddon:   "v1.6.6-eksbuild.1",
+			},
+			defaultVersion: map[string]string{
+				KubeProxyAddon: "v1.15.11-eksbuild.1",
+				VPCCNIAddon:    "v1.6.1-eksbuild.1",
+				CoreDNSAddon:   "v1.6.6-eksbuild.1",
+			},
+			updates: map[string]string{},
+		}
+	})
+
+	It("updates the components installed as EKS add-ons to their default versions", func() {
+		updateRequired, err := UpdateCoreComponents(nil, addonsAPI, "cluster-1", "eu-west-1", "", "1.15.11", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updateRequired).To(BeFalse())
+		Expect(addonsAPI.updates).To(Equal(map[string]string{
+			KubeProxyAddon: "v1.15.11-eksbuild.1",
+			VPCCNIAddon:    "v1.6.1-eksbuild.1",
+		}))
+	})
+
+	It("only reports the updates in plan mode", func() {
+		updateRequired, err := UpdateCoreComponents(nil, addonsAPI, "cluster-1", "eu-west-1", "", "1.15.11", true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updateRequired).To(BeTrue())
+		Expect(addonsAPI.updates).To(BeEmpty())
+	})
+})
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/weaveworks/eksctl/pkg/addons"
 	appsv1 "k8s.io/api/apps/v1"
@@ -14,6 +13,7 @@ import (
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 const (
@@ -24,8 +24,8 @@ const (
 )
 
 // UpdateCoreDNS will update the `coredns` add-on and returns true
-// if an update is available
-func UpdateCoreDNS(rawClient kubernetes.RawClientInterface, region, controlPlaneVersion string, plan bool) (bool, error) {
+// if an update is available; the image is pulled from registryOverride when it's set
+func UpdateCoreDNS(rawClient kubernetes.RawClientInterface, region, registryOverride, controlPlaneVersion string, plan bool) (bool, error) {
 	kubeDNSSevice, err := rawClient.ClientSet().CoreV1().Services(metav1.NamespaceSystem).Get(KubeDNS, metav1.GetOptions{})
 	if err != nil {
 		if apierrs.IsNotFound(err) {
@@ -68,6 +68,7 @@ func UpdateCoreDNS(rawClient kubernetes.RawClientInterface, region, controlPlane
 			if err := addons.UseRegionalImage(&deployment.Spec.Template, region); err != nil {
 				return false, err
 			}
+			addons.UseRegistryOverride(&deployment.Spec.Template, registryOverride)
 			tagMismatch, err = addons.ImageTagsDiffer(
 				deployment.Spec.Template.Spec.Containers[0].Image,
 				kubeDNSDeployment.Spec.Template.Spec.Containers[0].Image,
@@ -0,0 +1,66 @@
+package defaultaddons
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+
+	"github.com/weaveworks/eksctl/pkg/addons"
+)
+
+// kubeProxyImageFormat is the repository of kube-proxy, which is tagged with the platform
+// version of the cluster, e.g. v1.17.9, only known once it's created
+const kubeProxyImageFormat = "%s.dkr.ecr.%s.%s/eks/kube-proxy"
+
+// CoreComponentImages returns the images of aws-node, coredns and kube-proxy for a cluster
+// running controlPlaneVersion in region, as the EKS registry of region serves them
+func CoreComponentImages(region, controlPlaneVersion string) ([]string, error) {
+	awsNode, err := awsNodeImage(region)
+	if err != nil {
+		return nil, err
+	}
+	coreDNS, err := coreDNSImage(region, controlPlaneVersion)
+	if err != nil {
+		return nil, err
+	}
+	kubeProxy, err := addons.RegionalImage(kubeProxyImageFormat, region)
+	if err != nil {
+		return nil, err
+	}
+	return []string{awsNode, coreDNS, kubeProxy}, nil
+}
+
+func awsNodeImage(region string) (string, error) {
+	list, err := LoadAsset(AWSNode, "yaml")
+	if err != nil {
+		return "", err
+	}
+	for _, item := range list.Items {
+		daemonSet, ok := item.Object.(*appsv1.DaemonSet)
+		if !ok {
+			continue
+		}
+		imageParts := strings.Split(daemonSet.Spec.Template.Spec.Containers[0].Image, ":")
+		if len(imageParts) != 2 {
+			return "", fmt.Errorf("invalid container image: %s", daemonSet.Spec.Template.Spec.Containers[0].Image)
+		}
+		return addons.RegionalImage(awsNodeImageFormatPrefix+":"+imageParts[1], region)
+	}
+	return "", fmt.Errorf("no DaemonSet found in the manifest of %q", AWSNode)
+}
+
+func coreDNSImage(region, controlPlaneVersion string) (string, error) {
+	list, err := loadAssetCoreDNS(controlPlaneVersion + ".0")
+	if err != nil {
+		return "", err
+	}
+	for _, item := range list.Items {
+		deployment, ok := item.Object.(*appsv1.Deployment)
+		if !ok || deployment.Name != CoreDNS {
+			continue
+		}
+		return addons.RegionalImage(deployment.Spec.Template.Spec.Containers[0].Image, region)
+	}
+	return "", fmt.Errorf("no Deployment found in the manifest of %q", CoreDNS)
+}
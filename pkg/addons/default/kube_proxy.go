@@ -4,9 +4,10 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/printers"
 
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
@@ -19,8 +20,9 @@ const (
 	KubeProxy = "kube-proxy"
 )
 
-// UpdateKubeProxyImageTag updates image tag for kube-system:damoneset/kube-proxy based to match controlPlaneVersion
-func UpdateKubeProxyImageTag(clientSet kubernetes.Interface, controlPlaneVersion string, plan bool) (bool, error) {
+// UpdateKubeProxyImageTag updates image tag for kube-system:damoneset/kube-proxy based to match controlPlaneVersion,
+// the image is pulled from registryOverride when it's set
+func UpdateKubeProxyImageTag(clientSet kubernetes.Interface, controlPlaneVersion, registryOverride string, plan bool) (bool, error) {
 	printer := printers.NewJSONPrinter()
 
 	d, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(KubeProxy, metav1.GetOptions{})
@@ -47,8 +49,9 @@ func UpdateKubeProxyImageTag(clientSet kubernetes.Interface, controlPlaneVersion
 	}
 
 	desiredTag := "v" + controlPlaneVersion
+	desiredRepository := api.OverrideImageRegistry(imageParts[0], registryOverride)
 
-	if imageParts[1] == desiredTag {
+	if imageParts[0] == desiredRepository && imageParts[1] == desiredTag {
 		logger.Debug("imageParts = %v, desiredTag = %s", imageParts, desiredTag)
 		logger.Info("%q is already up-to-date", KubeProxy)
 		return false, nil
@@ -59,7 +62,7 @@ func UpdateKubeProxyImageTag(clientSet kubernetes.Interface, controlPlaneVersion
 		return true, nil
 	}
 
-	imageParts[1] = desiredTag
+	imageParts[0], imageParts[1] = desiredRepository, desiredTag
 	*image = strings.Join(imageParts, ":")
 
 	if err := printer.LogObj(logger.Debug, KubeProxy+" [updated] = \\\n%s\n", d); err != nil {
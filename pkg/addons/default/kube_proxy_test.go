@@ -38,15 +38,25 @@ var _ = Describe("default addons - kube-proxy", func() {
 		})
 
 		It("can update based on control plane version", func() {
-			_, err := UpdateKubeProxyImageTag(clientSet, "1.13.0", false)
+			_, err := UpdateKubeProxyImageTag(clientSet, "1.13.0", "", false)
 			Expect(err).ToNot(HaveOccurred())
 			check("v1.13.0")
 		})
 
 		It("can dry-run update based on control plane version", func() {
-			_, err := UpdateKubeProxyImageTag(clientSet, "1.13.1", true)
+			_, err := UpdateKubeProxyImageTag(clientSet, "1.13.1", "", true)
 			Expect(err).ToNot(HaveOccurred())
 			check("v1.12.6")
 		})
+
+		It("pulls the image from the registry override", func() {
+			needsUpdate, err := UpdateKubeProxyImageTag(clientSet, "1.12.6", "registry.example.com", false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(needsUpdate).To(BeFalse())
+
+			kubeProxy, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(KubeProxy, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(kubeProxy.Spec.Template.Spec.Containers[0].Image).To(Equal("registry.example.com/eks/kube-proxy:v1.12.6"))
+		})
 	})
 })
@@ -0,0 +1,196 @@
+package defaultaddons
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/weaveworks/eksctl/pkg/eksaddons"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+const kubeProxyConfig = "kube-proxy-config"
+
+// ManagedAddonMigration describes how a self-managed core component is adopted into an EKS
+// add-on, and which parts of its configuration are preserved or overridden by the add-on
+type ManagedAddonMigration struct {
+	AddonName string `json:"addonName"`
+	Version   string `json:"version"`
+	// ConfigurationValues are the configuration values preserved from the self-managed component
+	ConfigurationValues map[string]interface{} `json:"configurationValues,omitempty"`
+	Preserved           []string               `json:"preserved,omitempty"`
+	Overridden          []string               `json:"overridden,omitempty"`
+}
+
+// PlanManagedAddonsMigration returns the migrations of kube-proxy, aws-node and coredns to EKS
+// add-ons of their default versions for clusterVersion; components that are already EKS add-ons
+// or that aren't installed are skipped
+func PlanManagedAddonsMigration(clientSet kubernetes.Interface, addonsAPI eksaddons.API, clusterName, clusterVersion string) ([]*ManagedAddonMigration, error) {
+	installed, err := eksaddons.ListInstalled(addonsAPI, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	managed := map[string]bool{}
+	for _, addon := range installed {
+		managed[aws.StringValue(addon.AddonName)] = true
+	}
+
+	planners := []struct {
+		addonName string
+		plan      func(clientSet kubernetes.Interface, addonsAPI eksaddons.API, migration *ManagedAddonMigration) (bool, error)
+	}{
+		{KubeProxyAddon, planKubeProxyMigration},
+		{VPCCNIAddon, planVPCCNIMigration},
+		{CoreDNSAddon, planCoreDNSMigration},
+	}
+
+	var migrations []*ManagedAddonMigration
+	for _, planner := range planners {
+		if managed[planner.addonName] {
+			logger.Info("%q is already an EKS add-on", planner.addonName)
+			continue
+		}
+		version, err := eksaddons.DefaultVersion(addonsAPI, planner.addonName, clusterVersion)
+		if err != nil {
+			return nil, err
+		}
+		migration := &ManagedAddonMigration{
+			AddonName: planner.addonName,
+			Version:   version,
+		}
+		found, err := planner.plan(clientSet, addonsAPI, migration)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			logger.Warning("%q is not installed, skipping it", planner.addonName)
+			continue
+		}
+		sort.Strings(migration.Preserved)
+		sort.Strings(migration.Overridden)
+		migrations = append(migrations, migration)
+	}
+	return migrations, nil
+}
+
+// MigrateToManagedAddon creates the EKS add-on of a migration, overwriting the self-managed
+// component, and waits for it to become active
+func MigrateToManagedAddon(addonsAPI eksaddons.API, clusterName string, migration *ManagedAddonMigration, waitTimeout time.Duration) error {
+	input := &eksaddons.CreateAddonInput{
+		ClusterName:      &clusterName,
+		AddonName:        &migration.AddonName,
+		AddonVersion:     &migration.Version,
+		ResolveConflicts: aws.String(eksaddons.ResolveConflictsOverwrite),
+	}
+	if len(migration.ConfigurationValues) > 0 {
+		values, err := json.Marshal(migration.ConfigurationValues)
+		if err != nil {
+			return errors.Wrapf(err, "marshalling the configuration values of %q", migration.AddonName)
+		}
+		input.ConfigurationValues = aws.String(string(values))
+	}
+	return eksaddons.Create(addonsAPI, input, waitTimeout)
+}
+
+func planKubeProxyMigration(clientSet kubernetes.Interface, _ eksaddons.API, migration *ManagedAddonMigration) (bool, error) {
+	d, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(KubeProxy, metav1.GetOptions{})
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "getting %q", KubeProxy)
+	}
+	for _, container := range d.Spec.Template.Spec.Containers {
+		migration.Overridden = append(migration.Overridden, fmt.Sprintf("image %s", container.Image))
+	}
+
+	if _, err := clientSet.CoreV1().ConfigMaps(metav1.NamespaceSystem).Get(kubeProxyConfig, metav1.GetOptions{}); err == nil {
+		migration.Overridden = append(migration.Overridden, fmt.Sprintf("ConfigMap %s", kubeProxyConfig))
+	} else if !apierrs.IsNotFound(err) {
+		return false, errors.Wrapf(err, "getting ConfigMap %q", kubeProxyConfig)
+	}
+	return true, nil
+}
+
+// planVPCCNIMigration preserves the environment variables of aws-node the add-on accepts
+func planVPCCNIMigration(clientSet kubernetes.Interface, addonsAPI eksaddons.API, migration *ManagedAddonMigration) (bool, error) {
+	d, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(AWSNode, metav1.GetOptions{})
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "getting %q", AWSNode)
+	}
+
+	supported, err := eksaddons.ConfigurationProperties(addonsAPI, migration.AddonName, migration.Version, "env")
+	if err != nil {
+		return false, err
+	}
+
+	env := map[string]string{}
+	for _, container := range d.Spec.Template.Spec.Containers {
+		migration.Overridden = append(migration.Overridden, fmt.Sprintf("image %s", container.Image))
+		if container.Name != AWSNode {
+			continue
+		}
+		for _, envVar := range container.Env {
+			switch {
+			case envVar.ValueFrom != nil:
+				migration.Overridden = append(migration.Overridden, fmt.Sprintf("env %s", envVar.Name))
+			case supported[envVar.Name]:
+				env[envVar.Name] = envVar.Value
+				migration.Preserved = append(migration.Preserved, fmt.Sprintf("env %s", envVar.Name))
+			default:
+				migration.Overridden = append(migration.Overridden, fmt.Sprintf("env %s", envVar.Name))
+			}
+		}
+	}
+	if len(env) > 0 {
+		migration.ConfigurationValues = map[string]interface{}{"env": env}
+	}
+	return true, nil
+}
+
+// planCoreDNSMigration preserves the Corefile of coredns when the add-on accepts it
+func planCoreDNSMigration(clientSet kubernetes.Interface, addonsAPI eksaddons.API, migration *ManagedAddonMigration) (bool, error) {
+	d, err := clientSet.AppsV1().Deployments(metav1.NamespaceSystem).Get(CoreDNS, metav1.GetOptions{})
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "getting %q", CoreDNS)
+	}
+	for _, container := range d.Spec.Template.Spec.Containers {
+		migration.Overridden = append(migration.Overridden, fmt.Sprintf("image %s", container.Image))
+	}
+
+	cm, err := clientSet.CoreV1().ConfigMaps(metav1.NamespaceSystem).Get(CoreDNS, metav1.GetOptions{})
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return true, nil
+		}
+		return false, errors.Wrapf(err, "getting ConfigMap %q", CoreDNS)
+	}
+	corefile, ok := cm.Data["Corefile"]
+	if !ok {
+		return true, nil
+	}
+	supported, err := eksaddons.ConfigurationProperties(addonsAPI, migration.AddonName, migration.Version)
+	if err != nil {
+		return false, err
+	}
+	if supported["corefile"] {
+		migration.ConfigurationValues = map[string]interface{}{"corefile": corefile}
+		migration.Preserved = append(migration.Preserved, "Corefile")
+	} else {
+		migration.Overridden = append(migration.Overridden, "Corefile")
+	}
+	return true, nil
+}
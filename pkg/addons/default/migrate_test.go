@@ -0,0 +1,94 @@
+package defaultaddons_test
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	. "github.com/weaveworks/eksctl/pkg/addons/default"
+	"github.com/weaveworks/eksctl/pkg/eksaddons"
+)
+
+var _ = Describe("default addons - migration to EKS add-ons", func() {
+	var (
+		addonsAPI *fakeAddonsAPI
+		clientSet *fake.Clientset
+	)
+
+	podSpec := func(name, image string, env ...corev1.EnvVar) corev1.PodTemplateSpec {
+		return corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: name, Image: image, Env: env}}}}
+	}
+
+	BeforeEach(func() {
+		addonsAPI = &fakeAddonsAPI{
+			installed: map[string]string{KubeProxyAddon: "v1.15.11-eksbuild.1"},
+			defaultVersion: map[string]string{
+				VPCCNIAddon:  "v1.6.1-eksbuild.1",
+				CoreDNSAddon: "v1.6.6-eksbuild.1",
+			},
+			schemas: map[string]string{
+				VPCCNIAddon:  `{"properties": {"env": {"properties": {"WARM_IP_TARGET": {"type": "string"}}}}}`,
+				CoreDNSAddon: `{"properties": {"replicaCount": {"type": "integer"}}}`,
+			},
+		}
+		clientSet = fake.NewSimpleClientset(
+			&appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: AWSNode, Namespace: metav1.NamespaceSystem},
+				Spec: appsv1.DaemonSetSpec{Template: podSpec(AWSNode, "amazon-k8s-cni:v1.5.5",
+					corev1.EnvVar{Name: "WARM_IP_TARGET", Value: "5"},
+					corev1.EnvVar{Name: "CUSTOM_FLAG", Value: "true"},
+				)},
+			},
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: CoreDNS, Namespace: metav1.NamespaceSystem},
+				Spec:       appsv1.DeploymentSpec{Template: podSpec(CoreDNS, "coredns:v1.6.6")},
+			},
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: CoreDNS, Namespace: metav1.NamespaceSystem},
+				Data:       map[string]string{"Corefile": ".:53 {}"},
+			},
+		)
+	})
+
+	It("preserves the configuration the add-ons accept and reports what they override", func() {
+		migrations, err := PlanManagedAddonsMigration(clientSet, addonsAPI, "cluster-1", "1.15")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(migrations).To(Equal([]*ManagedAddonMigration{
+			{
+				AddonName:           VPCCNIAddon,
+				Version:             "v1.6.1-eksbuild.1",
+				ConfigurationValues: map[string]interface{}{"env": map[string]string{"WARM_IP_TARGET": "5"}},
+				Preserved:           []string{"env WARM_IP_TARGET"},
+				Overridden:          []string{"env CUSTOM_FLAG", "image amazon-k8s-cni:v1.5.5"},
+			},
+			{
+				AddonName:  CoreDNSAddon,
+				Version:    "v1.6.6-eksbuild.1",
+				Overridden: []string{"Corefile", "image coredns:v1.6.6"},
+			},
+		}))
+	})
+
+	It("creates the add-ons with their preserved configuration, overwriting conflicts", func() {
+		migration := &ManagedAddonMigration{
+			AddonName:           VPCCNIAddon,
+			Version:             "v1.6.1-eksbuild.1",
+			ConfigurationValues: map[string]interface{}{"env": map[string]string{"WARM_IP_TARGET": "5"}},
+		}
+		addonsAPI.statuses = map[string]string{VPCCNIAddon: eksaddons.AddonStatusActive}
+		Expect(MigrateToManagedAddon(addonsAPI, "cluster-1", migration, time.Minute)).To(Succeed())
+		Expect(addonsAPI.created).To(Equal([]*eksaddons.CreateAddonInput{{
+			ClusterName:         aws.String("cluster-1"),
+			AddonName:           aws.String(VPCCNIAddon),
+			AddonVersion:        aws.String("v1.6.1-eksbuild.1"),
+			ResolveConflicts:    aws.String(eksaddons.ResolveConflictsOverwrite),
+			ConfigurationValues: aws.String(`{"env":{"WARM_IP_TARGET":"5"}}`),
+		}}))
+	})
+})
@@ -0,0 +1,162 @@
+package defaultaddons
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/weaveworks/eksctl/pkg/eksaddons"
+	kubewrapper "github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+const (
+	enablePodENI           = "ENABLE_POD_ENI"
+	enablePrefixDelegation = "ENABLE_PREFIX_DELEGATION"
+)
+
+// SampleSecurityGroupPolicy is the name of the SecurityGroupPolicy created by
+// CreateSampleSecurityGroupPolicy
+const SampleSecurityGroupPolicy = "sample-security-group-policy"
+
+const sampleSecurityGroupPolicyManifest = `apiVersion: vpcresources.k8s.aws/v1beta1
+kind: SecurityGroupPolicy
+metadata:
+  name: %s
+  namespace: default
+spec:
+  podSelector:
+    matchLabels:
+      security-groups-for-pods: sample
+  securityGroups:
+    groupIds:
+      - %s
+`
+
+// EnablePodENI enables pod ENIs in the VPC CNI plugin, through the configuration values of the
+// vpc-cni add-on when it's installed as an EKS add-on, or the environment of aws-node otherwise
+func EnablePodENI(clientSet kubernetes.Interface, addonsAPI eksaddons.API, clusterName string) error {
+	installed, err := eksaddons.ListInstalled(addonsAPI, clusterName)
+	if err != nil {
+		return err
+	}
+	for _, addon := range installed {
+		if aws.StringValue(addon.AddonName) == VPCCNIAddon {
+			return enableAddonPodENI(addonsAPI, clusterName, addon)
+		}
+	}
+	return enableAWSNodePodENI(clientSet)
+}
+
+func enableAddonPodENI(addonsAPI eksaddons.API, clusterName string, addon *eksaddons.Addon) error {
+	values := map[string]interface{}{}
+	if configurationValues := aws.StringValue(addon.ConfigurationValues); configurationValues != "" {
+		if err := json.Unmarshal([]byte(configurationValues), &values); err != nil {
+			return errors.Wrapf(err, "parsing the configuration values of EKS add-on %q", VPCCNIAddon)
+		}
+	}
+	env, _ := values["env"].(map[string]interface{})
+	if env == nil {
+		env = map[string]interface{}{}
+	}
+	if env[enablePodENI] == "true" {
+		logger.Info("pod ENIs are already enabled in EKS add-on %q", VPCCNIAddon)
+		return nil
+	}
+	env[enablePodENI] = "true"
+	values["env"] = env
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return errors.Wrapf(err, "marshalling the configuration values of EKS add-on %q", VPCCNIAddon)
+	}
+	if _, err := addonsAPI.UpdateAddon(&eksaddons.UpdateAddonInput{
+		ClusterName:         &clusterName,
+		AddonName:           aws.String(VPCCNIAddon),
+		ConfigurationValues: aws.String(string(data)),
+		ResolveConflicts:    aws.String(eksaddons.ResolveConflictsPreserve),
+	}); err != nil {
+		return errors.Wrapf(err, "enabling pod ENIs in EKS add-on %q", VPCCNIAddon)
+	}
+	logger.Info("enabled pod ENIs in EKS add-on %q", VPCCNIAddon)
+	return nil
+}
+
+func enableAWSNodePodENI(clientSet kubernetes.Interface) error {
+	daemonSets := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem)
+	d, err := daemonSets.Get(AWSNode, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "getting %q", AWSNode)
+	}
+
+	container, err := awsNodeContainer(d)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for i, envVar := range container.Env {
+		if envVar.Name != enablePodENI {
+			continue
+		}
+		if envVar.Value == "true" {
+			logger.Info("pod ENIs are already enabled in %q", AWSNode)
+			return nil
+		}
+		container.Env[i] = corev1.EnvVar{Name: enablePodENI, Value: "true"}
+		found = true
+	}
+	if !found {
+		container.Env = append(container.Env, corev1.EnvVar{Name: enablePodENI, Value: "true"})
+	}
+
+	if _, err := daemonSets.Update(d); err != nil {
+		return errors.Wrapf(err, "enabling pod ENIs in %q", AWSNode)
+	}
+	logger.Info("enabled pod ENIs in %q", AWSNode)
+	return nil
+}
+
+// PrefixDelegationEnabled returns whether prefix delegation is enabled in the environment of
+// aws-node, which is also where the configuration values of the vpc-cni add-on end up
+func PrefixDelegationEnabled(clientSet kubernetes.Interface) (bool, error) {
+	d, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(AWSNode, metav1.GetOptions{})
+	if err != nil {
+		return false, errors.Wrapf(err, "getting %q", AWSNode)
+	}
+	container, err := awsNodeContainer(d)
+	if err != nil {
+		return false, err
+	}
+	for _, envVar := range container.Env {
+		if envVar.Name == enablePrefixDelegation {
+			return envVar.Value == "true", nil
+		}
+	}
+	return false, nil
+}
+
+func awsNodeContainer(d *appsv1.DaemonSet) (*corev1.Container, error) {
+	for i := range d.Spec.Template.Spec.Containers {
+		if d.Spec.Template.Spec.Containers[i].Name == AWSNode {
+			return &d.Spec.Template.Spec.Containers[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no %q container in DaemonSet %q", AWSNode, AWSNode)
+}
+
+// CreateSampleSecurityGroupPolicy creates a SecurityGroupPolicy that assigns securityGroupID to the
+// pods of the default namespace labelled with security-groups-for-pods=sample
+func CreateSampleSecurityGroupPolicy(rawClient *kubewrapper.RawClient, securityGroupID string) error {
+	manifest := fmt.Sprintf(sampleSecurityGroupPolicyManifest, SampleSecurityGroupPolicy, securityGroupID)
+	if err := rawClient.CreateOrReplace([]byte(manifest), false); err != nil {
+		return errors.Wrapf(err, "creating SecurityGroupPolicy %q", SampleSecurityGroupPolicy)
+	}
+	return nil
+}
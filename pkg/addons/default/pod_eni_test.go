@@ -0,0 +1,69 @@
+package defaultaddons_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	. "github.com/weaveworks/eksctl/pkg/addons/default"
+)
+
+var _ = Describe("default addons - pod ENIs", func() {
+	var (
+		addonsAPI *fakeAddonsAPI
+		clientSet *fake.Clientset
+	)
+
+	BeforeEach(func() {
+		addonsAPI = &fakeAddonsAPI{
+			installed:      map[string]string{},
+			updates:        map[string]string{},
+			configurations: map[string]string{},
+		}
+		clientSet = fake.NewSimpleClientset(&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: AWSNode, Namespace: metav1.NamespaceSystem},
+			Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name: AWSNode,
+				Env:  []corev1.EnvVar{{Name: "ENABLE_POD_ENI", Value: "false"}},
+			}}}}},
+		})
+	})
+
+	It("enables pod ENIs in the environment of a self-managed aws-node", func() {
+		Expect(EnablePodENI(clientSet, addonsAPI, "cluster-1")).To(Succeed())
+
+		d, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(AWSNode, metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d.Spec.Template.Spec.Containers[0].Env).To(Equal([]corev1.EnvVar{{Name: "ENABLE_POD_ENI", Value: "true"}}))
+	})
+
+	It("enables pod ENIs in the configuration values of the vpc-cni add-on", func() {
+		addonsAPI.installed[VPCCNIAddon] = "v1.7.5-eksbuild.1"
+
+		Expect(EnablePodENI(clientSet, addonsAPI, "cluster-1")).To(Succeed())
+		Expect(addonsAPI.configurations).To(Equal(map[string]string{VPCCNIAddon: `{"env":{"ENABLE_POD_ENI":"true"}}`}))
+
+		d, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(AWSNode, metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d.Spec.Template.Spec.Containers[0].Env[0].Value).To(Equal("false"))
+	})
+
+	It("reads whether prefix delegation is enabled from the environment of aws-node", func() {
+		enabled, err := PrefixDelegationEnabled(clientSet)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(enabled).To(BeFalse())
+
+		d, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(AWSNode, metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		d.Spec.Template.Spec.Containers[0].Env = append(d.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "ENABLE_PREFIX_DELEGATION", Value: "true"})
+		_, err = clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Update(d)
+		Expect(err).NotTo(HaveOccurred())
+
+		enabled, err = PrefixDelegationEnabled(clientSet)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(enabled).To(BeTrue())
+	})
+})
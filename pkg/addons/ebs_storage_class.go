@@ -0,0 +1,90 @@
+package addons
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+const (
+	ebsCSIProvisioner = "ebs.csi.aws.com"
+	// legacyGP2StorageClass is the default StorageClass of EKS clusters, provisioned by the
+	// in-tree EBS plugin
+	legacyGP2StorageClass = "gp2"
+	// IsDefaultStorageClassAnnotation marks the StorageClass of claims that don't name one
+	IsDefaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+)
+
+// CreateEBSStorageClass creates an encrypted gp3 StorageClass provisioned by the EBS CSI driver,
+// and when it's set as default, removes the default annotation of the in-tree gp2 class; an
+// existing StorageClass of the same name is left as it is, parameters of a StorageClass can't be
+// updated
+func CreateEBSStorageClass(clientSet kubernetes.Interface, config *api.AddonStorageClass) error {
+	setDefault := api.IsEnabled(config.SetDefault)
+
+	parameters := map[string]string{
+		"type":      "gp3",
+		"encrypted": "true",
+	}
+	if config.KMSKeyARN != "" {
+		parameters["kmsKeyId"] = config.KMSKeyARN
+	}
+	bindingMode := storagev1.VolumeBindingWaitForFirstConsumer
+	reclaimPolicy := corev1.PersistentVolumeReclaimDelete
+	storageClass := &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: config.Name,
+		},
+		Provisioner:          ebsCSIProvisioner,
+		Parameters:           parameters,
+		ReclaimPolicy:        &reclaimPolicy,
+		VolumeBindingMode:    &bindingMode,
+		AllowVolumeExpansion: api.Enabled(),
+	}
+	if setDefault {
+		storageClass.Annotations = map[string]string{IsDefaultStorageClassAnnotation: "true"}
+		if err := demoteStorageClass(clientSet, legacyGP2StorageClass); err != nil {
+			return err
+		}
+	}
+
+	_, err := clientSet.StorageV1().StorageClasses().Create(storageClass)
+	switch {
+	case apierrors.IsAlreadyExists(err):
+		logger.Warning("StorageClass %q already exists, it is left as it is", config.Name)
+	case err != nil:
+		return errors.Wrapf(err, "creating StorageClass %q", config.Name)
+	default:
+		logger.Info("created StorageClass %q", config.Name)
+	}
+	return nil
+}
+
+// demoteStorageClass sets the default annotation of a StorageClass to false, so that it doesn't
+// compete with the new default class
+func demoteStorageClass(clientSet kubernetes.Interface, name string) error {
+	storageClass, err := clientSet.StorageV1().StorageClasses().Get(name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return errors.Wrapf(err, "getting StorageClass %q", name)
+	}
+	if storageClass.Annotations[IsDefaultStorageClassAnnotation] != "true" {
+		return nil
+	}
+
+	patch := []byte(`{"metadata":{"annotations":{"` + IsDefaultStorageClassAnnotation + `":"false"}}}`)
+	if _, err := clientSet.StorageV1().StorageClasses().Patch(name, types.MergePatchType, patch); err != nil {
+		return errors.Wrapf(err, "removing the default annotation of StorageClass %q", name)
+	}
+	logger.Info("StorageClass %q is no longer the default", name)
+	return nil
+}
@@ -0,0 +1,70 @@
+package addons_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	. "github.com/weaveworks/eksctl/pkg/addons"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+var _ = Describe("EBS StorageClass", func() {
+	var clientSet *fake.Clientset
+
+	BeforeEach(func() {
+		clientSet = fake.NewSimpleClientset(&storagev1.StorageClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "gp2",
+				Annotations: map[string]string{IsDefaultStorageClassAnnotation: "true"},
+			},
+			Provisioner: "kubernetes.io/aws-ebs",
+		})
+	})
+
+	getStorageClass := func(name string) *storagev1.StorageClass {
+		storageClass, err := clientSet.StorageV1().StorageClasses().Get(name, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		return storageClass
+	}
+
+	It("creates an encrypted default gp3 class and demotes gp2", func() {
+		config := &api.AddonStorageClass{
+			Name:       "gp3",
+			KMSKeyARN:  "arn:aws:kms:us-west-2:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+			SetDefault: api.Enabled(),
+		}
+		Expect(CreateEBSStorageClass(clientSet, config)).To(Succeed())
+
+		gp3 := getStorageClass("gp3")
+		Expect(gp3.Provisioner).To(Equal("ebs.csi.aws.com"))
+		Expect(gp3.Parameters).To(Equal(map[string]string{
+			"type":      "gp3",
+			"encrypted": "true",
+			"kmsKeyId":  config.KMSKeyARN,
+		}))
+		Expect(*gp3.VolumeBindingMode).To(Equal(storagev1.VolumeBindingWaitForFirstConsumer))
+		Expect(gp3.Annotations).To(HaveKeyWithValue(IsDefaultStorageClassAnnotation, "true"))
+
+		Expect(getStorageClass("gp2").Annotations).To(HaveKeyWithValue(IsDefaultStorageClassAnnotation, "false"))
+	})
+
+	It("leaves gp2 as the default when the class isn't set as default", func() {
+		config := &api.AddonStorageClass{Name: "encrypted-gp3", SetDefault: api.Disabled()}
+		Expect(CreateEBSStorageClass(clientSet, config)).To(Succeed())
+
+		storageClass := getStorageClass("encrypted-gp3")
+		Expect(storageClass.Annotations).ToNot(HaveKey(IsDefaultStorageClassAnnotation))
+		Expect(storageClass.Parameters).ToNot(HaveKey("kmsKeyId"))
+
+		Expect(getStorageClass("gp2").Annotations).To(HaveKeyWithValue(IsDefaultStorageClassAnnotation, "true"))
+	})
+
+	It("leaves an existing class as it is", func() {
+		Expect(CreateEBSStorageClass(clientSet, &api.AddonStorageClass{Name: "gp2", SetDefault: api.Disabled()})).To(Succeed())
+		Expect(getStorageClass("gp2").Provisioner).To(Equal("kubernetes.io/aws-ebs"))
+	})
+})
@@ -0,0 +1,397 @@
+package addons
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	admv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	certsv1beta1 "k8s.io/api/certificates/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+const (
+	gmsaWebhookName       = "gmsa-webhook"
+	gmsaWebhookNamespace  = metav1.NamespaceSystem
+	gmsaWebhookImage      = "sigwindowstools/k8s-gmsa-webhook:v0.3.0"
+	gmsaWebhookCertSecret = "gmsa-webhook-certs"
+	gmsaCRDGroup          = "windows.k8s.io"
+
+	// GMSAWebhookNamespaceLabel opts the pods of a namespace out of the gMSA webhook when set
+	// to "disabled"
+	GMSAWebhookNamespaceLabel = "gmsa-webhook"
+)
+
+// NewGMSAWebhook creates a new GMSAWebhook, its image is pulled from registryOverride when
+// it's set
+func NewGMSAWebhook(rawClient kubernetes.RawClientInterface, clusterStatus *api.ClusterStatus, registryOverride string, planMode bool) *GMSAWebhook {
+	return &GMSAWebhook{
+		rawClient:        rawClient,
+		clusterStatus:    clusterStatus,
+		registryOverride: registryOverride,
+		planMode:         planMode,
+	}
+}
+
+// A GMSAWebhook deploys the GMSACredentialSpec CRD and the admission webhook that resolves
+// the credential specs of Windows pods using group Managed Service Accounts, and checks that
+// their service accounts are allowed to use them
+type GMSAWebhook struct {
+	rawClient        kubernetes.RawClientInterface
+	clusterStatus    *api.ClusterStatus
+	registryOverride string
+	planMode         bool
+}
+
+// Deploy deploys the gMSA webhook to the cluster, its certificate is signed by the CA of the
+// cluster, which the webhook configurations trust
+func (g *GMSAWebhook) Deploy() error {
+	if err := g.generateCert(); err != nil {
+		return errors.Wrap(err, "generating the certificate of the gMSA webhook")
+	}
+
+	objects := []runtime.Object{
+		g.makeCRD(),
+		g.makeServiceAccount(),
+		g.makeClusterRole(),
+		g.makeClusterRoleBinding(),
+		g.makeDeployment(),
+		g.makeService(),
+		g.makeMutatingWebhookConfiguration(),
+		g.makeValidatingWebhookConfiguration(),
+	}
+	for _, object := range objects {
+		if err := applyWebhookResource(g.rawClient, object, g.planMode); err != nil {
+			return errors.Wrap(err, "error installing the gMSA webhook")
+		}
+	}
+	return nil
+}
+
+// generateCert requests a certificate for the service of the webhook from the cluster and
+// stores it in a secret, unless the secret exists already
+func (g *GMSAWebhook) generateCert() error {
+	clientSet := g.rawClient.ClientSet()
+	_, err := clientSet.CoreV1().Secrets(gmsaWebhookNamespace).Get(gmsaWebhookCertSecret, metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+	if g.planMode {
+		logger.Info("(plan) would request a certificate for the gMSA webhook")
+		return nil
+	}
+
+	csrName := fmt.Sprintf("%s.%s", gmsaWebhookName, gmsaWebhookNamespace)
+	csrClientSet := clientSet.CertificatesV1beta1().CertificateSigningRequests()
+	// a request left from a previous attempt can't be reused without its private key
+	if err := csrClientSet.Delete(csrName, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	csrPEM, privateKey, err := generateCertReq(gmsaWebhookName, gmsaWebhookNamespace)
+	if err != nil {
+		return errors.Wrap(err, "generating CSR")
+	}
+	request := &certsv1beta1.CertificateSigningRequest{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "CertificateSigningRequest",
+			APIVersion: "certificates.k8s.io/v1beta1",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: csrName},
+		Spec: certsv1beta1.CertificateSigningRequestSpec{
+			Request: csrPEM,
+			Groups:  []string{"system:authenticated"},
+			Usages: []certsv1beta1.KeyUsage{
+				certsv1beta1.UsageDigitalSignature,
+				certsv1beta1.UsageKeyEncipherment,
+				certsv1beta1.UsageServerAuth,
+			},
+		},
+	}
+	if err := applyWebhookResource(g.rawClient, request, false); err != nil {
+		return errors.Wrap(err, "creating CertificateSigningRequest")
+	}
+
+	request.Status.Conditions = []certsv1beta1.CertificateSigningRequestCondition{
+		{
+			Type:           certsv1beta1.CertificateApproved,
+			LastUpdateTime: metav1.NewTime(time.Now()),
+			Message:        "This CSR was approved by eksctl",
+			Reason:         "eksctl-approve",
+		},
+	}
+	if _, err := csrClientSet.UpdateApproval(request); err != nil {
+		return errors.Wrap(err, "updating approval")
+	}
+
+	logger.Info("waiting for certificate to be available")
+	cert, err := watchCSRApproval(csrClientSet, csrName, certWaitTimeout)
+	if err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      gmsaWebhookCertSecret,
+			Namespace: gmsaWebhookNamespace,
+		},
+		Data: map[string][]byte{
+			"key": privateKey,
+			"crt": cert,
+		},
+	}
+	return applyWebhookResource(g.rawClient, secret, false)
+}
+
+func (g *GMSAWebhook) objectMeta() metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      gmsaWebhookName,
+		Namespace: gmsaWebhookNamespace,
+		Labels: map[string]string{
+			"app.kubernetes.io/name": gmsaWebhookName,
+		},
+	}
+}
+
+func (g *GMSAWebhook) clusterObjectMeta() metav1.ObjectMeta {
+	meta := g.objectMeta()
+	meta.Namespace = ""
+	return meta
+}
+
+func (g *GMSAWebhook) makeCRD() *apiextensionsv1beta1.CustomResourceDefinition {
+	return &apiextensionsv1beta1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "CustomResourceDefinition",
+			APIVersion: "apiextensions.k8s.io/v1beta1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "gmsacredentialspecs." + gmsaCRDGroup,
+		},
+		Spec: apiextensionsv1beta1.CustomResourceDefinitionSpec{
+			Group: gmsaCRDGroup,
+			// v1alpha1 is served for the credential specs created by earlier versions of the webhook
+			Versions: []apiextensionsv1beta1.CustomResourceDefinitionVersion{
+				{Name: "v1", Served: true, Storage: true},
+				{Name: "v1alpha1", Served: true, Storage: false},
+			},
+			Scope: apiextensionsv1beta1.ClusterScoped,
+			Names: apiextensionsv1beta1.CustomResourceDefinitionNames{
+				Kind:     "GMSACredentialSpec",
+				ListKind: "GMSACredentialSpecList",
+				Plural:   "gmsacredentialspecs",
+				Singular: "gmsacredentialspec",
+			},
+			Validation: &apiextensionsv1beta1.CustomResourceValidation{
+				OpenAPIV3Schema: &apiextensionsv1beta1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]apiextensionsv1beta1.JSONSchemaProps{
+						"credspec": {
+							Description: "the credential spec of the gMSA, as generated by New-CredentialSpec",
+							Type:        "object",
+						},
+					},
+					Required: []string{"credspec"},
+				},
+			},
+		},
+	}
+}
+
+func (g *GMSAWebhook) makeServiceAccount() *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ServiceAccount",
+			APIVersion: "v1",
+		},
+		ObjectMeta: g.objectMeta(),
+	}
+}
+
+func (g *GMSAWebhook) makeClusterRole() *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ClusterRole",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+		ObjectMeta: g.clusterObjectMeta(),
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{gmsaCRDGroup},
+				Resources: []string{"gmsacredentialspecs"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			{
+				// the webhook checks that service accounts are allowed to use the credential specs
+				APIGroups: []string{"authorization.k8s.io"},
+				Resources: []string{"localsubjectaccessreviews", "subjectaccessreviews"},
+				Verbs:     []string{"create"},
+			},
+		},
+	}
+}
+
+func (g *GMSAWebhook) makeClusterRoleBinding() *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ClusterRoleBinding",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+		ObjectMeta: g.clusterObjectMeta(),
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     gmsaWebhookName,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      gmsaWebhookName,
+				Namespace: gmsaWebhookNamespace,
+			},
+		},
+	}
+}
+
+func (g *GMSAWebhook) makeDeployment() *appsv1.Deployment {
+	// pod creation fails while the webhook is unavailable
+	replicas := int32(2)
+	meta := g.objectMeta()
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Deployment",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: meta,
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: meta.Labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: meta.Labels},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: gmsaWebhookName,
+					NodeSelector: map[string]string{
+						"beta.kubernetes.io/os": "linux",
+					},
+					Containers: []corev1.Container{
+						{
+							Name:  gmsaWebhookName,
+							Image: api.OverrideImageRegistry(gmsaWebhookImage, g.registryOverride),
+							Env: []corev1.EnvVar{
+								{Name: "TLS_KEY", Value: "/tls/key"},
+								{Name: "TLS_CRT", Value: "/tls/crt"},
+							},
+							Ports: []corev1.ContainerPort{{ContainerPort: 443}},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "tls", MountPath: "/tls", ReadOnly: true},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "tls",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{SecretName: gmsaWebhookCertSecret},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (g *GMSAWebhook) makeService() *corev1.Service {
+	meta := g.objectMeta()
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: meta,
+		Spec: corev1.ServiceSpec{
+			Ports:    []corev1.ServicePort{{Port: 443, TargetPort: intstr.FromInt(443)}},
+			Selector: meta.Labels,
+		},
+	}
+}
+
+func (g *GMSAWebhook) webhook(name, path string, operations ...admv1beta1.OperationType) admv1beta1.Webhook {
+	failurePolicy := admv1beta1.Fail
+	webhookPath := path
+	return admv1beta1.Webhook{
+		Name: name,
+		ClientConfig: admv1beta1.WebhookClientConfig{
+			Service: &admv1beta1.ServiceReference{
+				Name:      gmsaWebhookName,
+				Namespace: gmsaWebhookNamespace,
+				Path:      &webhookPath,
+			},
+			CABundle: g.clusterStatus.CertificateAuthorityData,
+		},
+		Rules: []admv1beta1.RuleWithOperations{
+			{
+				Operations: operations,
+				Rule: admv1beta1.Rule{
+					APIGroups:   []string{""},
+					APIVersions: []string{"v1"},
+					Resources:   []string{"pods"},
+				},
+			},
+		},
+		FailurePolicy: &failurePolicy,
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      GMSAWebhookNamespaceLabel,
+					Operator: metav1.LabelSelectorOpNotIn,
+					Values:   []string{"disabled"},
+				},
+			},
+		},
+	}
+}
+
+func (g *GMSAWebhook) makeMutatingWebhookConfiguration() *admv1beta1.MutatingWebhookConfiguration {
+	return &admv1beta1.MutatingWebhookConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "MutatingWebhookConfiguration",
+			APIVersion: "admissionregistration.k8s.io/v1beta1",
+		},
+		ObjectMeta: g.clusterObjectMeta(),
+		Webhooks: []admv1beta1.Webhook{
+			g.webhook("admission-webhook.windows-gmsa.sigs.k8s.io", "/mutate", admv1beta1.Create),
+		},
+	}
+}
+
+func (g *GMSAWebhook) makeValidatingWebhookConfiguration() *admv1beta1.ValidatingWebhookConfiguration {
+	return &admv1beta1.ValidatingWebhookConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ValidatingWebhookConfiguration",
+			APIVersion: "admissionregistration.k8s.io/v1beta1",
+		},
+		ObjectMeta: g.clusterObjectMeta(),
+		Webhooks: []admv1beta1.Webhook{
+			g.webhook("admission-webhook.windows-gmsa.sigs.k8s.io", "/validate", admv1beta1.Create, admv1beta1.Update),
+		},
+	}
+}
@@ -0,0 +1,84 @@
+package addons_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	admv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/weaveworks/eksctl/pkg/addons"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils"
+)
+
+var _ = Describe("gMSA webhook", func() {
+	var rawClient *testutils.FakeRawClient
+
+	BeforeEach(func() {
+		rawClient = testutils.NewFakeRawClient()
+		rawClient.AssumeObjectsMissing = true
+
+		// the certificate of the webhook was requested already
+		secret := &corev1.Secret{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Secret",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "gmsa-webhook-certs",
+				Namespace: metav1.NamespaceSystem,
+			},
+		}
+		resource, err := rawClient.NewRawResource(secret)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = resource.CreateOrReplace(false)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("deploys the CRD and the webhook trusting the CA of the cluster", func() {
+		clusterStatus := &api.ClusterStatus{CertificateAuthorityData: []byte("ca")}
+		Expect(NewGMSAWebhook(rawClient, clusterStatus, "", false).Deploy()).To(Succeed())
+
+		var (
+			crd        *apiextensionsv1beta1.CustomResourceDefinition
+			deployment *appsv1.Deployment
+			mutating   *admv1beta1.MutatingWebhookConfiguration
+			validating *admv1beta1.ValidatingWebhookConfiguration
+		)
+		for _, item := range rawClient.Collection.CreatedItems() {
+			switch object := item.(type) {
+			case *apiextensionsv1beta1.CustomResourceDefinition:
+				crd = object
+			case *appsv1.Deployment:
+				deployment = object
+			case *admv1beta1.MutatingWebhookConfiguration:
+				mutating = object
+			case *admv1beta1.ValidatingWebhookConfiguration:
+				validating = object
+			}
+		}
+
+		Expect(crd).ToNot(BeNil())
+		Expect(crd.Spec.Names.Kind).To(Equal("GMSACredentialSpec"))
+		Expect(crd.Spec.Scope).To(Equal(apiextensionsv1beta1.ClusterScoped))
+
+		Expect(deployment).ToNot(BeNil())
+		Expect(deployment.Spec.Template.Spec.NodeSelector).To(HaveKeyWithValue("beta.kubernetes.io/os", "linux"))
+		Expect(deployment.Spec.Template.Spec.Volumes[0].Secret.SecretName).To(Equal("gmsa-webhook-certs"))
+
+		Expect(mutating).ToNot(BeNil())
+		Expect(mutating.Webhooks).To(HaveLen(1))
+		Expect(mutating.Webhooks[0].ClientConfig.CABundle).To(Equal([]byte("ca")))
+		Expect(*mutating.Webhooks[0].ClientConfig.Service.Path).To(Equal("/mutate"))
+		Expect(mutating.Webhooks[0].NamespaceSelector.MatchExpressions[0].Key).To(Equal(GMSAWebhookNamespaceLabel))
+
+		Expect(validating).ToNot(BeNil())
+		Expect(validating.Webhooks).To(HaveLen(1))
+		Expect(*validating.Webhooks[0].ClientConfig.Service.Path).To(Equal("/validate"))
+		Expect(validating.Webhooks[0].Rules[0].Operations).To(ConsistOf(admv1beta1.Create, admv1beta1.Update))
+	})
+})
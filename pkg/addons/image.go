@@ -22,16 +22,35 @@ func awsDNSSuffixForRegion(region string) (string, error) {
 // UseRegionalImage sets the region and AWS DNS suffix for a container image
 // in format '%s.dkr.ecr.%s.%s/image:tag'
 func UseRegionalImage(spec *corev1.PodTemplateSpec, region string) error {
-	imageFormat := spec.Spec.Containers[0].Image
-	dnsSuffix, err := awsDNSSuffixForRegion(region)
+	regionalImage, err := RegionalImage(spec.Spec.Containers[0].Image, region)
 	if err != nil {
 		return err
 	}
-	regionalImage := fmt.Sprintf(imageFormat, api.EKSResourceAccountID(region), region, dnsSuffix)
 	spec.Spec.Containers[0].Image = regionalImage
 	return nil
 }
 
+// RegionalImage returns the image of the EKS registry of region for an image in format
+// '%s.dkr.ecr.%s.%s/image:tag'
+func RegionalImage(imageFormat, region string) (string, error) {
+	dnsSuffix, err := awsDNSSuffixForRegion(region)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(imageFormat, api.EKSResourceAccountID(region), region, dnsSuffix), nil
+}
+
+// UseRegistryOverride pulls the images of all the containers of a pod template from registry,
+// see api.OverrideImageRegistry
+func UseRegistryOverride(spec *corev1.PodTemplateSpec, registry string) {
+	for i := range spec.Spec.InitContainers {
+		spec.Spec.InitContainers[i].Image = api.OverrideImageRegistry(spec.Spec.InitContainers[i].Image, registry)
+	}
+	for i := range spec.Spec.Containers {
+		spec.Spec.Containers[i].Image = api.OverrideImageRegistry(spec.Spec.Containers[i].Image, registry)
+	}
+}
+
 // imageTag extracts the container image's tag.
 func imageTag(image string) (string, error) {
 	parts := strings.Split(image, ":")
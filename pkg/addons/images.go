@@ -0,0 +1,61 @@
+package addons
+
+import (
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+)
+
+// Images returns the images of the add-ons eksctl deploys for cfg, before containerRegistryOverride
+// is applied to them; installVPCController is whether the Windows VPC controller is installed
+func Images(cfg *api.ClusterConfig, region string, installVPCController bool) ([]string, error) {
+	var images []string
+	if installVPCController {
+		for _, assetFunc := range []assetFunc{vpcResourceControllerDepYamlBytes, vpcAdmissionWebhookDepYamlBytes} {
+			image, err := vpcControllerImage(assetFunc, region)
+			if err != nil {
+				return nil, err
+			}
+			images = append(images, image)
+		}
+	}
+	if cfg.HasWindowsGMSA() {
+		images = append(images, gmsaWebhookImage)
+	}
+	if cfg.HasNodeTerminationHandler() {
+		images = append(images, nodeTerminationHandlerImage)
+	}
+	for _, addon := range cfg.Addons {
+		if !api.IsEnabled(addon.SnapshotController) {
+			continue
+		}
+		release, err := SnapshotControllerReleaseFor(cfg.Metadata.Version)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, release.Image())
+	}
+	return images, nil
+}
+
+func vpcControllerImage(assetFunc assetFunc, region string) (string, error) {
+	manifest, err := assetFunc()
+	if err != nil {
+		return "", err
+	}
+	rawExtension, err := kubernetes.NewRawExtension(manifest)
+	if err != nil {
+		return "", err
+	}
+	deployment, ok := rawExtension.Object.(*appsv1.Deployment)
+	if !ok {
+		return "", &typeAssertionError{&appsv1.Deployment{}, rawExtension.Object}
+	}
+	image, err := RegionalImage(deployment.Spec.Template.Spec.Containers[0].Image, region)
+	if err != nil {
+		return "", errors.Wrap(err, "getting the image of the VPC controller")
+	}
+	return image, nil
+}
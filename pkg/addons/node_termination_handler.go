@@ -0,0 +1,257 @@
+package addons
+
+import (
+	"github.com/pkg/errors"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	nodeTerminationHandlerImage = "amazon/aws-node-termination-handler:v1.6.1"
+	nodeTerminationHandlerName  = api.NodeTerminationHandlerName
+)
+
+// NewNodeTerminationHandler creates a new NodeTerminationHandler, queueURL
+// is only used in queue mode; the image is pulled from registryOverride when it's set
+func NewNodeTerminationHandler(rawClient kubernetes.RawClientInterface, config *api.NodeTerminationHandler, queueURL, region, registryOverride string, planMode bool) *NodeTerminationHandler {
+	return &NodeTerminationHandler{
+		rawClient:        rawClient,
+		config:           config,
+		queueURL:         queueURL,
+		region:           region,
+		registryOverride: registryOverride,
+		planMode:         planMode,
+	}
+}
+
+// A NodeTerminationHandler deploys AWS Node Termination Handler to a cluster
+type NodeTerminationHandler struct {
+	rawClient        kubernetes.RawClientInterface
+	config           *api.NodeTerminationHandler
+	queueURL         string
+	region           string
+	registryOverride string
+	planMode         bool
+}
+
+// Deploy deploys AWS Node Termination Handler to the specified cluster
+func (n *NodeTerminationHandler) Deploy() error {
+	objects := []runtime.Object{n.makeClusterRole(), n.makeClusterRoleBinding()}
+
+	if n.config.Mode == api.NodeTerminationHandlerModeQueue {
+		if n.queueURL == "" {
+			return errors.New("queue URL must be set for Node Termination Handler in queue mode")
+		}
+		// in queue mode the service account is created along with its IAM role
+		objects = append(objects, n.makeDeployment())
+	} else {
+		objects = append(objects, n.makeServiceAccount(), n.makeDaemonSet())
+	}
+
+	for _, object := range objects {
+		if err := n.applyRawResource(object); err != nil {
+			return errors.Wrap(err, "error installing Node Termination Handler")
+		}
+	}
+	return nil
+}
+
+func (n *NodeTerminationHandler) applyRawResource(object runtime.Object) error {
+	rawResource, err := n.rawClient.NewRawResource(object)
+	if err != nil {
+		return err
+	}
+	msg, err := rawResource.CreateOrReplace(n.planMode)
+	if err != nil {
+		return err
+	}
+	logger.Info(msg)
+	return nil
+}
+
+func (n *NodeTerminationHandler) objectMeta() metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      nodeTerminationHandlerName,
+		Namespace: api.NodeTerminationHandlerNamespace,
+		Labels: map[string]string{
+			"app.kubernetes.io/name": nodeTerminationHandlerName,
+		},
+	}
+}
+
+func (n *NodeTerminationHandler) makeServiceAccount() *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ServiceAccount",
+			APIVersion: "v1",
+		},
+		ObjectMeta: n.objectMeta(),
+	}
+}
+
+func (n *NodeTerminationHandler) makeClusterRole() *rbacv1.ClusterRole {
+	meta := n.objectMeta()
+	meta.Namespace = ""
+	return &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ClusterRole",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+		ObjectMeta: meta,
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"nodes"},
+				Verbs:     []string{"get", "list", "patch", "update"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods"},
+				Verbs:     []string{"get", "list"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods/eviction"},
+				Verbs:     []string{"create"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"events"},
+				Verbs:     []string{"create", "patch"},
+			},
+			{
+				APIGroups: []string{"apps", "extensions"},
+				Resources: []string{"daemonsets"},
+				Verbs:     []string{"get"},
+			},
+		},
+	}
+}
+
+func (n *NodeTerminationHandler) makeClusterRoleBinding() *rbacv1.ClusterRoleBinding {
+	meta := n.objectMeta()
+	meta.Namespace = ""
+	return &rbacv1.ClusterRoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ClusterRoleBinding",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+		ObjectMeta: meta,
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     nodeTerminationHandlerName,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      nodeTerminationHandlerName,
+				Namespace: api.NodeTerminationHandlerNamespace,
+			},
+		},
+	}
+}
+
+func (n *NodeTerminationHandler) makeDaemonSet() *appsv1.DaemonSet {
+	podSpec := n.makePodSpec([]corev1.EnvVar{
+		{Name: "ENABLE_SPOT_INTERRUPTION_DRAINING", Value: "true"},
+		{Name: "ENABLE_SCHEDULED_EVENT_DRAINING", Value: "true"},
+		{Name: "ENABLE_REBALANCE_MONITORING", Value: "false"},
+	})
+	// the instance metadata of each node is only reachable from the node itself
+	podSpec.HostNetwork = true
+	podSpec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+	podSpec.Tolerations = []corev1.Toleration{{Operator: corev1.TolerationOpExists}}
+
+	return &appsv1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "DaemonSet",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: n.objectMeta(),
+		Spec: appsv1.DaemonSetSpec{
+			Selector: n.selector(),
+			Template: n.makePodTemplate(podSpec),
+		},
+	}
+}
+
+func (n *NodeTerminationHandler) makeDeployment() *appsv1.Deployment {
+	podSpec := n.makePodSpec([]corev1.EnvVar{
+		{Name: "ENABLE_SQS_TERMINATION_DRAINING", Value: "true"},
+		{Name: "QUEUE_URL", Value: n.queueURL},
+		{Name: "AWS_REGION", Value: n.region},
+		{Name: "CHECK_ASG_TAG_BEFORE_DRAINING", Value: "true"},
+		{Name: "MANAGED_ASG_TAG", Value: api.NodeTerminationHandlerManagedASGTag},
+	})
+	replicas := int32(1)
+
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Deployment",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: n.objectMeta(),
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: n.selector(),
+			Template: n.makePodTemplate(podSpec),
+		},
+	}
+}
+
+func (n *NodeTerminationHandler) selector() *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchLabels: n.objectMeta().Labels,
+	}
+}
+
+func (n *NodeTerminationHandler) makePodTemplate(podSpec corev1.PodSpec) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: n.objectMeta().Labels,
+		},
+		Spec: podSpec,
+	}
+}
+
+func (n *NodeTerminationHandler) makePodSpec(env []corev1.EnvVar) corev1.PodSpec {
+	fieldEnv := func(name, fieldPath string) corev1.EnvVar {
+		return corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: fieldPath},
+			},
+		}
+	}
+
+	commonEnv := []corev1.EnvVar{
+		fieldEnv("NODE_NAME", "spec.nodeName"),
+		fieldEnv("POD_NAME", "metadata.name"),
+		fieldEnv("NAMESPACE", "metadata.namespace"),
+		{Name: "DELETE_LOCAL_DATA", Value: "true"},
+		{Name: "IGNORE_DAEMON_SETS", Value: "true"},
+	}
+
+	return corev1.PodSpec{
+		ServiceAccountName: nodeTerminationHandlerName,
+		PriorityClassName:  "system-node-critical",
+		NodeSelector: map[string]string{
+			"kubernetes.io/os": "linux",
+		},
+		Containers: []corev1.Container{
+			{
+				Name:  nodeTerminationHandlerName,
+				Image: api.OverrideImageRegistry(nodeTerminationHandlerImage, n.registryOverride),
+				Env:   append(commonEnv, env...),
+			},
+		},
+	}
+}
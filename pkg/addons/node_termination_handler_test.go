@@ -0,0 +1,71 @@
+package addons_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/weaveworks/eksctl/pkg/addons"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Node Termination Handler", func() {
+	const queueURL = "https://sqs.us-west-2.amazonaws.com/123456789012/eksctl-test-cluster-queue"
+
+	var rawClient *testutils.FakeRawClient
+
+	BeforeEach(func() {
+		rawClient = testutils.NewFakeRawClient()
+		rawClient.AssumeObjectsMissing = true
+	})
+
+	envValue := func(env []corev1.EnvVar, name string) string {
+		for _, e := range env {
+			if e.Name == name {
+				return e.Value
+			}
+		}
+		return ""
+	}
+
+	It("deploys a DaemonSet in IMDS mode", func() {
+		config := &api.NodeTerminationHandler{Enabled: api.Enabled(), Mode: api.NodeTerminationHandlerModeIMDS}
+		Expect(NewNodeTerminationHandler(rawClient, config, "", "us-west-2", "", false).Deploy()).To(Succeed())
+
+		Expect(rawClient.Collection.CreatedItems()).To(HaveLen(4))
+		clientSet := rawClient.ClientSet()
+
+		daemonSet, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(api.NodeTerminationHandlerName, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		podSpec := daemonSet.Spec.Template.Spec
+		Expect(podSpec.HostNetwork).To(BeTrue())
+		Expect(envValue(podSpec.Containers[0].Env, "ENABLE_SPOT_INTERRUPTION_DRAINING")).To(Equal("true"))
+
+		_, err = clientSet.CoreV1().ServiceAccounts(metav1.NamespaceSystem).Get(api.NodeTerminationHandlerName, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("deploys a Deployment processing the queue in queue mode", func() {
+		config := &api.NodeTerminationHandler{Enabled: api.Enabled(), Mode: api.NodeTerminationHandlerModeQueue}
+		Expect(NewNodeTerminationHandler(rawClient, config, queueURL, "us-west-2", "", false).Deploy()).To(Succeed())
+
+		// the service account is created along with its IAM role
+		Expect(rawClient.Collection.CreatedItems()).To(HaveLen(3))
+		clientSet := rawClient.ClientSet()
+
+		deployment, err := clientSet.AppsV1().Deployments(metav1.NamespaceSystem).Get(api.NodeTerminationHandlerName, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		env := deployment.Spec.Template.Spec.Containers[0].Env
+		Expect(envValue(env, "QUEUE_URL")).To(Equal(queueURL))
+		Expect(envValue(env, "AWS_REGION")).To(Equal("us-west-2"))
+		Expect(envValue(env, "MANAGED_ASG_TAG")).To(Equal(api.NodeTerminationHandlerManagedASGTag))
+	})
+
+	It("requires the queue URL in queue mode", func() {
+		config := &api.NodeTerminationHandler{Enabled: api.Enabled(), Mode: api.NodeTerminationHandlerModeQueue}
+		Expect(NewNodeTerminationHandler(rawClient, config, "", "us-west-2", "", false).Deploy()).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,332 @@
+package addons
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+const (
+	snapshotControllerName      = "snapshot-controller"
+	snapshotControllerNamespace = metav1.NamespaceSystem
+	snapshotCRDGroup            = "snapshot.storage.k8s.io"
+)
+
+// SnapshotControllerRelease is a release of the external snapshotter pinned for a range of
+// Kubernetes versions
+type SnapshotControllerRelease struct {
+	// MinKubernetesVersion is the earliest Kubernetes version the release supports
+	MinKubernetesVersion string
+	// Version of the snapshot controller image
+	Version string
+	// APIVersion is the storage version of the snapshot CRDs
+	APIVersion string
+}
+
+// Image returns the image of the snapshot controller of the release
+func (r SnapshotControllerRelease) Image() string {
+	return "k8s.gcr.io/sig-storage/snapshot-controller:" + r.Version
+}
+
+// snapshotControllerReleases are ordered from the latest; VolumeSnapshots are beta from
+// Kubernetes 1.17 and GA from 1.20
+var snapshotControllerReleases = []SnapshotControllerRelease{
+	{MinKubernetesVersion: "1.20.0", Version: "v4.2.1", APIVersion: "v1"},
+	{MinKubernetesVersion: "1.17.0", Version: "v3.0.3", APIVersion: "v1beta1"},
+}
+
+// maxSnapshotControllerKubernetesVersion is the first Kubernetes version that no longer serves
+// apiextensions.k8s.io/v1beta1, which the CRDs are created with
+const maxSnapshotControllerKubernetesVersion = "1.22.0"
+
+// SnapshotControllerReleaseFor returns the release of the external snapshotter pinned for
+// kubernetesVersion
+func SnapshotControllerReleaseFor(kubernetesVersion string) (*SnapshotControllerRelease, error) {
+	v, err := semver.ParseTolerant(kubernetesVersion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing Kubernetes version %q", kubernetesVersion)
+	}
+	if v.GTE(semver.MustParse(maxSnapshotControllerKubernetesVersion)) {
+		return nil, fmt.Errorf("no snapshot controller release is pinned for Kubernetes %s", kubernetesVersion)
+	}
+	for _, release := range snapshotControllerReleases {
+		if v.GTE(semver.MustParse(release.MinKubernetesVersion)) {
+			release := release
+			return &release, nil
+		}
+	}
+	return nil, fmt.Errorf("VolumeSnapshots require Kubernetes 1.17 or later, the cluster runs %s", kubernetesVersion)
+}
+
+// NewSnapshotController creates a new SnapshotController for a cluster running kubernetesVersion,
+// the image is pulled from registryOverride when it's set
+func NewSnapshotController(rawClient kubernetes.RawClientInterface, kubernetesVersion, registryOverride string, planMode bool) *SnapshotController {
+	return &SnapshotController{
+		rawClient:         rawClient,
+		kubernetesVersion: kubernetesVersion,
+		registryOverride:  registryOverride,
+		planMode:          planMode,
+	}
+}
+
+// A SnapshotController deploys the CSI snapshot CRDs and the external snapshot controller,
+// which CSI drivers like the EBS CSI driver rely on for VolumeSnapshots but don't install
+type SnapshotController struct {
+	rawClient         kubernetes.RawClientInterface
+	kubernetesVersion string
+	registryOverride  string
+	planMode          bool
+}
+
+// Deploy deploys the snapshot CRDs and controller of the release pinned for the Kubernetes
+// version of the cluster
+func (s *SnapshotController) Deploy() error {
+	release, err := SnapshotControllerReleaseFor(s.kubernetesVersion)
+	if err != nil {
+		return err
+	}
+
+	objects := []runtime.Object{
+		s.makeCRD("VolumeSnapshotClass", "volumesnapshotclasses", apiextensionsv1beta1.ClusterScoped, release, "vsclass", "vsclasses"),
+		s.makeCRD("VolumeSnapshotContent", "volumesnapshotcontents", apiextensionsv1beta1.ClusterScoped, release, "vsc", "vscs"),
+		s.makeCRD("VolumeSnapshot", "volumesnapshots", apiextensionsv1beta1.NamespaceScoped, release, "vs"),
+		s.makeServiceAccount(),
+		s.makeClusterRole(),
+		s.makeClusterRoleBinding(),
+		s.makeLeaderElectionRole(),
+		s.makeLeaderElectionRoleBinding(),
+		s.makeDeployment(release),
+	}
+	for _, object := range objects {
+		if err := s.applyRawResource(object); err != nil {
+			return errors.Wrap(err, "error installing the snapshot controller")
+		}
+	}
+	return nil
+}
+
+func (s *SnapshotController) applyRawResource(object runtime.Object) error {
+	rawResource, err := s.rawClient.NewRawResource(object)
+	if err != nil {
+		return err
+	}
+	msg, err := rawResource.CreateOrReplace(s.planMode)
+	if err != nil {
+		return err
+	}
+	logger.Info(msg)
+	return nil
+}
+
+func (s *SnapshotController) objectMeta() metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      snapshotControllerName,
+		Namespace: snapshotControllerNamespace,
+		Labels: map[string]string{
+			"app.kubernetes.io/name": snapshotControllerName,
+		},
+	}
+}
+
+func (s *SnapshotController) clusterObjectMeta() metav1.ObjectMeta {
+	meta := s.objectMeta()
+	meta.Namespace = ""
+	return meta
+}
+
+// makeCRD makes a snapshot CRD; v1 releases still serve v1beta1 for the snapshots created by
+// earlier releases
+func (s *SnapshotController) makeCRD(kind, plural string, scope apiextensionsv1beta1.ResourceScope, release *SnapshotControllerRelease, shortNames ...string) *apiextensionsv1beta1.CustomResourceDefinition {
+	versions := []apiextensionsv1beta1.CustomResourceDefinitionVersion{
+		{Name: release.APIVersion, Served: true, Storage: true},
+	}
+	if release.APIVersion != "v1beta1" {
+		versions = append(versions, apiextensionsv1beta1.CustomResourceDefinitionVersion{Name: "v1beta1", Served: true, Storage: false})
+	}
+
+	crd := &apiextensionsv1beta1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "CustomResourceDefinition",
+			APIVersion: "apiextensions.k8s.io/v1beta1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: plural + "." + snapshotCRDGroup,
+		},
+		Spec: apiextensionsv1beta1.CustomResourceDefinitionSpec{
+			Group:    snapshotCRDGroup,
+			Versions: versions,
+			Scope:    scope,
+			Names: apiextensionsv1beta1.CustomResourceDefinitionNames{
+				Kind:       kind,
+				ListKind:   kind + "List",
+				Plural:     plural,
+				Singular:   strings.ToLower(kind),
+				ShortNames: shortNames,
+			},
+		},
+	}
+	if kind != "VolumeSnapshotClass" {
+		crd.Spec.Subresources = &apiextensionsv1beta1.CustomResourceSubresources{
+			Status: &apiextensionsv1beta1.CustomResourceSubresourceStatus{},
+		}
+	}
+	return crd
+}
+
+func (s *SnapshotController) makeServiceAccount() *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ServiceAccount",
+			APIVersion: "v1",
+		},
+		ObjectMeta: s.objectMeta(),
+	}
+}
+
+func (s *SnapshotController) makeClusterRole() *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ClusterRole",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+		ObjectMeta: s.clusterObjectMeta(),
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"persistentvolumes", "persistentvolumeclaims"},
+				Verbs:     []string{"get", "list", "watch", "update"},
+			},
+			{
+				APIGroups: []string{"storage.k8s.io"},
+				Resources: []string{"storageclasses"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"events"},
+				Verbs:     []string{"list", "watch", "create", "update", "patch"},
+			},
+			{
+				APIGroups: []string{snapshotCRDGroup},
+				Resources: []string{"volumesnapshotclasses"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			{
+				APIGroups: []string{snapshotCRDGroup},
+				Resources: []string{"volumesnapshotcontents", "volumesnapshotcontents/status"},
+				Verbs:     []string{"create", "get", "list", "watch", "update", "patch", "delete"},
+			},
+			{
+				APIGroups: []string{snapshotCRDGroup},
+				Resources: []string{"volumesnapshots", "volumesnapshots/status"},
+				Verbs:     []string{"get", "list", "watch", "update", "patch"},
+			},
+		},
+	}
+}
+
+func (s *SnapshotController) makeClusterRoleBinding() *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ClusterRoleBinding",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+		ObjectMeta: s.clusterObjectMeta(),
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     snapshotControllerName,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      snapshotControllerName,
+				Namespace: snapshotControllerNamespace,
+			},
+		},
+	}
+}
+
+func (s *SnapshotController) makeLeaderElectionRole() *rbacv1.Role {
+	return &rbacv1.Role{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Role",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+		ObjectMeta: s.objectMeta(),
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{"coordination.k8s.io"},
+				Resources: []string{"leases"},
+				Verbs:     []string{"get", "watch", "list", "delete", "update", "create"},
+			},
+		},
+	}
+}
+
+func (s *SnapshotController) makeLeaderElectionRoleBinding() *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "RoleBinding",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+		ObjectMeta: s.objectMeta(),
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     snapshotControllerName,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      snapshotControllerName,
+				Namespace: snapshotControllerNamespace,
+			},
+		},
+	}
+}
+
+func (s *SnapshotController) makeDeployment(release *SnapshotControllerRelease) *appsv1.Deployment {
+	// a standby replica takes over through leader election
+	replicas := int32(2)
+	meta := s.objectMeta()
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Deployment",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: meta,
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: meta.Labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: meta.Labels},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: snapshotControllerName,
+					NodeSelector: map[string]string{
+						"beta.kubernetes.io/os": "linux",
+					},
+					Containers: []corev1.Container{
+						{
+							Name:  snapshotControllerName,
+							Image: api.OverrideImageRegistry(release.Image(), s.registryOverride),
+							Args:  []string{"--v=5", "--leader-election=true"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,63 @@
+package addons_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+
+	. "github.com/weaveworks/eksctl/pkg/addons"
+	"github.com/weaveworks/eksctl/pkg/testutils"
+)
+
+var _ = Describe("Snapshot controller", func() {
+	var rawClient *testutils.FakeRawClient
+
+	BeforeEach(func() {
+		rawClient = testutils.NewFakeRawClient()
+		rawClient.AssumeObjectsMissing = true
+	})
+
+	It("pins the release to the Kubernetes version", func() {
+		release, err := SnapshotControllerReleaseFor("1.18")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(release.Version).To(Equal("v3.0.3"))
+		Expect(release.APIVersion).To(Equal("v1beta1"))
+
+		release, err = SnapshotControllerReleaseFor("1.21")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(release.Version).To(Equal("v4.2.1"))
+		Expect(release.APIVersion).To(Equal("v1"))
+
+		_, err = SnapshotControllerReleaseFor("1.15")
+		Expect(err).To(MatchError("VolumeSnapshots require Kubernetes 1.17 or later, the cluster runs 1.15"))
+	})
+
+	It("deploys the CRDs and the controller of the pinned release", func() {
+		Expect(NewSnapshotController(rawClient, "1.20", "", false).Deploy()).To(Succeed())
+
+		crds := map[string]*apiextensionsv1beta1.CustomResourceDefinition{}
+		var deployment *appsv1.Deployment
+		for _, item := range rawClient.Collection.CreatedItems() {
+			switch object := item.(type) {
+			case *apiextensionsv1beta1.CustomResourceDefinition:
+				crds[object.Spec.Names.Kind] = object
+			case *appsv1.Deployment:
+				deployment = object
+			}
+		}
+
+		Expect(crds).To(HaveLen(3))
+		Expect(crds["VolumeSnapshot"].Spec.Scope).To(Equal(apiextensionsv1beta1.NamespaceScoped))
+		Expect(crds["VolumeSnapshot"].Spec.Versions).To(ConsistOf(
+			apiextensionsv1beta1.CustomResourceDefinitionVersion{Name: "v1", Served: true, Storage: true},
+			apiextensionsv1beta1.CustomResourceDefinitionVersion{Name: "v1beta1", Served: true, Storage: false},
+		))
+		Expect(crds["VolumeSnapshotContent"].Spec.Scope).To(Equal(apiextensionsv1beta1.ClusterScoped))
+		Expect(crds["VolumeSnapshotClass"].Spec.Subresources).To(BeNil())
+
+		Expect(deployment).ToNot(BeNil())
+		Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(Equal("k8s.gcr.io/sig-storage/snapshot-controller:v4.2.1"))
+	})
+})
@@ -5,10 +5,10 @@ import (
 	"time"
 
 	"github.com/cloudflare/cfssl/csr"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes/typed/certificates/v1beta1"
 
@@ -28,22 +28,25 @@ const (
 	certWaitTimeout = 45 * time.Second
 )
 
-// NewVPCController creates a new VPCController
-func NewVPCController(rawClient kubernetes.RawClientInterface, clusterStatus *api.ClusterStatus, region string, planMode bool) *VPCController {
+// NewVPCController creates a new VPCController, its images are pulled from registryOverride
+// when it's set
+func NewVPCController(rawClient kubernetes.RawClientInterface, clusterStatus *api.ClusterStatus, region, registryOverride string, planMode bool) *VPCController {
 	return &VPCController{
-		rawClient:     rawClient,
-		clusterStatus: clusterStatus,
-		region:        region,
-		planMode:      planMode,
+		rawClient:        rawClient,
+		clusterStatus:    clusterStatus,
+		region:           region,
+		registryOverride: registryOverride,
+		planMode:         planMode,
 	}
 }
 
 // A VPCController deploys Windows VPC controller to a cluster
 type VPCController struct {
-	rawClient     kubernetes.RawClientInterface
-	clusterStatus *api.ClusterStatus
-	region        string
-	planMode      bool
+	rawClient        kubernetes.RawClientInterface
+	clusterStatus    *api.ClusterStatus
+	region           string
+	registryOverride string
+	planMode         bool
 }
 
 // Deploy deploys VPC controller to the specified cluster
@@ -288,11 +291,18 @@ func (v *VPCController) applyDeployment(manifests []byte) error {
 	if err := UseRegionalImage(&deployment.Spec.Template, v.region); err != nil {
 		return err
 	}
+	UseRegistryOverride(&deployment.Spec.Template, v.registryOverride)
 	return v.applyRawResource(rawExtension.Object)
 }
 
 func (v *VPCController) applyRawResource(object runtime.Object) error {
-	rawResource, err := v.rawClient.NewRawResource(object)
+	return applyWebhookResource(v.rawClient, object, v.planMode)
+}
+
+// applyWebhookResource creates or replaces an object, keeping the cluster IP of services and
+// the resource version of webhook configurations, which can't be replaced without them
+func applyWebhookResource(rawClient kubernetes.RawClientInterface, object runtime.Object, planMode bool) error {
+	rawResource, err := rawClient.NewRawResource(object)
 	if err != nil {
 		return err
 	}
@@ -323,9 +333,21 @@ func (v *VPCController) applyRawResource(object runtime.Object) error {
 			}
 			newObject.SetResourceVersion(mwc.GetResourceVersion())
 		}
+	case *admv1beta1.ValidatingWebhookConfiguration:
+		r, found, err := rawResource.Get()
+		if err != nil {
+			return err
+		}
+		if found {
+			vwc, ok := r.(*admv1beta1.ValidatingWebhookConfiguration)
+			if !ok {
+				return &typeAssertionError{&admv1beta1.ValidatingWebhookConfiguration{}, r}
+			}
+			newObject.SetResourceVersion(vwc.GetResourceVersion())
+		}
 	}
 
-	msg, err := rawResource.CreateOrReplace(v.planMode)
+	msg, err := rawResource.CreateOrReplace(planMode)
 	if err != nil {
 		return err
 	}
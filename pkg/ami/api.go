@@ -18,12 +18,14 @@ import (
 const (
 	ImageClassGeneral = iota
 	ImageClassGPU
+	ImageClassARM
 )
 
 // ImageClasses is a list of image class names
 var ImageClasses = []string{
 	"ImageClassGeneral",
 	"ImageClassGPU",
+	"ImageClassARM",
 }
 
 // Use checks if a given AMI ID is available in AWS EC2 as well as checking and populating RootDevice information
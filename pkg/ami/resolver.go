@@ -3,7 +3,8 @@ package ami
 import (
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
-	"github.com/kris-nova/logger"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 // MultiResolver is a Resolver that delegates to one or more Resolvers.
@@ -2,16 +2,15 @@ package ami
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/utils"
 )
 
@@ -74,8 +73,8 @@ func MakeSSMParameterName(version, instanceType, imageFamily string) (string, er
 // instanceEC2ArchName returns the name of the architecture as used by EC2
 // resources.
 func instanceEC2ArchName(instanceType string) string {
-	// eg: a1.large - an ARM instance type.
-	if strings.HasPrefix(instanceType, "a") {
+	// eg: a1.large or m6g.large - ARM instance types.
+	if utils.IsARMInstanceType(instanceType) {
 		return "arm64"
 	}
 	return "x86_64"
@@ -86,5 +85,8 @@ func imageType(imageFamily, instanceType string) string {
 	if utils.IsGPUInstanceType(instanceType) {
 		return family + "-gpu"
 	}
+	if utils.IsARMInstanceType(instanceType) {
+		return family + "-arm64"
+	}
 	return family
 }
@@ -86,6 +86,23 @@ var _ = Describe("AMI Auto Resolution", func() {
 				})
 			})
 
+			Context("and ARM instance type", func() {
+				BeforeEach(func() {
+					instanceType = "m6g.large"
+					imageFamily = "AmazonLinux2"
+
+					_, p = createProviders()
+					addMockGetParameter(p, "/aws/service/eks/optimized-ami/1.12/amazon-linux-2-arm64/recommended/image_id", expectedAmi)
+					resolver := NewSSMResolver(p.MockSSM())
+					resolvedAmi, err = resolver.Resolve(region, version, instanceType, imageFamily)
+				})
+
+				It("should have returned the arm64 ami id", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(resolvedAmi).To(BeEquivalentTo(expectedAmi))
+				})
+			})
+
 			Context("and gpu instance type", func() {
 				BeforeEach(func() {
 					instanceType = "p2.xlarge"
@@ -3,7 +3,7 @@ package ami
 import (
 	"fmt"
 
-	"github.com/kris-nova/logger"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/utils"
 )
 
@@ -18,6 +18,10 @@ type StaticDefaultResolver struct {
 func (r *StaticDefaultResolver) Resolve(region, version, instanceType, imageFamily string) (string, error) {
 	logger.Debug("resolving AMI using StaticDefaultResolver for region %s, version %s, instanceType %s and imageFamily %s", region, version, instanceType, imageFamily)
 
+	if utils.IsARMInstanceType(instanceType) {
+		return "", &UnsupportedQueryError{msg: fmt.Sprintf("can't resolve AMI using StaticDefaultResolver as there are no static AMIs for ARM instance type %s", instanceType)}
+	}
+
 	regionalAMIs := StaticImages[version][imageFamily][ImageClassGeneral]
 	return regionalAMIs[region], nil
 }
@@ -0,0 +1,238 @@
+package v1alpha5
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Names of EKS add-ons eksctl configures beyond installing them
+const (
+	VPCCNIAddon       = "vpc-cni"
+	EBSCSIDriverAddon = "aws-ebs-csi-driver"
+	EFSCSIDriverAddon = "aws-efs-csi-driver"
+)
+
+// addonServiceAccount is the service account of an add-on and the managed policy its role needs
+type addonServiceAccount struct {
+	namespace string
+	name      string
+	policy    string
+}
+
+// addonServiceAccounts are the add-ons eksctl can create the IAM role of
+var addonServiceAccounts = map[string]addonServiceAccount{
+	VPCCNIAddon:       {namespace: "kube-system", name: "aws-node", policy: "AmazonEKS_CNI_Policy"},
+	EBSCSIDriverAddon: {namespace: "kube-system", name: "ebs-csi-controller-sa", policy: "service-role/AmazonEBSCSIDriverPolicy"},
+	EFSCSIDriverAddon: {namespace: "kube-system", name: "efs-csi-controller-sa", policy: "service-role/AmazonEFSCSIDriverPolicy"},
+}
+
+// Values of Addon.ResolveConflicts
+const (
+	ResolveConflictsNone      = "none"
+	ResolveConflictsOverwrite = "overwrite"
+	ResolveConflictsPreserve  = "preserve"
+)
+
+// DefaultEBSStorageClassName is the name of the gp3 StorageClass created along with the EBS CSI driver
+const DefaultEBSStorageClassName = "gp3"
+
+// Addon holds the config of an EKS add-on
+type Addon struct {
+	Name string `json:"name"`
+	// Version of the add-on, EKS installs the default version for the Kubernetes version of
+	// the cluster if it isn't set
+	// +optional
+	Version string `json:"version,omitempty"`
+	// ServiceAccountRoleARN is the IAM role of the service account of the add-on
+	// +optional
+	ServiceAccountRoleARN string `json:"serviceAccountRoleARN,omitempty"`
+	// AttachPolicyARNs are managed policies attached to the role eksctl creates for the service
+	// account of the add-on, along with the policy the add-on needs
+	// +optional
+	AttachPolicyARNs []string `json:"attachPolicyARNs,omitempty"`
+	// AttachPolicy is an inline policy of the role eksctl creates for the service account of
+	// the add-on
+	// +optional
+	AttachPolicy InlineDocument `json:"attachPolicy,omitempty"`
+	// RoleName is the name of the role eksctl creates for the service account of the add-on,
+	// which can contain the placeholders {ClusterName}, {Namespace} and {Name} of the service
+	// account; CloudFormation generates it by default
+	// +optional
+	RoleName string `json:"roleName,omitempty"`
+	// ConfigurationValues is a JSON or YAML document matching the configuration schema of the
+	// add-on version
+	// +optional
+	ConfigurationValues string `json:"configurationValues,omitempty"`
+	// ResolveConflicts is how EKS handles Kubernetes objects of the add-on that already exist,
+	// one of none, overwrite or preserve
+	// +optional
+	ResolveConflicts string `json:"resolveConflicts,omitempty"`
+	// StorageClass creates an encrypted gp3 StorageClass provisioned by the EBS CSI driver,
+	// only supported for aws-ebs-csi-driver
+	// +optional
+	StorageClass *AddonStorageClass `json:"storageClass,omitempty"`
+	// SnapshotController installs the CSI snapshot CRDs and the external snapshot controller,
+	// of the release pinned for the Kubernetes version of the cluster, for VolumeSnapshots to
+	// work; only supported for aws-ebs-csi-driver
+	// +optional
+	SnapshotController *bool `json:"snapshotController,omitempty"`
+}
+
+// AddonStorageClass holds the config of the StorageClass created along with the EBS CSI driver
+type AddonStorageClass struct {
+	// Name of the StorageClass, defaults to gp3
+	// +optional
+	Name string `json:"name,omitempty"`
+	// KMSKeyARN is the KMS key volumes are encrypted with, defaults to the AWS managed key of EBS
+	// +optional
+	KMSKeyARN string `json:"kmsKeyARN,omitempty"`
+	// SetDefault makes the StorageClass the default one, removing the default annotation of the
+	// in-tree gp2 class, defaults to true
+	// +optional
+	SetDefault *bool `json:"setDefault,omitempty"`
+}
+
+// HasAddons determines if any EKS add-ons are configured
+func (c *ClusterConfig) HasAddons() bool {
+	return len(c.Addons) > 0
+}
+
+// HasRoleConfig determines if the role eksctl creates for the add-on is customized
+func (a *Addon) HasRoleConfig() bool {
+	return len(a.AttachPolicyARNs) > 0 || len(a.AttachPolicy) > 0 || a.RoleName != ""
+}
+
+// CreatesRole determines if eksctl creates the IAM role of the service account of the add-on:
+// it must be an add-on whose service account eksctl knows, without serviceAccountRoleARN, and
+// either its role is customized or the cluster has an OIDC provider
+func (a *Addon) CreatesRole(withOIDC bool) bool {
+	if _, ok := addonServiceAccounts[a.Name]; !ok || a.ServiceAccountRoleARN != "" {
+		return false
+	}
+	return withOIDC || a.HasRoleConfig()
+}
+
+// IAMServiceAccount returns the iamserviceaccount of the role eksctl creates for the add-on,
+// with the managed policy the add-on needs in the given partition along with the policies of
+// the add-on
+func (a *Addon) IAMServiceAccount(partition string) *ClusterIAMServiceAccount {
+	sa, ok := addonServiceAccounts[a.Name]
+	if !ok {
+		return nil
+	}
+	return &ClusterIAMServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: sa.namespace,
+			Name:      sa.name,
+		},
+		AttachPolicyARNs: append([]string{fmt.Sprintf("arn:%s:iam::aws:policy/%s", partition, sa.policy)}, a.AttachPolicyARNs...),
+		AttachPolicy:     a.AttachPolicy,
+		RoleName:         a.RoleName,
+	}
+}
+
+// SupportedRoleAddons returns the add-ons eksctl can create the IAM role of
+func SupportedRoleAddons() []string {
+	names := make([]string, 0, len(addonServiceAccounts))
+	for name := range addonServiceAccounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SupportedResolveConflicts returns the supported values of Addon.ResolveConflicts
+func SupportedResolveConflicts() []string {
+	return []string{ResolveConflictsNone, ResolveConflictsOverwrite, ResolveConflictsPreserve}
+}
+
+func setAddonDefaults(addon *Addon) {
+	if addon.StorageClass == nil {
+		return
+	}
+	if addon.StorageClass.Name == "" {
+		addon.StorageClass.Name = DefaultEBSStorageClassName
+	}
+	if addon.StorageClass.SetDefault == nil {
+		addon.StorageClass.SetDefault = Enabled()
+	}
+}
+
+func validateAddons(clusterName string, addons []*Addon) error {
+	names := nameSet{}
+	for i, addon := range addons {
+		path := fmt.Sprintf("addons[%d]", i)
+		if addon.Name == "" {
+			return fmt.Errorf("%s.name must be set", path)
+		}
+		if ok, err := names.checkUnique(path+".name", addon.Name); !ok {
+			return err
+		}
+		if addon.ResolveConflicts != "" && !isSupportedResolveConflicts(addon.ResolveConflicts) {
+			return fmt.Errorf("%s.resolveConflicts must be one of %s", path, strings.Join(SupportedResolveConflicts(), ", "))
+		}
+		if addon.ServiceAccountRoleARN != "" {
+			if _, err := arn.Parse(addon.ServiceAccountRoleARN); err != nil {
+				return errors.Wrapf(err, "invalid ARN in %s.serviceAccountRoleARN: %q", path, addon.ServiceAccountRoleARN)
+			}
+		}
+		if err := validateAddonRole(clusterName, addon, path); err != nil {
+			return err
+		}
+		if IsEnabled(addon.SnapshotController) && addon.Name != EBSCSIDriverAddon {
+			return fmt.Errorf("%s.snapshotController is only supported for %q", path, EBSCSIDriverAddon)
+		}
+		if addon.StorageClass == nil {
+			continue
+		}
+		if addon.Name != EBSCSIDriverAddon {
+			return fmt.Errorf("%s.storageClass is only supported for %q", path, EBSCSIDriverAddon)
+		}
+		if addon.StorageClass.KMSKeyARN != "" {
+			if _, err := arn.Parse(addon.StorageClass.KMSKeyARN); err != nil {
+				return errors.Wrapf(err, "invalid ARN in %s.storageClass.kmsKeyARN: %q", path, addon.StorageClass.KMSKeyARN)
+			}
+		}
+	}
+	return nil
+}
+
+func validateAddonRole(clusterName string, addon *Addon, path string) error {
+	if !addon.HasRoleConfig() {
+		return nil
+	}
+	if addon.ServiceAccountRoleARN != "" {
+		return fmt.Errorf("%[1]s.serviceAccountRoleARN cannot be set along with %[1]s.attachPolicyARNs, %[1]s.attachPolicy or %[1]s.roleName", path)
+	}
+	sa := addon.IAMServiceAccount("aws")
+	if sa == nil {
+		return fmt.Errorf("%s.attachPolicyARNs, %s.attachPolicy and %s.roleName are only supported for %s", path, path, path, strings.Join(SupportedRoleAddons(), ", "))
+	}
+	for _, policyARN := range addon.AttachPolicyARNs {
+		if _, err := arn.Parse(policyARN); err != nil {
+			return errors.Wrapf(err, "invalid ARN in %s.attachPolicyARNs: %q", path, policyARN)
+		}
+	}
+	roleName, err := sa.RenderRoleName(clusterName)
+	if err != nil {
+		return errors.Wrapf(err, "invalid %s.roleName", path)
+	}
+	if len(roleName) > maxIAMRoleNameLength {
+		return fmt.Errorf("%s.roleName %q is longer than %d characters", path, roleName, maxIAMRoleNameLength)
+	}
+	return nil
+}
+
+func isSupportedResolveConflicts(value string) bool {
+	for _, supported := range SupportedResolveConflicts() {
+		if value == supported {
+			return true
+		}
+	}
+	return false
+}
@@ -22,6 +22,19 @@ func (c *ClusterConfig) HasClusterCloudWatchLogging() bool {
 	return c.CloudWatch != nil && c.CloudWatch.ClusterLogging != nil && len(c.CloudWatch.ClusterLogging.EnableTypes) > 0
 }
 
+// HasClusterCloudWatchLogType reports whether the given log type of the control plane is enabled
+func (c *ClusterConfig) HasClusterCloudWatchLogType(logType string) bool {
+	if !c.HasClusterCloudWatchLogging() {
+		return false
+	}
+	for _, t := range c.CloudWatch.ClusterLogging.EnableTypes {
+		if t == logType {
+			return true
+		}
+	}
+	return false
+}
+
 // AppendClusterCloudWatchLogTypes will append given log types to the config structure
 func (c *ClusterConfig) AppendClusterCloudWatchLogTypes(types ...string) {
 	c.CloudWatch.ClusterLogging.EnableTypes = append(c.CloudWatch.ClusterLogging.EnableTypes, types...)
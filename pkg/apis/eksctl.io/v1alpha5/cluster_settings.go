@@ -0,0 +1,42 @@
+package v1alpha5
+
+import (
+	"fmt"
+	"sort"
+)
+
+// clusterSettingsSetByEksctl are the fields of the EKS CreateCluster API eksctl sets from the
+// rest of the config, they can't be passed through clusterSettings
+var clusterSettingsSetByEksctl = map[string]string{
+	"name":                    "metadata.name",
+	"version":                 "metadata.version",
+	"roleArn":                 "iam.serviceRoleARN",
+	"resourcesVpcConfig":      "vpc",
+	"encryptionConfig":        "secretsEncryption",
+	"accessConfig":            "accessConfig",
+	"kubernetesNetworkConfig": "kubernetesNetworkConfig",
+	"logging":                 "cloudWatch.clusterLogging",
+	"tags":                    "metadata.tags",
+}
+
+// HasClusterSettings determines if any settings are passed through to the control plane
+func (c *ClusterConfig) HasClusterSettings() bool {
+	return len(c.ClusterSettings) > 0
+}
+
+func validateClusterSettings(settings InlineDocument) error {
+	var keys []string
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if field, ok := clusterSettingsSetByEksctl[key]; ok {
+			return fmt.Errorf("clusterSettings.%s is set by eksctl, use %s instead", key, field)
+		}
+		if key == "" || key[0] < 'a' || key[0] > 'z' {
+			return fmt.Errorf("clusterSettings.%s must be a field of the EKS CreateCluster API, e.g. controlPlaneScalingConfig", key)
+		}
+	}
+	return nil
+}
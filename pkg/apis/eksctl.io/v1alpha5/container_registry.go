@@ -0,0 +1,46 @@
+package v1alpha5
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HasContainerRegistryOverride determines if images are pulled from a registry override
+func (c *ClusterConfig) HasContainerRegistryOverride() bool {
+	return c.ContainerRegistryOverride != ""
+}
+
+// OverrideImageRegistry replaces the registry of image with registry, keeping its repository
+// path, e.g. 602401143452.dkr.ecr.us-west-2.amazonaws.com/eks/coredns:v1.6.6 becomes
+// <registry>/eks/coredns:v1.6.6; images of Docker Hub, which have no registry, keep their whole
+// path. The image is returned as it is when registry is empty or it's already pulled from it
+func OverrideImageRegistry(image, registry string) string {
+	registry = strings.TrimSuffix(registry, "/")
+	if registry == "" || strings.HasPrefix(image, registry+"/") {
+		return image
+	}
+	path := image
+	if i := strings.Index(image, "/"); i > 0 && isRegistryHost(image[:i]) {
+		path = image[i+1:]
+	}
+	return registry + "/" + path
+}
+
+// isRegistryHost determines if the first component of an image reference is the host of a
+// registry, like Docker does
+func isRegistryHost(component string) bool {
+	return strings.ContainsAny(component, ".:") || component == "localhost"
+}
+
+func validateContainerRegistryOverride(registry string) error {
+	if registry == "" {
+		return nil
+	}
+	if strings.Contains(registry, "://") {
+		return fmt.Errorf("containerRegistryOverride must be a registry without a scheme, e.g. 123456789012.dkr.ecr.us-west-2.amazonaws.com, got %q", registry)
+	}
+	if host := strings.SplitN(registry, "/", 2)[0]; !isRegistryHost(host) {
+		return fmt.Errorf("containerRegistryOverride must start with the host of a registry, e.g. 123456789012.dkr.ecr.us-west-2.amazonaws.com, got %q", registry)
+	}
+	return nil
+}
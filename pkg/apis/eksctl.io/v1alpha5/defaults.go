@@ -14,10 +14,20 @@ func SetClusterConfigDefaults(cfg *ClusterConfig) {
 		cfg.IAM.WithOIDC = Disabled()
 	}
 
+	if cfg.HasNodeTerminationHandler() {
+		setNodeTerminationHandlerDefaults(cfg)
+	}
+
 	for _, sa := range cfg.IAM.ServiceAccounts {
 		if sa.Namespace == "" {
 			sa.Namespace = metav1.NamespaceDefault
 		}
+		if sa.CreateNamespace == nil {
+			sa.CreateNamespace = Enabled()
+		}
+		if sa.RoleOnly == nil {
+			sa.RoleOnly = Disabled()
+		}
 	}
 
 	if cfg.HasClusterCloudWatchLogging() && len(cfg.CloudWatch.ClusterLogging.EnableTypes) == 1 {
@@ -26,11 +36,96 @@ func SetClusterConfigDefaults(cfg *ClusterConfig) {
 			cfg.CloudWatch.ClusterLogging.EnableTypes = SupportedCloudWatchClusterLogTypes()
 		}
 	}
+
+	if cfg.HasAlarms() && cfg.Alarms.APIServerErrorThreshold == nil {
+		threshold := DefaultAPIServerErrorThreshold
+		cfg.Alarms.APIServerErrorThreshold = &threshold
+	}
+
+	if cfg.HasNodeGroupVolumeEncryptionDefaults() {
+		volumeEncryption := cfg.NodeGroupDefaults.VolumeEncryption
+		for _, ng := range cfg.NodeGroups {
+			ng.VolumeEncrypted, ng.VolumeKmsKeyID = volumeEncryptionDefaults(ng.VolumeEncrypted, ng.VolumeKmsKeyID, volumeEncryption)
+		}
+		for _, ng := range cfg.ManagedNodeGroups {
+			ng.VolumeEncrypted, ng.VolumeKmsKeyID = volumeEncryptionDefaults(ng.VolumeEncrypted, ng.VolumeKmsKeyID, volumeEncryption)
+		}
+	}
+
+	for _, addon := range cfg.Addons {
+		setAddonDefaults(addon)
+	}
+
+	if cfg.VPC != nil && cfg.VPC.FlowLogs != nil {
+		setVPCFlowLogsDefaults(cfg.VPC.FlowLogs)
+	}
+}
+
+func setVPCFlowLogsDefaults(flowLogs *VPCFlowLogs) {
+	if flowLogs.TrafficType == "" {
+		flowLogs.TrafficType = VPCFlowLogsTrafficTypeAll
+	}
+	if flowLogs.AggregationInterval == nil {
+		aggregationInterval := DefaultVPCFlowLogsAggregationInterval
+		flowLogs.AggregationInterval = &aggregationInterval
+	}
+}
+
+// setNodeTerminationHandlerDefaults sets the default mode and, in queue mode, adds the
+// service account that allows the handler to process the queue and complete lifecycle hooks
+func setNodeTerminationHandlerDefaults(cfg *ClusterConfig) {
+	if cfg.NodeTerminationHandler.Mode == "" {
+		cfg.NodeTerminationHandler.Mode = NodeTerminationHandlerModeIMDS
+	}
+	if cfg.NodeTerminationHandler.Mode != NodeTerminationHandlerModeQueue {
+		return
+	}
+	for _, sa := range cfg.IAM.ServiceAccounts {
+		if sa.Namespace == NodeTerminationHandlerNamespace && sa.Name == NodeTerminationHandlerName {
+			return
+		}
+	}
+	cfg.IAM.ServiceAccounts = append(cfg.IAM.ServiceAccounts, &ClusterIAMServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      NodeTerminationHandlerName,
+			Namespace: NodeTerminationHandlerNamespace,
+		},
+		AttachPolicy: InlineDocument{
+			"Version": "2012-10-17",
+			"Statement": []interface{}{
+				map[string]interface{}{
+					"Effect": "Allow",
+					"Action": []string{
+						"autoscaling:CompleteLifecycleAction",
+						"autoscaling:DescribeAutoScalingInstances",
+						"autoscaling:DescribeTags",
+						"ec2:DescribeInstances",
+						"sqs:DeleteMessage",
+						"sqs:ReceiveMessage",
+					},
+					"Resource": "*",
+				},
+			},
+		},
+	})
+}
+
+// volumeEncryptionDefaults returns the root volume encryption settings of a nodegroup,
+// with the cluster-wide settings applied unless the nodegroup sets them explicitly
+func volumeEncryptionDefaults(encrypted *bool, kmsKeyID *string, volumeEncryption *VolumeEncryption) (*bool, *string) {
+	if encrypted == nil {
+		encrypted = Enabled()
+	}
+	if IsEnabled(encrypted) && !IsSetAndNonEmptyString(kmsKeyID) {
+		kmsKeyID = volumeEncryption.KMSKeyARN
+	}
+	return encrypted, kmsKeyID
 }
 
 // SetNodeGroupDefaults will set defaults for a given nodegroup
 func SetNodeGroupDefaults(ng *NodeGroup, meta *ClusterMeta) {
-	if ng.InstanceType == "" {
+	// the instance types of an instance selector are resolved when the nodegroup is created
+	if ng.InstanceType == "" && ng.InstanceSelector == nil {
 		if HasMixedInstances(ng) {
 			ng.InstanceType = "mixed"
 		} else {
@@ -50,7 +145,12 @@ func SetNodeGroupDefaults(ng *NodeGroup, meta *ClusterMeta) {
 		ng.SecurityGroups.WithLocal = Enabled()
 	}
 	if ng.SecurityGroups.WithShared == nil {
-		ng.SecurityGroups.WithShared = Enabled()
+		// the shared security group lives in the account of the cluster
+		if ng.Account == nil {
+			ng.SecurityGroups.WithShared = Enabled()
+		} else {
+			ng.SecurityGroups.WithShared = Disabled()
+		}
 	}
 
 	if ng.SSH == nil {
@@ -65,6 +165,12 @@ func SetNodeGroupDefaults(ng *NodeGroup, meta *ClusterMeta) {
 		ng.VolumeType = &DefaultNodeVolumeType
 	}
 
+	for _, volume := range ng.AdditionalVolumes {
+		if !IsSetAndNonEmptyString(volume.Type) {
+			volume.Type = &DefaultNodeVolumeType
+		}
+	}
+
 	if ng.IAM == nil {
 		ng.IAM = &NodeGroupIAM{}
 	}
@@ -87,7 +193,7 @@ func SetManagedNodeGroupDefaults(ng *ManagedNodeGroup, meta *ClusterMeta) {
 	if ng.AMIFamily == "" {
 		ng.AMIFamily = NodeImageFamilyAmazonLinux2
 	}
-	if ng.InstanceType == "" {
+	if ng.InstanceType == "" && ng.InstanceSelector == nil {
 		ng.InstanceType = DefaultNodeType
 	}
 	if ng.ScalingConfig == nil {
@@ -0,0 +1,116 @@
+package v1alpha5
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/pkg/errors"
+)
+
+// Values of FargateLogging.Destination
+const (
+	FargateLoggingCloudWatch = "cloudwatch"
+	FargateLoggingFirehose   = "firehose"
+	FargateLoggingOpenSearch = "opensearch"
+)
+
+// FargateLogging holds the config of the Fluent Bit log router EKS runs in Fargate pods; the
+// router is configured by the aws-observability ConfigMap, which is shared by all the Fargate
+// profiles of a cluster
+type FargateLogging struct {
+	// Destination of the logs, one of cloudwatch, firehose or opensearch
+	Destination string `json:"destination"`
+	// LogGroupName is the CloudWatch log group of the cloudwatch destination, defaults to
+	// /aws/eks/<cluster>/fargate
+	// +optional
+	LogGroupName string `json:"logGroupName,omitempty"`
+	// LogStreamPrefix is the prefix of the CloudWatch log streams of the cloudwatch destination,
+	// defaults to fargate-
+	// +optional
+	LogStreamPrefix string `json:"logStreamPrefix,omitempty"`
+	// DeliveryStream is the Kinesis Data Firehose delivery stream of the firehose destination
+	// +optional
+	DeliveryStream string `json:"deliveryStream,omitempty"`
+	// DomainARN is the ARN of the OpenSearch domain of the opensearch destination
+	// +optional
+	DomainARN string `json:"domainARN,omitempty"`
+	// Endpoint is the HTTPS endpoint of the OpenSearch domain of the opensearch destination
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+	// Index is the OpenSearch index of the opensearch destination, defaults to fargate
+	// +optional
+	Index string `json:"index,omitempty"`
+}
+
+// SupportedFargateLoggingDestinations returns the supported values of FargateLogging.Destination
+func SupportedFargateLoggingDestinations() []string {
+	return []string{FargateLoggingCloudWatch, FargateLoggingFirehose, FargateLoggingOpenSearch}
+}
+
+// FargateLogging returns the logging config of the Fargate profiles, nil if none of them
+// ships logs
+func (c *ClusterConfig) FargateLogging() *FargateLogging {
+	for _, profile := range c.FargateProfiles {
+		if profile.Logging != nil {
+			return profile.Logging
+		}
+	}
+	return nil
+}
+
+func validateFargateLogging(profiles []*FargateProfile) error {
+	var shared *FargateLogging
+	for i, profile := range profiles {
+		if profile.Logging == nil {
+			continue
+		}
+		path := fmt.Sprintf("fargateProfiles[%d].logging", i)
+		if err := profile.Logging.validate(path); err != nil {
+			return err
+		}
+		if shared == nil {
+			shared = profile.Logging
+			continue
+		}
+		if !reflect.DeepEqual(shared, profile.Logging) {
+			return fmt.Errorf("%s differs from the logging of the other Fargate profiles, the aws-observability ConfigMap is shared by all the profiles of a cluster", path)
+		}
+	}
+	return nil
+}
+
+func (l *FargateLogging) validate(path string) error {
+	switch l.Destination {
+	case FargateLoggingCloudWatch:
+		if l.DeliveryStream != "" || l.DomainARN != "" || l.Endpoint != "" || l.Index != "" {
+			return fmt.Errorf("%s only supports logGroupName and logStreamPrefix with the %s destination", path, l.Destination)
+		}
+	case FargateLoggingFirehose:
+		if l.DeliveryStream == "" {
+			return fmt.Errorf("%s.deliveryStream must be set for the %s destination", path, l.Destination)
+		}
+		if l.LogGroupName != "" || l.LogStreamPrefix != "" || l.DomainARN != "" || l.Endpoint != "" || l.Index != "" {
+			return fmt.Errorf("%s only supports deliveryStream with the %s destination", path, l.Destination)
+		}
+	case FargateLoggingOpenSearch:
+		if l.DomainARN == "" || l.Endpoint == "" {
+			return fmt.Errorf("%[1]s.domainARN and %[1]s.endpoint must be set for the %[2]s destination", path, l.Destination)
+		}
+		if _, err := arn.Parse(l.DomainARN); err != nil {
+			return errors.Wrapf(err, "invalid ARN in %s.domainARN: %q", path, l.DomainARN)
+		}
+		endpoint, err := url.Parse(l.Endpoint)
+		if err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+			return fmt.Errorf("%s.endpoint must be an https URL, got %q", path, l.Endpoint)
+		}
+		if l.LogGroupName != "" || l.LogStreamPrefix != "" || l.DeliveryStream != "" {
+			return fmt.Errorf("%s only supports domainARN, endpoint and index with the %s destination", path, l.Destination)
+		}
+	default:
+		return fmt.Errorf("%s.destination must be one of %s", path, strings.Join(SupportedFargateLoggingDestinations(), ", "))
+	}
+	return nil
+}
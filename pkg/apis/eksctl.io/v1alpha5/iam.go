@@ -2,6 +2,7 @@ package v1alpha5
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -22,6 +23,14 @@ type ClusterIAM struct {
 	FargatePodExecutionRoleARN *string `json:"fargatePodExecutionRoleARN,omitempty"`
 	// +optional
 	FargatePodExecutionRolePermissionsBoundary *string `json:"fargatePodExecutionRolePermissionsBoundary,omitempty"`
+	// FargatePodExecutionRoleName is the name of the Fargate pod execution role eksctl creates,
+	// CloudFormation generates it by default
+	// +optional
+	FargatePodExecutionRoleName string `json:"fargatePodExecutionRoleName,omitempty"`
+	// FargatePodExecutionRolePolicyARNs are managed policies attached to the Fargate pod execution
+	// role eksctl creates, along with AmazonEKSFargatePodExecutionRolePolicy
+	// +optional
+	FargatePodExecutionRolePolicyARNs []string `json:"fargatePodExecutionRolePolicyARNs,omitempty"`
 	// +optional
 	WithOIDC *bool `json:"withOIDC,omitempty"`
 	// +optional
@@ -37,14 +46,48 @@ type ClusterIAMServiceAccount struct {
 	AttachPolicy InlineDocument `json:"attachPolicy,omitempty"`
 	// +optional
 	PermissionsBoundary string `json:"permissionsBoundary,omitempty"`
+	// RoleName is the name of the role eksctl creates, which can contain the placeholders
+	// {ClusterName}, {Namespace} and {Name}; CloudFormation generates it by default
+	// +optional
+	RoleName string `json:"roleName,omitempty"`
+	// AttachRoleARN is an existing role the service account is bound to instead of a role
+	// eksctl creates, its trust policy must allow the service account to assume it
+	// +optional
+	AttachRoleARN string `json:"attachRoleARN,omitempty"`
+	// AutomountServiceAccountToken is set on the service account eksctl creates or updates
+	// +optional
+	AutomountServiceAccountToken *bool `json:"automountServiceAccountToken,omitempty"`
+	// CreateNamespace creates the namespace of the service account if it doesn't exist,
+	// defaults to true
+	// +optional
+	CreateNamespace *bool `json:"createNamespace,omitempty"`
+	// RoleOnly only creates the role, the service account is left to other tools, e.g. a
+	// Helm chart that annotates it with the ARN of the role
+	// +optional
+	RoleOnly *bool `json:"roleOnly,omitempty"`
 	// +optional
 	Status *ClusterIAMServiceAccountStatus `json:"status,omitempty"`
 }
 
+// roleNamePlaceholder matches the placeholders of ClusterIAMServiceAccount.RoleName, which use
+// single braces so that they don't collide with config files rendered as Go templates
+var roleNamePlaceholder = regexp.MustCompile(`\{[^{}]*\}`)
+
+// maxIAMRoleNameLength is the maximum length of the name of an IAM role
+const maxIAMRoleNameLength = 64
+
 // ClusterIAMServiceAccountStatus holds status of iamserviceaccount
 type ClusterIAMServiceAccountStatus struct {
 	// +optional
 	RoleARN *string `json:"roleARN,omitempty"`
+	// +optional
+	AttachedPolicyARNs []string `json:"attachedPolicyARNs,omitempty"`
+	// +optional
+	InlinePolicyNames []string `json:"inlinePolicyNames,omitempty"`
+	// Problems prevent pods using the serviceaccount from assuming the role, e.g. a trust
+	// policy for the OIDC issuer of a cluster that was recreated
+	// +optional
+	Problems []string `json:"problems,omitempty"`
 }
 
 // NameString returns common name string
@@ -65,6 +108,27 @@ func ClusterIAMServiceAccountNameStringToObjectMeta(name string) (*metav1.Object
 	return meta, nil
 }
 
+// RenderRoleName replaces the placeholders of RoleName for the service account of the given cluster
+func (sa *ClusterIAMServiceAccount) RenderRoleName(clusterName string) (string, error) {
+	values := map[string]string{
+		"{ClusterName}": clusterName,
+		"{Namespace}":   sa.Namespace,
+		"{Name}":        sa.Name,
+	}
+	var err error
+	name := roleNamePlaceholder.ReplaceAllStringFunc(sa.RoleName, func(placeholder string) string {
+		value, ok := values[placeholder]
+		if !ok && err == nil {
+			err = fmt.Errorf("unknown placeholder %s in role name %q, the placeholders are {ClusterName}, {Namespace} and {Name}", placeholder, sa.RoleName)
+		}
+		return value
+	})
+	if err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
 // SetAnnotations sets eks.amazonaws.com/role-arn annotation according to IAM role used
 func (sa *ClusterIAMServiceAccount) SetAnnotations() {
 	if sa.Annotations == nil {
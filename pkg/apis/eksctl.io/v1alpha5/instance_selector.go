@@ -0,0 +1,58 @@
+package v1alpha5
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Architectures supported by the instance selector
+const (
+	CPUArchitectureX86_64 = "x86_64"
+	CPUArchitectureARM64  = "arm64"
+)
+
+// MemoryMiB returns the memory of the selector in MiB, or 0 when it's not set
+func (s *InstanceSelector) MemoryMiB() (int64, error) {
+	if s.Memory == "" {
+		return 0, nil
+	}
+	value, unit := s.Memory, int64(1024)
+	switch {
+	case strings.HasSuffix(value, "GiB"):
+		value = strings.TrimSuffix(value, "GiB")
+	case strings.HasSuffix(value, "MiB"):
+		value, unit = strings.TrimSuffix(value, "MiB"), 1
+	}
+	memory, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || memory <= 0 {
+		return 0, fmt.Errorf("invalid memory %q, expected e.g. \"16\", \"16GiB\" or \"512MiB\"", s.Memory)
+	}
+	return memory * unit, nil
+}
+
+// Architecture returns the CPU architecture of the selector, which defaults to x86_64
+func (s *InstanceSelector) Architecture() string {
+	if s.CPUArchitecture == "" {
+		return CPUArchitectureX86_64
+	}
+	return s.CPUArchitecture
+}
+
+func validateInstanceSelector(s *InstanceSelector, path string) error {
+	if s.VCPUs < 0 || s.GPUs < 0 {
+		return fmt.Errorf("%s.instanceSelector.vCPUs and %s.instanceSelector.gpus cannot be negative", path, path)
+	}
+	if s.VCPUs == 0 && s.Memory == "" && s.GPUs == 0 {
+		return fmt.Errorf("%s.instanceSelector must set at least one of vCPUs, memory or gpus", path)
+	}
+	if _, err := s.MemoryMiB(); err != nil {
+		return fmt.Errorf("%s.instanceSelector: %s", path, err.Error())
+	}
+	switch s.Architecture() {
+	case CPUArchitectureX86_64, CPUArchitectureARM64:
+	default:
+		return fmt.Errorf("%s.instanceSelector.cpuArchitecture must be %s or %s", path, CPUArchitectureX86_64, CPUArchitectureARM64)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package v1alpha5
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TimeoutPhase is a phase of a command with its own timeout budget
+type TimeoutPhase string
+
+const (
+	// TimeoutPhaseControlPlane is the creation of the control plane, until its API is reachable
+	TimeoutPhaseControlPlane TimeoutPhase = "controlPlane"
+	// TimeoutPhaseNodeGroups is the creation of the nodegroups, until their nodes are ready
+	TimeoutPhaseNodeGroups TimeoutPhase = "nodeGroups"
+	// TimeoutPhaseAddons is the creation of the EKS add-ons
+	TimeoutPhaseAddons TimeoutPhase = "addons"
+	// TimeoutPhaseCleanup is the cleanup of the resources left by Kubernetes, e.g. load balancers,
+	// when deleting a cluster
+	TimeoutPhaseCleanup TimeoutPhase = "cleanup"
+)
+
+// DefaultCleanupTimeout is the default timeout of the cleanup phase, which doesn't default to
+// the overall timeout as deleting a cluster mostly waits for CloudFormation
+var DefaultCleanupTimeout = 10 * time.Minute
+
+// PhaseTimeouts are the timeouts of the phases of a command, those that aren't set default to
+// the overall timeout
+type PhaseTimeouts struct {
+	ControlPlane time.Duration
+	NodeGroups   time.Duration
+	Addons       time.Duration
+	Cleanup      time.Duration
+}
+
+// Timeout returns the timeout of a phase, or the overall timeout for any other wait
+func (p *ProviderConfig) Timeout(phase TimeoutPhase) time.Duration {
+	var timeout time.Duration
+	switch phase {
+	case TimeoutPhaseControlPlane:
+		timeout = p.Timeouts.ControlPlane
+	case TimeoutPhaseNodeGroups:
+		timeout = p.Timeouts.NodeGroups
+	case TimeoutPhaseAddons:
+		timeout = p.Timeouts.Addons
+	case TimeoutPhaseCleanup:
+		if p.Timeouts.Cleanup == 0 {
+			return DefaultCleanupTimeout
+		}
+		return p.Timeouts.Cleanup
+	}
+	if timeout == 0 {
+		return p.WaitTimeout
+	}
+	return timeout
+}
+
+// FlagName returns the name of the flag setting the timeout of the phase
+func (p TimeoutPhase) FlagName() string {
+	switch p {
+	case TimeoutPhaseControlPlane:
+		return "control-plane-timeout"
+	case TimeoutPhaseNodeGroups:
+		return "nodegroups-timeout"
+	case TimeoutPhaseAddons:
+		return "addons-timeout"
+	case TimeoutPhaseCleanup:
+		return "cleanup-timeout"
+	}
+	return "timeout"
+}
+
+// WrapTimeout attributes a timeout to the phase it happened in, so that the right timeout is
+// raised when retrying
+func (p TimeoutPhase) WrapTimeout(err error, timeout time.Duration) error {
+	if p == "" {
+		return errors.Wrapf(err, "timed out after %s, the timeout can be raised with --timeout", timeout)
+	}
+	return errors.Wrapf(err, "the %s phase timed out after %s, its timeout can be raised with --%s", p, timeout, p.FlagName())
+}
@@ -0,0 +1,47 @@
+package v1alpha5
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Phase timeouts", func() {
+	var p *ProviderConfig
+
+	BeforeEach(func() {
+		p = &ProviderConfig{WaitTimeout: DefaultWaitTimeout}
+	})
+
+	It("defaults to the overall timeout, except for the cleanup", func() {
+		Expect(p.Timeout(TimeoutPhaseControlPlane)).To(Equal(DefaultWaitTimeout))
+		Expect(p.Timeout(TimeoutPhaseNodeGroups)).To(Equal(DefaultWaitTimeout))
+		Expect(p.Timeout(TimeoutPhaseAddons)).To(Equal(DefaultWaitTimeout))
+		Expect(p.Timeout(TimeoutPhaseCleanup)).To(Equal(DefaultCleanupTimeout))
+		Expect(p.Timeout("")).To(Equal(DefaultWaitTimeout))
+	})
+
+	It("uses the timeouts set for phases", func() {
+		p.Timeouts = PhaseTimeouts{
+			ControlPlane: 40 * time.Minute,
+			NodeGroups:   20 * time.Minute,
+			Addons:       5 * time.Minute,
+			Cleanup:      15 * time.Minute,
+		}
+		Expect(p.Timeout(TimeoutPhaseControlPlane)).To(Equal(40 * time.Minute))
+		Expect(p.Timeout(TimeoutPhaseNodeGroups)).To(Equal(20 * time.Minute))
+		Expect(p.Timeout(TimeoutPhaseAddons)).To(Equal(5 * time.Minute))
+		Expect(p.Timeout(TimeoutPhaseCleanup)).To(Equal(15 * time.Minute))
+		Expect(p.Timeout("")).To(Equal(DefaultWaitTimeout))
+	})
+
+	It("attributes timeouts to their phase", func() {
+		err := TimeoutPhaseNodeGroups.WrapTimeout(errors.New("waiting for nodes"), 20*time.Minute)
+		Expect(err).To(MatchError("the nodeGroups phase timed out after 20m0s, its timeout can be raised with --nodegroups-timeout: waiting for nodes"))
+
+		err = TimeoutPhase("").WrapTimeout(errors.New("waiting for stack"), DefaultWaitTimeout)
+		Expect(err).To(MatchError("timed out after 25m0s, the timeout can be raised with --timeout: waiting for stack"))
+	})
+})
@@ -2,16 +2,23 @@ package v1alpha5
 
 import (
 	"fmt"
+	"net"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
 	"github.com/aws/aws-sdk-go/service/cloudtrail/cloudtrailiface"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/eks/eksiface"
 	"github.com/aws/aws-sdk-go/service/elb/elbiface"
 	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/aws/aws-sdk-go/service/pricing/pricingiface"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
 	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -118,6 +125,8 @@ const (
 
 	// NodeVolumeTypeGP2 is General Purpose SSD
 	NodeVolumeTypeGP2 = "gp2"
+	// NodeVolumeTypeGP3 is General Purpose SSD with configurable IOPS and throughput
+	NodeVolumeTypeGP3 = "gp3"
 	// NodeVolumeTypeIO1 is Provisioned IOPS SSD
 	NodeVolumeTypeIO1 = "io1"
 	// NodeVolumeTypeSC1 is Throughput Optimized HDD
@@ -125,6 +134,15 @@ const (
 	// NodeVolumeTypeST1 is Cold HDD
 	NodeVolumeTypeST1 = "st1"
 
+	// MinGP3VolumeIOPS is the minimum IOPS of a gp3 volume
+	MinGP3VolumeIOPS = 3000
+	// MaxGP3VolumeIOPS is the maximum IOPS of a gp3 volume
+	MaxGP3VolumeIOPS = 16000
+	// MinGP3VolumeThroughput is the minimum throughput of a gp3 volume in MiB/s
+	MinGP3VolumeThroughput = 125
+	// MaxGP3VolumeThroughput is the maximum throughput of a gp3 volume in MiB/s
+	MaxGP3VolumeThroughput = 1000
+
 	// DefaultNodeImageFamily defines the default image family for the worker nodes
 	DefaultNodeImageFamily = NodeImageFamilyAmazonLinux2
 	// NodeImageFamilyAmazonLinux2 represents Amazon Linux 2 family
@@ -154,6 +172,10 @@ const (
 	// ClusterNameTag defines the tag of the cluster name
 	ClusterNameTag = "alpha.eksctl.io/cluster-name"
 
+	// EKSClusterNameTag is the tag of the cluster name EKS sets on the instances of managed
+	// nodegroups, which can be activated as a cost allocation tag to report costs per cluster
+	EKSClusterNameTag = "eks:cluster-name"
+
 	// OldClusterNameTag defines the tag of the cluster name
 	OldClusterNameTag = "eksctl.cluster.k8s.io/v1alpha1/cluster-name"
 
@@ -172,6 +194,20 @@ const (
 	// IAMServiceAccountNameTag defines the tag of the iamserviceaccount name
 	IAMServiceAccountNameTag = "alpha.eksctl.io/iamserviceaccount-name"
 
+	// IAMServiceAccountRoleOnlyTag marks the iamserviceaccounts whose serviceaccount eksctl
+	// doesn't manage
+	IAMServiceAccountRoleOnlyTag = "alpha.eksctl.io/iamserviceaccount-role-only"
+
+	// AddonNameTag defines the tag of the name of the EKS add-on whose role a stack defines
+	AddonNameTag = "alpha.eksctl.io/addon-name"
+
+	// NodeGroupAccessTag marks the stacks allowing a nodegroup in another account to reach the
+	// control plane, its value is the name of the nodegroup
+	NodeGroupAccessTag = "alpha.eksctl.io/nodegroup-access"
+
+	// DeletionProtectionTag marks the cluster stacks of clusters with deletion protection
+	DeletionProtectionTag = "alpha.eksctl.io/deletion-protection"
+
 	// ClusterNameLabel defines the tag of the cluster name
 	ClusterNameLabel = "alpha.eksctl.io/cluster-name"
 
@@ -307,6 +343,7 @@ func SupportedVersions() []string {
 func SupportedNodeVolumeTypes() []string {
 	return []string{
 		NodeVolumeTypeGP2,
+		NodeVolumeTypeGP3,
 		NodeVolumeTypeIO1,
 		NodeVolumeTypeSC1,
 		NodeVolumeTypeST1,
@@ -351,6 +388,25 @@ type ClusterMeta struct {
 	Version string `json:"version,omitempty"`
 	// +optional
 	Tags map[string]string `json:"tags,omitempty"`
+	// RequiredTags are checked against the tags of every resource before it's created
+	// +optional
+	RequiredTags []RequiredTag `json:"requiredTags,omitempty"`
+	// DeletionProtection enables termination protection on the cluster stack, and makes
+	// `eksctl delete cluster` refuse to delete the cluster unless it's explicitly disabled
+	// +optional
+	DeletionProtection *bool `json:"deletionProtection,omitempty"`
+}
+
+// RequiredTag is a tag that must be set on every resource eksctl creates, it's
+// typically used to enforce an organisation's tag policy client-side
+type RequiredTag struct {
+	Key string `json:"key"`
+	// Pattern is a regular expression the value must match
+	// +optional
+	Pattern string `json:"pattern,omitempty"`
+	// AllowedValues restricts the value to one of the given values
+	// +optional
+	AllowedValues []string `json:"allowedValues,omitempty"`
 }
 
 // ClusterStatus hold read-only attributes of a cluster
@@ -404,9 +460,16 @@ type ClusterProvider interface {
 	SSM() ssmiface.SSMAPI
 	IAM() iamiface.IAMAPI
 	CloudTrail() cloudtrailiface.CloudTrailAPI
+	KMS() kmsiface.KMSAPI
+	SNS() snsiface.SNSAPI
+	EventBridge() eventbridgeiface.EventBridgeAPI
+	Route53() route53iface.Route53API
+	CloudWatchLogs() cloudwatchlogsiface.CloudWatchLogsAPI
+	Pricing() pricingiface.PricingAPI
 	Region() string
 	Profile() string
 	WaitTimeout() time.Duration
+	Timeout(TimeoutPhase) time.Duration
 }
 
 // ProviderConfig holds global parameters for all interactions with AWS APIs
@@ -416,6 +479,39 @@ type ProviderConfig struct {
 	Region      string
 	Profile     string
 	WaitTimeout time.Duration
+	// Timeouts are the budgets of the phases of a command, out of the overall WaitTimeout
+	Timeouts PhaseTimeouts
+
+	// EndpointURL overrides the endpoints of all services, e.g. to use an emulator
+	EndpointURL string
+	// ServiceEndpoints override the endpoints of individual services, keyed by service
+	ServiceEndpoints map[string]string
+	// UseFIPSEndpoints switches all services to their FIPS endpoints
+	UseFIPSEndpoints bool
+	// CABundle is the path of PEM certificates trusted by the clients of the AWS
+	// and Kubernetes APIs, in addition to the system and cluster CAs
+	CABundle string
+	// CacheTTL enables caching the AWS metadata that rarely changes, e.g. AMIs and
+	// availability zones, on disk in CacheDir for the given duration
+	CacheTTL time.Duration
+	CacheDir string
+	// AssumeRole is the role all the AWS calls are made as, instead of the identity of the
+	// credentials of the environment or profile
+	AssumeRole AssumeRoleConfig
+}
+
+// AssumeRoleConfig holds the role assumed for all the AWS calls of a command
+type AssumeRoleConfig struct {
+	RoleARN     string
+	ExternalID  string
+	SessionName string
+	Duration    time.Duration
+	// ChainedRoleARN is assumed with the credentials of RoleARN, e.g. a role of the account of
+	// the cluster trusting a role of a central account
+	ChainedRoleARN    string
+	ChainedExternalID string
+	// ChainedDuration is the duration of the session of ChainedRoleARN, STS limits it to an hour
+	ChainedDuration time.Duration
 }
 
 // +genclient
@@ -451,6 +547,52 @@ type ClusterConfig struct {
 	// +optional
 	SecretsEncryption *SecretsEncryption `json:"secretsEncryption,omitempty"`
 
+	// +optional
+	NodeGroupDefaults *NodeGroupDefaults `json:"nodeGroupDefaults,omitempty"`
+
+	// +optional
+	NodeTerminationHandler *NodeTerminationHandler `json:"nodeTerminationHandler,omitempty"`
+
+	// +optional
+	Hooks *ClusterHooks `json:"hooks,omitempty"`
+
+	// +optional
+	Notifications *Notifications `json:"notifications,omitempty"`
+
+	// +optional
+	Alarms *Alarms `json:"alarms,omitempty"`
+
+	// +optional
+	Budget *Budget `json:"budget,omitempty"`
+
+	// +optional
+	Billing *Billing `json:"billing,omitempty"`
+
+	// +optional
+	AccessConfig *AccessConfig `json:"accessConfig,omitempty"`
+
+	// +optional
+	KubernetesNetworkConfig *KubernetesNetworkConfig `json:"kubernetesNetworkConfig,omitempty"`
+
+	// +optional
+	CloudFormation *ClusterCloudFormation `json:"cloudFormation,omitempty"`
+
+	// +optional
+	Addons []*Addon `json:"addons,omitempty"`
+
+	// ClusterSettings are passed through to the control plane as they are, to use features of
+	// EKS that eksctl doesn't have fields for yet, e.g. controlPlaneScalingConfig; the keys are
+	// the fields of the EKS CreateCluster API
+	// +optional
+	ClusterSettings InlineDocument `json:"clusterSettings,omitempty"`
+
+	// ContainerRegistryOverride is the registry, e.g. a private ECR registry, the images of the
+	// core components, of the add-ons eksctl deploys and the pause image of nodes are pulled
+	// from instead of their public registries, for clusters without internet access; the
+	// images are expected under their original repository paths
+	// +optional
+	ContainerRegistryOverride string `json:"containerRegistryOverride,omitempty"`
+
 	Status *ClusterStatus `json:"status,omitempty"`
 }
 
@@ -601,6 +743,8 @@ type NodeGroup struct {
 	AMIFamily string `json:"amiFamily,omitempty"`
 	// +optional
 	InstanceType string `json:"instanceType,omitempty"`
+	// +optional
+	InstanceSelector *InstanceSelector `json:"instanceSelector,omitempty"`
 	//+optional
 	InstancesDistribution *NodeGroupInstancesDistribution `json:"instancesDistribution,omitempty"`
 	// +optional
@@ -635,6 +779,11 @@ type NodeGroup struct {
 	VolumeKmsKeyID *string `json:"volumeKmsKeyID,omitempty"`
 	// +optional
 	VolumeIOPS *int `json:"volumeIOPS"`
+	// +optional
+	VolumeThroughput *int `json:"volumeThroughput,omitempty"`
+
+	// +optional
+	AdditionalVolumes []*VolumeMapping `json:"additionalVolumes,omitempty"`
 
 	// +optional
 	MaxPodsPerNode int `json:"maxPodsPerNode,omitempty"`
@@ -671,6 +820,35 @@ type NodeGroup struct {
 
 	// +optional
 	KubeletExtraConfig *InlineDocument `json:"kubeletExtraConfig,omitempty"`
+
+	// +optional
+	InstanceStore *NodeGroupInstanceStore `json:"instanceStore,omitempty"`
+
+	// GMSA allows the Windows containers of the nodegroup to use group Managed Service Accounts
+	// +optional
+	GMSA *NodeGroupGMSA `json:"gmsa,omitempty"`
+
+	// Account creates the nodegroup in another account than the control plane, e.g. a
+	// workload account the VPC of the cluster is shared with
+	// +optional
+	Account *NodeGroupAccount `json:"account,omitempty"`
+}
+
+// VolumeMapping defines an additional EBS volume attached to each node of a nodegroup,
+// the volume uses the same encryption settings as the root volume
+type VolumeMapping struct {
+	// Name is the block device name, e.g. /dev/xvdb
+	Name string `json:"name"`
+	Size *int   `json:"size"`
+	// +optional
+	Type *string `json:"type,omitempty"`
+	// +optional
+	IOPS *int `json:"iops,omitempty"`
+	// +optional
+	Throughput *int `json:"throughput,omitempty"`
+	// +optional
+	// MountPath is where the volume is formatted and mounted, if set
+	MountPath string `json:"mountPath,omitempty"`
 }
 
 // ListOptions returns metav1.ListOptions with label selector for the nodegroup
@@ -777,6 +955,22 @@ type (
 		SpotAllocationStrategy *string `json:"spotAllocationStrategy,omitempty"`
 	}
 
+	// InstanceSelector selects the instance types of a nodegroup by their resources, like the
+	// ec2-instance-selector; the instance types are resolved when the nodegroup is created
+	InstanceSelector struct {
+		// +optional
+		VCPUs int `json:"vCPUs,omitempty"`
+		// Memory in GiB, e.g. "16" or "16GiB", or in MiB, e.g. "512MiB"
+		// +optional
+		Memory string `json:"memory,omitempty"`
+		// GPUs is the number of GPUs, instance types with GPUs are only selected when it's set
+		// +optional
+		GPUs int `json:"gpus,omitempty"`
+		// CPUArchitecture is x86_64 (default) or arm64
+		// +optional
+		CPUArchitecture string `json:"cpuArchitecture,omitempty"`
+	}
+
 	// NodeGroupBottlerocket holds the configuration for Bottlerocket based
 	// NodeGroups.
 	NodeGroupBottlerocket struct {
@@ -785,6 +979,42 @@ type (
 		// +optional
 		Settings *InlineDocument `json:"settings,omitempty"`
 	}
+
+	// NodeGroupInstanceStore holds the configuration for the local NVMe
+	// instance store volumes of a NodeGroup
+	NodeGroupInstanceStore struct {
+		// +optional
+		RAID0     *bool  `json:"raid0,omitempty"`
+		MountPath string `json:"mountPath"`
+	}
+
+	// NodeGroupAccount holds the role eksctl assumes to create a NodeGroup in another account
+	// than the control plane
+	NodeGroupAccount struct {
+		// RoleARN is the role in the account of the nodegroup, it's assumed with the
+		// credentials used for the cluster
+		RoleARN string `json:"roleARN"`
+		// +optional
+		ExternalID string `json:"externalID,omitempty"`
+	}
+
+	// NodeGroupGMSA holds the configuration for group Managed Service Accounts (gMSA) in the
+	// Windows containers of a NodeGroup
+	NodeGroupGMSA struct {
+		// DomainlessSecretARN is the ARN of the Secrets Manager secret holding the credentials of
+		// the domain user the CCG plugin of the nodes retrieves gMSA passwords with, so that the
+		// nodes don't need to be joined to the domain
+		// +optional
+		DomainlessSecretARN string `json:"domainlessSecretARN,omitempty"`
+		// SecretKMSKeyARN is the ARN of the KMS key the secret is encrypted with, when it isn't
+		// the AWS managed key of Secrets Manager
+		// +optional
+		SecretKMSKeyARN string `json:"secretKMSKeyARN,omitempty"`
+		// DirectoryServiceAccess allows the nodes to be joined to an AWS Directory Service
+		// domain with Systems Manager, for gMSA on domain-joined nodes
+		// +optional
+		DirectoryServiceAccess *bool `json:"directoryServiceAccess,omitempty"`
+	}
 )
 
 // ScalingConfig defines the scaling config
@@ -807,9 +1037,16 @@ type ManagedNodeGroup struct {
 	// +optional
 	InstanceType string `json:"instanceType,omitempty"`
 	// +optional
+	InstanceSelector *InstanceSelector `json:"instanceSelector,omitempty"`
+	// +optional
 	*ScalingConfig `json:",inline"`
 	// +optional
 	VolumeSize *int `json:"volumeSize,omitempty"`
+	// VolumeEncrypted encrypts the root volume, which requires a launch template generated by eksctl
+	// +optional
+	VolumeEncrypted *bool `json:"volumeEncrypted,omitempty"`
+	// +optional
+	VolumeKmsKeyID *string `json:"volumeKmsKeyID,omitempty"`
 	// +optional
 	AvailabilityZones []string `json:"availabilityZones,omitempty"`
 	// +optional
@@ -823,6 +1060,11 @@ type ManagedNodeGroup struct {
 	Tags map[string]string `json:"tags,omitempty"`
 	// +optional
 	IAM *NodeGroupIAM `json:"iam,omitempty"`
+
+	// +optional
+	MaxPodsPerNode int `json:"maxPodsPerNode,omitempty"`
+	// +optional
+	KubeletExtraConfig *InlineDocument `json:"kubeletExtraConfig,omitempty"`
 }
 
 // ListOptions returns metav1.ListOptions with label selector for the managed nodegroup
@@ -848,6 +1090,12 @@ func (n *ManagedNodeGroup) GetAMIFamily() string {
 	return n.AMIFamily
 }
 
+// RequiresLaunchTemplate reports whether the managed nodegroup needs a custom
+// launch template generated by eksctl in order to customize the kubelet or the root volume
+func (n *ManagedNodeGroup) RequiresLaunchTemplate() bool {
+	return n.MaxPodsPerNode != 0 || n.KubeletExtraConfig != nil || IsEnabled(n.VolumeEncrypted)
+}
+
 func makeListOptions(nodeGroupName string) metav1.ListOptions {
 	return metav1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s=%s", NodeGroupNameLabel, nodeGroupName),
@@ -872,6 +1120,16 @@ func HasMixedInstances(ng *NodeGroup) bool {
 	return ng.InstancesDistribution != nil && len(ng.InstancesDistribution.InstanceTypes) > 0
 }
 
+// HasSpotInstances reports whether the nodegroup may run Spot instances, i.e. not all
+// of its capacity above the on-demand base capacity is on-demand
+func HasSpotInstances(ng *NodeGroup) bool {
+	if !HasMixedInstances(ng) {
+		return false
+	}
+	onDemandPercentage := ng.InstancesDistribution.OnDemandPercentageAboveBaseCapacity
+	return onDemandPercentage != nil && *onDemandPercentage < 100
+}
+
 // IsAMI returns true if the argument is an AMI ID
 func IsAMI(amiFlag string) bool {
 	return strings.HasPrefix(amiFlag, "ami-")
@@ -892,6 +1150,11 @@ type FargateProfile struct {
 
 	// +optional
 	Tags map[string]string `json:"tags,omitempty"`
+
+	// +optional
+	// Logging ships the logs of the pods of the profile, through the Fluent Bit log router
+	// EKS runs in Fargate pods
+	Logging *FargateLogging `json:"logging,omitempty"`
 }
 
 // FargateProfileSelector defines rules to select workload to schedule onto Fargate.
@@ -907,3 +1170,314 @@ type FargateProfileSelector struct {
 type SecretsEncryption struct {
 	KeyARN *string `json:"keyARN,omitempty"`
 }
+
+// NodeGroupDefaults holds settings that are applied to every nodegroup
+// which doesn't set them explicitly
+type NodeGroupDefaults struct {
+	// +optional
+	VolumeEncryption *VolumeEncryption `json:"volumeEncryption,omitempty"`
+}
+
+// VolumeEncryption defines the encryption of the root volume of the nodes
+type VolumeEncryption struct {
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// +optional
+	KMSKeyARN *string `json:"kmsKeyARN,omitempty"`
+}
+
+// NodeTerminationHandler holds the configuration of AWS Node Termination Handler,
+// which drains nodes of unmanaged nodegroups before they are interrupted or terminated
+type NodeTerminationHandler struct {
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Mode is either "imds" (default), where the handler runs on every node and watches
+	// the instance metadata, or "queue", where it processes the events from an SQS queue
+	// +optional
+	Mode string `json:"mode,omitempty"`
+}
+
+// Values for `NodeTerminationHandler.Mode`
+const (
+	NodeTerminationHandlerModeIMDS  = "imds"
+	NodeTerminationHandlerModeQueue = "queue"
+)
+
+const (
+	// NodeTerminationHandlerName is the name of AWS Node Termination Handler
+	// objects and of its IAM service account in queue mode
+	NodeTerminationHandlerName = "aws-node-termination-handler"
+	// NodeTerminationHandlerNamespace is the namespace AWS Node Termination Handler is deployed to
+	NodeTerminationHandlerNamespace = "kube-system"
+	// NodeTerminationHandlerManagedASGTag marks the ASGs whose lifecycle hooks
+	// AWS Node Termination Handler completes in queue mode
+	NodeTerminationHandlerManagedASGTag = "aws-node-termination-handler/managed"
+)
+
+// HasWindowsGMSA reports whether any nodegroup uses group Managed Service Accounts, which
+// requires the gMSA admission webhook
+func (c *ClusterConfig) HasWindowsGMSA() bool {
+	for _, ng := range c.NodeGroups {
+		if ng.GMSA != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// HasNodeTerminationHandler reports whether AWS Node Termination Handler is enabled
+func (c *ClusterConfig) HasNodeTerminationHandler() bool {
+	return c.NodeTerminationHandler != nil && IsEnabled(c.NodeTerminationHandler.Enabled)
+}
+
+// HasNodeTerminationHandlerQueue reports whether AWS Node Termination Handler is enabled
+// in queue mode, which requires the queue to be created along with the cluster
+func (c *ClusterConfig) HasNodeTerminationHandlerQueue() bool {
+	return c.HasNodeTerminationHandler() && c.NodeTerminationHandler.Mode == NodeTerminationHandlerModeQueue
+}
+
+// HasNodeGroupVolumeEncryptionDefaults reports whether root volume encryption
+// is enabled for all nodegroups by default
+func (c *ClusterConfig) HasNodeGroupVolumeEncryptionDefaults() bool {
+	return c.NodeGroupDefaults != nil && c.NodeGroupDefaults.VolumeEncryption != nil && IsEnabled(c.NodeGroupDefaults.VolumeEncryption.Enabled)
+}
+
+// ClusterHooks are run at lifecycle points of the cluster, e.g. to register
+// the cluster with internal systems
+type ClusterHooks struct {
+	// PreCreate hooks run before any resources of the cluster are created
+	// +optional
+	PreCreate []Hook `json:"preCreate,omitempty"`
+	// PostCreate hooks run once the cluster and its initial nodegroups are ready
+	// +optional
+	PostCreate []Hook `json:"postCreate,omitempty"`
+	// PreDelete hooks run before any resources of the cluster are deleted
+	// +optional
+	PreDelete []Hook `json:"preDelete,omitempty"`
+	// PostDelete hooks run once the cluster has been deleted
+	// +optional
+	PostDelete []Hook `json:"postDelete,omitempty"`
+	// PostNodeGroupCreate hooks run for every nodegroup created with
+	// `eksctl create nodegroup`, once its nodes are ready
+	// +optional
+	PostNodeGroupCreate []Hook `json:"postNodeGroupCreate,omitempty"`
+}
+
+// Hook is either a local command, which receives the cluster details as environment
+// variables and the cluster config as JSON on stdin, or a manifest file to apply
+type Hook struct {
+	// +optional
+	Command []string `json:"command,omitempty"`
+	// Manifest is the path of a file with Kubernetes objects to create or replace
+	// +optional
+	Manifest string `json:"manifest,omitempty"`
+}
+
+// Lifecycle points of ClusterHooks
+const (
+	HookPreCreate           = "preCreate"
+	HookPostCreate          = "postCreate"
+	HookPreDelete           = "preDelete"
+	HookPostDelete          = "postDelete"
+	HookPostNodeGroupCreate = "postNodeGroupCreate"
+)
+
+// HooksFor returns the hooks to run at the given lifecycle point
+func (c *ClusterConfig) HooksFor(point string) []Hook {
+	if c.Hooks == nil {
+		return nil
+	}
+	switch point {
+	case HookPreCreate:
+		return c.Hooks.PreCreate
+	case HookPostCreate:
+		return c.Hooks.PostCreate
+	case HookPreDelete:
+		return c.Hooks.PreDelete
+	case HookPostDelete:
+		return c.Hooks.PostDelete
+	case HookPostNodeGroupCreate:
+		return c.Hooks.PostNodeGroupCreate
+	}
+	return nil
+}
+
+// Notifications configures where events about operations on the cluster, e.g. "ClusterCreated"
+// or "ClusterDeleteFailed", are published; any combination of destinations can be set
+type Notifications struct {
+	// SNSTopicARN is the ARN of an SNS topic to publish events to
+	// +optional
+	SNSTopicARN string `json:"snsTopicARN,omitempty"`
+	// EventBusName is the name or ARN of an EventBridge event bus to put events on
+	// +optional
+	EventBusName string `json:"eventBusName,omitempty"`
+	// WebhookURL is an HTTPS URL events are posted to as JSON, in a format
+	// accepted by Slack incoming webhooks
+	// +optional
+	WebhookURL string `json:"webhookURL,omitempty"`
+}
+
+// DefaultAPIServerErrorThreshold is the default number of 5xx responses of the API server
+// in 5 minutes above which the API server alarm goes off
+const DefaultAPIServerErrorThreshold = 10
+
+// Alarms configures the default CloudWatch alarms of the cluster and its nodegroups, i.e.
+// nodegroups below their desired capacity, failed node status checks, packets dropped by
+// NAT gateways and API server errors; they notify an SNS topic
+type Alarms struct {
+	// Enabled creates the default alarms
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// SNSTopicARN is an existing SNS topic the alarms notify, by default eksctl creates one
+	// in the cluster stack
+	// +optional
+	SNSTopicARN string `json:"snsTopicARN,omitempty"`
+	// EmailSubscriptions are email addresses subscribed to the topic eksctl creates
+	// +optional
+	EmailSubscriptions []string `json:"emailSubscriptions,omitempty"`
+	// APIServerErrorThreshold is the number of 5xx responses of the API server in 5 minutes
+	// above which the API server alarm goes off, it requires the audit logs; defaults to 10
+	// +optional
+	APIServerErrorThreshold *int `json:"apiServerErrorThreshold,omitempty"`
+}
+
+// HasAlarms reports whether the default CloudWatch alarms are enabled
+func (c *ClusterConfig) HasAlarms() bool {
+	return c.Alarms != nil && IsEnabled(c.Alarms.Enabled)
+}
+
+// Budget keeps the estimated cost of the cluster within a limit, e.g. in education or sandbox
+// accounts; the estimate covers the control plane, the NAT gateways and the nodegroups at
+// on-demand prices
+type Budget struct {
+	// MaxMonthlyUSD is the estimated monthly cost in USD above which creating the cluster,
+	// creating nodegroups and scaling nodegroups is refused, unless --override-budget is set
+	// +optional
+	MaxMonthlyUSD *float64 `json:"maxMonthlyUSD,omitempty"`
+}
+
+// HasBudget reports whether the estimated cost of the cluster is limited
+func (c *ClusterConfig) HasBudget() bool {
+	return c.Budget != nil && c.Budget.MaxMonthlyUSD != nil
+}
+
+// Billing configures how the costs of the cluster are reported in the Billing console
+type Billing struct {
+	// ActivateCostAllocationTags activates the eks:cluster-name tag as a cost allocation
+	// tag in the payer account, so that costs can be grouped by cluster
+	// +optional
+	ActivateCostAllocationTags *bool `json:"activateCostAllocationTags,omitempty"`
+}
+
+// Values for `AuthenticationMode`
+const (
+	// AuthenticationModeConfigMap authenticates IAM principals with the aws-auth ConfigMap only
+	AuthenticationModeConfigMap = "CONFIG_MAP"
+	// AuthenticationModeAPIAndConfigMap authenticates IAM principals with both EKS access entries and the aws-auth ConfigMap
+	AuthenticationModeAPIAndConfigMap = "API_AND_CONFIG_MAP"
+	// AuthenticationModeAPI authenticates IAM principals with EKS access entries only
+	AuthenticationModeAPI = "API"
+)
+
+// AccessConfig configures how IAM principals are authenticated to the cluster
+type AccessConfig struct {
+	// AuthenticationMode is one of CONFIG_MAP, API_AND_CONFIG_MAP and API; a cluster can
+	// only move towards API, which is irreversible
+	// +optional
+	AuthenticationMode string `json:"authenticationMode,omitempty"`
+}
+
+// SupportedAuthenticationModes are the valid values of `AuthenticationMode`
+func SupportedAuthenticationModes() []string {
+	return []string{AuthenticationModeConfigMap, AuthenticationModeAPIAndConfigMap, AuthenticationModeAPI}
+}
+
+// HasAuthenticationMode reports whether an authentication mode is set
+func (c *ClusterConfig) HasAuthenticationMode() bool {
+	return c.AccessConfig != nil && c.AccessConfig.AuthenticationMode != ""
+}
+
+// KubernetesNetworkConfig configures the Kubernetes network of the cluster
+type KubernetesNetworkConfig struct {
+	// ServiceIPv4CIDR is the CIDR Kubernetes service IPs are assigned from; it can only be
+	// set when creating the cluster. When unset, EKS picks 10.100.0.0/16 or 172.20.0.0/16
+	// +optional
+	ServiceIPv4CIDR string `json:"serviceIPv4CIDR,omitempty"`
+	// IPFamily is the IP family pods and services are assigned addresses from, one of
+	// IPv4 or IPv6; it can only be set when creating the cluster. Defaults to IPv4
+	// +optional
+	IPFamily string `json:"ipFamily,omitempty"`
+}
+
+// Values for `KubernetesNetworkConfig.IPFamily`
+const (
+	IPV4Family = "IPv4"
+	IPV6Family = "IPv6"
+)
+
+// MinIPv6Version is the earliest Kubernetes version EKS supports IPv6 clusters with
+const MinIPv6Version = "1.21"
+
+// SupportedIPFamilies returns the IP families a cluster can use
+func SupportedIPFamilies() []string {
+	return []string{IPV4Family, IPV6Family}
+}
+
+// HasServiceIPv4CIDR reports whether a service CIDR is set
+func (c *ClusterConfig) HasServiceIPv4CIDR() bool {
+	return c.KubernetesNetworkConfig != nil && c.KubernetesNetworkConfig.ServiceIPv4CIDR != ""
+}
+
+// IPv6Enabled reports whether pods and services are assigned IPv6 addresses
+func (c *ClusterConfig) IPv6Enabled() bool {
+	return c.KubernetesNetworkConfig != nil && c.KubernetesNetworkConfig.IPFamily == IPV6Family
+}
+
+// ClusterDNSIP returns the IP of the cluster DNS service, which EKS assigns as the tenth
+// address of the service CIDR, or an empty string if no service CIDR is set
+func (c *ClusterConfig) ClusterDNSIP() string {
+	if !c.HasServiceIPv4CIDR() {
+		return ""
+	}
+	_, serviceCIDR, err := net.ParseCIDR(c.KubernetesNetworkConfig.ServiceIPv4CIDR)
+	if err != nil {
+		return ""
+	}
+	ip := serviceCIDR.IP.To4()
+	if ip == nil {
+		return ""
+	}
+	dnsIP := make(net.IP, len(ip))
+	copy(dnsIP, ip)
+	dnsIP[3] += 10
+	return dnsIP.String()
+}
+
+// ClusterCloudFormation configures the CloudFormation stacks eksctl creates for the cluster
+type ClusterCloudFormation struct {
+	// DisableRollback keeps the resources of stacks that fail to be created, for troubleshooting;
+	// such stacks must be deleted before creating them again
+	// +optional
+	DisableRollback *bool `json:"disableRollback,omitempty"`
+	// TerminationProtection protects the stacks from deletion outside of eksctl, eksctl
+	// disables it when deleting them
+	// +optional
+	TerminationProtection *bool `json:"terminationProtection,omitempty"`
+	// ProtectClusterResources sets a stack policy on the cluster stack that forbids
+	// replacing or deleting the VPC and the control plane in stack updates
+	// +optional
+	ProtectClusterResources *bool `json:"protectClusterResources,omitempty"`
+	// NodeGroupBatchSize limits how many nodegroup stacks are created or deleted at the
+	// same time, to avoid API throttling with many nodegroups; all at once when unset
+	// +optional
+	NodeGroupBatchSize *int `json:"nodeGroupBatchSize,omitempty"`
+	// IAMServiceAccountConcurrency limits how many iamserviceaccount stacks are created at the
+	// same time, defaults to 10
+	// +optional
+	IAMServiceAccountConcurrency *int `json:"iamServiceAccountConcurrency,omitempty"`
+}
+
+// HasCostAllocationTags reports whether cost allocation tags should be activated
+func (c *ClusterConfig) HasCostAllocationTags() bool {
+	return c.Billing != nil && IsEnabled(c.Billing.ActivateCostAllocationTags)
+}
@@ -3,9 +3,11 @@ package v1alpha5
 import (
 	"fmt"
 	"net"
+	"regexp"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/blang/semver"
 	"github.com/pkg/errors"
 
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -38,6 +40,32 @@ func (s nameSet) checkUnique(path, name string) (bool, error) {
 
 // ValidateClusterConfig checks compatible fields of a given ClusterConfig
 func ValidateClusterConfig(cfg *ClusterConfig) error {
+	if cfg.NodeTerminationHandler != nil {
+		if err := validateNodeTerminationHandler(cfg); err != nil {
+			return err
+		}
+	}
+
+	if err := validateAddons(cfg.Metadata.Name, cfg.Addons); err != nil {
+		return err
+	}
+
+	if err := validateClusterSettings(cfg.ClusterSettings); err != nil {
+		return err
+	}
+
+	if err := validateContainerRegistryOverride(cfg.ContainerRegistryOverride); err != nil {
+		return err
+	}
+
+	if err := validateFargateLogging(cfg.FargateProfiles); err != nil {
+		return err
+	}
+
+	if err := validateFargatePodExecutionRoles(cfg); err != nil {
+		return err
+	}
+
 	if IsDisabled(cfg.IAM.WithOIDC) && len(cfg.IAM.ServiceAccounts) > 0 {
 		return fmt.Errorf("iam.withOIDC must be enabled explicitly for iam.serviceAccounts to be created")
 	}
@@ -51,8 +79,16 @@ func ValidateClusterConfig(cfg *ClusterConfig) error {
 		if ok, err := saNames.checkUnique("<namespace>/<name> of "+path, sa.NameString()); !ok {
 			return err
 		}
-		if len(sa.AttachPolicyARNs) == 0 && sa.AttachPolicy == nil {
-			return fmt.Errorf("%s.attachPolicyARNs or %s.attachPolicy must be set", path, path)
+		if err := validateIAMServiceAccountRole(cfg.Metadata.Name, sa, path); err != nil {
+			return err
+		}
+		if IsEnabled(sa.RoleOnly) {
+			if sa.AttachRoleARN != "" {
+				return fmt.Errorf("%[1]s.roleOnly cannot be enabled along with %[1]s.attachRoleARN, eksctl would neither create a role nor a serviceaccount", path)
+			}
+			if sa.AutomountServiceAccountToken != nil {
+				return fmt.Errorf("%[1]s.automountServiceAccountToken cannot be set when %[1]s.roleOnly is enabled", path)
+			}
 		}
 	}
 
@@ -73,6 +109,11 @@ func ValidateClusterConfig(cfg *ClusterConfig) error {
 		if err := validateNg(ng.NameString(), path); err != nil {
 			return err
 		}
+		if ng.Account != nil {
+			if err := validateNodeGroupAccount(cfg, ng, path); err != nil {
+				return err
+			}
+		}
 	}
 
 	for i, ng := range cfg.ManagedNodeGroups {
@@ -96,6 +137,46 @@ func ValidateClusterConfig(cfg *ClusterConfig) error {
 		}
 	}
 
+	if cfg.VPC != nil && len(cfg.VPC.ControlPlaneSubnetIDs) > 0 {
+		subnetIDs := nameSet{}
+		for i, subnetID := range cfg.VPC.ControlPlaneSubnetIDs {
+			if _, err := subnetIDs.checkUnique(fmt.Sprintf("vpc.controlPlaneSubnetIDs[%d]", i), subnetID); err != nil {
+				return err
+			}
+		}
+		if len(cfg.VPC.ControlPlaneSubnetIDs) < MinRequiredSubnets {
+			return fmt.Errorf("vpc.controlPlaneSubnetIDs must have at least %d subnets", MinRequiredSubnets)
+		}
+	}
+
+	if cfg.VPC != nil && len(cfg.VPC.SecurityGroupIDs) > 0 {
+		if err := validateControlPlaneSecurityGroups(cfg.VPC); err != nil {
+			return err
+		}
+	}
+
+	if cfg.VPC != nil && cfg.VPC.PodSecurityGroups != nil && IsEnabled(cfg.VPC.PodSecurityGroups.CreateSamplePolicy) && !cfg.HasPodSecurityGroups() {
+		return fmt.Errorf("vpc.podSecurityGroups.createSamplePolicy requires vpc.podSecurityGroups.enabled")
+	}
+
+	if cfg.HasPrivateHostedZone() {
+		if err := validatePrivateHostedZone(cfg); err != nil {
+			return err
+		}
+	}
+
+	if cfg.VPC != nil && (cfg.VPC.TransitGatewayID != "" || len(cfg.VPC.TransitGatewayCIDRs) > 0 || len(cfg.VPC.TransitGatewayTags) > 0 || len(cfg.VPC.Peering) > 0) {
+		if err := validateVPCAttachments(cfg.VPC); err != nil {
+			return err
+		}
+	}
+
+	if cfg.VPC != nil && cfg.VPC.FlowLogs != nil {
+		if err := validateVPCFlowLogs(cfg.VPC); err != nil {
+			return err
+		}
+	}
+
 	if cfg.VPC != nil && len(cfg.VPC.PublicAccessCIDRs) > 0 {
 		cidrs, err := validateCIDRs(cfg.VPC.PublicAccessCIDRs)
 		if err != nil {
@@ -104,9 +185,325 @@ func ValidateClusterConfig(cfg *ClusterConfig) error {
 		cfg.VPC.PublicAccessCIDRs = cidrs
 	}
 
+	if cfg.NodeGroupDefaults != nil {
+		if err := validateVolumeEncryptionDefaults(cfg); err != nil {
+			return err
+		}
+	}
+
+	if len(cfg.Metadata.RequiredTags) > 0 {
+		if err := validateRequiredTags(cfg); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Hooks != nil {
+		if err := validateHooks(cfg); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Notifications != nil {
+		if err := validateNotifications(cfg.Notifications); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Alarms != nil {
+		if err := validateAlarms(cfg.Alarms); err != nil {
+			return err
+		}
+	}
+
+	if cfg.HasBudget() && *cfg.Budget.MaxMonthlyUSD <= 0 {
+		return fmt.Errorf("budget.maxMonthlyUSD must be greater than 0")
+	}
+
+	if cfg.CloudFormation != nil && cfg.CloudFormation.NodeGroupBatchSize != nil && *cfg.CloudFormation.NodeGroupBatchSize < 1 {
+		return fmt.Errorf("cloudFormation.nodeGroupBatchSize must be at least 1")
+	}
+
+	if cfg.CloudFormation != nil && cfg.CloudFormation.IAMServiceAccountConcurrency != nil && *cfg.CloudFormation.IAMServiceAccountConcurrency < 1 {
+		return fmt.Errorf("cloudFormation.iamServiceAccountConcurrency must be at least 1")
+	}
+
+	if cfg.HasServiceIPv4CIDR() {
+		if err := validateKubernetesNetworkConfig(cfg); err != nil {
+			return err
+		}
+	}
+
+	if cfg.KubernetesNetworkConfig != nil && cfg.KubernetesNetworkConfig.IPFamily != "" {
+		if err := validateIPFamily(cfg); err != nil {
+			return err
+		}
+	}
+
+	if cfg.HasAuthenticationMode() {
+		if err := ValidateAuthenticationMode(cfg.AccessConfig.AuthenticationMode); err != nil {
+			return errors.Wrap(err, "accessConfig.authenticationMode")
+		}
+	}
+
+	return nil
+}
+
+// ValidateAuthenticationMode checks that mode is a supported authentication mode
+func ValidateAuthenticationMode(mode string) error {
+	for _, supported := range SupportedAuthenticationModes() {
+		if mode == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid authentication mode %q, must be one of %s", mode, strings.Join(SupportedAuthenticationModes(), ", "))
+}
+
+// serviceCIDRRanges are the ranges EKS accepts a service CIDR from
+var serviceCIDRRanges = []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10"}
+
+func validateKubernetesNetworkConfig(cfg *ClusterConfig) error {
+	const path = "kubernetesNetworkConfig.serviceIPv4CIDR"
+	serviceCIDR := cfg.KubernetesNetworkConfig.ServiceIPv4CIDR
+	ip, ipNet, err := net.ParseCIDR(serviceCIDR)
+	if err != nil || ip.To4() == nil {
+		return fmt.Errorf("%s must be an IPv4 CIDR, got %q", path, serviceCIDR)
+	}
+	if !ip.Equal(ipNet.IP) {
+		return fmt.Errorf("%s must be a network address, e.g. %s", path, ipNet.String())
+	}
+	if prefix, _ := ipNet.Mask.Size(); prefix < 12 || prefix > 24 {
+		return fmt.Errorf("%s must have a prefix length between /12 and /24, got /%d", path, prefix)
+	}
+
+	inRange := false
+	for _, r := range serviceCIDRRanges {
+		_, rangeNet, _ := net.ParseCIDR(r)
+		if cidrContains(rangeNet, ipNet) {
+			inRange = true
+			break
+		}
+	}
+	if !inRange {
+		return fmt.Errorf("%s must be within one of %s", path, strings.Join(serviceCIDRRanges, ", "))
+	}
+
+	if cfg.VPC != nil && cfg.VPC.CIDR != nil && cidrsOverlap(&cfg.VPC.CIDR.IPNet, ipNet) {
+		return fmt.Errorf("%s (%s) must not overlap with vpc.cidr (%s)", path, serviceCIDR, cfg.VPC.CIDR.String())
+	}
+	return nil
+}
+
+// validateIPFamily checks that an IPv6 cluster only uses what EKS supports with IPv6: pods get
+// their addresses from the IPv6 CIDRs of the subnets, the VPC CNI needs IRSA to assign them,
+// and only nodes bootstrapped by EKS know the IPv6 service CIDR of the cluster
+func validateIPFamily(cfg *ClusterConfig) error {
+	const path = "kubernetesNetworkConfig.ipFamily"
+	ipFamily := cfg.KubernetesNetworkConfig.IPFamily
+	if ipFamily != IPV4Family && ipFamily != IPV6Family {
+		return fmt.Errorf("invalid value %q for %s, must be one of %s", ipFamily, path, strings.Join(SupportedIPFamilies(), ", "))
+	}
+	if ipFamily == IPV4Family {
+		return nil
+	}
+
+	if cfg.Metadata.Version != "" {
+		version, err := semver.ParseTolerant(cfg.Metadata.Version)
+		if err != nil {
+			return fmt.Errorf("unable to parse metadata.version %q", cfg.Metadata.Version)
+		}
+		if minVersion, _ := semver.ParseTolerant(MinIPv6Version); version.LT(minVersion) {
+			return fmt.Errorf("%s %s requires Kubernetes %s or later", path, IPV6Family, MinIPv6Version)
+		}
+	}
+	if cfg.HasServiceIPv4CIDR() {
+		return fmt.Errorf("kubernetesNetworkConfig.serviceIPv4CIDR cannot be set when %s is %s", path, IPV6Family)
+	}
+	if !IsEnabled(cfg.IAM.WithOIDC) {
+		return fmt.Errorf("iam.withOIDC must be enabled when %s is %s", path, IPV6Family)
+	}
+	if cfg.VPC != nil && cfg.VPC.ID == "" && !cfg.HasAnySubnets() && !IsEnabled(cfg.VPC.AutoAllocateIPv6) {
+		return fmt.Errorf("vpc.autoAllocateIPv6 must be enabled when %s is %s", path, IPV6Family)
+	}
+	if len(cfg.NodeGroups) > 0 {
+		return fmt.Errorf("nodeGroups are not supported when %s is %s, use managedNodeGroups instead", path, IPV6Family)
+	}
+	for i, ng := range cfg.ManagedNodeGroups {
+		if ng.RequiresLaunchTemplate() {
+			return fmt.Errorf("managedNodeGroups[%d].maxPodsPerNode and managedNodeGroups[%d].kubeletExtraConfig are not supported when %s is %s", i, i, path, IPV6Family)
+		}
+	}
+	return nil
+}
+
+// cidrContains reports whether inner is a subnet of outer
+func cidrContains(outer, inner *net.IPNet) bool {
+	outerPrefix, _ := outer.Mask.Size()
+	innerPrefix, _ := inner.Mask.Size()
+	return innerPrefix >= outerPrefix && outer.Contains(inner.IP)
+}
+
+func cidrsOverlap(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+func validateNotifications(notifications *Notifications) error {
+	if notifications.SNSTopicARN != "" {
+		if _, err := arn.Parse(notifications.SNSTopicARN); err != nil {
+			return errors.Wrapf(err, "invalid ARN in notifications.snsTopicARN: %q", notifications.SNSTopicARN)
+		}
+	}
+	if notifications.WebhookURL != "" && !strings.HasPrefix(notifications.WebhookURL, "https://") {
+		return fmt.Errorf("notifications.webhookURL must be an HTTPS URL")
+	}
+	return nil
+}
+
+func validateAlarms(alarms *Alarms) error {
+	if alarms.SNSTopicARN != "" {
+		if _, err := arn.Parse(alarms.SNSTopicARN); err != nil {
+			return errors.Wrapf(err, "invalid ARN in alarms.snsTopicARN: %q", alarms.SNSTopicARN)
+		}
+		if len(alarms.EmailSubscriptions) > 0 {
+			return fmt.Errorf("alarms.emailSubscriptions can only be set when eksctl creates the topic, subscribe to alarms.snsTopicARN instead")
+		}
+	}
+	for i, email := range alarms.EmailSubscriptions {
+		if !strings.Contains(email, "@") {
+			return fmt.Errorf("alarms.emailSubscriptions[%d]: %q is not an email address", i, email)
+		}
+	}
+	if alarms.APIServerErrorThreshold != nil && *alarms.APIServerErrorThreshold < 1 {
+		return fmt.Errorf("alarms.apiServerErrorThreshold must be at least 1")
+	}
+	return nil
+}
+
+func validateHooks(cfg *ClusterConfig) error {
+	for _, point := range []string{HookPreCreate, HookPostCreate, HookPreDelete, HookPostDelete, HookPostNodeGroupCreate} {
+		for i, hook := range cfg.HooksFor(point) {
+			path := fmt.Sprintf("hooks.%s[%d]", point, i)
+			if (len(hook.Command) > 0) == (hook.Manifest != "") {
+				return fmt.Errorf("%s must set exactly one of command and manifest", path)
+			}
+			// manifests can only be applied while the cluster exists
+			if hook.Manifest != "" && (point == HookPreCreate || point == HookPostDelete) {
+				return fmt.Errorf("%s.manifest is not supported in %s hooks", path, point)
+			}
+		}
+	}
+	return nil
+}
+
+func validateNodeTerminationHandler(cfg *ClusterConfig) error {
+	switch cfg.NodeTerminationHandler.Mode {
+	case "", NodeTerminationHandlerModeIMDS:
+		return nil
+	case NodeTerminationHandlerModeQueue:
+		if !IsEnabled(cfg.IAM.WithOIDC) {
+			return fmt.Errorf("iam.withOIDC must be enabled explicitly for nodeTerminationHandler.mode %q", NodeTerminationHandlerModeQueue)
+		}
+		return nil
+	default:
+		return fmt.Errorf("nodeTerminationHandler.mode must be either %q or %q", NodeTerminationHandlerModeIMDS, NodeTerminationHandlerModeQueue)
+	}
+}
+
+func validateVolumeEncryptionDefaults(cfg *ClusterConfig) error {
+	volumeEncryption := cfg.NodeGroupDefaults.VolumeEncryption
+	if volumeEncryption == nil {
+		return nil
+	}
+
+	const path = "nodeGroupDefaults.volumeEncryption"
+	if IsSetAndNonEmptyString(volumeEncryption.KMSKeyARN) {
+		if !IsEnabled(volumeEncryption.Enabled) {
+			return fmt.Errorf("%s.kmsKeyARN can not be set without %s.enabled enabled explicitly", path, path)
+		}
+		if _, err := arn.Parse(*volumeEncryption.KMSKeyARN); err != nil {
+			return errors.Wrapf(err, "invalid ARN in %s.kmsKeyARN: %q", path, *volumeEncryption.KMSKeyARN)
+		}
+	}
+
+	return nil
+}
+
+// validateRequiredTags checks the tags each resource will be created with against
+// metadata.requiredTags, so that tag policies are enforced before anything is created
+func validateRequiredTags(cfg *ClusterConfig) error {
+	patterns := make([]*regexp.Regexp, len(cfg.Metadata.RequiredTags))
+	for i, requiredTag := range cfg.Metadata.RequiredTags {
+		path := fmt.Sprintf("metadata.requiredTags[%d]", i)
+		if requiredTag.Key == "" {
+			return fmt.Errorf("%s.key must be set", path)
+		}
+		if requiredTag.Pattern != "" {
+			re, err := regexp.Compile(requiredTag.Pattern)
+			if err != nil {
+				return errors.Wrapf(err, "invalid %s.pattern", path)
+			}
+			patterns[i] = re
+		}
+	}
+
+	checkTags := func(tags map[string]string, path string) error {
+		for i, requiredTag := range cfg.Metadata.RequiredTags {
+			value, ok := tags[requiredTag.Key]
+			if !ok {
+				return fmt.Errorf("tag %q is required by metadata.requiredTags but is not set in %s", requiredTag.Key, path)
+			}
+			if re := patterns[i]; re != nil && !re.MatchString(value) {
+				return fmt.Errorf("value %q of tag %q in %s doesn't match pattern %q", value, requiredTag.Key, path, requiredTag.Pattern)
+			}
+			if len(requiredTag.AllowedValues) > 0 && !isAllowedTagValue(value, requiredTag.AllowedValues) {
+				return fmt.Errorf("value %q of tag %q in %s must be one of %s", value, requiredTag.Key, path, strings.Join(requiredTag.AllowedValues, ", "))
+			}
+		}
+		return nil
+	}
+
+	// metadata.tags are applied to all the stacks and nodegroups, which may override them
+	withClusterTags := func(tags map[string]string) map[string]string {
+		merged := map[string]string{}
+		for k, v := range cfg.Metadata.Tags {
+			merged[k] = v
+		}
+		for k, v := range tags {
+			merged[k] = v
+		}
+		return merged
+	}
+
+	if err := checkTags(cfg.Metadata.Tags, "metadata.tags"); err != nil {
+		return err
+	}
+	for i, ng := range cfg.NodeGroups {
+		if err := checkTags(withClusterTags(ng.Tags), fmt.Sprintf("nodeGroups[%d].tags", i)); err != nil {
+			return err
+		}
+	}
+	for i, ng := range cfg.ManagedNodeGroups {
+		if err := checkTags(withClusterTags(ng.Tags), fmt.Sprintf("managedNodeGroups[%d].tags", i)); err != nil {
+			return err
+		}
+	}
+	// Fargate profiles are created through the EKS API and only get their own tags
+	for i, fp := range cfg.FargateProfiles {
+		if err := checkTags(fp.Tags, fmt.Sprintf("fargateProfiles[%d].tags", i)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+func isAllowedTagValue(value string, allowedValues []string) bool {
+	for _, allowed := range allowedValues {
+		if value == allowed {
+			return true
+		}
+	}
+	return false
+}
+
 // ValidateClusterEndpointConfig checks the endpoint configuration for potential issues
 func (c *ClusterConfig) ValidateClusterEndpointConfig() error {
 	if !c.HasClusterEndpointAccess() {
@@ -146,20 +543,17 @@ func ValidateNodeGroup(i int, ng *NodeGroup) error {
 		if IsSetAndNonEmptyString(ng.VolumeName) {
 			return errCantSet("volumeName")
 		}
-		if IsEnabled(ng.VolumeEncrypted) {
-			return errCantSet("volumeEncrypted")
-		}
-		if IsSetAndNonEmptyString(ng.VolumeKmsKeyID) {
-			return errCantSet("volumeKmsKeyID")
+		if ng.VolumeThroughput != nil {
+			return errCantSet("volumeThroughput")
 		}
 	}
 
-	if ng.VolumeType != nil && *ng.VolumeType == NodeVolumeTypeIO1 {
-		if ng.VolumeIOPS == nil {
-			return fmt.Errorf("%s.volumeIOPS is required for %s volume type", path, NodeVolumeTypeIO1)
-		}
-	} else if ng.VolumeIOPS != nil {
-		return fmt.Errorf("%s.volumeIOPS is only supported for %s volume type", path, NodeVolumeTypeIO1)
+	if err := validateVolumeIOPSAndThroughput(ng.VolumeType, ng.VolumeIOPS, ng.VolumeThroughput, path+".volumeIOPS", path+".volumeThroughput"); err != nil {
+		return err
+	}
+
+	if err := validateAdditionalVolumes(ng.AdditionalVolumes, path); err != nil {
+		return err
 	}
 
 	if ng.VolumeEncrypted == nil || IsDisabled(ng.VolumeEncrypted) {
@@ -216,6 +610,14 @@ func ValidateNodeGroup(i int, ng *NodeGroup) error {
 		if ng.OverrideBootstrapCommand != nil {
 			return fieldNotSupported("overrideBootstrapCommand")
 		}
+		if ng.InstanceStore != nil {
+			return fieldNotSupported("instanceStore")
+		}
+		for i, volume := range ng.AdditionalVolumes {
+			if volume.MountPath != "" {
+				return fieldNotSupported(fmt.Sprintf("additionalVolumes[%d].mountPath", i))
+			}
+		}
 
 	} else if err := validateNodeGroupKubeletExtraConfig(ng.KubeletExtraConfig); err != nil {
 		return err
@@ -228,13 +630,169 @@ func ValidateNodeGroup(i int, ng *NodeGroup) error {
 		}
 	}
 
+	if ng.InstanceSelector != nil {
+		if (ng.InstanceType != "" && ng.InstanceType != "mixed") || (ng.InstancesDistribution != nil && len(ng.InstancesDistribution.InstanceTypes) > 0) {
+			return fmt.Errorf("%s.instanceSelector cannot be used with %s.instanceType or %s.instancesDistribution.instanceTypes", path, path, path)
+		}
+		if err := validateInstanceSelector(ng.InstanceSelector, path); err != nil {
+			return err
+		}
+	}
+
 	if err := validateInstancesDistribution(ng); err != nil {
 		return err
 	}
 
+	if ng.InstanceStore != nil {
+		if err := validateInstanceStore(ng.InstanceStore, path); err != nil {
+			return err
+		}
+	}
+
+	if ng.GMSA != nil {
+		if err := validateGMSA(ng, path); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+func validateGMSA(ng *NodeGroup, path string) error {
+	gmsa := ng.GMSA
+	if !IsWindowsImage(ng.AMIFamily) {
+		return fmt.Errorf("%s.gmsa is only supported for Windows nodegroups", path)
+	}
+	if gmsa.DomainlessSecretARN == "" && !IsEnabled(gmsa.DirectoryServiceAccess) {
+		return fmt.Errorf("%s.gmsa requires either domainlessSecretARN or directoryServiceAccess", path)
+	}
+	if gmsa.DomainlessSecretARN != "" {
+		parsed, err := arn.Parse(gmsa.DomainlessSecretARN)
+		if err != nil {
+			return errors.Wrapf(err, "invalid ARN in %s.gmsa.domainlessSecretARN: %q", path, gmsa.DomainlessSecretARN)
+		}
+		if parsed.Service != "secretsmanager" {
+			return fmt.Errorf("%s.gmsa.domainlessSecretARN must be the ARN of a Secrets Manager secret, got %q", path, gmsa.DomainlessSecretARN)
+		}
+	}
+	if gmsa.SecretKMSKeyARN != "" {
+		if gmsa.DomainlessSecretARN == "" {
+			return fmt.Errorf("%s.gmsa.secretKMSKeyARN can only be set with %s.gmsa.domainlessSecretARN", path, path)
+		}
+		if _, err := arn.Parse(gmsa.SecretKMSKeyARN); err != nil {
+			return errors.Wrapf(err, "invalid ARN in %s.gmsa.secretKMSKeyARN: %q", path, gmsa.SecretKMSKeyARN)
+		}
+	}
+	// the permissions are added to the instance role eksctl creates
+	if ng.IAM != nil && (ng.IAM.InstanceRoleARN != "" || ng.IAM.InstanceProfileARN != "") {
+		return fmt.Errorf("%s.gmsa can't be used with %s.iam.instanceRoleARN or %s.iam.instanceProfileARN, add its permissions to the existing role instead", path, path, path)
+	}
+	return nil
+}
+
+// validateNodeGroupAccount checks that a nodegroup in another account can join the cluster, which
+// requires the VPC of the cluster to be shared with that account, as the stacks of the nodegroup
+// can't import the outputs of the cluster stack across accounts
+func validateNodeGroupAccount(cfg *ClusterConfig, ng *NodeGroup, path string) error {
+	if ng.Account.RoleARN == "" {
+		return fmt.Errorf("%s.account.roleARN must be set", path)
+	}
+	if _, err := arn.Parse(ng.Account.RoleARN); err != nil {
+		return errors.Wrapf(err, "invalid ARN in %s.account.roleARN: %q", path, ng.Account.RoleARN)
+	}
+	if cfg.VPC == nil || cfg.VPC.ID == "" {
+		return fmt.Errorf("%s.account requires vpc.id, the VPC of the cluster must be an existing VPC shared with the account of the nodegroup", path)
+	}
+	if ng.SecurityGroups != nil {
+		if IsEnabled(ng.SecurityGroups.WithShared) {
+			return fmt.Errorf("%s.securityGroups.withShared can't be enabled along with %s.account, the shared security group belongs to the account of the cluster", path, path)
+		}
+		if IsDisabled(ng.SecurityGroups.WithLocal) {
+			return fmt.Errorf("%s.securityGroups.withLocal can't be disabled along with %s.account, the nodes reach the control plane through their local security group", path, path)
+		}
+	}
+	return nil
+}
+
+func validateInstanceStore(instanceStore *NodeGroupInstanceStore, path string) error {
+	if instanceStore.MountPath == "" {
+		return fmt.Errorf("%s.instanceStore.mountPath must be set", path)
+	}
+	if !strings.HasPrefix(instanceStore.MountPath, "/") {
+		return fmt.Errorf("%s.instanceStore.mountPath must be an absolute path, got %q", path, instanceStore.MountPath)
+	}
+	return nil
+}
+
+func validateVolumeIOPSAndThroughput(volumeType *string, iops, throughput *int, iopsPath, throughputPath string) error {
+	isVolumeType := func(t string) bool {
+		return volumeType != nil && *volumeType == t
+	}
+
+	switch {
+	case isVolumeType(NodeVolumeTypeIO1):
+		if iops == nil {
+			return fmt.Errorf("%s is required for %s volume type", iopsPath, NodeVolumeTypeIO1)
+		}
+	case isVolumeType(NodeVolumeTypeGP3):
+		if iops != nil && (*iops < MinGP3VolumeIOPS || *iops > MaxGP3VolumeIOPS) {
+			return fmt.Errorf("%s must be between %d and %d for %s volume type", iopsPath, MinGP3VolumeIOPS, MaxGP3VolumeIOPS, NodeVolumeTypeGP3)
+		}
+	default:
+		if iops != nil {
+			return fmt.Errorf("%s is only supported for %s and %s volume types", iopsPath, NodeVolumeTypeIO1, NodeVolumeTypeGP3)
+		}
+	}
+
+	if throughput != nil {
+		if !isVolumeType(NodeVolumeTypeGP3) {
+			return fmt.Errorf("%s is only supported for %s volume type", throughputPath, NodeVolumeTypeGP3)
+		}
+		if *throughput < MinGP3VolumeThroughput || *throughput > MaxGP3VolumeThroughput {
+			return fmt.Errorf("%s must be between %d and %d", throughputPath, MinGP3VolumeThroughput, MaxGP3VolumeThroughput)
+		}
+	}
+	return nil
+}
+
+func validateAdditionalVolumes(volumes []*VolumeMapping, path string) error {
+	names := nameSet{}
+	for i, volume := range volumes {
+		volumePath := fmt.Sprintf("%s.additionalVolumes[%d]", path, i)
+		if volume.Name == "" {
+			return fmt.Errorf("%s.name must be set", volumePath)
+		}
+		if !strings.HasPrefix(volume.Name, "/dev/") {
+			return fmt.Errorf("%s.name must be a block device name, e.g. /dev/xvdb, got %q", volumePath, volume.Name)
+		}
+		if _, err := names.checkUnique(volumePath+".name", volume.Name); err != nil {
+			return err
+		}
+		if volume.Size == nil || *volume.Size <= 0 {
+			return fmt.Errorf("%s.size must be set to a positive value", volumePath)
+		}
+		if volume.Type != nil && !isSupportedVolumeType(*volume.Type) {
+			return fmt.Errorf("%s.type %q is not supported, supported values: %s", volumePath, *volume.Type, strings.Join(SupportedNodeVolumeTypes(), ", "))
+		}
+		if err := validateVolumeIOPSAndThroughput(volume.Type, volume.IOPS, volume.Throughput, volumePath+".iops", volumePath+".throughput"); err != nil {
+			return err
+		}
+		if volume.MountPath != "" && !strings.HasPrefix(volume.MountPath, "/") {
+			return fmt.Errorf("%s.mountPath must be an absolute path, got %q", volumePath, volume.MountPath)
+		}
+	}
+	return nil
+}
+
+func isSupportedVolumeType(volumeType string) bool {
+	for _, t := range SupportedNodeVolumeTypes() {
+		if volumeType == t {
+			return true
+		}
+	}
+	return false
+}
+
 // ValidateNodeGroupLabels uses proper Kubernetes label validation,
 // it's designed to make sure users don't pass weird labels to the
 // nodes, which would prevent kubelets to startup properly
@@ -377,6 +935,31 @@ func ValidateManagedNodeGroup(ng *ManagedNodeGroup, index int) error {
 		}
 	}
 
+	if err := validateNodeGroupKubeletExtraConfig(ng.KubeletExtraConfig); err != nil {
+		return err
+	}
+
+	if ng.MaxPodsPerNode < 0 {
+		return fmt.Errorf("%s.maxPodsPerNode cannot be negative", path)
+	}
+
+	if ng.InstanceSelector != nil {
+		if ng.InstanceType != "" {
+			return fmt.Errorf("%s.instanceSelector cannot be used with %s.instanceType", path, path)
+		}
+		if err := validateInstanceSelector(ng.InstanceSelector, path); err != nil {
+			return err
+		}
+	}
+
+	if ng.RequiresLaunchTemplate() && ng.SSH != nil && len(ng.SSH.SourceSecurityGroupIDs) > 0 {
+		return fmt.Errorf("%s.ssh.sourceSecurityGroupIds cannot be used with %s.maxPodsPerNode, %s.kubeletExtraConfig or %s.volumeEncrypted", path, path, path, path)
+	}
+
+	if !IsEnabled(ng.VolumeEncrypted) && IsSetAndNonEmptyString(ng.VolumeKmsKeyID) {
+		return fmt.Errorf("%s.volumeKmsKeyID can not be set without %s.volumeEncrypted enabled explicitly", path, path)
+	}
+
 	// TODO fix error messages to not use CLI flags
 	if ng.MinSize == nil {
 		if ng.DesiredCapacity == nil {
@@ -418,17 +1001,20 @@ func validateInstancesDistribution(ng *NodeGroup) error {
 	}
 
 	distribution := ng.InstancesDistribution
-	if distribution.InstanceTypes == nil || len(distribution.InstanceTypes) == 0 {
-		return fmt.Errorf("at least two instance types have to be specified for mixed nodegroups")
-	}
+	// the instance types of an instance selector are resolved when the nodegroup is created
+	if ng.InstanceSelector == nil {
+		if distribution.InstanceTypes == nil || len(distribution.InstanceTypes) == 0 {
+			return fmt.Errorf("at least two instance types have to be specified for mixed nodegroups")
+		}
 
-	allInstanceTypes := make(map[string]bool)
-	for _, instanceType := range distribution.InstanceTypes {
-		allInstanceTypes[instanceType] = true
-	}
+		allInstanceTypes := make(map[string]bool)
+		for _, instanceType := range distribution.InstanceTypes {
+			allInstanceTypes[instanceType] = true
+		}
 
-	if len(allInstanceTypes) < 1 || len(allInstanceTypes) > 20 {
-		return fmt.Errorf("mixed nodegroups should have between 1 and 20 different instance types")
+		if len(allInstanceTypes) < 1 || len(allInstanceTypes) > 20 {
+			return fmt.Errorf("mixed nodegroups should have between 1 and 20 different instance types")
+		}
 	}
 
 	if distribution.OnDemandBaseCapacity != nil && *distribution.OnDemandBaseCapacity < 0 {
@@ -587,3 +1173,190 @@ func checkBottlerocketSettings(doc *InlineDocument, path string) error {
 
 	return nil
 }
+
+func validateVPCFlowLogs(vpc *ClusterVPC) error {
+	if vpc.ID != "" {
+		return fmt.Errorf("vpc.flowLogs is only supported when eksctl creates the VPC")
+	}
+	flowLogs := vpc.FlowLogs
+	switch flowLogs.Destination {
+	case VPCFlowLogsDestinationCloudWatch, VPCFlowLogsDestinationS3:
+	default:
+		return fmt.Errorf("vpc.flowLogs.destination must be either %q or %q", VPCFlowLogsDestinationCloudWatch, VPCFlowLogsDestinationS3)
+	}
+	switch flowLogs.TrafficType {
+	case "", VPCFlowLogsTrafficTypeAccept, VPCFlowLogsTrafficTypeReject, VPCFlowLogsTrafficTypeAll:
+	default:
+		return fmt.Errorf("vpc.flowLogs.trafficType must be one of %q, %q or %q", VPCFlowLogsTrafficTypeAccept, VPCFlowLogsTrafficTypeReject, VPCFlowLogsTrafficTypeAll)
+	}
+	if flowLogs.AggregationInterval != nil && *flowLogs.AggregationInterval != 60 && *flowLogs.AggregationInterval != 600 {
+		return fmt.Errorf("vpc.flowLogs.aggregationInterval must be either 60 or 600 seconds")
+	}
+	return nil
+}
+
+// validateIAMServiceAccountRole checks that the service account either has the policies of a role
+// eksctl creates, or an existing role
+func validateIAMServiceAccountRole(clusterName string, sa *ClusterIAMServiceAccount, path string) error {
+	if sa.AttachRoleARN != "" {
+		if len(sa.AttachPolicyARNs) > 0 || sa.AttachPolicy != nil || sa.PermissionsBoundary != "" || sa.RoleName != "" {
+			return fmt.Errorf("%[1]s.attachRoleARN cannot be set along with %[1]s.attachPolicyARNs, %[1]s.attachPolicy, %[1]s.permissionsBoundary or %[1]s.roleName", path)
+		}
+		if _, err := arn.Parse(sa.AttachRoleARN); err != nil {
+			return errors.Wrapf(err, "invalid %s.attachRoleARN %q", path, sa.AttachRoleARN)
+		}
+		return nil
+	}
+
+	if len(sa.AttachPolicyARNs) == 0 && sa.AttachPolicy == nil {
+		return fmt.Errorf("%s.attachPolicyARNs or %s.attachPolicy must be set", path, path)
+	}
+	roleName, err := sa.RenderRoleName(clusterName)
+	if err != nil {
+		return errors.Wrapf(err, "invalid %s.roleName", path)
+	}
+	if len(roleName) > maxIAMRoleNameLength {
+		return fmt.Errorf("%s.roleName %q is longer than %d characters", path, roleName, maxIAMRoleNameLength)
+	}
+	return nil
+}
+
+// validateFargatePodExecutionRoles checks that the Fargate pod execution role eksctl creates is
+// only customised when eksctl creates it, and the ARNs of the existing roles
+func validateFargatePodExecutionRoles(cfg *ClusterConfig) error {
+	iam := cfg.IAM
+	if IsSetAndNonEmptyString(iam.FargatePodExecutionRoleARN) {
+		if iam.FargatePodExecutionRoleName != "" || len(iam.FargatePodExecutionRolePolicyARNs) > 0 || IsSetAndNonEmptyString(iam.FargatePodExecutionRolePermissionsBoundary) {
+			return errors.New("iam.fargatePodExecutionRoleARN cannot be set along with iam.fargatePodExecutionRoleName, iam.fargatePodExecutionRolePolicyARNs or iam.fargatePodExecutionRolePermissionsBoundary")
+		}
+		if _, err := arn.Parse(*iam.FargatePodExecutionRoleARN); err != nil {
+			return errors.Wrapf(err, "invalid iam.fargatePodExecutionRoleARN %q", *iam.FargatePodExecutionRoleARN)
+		}
+	}
+	if len(iam.FargatePodExecutionRoleName) > maxIAMRoleNameLength {
+		return fmt.Errorf("iam.fargatePodExecutionRoleName %q is longer than %d characters", iam.FargatePodExecutionRoleName, maxIAMRoleNameLength)
+	}
+	for i, policyARN := range iam.FargatePodExecutionRolePolicyARNs {
+		if _, err := arn.Parse(policyARN); err != nil {
+			return errors.Wrapf(err, "invalid iam.fargatePodExecutionRolePolicyARNs[%d] %q", i, policyARN)
+		}
+	}
+	for i, profile := range cfg.FargateProfiles {
+		if profile.PodExecutionRoleARN == "" {
+			continue
+		}
+		if _, err := arn.Parse(profile.PodExecutionRoleARN); err != nil {
+			return errors.Wrapf(err, "invalid fargateProfiles[%d].podExecutionRoleARN %q", i, profile.PodExecutionRoleARN)
+		}
+	}
+	return nil
+}
+
+// maxControlPlaneSecurityGroups is the maximum number of security groups EKS attaches to the
+// network interfaces of the control plane
+const maxControlPlaneSecurityGroups = 5
+
+// validateControlPlaneSecurityGroups checks vpc.securityGroupIDs; whether they exist in the VPC
+// and allow the traffic between the control plane and the nodes is checked against EC2
+func validateControlPlaneSecurityGroups(vpc *ClusterVPC) error {
+	if len(vpc.SecurityGroupIDs) > maxControlPlaneSecurityGroups {
+		return fmt.Errorf("vpc.securityGroupIDs must have at most %d security groups", maxControlPlaneSecurityGroups)
+	}
+	ids := nameSet{}
+	for i, id := range vpc.SecurityGroupIDs {
+		path := fmt.Sprintf("vpc.securityGroupIDs[%d]", i)
+		if !strings.HasPrefix(id, "sg-") {
+			return fmt.Errorf("%s: %q is not a security group ID", path, id)
+		}
+		if _, err := ids.checkUnique(path, id); err != nil {
+			return err
+		}
+	}
+	// vpc.securityGroup is set from the cluster stack to the first ID
+	if vpc.SecurityGroup != "" && vpc.SecurityGroup != vpc.SecurityGroupIDs[0] {
+		return fmt.Errorf("vpc.securityGroup and vpc.securityGroupIDs cannot be set at the same time")
+	}
+	return nil
+}
+
+// validateVPCAttachments checks the transit gateway and peering connections of the VPC, and that
+// their destination CIDRs can all be routed from the same route tables
+func validateVPCAttachments(vpc *ClusterVPC) error {
+	if vpc.ID != "" {
+		return fmt.Errorf("vpc.transitGatewayID and vpc.peering are only supported when eksctl creates the VPC")
+	}
+
+	destinations := nameSet{}
+	validateDestinations := func(path string, cidrs []string) error {
+		for i, cidr := range cidrs {
+			cidrPath := fmt.Sprintf("%s[%d]", path, i)
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return errors.Wrapf(err, "invalid CIDR in %s", cidrPath)
+			}
+			if vpc.CIDR != nil && cidrsOverlap(&vpc.CIDR.IPNet, ipNet) {
+				return fmt.Errorf("%s (%s) must not overlap with vpc.cidr (%s)", cidrPath, cidr, vpc.CIDR.String())
+			}
+			if _, ok := destinations[ipNet.String()]; ok {
+				return fmt.Errorf("%s (%s) is routed more than once", cidrPath, cidr)
+			}
+			destinations[ipNet.String()] = struct{}{}
+		}
+		return nil
+	}
+
+	if vpc.TransitGatewayID == "" {
+		if len(vpc.TransitGatewayCIDRs) > 0 || len(vpc.TransitGatewayTags) > 0 {
+			return fmt.Errorf("vpc.transitGatewayCIDRs and vpc.transitGatewayTags require vpc.transitGatewayID")
+		}
+	} else if !strings.HasPrefix(vpc.TransitGatewayID, "tgw-") {
+		return fmt.Errorf("vpc.transitGatewayID (%s) must be the ID of a transit gateway", vpc.TransitGatewayID)
+	}
+	if err := validateDestinations("vpc.transitGatewayCIDRs", vpc.TransitGatewayCIDRs); err != nil {
+		return err
+	}
+
+	peerVPCIDs := nameSet{}
+	for i, peering := range vpc.Peering {
+		path := fmt.Sprintf("vpc.peering[%d]", i)
+		if !strings.HasPrefix(peering.PeerVPCID, "vpc-") {
+			return fmt.Errorf("%s.peerVPCID (%s) must be the ID of a VPC", path, peering.PeerVPCID)
+		}
+		if _, err := peerVPCIDs.checkUnique(path+".peerVPCID", peering.PeerVPCID); err != nil {
+			return err
+		}
+		if peering.PeerOwnerID != "" && peering.PeerRoleARN == "" {
+			return fmt.Errorf("%s.peerRoleARN must be set to accept a peering connection with another account", path)
+		}
+		if peering.PeerRoleARN != "" {
+			if _, err := arn.Parse(peering.PeerRoleARN); err != nil {
+				return errors.Wrapf(err, "invalid ARN in %s.peerRoleARN", path)
+			}
+		}
+		if err := validateDestinations(path+".destinationCIDRs", peering.DestinationCIDRs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validatePrivateHostedZone(cfg *ClusterConfig) error {
+	if !cfg.HasPrivateEndpointAccess() {
+		return fmt.Errorf("vpc.privateHostedZone requires vpc.clusterEndpoints.privateAccess")
+	}
+	vpcIDs := cfg.VPC.PrivateHostedZone.AssociatedVPCIDs
+	if len(vpcIDs) == 0 {
+		return fmt.Errorf("vpc.privateHostedZone.associatedVPCIDs must have at least one VPC")
+	}
+	ids := nameSet{}
+	for i, vpcID := range vpcIDs {
+		path := fmt.Sprintf("vpc.privateHostedZone.associatedVPCIDs[%d]", i)
+		if _, err := ids.checkUnique(path, vpcID); err != nil {
+			return err
+		}
+		if vpcID == cfg.VPC.ID {
+			return fmt.Errorf("%s is the VPC of the cluster, where EKS already resolves the API server endpoint to its private IPs", path)
+		}
+	}
+	return nil
+}
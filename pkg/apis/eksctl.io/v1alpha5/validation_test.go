@@ -223,6 +223,61 @@ var _ = Describe("ClusterConfig validation", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
+		It("should render the role name of iam.serviceAccounts", func() {
+			cfg.IAM.WithOIDC = Enabled()
+			cfg.Metadata.Name = "cluster-1"
+
+			sa := &ClusterIAMServiceAccount{AttachPolicyARNs: []string{"arn:aws:iam::aws:policy/ReadOnlyAccess"}}
+			sa.Name = "sa-1"
+			sa.Namespace = "ns-1"
+			sa.RoleName = "{ClusterName}-{Namespace}-{Name}"
+			cfg.IAM.ServiceAccounts = []*ClusterIAMServiceAccount{sa}
+
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+			Expect(sa.RenderRoleName(cfg.Metadata.Name)).To(Equal("cluster-1-ns-1-sa-1"))
+
+			sa.RoleName = "{Cluster}"
+			err = ValidateClusterConfig(cfg)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("invalid iam.serviceAccounts[0].roleName"))
+
+			sa.RoleName = "{ClusterName}-{Namespace}-{Name}-rrrrrrrrrrrrrrrrrrrrrrrrrrrrrrrrrrrrrrrrrrrrrrrrrr"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("is longer than 64 characters")))
+		})
+
+		It("should only allow an existing role without the policies of iam.serviceAccounts", func() {
+			cfg.IAM.WithOIDC = Enabled()
+
+			sa := &ClusterIAMServiceAccount{AttachRoleARN: "arn:aws:iam::123456789012:role/existing"}
+			sa.Name = "sa-1"
+			cfg.IAM.ServiceAccounts = []*ClusterIAMServiceAccount{sa}
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+
+			sa.AttachPolicyARNs = []string{"arn:aws:iam::aws:policy/ReadOnlyAccess"}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(HavePrefix("iam.serviceAccounts[0].attachRoleARN cannot be set along with")))
+
+			sa.AttachPolicyARNs = nil
+			sa.AttachRoleARN = "existing"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(HavePrefix(`invalid iam.serviceAccounts[0].attachRoleARN "existing"`)))
+		})
+
+		It("should not allow options of the serviceaccount in roleOnly mode", func() {
+			cfg.IAM.WithOIDC = Enabled()
+
+			sa := &ClusterIAMServiceAccount{AttachPolicyARNs: []string{"arn:aws:iam::aws:policy/ReadOnlyAccess"}, RoleOnly: Enabled()}
+			sa.Name = "sa-1"
+			cfg.IAM.ServiceAccounts = []*ClusterIAMServiceAccount{sa}
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+
+			sa.AutomountServiceAccountToken = Disabled()
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("iam.serviceAccounts[0].automountServiceAccountToken cannot be set when iam.serviceAccounts[0].roleOnly is enabled"))
+
+			sa.AutomountServiceAccountToken = nil
+			sa.AttachPolicyARNs = nil
+			sa.AttachRoleARN = "arn:aws:iam::123456789012:role/existing"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(HavePrefix("iam.serviceAccounts[0].roleOnly cannot be enabled along with")))
+		})
+
 		It("should fail when unnamed iam.serviceAccounts[1] is given", func() {
 			cfg.IAM.WithOIDC = Enabled()
 
@@ -582,6 +637,1101 @@ var _ = Describe("ClusterConfig validation", func() {
 			})
 
 		})
+
+		Context("nodeGroupDefaults.volumeEncryption", func() {
+			keyARN := "arn:aws:kms:us-west-2:123456789012:key/36c0b54e-64ed-4f2d-a1c7-96558764311e"
+
+			var cfg *ClusterConfig
+			BeforeEach(func() {
+				cfg = NewClusterConfig()
+				cfg.NodeGroupDefaults = &NodeGroupDefaults{
+					VolumeEncryption: &VolumeEncryption{
+						Enabled:   &enabled,
+						KMSKeyARN: &keyARN,
+					},
+				}
+			})
+
+			It("Applies the defaults to nodegroups that don't set encryption", func() {
+				ng := cfg.NewNodeGroup()
+				ng.Name = nodegroup
+				ng.VolumeSize = &volSize
+
+				SetClusterConfigDefaults(cfg)
+				Expect(ValidateClusterConfig(cfg)).To(Succeed())
+				Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+				Expect(*ng.VolumeEncrypted).To(BeTrue())
+				Expect(*ng.VolumeKmsKeyID).To(Equal(keyARN))
+			})
+
+			It("Doesn't override the settings of a nodegroup", func() {
+				ng := cfg.NewNodeGroup()
+				ng.Name = nodegroup
+				ng.VolumeSize = &volSize
+				ng.VolumeEncrypted = &enabled
+				ng.VolumeKmsKeyID = &kmsKeyID
+
+				optOut := cfg.NewNodeGroup()
+				optOut.Name = "ng2"
+				optOut.VolumeSize = &volSize
+				optOut.VolumeEncrypted = &disabled
+
+				SetClusterConfigDefaults(cfg)
+				Expect(ValidateClusterConfig(cfg)).To(Succeed())
+				Expect(*ng.VolumeKmsKeyID).To(Equal(kmsKeyID))
+				Expect(*optOut.VolumeEncrypted).To(BeFalse())
+				Expect(optOut.VolumeKmsKeyID).To(BeNil())
+			})
+
+			It("Applies the defaults to nodegroups without volumeSize and to managed nodegroups", func() {
+				ng := cfg.NewNodeGroup()
+				ng.Name = nodegroup
+
+				mng := NewManagedNodeGroup()
+				mng.Name = "mng"
+				cfg.ManagedNodeGroups = []*ManagedNodeGroup{mng}
+
+				SetClusterConfigDefaults(cfg)
+				Expect(ValidateClusterConfig(cfg)).To(Succeed())
+				Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+				Expect(*ng.VolumeEncrypted).To(BeTrue())
+				Expect(*ng.VolumeKmsKeyID).To(Equal(keyARN))
+
+				SetManagedNodeGroupDefaults(mng, cfg.Metadata)
+				Expect(ValidateManagedNodeGroup(mng, 0)).To(Succeed())
+				Expect(*mng.VolumeEncrypted).To(BeTrue())
+				Expect(*mng.VolumeKmsKeyID).To(Equal(keyARN))
+				Expect(mng.RequiresLaunchTemplate()).To(BeTrue())
+			})
+
+			It("Forbids setting kmsKeyARN without enabled", func() {
+				cfg.NodeGroupDefaults.VolumeEncryption.Enabled = nil
+
+				err := ValidateClusterConfig(cfg)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("Forbids an invalid kmsKeyARN", func() {
+				invalidARN := "36c0b54e-64ed-4f2d-a1c7-96558764311e"
+				cfg.NodeGroupDefaults.VolumeEncryption.KMSKeyARN = &invalidARN
+
+				err := ValidateClusterConfig(cfg)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("volume types", func() {
+		var (
+			ng         *NodeGroup
+			volumeSize = 100
+			gp3        = NodeVolumeTypeGP3
+			gp2        = NodeVolumeTypeGP2
+		)
+
+		BeforeEach(func() {
+			ng = &NodeGroup{
+				AMIFamily:  NodeImageFamilyAmazonLinux2,
+				VolumeSize: &volumeSize,
+			}
+		})
+
+		It("allows iops and throughput for gp3 volumes", func() {
+			iops, throughput := 3000, 125
+			ng.VolumeType = &gp3
+			ng.VolumeIOPS = &iops
+			ng.VolumeThroughput = &throughput
+			Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("rejects throughput for other volume types", func() {
+			throughput := 125
+			ng.VolumeType = &gp2
+			ng.VolumeThroughput = &throughput
+			err := ValidateNodeGroup(0, ng)
+			Expect(err).To(MatchError("nodeGroups[0].volumeThroughput is only supported for gp3 volume type"))
+		})
+
+		It("rejects out of range gp3 settings", func() {
+			iops, throughput := 100, 2000
+			ng.VolumeType = &gp3
+			ng.VolumeIOPS = &iops
+			Expect(ValidateNodeGroup(0, ng)).NotTo(Succeed())
+
+			ng.VolumeIOPS = nil
+			ng.VolumeThroughput = &throughput
+			Expect(ValidateNodeGroup(0, ng)).NotTo(Succeed())
+		})
+
+		Context("additionalVolumes", func() {
+			It("accepts valid volumes", func() {
+				throughput := 500
+				ng.AdditionalVolumes = []*VolumeMapping{
+					{Name: "/dev/xvdb", Size: &volumeSize, Type: &gp3, Throughput: &throughput, MountPath: "/mnt/data"},
+					{Name: "/dev/xvdc", Size: &volumeSize},
+				}
+				Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+			})
+
+			It("requires a block device name", func() {
+				ng.AdditionalVolumes = []*VolumeMapping{{Name: "xvdb", Size: &volumeSize}}
+				Expect(ValidateNodeGroup(0, ng)).NotTo(Succeed())
+			})
+
+			It("requires unique names", func() {
+				ng.AdditionalVolumes = []*VolumeMapping{
+					{Name: "/dev/xvdb", Size: &volumeSize},
+					{Name: "/dev/xvdb", Size: &volumeSize},
+				}
+				Expect(ValidateNodeGroup(0, ng)).NotTo(Succeed())
+			})
+
+			It("requires a size", func() {
+				ng.AdditionalVolumes = []*VolumeMapping{{Name: "/dev/xvdb"}}
+				err := ValidateNodeGroup(0, ng)
+				Expect(err).To(MatchError("nodeGroups[0].additionalVolumes[0].size must be set to a positive value"))
+			})
+
+			It("rejects relative mount paths", func() {
+				ng.AdditionalVolumes = []*VolumeMapping{{Name: "/dev/xvdb", Size: &volumeSize, MountPath: "data"}}
+				Expect(ValidateNodeGroup(0, ng)).NotTo(Succeed())
+			})
+
+			It("rejects mount paths for Bottlerocket", func() {
+				ng.AMIFamily = NodeImageFamilyBottlerocket
+				ng.AdditionalVolumes = []*VolumeMapping{{Name: "/dev/xvdb", Size: &volumeSize, MountPath: "/mnt/data"}}
+				Expect(ValidateNodeGroup(0, ng)).NotTo(Succeed())
+			})
+		})
+	})
+
+	Describe("instance store", func() {
+		var ng *NodeGroup
+		BeforeEach(func() {
+			ng = &NodeGroup{
+				AMIFamily: NodeImageFamilyAmazonLinux2,
+			}
+		})
+
+		It("requires mountPath to be set", func() {
+			ng.InstanceStore = &NodeGroupInstanceStore{RAID0: Enabled()}
+			err := ValidateNodeGroup(0, ng)
+			Expect(err).To(MatchError("nodeGroups[0].instanceStore.mountPath must be set"))
+		})
+
+		It("requires mountPath to be absolute", func() {
+			ng.InstanceStore = &NodeGroupInstanceStore{MountPath: "var/lib/docker"}
+			err := ValidateNodeGroup(0, ng)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("allows an absolute mountPath", func() {
+			ng.InstanceStore = &NodeGroupInstanceStore{RAID0: Enabled(), MountPath: "/var/lib/docker"}
+			err := ValidateNodeGroup(0, ng)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("is not supported for Bottlerocket", func() {
+			ng.AMIFamily = NodeImageFamilyBottlerocket
+			ng.InstanceStore = &NodeGroupInstanceStore{MountPath: "/mnt/data"}
+			err := ValidateNodeGroup(0, ng)
+			Expect(err).To(MatchError("instanceStore is not supported for Bottlerocket nodegroups (path=nodeGroups[0].instanceStore)"))
+		})
+
+		It("is not supported for Windows", func() {
+			ng.AMIFamily = NodeImageFamilyWindowsServer2019FullContainer
+			ng.InstanceStore = &NodeGroupInstanceStore{MountPath: "/mnt/data"}
+			err := ValidateNodeGroup(0, ng)
+			Expect(err).To(MatchError("instanceStore is not supported for WindowsServer2019FullContainer nodegroups (path=nodeGroups[0].instanceStore)"))
+		})
+	})
+
+	Describe("gMSA", func() {
+		const secretARN = "arn:aws:secretsmanager:us-west-2:123456789012:secret:gmsa-user-AbCdEf"
+		var ng *NodeGroup
+		BeforeEach(func() {
+			ng = &NodeGroup{
+				AMIFamily: NodeImageFamilyWindowsServer2019FullContainer,
+				GMSA:      &NodeGroupGMSA{DomainlessSecretARN: secretARN},
+			}
+		})
+
+		It("allows domainless gMSA", func() {
+			ng.GMSA.SecretKMSKeyARN = "arn:aws:kms:us-west-2:123456789012:key/12345678-1234-1234-1234-123456789012"
+			Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("allows domain-joined gMSA", func() {
+			ng.GMSA = &NodeGroupGMSA{DirectoryServiceAccess: Enabled()}
+			Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("is only supported for Windows", func() {
+			ng.AMIFamily = NodeImageFamilyAmazonLinux2
+			err := ValidateNodeGroup(0, ng)
+			Expect(err).To(MatchError("nodeGroups[0].gmsa is only supported for Windows nodegroups"))
+		})
+
+		It("requires a secret or Directory Service access", func() {
+			ng.GMSA = &NodeGroupGMSA{}
+			err := ValidateNodeGroup(0, ng)
+			Expect(err).To(MatchError("nodeGroups[0].gmsa requires either domainlessSecretARN or directoryServiceAccess"))
+		})
+
+		It("requires the ARN of a secret", func() {
+			ng.GMSA.DomainlessSecretARN = "arn:aws:ssm:us-west-2:123456789012:parameter/gmsa-user"
+			Expect(ValidateNodeGroup(0, ng)).NotTo(Succeed())
+		})
+
+		It("requires a secret for secretKMSKeyARN", func() {
+			ng.GMSA = &NodeGroupGMSA{
+				DirectoryServiceAccess: Enabled(),
+				SecretKMSKeyARN:        "arn:aws:kms:us-west-2:123456789012:key/12345678-1234-1234-1234-123456789012",
+			}
+			Expect(ValidateNodeGroup(0, ng)).NotTo(Succeed())
+		})
+
+		It("rejects an existing instance role", func() {
+			ng.IAM = &NodeGroupIAM{InstanceRoleARN: "arn:aws:iam::123456789012:role/nodes"}
+			Expect(ValidateNodeGroup(0, ng)).NotTo(Succeed())
+		})
+	})
+
+	Describe("instance selector", func() {
+		var ng *NodeGroup
+		BeforeEach(func() {
+			ng = &NodeGroup{
+				AMIFamily:        NodeImageFamilyAmazonLinux2,
+				InstanceSelector: &InstanceSelector{VCPUs: 4, Memory: "16GiB"},
+			}
+		})
+
+		It("allows a selector without an instance type", func() {
+			Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("allows a selector with an instances distribution", func() {
+			ng.InstancesDistribution = &NodeGroupInstancesDistribution{SpotInstancePools: newInt(2)}
+			Expect(ValidateNodeGroup(0, ng)).To(Succeed())
+		})
+
+		It("rejects a selector with an instance type", func() {
+			ng.InstanceType = "m5.xlarge"
+			err := ValidateNodeGroup(0, ng)
+			Expect(err).To(MatchError("nodeGroups[0].instanceSelector cannot be used with nodeGroups[0].instanceType or nodeGroups[0].instancesDistribution.instanceTypes"))
+		})
+
+		It("rejects an empty selector", func() {
+			ng.InstanceSelector = &InstanceSelector{CPUArchitecture: CPUArchitectureARM64}
+			err := ValidateNodeGroup(0, ng)
+			Expect(err).To(MatchError("nodeGroups[0].instanceSelector must set at least one of vCPUs, memory or gpus"))
+		})
+
+		It("rejects invalid memory and architectures", func() {
+			ng.InstanceSelector.Memory = "16GB"
+			Expect(ValidateNodeGroup(0, ng)).NotTo(Succeed())
+
+			ng.InstanceSelector.Memory = "16"
+			ng.InstanceSelector.CPUArchitecture = "i386"
+			Expect(ValidateNodeGroup(0, ng)).To(MatchError("nodeGroups[0].instanceSelector.cpuArchitecture must be x86_64 or arm64"))
+		})
+
+		It("parses the memory", func() {
+			for memory, mib := range map[string]int64{"16": 16384, "16GiB": 16384, "512MiB": 512, "": 0} {
+				ng.InstanceSelector.Memory = memory
+				Expect(ng.InstanceSelector.MemoryMiB()).To(Equal(mib))
+			}
+		})
+	})
+
+	Describe("nodeTerminationHandler", func() {
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+			cfg.NodeTerminationHandler = &NodeTerminationHandler{Enabled: Enabled()}
+		})
+
+		It("defaults to IMDS mode", func() {
+			SetClusterConfigDefaults(cfg)
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+			Expect(cfg.NodeTerminationHandler.Mode).To(Equal(NodeTerminationHandlerModeIMDS))
+			Expect(cfg.IAM.ServiceAccounts).To(BeEmpty())
+		})
+
+		It("rejects an unknown mode", func() {
+			cfg.NodeTerminationHandler.Mode = "webhook"
+			SetClusterConfigDefaults(cfg)
+			err := ValidateClusterConfig(cfg)
+			Expect(err).To(MatchError(`nodeTerminationHandler.mode must be either "imds" or "queue"`))
+		})
+
+		It("requires iam.withOIDC in queue mode", func() {
+			cfg.NodeTerminationHandler.Mode = NodeTerminationHandlerModeQueue
+			SetClusterConfigDefaults(cfg)
+			err := ValidateClusterConfig(cfg)
+			Expect(err).To(MatchError(`iam.withOIDC must be enabled explicitly for nodeTerminationHandler.mode "queue"`))
+		})
+
+		It("adds the IAM service account in queue mode", func() {
+			cfg.NodeTerminationHandler.Mode = NodeTerminationHandlerModeQueue
+			cfg.IAM.WithOIDC = Enabled()
+			SetClusterConfigDefaults(cfg)
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+
+			Expect(cfg.IAM.ServiceAccounts).To(HaveLen(1))
+			Expect(cfg.IAM.ServiceAccounts[0].NameString()).To(Equal("kube-system/aws-node-termination-handler"))
+			Expect(cfg.IAM.ServiceAccounts[0].AttachPolicy).ToNot(BeEmpty())
+
+			SetClusterConfigDefaults(cfg)
+			Expect(cfg.IAM.ServiceAccounts).To(HaveLen(1))
+		})
+	})
+
+	Describe("metadata.requiredTags", func() {
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+			cfg.Metadata.RequiredTags = []RequiredTag{
+				{Key: "cost-center", Pattern: "^[0-9]{4}$"},
+				{Key: "env", AllowedValues: []string{"dev", "prod"}},
+			}
+			cfg.Metadata.Tags = map[string]string{
+				"cost-center": "1234",
+				"env":         "dev",
+			}
+		})
+
+		It("accepts tags matching the policy", func() {
+			ng := cfg.NewNodeGroup()
+			ng.Name = "ng-1"
+			ng.Tags = map[string]string{"env": "prod"}
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("rejects a missing tag", func() {
+			delete(cfg.Metadata.Tags, "env")
+			err := ValidateClusterConfig(cfg)
+			Expect(err).To(MatchError(`tag "env" is required by metadata.requiredTags but is not set in metadata.tags`))
+		})
+
+		It("rejects a value that doesn't match the pattern", func() {
+			cfg.Metadata.Tags["cost-center"] = "abc"
+			err := ValidateClusterConfig(cfg)
+			Expect(err).To(MatchError(`value "abc" of tag "cost-center" in metadata.tags doesn't match pattern "^[0-9]{4}$"`))
+		})
+
+		It("rejects a nodegroup overriding a tag with a value that isn't allowed", func() {
+			ng := cfg.NewNodeGroup()
+			ng.Name = "ng-1"
+			ng.Tags = map[string]string{"env": "staging"}
+			err := ValidateClusterConfig(cfg)
+			Expect(err).To(MatchError(`value "staging" of tag "env" in nodeGroups[0].tags must be one of dev, prod`))
+		})
+
+		It("checks the tags of Fargate profiles", func() {
+			cfg.FargateProfiles = []*FargateProfile{{Name: "fp-1"}}
+			err := ValidateClusterConfig(cfg)
+			Expect(err).To(MatchError(`tag "cost-center" is required by metadata.requiredTags but is not set in fargateProfiles[0].tags`))
+		})
+
+		It("rejects an invalid pattern", func() {
+			cfg.Metadata.RequiredTags[0].Pattern = "[0-9"
+			err := ValidateClusterConfig(cfg)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid metadata.requiredTags[0].pattern"))
+		})
+	})
+
+	Describe("hooks", func() {
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+			cfg.Hooks = &ClusterHooks{
+				PreCreate:  []Hook{{Command: []string{"./register.sh"}}},
+				PostCreate: []Hook{{Manifest: "rbac.yaml"}, {Command: []string{"./notify.sh", "created"}}},
+			}
+		})
+
+		It("accepts commands and manifests", func() {
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("requires exactly one of command and manifest", func() {
+			cfg.Hooks.PostCreate[0].Command = []string{"./notify.sh"}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("hooks.postCreate[0] must set exactly one of command and manifest"))
+
+			cfg.Hooks.PostCreate[0] = Hook{}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("hooks.postCreate[0] must set exactly one of command and manifest"))
+		})
+
+		It("rejects manifests when the cluster doesn't exist", func() {
+			cfg.Hooks.PostDelete = []Hook{{Manifest: "rbac.yaml"}}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("hooks.postDelete[0].manifest is not supported in postDelete hooks"))
+		})
+	})
+
+	Describe("notifications", func() {
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+			cfg.Notifications = &Notifications{
+				SNSTopicARN:  "arn:aws:sns:us-west-2:123456789012:clusters",
+				EventBusName: "default",
+				WebhookURL:   "https://hooks.slack.com/services/T000/B000/XXXX",
+			}
+		})
+
+		It("accepts all destinations", func() {
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("rejects an invalid topic ARN", func() {
+			cfg.Notifications.SNSTopicARN = "clusters"
+			err := ValidateClusterConfig(cfg)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid ARN in notifications.snsTopicARN"))
+		})
+
+		It("rejects a plain HTTP webhook", func() {
+			cfg.Notifications.WebhookURL = "http://example.com/events"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("notifications.webhookURL must be an HTTPS URL"))
+		})
+	})
+
+	Describe("alarms", func() {
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+			cfg.Alarms = &Alarms{
+				Enabled:            Enabled(),
+				EmailSubscriptions: []string{"oncall@example.com"},
+			}
+		})
+
+		It("accepts email subscriptions to the topic eksctl creates", func() {
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("rejects email subscriptions with an existing topic", func() {
+			cfg.Alarms.SNSTopicARN = "arn:aws:sns:us-west-2:123456789012:alarms"
+			err := ValidateClusterConfig(cfg)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("alarms.emailSubscriptions can only be set when eksctl creates the topic"))
+		})
+
+		It("rejects an invalid topic ARN", func() {
+			cfg.Alarms.EmailSubscriptions = nil
+			cfg.Alarms.SNSTopicARN = "alarms"
+			err := ValidateClusterConfig(cfg)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid ARN in alarms.snsTopicARN"))
+		})
+
+		It("rejects an invalid email address", func() {
+			cfg.Alarms.EmailSubscriptions = []string{"oncall"}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(`alarms.emailSubscriptions[0]: "oncall" is not an email address`))
+		})
+
+		It("rejects a threshold below 1", func() {
+			threshold := 0
+			cfg.Alarms.APIServerErrorThreshold = &threshold
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("alarms.apiServerErrorThreshold must be at least 1"))
+		})
+	})
+
+	Describe("budget", func() {
+		It("accepts a positive budget", func() {
+			cfg := NewClusterConfig()
+			maxMonthlyUSD := 150.0
+			cfg.Budget = &Budget{MaxMonthlyUSD: &maxMonthlyUSD}
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("rejects a budget of 0", func() {
+			cfg := NewClusterConfig()
+			maxMonthlyUSD := 0.0
+			cfg.Budget = &Budget{MaxMonthlyUSD: &maxMonthlyUSD}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("budget.maxMonthlyUSD must be greater than 0"))
+		})
+	})
+
+	Describe("accessConfig", func() {
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+		})
+
+		It("accepts supported authentication modes", func() {
+			for _, mode := range SupportedAuthenticationModes() {
+				cfg.AccessConfig = &AccessConfig{AuthenticationMode: mode}
+				Expect(ValidateClusterConfig(cfg)).To(Succeed())
+			}
+		})
+
+		It("rejects an unknown authentication mode", func() {
+			cfg.AccessConfig = &AccessConfig{AuthenticationMode: "IAM"}
+			err := ValidateClusterConfig(cfg)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`accessConfig.authenticationMode: invalid authentication mode "IAM"`))
+		})
+	})
+
+	Describe("version support", func() {
+		It("accepts the supported versions", func() {
+			for _, version := range SupportedVersions() {
+				Expect(CheckVersionSupport(version)).To(Succeed())
+			}
+		})
+
+		It("rejects versions EKS no longer supports", func() {
+			err := CheckVersionSupport(Version1_10)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("Kubernetes 1.10 is no longer supported by EKS"))
+		})
+
+		It("asks to upgrade eksctl for versions newer than the latest", func() {
+			err := CheckVersionSupport("1.99")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("newer than the versions supported by this build of eksctl"))
+			Expect(err.Error()).To(HaveSuffix("upgrade eksctl"))
+		})
+
+		It("describes the support matrix", func() {
+			support := VersionSupport()
+			Expect(support.Supported).To(ContainElement(support.Default))
+			Expect(support.Supported).To(ContainElement(support.Latest))
+			Expect(support.Deprecated).NotTo(ContainElement(support.Latest))
+		})
+	})
+
+	Describe("clusterSettings", func() {
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+		})
+
+		It("accepts fields of the EKS API eksctl doesn't set", func() {
+			cfg.ClusterSettings = InlineDocument{
+				"controlPlaneScalingConfig": map[string]interface{}{"tier": "tier-xl"},
+			}
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("rejects fields eksctl sets", func() {
+			cfg.ClusterSettings = InlineDocument{"accessConfig": map[string]interface{}{"authenticationMode": "API"}}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("clusterSettings.accessConfig is set by eksctl, use accessConfig instead"))
+		})
+
+		It("rejects CloudFormation property names", func() {
+			cfg.ClusterSettings = InlineDocument{"ControlPlaneScalingConfig": map[string]interface{}{"Tier": "tier-xl"}}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("clusterSettings.ControlPlaneScalingConfig must be a field of the EKS CreateCluster API, e.g. controlPlaneScalingConfig"))
+		})
+	})
+
+	Describe("containerRegistryOverride", func() {
+		It("requires the host of a registry", func() {
+			cfg := NewClusterConfig()
+			cfg.ContainerRegistryOverride = "123456789012.dkr.ecr.us-west-2.amazonaws.com/mirror"
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+
+			cfg.ContainerRegistryOverride = "https://registry.example.com"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("must be a registry without a scheme")))
+
+			cfg.ContainerRegistryOverride = "mirror"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("must start with the host of a registry")))
+		})
+
+		It("rewrites the registry of images", func() {
+			registry := "registry.example.com:5000/mirror"
+			Expect(OverrideImageRegistry("602401143452.dkr.ecr.us-west-2.amazonaws.com/eks/coredns:v1.6.6", registry)).To(Equal("registry.example.com:5000/mirror/eks/coredns:v1.6.6"))
+			Expect(OverrideImageRegistry("amazon/aws-node-termination-handler:v1.6.1", registry)).To(Equal("registry.example.com:5000/mirror/amazon/aws-node-termination-handler:v1.6.1"))
+			Expect(OverrideImageRegistry("registry.example.com:5000/mirror/eks/coredns:v1.6.6", registry)).To(Equal("registry.example.com:5000/mirror/eks/coredns:v1.6.6"))
+			Expect(OverrideImageRegistry("amazon/aws-node-termination-handler:v1.6.1", "")).To(Equal("amazon/aws-node-termination-handler:v1.6.1"))
+		})
+	})
+
+	Describe("nodeGroups[].account", func() {
+		var (
+			cfg *ClusterConfig
+			ng  *NodeGroup
+		)
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+			ng = cfg.NewNodeGroup()
+			ng.Name = "workload"
+			// as decoded from a config file
+			ng.SecurityGroups = nil
+			ng.Account = &NodeGroupAccount{RoleARN: "arn:aws:iam::444455556666:role/eksctl-nodegroups"}
+		})
+
+		It("requires a VPC shared with the account of the nodegroup", func() {
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("nodeGroups[0].account requires vpc.id")))
+
+			cfg.VPC.ID = "vpc-shared"
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("requires the ARN of a role", func() {
+			cfg.VPC.ID = "vpc-shared"
+			ng.Account.RoleARN = "eksctl-nodegroups"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("invalid ARN in nodeGroups[0].account.roleARN")))
+		})
+
+		It("rejects the security groups of the account of the cluster", func() {
+			cfg.VPC.ID = "vpc-shared"
+			ng.SecurityGroups = &NodeGroupSGs{WithShared: Enabled()}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("nodeGroups[0].securityGroups.withShared can't be enabled")))
+
+			ng.SecurityGroups = &NodeGroupSGs{WithLocal: Disabled()}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("nodeGroups[0].securityGroups.withLocal can't be disabled")))
+		})
+
+		It("doesn't attach the shared security group by default", func() {
+			SetNodeGroupDefaults(ng, cfg.Metadata)
+			Expect(*ng.SecurityGroups.WithShared).To(BeFalse())
+			Expect(*ng.SecurityGroups.WithLocal).To(BeTrue())
+		})
+	})
+
+	Describe("vpc.controlPlaneSubnetIDs", func() {
+		It("requires at least two unique subnets", func() {
+			cfg := NewClusterConfig()
+			cfg.VPC.ControlPlaneSubnetIDs = []string{"subnet-1"}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("vpc.controlPlaneSubnetIDs must have at least 2 subnets"))
+
+			cfg.VPC.ControlPlaneSubnetIDs = []string{"subnet-1", "subnet-1"}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(`vpc.controlPlaneSubnetIDs[1] "subnet-1" is not unique`))
+
+			cfg.VPC.ControlPlaneSubnetIDs = []string{"subnet-1", "subnet-2"}
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+	})
+
+	Describe("vpc.securityGroupIDs", func() {
+		It("requires at most five unique security group IDs", func() {
+			cfg := NewClusterConfig()
+			cfg.VPC.SecurityGroupIDs = []string{"sg-1", "sg-2", "sg-3", "sg-4", "sg-5", "sg-6"}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("vpc.securityGroupIDs must have at most 5 security groups"))
+
+			cfg.VPC.SecurityGroupIDs = []string{"sg-1", "subnet-1"}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(`vpc.securityGroupIDs[1]: "subnet-1" is not a security group ID`))
+
+			cfg.VPC.SecurityGroupIDs = []string{"sg-1", "sg-1"}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(`vpc.securityGroupIDs[1] "sg-1" is not unique`))
+
+			cfg.VPC.SecurityGroupIDs = []string{"sg-1", "sg-2"}
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("only allows vpc.securityGroup to be the first security group ID", func() {
+			cfg := NewClusterConfig()
+			cfg.VPC.SecurityGroupIDs = []string{"sg-1", "sg-2"}
+			cfg.VPC.SecurityGroup = "sg-2"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("vpc.securityGroup and vpc.securityGroupIDs cannot be set at the same time"))
+
+			cfg.VPC.SecurityGroup = "sg-1"
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+	})
+
+	Describe("vpc.flowLogs", func() {
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+			cfg.VPC.FlowLogs = &VPCFlowLogs{Destination: VPCFlowLogsDestinationCloudWatch}
+		})
+
+		It("accepts the supported destinations, traffic types and aggregation intervals", func() {
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+
+			aggregationInterval := 60
+			cfg.VPC.FlowLogs = &VPCFlowLogs{
+				Destination:         VPCFlowLogsDestinationS3,
+				TrafficType:         VPCFlowLogsTrafficTypeReject,
+				AggregationInterval: &aggregationInterval,
+			}
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("rejects unsupported values", func() {
+			cfg.VPC.FlowLogs.Destination = "kinesis"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(`vpc.flowLogs.destination must be either "cloudwatch" or "s3"`))
+
+			cfg.VPC.FlowLogs.Destination = VPCFlowLogsDestinationS3
+			cfg.VPC.FlowLogs.TrafficType = "DROP"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(`vpc.flowLogs.trafficType must be one of "ACCEPT", "REJECT" or "ALL"`))
+
+			cfg.VPC.FlowLogs.TrafficType = ""
+			aggregationInterval := 300
+			cfg.VPC.FlowLogs.AggregationInterval = &aggregationInterval
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("vpc.flowLogs.aggregationInterval must be either 60 or 600 seconds"))
+		})
+
+		It("requires eksctl to create the VPC", func() {
+			cfg.VPC.ID = "vpc-1"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("vpc.flowLogs is only supported when eksctl creates the VPC"))
+		})
+
+		It("defaults to all traffic aggregated over 600 seconds", func() {
+			SetClusterConfigDefaults(cfg)
+			Expect(cfg.VPC.FlowLogs.TrafficType).To(Equal(VPCFlowLogsTrafficTypeAll))
+			Expect(*cfg.VPC.FlowLogs.AggregationInterval).To(Equal(600))
+		})
+	})
+
+	Describe("vpc.podSecurityGroups", func() {
+		It("requires security groups for pods to be enabled to create the sample policy", func() {
+			cfg := NewClusterConfig()
+			cfg.VPC.PodSecurityGroups = &PodSecurityGroups{CreateSamplePolicy: Enabled()}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("vpc.podSecurityGroups.createSamplePolicy requires vpc.podSecurityGroups.enabled"))
+
+			cfg.VPC.PodSecurityGroups.Enabled = Enabled()
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+	})
+
+	Describe("vpc.privateHostedZone", func() {
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+			cfg.VPC.ID = "vpc-cluster"
+			cfg.VPC.ClusterEndpoints = &ClusterEndpoints{PrivateAccess: Enabled(), PublicAccess: Disabled()}
+			cfg.VPC.PrivateHostedZone = &PrivateHostedZone{AssociatedVPCIDs: []string{"vpc-peered-1", "vpc-peered-2"}}
+		})
+
+		It("accepts peered VPCs of a cluster with private endpoint access", func() {
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("requires private endpoint access", func() {
+			cfg.VPC.ClusterEndpoints.PrivateAccess = Disabled()
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("vpc.privateHostedZone requires vpc.clusterEndpoints.privateAccess"))
+		})
+
+		It("requires unique VPCs other than the cluster VPC", func() {
+			cfg.VPC.PrivateHostedZone.AssociatedVPCIDs = nil
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("must have at least one VPC")))
+
+			cfg.VPC.PrivateHostedZone.AssociatedVPCIDs = []string{"vpc-peered-1", "vpc-peered-1"}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("is not unique")))
+
+			cfg.VPC.PrivateHostedZone.AssociatedVPCIDs = []string{"vpc-cluster"}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("is the VPC of the cluster")))
+		})
+	})
+
+	Describe("vpc.transitGatewayID and vpc.peering", func() {
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+			cfg.VPC.TransitGatewayID = "tgw-1"
+			cfg.VPC.TransitGatewayCIDRs = []string{"10.0.0.0/8"}
+			cfg.VPC.Peering = []VPCPeering{{
+				PeerVPCID:        "vpc-shared",
+				PeerOwnerID:      "123456789012",
+				PeerRoleARN:      "arn:aws:iam::123456789012:role/peering",
+				DestinationCIDRs: []string{"172.16.0.0/16"},
+			}}
+		})
+
+		It("accepts attachments of a VPC created by eksctl", func() {
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("rejects attachments of an existing VPC", func() {
+			cfg.VPC.ID = "vpc-existing"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("only supported when eksctl creates the VPC")))
+		})
+
+		It("rejects destination CIDRs overlapping with the VPC or routed twice", func() {
+			cfg.VPC.TransitGatewayCIDRs = []string{"192.168.0.0/24"}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("must not overlap with vpc.cidr")))
+
+			cfg.VPC.TransitGatewayCIDRs = []string{"172.16.0.0/16"}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("vpc.peering[0].destinationCIDRs[0] (172.16.0.0/16) is routed more than once"))
+		})
+
+		It("requires a transit gateway for its routes and tags", func() {
+			cfg.VPC.TransitGatewayID = ""
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("require vpc.transitGatewayID")))
+		})
+
+		It("requires a role to peer with another account", func() {
+			cfg.VPC.Peering[0].PeerRoleARN = ""
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("vpc.peering[0].peerRoleARN must be set to accept a peering connection with another account"))
+		})
+	})
+
+	Describe("kubernetesNetworkConfig", func() {
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+			cfg.KubernetesNetworkConfig = &KubernetesNetworkConfig{}
+		})
+
+		It("accepts a private service CIDR and derives the cluster DNS IP", func() {
+			cfg.KubernetesNetworkConfig.ServiceIPv4CIDR = "172.16.0.0/16"
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+			Expect(cfg.ClusterDNSIP()).To(Equal("172.16.0.10"))
+		})
+
+		It("rejects invalid service CIDRs", func() {
+			for cidr, msg := range map[string]string{
+				"172.16.0.0":     "must be an IPv4 CIDR",
+				"fd00::/108":     "must be an IPv4 CIDR",
+				"172.16.0.1/16":  "must be a network address",
+				"10.0.0.0/8":     "prefix length between /12 and /24",
+				"172.16.0.0/25":  "prefix length between /12 and /24",
+				"52.94.0.0/16":   "must be within one of",
+				"192.168.0.0/20": "must not overlap with vpc.cidr",
+			} {
+				cfg.KubernetesNetworkConfig.ServiceIPv4CIDR = cidr
+				err := ValidateClusterConfig(cfg)
+				Expect(err).To(HaveOccurred(), cidr)
+				Expect(err.Error()).To(ContainSubstring(msg), cidr)
+			}
+		})
+
+		Context("ipFamily", func() {
+			BeforeEach(func() {
+				cfg.Metadata.Version = MinIPv6Version
+				cfg.KubernetesNetworkConfig.IPFamily = IPV6Family
+				cfg.IAM.WithOIDC = Enabled()
+				cfg.VPC.AutoAllocateIPv6 = Enabled()
+				cfg.ManagedNodeGroups = []*ManagedNodeGroup{{Name: "mng-1"}}
+			})
+
+			It("accepts an IPv6 cluster with managed nodegroups", func() {
+				Expect(ValidateClusterConfig(cfg)).To(Succeed())
+				Expect(cfg.IPv6Enabled()).To(BeTrue())
+			})
+
+			It("rejects unknown IP families", func() {
+				cfg.KubernetesNetworkConfig.IPFamily = "ipv6"
+				Expect(ValidateClusterConfig(cfg)).To(MatchError(`invalid value "ipv6" for kubernetesNetworkConfig.ipFamily, must be one of IPv4, IPv6`))
+			})
+
+			It("rejects Kubernetes versions that don't support IPv6", func() {
+				cfg.Metadata.Version = Version1_15
+				Expect(ValidateClusterConfig(cfg)).To(MatchError("kubernetesNetworkConfig.ipFamily IPv6 requires Kubernetes 1.21 or later"))
+			})
+
+			It("rejects a service IPv4 CIDR", func() {
+				cfg.KubernetesNetworkConfig.ServiceIPv4CIDR = "172.16.0.0/16"
+				Expect(ValidateClusterConfig(cfg)).To(MatchError("kubernetesNetworkConfig.serviceIPv4CIDR cannot be set when kubernetesNetworkConfig.ipFamily is IPv6"))
+			})
+
+			It("requires OIDC", func() {
+				cfg.IAM.WithOIDC = nil
+				Expect(ValidateClusterConfig(cfg)).To(MatchError("iam.withOIDC must be enabled when kubernetesNetworkConfig.ipFamily is IPv6"))
+			})
+
+			It("requires IPv6 subnets when eksctl creates the VPC", func() {
+				cfg.VPC.AutoAllocateIPv6 = Disabled()
+				Expect(ValidateClusterConfig(cfg)).To(MatchError("vpc.autoAllocateIPv6 must be enabled when kubernetesNetworkConfig.ipFamily is IPv6"))
+
+				cfg.VPC.ID = "vpc-1"
+				Expect(ValidateClusterConfig(cfg)).To(Succeed())
+			})
+
+			It("rejects unmanaged nodegroups and custom kubelet settings", func() {
+				cfg.NodeGroups = []*NodeGroup{{Name: "ng-1"}}
+				Expect(ValidateClusterConfig(cfg)).To(MatchError("nodeGroups are not supported when kubernetesNetworkConfig.ipFamily is IPv6, use managedNodeGroups instead"))
+
+				cfg.NodeGroups = nil
+				cfg.ManagedNodeGroups[0].MaxPodsPerNode = 110
+				Expect(ValidateClusterConfig(cfg)).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("cloudFormation", func() {
+		It("rejects a nodegroup batch size below 1", func() {
+			cfg := NewClusterConfig()
+			batchSize := 0
+			cfg.CloudFormation = &ClusterCloudFormation{NodeGroupBatchSize: &batchSize}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("cloudFormation.nodeGroupBatchSize must be at least 1"))
+
+			batchSize = 10
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+	})
+
+	Describe("addons", func() {
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+		})
+
+		It("accepts a storage class for the EBS CSI driver", func() {
+			cfg.Addons = []*Addon{
+				{Name: "vpc-cni", ResolveConflicts: ResolveConflictsOverwrite},
+				{Name: EBSCSIDriverAddon, StorageClass: &AddonStorageClass{
+					KMSKeyARN: "arn:aws:kms:us-west-2:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+				}},
+			}
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("rejects duplicate names", func() {
+			cfg.Addons = []*Addon{{Name: "vpc-cni"}, {Name: "vpc-cni"}}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(`addons[1].name "vpc-cni" is not unique`))
+		})
+
+		It("rejects an unsupported resolveConflicts", func() {
+			cfg.Addons = []*Addon{{Name: "vpc-cni", ResolveConflicts: "replace"}}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("addons[0].resolveConflicts must be one of none, overwrite, preserve"))
+		})
+
+		It("rejects a storage class for other add-ons", func() {
+			cfg.Addons = []*Addon{{Name: "vpc-cni", StorageClass: &AddonStorageClass{}}}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(`addons[0].storageClass is only supported for "aws-ebs-csi-driver"`))
+		})
+
+		It("rejects a snapshot controller for other add-ons", func() {
+			cfg.Addons = []*Addon{{Name: "vpc-cni", SnapshotController: Enabled()}}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(`addons[0].snapshotController is only supported for "aws-ebs-csi-driver"`))
+		})
+
+		It("rejects an invalid KMS key ARN", func() {
+			cfg.Addons = []*Addon{{Name: EBSCSIDriverAddon, StorageClass: &AddonStorageClass{KMSKeyARN: "1234abcd"}}}
+			Expect(ValidateClusterConfig(cfg)).To(HaveOccurred())
+		})
+
+		It("accepts policies and a role name for the roles eksctl creates", func() {
+			cfg.Addons = []*Addon{{
+				Name:             EBSCSIDriverAddon,
+				AttachPolicyARNs: []string{"arn:aws:iam::123456789012:policy/ebs-kms"},
+				AttachPolicy:     InlineDocument{"Version": "2012-10-17"},
+				RoleName:         "{ClusterName}-ebs-csi",
+			}}
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("rejects policies along with serviceAccountRoleARN", func() {
+			cfg.Addons = []*Addon{{
+				Name:                  VPCCNIAddon,
+				ServiceAccountRoleARN: "arn:aws:iam::123456789012:role/cni",
+				RoleName:              "cni",
+			}}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("addons[0].serviceAccountRoleARN cannot be set along with addons[0].attachPolicyARNs, addons[0].attachPolicy or addons[0].roleName"))
+		})
+
+		It("rejects policies for add-ons whose service account isn't known", func() {
+			cfg.Addons = []*Addon{{Name: "coredns", AttachPolicyARNs: []string{"arn:aws:iam::123456789012:policy/dns"}}}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("addons[0].attachPolicyARNs, addons[0].attachPolicy and addons[0].roleName are only supported for aws-ebs-csi-driver, aws-efs-csi-driver, vpc-cni"))
+		})
+
+		It("rejects an invalid policy ARN", func() {
+			cfg.Addons = []*Addon{{Name: VPCCNIAddon, AttachPolicyARNs: []string{"cni-policy"}}}
+			Expect(ValidateClusterConfig(cfg)).To(HaveOccurred())
+		})
+
+		It("creates the roles of known add-ons without serviceAccountRoleARN", func() {
+			Expect((&Addon{Name: VPCCNIAddon}).CreatesRole(true)).To(BeTrue())
+			Expect((&Addon{Name: VPCCNIAddon}).CreatesRole(false)).To(BeFalse())
+			Expect((&Addon{Name: VPCCNIAddon, RoleName: "cni"}).CreatesRole(false)).To(BeTrue())
+			Expect((&Addon{Name: VPCCNIAddon, ServiceAccountRoleARN: "arn:aws:iam::123456789012:role/cni"}).CreatesRole(true)).To(BeFalse())
+			Expect((&Addon{Name: "coredns"}).CreatesRole(true)).To(BeFalse())
+
+			sa := (&Addon{Name: EBSCSIDriverAddon, AttachPolicyARNs: []string{"arn:aws-cn:iam::123456789012:policy/ebs-kms"}}).IAMServiceAccount("aws-cn")
+			Expect(sa.NameString()).To(Equal("kube-system/ebs-csi-controller-sa"))
+			Expect(sa.AttachPolicyARNs).To(Equal([]string{
+				"arn:aws-cn:iam::aws:policy/service-role/AmazonEBSCSIDriverPolicy",
+				"arn:aws-cn:iam::123456789012:policy/ebs-kms",
+			}))
+		})
+	})
+
+	Describe("fargateProfiles[].logging", func() {
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+		})
+
+		profile := func(name string, logging *FargateLogging) *FargateProfile {
+			return &FargateProfile{Name: name, Selectors: []FargateProfileSelector{{Namespace: name}}, Logging: logging}
+		}
+
+		It("accepts the same logging on every profile", func() {
+			cfg.FargateProfiles = []*FargateProfile{
+				profile("default", &FargateLogging{Destination: FargateLoggingFirehose, DeliveryStream: "logs"}),
+				profile("jobs", nil),
+				profile("web", &FargateLogging{Destination: FargateLoggingFirehose, DeliveryStream: "logs"}),
+			}
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("rejects different logging between profiles", func() {
+			cfg.FargateProfiles = []*FargateProfile{
+				profile("default", &FargateLogging{Destination: FargateLoggingCloudWatch}),
+				profile("web", &FargateLogging{Destination: FargateLoggingFirehose, DeliveryStream: "logs"}),
+			}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("fargateProfiles[1].logging differs from the logging of the other Fargate profiles")))
+		})
+
+		It("rejects an incomplete destination", func() {
+			cfg.FargateProfiles = []*FargateProfile{profile("default", &FargateLogging{Destination: "s3"})}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("fargateProfiles[0].logging.destination must be one of cloudwatch, firehose, opensearch"))
+
+			cfg.FargateProfiles = []*FargateProfile{profile("default", &FargateLogging{Destination: FargateLoggingFirehose})}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError("fargateProfiles[0].logging.deliveryStream must be set for the firehose destination"))
+
+			cfg.FargateProfiles = []*FargateProfile{profile("default", &FargateLogging{
+				Destination: FargateLoggingOpenSearch,
+				DomainARN:   "arn:aws:es:us-west-2:123456789012:domain/logs",
+				Endpoint:    "search-logs.us-west-2.es.amazonaws.com",
+			})}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(`fargateProfiles[0].logging.endpoint must be an https URL, got "search-logs.us-west-2.es.amazonaws.com"`))
+		})
+	})
+
+	Describe("Fargate pod execution roles", func() {
+		var cfg *ClusterConfig
+
+		BeforeEach(func() {
+			cfg = NewClusterConfig()
+		})
+
+		It("accepts a customised role and per-profile roles", func() {
+			cfg.IAM.FargatePodExecutionRoleName = "fargate-pods"
+			cfg.IAM.FargatePodExecutionRolePolicyARNs = []string{"arn:aws:iam::123456789012:policy/fargate-secrets"}
+			cfg.FargateProfiles = []*FargateProfile{{
+				Name:                "jobs",
+				Selectors:           []FargateProfileSelector{{Namespace: "jobs"}},
+				PodExecutionRoleARN: "arn:aws:iam::123456789012:role/jobs-pods",
+			}}
+			Expect(ValidateClusterConfig(cfg)).To(Succeed())
+		})
+
+		It("rejects customising an existing role", func() {
+			cfg.IAM.FargatePodExecutionRoleARN = strings.Pointer("arn:aws:iam::123456789012:role/fargate-pods")
+			cfg.IAM.FargatePodExecutionRoleName = "fargate-pods"
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring("iam.fargatePodExecutionRoleARN cannot be set along with iam.fargatePodExecutionRoleName")))
+		})
+
+		It("rejects an invalid role ARN of a profile", func() {
+			cfg.FargateProfiles = []*FargateProfile{{
+				Name:                "jobs",
+				Selectors:           []FargateProfileSelector{{Namespace: "jobs"}},
+				PodExecutionRoleARN: "jobs-pods",
+			}}
+			Expect(ValidateClusterConfig(cfg)).To(MatchError(ContainSubstring(`invalid fargateProfiles[0].podExecutionRoleARN "jobs-pods"`)))
+		})
 	})
 
 	Describe("FargateProfile", func() {
@@ -0,0 +1,62 @@
+package v1alpha5
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver"
+)
+
+// KubernetesVersionSupport is the matrix of Kubernetes versions this build of eksctl supports
+type KubernetesVersionSupport struct {
+	Default    string   `json:"default"`
+	Latest     string   `json:"latest"`
+	Supported  []string `json:"supported"`
+	Deprecated []string `json:"deprecated"`
+}
+
+// VersionSupport returns the matrix of Kubernetes versions this build of eksctl supports
+func VersionSupport() KubernetesVersionSupport {
+	return KubernetesVersionSupport{
+		Default:    DefaultVersion,
+		Latest:     LatestVersion,
+		Supported:  SupportedVersions(),
+		Deprecated: DeprecatedVersions(),
+	}
+}
+
+// IsSupportedVersion determines if version is one of SupportedVersions
+func IsSupportedVersion(version string) bool {
+	return containsVersion(SupportedVersions(), version)
+}
+
+// IsDeprecatedVersion determines if version is one of DeprecatedVersions
+func IsDeprecatedVersion(version string) bool {
+	return containsVersion(DeprecatedVersions(), version)
+}
+
+// CheckVersionSupport checks that this build of eksctl supports Kubernetes version, the error
+// explains whether EKS dropped it or eksctl has to be upgraded
+func CheckVersionSupport(version string) error {
+	if IsSupportedVersion(version) {
+		return nil
+	}
+	supported := strings.Join(SupportedVersions(), ", ")
+	if IsDeprecatedVersion(version) {
+		return fmt.Errorf("Kubernetes %s is no longer supported by EKS, supported versions: %s", version, supported)
+	}
+	v, err := semver.ParseTolerant(version)
+	if err == nil && v.GT(semver.MustParse(LatestVersion+".0")) {
+		return fmt.Errorf("Kubernetes %s is newer than the versions supported by this build of eksctl (%s), upgrade eksctl", version, supported)
+	}
+	return fmt.Errorf("Kubernetes %s isn't supported, supported versions: %s", version, supported)
+}
+
+func containsVersion(versions []string, version string) bool {
+	for _, v := range versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
@@ -15,10 +15,19 @@ type (
 		Network `json:",inline"` // global CIDR and VPC ID
 		// +optional
 		SecurityGroup string `json:"securityGroup,omitempty"` // cluster SG
+		// existing security groups of an existing VPC that EKS attaches to the network
+		// interfaces of the control plane, instead of the one eksctl creates; nodegroups
+		// with a local security group add their rules to the first one
+		// +optional
+		SecurityGroupIDs []string `json:"securityGroupIDs,omitempty"`
 		// subnets are either public or private for use with separate nodegroups
 		// these are keyed by AZ for convenience
 		// +optional
 		Subnets *ClusterSubnets `json:"subnets,omitempty"`
+		// subnets of an existing VPC where EKS places the network interfaces of the control
+		// plane, instead of the subnets of nodegroups
+		// +optional
+		ControlPlaneSubnetIDs []string `json:"controlPlaneSubnetIDs,omitempty"`
 		// for additional CIDR associations, e.g. to use with separate CIDR for
 		// private subnets or any ad-hoc subnets
 		// +optional
@@ -33,6 +42,26 @@ type (
 		ClusterEndpoints *ClusterEndpoints `json:"clusterEndpoints,omitempty"`
 		// +optional
 		PublicAccessCIDRs []string `json:"publicAccessCIDRs,omitempty"`
+		// +optional
+		PodSecurityGroups *PodSecurityGroups `json:"podSecurityGroups,omitempty"`
+		// +optional
+		PrivateHostedZone *PrivateHostedZone `json:"privateHostedZone,omitempty"`
+		// existing transit gateway the VPC created by eksctl is attached to, through
+		// its private subnets
+		// +optional
+		TransitGatewayID string `json:"transitGatewayID,omitempty"`
+		// destination CIDRs routed through TransitGatewayID from all subnets
+		// +optional
+		TransitGatewayCIDRs []string `json:"transitGatewayCIDRs,omitempty"`
+		// tags of the transit gateway attachment
+		// +optional
+		TransitGatewayTags map[string]string `json:"transitGatewayTags,omitempty"`
+		// VPCs the VPC created by eksctl is peered with
+		// +optional
+		Peering []VPCPeering `json:"peering,omitempty"`
+		// flow logs of the VPC created by eksctl
+		// +optional
+		FlowLogs *VPCFlowLogs `json:"flowLogs,omitempty"`
 	}
 	// ClusterSubnets holds private and public subnets
 	ClusterSubnets struct {
@@ -53,6 +82,67 @@ type (
 		Gateway *string `json:"gateway,omitempty"`
 	}
 
+	// PodSecurityGroups configures security groups for pods, which assigns pods selected by a
+	// SecurityGroupPolicy a branch network interface with its own security groups
+	PodSecurityGroups struct {
+		// Enabled enables pod ENIs in the VPC CNI plugin, all nodegroups must use
+		// instance types that support ENI trunking
+		// +optional
+		Enabled *bool `json:"enabled,omitempty"`
+		// CreateSamplePolicy creates a SecurityGroupPolicy that assigns the shared node
+		// security group to the pods of the default namespace labelled with
+		// security-groups-for-pods=sample
+		// +optional
+		CreateSamplePolicy *bool `json:"createSamplePolicy,omitempty"`
+	}
+
+	// PrivateHostedZone configures a Route 53 private hosted zone that resolves the API server
+	// endpoint to its private IPs in other VPCs, e.g. peered ones, which the hosted zone EKS
+	// manages for the cluster VPC isn't associated with
+	PrivateHostedZone struct {
+		// AssociatedVPCIDs are the VPCs, in the region of the cluster, the hosted zone
+		// is associated with
+		AssociatedVPCIDs []string `json:"associatedVPCIDs,omitempty"`
+	}
+
+	// VPCPeering configures a peering connection from the VPC created by eksctl to an
+	// existing VPC
+	VPCPeering struct {
+		// PeerVPCID is the ID of the VPC to peer with
+		PeerVPCID string `json:"peerVPCID"`
+		// PeerOwnerID is the account of PeerVPCID, when it's another account
+		// +optional
+		PeerOwnerID string `json:"peerOwnerID,omitempty"`
+		// PeerRegion is the region of PeerVPCID, when it's another region
+		// +optional
+		PeerRegion string `json:"peerRegion,omitempty"`
+		// PeerRoleARN is a role of the account of PeerVPCID that can accept the
+		// peering connection, required when the account is another one
+		// +optional
+		PeerRoleARN string `json:"peerRoleARN,omitempty"`
+		// DestinationCIDRs are routed through the peering connection from all subnets
+		// +optional
+		DestinationCIDRs []string `json:"destinationCIDRs,omitempty"`
+		// Tags are the tags of the peering connection
+		// +optional
+		Tags map[string]string `json:"tags,omitempty"`
+	}
+
+	// VPCFlowLogs configures the flow logs of the VPC created by eksctl, which are published
+	// to a log group or a bucket eksctl creates
+	VPCFlowLogs struct {
+		// Valid variants are `VPCFlowLogsDestination` constants
+		Destination string `json:"destination"`
+		// TrafficType is the traffic that is logged, one of ACCEPT, REJECT or ALL,
+		// defaults to ALL
+		// +optional
+		TrafficType string `json:"trafficType,omitempty"`
+		// AggregationInterval is the maximum interval in seconds during which a flow is
+		// captured into a record, either 60 or 600, defaults to 600
+		// +optional
+		AggregationInterval *int `json:"aggregationInterval,omitempty"`
+	}
+
 	// ClusterEndpoints holds cluster api server endpoint access information
 	ClusterEndpoints struct {
 		PrivateAccess *bool `json:"privateAccess,omitempty"`
@@ -71,6 +161,25 @@ const (
 	SubnetTopologyPublic SubnetTopology = "Public"
 )
 
+// Values for `VPCFlowLogsDestination`
+const (
+	// VPCFlowLogsDestinationCloudWatch publishes the flow logs to a CloudWatch Logs log group
+	VPCFlowLogsDestinationCloudWatch = "cloudwatch"
+	// VPCFlowLogsDestinationS3 publishes the flow logs to an S3 bucket
+	VPCFlowLogsDestinationS3 = "s3"
+)
+
+// Values for `VPCFlowLogs.TrafficType`
+const (
+	VPCFlowLogsTrafficTypeAccept = "ACCEPT"
+	VPCFlowLogsTrafficTypeReject = "REJECT"
+	VPCFlowLogsTrafficTypeAll    = "ALL"
+)
+
+// DefaultVPCFlowLogsAggregationInterval is the default maximum aggregation interval of flow
+// logs, in seconds
+const DefaultVPCFlowLogsAggregationInterval = 600
+
 // SubnetTopologies returns a list of topologies
 func SubnetTopologies() []SubnetTopology {
 	return []SubnetTopology{
@@ -247,3 +356,14 @@ func (c *ClusterConfig) HasPrivateEndpointAccess() bool {
 		c.VPC.ClusterEndpoints.PrivateAccess != nil &&
 		*c.VPC.ClusterEndpoints.PrivateAccess
 }
+
+// HasPrivateHostedZone reports whether a private hosted zone resolves the API server endpoint
+// in other VPCs
+func (c *ClusterConfig) HasPrivateHostedZone() bool {
+	return c.VPC != nil && c.VPC.PrivateHostedZone != nil
+}
+
+// HasPodSecurityGroups reports whether security groups for pods are enabled
+func (c *ClusterConfig) HasPodSecurityGroups() bool {
+	return c.VPC != nil && c.VPC.PodSecurityGroups != nil && IsEnabled(c.VPC.PodSecurityGroups.Enabled)
+}
@@ -25,6 +25,137 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessConfig) DeepCopyInto(out *AccessConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessConfig.
+func (in *AccessConfig) DeepCopy() *AccessConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Addon) DeepCopyInto(out *Addon) {
+	*out = *in
+	if in.AttachPolicyARNs != nil {
+		in, out := &in.AttachPolicyARNs, &out.AttachPolicyARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.AttachPolicy.DeepCopyInto(&out.AttachPolicy)
+	if in.StorageClass != nil {
+		in, out := &in.StorageClass, &out.StorageClass
+		*out = new(AddonStorageClass)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SnapshotController != nil {
+		in, out := &in.SnapshotController, &out.SnapshotController
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Addon.
+func (in *Addon) DeepCopy() *Addon {
+	if in == nil {
+		return nil
+	}
+	out := new(Addon)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonStorageClass) DeepCopyInto(out *AddonStorageClass) {
+	*out = *in
+	if in.SetDefault != nil {
+		in, out := &in.SetDefault, &out.SetDefault
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonStorageClass.
+func (in *AddonStorageClass) DeepCopy() *AddonStorageClass {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonStorageClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Billing) DeepCopyInto(out *Billing) {
+	*out = *in
+	if in.ActivateCostAllocationTags != nil {
+		in, out := &in.ActivateCostAllocationTags, &out.ActivateCostAllocationTags
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Billing.
+func (in *Billing) DeepCopy() *Billing {
+	if in == nil {
+		return nil
+	}
+	out := new(Billing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCloudFormation) DeepCopyInto(out *ClusterCloudFormation) {
+	*out = *in
+	if in.DisableRollback != nil {
+		in, out := &in.DisableRollback, &out.DisableRollback
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TerminationProtection != nil {
+		in, out := &in.TerminationProtection, &out.TerminationProtection
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ProtectClusterResources != nil {
+		in, out := &in.ProtectClusterResources, &out.ProtectClusterResources
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NodeGroupBatchSize != nil {
+		in, out := &in.NodeGroupBatchSize, &out.NodeGroupBatchSize
+		*out = new(int)
+		**out = **in
+	}
+	if in.IAMServiceAccountConcurrency != nil {
+		in, out := &in.IAMServiceAccountConcurrency, &out.IAMServiceAccountConcurrency
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCloudFormation.
+func (in *ClusterCloudFormation) DeepCopy() *ClusterCloudFormation {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCloudFormation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterCloudWatch) DeepCopyInto(out *ClusterCloudWatch) {
 	*out = *in
@@ -134,6 +265,70 @@ func (in *ClusterConfig) DeepCopyInto(out *ClusterConfig) {
 		*out = new(SecretsEncryption)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NodeGroupDefaults != nil {
+		in, out := &in.NodeGroupDefaults, &out.NodeGroupDefaults
+		*out = new(NodeGroupDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeTerminationHandler != nil {
+		in, out := &in.NodeTerminationHandler, &out.NodeTerminationHandler
+		*out = new(NodeTerminationHandler)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = new(ClusterHooks)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(Notifications)
+		**out = **in
+	}
+	if in.Alarms != nil {
+		in, out := &in.Alarms, &out.Alarms
+		*out = new(Alarms)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Budget != nil {
+		in, out := &in.Budget, &out.Budget
+		*out = new(Budget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Billing != nil {
+		in, out := &in.Billing, &out.Billing
+		*out = new(Billing)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AccessConfig != nil {
+		in, out := &in.AccessConfig, &out.AccessConfig
+		*out = new(AccessConfig)
+		**out = **in
+	}
+	if in.KubernetesNetworkConfig != nil {
+		in, out := &in.KubernetesNetworkConfig, &out.KubernetesNetworkConfig
+		*out = new(KubernetesNetworkConfig)
+		**out = **in
+	}
+	if in.CloudFormation != nil {
+		in, out := &in.CloudFormation, &out.CloudFormation
+		*out = new(ClusterCloudFormation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Addons != nil {
+		in, out := &in.Addons, &out.Addons
+		*out = make([]*Addon, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Addon)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.ClusterSettings != nil {
+		in.ClusterSettings.DeepCopyInto(&out.ClusterSettings)
+	}
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
 		*out = new(ClusterStatus)
@@ -219,6 +414,57 @@ func (in *ClusterEndpoints) DeepCopy() *ClusterEndpoints {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHooks) DeepCopyInto(out *ClusterHooks) {
+	*out = *in
+	if in.PreCreate != nil {
+		in, out := &in.PreCreate, &out.PreCreate
+		*out = make([]Hook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PostCreate != nil {
+		in, out := &in.PostCreate, &out.PostCreate
+		*out = make([]Hook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PreDelete != nil {
+		in, out := &in.PreDelete, &out.PreDelete
+		*out = make([]Hook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PostDelete != nil {
+		in, out := &in.PostDelete, &out.PostDelete
+		*out = make([]Hook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PostNodeGroupCreate != nil {
+		in, out := &in.PostNodeGroupCreate, &out.PostNodeGroupCreate
+		*out = make([]Hook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHooks.
+func (in *ClusterHooks) DeepCopy() *ClusterHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterIAM) DeepCopyInto(out *ClusterIAM) {
 	*out = *in
@@ -242,6 +488,11 @@ func (in *ClusterIAM) DeepCopyInto(out *ClusterIAM) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.FargatePodExecutionRolePolicyARNs != nil {
+		in, out := &in.FargatePodExecutionRolePolicyARNs, &out.FargatePodExecutionRolePolicyARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.WithOIDC != nil {
 		in, out := &in.WithOIDC, &out.WithOIDC
 		*out = new(bool)
@@ -281,6 +532,21 @@ func (in *ClusterIAMServiceAccount) DeepCopyInto(out *ClusterIAMServiceAccount)
 		copy(*out, *in)
 	}
 	in.AttachPolicy.DeepCopyInto(&out.AttachPolicy)
+	if in.AutomountServiceAccountToken != nil {
+		in, out := &in.AutomountServiceAccountToken, &out.AutomountServiceAccountToken
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CreateNamespace != nil {
+		in, out := &in.CreateNamespace, &out.CreateNamespace
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RoleOnly != nil {
+		in, out := &in.RoleOnly, &out.RoleOnly
+		*out = new(bool)
+		**out = **in
+	}
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
 		*out = new(ClusterIAMServiceAccountStatus)
@@ -307,6 +573,21 @@ func (in *ClusterIAMServiceAccountStatus) DeepCopyInto(out *ClusterIAMServiceAcc
 		*out = new(string)
 		**out = **in
 	}
+	if in.AttachedPolicyARNs != nil {
+		in, out := &in.AttachedPolicyARNs, &out.AttachedPolicyARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InlinePolicyNames != nil {
+		in, out := &in.InlinePolicyNames, &out.InlinePolicyNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Problems != nil {
+		in, out := &in.Problems, &out.Problems
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -330,6 +611,18 @@ func (in *ClusterMeta) DeepCopyInto(out *ClusterMeta) {
 			(*out)[key] = val
 		}
 	}
+	if in.RequiredTags != nil {
+		in, out := &in.RequiredTags, &out.RequiredTags
+		*out = make([]RequiredTag, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DeletionProtection != nil {
+		in, out := &in.DeletionProtection, &out.DeletionProtection
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -419,11 +712,21 @@ func (in *ClusterSubnets) DeepCopy() *ClusterSubnets {
 func (in *ClusterVPC) DeepCopyInto(out *ClusterVPC) {
 	*out = *in
 	in.Network.DeepCopyInto(&out.Network)
+	if in.SecurityGroupIDs != nil {
+		in, out := &in.SecurityGroupIDs, &out.SecurityGroupIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Subnets != nil {
 		in, out := &in.Subnets, &out.Subnets
 		*out = new(ClusterSubnets)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ControlPlaneSubnetIDs != nil {
+		in, out := &in.ControlPlaneSubnetIDs, &out.ControlPlaneSubnetIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.ExtraCIDRs != nil {
 		in, out := &in.ExtraCIDRs, &out.ExtraCIDRs
 		*out = make([]*ipnet.IPNet, len(*in))
@@ -454,6 +757,40 @@ func (in *ClusterVPC) DeepCopyInto(out *ClusterVPC) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PodSecurityGroups != nil {
+		in, out := &in.PodSecurityGroups, &out.PodSecurityGroups
+		*out = new(PodSecurityGroups)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrivateHostedZone != nil {
+		in, out := &in.PrivateHostedZone, &out.PrivateHostedZone
+		*out = new(PrivateHostedZone)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TransitGatewayCIDRs != nil {
+		in, out := &in.TransitGatewayCIDRs, &out.TransitGatewayCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TransitGatewayTags != nil {
+		in, out := &in.TransitGatewayTags, &out.TransitGatewayTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Peering != nil {
+		in, out := &in.Peering, &out.Peering
+		*out = make([]VPCPeering, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FlowLogs != nil {
+		in, out := &in.FlowLogs, &out.FlowLogs
+		*out = new(VPCFlowLogs)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -467,6 +804,22 @@ func (in *ClusterVPC) DeepCopy() *ClusterVPC {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FargateLogging) DeepCopyInto(out *FargateLogging) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FargateLogging.
+func (in *FargateLogging) DeepCopy() *FargateLogging {
+	if in == nil {
+		return nil
+	}
+	out := new(FargateLogging)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FargateProfile) DeepCopyInto(out *FargateProfile) {
 	*out = *in
@@ -489,6 +842,11 @@ func (in *FargateProfile) DeepCopyInto(out *FargateProfile) {
 			(*out)[key] = val
 		}
 	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(FargateLogging)
+		**out = **in
+	}
 	return
 }
 
@@ -536,51 +894,123 @@ func (in InlineDocument) DeepCopyInto(out *InlineDocument) {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManagedNodeGroup) DeepCopyInto(out *ManagedNodeGroup) {
+func (in *Hook) DeepCopyInto(out *Hook) {
 	*out = *in
-	if in.ScalingConfig != nil {
-		in, out := &in.ScalingConfig, &out.ScalingConfig
-		*out = new(ScalingConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.VolumeSize != nil {
-		in, out := &in.VolumeSize, &out.VolumeSize
-		*out = new(int)
-		**out = **in
-	}
-	if in.AvailabilityZones != nil {
-		in, out := &in.AvailabilityZones, &out.AvailabilityZones
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.SSH != nil {
-		in, out := &in.SSH, &out.SSH
-		*out = new(NodeGroupSSH)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Tags != nil {
-		in, out := &in.Tags, &out.Tags
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.IAM != nil {
-		in, out := &in.IAM, &out.IAM
-		*out = new(NodeGroupIAM)
-		(*in).DeepCopyInto(*out)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedNodeGroup.
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Hook.
+func (in *Hook) DeepCopy() *Hook {
+	if in == nil {
+		return nil
+	}
+	out := new(Hook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceSelector) DeepCopyInto(out *InstanceSelector) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceSelector.
+func (in *InstanceSelector) DeepCopy() *InstanceSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesNetworkConfig) DeepCopyInto(out *KubernetesNetworkConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesNetworkConfig.
+func (in *KubernetesNetworkConfig) DeepCopy() *KubernetesNetworkConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesNetworkConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedNodeGroup) DeepCopyInto(out *ManagedNodeGroup) {
+	*out = *in
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(InstanceSelector)
+		**out = **in
+	}
+	if in.ScalingConfig != nil {
+		in, out := &in.ScalingConfig, &out.ScalingConfig
+		*out = new(ScalingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VolumeSize != nil {
+		in, out := &in.VolumeSize, &out.VolumeSize
+		*out = new(int)
+		**out = **in
+	}
+	if in.VolumeEncrypted != nil {
+		in, out := &in.VolumeEncrypted, &out.VolumeEncrypted
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VolumeKmsKeyID != nil {
+		in, out := &in.VolumeKmsKeyID, &out.VolumeKmsKeyID
+		*out = new(string)
+		**out = **in
+	}
+	if in.AvailabilityZones != nil {
+		in, out := &in.AvailabilityZones, &out.AvailabilityZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SSH != nil {
+		in, out := &in.SSH, &out.SSH
+		*out = new(NodeGroupSSH)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.IAM != nil {
+		in, out := &in.IAM, &out.IAM
+		*out = new(NodeGroupIAM)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KubeletExtraConfig != nil {
+		in, out := &in.KubeletExtraConfig, &out.KubeletExtraConfig
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedNodeGroup.
 func (in *ManagedNodeGroup) DeepCopy() *ManagedNodeGroup {
 	if in == nil {
 		return nil
@@ -613,6 +1043,11 @@ func (in *Network) DeepCopy() *Network {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeGroup) DeepCopyInto(out *NodeGroup) {
 	*out = *in
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(InstanceSelector)
+		**out = **in
+	}
 	if in.InstancesDistribution != nil {
 		in, out := &in.InstancesDistribution, &out.InstancesDistribution
 		*out = new(NodeGroupInstancesDistribution)
@@ -685,6 +1120,22 @@ func (in *NodeGroup) DeepCopyInto(out *NodeGroup) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.VolumeThroughput != nil {
+		in, out := &in.VolumeThroughput, &out.VolumeThroughput
+		*out = new(int)
+		**out = **in
+	}
+	if in.AdditionalVolumes != nil {
+		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
+		*out = make([]*VolumeMapping, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(VolumeMapping)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 	if in.Labels != nil {
 		in, out := &in.Labels, &out.Labels
 		*out = make(map[string]string, len(*in))
@@ -738,6 +1189,21 @@ func (in *NodeGroup) DeepCopyInto(out *NodeGroup) {
 		in, out := &in.KubeletExtraConfig, &out.KubeletExtraConfig
 		*out = (*in).DeepCopy()
 	}
+	if in.InstanceStore != nil {
+		in, out := &in.InstanceStore, &out.InstanceStore
+		*out = new(NodeGroupInstanceStore)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GMSA != nil {
+		in, out := &in.GMSA, &out.GMSA
+		*out = new(NodeGroupGMSA)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Account != nil {
+		in, out := &in.Account, &out.Account
+		*out = new(NodeGroupAccount)
+		**out = **in
+	}
 	return
 }
 
@@ -751,6 +1217,22 @@ func (in *NodeGroup) DeepCopy() *NodeGroup {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupAccount) DeepCopyInto(out *NodeGroupAccount) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupAccount.
+func (in *NodeGroupAccount) DeepCopy() *NodeGroupAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeGroupBottlerocket) DeepCopyInto(out *NodeGroupBottlerocket) {
 	*out = *in
@@ -776,6 +1258,48 @@ func (in *NodeGroupBottlerocket) DeepCopy() *NodeGroupBottlerocket {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupDefaults) DeepCopyInto(out *NodeGroupDefaults) {
+	*out = *in
+	if in.VolumeEncryption != nil {
+		in, out := &in.VolumeEncryption, &out.VolumeEncryption
+		*out = new(VolumeEncryption)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupDefaults.
+func (in *NodeGroupDefaults) DeepCopy() *NodeGroupDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupGMSA) DeepCopyInto(out *NodeGroupGMSA) {
+	*out = *in
+	if in.DirectoryServiceAccess != nil {
+		in, out := &in.DirectoryServiceAccess, &out.DirectoryServiceAccess
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupGMSA.
+func (in *NodeGroupGMSA) DeepCopy() *NodeGroupGMSA {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupGMSA)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeGroupIAM) DeepCopyInto(out *NodeGroupIAM) {
 	*out = *in
@@ -915,6 +1439,27 @@ func (in *NodeGroupInstancesDistribution) DeepCopy() *NodeGroupInstancesDistribu
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupInstanceStore) DeepCopyInto(out *NodeGroupInstanceStore) {
+	*out = *in
+	if in.RAID0 != nil {
+		in, out := &in.RAID0, &out.RAID0
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupInstanceStore.
+func (in *NodeGroupInstanceStore) DeepCopy() *NodeGroupInstanceStore {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupInstanceStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeGroupSGs) DeepCopyInto(out *NodeGroupSGs) {
 	*out = *in
@@ -987,9 +1532,169 @@ func (in *NodeGroupSSH) DeepCopy() *NodeGroupSSH {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeTerminationHandler) DeepCopyInto(out *NodeTerminationHandler) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeTerminationHandler.
+func (in *NodeTerminationHandler) DeepCopy() *NodeTerminationHandler {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeTerminationHandler)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Alarms) DeepCopyInto(out *Alarms) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EmailSubscriptions != nil {
+		in, out := &in.EmailSubscriptions, &out.EmailSubscriptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.APIServerErrorThreshold != nil {
+		in, out := &in.APIServerErrorThreshold, &out.APIServerErrorThreshold
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Alarms.
+func (in *Alarms) DeepCopy() *Alarms {
+	if in == nil {
+		return nil
+	}
+	out := new(Alarms)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Budget) DeepCopyInto(out *Budget) {
+	*out = *in
+	if in.MaxMonthlyUSD != nil {
+		in, out := &in.MaxMonthlyUSD, &out.MaxMonthlyUSD
+		*out = new(float64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Budget.
+func (in *Budget) DeepCopy() *Budget {
+	if in == nil {
+		return nil
+	}
+	out := new(Budget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Notifications) DeepCopyInto(out *Notifications) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Notifications.
+func (in *Notifications) DeepCopy() *Notifications {
+	if in == nil {
+		return nil
+	}
+	out := new(Notifications)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PhaseTimeouts) DeepCopyInto(out *PhaseTimeouts) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PhaseTimeouts.
+func (in *PhaseTimeouts) DeepCopy() *PhaseTimeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(PhaseTimeouts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodSecurityGroups) DeepCopyInto(out *PodSecurityGroups) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CreateSamplePolicy != nil {
+		in, out := &in.CreateSamplePolicy, &out.CreateSamplePolicy
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSecurityGroups.
+func (in *PodSecurityGroups) DeepCopy() *PodSecurityGroups {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSecurityGroups)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrivateHostedZone) DeepCopyInto(out *PrivateHostedZone) {
+	*out = *in
+	if in.AssociatedVPCIDs != nil {
+		in, out := &in.AssociatedVPCIDs, &out.AssociatedVPCIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivateHostedZone.
+func (in *PrivateHostedZone) DeepCopy() *PrivateHostedZone {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivateHostedZone)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderConfig) DeepCopyInto(out *ProviderConfig) {
 	*out = *in
+	out.Timeouts = in.Timeouts
+	if in.ServiceEndpoints != nil {
+		in, out := &in.ServiceEndpoints, &out.ServiceEndpoints
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -1003,6 +1708,27 @@ func (in *ProviderConfig) DeepCopy() *ProviderConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequiredTag) DeepCopyInto(out *RequiredTag) {
+	*out = *in
+	if in.AllowedValues != nil {
+		in, out := &in.AllowedValues, &out.AllowedValues
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequiredTag.
+func (in *RequiredTag) DeepCopy() *RequiredTag {
+	if in == nil {
+		return nil
+	}
+	out := new(RequiredTag)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScalingConfig) DeepCopyInto(out *ScalingConfig) {
 	*out = *in
@@ -1054,3 +1780,114 @@ func (in *SecretsEncryption) DeepCopy() *SecretsEncryption {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPCFlowLogs) DeepCopyInto(out *VPCFlowLogs) {
+	*out = *in
+	if in.AggregationInterval != nil {
+		in, out := &in.AggregationInterval, &out.AggregationInterval
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPCFlowLogs.
+func (in *VPCFlowLogs) DeepCopy() *VPCFlowLogs {
+	if in == nil {
+		return nil
+	}
+	out := new(VPCFlowLogs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VPCPeering) DeepCopyInto(out *VPCPeering) {
+	*out = *in
+	if in.DestinationCIDRs != nil {
+		in, out := &in.DestinationCIDRs, &out.DestinationCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPCPeering.
+func (in *VPCPeering) DeepCopy() *VPCPeering {
+	if in == nil {
+		return nil
+	}
+	out := new(VPCPeering)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeEncryption) DeepCopyInto(out *VolumeEncryption) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.KMSKeyARN != nil {
+		in, out := &in.KMSKeyARN, &out.KMSKeyARN
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeEncryption.
+func (in *VolumeEncryption) DeepCopy() *VolumeEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeMapping) DeepCopyInto(out *VolumeMapping) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		*out = new(int)
+		**out = **in
+	}
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(string)
+		**out = **in
+	}
+	if in.IOPS != nil {
+		in, out := &in.IOPS, &out.IOPS
+		*out = new(int)
+		**out = **in
+	}
+	if in.Throughput != nil {
+		in, out := &in.Throughput, &out.Throughput
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeMapping.
+func (in *VolumeMapping) DeepCopy() *VolumeMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeMapping)
+	in.DeepCopyInto(out)
+	return out
+}
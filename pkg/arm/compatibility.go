@@ -0,0 +1,294 @@
+// Package arm checks whether the workloads of nodegroups can run on ARM, i.e. Graviton, instances
+package arm
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/utils"
+	"github.com/weaveworks/eksctl/pkg/utils/registry"
+)
+
+const (
+	archARM64 = "arm64"
+
+	// maxManifestSize limits the size of the image manifests and configs fetched from registries
+	maxManifestSize = 4 << 20
+)
+
+var archLabels = []string{"kubernetes.io/arch", "beta.kubernetes.io/arch"}
+
+// systemDaemonSets are the DaemonSets EKS runs on the nodes of every architecture, from images
+// in the EKS registries, so their pods don't keep nodegroups from being migrated
+var systemDaemonSets = []string{"aws-node", "kube-proxy"}
+
+// ecrRegistryRegex matches the hostnames of ECR registries, capturing the registry ID and the region
+var ecrRegistryRegex = regexp.MustCompile(`^(\d{12})\.dkr\.ecr(?:-fips)?\.([a-z0-9-]+)\.amazonaws\.com(?:\.cn)?$`)
+
+// Status of a nodegroup
+const (
+	StatusCompatible   = "compatible"
+	StatusIncompatible = "incompatible"
+	StatusARM          = "arm"
+)
+
+// NodeGroup is a nodegroup to check
+type NodeGroup interface {
+	NameString() string
+	ListOptions() metav1.ListOptions
+}
+
+// NodeGroupReport is the result of checking a nodegroup
+type NodeGroupReport struct {
+	Name         string
+	InstanceType string
+	// GravitonInstanceType is the Graviton instance type equivalent to InstanceType, if any
+	GravitonInstanceType string
+	Status               string
+	// Blockers are the reasons the nodegroup cannot be migrated, e.g. images that
+	// aren't built for arm64, or pods that select amd64 nodes
+	Blockers []string
+}
+
+// ImageInspector returns the architectures an image is built for
+type ImageInspector interface {
+	Architectures(image string) ([]string, error)
+}
+
+// ECRClientGetter returns a client of the ECR API in the given region
+type ECRClientGetter func(region string) (ecriface.ECRAPI, error)
+
+type registryInspector struct {
+	httpClient   *http.Client
+	newECRClient ECRClientGetter
+	// ecrTokens caches the authorization tokens of ECR registries by hostname
+	ecrTokens map[string]string
+}
+
+// NewRegistryInspector returns an ImageInspector that fetches the manifests of images from their
+// registries, authenticating to ECR registries with the tokens of the ECR clients newECRClient
+// returns, and to other registries anonymously, so the manifests of their private images
+// cannot be inspected
+func NewRegistryInspector(newECRClient ECRClientGetter) ImageInspector {
+	return &registryInspector{
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		newECRClient: newECRClient,
+		ecrTokens:    map[string]string{},
+	}
+}
+
+func (r *registryInspector) Architectures(image string) ([]string, error) {
+	ref, err := registry.ParseImage(image)
+	if err != nil {
+		return nil, err
+	}
+	client := registry.NewClient(r.httpClient, maxManifestSize)
+	if match := ecrRegistryRegex.FindStringSubmatch(ref.Registry); match != nil {
+		token, err := r.ecrToken(ref.Registry, match[1], match[2])
+		if err != nil {
+			return nil, err
+		}
+		client.SetBasicAuth(token)
+	}
+	return client.Architectures(ref)
+}
+
+// ecrToken returns the authorization token of an ECR registry, which is only valid in its region
+func (r *registryInspector) ecrToken(hostname, registryID, region string) (string, error) {
+	if token, ok := r.ecrTokens[hostname]; ok {
+		return token, nil
+	}
+	ecrAPI, err := r.newECRClient(region)
+	if err != nil {
+		return "", err
+	}
+	output, err := ecrAPI.GetAuthorizationToken(&ecr.GetAuthorizationTokenInput{
+		RegistryIds: aws.StringSlice([]string{registryID}),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "getting an authorization token for %s", hostname)
+	}
+	if len(output.AuthorizationData) == 0 || output.AuthorizationData[0].AuthorizationToken == nil {
+		return "", fmt.Errorf("no authorization token was returned for %s", hostname)
+	}
+	token := *output.AuthorizationData[0].AuthorizationToken
+	r.ecrTokens[hostname] = token
+	return token, nil
+}
+
+type imageArchitectures struct {
+	architectures []string
+	err           error
+}
+
+// Checker checks the workloads of nodegroups
+type Checker struct {
+	clientSet kubernetes.Interface
+	inspector ImageInspector
+	images    map[string]imageArchitectures
+}
+
+// NewChecker returns a Checker
+func NewChecker(clientSet kubernetes.Interface, inspector ImageInspector) *Checker {
+	return &Checker{
+		clientSet: clientSet,
+		inspector: inspector,
+		images:    map[string]imageArchitectures{},
+	}
+}
+
+// Check inspects the images and the node selection of the pods running on the nodes of the
+// nodegroup, instanceType is the instance type of the nodegroup
+func (c *Checker) Check(ng NodeGroup, instanceType string) (*NodeGroupReport, error) {
+	report := &NodeGroupReport{
+		Name:                 ng.NameString(),
+		InstanceType:         instanceType,
+		GravitonInstanceType: GravitonInstanceType(instanceType),
+		Status:               StatusCompatible,
+	}
+	if utils.IsARMInstanceType(instanceType) {
+		report.Status = StatusARM
+		return report, nil
+	}
+
+	nodes, err := c.clientSet.CoreV1().Nodes().List(ng.ListOptions())
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing nodes of nodegroup %q", ng.NameString())
+	}
+
+	blockers := map[string]bool{}
+	if report.GravitonInstanceType == "" {
+		blockers[fmt.Sprintf("no Graviton instance type is equivalent to %s", instanceType)] = true
+	}
+	if len(nodes.Items) == 0 {
+		blockers["the nodegroup has no nodes, so its workloads cannot be inspected"] = true
+	}
+
+	nodeNames := map[string]bool{}
+	for _, node := range nodes.Items {
+		nodeNames[node.Name] = true
+	}
+	pods, err := c.clientSet.CoreV1().Pods(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing pods")
+	}
+	for _, pod := range pods.Items {
+		if !nodeNames[pod.Spec.NodeName] || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed || isSystemPod(&pod) {
+			continue
+		}
+		for _, blocker := range c.checkPod(&pod) {
+			blockers[blocker] = true
+		}
+	}
+
+	for blocker := range blockers {
+		report.Blockers = append(report.Blockers, blocker)
+	}
+	sort.Strings(report.Blockers)
+	if len(report.Blockers) > 0 {
+		report.Status = StatusIncompatible
+	}
+	return report, nil
+}
+
+func (c *Checker) checkPod(pod *corev1.Pod) []string {
+	var blockers []string
+	if !allowsARM64(pod) {
+		blockers = append(blockers, fmt.Sprintf("%s only selects nodes of other architectures", workloadName(pod)))
+	}
+
+	containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, container := range containers {
+		result, ok := c.images[container.Image]
+		if !ok {
+			result.architectures, result.err = c.inspector.Architectures(container.Image)
+			c.images[container.Image] = result
+		}
+		switch {
+		case result.err != nil:
+			blockers = append(blockers, fmt.Sprintf("image %s cannot be inspected: %s", container.Image, result.err.Error()))
+		case !contains(result.architectures, archARM64):
+			blockers = append(blockers, fmt.Sprintf("image %s is not built for arm64 (%s)", container.Image, strings.Join(result.architectures, ", ")))
+		}
+	}
+	return blockers
+}
+
+func isSystemPod(pod *corev1.Pod) bool {
+	owner := metav1.GetControllerOf(pod)
+	return pod.Namespace == metav1.NamespaceSystem && owner != nil && owner.Kind == "DaemonSet" && contains(systemDaemonSets, owner.Name)
+}
+
+// allowsARM64 returns false when the node selector or the required node affinity
+// of the pod exclude arm64 nodes
+func allowsARM64(pod *corev1.Pod) bool {
+	for _, label := range archLabels {
+		if arch, ok := pod.Spec.NodeSelector[label]; ok && arch != archARM64 {
+			return false
+		}
+	}
+
+	affinity := pod.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	// the terms are ORed, so any term allowing arm64 is enough
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		if termAllowsARM64(term) {
+			return true
+		}
+	}
+	return false
+}
+
+func termAllowsARM64(term corev1.NodeSelectorTerm) bool {
+	for _, expression := range term.MatchExpressions {
+		if !contains(archLabels, expression.Key) {
+			continue
+		}
+		switch expression.Operator {
+		case corev1.NodeSelectorOpIn:
+			if !contains(expression.Values, archARM64) {
+				return false
+			}
+		case corev1.NodeSelectorOpNotIn:
+			if contains(expression.Values, archARM64) {
+				return false
+			}
+		case corev1.NodeSelectorOpDoesNotExist:
+			return false
+		}
+	}
+	return true
+}
+
+// workloadName returns the controller of the pod, or the pod itself, so that the replicas
+// of a workload are reported once
+func workloadName(pod *corev1.Pod) string {
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		return fmt.Sprintf("%s %s/%s", owner.Kind, pod.Namespace, owner.Name)
+	}
+	return fmt.Sprintf("Pod %s/%s", pod.Namespace, pod.Name)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
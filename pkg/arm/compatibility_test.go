@@ -0,0 +1,185 @@
+package arm_test
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/arm"
+)
+
+type fakeInspector map[string][]string
+
+func (f fakeInspector) Architectures(image string) ([]string, error) {
+	architectures, ok := f[image]
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+	return architectures, nil
+}
+
+type fakeECR struct {
+	ecriface.ECRAPI
+	region      string
+	registryIDs []string
+}
+
+func (f *fakeECR) GetAuthorizationToken(input *ecr.GetAuthorizationTokenInput) (*ecr.GetAuthorizationTokenOutput, error) {
+	for _, id := range input.RegistryIds {
+		f.registryIDs = append(f.registryIDs, *id)
+	}
+	return nil, errors.New("AccessDeniedException")
+}
+
+var _ = Describe("ARM compatibility", func() {
+	var (
+		ng      *api.NodeGroup
+		objects []runtime.Object
+		checker func() *Checker
+	)
+
+	node := func(name, nodeGroup string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{api.NodeGroupNameLabel: nodeGroup},
+		}}
+	}
+	pod := func(name, nodeName string, images ...string) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		for _, image := range images {
+			p.Spec.Containers = append(p.Spec.Containers, corev1.Container{Name: image, Image: image})
+		}
+		return p
+	}
+
+	BeforeEach(func() {
+		ng = api.NewNodeGroup()
+		ng.Name = "ng-1"
+		objects = []runtime.Object{node("node-1", "ng-1"), node("node-2", "ng-2")}
+		checker = func() *Checker {
+			return NewChecker(fake.NewSimpleClientset(objects...), fakeInspector{
+				"nginx:1.19":        {"amd64", "arm64"},
+				"example/app:v1":    {"amd64"},
+				"example/legacy:v1": {"amd64"},
+			})
+		}
+	})
+
+	It("reports a nodegroup with multi-arch images as compatible", func() {
+		objects = append(objects, pod("web", "node-1", "nginx:1.19"), pod("app", "node-2", "example/app:v1"))
+
+		report, err := checker().Check(ng, "m5.xlarge")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Status).To(Equal(StatusCompatible))
+		Expect(report.GravitonInstanceType).To(Equal("m6g.xlarge"))
+		Expect(report.Blockers).To(BeEmpty())
+	})
+
+	It("reports images without arm64 support and images that cannot be inspected", func() {
+		objects = append(objects, pod("app", "node-1", "nginx:1.19", "example/app:v1", "private/app:v1"))
+		completed := pod("job", "node-1", "example/legacy:v1")
+		completed.Status.Phase = corev1.PodSucceeded
+		objects = append(objects, completed)
+
+		report, err := checker().Check(ng, "m5.xlarge")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Status).To(Equal(StatusIncompatible))
+		Expect(report.Blockers).To(ConsistOf(
+			"image example/app:v1 is not built for arm64 (amd64)",
+			"image private/app:v1 cannot be inspected: unauthorized",
+		))
+	})
+
+	It("skips the system DaemonSets of EKS", func() {
+		isController := true
+		for _, name := range []string{"aws-node", "kube-proxy"} {
+			systemPod := pod(name+"-abcde", "node-1", "602401143452.dkr.ecr.us-west-2.amazonaws.com/eks/"+name+":v1")
+			systemPod.Namespace = metav1.NamespaceSystem
+			systemPod.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: name, Controller: &isController}}
+			objects = append(objects, systemPod)
+		}
+
+		report, err := checker().Check(ng, "m5.xlarge")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Status).To(Equal(StatusCompatible))
+	})
+
+	It("reports pods that select amd64 nodes", func() {
+		selecting := pod("web", "node-1", "nginx:1.19")
+		selecting.Spec.NodeSelector = map[string]string{"kubernetes.io/arch": "amd64"}
+		affinity := pod("api", "node-1", "nginx:1.19")
+		affinity.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{
+						Key:      "beta.kubernetes.io/arch",
+						Operator: corev1.NodeSelectorOpIn,
+						Values:   []string{"amd64"},
+					}},
+				}},
+			},
+		}}
+		objects = append(objects, selecting, affinity)
+
+		report, err := checker().Check(ng, "c5.large")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Blockers).To(ConsistOf(
+			"Pod default/web only selects nodes of other architectures",
+			"Pod default/api only selects nodes of other architectures",
+		))
+	})
+
+	It("reports nodegroups without nodes or a Graviton instance type", func() {
+		ng.Name = "ng-gpu"
+		report, err := checker().Check(ng, "p3.2xlarge")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Blockers).To(ConsistOf(
+			"no Graviton instance type is equivalent to p3.2xlarge",
+			"the nodegroup has no nodes, so its workloads cannot be inspected",
+		))
+	})
+
+	It("skips ARM nodegroups", func() {
+		report, err := checker().Check(ng, "m6g.large")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Status).To(Equal(StatusARM))
+	})
+
+	It("authenticates to ECR registries in their region", func() {
+		fake := &fakeECR{}
+		inspector := NewRegistryInspector(func(region string) (ecriface.ECRAPI, error) {
+			fake.region = region
+			return fake, nil
+		})
+
+		_, err := inspector.Architectures("123456789012.dkr.ecr.eu-north-1.amazonaws.com/team/app:v1")
+		Expect(err).To(MatchError("getting an authorization token for 123456789012.dkr.ecr.eu-north-1.amazonaws.com: AccessDeniedException"))
+		Expect(fake.region).To(Equal("eu-north-1"))
+		Expect(fake.registryIDs).To(Equal([]string{"123456789012"}))
+	})
+
+	DescribeTable("Graviton instance types",
+		func(instanceType, gravitonInstanceType string) {
+			Expect(GravitonInstanceType(instanceType)).To(Equal(gravitonInstanceType))
+		},
+		Entry("general purpose", "m5.large", "m6g.large"),
+		Entry("AMD and NVMe storage", "m5ad.4xlarge", "m6gd.4xlarge"),
+		Entry("compute optimized", "c5n.2xlarge", "c6g.2xlarge"),
+		Entry("burstable", "t3.micro", "t4g.micro"),
+		Entry("unavailable size", "m5.24xlarge", ""),
+		Entry("GPU", "g4dn.xlarge", ""),
+	)
+})
@@ -0,0 +1,45 @@
+package arm
+
+import (
+	"regexp"
+	"strings"
+)
+
+// gravitonFamilies maps the classes of x86 instance families to their Graviton equivalents
+var gravitonFamilies = map[string]string{
+	"m": "m6g",
+	"c": "c6g",
+	"r": "r6g",
+	"t": "t4g",
+}
+
+var gravitonSizes = map[string][]string{
+	"m6g": {"medium", "large", "xlarge", "2xlarge", "4xlarge", "8xlarge", "12xlarge", "16xlarge"},
+	"c6g": {"medium", "large", "xlarge", "2xlarge", "4xlarge", "8xlarge", "12xlarge", "16xlarge"},
+	"r6g": {"medium", "large", "xlarge", "2xlarge", "4xlarge", "8xlarge", "12xlarge", "16xlarge"},
+	"t4g": {"nano", "micro", "small", "medium", "large", "xlarge", "2xlarge"},
+}
+
+// instanceTypeRegex matches the class, generation, attributes and size of an instance type,
+// e.g. m, 5, ad and xlarge for m5ad.xlarge
+var instanceTypeRegex = regexp.MustCompile(`^([a-z]+)([0-9]+)([a-z]*)\.([a-z0-9]+)$`)
+
+// GravitonInstanceType returns the Graviton instance type of the same class and size as the
+// given general purpose, compute or memory optimized, or burstable instance type, with local
+// NVMe storage when the instance type has it, or an empty string when there is none
+func GravitonInstanceType(instanceType string) string {
+	match := instanceTypeRegex.FindStringSubmatch(instanceType)
+	if match == nil {
+		return ""
+	}
+	class, attributes, size := match[1], match[3], match[4]
+
+	family, ok := gravitonFamilies[class]
+	if !ok || !contains(gravitonSizes[family], size) {
+		return ""
+	}
+	if strings.Contains(attributes, "d") && class != "t" {
+		family += "d"
+	}
+	return family + "." + size
+}
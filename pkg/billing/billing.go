@@ -0,0 +1,128 @@
+// Package billing activates cost allocation tags, so that the costs of clusters
+// can be grouped by cluster in the Billing console and in Cost Explorer
+package billing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/costexplorer"
+	"github.com/pkg/errors"
+)
+
+// Values of `CostAllocationTagStatusEntry.Status`
+const (
+	CostAllocationTagStatusActive   = "Active"
+	CostAllocationTagStatusInactive = "Inactive"
+)
+
+// CostAllocationTagsAPI is the operation of the Cost Explorer API that activates cost
+// allocation tags; it's defined here as the version of aws-sdk-go eksctl uses predates it
+type CostAllocationTagsAPI interface {
+	UpdateCostAllocationTagsStatus(input *UpdateCostAllocationTagsStatusInput) (*UpdateCostAllocationTagsStatusOutput, error)
+}
+
+// CostAllocationTagStatusEntry is the status to set a cost allocation tag to
+type CostAllocationTagStatusEntry struct {
+	_ struct{} `type:"structure"`
+
+	TagKey *string `type:"string" required:"true"`
+	Status *string `type:"string" required:"true"`
+}
+
+// UpdateCostAllocationTagsStatusInput is the input of UpdateCostAllocationTagsStatus
+type UpdateCostAllocationTagsStatusInput struct {
+	_ struct{} `type:"structure"`
+
+	CostAllocationTagsStatus []*CostAllocationTagStatusEntry `type:"list" required:"true"`
+}
+
+// UpdateCostAllocationTagsStatusError is the error of updating the status of one tag
+type UpdateCostAllocationTagsStatusError struct {
+	_ struct{} `type:"structure"`
+
+	TagKey  *string `type:"string"`
+	Code    *string `type:"string"`
+	Message *string `type:"string"`
+}
+
+// UpdateCostAllocationTagsStatusOutput is the output of UpdateCostAllocationTagsStatus
+type UpdateCostAllocationTagsStatusOutput struct {
+	_ struct{} `type:"structure"`
+
+	Errors []*UpdateCostAllocationTagsStatusError `type:"list"`
+}
+
+type costExplorer struct {
+	client *costexplorer.CostExplorer
+}
+
+// NewCostAllocationTagsAPI returns an implementation of CostAllocationTagsAPI using
+// the protocol handlers and credentials of the Cost Explorer client
+func NewCostAllocationTagsAPI(client *costexplorer.CostExplorer) CostAllocationTagsAPI {
+	return &costExplorer{client: client}
+}
+
+func (c *costExplorer) UpdateCostAllocationTagsStatus(input *UpdateCostAllocationTagsStatusInput) (*UpdateCostAllocationTagsStatusOutput, error) {
+	op := &request.Operation{
+		Name:       "UpdateCostAllocationTagsStatus",
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}
+	output := &UpdateCostAllocationTagsStatusOutput{}
+	return output, c.client.NewRequest(op, input, output).Send()
+}
+
+// ActivateCostAllocationTags activates the tags as cost allocation tags, which is only
+// allowed in the management (payer) account of an organization; tags can only be
+// activated once they've been seen in billing data, up to 24 hours after tagging resources
+func ActivateCostAllocationTags(api CostAllocationTagsAPI, tagKeys ...string) error {
+	input := &UpdateCostAllocationTagsStatusInput{}
+	for _, tagKey := range tagKeys {
+		input.CostAllocationTagsStatus = append(input.CostAllocationTagsStatus, &CostAllocationTagStatusEntry{
+			TagKey: aws.String(tagKey),
+			Status: aws.String(CostAllocationTagStatusActive),
+		})
+	}
+
+	output, err := api.UpdateCostAllocationTagsStatus(input)
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && isPermissionError(awsErr.Code()) {
+			return fmt.Errorf("insufficient permissions to activate cost allocation tags (%s), this requires ce:UpdateCostAllocationTagsStatus in the management account", awsErr.Code())
+		}
+		return errors.Wrap(err, "activating cost allocation tags")
+	}
+
+	var messages []string
+	for _, tagErr := range output.Errors {
+		messages = append(messages, fmt.Sprintf("%s: %s (%s)", aws.StringValue(tagErr.TagKey), aws.StringValue(tagErr.Message), aws.StringValue(tagErr.Code)))
+	}
+	if len(messages) > 0 {
+		return fmt.Errorf("activating cost allocation tags: %s", strings.Join(messages, ", "))
+	}
+	return nil
+}
+
+func isPermissionError(code string) bool {
+	switch code {
+	case "AccessDeniedException", "AccessDenied", "UnauthorizedOperation":
+		return true
+	}
+	return false
+}
+
+// Instructions describes how to activate the tags manually, e.g. when the credentials
+// in use aren't allowed to, or belong to a member account
+func Instructions(tagKeys ...string) string {
+	var statuses []string
+	for _, tagKey := range tagKeys {
+		statuses = append(statuses, fmt.Sprintf("TagKey=%s,Status=%s", tagKey, CostAllocationTagStatusActive))
+	}
+	return fmt.Sprintf("to activate the cost allocation tags %s, run 'aws ce update-cost-allocation-tags-status --cost-allocation-tags-status %s' "+
+		"with credentials of the management account, or select them in 'Cost allocation tags' of the Billing console; "+
+		"tags can be activated up to 24 hours after the first resources are tagged",
+		strings.Join(tagKeys, ", "), strings.Join(statuses, " "))
+}
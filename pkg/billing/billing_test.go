@@ -0,0 +1,90 @@
+package billing
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/costexplorer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type fakeCostAllocationTagsAPI struct {
+	input  *UpdateCostAllocationTagsStatusInput
+	output *UpdateCostAllocationTagsStatusOutput
+	err    error
+}
+
+func (f *fakeCostAllocationTagsAPI) UpdateCostAllocationTagsStatus(input *UpdateCostAllocationTagsStatusInput) (*UpdateCostAllocationTagsStatusOutput, error) {
+	f.input = input
+	return f.output, f.err
+}
+
+var _ = Describe("cost allocation tags", func() {
+	It("activates the tags", func() {
+		fake := &fakeCostAllocationTagsAPI{output: &UpdateCostAllocationTagsStatusOutput{}}
+		Expect(ActivateCostAllocationTags(fake, "eks:cluster-name")).To(Succeed())
+
+		Expect(fake.input.CostAllocationTagsStatus).To(HaveLen(1))
+		Expect(*fake.input.CostAllocationTagsStatus[0].TagKey).To(Equal("eks:cluster-name"))
+		Expect(*fake.input.CostAllocationTagsStatus[0].Status).To(Equal(CostAllocationTagStatusActive))
+	})
+
+	It("reports the errors of tags", func() {
+		fake := &fakeCostAllocationTagsAPI{output: &UpdateCostAllocationTagsStatusOutput{
+			Errors: []*UpdateCostAllocationTagsStatusError{{
+				TagKey:  aws.String("eks:cluster-name"),
+				Code:    aws.String("TagKeysNotFoundException"),
+				Message: aws.String("tag key not found"),
+			}},
+		}}
+		err := ActivateCostAllocationTags(fake, "eks:cluster-name")
+		Expect(err).To(MatchError("activating cost allocation tags: eks:cluster-name: tag key not found (TagKeysNotFoundException)"))
+	})
+
+	It("reports missing permissions", func() {
+		fake := &fakeCostAllocationTagsAPI{err: awserr.New("AccessDeniedException", "not authorized", nil)}
+		err := ActivateCostAllocationTags(fake, "eks:cluster-name")
+		Expect(err).To(MatchError(ContainSubstring("requires ce:UpdateCostAllocationTagsStatus in the management account")))
+	})
+
+	It("describes how to activate the tags manually", func() {
+		Expect(Instructions("eks:cluster-name")).To(ContainSubstring("aws ce update-cost-allocation-tags-status --cost-allocation-tags-status TagKey=eks:cluster-name,Status=Active"))
+	})
+
+	It("calls the Cost Explorer API", func() {
+		var (
+			target string
+			body   map[string]interface{}
+		)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			target = r.Header.Get("X-Amz-Target")
+			data, err := ioutil.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(json.Unmarshal(data, &body)).To(Succeed())
+			w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+			_, _ = w.Write([]byte(`{"Errors":[]}`))
+		}))
+		defer server.Close()
+
+		s := session.Must(session.NewSession(&aws.Config{
+			Region:      aws.String("us-east-1"),
+			Endpoint:    aws.String(server.URL),
+			Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		}))
+		Expect(ActivateCostAllocationTags(NewCostAllocationTagsAPI(costexplorer.New(s)), "eks:cluster-name")).To(Succeed())
+
+		Expect(target).To(Equal("AWSInsightsIndexService.UpdateCostAllocationTagsStatus"))
+		Expect(body).To(Equal(map[string]interface{}{
+			"CostAllocationTagsStatus": []interface{}{
+				map[string]interface{}{"TagKey": "eks:cluster-name", "Status": "Active"},
+			},
+		}))
+	})
+})
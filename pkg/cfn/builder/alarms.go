@@ -0,0 +1,174 @@
+package builder
+
+import (
+	"fmt"
+
+	gfn "github.com/awslabs/goformation/cloudformation"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
+)
+
+const (
+	alarmsTopicResource = "AlarmsTopic"
+
+	// alarmPeriod is the period in seconds over which the metrics of the alarms are evaluated
+	alarmPeriod = 300
+	// natGatewayPacketsDropThreshold is the number of packets dropped by a NAT gateway in a period
+	// above which its alarm goes off
+	natGatewayPacketsDropThreshold = 100
+)
+
+// APIServerErrorsMetric returns the namespace and the name of the metric that counts the 5xx
+// responses of the API server, which is populated by a metric filter on the audit logs
+func APIServerErrorsMetric(clusterName string) (string, string) {
+	return "eksctl/" + clusterName, "APIServerErrors"
+}
+
+// addResourcesForAlarms adds the SNS topic the alarms notify, unless an existing topic is used,
+// along with the alarms of the NAT gateways and of the API server
+func (c *ClusterResourceSet) addResourcesForAlarms() {
+	topic := gfn.NewString(c.spec.Alarms.SNSTopicARN)
+	if c.spec.Alarms.SNSTopicARN == "" {
+		var subscriptions []interface{}
+		for _, email := range c.spec.Alarms.EmailSubscriptions {
+			subscriptions = append(subscriptions, map[string]interface{}{
+				"Protocol": "email",
+				"Endpoint": email,
+			})
+		}
+		topic = c.newResource(alarmsTopicResource, &awsCloudFormationResource{
+			Type: "AWS::SNS::Topic",
+			Properties: map[string]interface{}{
+				"DisplayName":  fmt.Sprintf("Alarms of EKS cluster %q", c.spec.Metadata.Name),
+				"Subscription": subscriptions,
+			},
+		})
+		c.rs.defineOutputWithoutCollector(outputs.ClusterAlarmsTopicARN, topic, true)
+	}
+
+	for i, natGateway := range c.natGateways {
+		c.newResource(fmt.Sprintf("NATGatewayPacketsDropAlarm%d", i), newAlarm(
+			fmt.Sprintf("Packets dropped by a NAT gateway of EKS cluster %q", c.spec.Metadata.Name),
+			topic,
+			map[string]interface{}{
+				"Namespace":  "AWS/NATGateway",
+				"MetricName": "PacketsDropCount",
+				"Dimensions": []interface{}{
+					map[string]interface{}{"Name": "NatGatewayId", "Value": natGateway},
+				},
+				"Statistic":          "Sum",
+				"Period":             alarmPeriod,
+				"EvaluationPeriods":  1,
+				"Threshold":          natGatewayPacketsDropThreshold,
+				"ComparisonOperator": "GreaterThanThreshold",
+			},
+		))
+	}
+
+	if c.spec.HasClusterCloudWatchLogType("audit") {
+		namespace, metricName := APIServerErrorsMetric(c.spec.Metadata.Name)
+		c.newResource("APIServerErrorsAlarm", newAlarm(
+			fmt.Sprintf("5xx responses of the API server of EKS cluster %q", c.spec.Metadata.Name),
+			topic,
+			map[string]interface{}{
+				"Namespace":          namespace,
+				"MetricName":         metricName,
+				"Statistic":          "Sum",
+				"Period":             alarmPeriod,
+				"EvaluationPeriods":  1,
+				"Threshold":          *c.spec.Alarms.APIServerErrorThreshold,
+				"ComparisonOperator": "GreaterThanThreshold",
+			},
+		))
+	}
+}
+
+// addResourcesForAlarms adds the alarms of instances missing from the nodegroup, i.e. fewer
+// instances in service than desired, and of failed status checks of its instances
+func (n *NodeGroupResourceSet) addResourcesForAlarms(asg *awsCloudFormationResource) {
+	topic := gfn.NewString(n.clusterSpec.Alarms.SNSTopicARN)
+	if n.clusterSpec.Alarms.SNSTopicARN == "" {
+		topic = makeImportValue(n.clusterStackName, outputs.ClusterAlarmsTopicARN)
+	}
+
+	// the capacity metrics of the ASG are only published when collected
+	asg.Properties["MetricsCollection"] = []interface{}{
+		map[string]interface{}{
+			"Granularity": "1Minute",
+			"Metrics":     []string{"GroupDesiredCapacity", "GroupInServiceInstances"},
+		},
+	}
+	dimensions := []interface{}{
+		map[string]interface{}{"Name": "AutoScalingGroupName", "Value": gfn.MakeRef("NodeGroup")},
+	}
+	groupMetric := func(id, metricName, statistic string) map[string]interface{} {
+		return map[string]interface{}{
+			"Id": id,
+			"MetricStat": map[string]interface{}{
+				"Metric": map[string]interface{}{
+					"Namespace":  "AWS/AutoScaling",
+					"MetricName": metricName,
+					"Dimensions": dimensions,
+				},
+				"Period": alarmPeriod,
+				"Stat":   statistic,
+			},
+			"ReturnData": false,
+		}
+	}
+
+	n.newResource("NodeGroupCapacityAlarm", newAlarm(
+		fmt.Sprintf("Instances missing from nodegroup %q of EKS cluster %q", n.nodeGroupName, n.clusterSpec.Metadata.Name),
+		topic,
+		map[string]interface{}{
+			"Metrics": []interface{}{
+				groupMetric("desired", "GroupDesiredCapacity", "Minimum"),
+				groupMetric("inService", "GroupInServiceInstances", "Maximum"),
+				map[string]interface{}{
+					"Id":         "missing",
+					"Expression": "desired - inService",
+					"Label":      "Missing instances",
+					"ReturnData": true,
+				},
+			},
+			// instances take a few minutes to be in service after a scale up
+			"EvaluationPeriods":  3,
+			"Threshold":          0,
+			"ComparisonOperator": "GreaterThanThreshold",
+		},
+	))
+
+	n.newResource("NodeGroupStatusCheckAlarm", newAlarm(
+		fmt.Sprintf("Failed status checks of instances of nodegroup %q of EKS cluster %q", n.nodeGroupName, n.clusterSpec.Metadata.Name),
+		topic,
+		map[string]interface{}{
+			"Namespace":          "AWS/EC2",
+			"MetricName":         "StatusCheckFailed",
+			"Dimensions":         dimensions,
+			"Statistic":          "Maximum",
+			"Period":             alarmPeriod,
+			"EvaluationPeriods":  2,
+			"Threshold":          0,
+			"ComparisonOperator": "GreaterThanThreshold",
+		},
+	))
+}
+
+// newAlarm returns a CloudWatch alarm that notifies the topic when it goes off, missing data
+// e.g. of a nodegroup scaled to zero doesn't make it go off
+func newAlarm(description string, topic *gfn.Value, properties map[string]interface{}) *awsCloudFormationResource {
+	properties["AlarmDescription"] = description
+	properties["AlarmActions"] = []*gfn.Value{topic}
+	properties["TreatMissingData"] = "notBreaching"
+	return &awsCloudFormationResource{
+		Type:       "AWS::CloudWatch::Alarm",
+		Properties: properties,
+	}
+}
+
+// alarmsOnNodeGroup reports whether the default alarms are created for the nodegroup; nodegroups
+// in another account can't import the topic from the cluster stack
+func alarmsOnNodeGroup(clusterSpec *api.ClusterConfig, ng *api.NodeGroup) bool {
+	return clusterSpec.HasAlarms() && ng.Account == nil
+}
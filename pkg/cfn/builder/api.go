@@ -6,6 +6,7 @@ import (
 
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
 	gfn "github.com/awslabs/goformation/cloudformation"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
 )
 
@@ -16,10 +17,11 @@ const (
 )
 
 type awsCloudFormationResource struct {
-	Type         string
-	Properties   map[string]interface{}
-	UpdatePolicy map[string]map[string]string `json:",omitempty"`
-	DependsOn    []string                     `json:",omitempty"`
+	Type           string
+	Properties     map[string]interface{}
+	UpdatePolicy   map[string]map[string]string `json:",omitempty"`
+	DependsOn      []string                     `json:",omitempty"`
+	DeletionPolicy string                       `json:",omitempty"`
 }
 
 // ResourceSet is an interface which cluster and nodegroup builders
@@ -100,6 +102,22 @@ func (r *resourceSet) newResource(name string, resource interface{}) *gfn.Value
 	return gfn.MakeRef(name)
 }
 
+// addInstanceSelectorMetadata records the instance selector and the instance types it was resolved
+// to in the metadata of the template, as the instance types of a region change over time, and the
+// template is reused on updates
+func (r *resourceSet) addInstanceSelectorMetadata(selector *api.InstanceSelector, instanceTypes []string) {
+	if selector == nil {
+		return
+	}
+	if r.template.Metadata == nil {
+		r.template.Metadata = map[string]interface{}{}
+	}
+	r.template.Metadata["InstanceSelector"] = map[string]interface{}{
+		"Selector":      selector,
+		"InstanceTypes": instanceTypes,
+	}
+}
+
 // renderJSON renders template as JSON
 func (r *resourceSet) renderJSON() ([]byte, error) {
 	return r.template.JSON()
@@ -76,22 +76,56 @@ type Properties struct {
 	CidrIp, CidrIpv6, IpProtocol string
 	FromPort, ToPort             int
 
+	GroupId, SourceSecurityGroupId, SourceSecurityGroupOwnerId interface{}
+
 	VpcId, SubnetId                            interface{}
 	RouteTableId, AllocationId                 interface{}
 	GatewayId, InternetGatewayId, NatGatewayId interface{}
 	DestinationCidrBlock                       interface{}
 
+	TransitGatewayId, VpcPeeringConnectionId interface{}
+	PeerVpcId, PeerOwnerId, PeerRoleArn      interface{}
+	SubnetIds                                []interface{}
+
+	ResourceId, LogGroupName, LogDestination, DeliverLogsPermissionArn interface{}
+	ResourceType, TrafficType, LogDestinationType                      string
+	MaxAggregationInterval                                             int
+
 	Ipv6CidrBlock map[string][]interface{}
 
 	AmazonProvidedIpv6CidrBlock         bool
 	AvailabilityZone, Domain, CidrBlock string
 
+	Namespace, MetricName string
+	Dimensions            []struct {
+		Name  string
+		Value interface{}
+	}
+	AlarmActions      []interface{}
+	MetricsCollection []struct {
+		Granularity string
+		Metrics     []string
+	}
+	Subscription []struct {
+		Protocol, Endpoint string
+	}
+
 	Name, Version      string
 	RoleArn            interface{}
 	ResourcesVpcConfig struct {
 		SecurityGroupIds []interface{}
 		SubnetIds        []interface{}
 	}
+	AccessConfig *struct {
+		AuthenticationMode string
+	}
+	KubernetesNetworkConfig *struct {
+		ServiceIpv4Cidr string
+		IpFamily        string
+	}
+	ControlPlaneScalingConfig *struct {
+		Tier string
+	}
 	MixedInstancesPolicy *struct {
 		LaunchTemplate struct {
 			LaunchTemplateSpecification struct {
@@ -128,11 +162,19 @@ type LaunchTemplateData struct {
 			MaxPrice         string
 		}
 	}
+	TagSpecifications []struct {
+		ResourceType string
+		Tags         []Tag
+	}
 }
 
 type Template struct {
 	Description string
-	Resources   map[string]struct{ Properties Properties }
+	Resources   map[string]struct {
+		Properties     Properties
+		DependsOn      []string
+		DeletionPolicy string
+	}
 }
 
 func kubeconfigBody(authenticator string) string {
@@ -612,6 +654,38 @@ var _ = Describe("CloudFormation template builder API", func() {
 		})
 	})
 
+	Context("NodeGroup launch template tags", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		cfg.Metadata.Tags = map[string]string{"team": "platform", "env": "dev"}
+		ng.InstanceType = "t2.medium"
+		ng.Name = "ng-abcd1234"
+		ng.Tags = map[string]string{"env": "prod"}
+
+		build(cfg, "eksctl-test-123-cluster", ng)
+
+		roundtrip()
+
+		It("should tag instances, volumes and network interfaces with the cluster and nodegroup tags", func() {
+			expectedTags := []Tag{
+				{Key: api.ClusterNameTag, Value: clusterName},
+				{Key: api.NodeGroupNameTag, Value: "ng-abcd1234"},
+				{Key: api.EKSClusterNameTag, Value: clusterName},
+				{Key: "env", Value: "prod"},
+				{Key: "team", Value: "platform"},
+			}
+
+			tagSpecifications := getLaunchTemplateData(ngTemplate).TagSpecifications
+			Expect(tagSpecifications).To(HaveLen(3))
+			Expect(tagSpecifications[0].ResourceType).To(Equal("instance"))
+			Expect(tagSpecifications[0].Tags).To(Equal(expectedTags))
+			Expect(tagSpecifications[1].ResourceType).To(Equal("volume"))
+			Expect(tagSpecifications[1].Tags).To(Equal(expectedTags))
+			Expect(tagSpecifications[2].ResourceType).To(Equal("network-interface"))
+			Expect(tagSpecifications[2].Tags).To(Equal(expectedTags))
+		})
+	})
+
 	Context("NodeGroup DesiredCapacity=nil MaxSize=nil MinSize=nil", func() {
 		cfg, ng := newClusterConfigAndNodegroup(true)
 
@@ -1297,6 +1371,95 @@ var _ = Describe("CloudFormation template builder API", func() {
 		})
 	})
 
+	Context("NodeGroupWindowsGMSA", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		ng.AMIFamily = api.NodeImageFamilyWindowsServer2019FullContainer
+		ng.GMSA = &api.NodeGroupGMSA{
+			DomainlessSecretARN:    "arn:aws:secretsmanager:us-west-2:123456789012:secret:gmsa-user-AbCdEf",
+			SecretKMSKeyARN:        "arn:aws:kms:us-west-2:123456789012:key/12345678-1234-1234-1234-123456789012",
+			DirectoryServiceAccess: api.Enabled(),
+		}
+
+		build(cfg, "eksctl-test-gmsa-cluster", ng)
+
+		roundtrip()
+
+		It("should allow reading the secret of the domain user", func() {
+			Expect(ngTemplate.Resources).To(HaveKey("PolicyGMSASecret"))
+			policy := ngTemplate.Resources["PolicyGMSASecret"].Properties
+			isRefTo(policy.Roles[0], "NodeInstanceRole")
+			Expect(policy.PolicyDocument.Statement[0].Resource).To(Equal(ng.GMSA.DomainlessSecretARN))
+			Expect(policy.PolicyDocument.Statement[0].Action).To(Equal([]string{"secretsmanager:GetSecretValue"}))
+
+			Expect(ngTemplate.Resources).To(HaveKey("PolicyGMSASecretKMS"))
+			policy = ngTemplate.Resources["PolicyGMSASecretKMS"].Properties
+			Expect(policy.PolicyDocument.Statement[0].Resource).To(Equal(ng.GMSA.SecretKMSKeyARN))
+			Expect(policy.PolicyDocument.Statement[0].Action).To(Equal([]string{"kms:Decrypt"}))
+		})
+
+		It("should allow joining the nodes to a Directory Service domain", func() {
+			role := ngTemplate.Resources["NodeInstanceRole"].Properties
+			Expect(role.ManagedPolicyArns).To(ConsistOf(makePolicyARNRef("AmazonEKSWorkerNodePolicy",
+				"AmazonEKS_CNI_Policy", "AmazonEC2ContainerRegistryReadOnly",
+				"AmazonSSMManagedInstanceCore", "AmazonSSMDirectoryServiceAccess")))
+		})
+	})
+
+	Context("Nodegroup in another account", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		cfg.Status.ARN = "arn:aws:eks:us-west-2:111122223333:cluster/" + clusterName
+		ng.Account = &api.NodeGroupAccount{
+			RoleARN: "arn:aws:iam::444455556666:role/eksctl-nodegroups",
+		}
+		ng.SecurityGroups.WithShared = api.Disabled()
+
+		build(cfg, "eksctl-test-cross-account-ng", ng)
+
+		roundtrip()
+
+		It("should use the resources of the shared VPC without importing them", func() {
+			templateBody, err := ngrs.RenderJSON()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(string(templateBody)).ToNot(ContainSubstring("Fn::ImportValue"))
+
+			Expect(ngTemplate.Resources["SG"].Properties.VpcId).To(Equal(vpcID))
+			Expect(ngTemplate.Resources["NodeGroup"].Properties.VPCZoneIdentifier).To(Equal([]interface{}{
+				"subnet-0ade11bad78dced9e", "subnet-0f98135715dfcf55f", "subnet-0e2e63ff1712bf6ef",
+			}))
+		})
+
+		It("should only add the rules of its own security group", func() {
+			ingress := ngTemplate.Resources["IngressInterCluster"].Properties
+			Expect(ingress.SourceSecurityGroupId).To(Equal(cfg.VPC.SecurityGroup))
+			Expect(ingress.SourceSecurityGroupOwnerId).To(Equal("111122223333"))
+
+			Expect(ngTemplate.Resources).ToNot(HaveKey("EgressInterCluster"))
+			Expect(ngTemplate.Resources).ToNot(HaveKey("EgressInterClusterAPI"))
+			Expect(ngTemplate.Resources).ToNot(HaveKey("IngressInterClusterCP"))
+			Expect(ngrs.Template().Outputs).To(HaveKey("SecurityGroup"))
+		})
+
+		It("should allow the nodes to reach the control plane from the account of the cluster", func() {
+			access := NewNodeGroupAccessResourceSet(cfg, ng, "sg-nodes")
+			Expect(access.AddAllResources()).To(Succeed())
+
+			accessTemplate := &Template{}
+			templateBody, err := access.RenderJSON()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(json.Unmarshal(templateBody, accessTemplate)).To(Succeed())
+
+			Expect(accessTemplate.Resources).To(HaveLen(3))
+			ingress := accessTemplate.Resources["IngressInterClusterCP"].Properties
+			Expect(ingress.GroupId).To(Equal(cfg.VPC.SecurityGroup))
+			Expect(ingress.SourceSecurityGroupId).To(Equal("sg-nodes"))
+			Expect(ingress.SourceSecurityGroupOwnerId).To(Equal("444455556666"))
+			Expect(ingress.FromPort).To(Equal(443))
+			Expect(accessTemplate.Resources["EgressInterCluster"].Properties.GroupId).To(Equal(cfg.VPC.SecurityGroup))
+		})
+	})
+
 	Context("NodeGroupEBS", func() {
 		cfg, ng := newClusterConfigAndNodegroup(true)
 
@@ -1516,6 +1679,27 @@ var _ = Describe("CloudFormation template builder API", func() {
 		})
 	})
 
+	Context("Nodegroup encrypted volume without VolumeSize", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		ng.VolumeSize = nil
+		ng.VolumeEncrypted = api.Enabled()
+
+		build(cfg, "eksctl-test-private-ng", ng)
+
+		roundtrip()
+
+		It("should encrypt the root volume with the size of the AMI", func() {
+			ltd := getLaunchTemplateData(ngTemplate)
+			Expect(ltd.BlockDeviceMappings).To(HaveLen(1))
+
+			rootVolume := ltd.BlockDeviceMappings[0].(map[string]interface{})
+			Expect(rootVolume).To(HaveKeyWithValue("DeviceName", "/dev/xvda"))
+			Expect(rootVolume["Ebs"].(map[string]interface{})).To(HaveKeyWithValue("Encrypted", true))
+			Expect(rootVolume["Ebs"].(map[string]interface{})).ToNot(HaveKey("VolumeSize"))
+		})
+	})
+
 	Context("Nodegroup{VolumeType=sc1 VolumeSize=2.0}", func() {
 		cfg, ng := newClusterConfigAndNodegroup(true)
 
@@ -1538,6 +1722,160 @@ var _ = Describe("CloudFormation template builder API", func() {
 		})
 	})
 
+	Context("Nodegroup{VolumeType=gp3} with additional volumes", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		var (
+			iops       = 4000
+			throughput = 250
+			dataSize   = 100
+		)
+		*ng.VolumeType = api.NodeVolumeTypeGP3
+		ng.VolumeIOPS = &iops
+		ng.VolumeThroughput = &throughput
+		ng.VolumeEncrypted = api.Enabled()
+		ng.AdditionalVolumes = []*api.VolumeMapping{{
+			Name:       "/dev/xvdb",
+			Size:       &dataSize,
+			Type:       aws.String(api.NodeVolumeTypeGP3),
+			Throughput: &throughput,
+			MountPath:  "/mnt/data",
+		}}
+
+		build(cfg, "eksctl-test-private-ng", ng)
+
+		roundtrip()
+
+		It("should have correct block device mappings", func() {
+			ltd := getLaunchTemplateData(ngTemplate)
+			Expect(ltd.BlockDeviceMappings).To(HaveLen(2))
+
+			rootVolume := ltd.BlockDeviceMappings[0].(map[string]interface{})
+			Expect(rootVolume).To(HaveKeyWithValue("DeviceName", "/dev/xvda"))
+			rootEBS := rootVolume["Ebs"].(map[string]interface{})
+			Expect(rootEBS).To(HaveKeyWithValue("VolumeType", "gp3"))
+			Expect(rootEBS).To(HaveKeyWithValue("Iops", 4000.0))
+			Expect(rootEBS).To(HaveKeyWithValue("Throughput", 250.0))
+
+			dataVolume := ltd.BlockDeviceMappings[1].(map[string]interface{})
+			Expect(dataVolume).To(HaveKeyWithValue("DeviceName", "/dev/xvdb"))
+			dataEBS := dataVolume["Ebs"].(map[string]interface{})
+			Expect(dataEBS).To(HaveKeyWithValue("VolumeSize", 100.0))
+			Expect(dataEBS).To(HaveKeyWithValue("VolumeType", "gp3"))
+			Expect(dataEBS).To(HaveKeyWithValue("Throughput", 250.0))
+			Expect(dataEBS).To(HaveKeyWithValue("Encrypted", true))
+			Expect(dataEBS).ToNot(HaveKey("Iops"))
+		})
+	})
+
+	Context("NodeTerminationHandler{Mode=queue}", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		cfg.NodeTerminationHandler = &api.NodeTerminationHandler{
+			Enabled: api.Enabled(),
+			Mode:    api.NodeTerminationHandlerModeQueue,
+		}
+
+		build(cfg, "eksctl-test-spot-ng", ng)
+
+		roundtrip()
+
+		It("should have the queue and the event rules in the cluster stack", func() {
+			Expect(clusterTemplate.Resources).To(HaveKey("NodeTerminationHandlerQueue"))
+			Expect(clusterTemplate.Resources).To(HaveKey("NodeTerminationHandlerQueuePolicy"))
+			Expect(clusterTemplate.Resources).To(HaveKey("NodeTerminationHandlerSpotInterruptionRule"))
+			Expect(clusterTemplate.Resources).To(HaveKey("NodeTerminationHandlerASGTerminateRule"))
+			Expect(crs.Template().Outputs).To(HaveKey("NodeTerminationHandlerQueueURL"))
+		})
+
+		It("should have the lifecycle hook and the managed tag in the nodegroup stack", func() {
+			Expect(ngTemplate.Resources).To(HaveKey("NodeGroupTerminationLifecycleHook"))
+			Expect(ngTemplate.Resources["NodeGroup"].Properties.Tags).To(ContainElement(Tag{
+				Key:               api.NodeTerminationHandlerManagedASGTag,
+				Value:             "true",
+				PropagateAtLaunch: "true",
+			}))
+		})
+	})
+
+	Context("Alarms{Enabled=true}", func() {
+		cfg, ng := newClusterConfigAndNodegroup(false)
+
+		threshold := 20
+		cfg.Alarms = &api.Alarms{
+			Enabled:                 api.Enabled(),
+			EmailSubscriptions:      []string{"oncall@example.com"},
+			APIServerErrorThreshold: &threshold,
+		}
+		cfg.CloudWatch = &api.ClusterCloudWatch{
+			ClusterLogging: &api.ClusterCloudWatchLogging{
+				EnableTypes: []string{"api", "audit"},
+			},
+		}
+
+		setSubnets(cfg)
+
+		build(cfg, "eksctl-test-alarms-cluster", ng)
+
+		roundtrip()
+
+		It("should have the topic and the alarms of the NAT gateway and the API server in the cluster stack", func() {
+			topic := clusterTemplate.Resources["AlarmsTopic"].Properties
+			Expect(topic.Subscription).To(HaveLen(1))
+			Expect(topic.Subscription[0].Protocol).To(Equal("email"))
+			Expect(topic.Subscription[0].Endpoint).To(Equal("oncall@example.com"))
+			Expect(crs.Template().Outputs).To(HaveKey("AlarmsTopicARN"))
+
+			natAlarm := clusterTemplate.Resources["NATGatewayPacketsDropAlarm0"].Properties
+			Expect(natAlarm.MetricName).To(Equal("PacketsDropCount"))
+			isRefTo(natAlarm.Dimensions[0].Value, "NATGateway")
+			isRefTo(natAlarm.AlarmActions[0], "AlarmsTopic")
+
+			apiAlarm := clusterTemplate.Resources["APIServerErrorsAlarm"].Properties
+			Expect(apiAlarm.Namespace).To(Equal("eksctl/" + cfg.Metadata.Name))
+			Expect(apiAlarm.MetricName).To(Equal("APIServerErrors"))
+		})
+
+		It("should collect the capacity metrics of the ASG and have its alarms in the nodegroup stack", func() {
+			Expect(ngTemplate.Resources["NodeGroup"].Properties.MetricsCollection).To(HaveLen(1))
+			Expect(ngTemplate.Resources["NodeGroup"].Properties.MetricsCollection[0].Metrics).To(ConsistOf("GroupDesiredCapacity", "GroupInServiceInstances"))
+			Expect(ngTemplate.Resources).To(HaveKey("NodeGroupCapacityAlarm"))
+
+			statusCheckAlarm := ngTemplate.Resources["NodeGroupStatusCheckAlarm"].Properties
+			Expect(statusCheckAlarm.MetricName).To(Equal("StatusCheckFailed"))
+			isRefTo(statusCheckAlarm.Dimensions[0].Value, "NodeGroup")
+			Expect(statusCheckAlarm.AlarmActions[0]).To(Equal(map[string]interface{}{
+				"Fn::ImportValue": "eksctl-test-alarms-cluster::AlarmsTopicARN",
+			}))
+		})
+	})
+
+	Context("Alarms{SNSTopicARN}", func() {
+		cfg, ng := newClusterConfigAndNodegroup(false)
+
+		cfg.Alarms = &api.Alarms{
+			Enabled:     api.Enabled(),
+			SNSTopicARN: "arn:aws:sns:us-west-2:123456789012:alarms",
+		}
+
+		setSubnets(cfg)
+
+		build(cfg, "eksctl-test-alarms-existing-topic-cluster", ng)
+
+		roundtrip()
+
+		It("should notify the existing topic without creating one", func() {
+			Expect(clusterTemplate.Resources).NotTo(HaveKey("AlarmsTopic"))
+			Expect(crs.Template().Outputs).NotTo(HaveKey("AlarmsTopicARN"))
+			Expect(clusterTemplate.Resources["NATGatewayPacketsDropAlarm0"].Properties.AlarmActions).To(ConsistOf("arn:aws:sns:us-west-2:123456789012:alarms"))
+			Expect(ngTemplate.Resources["NodeGroupStatusCheckAlarm"].Properties.AlarmActions).To(ConsistOf("arn:aws:sns:us-west-2:123456789012:alarms"))
+		})
+
+		It("should not have the API server alarm without the audit logs", func() {
+			Expect(clusterTemplate.Resources).NotTo(HaveKey("APIServerErrorsAlarm"))
+		})
+	})
+
 	Context("NodeGroup{PrivateNetworking=true SSH.Allow=true}", func() {
 		cfg, ng := newClusterConfigAndNodegroup(true)
 
@@ -2321,6 +2659,54 @@ var _ = Describe("CloudFormation template builder API", func() {
 		})
 	})
 
+	Context("with a customised Fargate pod execution role", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+		name := "test-fargate-role"
+		cfg.Metadata.Name = name
+		cfg.IAM.FargatePodExecutionRoleName = "fargate-pods"
+		cfg.IAM.FargatePodExecutionRolePolicyARNs = []string{"arn:aws:iam::123456789012:policy/fargate-secrets"}
+		cfg.FargateProfiles = []*api.FargateProfile{
+			{
+				Name:      "fp-default",
+				Selectors: []api.FargateProfileSelector{{Namespace: "default"}},
+			},
+		}
+		build(cfg, fmt.Sprintf("eksctl-%s-cluster", name), ng)
+		roundtrip()
+
+		It("should name the role and attach the policies", func() {
+			role := clusterTemplate.Resources["FargatePodExecutionRole"].Properties
+			Expect(role.RoleName).To(Equal("fargate-pods"))
+			Expect(role.ManagedPolicyArns).To(HaveLen(2))
+			Expect(role.ManagedPolicyArns[1]).To(Equal("arn:aws:iam::123456789012:policy/fargate-secrets"))
+			Expect(crs.WithNamedIAM()).To(BeTrue())
+		})
+	})
+
+	Context("with Fargate logging", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+		name := "test-fargate-logging"
+		cfg.Metadata.Name = name
+		cfg.FargateProfiles = []*api.FargateProfile{
+			{
+				Name:      "fp-default",
+				Selectors: []api.FargateProfileSelector{{Namespace: "default"}},
+				Logging:   &api.FargateLogging{Destination: api.FargateLoggingCloudWatch},
+			},
+		}
+		build(cfg, fmt.Sprintf("eksctl-%s-cluster", name), ng)
+		roundtrip()
+
+		It("should allow the Fargate pod execution role to write logs", func() {
+			Expect(clusterTemplate.Resources).To(HaveKey("PolicyFargateLogging"))
+			policy := clusterTemplate.Resources["PolicyFargateLogging"].Properties
+			Expect(policy.Roles).To(HaveLen(1))
+			isRefTo(policy.Roles[0], "FargatePodExecutionRole")
+			Expect(policy.PolicyDocument.Statement[0].Resource).To(Equal("*"))
+			Expect(policy.PolicyDocument.Statement[0].Action).To(ContainElement("logs:PutLogEvents"))
+		})
+	})
+
 	Context("without VPC and IAM", func() {
 		cfg, ng := newClusterConfigAndNodegroup(true)
 
@@ -2350,10 +2736,141 @@ var _ = Describe("CloudFormation template builder API", func() {
 			Expect(cp.ResourcesVpcConfig.SecurityGroupIds[0]).To(Equal(cfg.VPC.SecurityGroup))
 
 			Expect(cp.ResourcesVpcConfig.SubnetIds).To(HaveLen(6))
+			Expect(cp.AccessConfig).To(BeNil())
 		})
 
 	})
 
+	Context("with an authentication mode", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		cfg.Metadata.Name = "test-access-config"
+		cfg.AccessConfig = &api.AccessConfig{AuthenticationMode: api.AuthenticationModeAPIAndConfigMap}
+
+		build(cfg, "eksctl-test-access-config-cluster", ng)
+
+		roundtrip()
+
+		It("should set the access config of the control plane", func() {
+			cp := clusterTemplate.Resources["ControlPlane"].Properties
+			Expect(cp.AccessConfig).NotTo(BeNil())
+			Expect(cp.AccessConfig.AuthenticationMode).To(Equal(api.AuthenticationModeAPIAndConfigMap))
+			Expect(cp.KubernetesNetworkConfig).To(BeNil())
+		})
+	})
+
+	Context("with cluster settings", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		cfg.Metadata.Name = "test-cluster-settings"
+		cfg.ClusterSettings = api.InlineDocument{
+			"controlPlaneScalingConfig": map[string]interface{}{"tier": "tier-xl"},
+		}
+
+		build(cfg, "eksctl-test-cluster-settings-cluster", ng)
+
+		roundtrip()
+
+		It("should pass the settings through to the control plane", func() {
+			cp := clusterTemplate.Resources["ControlPlane"].Properties
+			Expect(cp.ControlPlaneScalingConfig).NotTo(BeNil())
+			Expect(cp.ControlPlaneScalingConfig.Tier).To(Equal("tier-xl"))
+			Expect(cp.Name).To(Equal("test-cluster-settings"))
+			Expect(cp.ResourcesVpcConfig.SubnetIds).NotTo(BeEmpty())
+		})
+	})
+
+	Context("with a service CIDR", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		cfg.Metadata.Name = "test-service-cidr"
+		cfg.KubernetesNetworkConfig = &api.KubernetesNetworkConfig{ServiceIPv4CIDR: "172.16.0.0/16"}
+
+		build(cfg, "eksctl-test-service-cidr-cluster", ng)
+
+		roundtrip()
+
+		It("should set the Kubernetes network config of the control plane", func() {
+			cp := clusterTemplate.Resources["ControlPlane"].Properties
+			Expect(cp.KubernetesNetworkConfig).NotTo(BeNil())
+			Expect(cp.KubernetesNetworkConfig.ServiceIpv4Cidr).To(Equal("172.16.0.0/16"))
+			Expect(cp.KubernetesNetworkConfig.IpFamily).To(BeEmpty())
+		})
+	})
+
+	Context("with the IPv6 IP family", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		cfg.Metadata.Name = "test-ipv6"
+		cfg.KubernetesNetworkConfig = &api.KubernetesNetworkConfig{IPFamily: api.IPV6Family}
+
+		build(cfg, "eksctl-test-ipv6-cluster", ng)
+
+		roundtrip()
+
+		It("should set the IP family of the control plane", func() {
+			cp := clusterTemplate.Resources["ControlPlane"].Properties
+			Expect(cp.KubernetesNetworkConfig).NotTo(BeNil())
+			Expect(cp.KubernetesNetworkConfig.IpFamily).To(Equal(api.IPV6Family))
+			Expect(cp.KubernetesNetworkConfig.ServiceIpv4Cidr).To(BeEmpty())
+		})
+	})
+
+	Context("with control plane subnets", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		cfg.Metadata.Name = "test-control-plane-subnets"
+		cfg.VPC.ControlPlaneSubnetIDs = []string{"subnet-cp1", "subnet-cp2"}
+
+		build(cfg, "eksctl-test-control-plane-subnets-cluster", ng)
+
+		roundtrip()
+
+		It("should only place the control plane in them", func() {
+			cp := clusterTemplate.Resources["ControlPlane"].Properties
+			Expect(cp.ResourcesVpcConfig.SubnetIds).To(Equal([]interface{}{"subnet-cp1", "subnet-cp2"}))
+		})
+	})
+
+	Context("with control plane security groups", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		cfg.Metadata.Name = "test-control-plane-security-groups"
+		cfg.VPC.SecurityGroupIDs = []string{"sg-cp1", "sg-cp2"}
+
+		build(cfg, "eksctl-test-control-plane-security-groups-cluster", ng)
+
+		roundtrip()
+
+		It("should pass them to the control plane instead of creating one", func() {
+			Expect(clusterTemplate.Resources).NotTo(HaveKey("ControlPlaneSecurityGroup"))
+			cp := clusterTemplate.Resources["ControlPlane"].Properties
+			Expect(cp.ResourcesVpcConfig.SecurityGroupIds).To(Equal([]interface{}{"sg-cp1", "sg-cp2"}))
+		})
+
+		It("should output the first one as the cluster security group", func() {
+			Expect(crs.Template().Outputs).To(HaveKey("SecurityGroup"))
+			Expect(crs.Template().Outputs["SecurityGroup"]).To(HaveKeyWithValue("Value", gfn.NewString("sg-cp1")))
+		})
+	})
+
+	Context("with security groups for pods", func() {
+		cfg, ng := newClusterConfigAndNodegroup(true)
+
+		cfg.Metadata.Name = "test-pod-security-groups"
+		cfg.VPC.PodSecurityGroups = &api.PodSecurityGroups{Enabled: api.Enabled()}
+
+		build(cfg, "eksctl-test-pod-security-groups-cluster", ng)
+
+		roundtrip()
+
+		It("should attach the VPC resource controller policy to the service role", func() {
+			Expect(clusterTemplate.Resources["ServiceRole"].Properties.ManagedPolicyArns).To(Equal(makePolicyARNRef(
+				"AmazonEKSServicePolicy", "AmazonEKSClusterPolicy", "AmazonEKSVPCResourceController",
+			)))
+		})
+	})
+
 	Context("without VPC", func() {
 		cfg, ng := newClusterConfigAndNodegroup(true)
 
@@ -2507,6 +3024,126 @@ var _ = Describe("CloudFormation template builder API", func() {
 		})
 	})
 
+	Context("VPC with a transit gateway and a peering connection", func() {
+		cfg, ng := newClusterConfigAndNodegroup(false)
+
+		cfg.Metadata.Name = "test-vpc-attachments"
+		cfg.VPC.TransitGatewayID = "tgw-1"
+		cfg.VPC.TransitGatewayCIDRs = []string{"10.0.0.0/8"}
+		cfg.VPC.TransitGatewayTags = map[string]string{"Name": "hub", "team": "network"}
+		cfg.VPC.Peering = []api.VPCPeering{{
+			PeerVPCID:        "vpc-shared",
+			PeerOwnerID:      "123456789012",
+			PeerRoleARN:      "arn:aws:iam::123456789012:role/peering",
+			DestinationCIDRs: []string{"172.16.0.0/16"},
+		}}
+
+		setSubnets(cfg)
+
+		build(cfg, "eksctl-test-vpc-attachments-cluster", ng)
+
+		roundtrip()
+
+		It("should attach the private subnets to the transit gateway", func() {
+			attachment := clusterTemplate.Resources["TransitGatewayAttachment"].Properties
+			Expect(attachment.TransitGatewayId).To(Equal("tgw-1"))
+			isRefTo(attachment.VpcId, "VPC")
+			Expect(attachment.SubnetIds).To(HaveLen(3))
+			for i, zone := range []string{"A", "B", "C"} {
+				isRefTo(attachment.SubnetIds[i], "SubnetPrivateUSWEST2"+zone)
+			}
+			Expect(attachment.Tags).To(HaveLen(2))
+			Expect(attachment.Tags[0].Key).To(Equal("Name"))
+			Expect(attachment.Tags[0].Value).To(Equal("hub"))
+		})
+
+		It("should peer the VPC", func() {
+			connection := clusterTemplate.Resources["VPCPeeringConnection0"].Properties
+			isRefTo(connection.VpcId, "VPC")
+			Expect(connection.PeerVpcId).To(Equal("vpc-shared"))
+			Expect(connection.PeerOwnerId).To(Equal("123456789012"))
+			Expect(connection.PeerRoleArn).To(Equal("arn:aws:iam::123456789012:role/peering"))
+		})
+
+		It("should route the destination CIDRs from all route tables", func() {
+			for _, alias := range []string{"Public", "PrivateUSWEST2A", "PrivateUSWEST2B", "PrivateUSWEST2C"} {
+				tgwRoute := clusterTemplate.Resources["TransitGatewayRoute"+alias+"0"]
+				Expect(tgwRoute.Properties.DestinationCidrBlock).To(Equal("10.0.0.0/8"))
+				Expect(tgwRoute.Properties.TransitGatewayId).To(Equal("tgw-1"))
+				Expect(tgwRoute.DependsOn).To(Equal([]string{"TransitGatewayAttachment"}))
+
+				peeringRoute := clusterTemplate.Resources["VPCPeering0Route"+alias+"0"].Properties
+				Expect(peeringRoute.DestinationCidrBlock).To(Equal("172.16.0.0/16"))
+				isRefTo(peeringRoute.VpcPeeringConnectionId, "VPCPeeringConnection0")
+			}
+			isRefTo(clusterTemplate.Resources["TransitGatewayRoutePublic0"].Properties.RouteTableId, "PublicRouteTable")
+			isRefTo(clusterTemplate.Resources["TransitGatewayRoutePrivateUSWEST2A0"].Properties.RouteTableId, "PrivateRouteTableUSWEST2A")
+		})
+	})
+
+	Context("VPC with flow logs", func() {
+		Context("published to CloudWatch Logs", func() {
+			cfg, ng := newClusterConfigAndNodegroup(false)
+
+			cfg.Metadata.Name = "test-flow-logs-cloudwatch"
+			aggregationInterval := 60
+			cfg.VPC.FlowLogs = &api.VPCFlowLogs{
+				Destination:         api.VPCFlowLogsDestinationCloudWatch,
+				TrafficType:         api.VPCFlowLogsTrafficTypeReject,
+				AggregationInterval: &aggregationInterval,
+			}
+
+			setSubnets(cfg)
+
+			build(cfg, "eksctl-test-flow-logs-cloudwatch-cluster", ng)
+
+			roundtrip()
+
+			It("should publish the flow logs of the VPC to a log group", func() {
+				flowLogs := clusterTemplate.Resources["FlowLogs"].Properties
+				isRefTo(flowLogs.ResourceId, "VPC")
+				Expect(flowLogs.ResourceType).To(Equal("VPC"))
+				Expect(flowLogs.TrafficType).To(Equal("REJECT"))
+				Expect(flowLogs.MaxAggregationInterval).To(Equal(60))
+				Expect(flowLogs.LogDestinationType).To(Equal("cloud-watch-logs"))
+				isRefTo(flowLogs.LogGroupName, "FlowLogsDestination")
+				isFnGetAttOf(flowLogs.DeliverLogsPermissionArn, "FlowLogsRole.Arn")
+				Expect(clusterTemplate.Resources).To(HaveKey("FlowLogsDestination"))
+			})
+
+			It("should allow the flow logs service to publish to the log group", func() {
+				checkARPD([]string{"VPCFlowLogs"}, clusterTemplate.Resources["FlowLogsRole"].Properties.AssumeRolePolicyDocument)
+				policy := clusterTemplate.Resources["PolicyFlowLogs"].Properties
+				isRefTo(policy.Roles[0], "FlowLogsRole")
+				isFnGetAttOf(policy.PolicyDocument.Statement[0].Resource, "FlowLogsDestination.Arn")
+			})
+		})
+
+		Context("delivered to S3", func() {
+			cfg, ng := newClusterConfigAndNodegroup(false)
+
+			cfg.Metadata.Name = "test-flow-logs-s3"
+			cfg.VPC.FlowLogs = &api.VPCFlowLogs{
+				Destination: api.VPCFlowLogsDestinationS3,
+				TrafficType: api.VPCFlowLogsTrafficTypeAll,
+			}
+
+			setSubnets(cfg)
+
+			build(cfg, "eksctl-test-flow-logs-s3-cluster", ng)
+
+			roundtrip()
+
+			It("should deliver the flow logs of the VPC to a retained bucket", func() {
+				flowLogs := clusterTemplate.Resources["FlowLogs"].Properties
+				Expect(flowLogs.LogDestinationType).To(Equal("s3"))
+				isFnGetAttOf(flowLogs.LogDestination, "FlowLogsDestination.Arn")
+				Expect(clusterTemplate.Resources["FlowLogsDestination"].DeletionPolicy).To(Equal("Retain"))
+				Expect(clusterTemplate.Resources).NotTo(HaveKey("FlowLogsRole"))
+			})
+		})
+	})
+
 	Context("VPC with custom CIDR and IPv6", func() {
 		cfg, ng := newClusterConfigAndNodegroup(false)
 
@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
 	gfn "github.com/awslabs/goformation/cloudformation"
@@ -23,6 +24,8 @@ type ClusterResourceSet struct {
 	vpc                  *gfn.Value
 	subnets              map[api.SubnetTopology][]*gfn.Value
 	securityGroups       []*gfn.Value
+	natGateways          []*gfn.Value
+	rawAPIOverrides      map[string]interface{}
 }
 
 // NewClusterResourceSet returns a resource set for the new cluster
@@ -76,6 +79,18 @@ func (c *ClusterResourceSet) AddAllResources() error {
 		c.addResourcesForFargate()
 	}
 
+	if c.spec.HasNodeTerminationHandlerQueue() {
+		c.addResourcesForNodeTerminationHandler()
+	}
+
+	if dedicatedVPC && c.spec.VPC.FlowLogs != nil {
+		c.addResourcesForVPCFlowLogs()
+	}
+
+	if c.spec.HasAlarms() {
+		c.addResourcesForAlarms()
+	}
+
 	c.rs.defineOutput(outputs.ClusterStackName, gfn.RefStackName, false, func(v string) error {
 		if c.spec.Status == nil {
 			c.spec.Status = &api.ClusterStatus{}
@@ -119,20 +134,73 @@ func (c *ClusterResourceSet) newResource(name string, resource interface{}) *gfn
 type awsEKSClusterKMS struct {
 	*awsEKSCluster   `json:",inline"`
 	EncryptionConfig []*encryptionConfig `json:"EncryptionConfig,omitempty"`
+	AccessConfig     *accessConfig       `json:"AccessConfig,omitempty"`
+
+	KubernetesNetworkConfig *kubernetesNetworkConfig `json:"KubernetesNetworkConfig,omitempty"`
+
+	// settings are the clusterSettings passed through to the control plane
+	settings api.InlineDocument
+	// overrides are the raw API overrides of the CreateCluster request
+	overrides map[string]interface{}
 }
 
 func (e *awsEKSClusterKMS) MarshalJSON() ([]byte, error) {
 	type Properties awsEKSClusterKMS
+	var properties interface{} = Properties(*e)
+	if len(e.settings) > 0 || len(e.overrides) > 0 {
+		merged, err := toProperties(properties)
+		if err != nil {
+			return nil, err
+		}
+		withClusterSettings(merged, e.settings)
+		withRawAPIOverrides(merged, e.overrides)
+		properties = merged
+	}
 	val, err := json.Marshal(&struct {
 		Type       string
-		Properties Properties
+		Properties interface{}
 	}{
 		Type:       "AWS::EKS::Cluster",
-		Properties: Properties(*e),
+		Properties: properties,
 	})
 	return val, err
 }
 
+// withClusterSettings adds the clusterSettings to the properties of the control plane; the
+// properties of AWS::EKS::Cluster are named like the fields of the EKS API, starting with an
+// uppercase letter
+func withClusterSettings(properties map[string]interface{}, settings api.InlineDocument) {
+	for key, value := range settings {
+		properties[toPropertyName(key)] = toPropertyValue(value)
+	}
+}
+
+func toPropertyName(field string) string {
+	if field == "" {
+		return field
+	}
+	return strings.ToUpper(field[:1]) + field[1:]
+}
+
+func toPropertyValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		properties := make(map[string]interface{}, len(value))
+		for key, v := range value {
+			properties[toPropertyName(key)] = toPropertyValue(v)
+		}
+		return properties
+	case []interface{}:
+		values := make([]interface{}, len(value))
+		for i, v := range value {
+			values[i] = toPropertyValue(v)
+		}
+		return values
+	default:
+		return value
+	}
+}
+
 type encryptionProvider struct {
 	KeyArn string `json:"KeyArn"`
 }
@@ -142,14 +210,29 @@ type encryptionConfig struct {
 	Resources []string            `json:"Resources"`
 }
 
+type accessConfig struct {
+	AuthenticationMode string `json:"AuthenticationMode"`
+}
+
+type kubernetesNetworkConfig struct {
+	ServiceIpv4Cidr string `json:"ServiceIpv4Cidr,omitempty"`
+	IpFamily        string `json:"IpFamily,omitempty"`
+}
+
 type awsEKSCluster gfn.AWSEKSCluster
 
 func (c *ClusterResourceSet) addResourcesForControlPlane() {
 	clusterVPC := &gfn.AWSEKSCluster_ResourcesVpcConfig{
 		SecurityGroupIds: c.securityGroups,
 	}
-	for topology := range c.subnets {
-		clusterVPC.SubnetIds = append(clusterVPC.SubnetIds, c.subnets[topology]...)
+	if len(c.spec.VPC.ControlPlaneSubnetIDs) > 0 {
+		for _, subnetID := range c.spec.VPC.ControlPlaneSubnetIDs {
+			clusterVPC.SubnetIds = append(clusterVPC.SubnetIds, gfn.NewString(subnetID))
+		}
+	} else {
+		for topology := range c.subnets {
+			clusterVPC.SubnetIds = append(clusterVPC.SubnetIds, c.subnets[topology]...)
+		}
 	}
 
 	serviceRoleARN := gfn.MakeFnGetAttString("ServiceRole.Arn")
@@ -169,6 +252,19 @@ func (c *ClusterResourceSet) addResourcesForControlPlane() {
 		}
 	}
 
+	var clusterAccessConfig *accessConfig
+	if c.spec.HasAuthenticationMode() {
+		clusterAccessConfig = &accessConfig{AuthenticationMode: c.spec.AccessConfig.AuthenticationMode}
+	}
+
+	var clusterNetworkConfig *kubernetesNetworkConfig
+	if c.spec.HasServiceIPv4CIDR() || c.spec.IPv6Enabled() {
+		clusterNetworkConfig = &kubernetesNetworkConfig{
+			ServiceIpv4Cidr: c.spec.KubernetesNetworkConfig.ServiceIPv4CIDR,
+			IpFamily:        c.spec.KubernetesNetworkConfig.IPFamily,
+		}
+	}
+
 	c.newResource("ControlPlane", &awsEKSClusterKMS{
 		awsEKSCluster: &awsEKSCluster{
 			Name:               gfn.NewString(c.spec.Metadata.Name),
@@ -176,7 +272,11 @@ func (c *ClusterResourceSet) addResourcesForControlPlane() {
 			Version:            gfn.NewString(c.spec.Metadata.Version),
 			ResourcesVpcConfig: clusterVPC,
 		},
-		EncryptionConfig: encryptionConfigs,
+		EncryptionConfig:        encryptionConfigs,
+		AccessConfig:            clusterAccessConfig,
+		KubernetesNetworkConfig: clusterNetworkConfig,
+		settings:                c.spec.ClusterSettings,
+		overrides:               c.rawAPIOverrides,
 	})
 
 	if c.spec.Status == nil {
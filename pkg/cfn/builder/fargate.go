@@ -39,11 +39,45 @@ func AddResourcesForFargate(rs *resourceSet, cfg *api.ClusterConfig) error {
 	if api.IsSetAndNonEmptyString(cfg.IAM.FargatePodExecutionRolePermissionsBoundary) {
 		role.PermissionsBoundary = gfn.NewString(*cfg.IAM.FargatePodExecutionRolePermissionsBoundary)
 	}
+	if cfg.IAM.FargatePodExecutionRoleName != "" {
+		role.RoleName = gfn.NewString(cfg.IAM.FargatePodExecutionRoleName)
+		rs.withNamedIAM = true
+	}
+	for _, policyARN := range cfg.IAM.FargatePodExecutionRolePolicyARNs {
+		role.ManagedPolicyArns = append(role.ManagedPolicyArns, gfn.NewString(policyARN))
+	}
 
-	rs.newResource(fargateRoleName, role)
+	refRole := rs.newResource(fargateRoleName, role)
+	if logging := cfg.FargateLogging(); logging != nil {
+		attachFargateLoggingPolicy(rs, refRole, logging)
+	}
 	rs.defineOutputFromAtt(outputs.FargatePodExecutionRoleARN, fmt.Sprintf("%s.Arn", fargateRoleName), true, func(v string) error {
 		cfg.IAM.FargatePodExecutionRoleARN = &v
 		return nil
 	})
 	return nil
 }
+
+// attachFargateLoggingPolicy allows the Fluent Bit log router of Fargate pods to write to the
+// destination of their logs
+func attachFargateLoggingPolicy(rs *resourceSet, refRole *gfn.Value, logging *api.FargateLogging) {
+	switch logging.Destination {
+	case api.FargateLoggingCloudWatch:
+		rs.attachAllowPolicy("PolicyFargateLogging", refRole, "*", []string{
+			"logs:CreateLogGroup",
+			"logs:CreateLogStream",
+			"logs:DescribeLogStreams",
+			"logs:PutLogEvents",
+		})
+	case api.FargateLoggingFirehose:
+		rs.attachAllowPolicy("PolicyFargateLogging", refRole,
+			addARNPartitionPrefix(fmt.Sprintf("firehose:${AWS::Region}:${AWS::AccountId}:deliverystream/%s", logging.DeliveryStream)),
+			[]string{"firehose:PutRecordBatch"},
+		)
+	case api.FargateLoggingOpenSearch:
+		rs.attachAllowPolicy("PolicyFargateLogging", refRole, logging.DomainARN+"/*", []string{
+			"es:ESHttpPost",
+			"es:ESHttpPut",
+		})
+	}
+}
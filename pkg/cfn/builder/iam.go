@@ -14,8 +14,9 @@ import (
 )
 
 const (
-	iamPolicyAmazonEKSServicePolicy = "AmazonEKSServicePolicy"
-	iamPolicyAmazonEKSClusterPolicy = "AmazonEKSClusterPolicy"
+	iamPolicyAmazonEKSServicePolicy         = "AmazonEKSServicePolicy"
+	iamPolicyAmazonEKSClusterPolicy         = "AmazonEKSClusterPolicy"
+	iamPolicyAmazonEKSVPCResourceController = "AmazonEKSVPCResourceController"
 
 	iamPolicyAmazonEKSWorkerNodePolicy           = "AmazonEKSWorkerNodePolicy"
 	iamPolicyAmazonEKSCNIPolicy                  = "AmazonEKS_CNI_Policy"
@@ -83,6 +84,10 @@ func (c *ClusterResourceSet) addResourcesForIAM() {
 			iamPolicyAmazonEKSClusterPolicy,
 		),
 	}
+	if c.spec.HasPodSecurityGroups() {
+		// the VPC resource controller manages the branch network interfaces of pods
+		role.ManagedPolicyArns = append(role.ManagedPolicyArns, makePolicyARNs(iamPolicyAmazonEKSVPCResourceController)...)
+	}
 	if api.IsSetAndNonEmptyString(c.spec.IAM.ServiceRolePermissionsBoundary) {
 		role.PermissionsBoundary = gfn.NewString(*c.spec.IAM.ServiceRolePermissionsBoundary)
 	}
@@ -160,6 +165,9 @@ func (n *NodeGroupResourceSet) addResourcesForIAM() error {
 	if err := createRole(n.rs, n.spec.IAM, false); err != nil {
 		return err
 	}
+	if n.spec.GMSA != nil {
+		n.addResourcesForWindowsGMSA()
+	}
 
 	n.newResource(cfnIAMInstanceProfileName, &gfn.AWSIAMInstanceProfile{
 		Path:  gfn.NewString("/"),
@@ -180,26 +188,28 @@ func (n *NodeGroupResourceSet) addResourcesForIAM() error {
 
 // IAMServiceAccountResourceSet holds iamserviceaccount stack build-time information
 type IAMServiceAccountResourceSet struct {
-	template *cft.Template
-	spec     *api.ClusterIAMServiceAccount
-	oidc     *iamoidc.OpenIDConnectManager
-	outputs  *outputs.CollectorSet
+	template    *cft.Template
+	spec        *api.ClusterIAMServiceAccount
+	clusterName string
+	oidc        *iamoidc.OpenIDConnectManager
+	outputs     *outputs.CollectorSet
 }
 
 // NewIAMServiceAccountResourceSet builds iamserviceaccount stack from the give spec
-func NewIAMServiceAccountResourceSet(spec *api.ClusterIAMServiceAccount, oidc *iamoidc.OpenIDConnectManager) *IAMServiceAccountResourceSet {
+func NewIAMServiceAccountResourceSet(spec *api.ClusterIAMServiceAccount, clusterName string, oidc *iamoidc.OpenIDConnectManager) *IAMServiceAccountResourceSet {
 	return &IAMServiceAccountResourceSet{
-		template: cft.NewTemplate(),
-		spec:     spec,
-		oidc:     oidc,
+		template:    cft.NewTemplate(),
+		spec:        spec,
+		clusterName: clusterName,
+		oidc:        oidc,
 	}
 }
 
 // WithIAM returns true
 func (*IAMServiceAccountResourceSet) WithIAM() bool { return true }
 
-// WithNamedIAM returns false
-func (*IAMServiceAccountResourceSet) WithNamedIAM() bool { return false }
+// WithNamedIAM returns true when the role is named
+func (rs *IAMServiceAccountResourceSet) WithNamedIAM() bool { return rs.spec.RoleName != "" }
 
 // AddAllResources adds all resources for the stack
 func (rs *IAMServiceAccountResourceSet) AddAllResources() error {
@@ -213,7 +223,12 @@ func (rs *IAMServiceAccountResourceSet) AddAllResources() error {
 	// so will need to give them unique names
 	// we will need to consider using a large stack for all the roles, but that needs some
 	// testing and potentially a better stack mutation strategy
+	roleName, err := rs.spec.RenderRoleName(rs.clusterName)
+	if err != nil {
+		return err
+	}
 	role := &cft.IAMRole{
+		RoleName:                 roleName,
 		AssumeRolePolicyDocument: rs.oidc.MakeAssumeRolePolicyDocument(rs.spec.Namespace, rs.spec.Name),
 		PermissionsBoundary:      rs.spec.PermissionsBoundary,
 	}
@@ -41,7 +41,7 @@ var _ = Describe("template builder for IAM", func() {
 
 		appendServiceAccountToClusterConfig(cfg, serviceAccount)
 
-		rs := NewIAMServiceAccountResourceSet(serviceAccount, oidc)
+		rs := NewIAMServiceAccountResourceSet(serviceAccount, cfg.Metadata.Name, oidc)
 
 		templateBody := []byte{}
 
@@ -83,7 +83,7 @@ var _ = Describe("template builder for IAM", func() {
 
 		appendServiceAccountToClusterConfig(cfg, serviceAccount)
 
-		rs := NewIAMServiceAccountResourceSet(serviceAccount, oidc)
+		rs := NewIAMServiceAccountResourceSet(serviceAccount, cfg.Metadata.Name, oidc)
 
 		templateBody := []byte{}
 
@@ -143,7 +143,7 @@ var _ = Describe("template builder for IAM", func() {
 
 		appendServiceAccountToClusterConfig(cfg, serviceAccount)
 
-		rs := NewIAMServiceAccountResourceSet(serviceAccount, oidc)
+		rs := NewIAMServiceAccountResourceSet(serviceAccount, cfg.Metadata.Name, oidc)
 
 		templateBody := []byte{}
 
@@ -195,7 +195,7 @@ var _ = Describe("template builder for IAM", func() {
 
 		appendServiceAccountToClusterConfig(cfg, serviceAccount)
 
-		rs := NewIAMServiceAccountResourceSet(serviceAccount, oidc)
+		rs := NewIAMServiceAccountResourceSet(serviceAccount, cfg.Metadata.Name, oidc)
 
 		templateBody := []byte{}
 
@@ -221,6 +221,32 @@ var _ = Describe("template builder for IAM", func() {
 		Expect(t).To(HaveOutputWithValue("Role1", `{ "Fn::GetAtt": "Role1.Arn" }`))
 	})
 
+	It("can construct an iamserviceaccount addon template with a named role", func() {
+		serviceAccount := &api.ClusterIAMServiceAccount{}
+
+		serviceAccount.Name = "sa-1"
+		serviceAccount.Namespace = "ns-1"
+
+		serviceAccount.AttachPolicyARNs = []string{"arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"}
+
+		serviceAccount.RoleName = "{ClusterName}-{Namespace}-{Name}"
+
+		appendServiceAccountToClusterConfig(cfg, serviceAccount)
+
+		rs := NewIAMServiceAccountResourceSet(serviceAccount, "cluster-1", oidc)
+
+		templateBody := []byte{}
+
+		Expect(rs).To(RenderWithoutErrors(&templateBody))
+		Expect(rs.WithNamedIAM()).To(BeTrue())
+
+		t := cft.NewTemplate()
+
+		Expect(t).To(LoadBytesWithoutErrors(templateBody))
+
+		Expect(t).To(HaveResourceWithPropertyValue("Role1", "RoleName", `"cluster-1-ns-1-sa-1"`))
+	})
+
 	It("can parse an iamserviceaccount addon template", func() {
 		t := cft.NewTemplate()
 
@@ -0,0 +1,6 @@
+package builder
+
+import log "github.com/weaveworks/eksctl/pkg/logger"
+
+// logger logs messages of the cfn subsystem
+var logger = log.WithScope("cfn")
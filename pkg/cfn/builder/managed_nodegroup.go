@@ -8,14 +8,21 @@ import (
 	"github.com/aws/aws-sdk-go/service/eks"
 	gfn "github.com/awslabs/goformation/cloudformation"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
+	"github.com/weaveworks/eksctl/pkg/nodebootstrap"
 	"github.com/weaveworks/eksctl/pkg/utils"
 )
 
+// amiTypeAL2ARM64 is the AMI type of managed nodegroups with ARM instances, which isn't defined
+// by the version of the EKS API in use
+const amiTypeAL2ARM64 = "AL2_ARM_64"
+
 // ManagedNodeGroupResourceSet defines the CloudFormation resources required for a managed nodegroup
 type ManagedNodeGroupResourceSet struct {
 	clusterConfig    *api.ClusterConfig
 	clusterStackName string
 	nodeGroup        *api.ManagedNodeGroup
+	rawAPIOverrides  map[string]interface{}
 	*resourceSet
 }
 
@@ -23,17 +30,26 @@ type ManagedNodeGroupResourceSet struct {
 // Rather than setting all field types to *gfn.Value, the types are conveniently chosen
 // to allow using values without requiring any conversion
 type managedNodeGroup struct {
-	ClusterName   string              `json:"ClusterName"`
-	NodegroupName string              `json:"NodegroupName"`
-	ScalingConfig *scalingConfig      `json:"ScalingConfig,omitempty"`
-	DiskSize      int                 `json:"DiskSize,omitempty"` // 0 is not a valid value
-	Subnets       interface{}         `json:"Subnets"`
-	InstanceTypes []string            `json:"InstanceTypes"`
-	AmiType       string              `json:"AmiType,omitempty"`
-	RemoteAccess  *remoteAccessConfig `json:"RemoteAccess,omitempty"`
-	NodeRole      *gfn.Value          `json:"NodeRole"`
-	Labels        map[string]string   `json:"Labels,omitempty"`
-	Tags          map[string]string   `json:"Tags,omitempty"`
+	ClusterName    string              `json:"ClusterName"`
+	NodegroupName  string              `json:"NodegroupName"`
+	ScalingConfig  *scalingConfig      `json:"ScalingConfig,omitempty"`
+	DiskSize       int                 `json:"DiskSize,omitempty"` // 0 is not a valid value
+	Subnets        interface{}         `json:"Subnets"`
+	InstanceTypes  []string            `json:"InstanceTypes"`
+	AmiType        string              `json:"AmiType,omitempty"`
+	RemoteAccess   *remoteAccessConfig `json:"RemoteAccess,omitempty"`
+	NodeRole       *gfn.Value          `json:"NodeRole"`
+	Labels         map[string]string   `json:"Labels,omitempty"`
+	Tags           map[string]string   `json:"Tags,omitempty"`
+	LaunchTemplate *launchTemplate     `json:"LaunchTemplate,omitempty"`
+
+	// overrides are the raw API overrides of the CreateNodegroup request
+	overrides map[string]interface{}
+}
+
+type launchTemplate struct {
+	ID      *gfn.Value `json:"Id"`
+	Version *gfn.Value `json:"Version"`
 }
 
 type scalingConfig struct {
@@ -52,14 +68,22 @@ type remoteAccessConfig struct {
 // MarshalJSON returns the JSON encoding for this CloudFormation resource
 func (e *managedNodeGroup) MarshalJSON() ([]byte, error) {
 	type Properties managedNodeGroup
+	var properties interface{} = Properties(*e)
+	if len(e.overrides) > 0 {
+		merged, err := toProperties(properties)
+		if err != nil {
+			return nil, err
+		}
+		withRawAPIOverrides(merged, e.overrides)
+		properties = merged
+	}
 	return json.Marshal(&struct {
 		Type       string
-		Properties Properties
+		Properties interface{}
 	}{
 		Type:       "AWS::EKS::Nodegroup",
-		Properties: Properties(*e),
+		Properties: properties,
 	})
-
 }
 
 // NewManagedNodeGroup creates a new ManagedNodeGroupResourceSet
@@ -81,6 +105,7 @@ func (m *ManagedNodeGroupResourceSet) AddAllResources() error {
 		"[created by eksctl]")
 
 	m.template.Mappings[servicePrincipalPartitionMapName] = servicePrincipalPartitionMappings
+	m.addInstanceSelectorMetadata(m.nodeGroup.InstanceSelector, []string{m.nodeGroup.InstanceType})
 
 	var nodeRole *gfn.Value
 	if m.nodeGroup.IAM.InstanceRoleARN == "" {
@@ -111,28 +136,130 @@ func (m *ManagedNodeGroupResourceSet) AddAllResources() error {
 		AmiType:       getAMIType(m.nodeGroup.InstanceType),
 		NodeRole:      nodeRole,
 		Labels:        m.nodeGroup.Labels,
-		Tags:          m.nodeGroup.Tags,
+		Tags:          makeResourceTags(m.clusterConfig, m.nodeGroup.Name, m.nodeGroup.Tags),
+		overrides:     m.rawAPIOverrides,
+	}
+
+	if m.nodeGroup.RequiresLaunchTemplate() {
+		// EKS rejects remote access and disk size settings on nodegroups that use a launch template,
+		// so these are configured in the launch template instead
+		if err := m.addLaunchTemplate(managedResource); err != nil {
+			return err
+		}
+	} else {
+		if api.IsEnabled(m.nodeGroup.SSH.Allow) {
+			managedResource.RemoteAccess = &remoteAccessConfig{
+				Ec2SshKey:            m.nodeGroup.SSH.PublicKeyName,
+				SourceSecurityGroups: aws.StringSlice(m.nodeGroup.SSH.SourceSecurityGroupIDs),
+			}
+		}
+		if m.nodeGroup.VolumeSize != nil {
+			managedResource.DiskSize = *m.nodeGroup.VolumeSize
+		}
+	}
+
+	m.newResource("ManagedNodeGroup", managedResource)
+
+	return nil
+}
+
+func (m *ManagedNodeGroupResourceSet) addLaunchTemplate(managedResource *managedNodeGroup) error {
+	userData, err := nodebootstrap.NewUserDataForManagedNodeGroup(m.nodeGroup)
+	if err != nil {
+		return err
+	}
+
+	launchTemplateData := &gfn.AWSEC2LaunchTemplate_LaunchTemplateData{
+		UserData: gfn.NewString(userData),
 	}
 
 	if api.IsEnabled(m.nodeGroup.SSH.Allow) {
-		managedResource.RemoteAccess = &remoteAccessConfig{
-			Ec2SshKey:            m.nodeGroup.SSH.PublicKeyName,
-			SourceSecurityGroups: aws.StringSlice(m.nodeGroup.SSH.SourceSecurityGroupIDs),
+		if api.IsSetAndNonEmptyString(m.nodeGroup.SSH.PublicKeyName) {
+			launchTemplateData.KeyName = gfn.NewString(*m.nodeGroup.SSH.PublicKeyName)
+		}
+		// EKS doesn't open the SSH port of nodes using a launch template, and only attaches the
+		// cluster security group when the launch template sets none, so both are set here
+		launchTemplateData.SecurityGroupIds = []*gfn.Value{
+			makeImportValue(m.clusterStackName, outputs.ClusterDefaultSecurityGroup),
+			m.addSSHSecurityGroup(),
 		}
 	}
-	if m.nodeGroup.VolumeSize != nil {
-		managedResource.DiskSize = *m.nodeGroup.VolumeSize
+
+	volumeSize := m.nodeGroup.VolumeSize
+	if (volumeSize != nil && *volumeSize > 0) || api.IsEnabled(m.nodeGroup.VolumeEncrypted) {
+		rootVolume := &gfn.AWSEC2LaunchTemplate_Ebs{}
+		if volumeSize != nil && *volumeSize > 0 {
+			rootVolume.VolumeSize = gfn.NewInteger(*volumeSize)
+		}
+		if api.IsEnabled(m.nodeGroup.VolumeEncrypted) {
+			rootVolume.Encrypted = gfn.True()
+			if api.IsSetAndNonEmptyString(m.nodeGroup.VolumeKmsKeyID) {
+				rootVolume.KmsKeyId = gfn.NewString(*m.nodeGroup.VolumeKmsKeyID)
+			}
+		}
+		launchTemplateData.BlockDeviceMappings = []gfn.AWSEC2LaunchTemplate_BlockDeviceMapping{{
+			DeviceName: gfn.NewString("/dev/xvda"),
+			Ebs:        rootVolume,
+		}}
 	}
 
-	m.newResource("ManagedNodeGroup", managedResource)
+	launchTemplateRef := m.newResource("LaunchTemplate", &gfn.AWSEC2LaunchTemplate{
+		LaunchTemplateName: gfn.MakeFnSubString(fmt.Sprintf("${%s}", gfn.StackName)),
+		LaunchTemplateData: launchTemplateData,
+	})
 
+	managedResource.LaunchTemplate = &launchTemplate{
+		ID:      launchTemplateRef,
+		Version: gfn.MakeFnGetAttString("LaunchTemplate.LatestVersionNumber"),
+	}
 	return nil
 }
 
+// addSSHSecurityGroup adds a security group allowing SSH access to the nodes, from inside the VPC
+// only with private networking, and returns a reference to it
+func (m *ManagedNodeGroupResourceSet) addSSHSecurityGroup() *gfn.Value {
+	desc := fmt.Sprintf("managed worker nodes in group %s", m.nodeGroup.Name)
+	refSSHSG := m.newResource("SSH", &gfn.AWSEC2SecurityGroup{
+		VpcId:            makeImportValue(m.clusterStackName, outputs.ClusterVPC),
+		GroupDescription: gfn.NewString("Allow SSH access to " + desc),
+	})
+	if m.nodeGroup.PrivateNetworking {
+		m.newResource("SSHIPv4", &gfn.AWSEC2SecurityGroupIngress{
+			GroupId:     refSSHSG,
+			CidrIp:      gfn.NewString(m.clusterConfig.VPC.CIDR.String()),
+			Description: gfn.NewString("Allow SSH access to " + desc + " (private, only inside VPC)"),
+			IpProtocol:  sgProtoTCP,
+			FromPort:    sgPortSSH,
+			ToPort:      sgPortSSH,
+		})
+		return refSSHSG
+	}
+	m.newResource("SSHIPv4", &gfn.AWSEC2SecurityGroupIngress{
+		GroupId:     refSSHSG,
+		CidrIp:      sgSourceAnywhereIPv4,
+		Description: gfn.NewString("Allow SSH access to " + desc),
+		IpProtocol:  sgProtoTCP,
+		FromPort:    sgPortSSH,
+		ToPort:      sgPortSSH,
+	})
+	m.newResource("SSHIPv6", &gfn.AWSEC2SecurityGroupIngress{
+		GroupId:     refSSHSG,
+		CidrIpv6:    sgSourceAnywhereIPv6,
+		Description: gfn.NewString("Allow SSH access to " + desc),
+		IpProtocol:  sgProtoTCP,
+		FromPort:    sgPortSSH,
+		ToPort:      sgPortSSH,
+	})
+	return refSSHSG
+}
+
 func getAMIType(instanceType string) string {
 	if utils.IsGPUInstanceType(instanceType) {
 		return eks.AMITypesAl2X8664Gpu
 	}
+	if utils.IsARMInstanceType(instanceType) {
+		return amiTypeAL2ARM64
+	}
 	return eks.AMITypesAl2X8664
 }
 
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/awslabs/goformation/v4"
 	"github.com/stretchr/testify/assert"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
@@ -164,3 +165,89 @@ func prefixPolicies(policies ...string) []string {
 	}
 	return prefixedPolicies
 }
+
+func TestManagedNodeGroupLaunchTemplate(t *testing.T) {
+	ng := api.NewManagedNodeGroup()
+	ng.Name = "ng-kubelet"
+	ng.MaxPodsPerNode = 20
+	ng.KubeletExtraConfig = &api.InlineDocument{
+		"evictionHard": map[string]interface{}{
+			"memory.available": "200Mi",
+		},
+	}
+	volumeSize := 50
+	ng.VolumeSize = &volumeSize
+
+	stack := NewManagedNodeGroup(api.NewClusterConfig(), ng, "lt-test")
+	assert.NoError(t, stack.AddAllResources())
+
+	bytes, err := stack.RenderJSON()
+	assert.NoError(t, err)
+
+	template, err := goformation.ParseJSON(bytes)
+	assert.NoError(t, err)
+
+	launchTemplate, ok := template.GetAllEC2LaunchTemplateResources()["LaunchTemplate"]
+	assert.True(t, ok)
+	assert.NotEmpty(t, launchTemplate.LaunchTemplateData.UserData)
+	assert.Len(t, launchTemplate.LaunchTemplateData.BlockDeviceMappings, 1)
+
+	assert.Contains(t, string(bytes), `"LaunchTemplate":{"Id":{"Ref":"LaunchTemplate"}`)
+	assert.NotContains(t, string(bytes), `"DiskSize"`)
+}
+
+func TestManagedNodeGroupLaunchTemplateSSH(t *testing.T) {
+	ng := api.NewManagedNodeGroup()
+	ng.Name = "ng-kubelet"
+	ng.MaxPodsPerNode = 20
+	ng.SSH.Allow = api.Enabled()
+	ng.SSH.PublicKeyName = aws.String("my-key")
+
+	stack := NewManagedNodeGroup(api.NewClusterConfig(), ng, "lt-test")
+	assert.NoError(t, stack.AddAllResources())
+
+	bytes, err := stack.RenderJSON()
+	assert.NoError(t, err)
+
+	template, err := goformation.ParseJSON(bytes)
+	assert.NoError(t, err)
+
+	launchTemplate, ok := template.GetAllEC2LaunchTemplateResources()["LaunchTemplate"]
+	assert.True(t, ok)
+	assert.Equal(t, "my-key", launchTemplate.LaunchTemplateData.KeyName)
+	assert.Contains(t, string(bytes), `"SecurityGroupIds":[{"Fn::ImportValue":"lt-test::ClusterSecurityGroupId"},{"Ref":"SSH"}]`)
+
+	_, ok = template.GetAllEC2SecurityGroupResources()["SSH"]
+	assert.True(t, ok)
+	ingress, ok := template.GetAllEC2SecurityGroupIngressResources()["SSHIPv4"]
+	assert.True(t, ok)
+	assert.Equal(t, "0.0.0.0/0", ingress.CidrIp)
+	assert.Equal(t, 22, ingress.FromPort)
+	assert.Equal(t, 22, ingress.ToPort)
+	assert.NotContains(t, string(bytes), `"RemoteAccess"`)
+}
+
+func TestManagedNodeGroupLaunchTemplateVolumeEncryption(t *testing.T) {
+	ng := api.NewManagedNodeGroup()
+	ng.Name = "ng-encrypted"
+	ng.VolumeEncrypted = api.Enabled()
+	ng.VolumeKmsKeyID = aws.String("36c0b54e-64ed-4f2d-a1c7-96558764311e")
+
+	stack := NewManagedNodeGroup(api.NewClusterConfig(), ng, "lt-test")
+	assert.NoError(t, stack.AddAllResources())
+
+	bytes, err := stack.RenderJSON()
+	assert.NoError(t, err)
+
+	template, err := goformation.ParseJSON(bytes)
+	assert.NoError(t, err)
+
+	launchTemplate, ok := template.GetAllEC2LaunchTemplateResources()["LaunchTemplate"]
+	assert.True(t, ok)
+	assert.Len(t, launchTemplate.LaunchTemplateData.BlockDeviceMappings, 1)
+	rootVolume := launchTemplate.LaunchTemplateData.BlockDeviceMappings[0]
+	assert.Equal(t, "/dev/xvda", rootVolume.DeviceName)
+	assert.True(t, rootVolume.Ebs.Encrypted)
+	assert.Equal(t, "36c0b54e-64ed-4f2d-a1c7-96558764311e", rootVolume.Ebs.KmsKeyId)
+	assert.Equal(t, 0, rootVolume.Ebs.VolumeSize)
+}
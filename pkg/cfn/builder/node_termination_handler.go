@@ -0,0 +1,101 @@
+package builder
+
+import (
+	gfn "github.com/awslabs/goformation/cloudformation"
+
+	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
+)
+
+const nodeTerminationHandlerQueueResource = "NodeTerminationHandlerQueue"
+
+// nodeTerminationHandlerEventPatterns are the EventBridge events that
+// are forwarded to the queue processed by AWS Node Termination Handler
+var nodeTerminationHandlerEventPatterns = map[string]map[string]interface{}{
+	"NodeTerminationHandlerASGTerminateRule": {
+		"source":      []string{"aws.autoscaling"},
+		"detail-type": []string{"EC2 Instance-terminate Lifecycle Action"},
+	},
+	"NodeTerminationHandlerSpotInterruptionRule": {
+		"source":      []string{"aws.ec2"},
+		"detail-type": []string{"EC2 Spot Instance Interruption Warning"},
+	},
+	"NodeTerminationHandlerRebalanceRule": {
+		"source":      []string{"aws.ec2"},
+		"detail-type": []string{"EC2 Instance Rebalance Recommendation"},
+	},
+	"NodeTerminationHandlerInstanceStateChangeRule": {
+		"source":      []string{"aws.ec2"},
+		"detail-type": []string{"EC2 Instance State-change Notification"},
+	},
+	"NodeTerminationHandlerScheduledChangeRule": {
+		"source":      []string{"aws.health"},
+		"detail-type": []string{"AWS Health Event"},
+		"detail": map[string]interface{}{
+			"service":           []string{"EC2"},
+			"eventTypeCategory": []string{"scheduledChange"},
+		},
+	},
+}
+
+// addResourcesForNodeTerminationHandler adds the SQS queue that AWS Node Termination Handler
+// processes in queue mode, along with the EventBridge rules that send the events to it
+func (c *ClusterResourceSet) addResourcesForNodeTerminationHandler() {
+	refQueue := c.newResource(nodeTerminationHandlerQueueResource, &awsCloudFormationResource{
+		Type: "AWS::SQS::Queue",
+		Properties: map[string]interface{}{
+			"MessageRetentionPeriod": 300,
+		},
+	})
+	queueARN := gfn.MakeFnGetAttString(nodeTerminationHandlerQueueResource + ".Arn")
+
+	c.newResource("NodeTerminationHandlerQueuePolicy", &awsCloudFormationResource{
+		Type: "AWS::SQS::QueuePolicy",
+		Properties: map[string]interface{}{
+			"Queues": []*gfn.Value{refQueue},
+			"PolicyDocument": map[string]interface{}{
+				"Version": "2012-10-17",
+				"Statement": []interface{}{
+					map[string]interface{}{
+						"Effect": "Allow",
+						"Principal": map[string][]string{
+							"Service": {"events.amazonaws.com", "sqs.amazonaws.com"},
+						},
+						"Action":   []string{"sqs:SendMessage"},
+						"Resource": queueARN,
+					},
+				},
+			},
+		},
+	})
+
+	for name, eventPattern := range nodeTerminationHandlerEventPatterns {
+		c.newResource(name, &awsCloudFormationResource{
+			Type: "AWS::Events::Rule",
+			Properties: map[string]interface{}{
+				"EventPattern": eventPattern,
+				"Targets": []interface{}{
+					map[string]interface{}{
+						"Id":  "1",
+						"Arn": queueARN,
+					},
+				},
+			},
+		})
+	}
+
+	c.rs.defineOutputWithoutCollector(outputs.ClusterNodeTerminationHandlerQueueURL, refQueue, false)
+}
+
+// addResourcesForNodeTerminationHandler adds the lifecycle hook that keeps instances from being
+// terminated by the ASG until AWS Node Termination Handler has drained them
+func (n *NodeGroupResourceSet) addResourcesForNodeTerminationHandler() {
+	n.newResource("NodeGroupTerminationLifecycleHook", &awsCloudFormationResource{
+		Type: "AWS::AutoScaling::LifecycleHook",
+		Properties: map[string]interface{}{
+			"AutoScalingGroupName": gfn.MakeRef("NodeGroup"),
+			"LifecycleTransition":  "autoscaling:EC2_INSTANCE_TERMINATING",
+			"DefaultResult":        "CONTINUE",
+			"HeartbeatTimeout":     300,
+		},
+	})
+}
@@ -2,12 +2,11 @@ package builder
 
 import (
 	"fmt"
+	"sort"
 
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
 	gfn "github.com/awslabs/goformation/cloudformation"
 
-	"github.com/kris-nova/logger"
-
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/outputs"
 	"github.com/weaveworks/eksctl/pkg/nodebootstrap"
@@ -26,6 +25,7 @@ type NodeGroupResourceSet struct {
 	securityGroups       []*gfn.Value
 	vpc                  *gfn.Value
 	userData             *gfn.Value
+	securityGroupID      string
 }
 
 // NewNodeGroupResourceSet returns a resource set for a nodegroup embedded in a cluster config
@@ -52,11 +52,21 @@ func (n *NodeGroupResourceSet) AddAllResources() error {
 
 	n.Template().Mappings[servicePrincipalPartitionMapName] = servicePrincipalPartitionMappings
 
+	instanceTypes := []string{n.spec.InstanceType}
+	if api.HasMixedInstances(n.spec) {
+		instanceTypes = n.spec.InstancesDistribution.InstanceTypes
+	}
+	n.rs.addInstanceSelectorMetadata(n.spec.InstanceSelector, instanceTypes)
+
 	n.rs.defineOutputWithoutCollector(outputs.NodeGroupFeaturePrivateNetworking, n.spec.PrivateNetworking, false)
 	n.rs.defineOutputWithoutCollector(outputs.NodeGroupFeatureSharedSecurityGroup, n.spec.SecurityGroups.WithShared, false)
 	n.rs.defineOutputWithoutCollector(outputs.NodeGroupFeatureLocalSecurityGroup, n.spec.SecurityGroups.WithLocal, false)
 
 	n.vpc = makeImportValue(n.clusterStackName, outputs.ClusterVPC)
+	if n.spec.Account != nil {
+		// the outputs of the cluster stack can't be imported from another account
+		n.vpc = gfn.NewString(n.clusterSpec.VPC.ID)
+	}
 
 	userData, err := nodebootstrap.NewUserData(n.clusterSpec, n.spec)
 	if err != nil {
@@ -94,7 +104,9 @@ func (n *NodeGroupResourceSet) AddAllResources() error {
 	if err := n.addResourcesForIAM(); err != nil {
 		return err
 	}
-	n.addResourcesForSecurityGroups()
+	if err := n.addResourcesForSecurityGroups(); err != nil {
+		return err
+	}
 
 	return n.addResourcesForNodeGroup()
 }
@@ -121,37 +133,20 @@ func (n *NodeGroupResourceSet) addResourcesForNodeGroup() error {
 		launchTemplateData.KeyName = gfn.NewString(*n.spec.SSH.PublicKeyName)
 	}
 
-	if volumeSize := n.spec.VolumeSize; volumeSize != nil && *volumeSize > 0 {
-		var (
-			kmsKeyID   *gfn.Value
-			volumeIOPS *gfn.Value
-		)
-		if api.IsSetAndNonEmptyString(n.spec.VolumeKmsKeyID) {
-			kmsKeyID = gfn.NewString(*n.spec.VolumeKmsKeyID)
-		}
-
-		if *n.spec.VolumeType == api.NodeVolumeTypeIO1 {
-			volumeIOPS = gfn.NewInteger(*n.spec.VolumeIOPS)
-		}
-
-		launchTemplateData.BlockDeviceMappings = []gfn.AWSEC2LaunchTemplate_BlockDeviceMapping{{
-			DeviceName: gfn.NewString(*n.spec.VolumeName),
-			Ebs: &gfn.AWSEC2LaunchTemplate_Ebs{
-				VolumeSize: gfn.NewInteger(*volumeSize),
-				VolumeType: gfn.NewString(*n.spec.VolumeType),
-				Encrypted:  gfn.NewBoolean(*n.spec.VolumeEncrypted),
-				KmsKeyId:   kmsKeyID,
-				Iops:       volumeIOPS,
-			},
-		}}
-	}
-
-	n.newResource("NodeGroupLaunchTemplate", &gfn.AWSEC2LaunchTemplate{
+	n.newResource("NodeGroupLaunchTemplate", &awsEC2LaunchTemplate{
 		LaunchTemplateName: launchTemplateName,
-		LaunchTemplateData: launchTemplateData,
+		LaunchTemplateData: &nodeGroupLaunchTemplateData{
+			awsEC2LaunchTemplateData: (*awsEC2LaunchTemplateData)(launchTemplateData),
+			BlockDeviceMappings:      makeBlockDeviceMappings(n.spec),
+			TagSpecifications:        makeLaunchTemplateTagSpecifications(n.instanceTags()),
+		},
 	})
 
-	vpcZoneIdentifier, err := AssignSubnets(n.spec.AvailabilityZones, n.clusterStackName, n.clusterSpec, n.spec.PrivateNetworking)
+	availabilityZones := n.spec.AvailabilityZones
+	if n.spec.Account != nil && len(availabilityZones) == 0 {
+		availabilityZones = subnetZones(n.clusterSpec, n.spec.PrivateNetworking)
+	}
+	vpcZoneIdentifier, err := AssignSubnets(availabilityZones, n.clusterStackName, n.clusterSpec, n.spec.PrivateNetworking)
 	if err != nil {
 		return err
 	}
@@ -182,13 +177,37 @@ func (n *NodeGroupResourceSet) addResourcesForNodeGroup() error {
 			},
 		)
 	}
+	if n.clusterSpec.HasNodeTerminationHandlerQueue() {
+		tags = append(tags, map[string]interface{}{
+			"Key":               api.NodeTerminationHandlerManagedASGTag,
+			"Value":             "true",
+			"PropagateAtLaunch": "true",
+		})
+	}
 
 	asg := nodeGroupResource(launchTemplateName, vpcZoneIdentifier, tags, n.spec)
 	n.newResource("NodeGroup", asg)
 
+	if n.clusterSpec.HasNodeTerminationHandlerQueue() {
+		n.addResourcesForNodeTerminationHandler()
+	}
+
+	if alarmsOnNodeGroup(n.clusterSpec, n.spec) {
+		n.addResourcesForAlarms(asg)
+	}
+
 	return nil
 }
 
+// instanceTags returns the tags of the instances and volumes, which include the eks:cluster-name
+// tag EKS sets on the instances of managed nodegroups, so that the costs of both kinds of
+// nodegroups are reported by cluster
+func (n *NodeGroupResourceSet) instanceTags() map[string]string {
+	tags := makeResourceTags(n.clusterSpec, n.spec.Name, n.spec.Tags)
+	tags[api.EKSClusterNameTag] = n.clusterSpec.Metadata.Name
+	return tags
+}
+
 // AssignSubnets subnets based on the specified availability zones
 func AssignSubnets(availabilityZones []string, clusterStackName string, clusterSpec *api.ClusterConfig, privateNetworking bool) (interface{}, error) {
 	// currently goformation type system doesn't allow specifying `VPCZoneIdentifier: { "Fn::ImportValue": ... }`,
@@ -229,11 +248,35 @@ func AssignSubnets(availabilityZones []string, clusterStackName string, clusterS
 	}, nil
 }
 
+// subnetZones returns the zones of the subnets of the cluster, so that a nodegroup in another
+// account uses all of them without importing the subnets from the cluster stack
+func subnetZones(clusterSpec *api.ClusterConfig, privateNetworking bool) []string {
+	if clusterSpec.VPC.Subnets == nil {
+		return nil
+	}
+	subnets := clusterSpec.VPC.Subnets.Private
+	if !privateNetworking {
+		subnets = clusterSpec.VPC.Subnets.Public
+	}
+	var zones []string
+	for az := range subnets {
+		zones = append(zones, az)
+	}
+	sort.Strings(zones)
+	return zones
+}
+
 // GetAllOutputs collects all outputs of the nodegroup
 func (n *NodeGroupResourceSet) GetAllOutputs(stack cfn.Stack) error {
 	return n.rs.GetAllOutputs(stack)
 }
 
+// SecurityGroupID returns the ID of the local security group of a nodegroup in another account
+// than the control plane, once the stack is created
+func (n *NodeGroupResourceSet) SecurityGroupID() string {
+	return n.securityGroupID
+}
+
 func newLaunchTemplateData(n *NodeGroupResourceSet) *gfn.AWSEC2LaunchTemplate_LaunchTemplateData {
 	launchTemplateData := &gfn.AWSEC2LaunchTemplate_LaunchTemplateData{
 		IamInstanceProfile: &gfn.AWSEC2LaunchTemplate_IamInstanceProfile{
@@ -0,0 +1,89 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	gfn "github.com/awslabs/goformation/cloudformation"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// NodeGroupAccessResourceSet holds the rules of the control plane security group allowing a
+// nodegroup in another account to join the cluster, the stack is created in the account of the
+// cluster once the nodegroup stack is created
+type NodeGroupAccessResourceSet struct {
+	rs                *resourceSet
+	clusterSpec       *api.ClusterConfig
+	spec              *api.NodeGroup
+	nodeSecurityGroup string
+}
+
+// NewNodeGroupAccessResourceSet returns a resource set for the access of a nodegroup in another
+// account, whose local security group is nodeSecurityGroup
+func NewNodeGroupAccessResourceSet(spec *api.ClusterConfig, ng *api.NodeGroup, nodeSecurityGroup string) *NodeGroupAccessResourceSet {
+	return &NodeGroupAccessResourceSet{
+		rs:                newResourceSet(),
+		clusterSpec:       spec,
+		spec:              ng,
+		nodeSecurityGroup: nodeSecurityGroup,
+	}
+}
+
+// AddAllResources adds the rules of the control plane security group
+func (a *NodeGroupAccessResourceSet) AddAllResources() error {
+	if a.nodeSecurityGroup == "" {
+		return fmt.Errorf("the security group of nodegroup %q is unknown", a.spec.Name)
+	}
+	if a.clusterSpec.VPC.SecurityGroup == "" {
+		return fmt.Errorf("the control plane security group of cluster %q is unknown", a.clusterSpec.Metadata.Name)
+	}
+	nodeGroupAccountID, err := accountIDFromRoleARN(a.spec.Account.RoleARN)
+	if err != nil {
+		return err
+	}
+
+	a.rs.template.Description = fmt.Sprintf("Control plane access of nodegroup %q in account %s %s", a.spec.Name, nodeGroupAccountID, templateDescriptionSuffix)
+
+	addControlPlaneRulesForNodeGroup(a.rs, gfn.NewString(a.clusterSpec.VPC.SecurityGroup), gfn.NewString(a.nodeSecurityGroup),
+		gfn.NewString(nodeGroupAccountID), "worker nodes in group "+a.spec.Name)
+	return nil
+}
+
+// WithIAM returns false
+func (*NodeGroupAccessResourceSet) WithIAM() bool { return false }
+
+// WithNamedIAM returns false
+func (*NodeGroupAccessResourceSet) WithNamedIAM() bool { return false }
+
+// RenderJSON returns the rendered JSON
+func (a *NodeGroupAccessResourceSet) RenderJSON() ([]byte, error) {
+	return a.rs.renderJSON()
+}
+
+// GetAllOutputs collects all outputs of the stack
+func (a *NodeGroupAccessResourceSet) GetAllOutputs(stack cfn.Stack) error {
+	return a.rs.GetAllOutputs(stack)
+}
+
+// clusterAccountID returns the account of the control plane of the cluster
+func clusterAccountID(spec *api.ClusterConfig) (string, error) {
+	if spec.Status == nil || spec.Status.ARN == "" {
+		return "", fmt.Errorf("the ARN of cluster %q is unknown", spec.Metadata.Name)
+	}
+	parsed, err := arn.Parse(spec.Status.ARN)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing the ARN of cluster %q", spec.Metadata.Name)
+	}
+	return parsed.AccountID, nil
+}
+
+func accountIDFromRoleARN(roleARN string) (string, error) {
+	parsed, err := arn.Parse(roleARN)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing role ARN %q", roleARN)
+	}
+	return parsed.AccountID, nil
+}
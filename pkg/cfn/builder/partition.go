@@ -11,11 +11,13 @@ var servicePrincipalPartitionMappings = map[string]map[string]string{
 		"EC2":            "ec2.amazonaws.com",
 		"EKS":            "eks.amazonaws.com",
 		"EKSFargatePods": "eks-fargate-pods.amazonaws.com",
+		"VPCFlowLogs":    "vpc-flow-logs.amazonaws.com",
 	},
 	"aws-cn": {
 		"EC2":            "ec2.amazonaws.com.cn",
 		"EKS":            "eks.amazonaws.com",
 		"EKSFargatePods": "eks-fargate-pods.amazonaws.com",
+		"VPCFlowLogs":    "vpc-flow-logs.amazonaws.com",
 	},
 }
 
@@ -0,0 +1,84 @@
+package builder
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// RawAPIOverrides are fields of the EKS API requests that are merged as they are into the
+// resources eksctl creates, to use parameters it doesn't model yet; they aren't validated and
+// override the fields set by eksctl
+type RawAPIOverrides struct {
+	// CreateCluster fields are merged into the control plane
+	CreateCluster map[string]interface{} `json:"createCluster,omitempty"`
+	// CreateNodegroup fields are merged into every managed nodegroup
+	CreateNodegroup map[string]interface{} `json:"createNodegroup,omitempty"`
+}
+
+// ParseRawAPIOverrides parses RawAPIOverrides from a JSON document
+func ParseRawAPIOverrides(data []byte) (*RawAPIOverrides, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	overrides := &RawAPIOverrides{}
+	if err := decoder.Decode(overrides); err != nil {
+		return nil, errors.Wrap(err, "parsing raw API overrides, expected an object with createCluster and createNodegroup fields")
+	}
+	return overrides, nil
+}
+
+// SetRawAPIOverrides merges the fields of the CreateCluster API request into the control plane
+func (c *ClusterResourceSet) SetRawAPIOverrides(overrides map[string]interface{}) {
+	warnRawAPIOverrides("CreateCluster", overrides)
+	c.rawAPIOverrides = overrides
+}
+
+// SetRawAPIOverrides merges the fields of the CreateNodegroup API request into the nodegroup
+func (m *ManagedNodeGroupResourceSet) SetRawAPIOverrides(overrides map[string]interface{}) {
+	warnRawAPIOverrides("CreateNodegroup", overrides)
+	m.rawAPIOverrides = overrides
+}
+
+func warnRawAPIOverrides(request string, overrides map[string]interface{}) {
+	if len(overrides) == 0 {
+		return
+	}
+	var fields []string
+	for field := range overrides {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	logger.Warning("overriding %s of the %s request with raw API overrides, eksctl doesn't validate them", strings.Join(fields, ", "), request)
+}
+
+// toProperties converts a resource to its properties, for fields to be merged into them
+func toProperties(resource interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	properties := map[string]interface{}{}
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return nil, err
+	}
+	return properties, nil
+}
+
+// withRawAPIOverrides merges overrides into properties; objects are merged field by field and
+// any other value replaces the one set by eksctl
+func withRawAPIOverrides(properties map[string]interface{}, overrides map[string]interface{}) {
+	for key, value := range overrides {
+		name := toPropertyName(key)
+		override, isObject := toPropertyValue(value).(map[string]interface{})
+		if existing, ok := properties[name].(map[string]interface{}); ok && isObject {
+			withRawAPIOverrides(existing, override)
+			continue
+		}
+		properties[name] = toPropertyValue(value)
+	}
+}
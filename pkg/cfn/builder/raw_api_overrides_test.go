@@ -0,0 +1,50 @@
+package builder
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+func TestParseRawAPIOverrides(t *testing.T) {
+	overrides, err := ParseRawAPIOverrides([]byte(`{"createCluster": {"upgradePolicy": {"supportType": "STANDARD"}}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"supportType": "STANDARD"}, overrides.CreateCluster["upgradePolicy"])
+	assert.Empty(t, overrides.CreateNodegroup)
+
+	_, err = ParseRawAPIOverrides([]byte(`{"createNodegroups": {}}`))
+	assert.Error(t, err)
+}
+
+func TestManagedNodeGroupRawAPIOverrides(t *testing.T) {
+	ng := api.NewManagedNodeGroup()
+	ng.Name = "ng-overrides"
+	minSize, maxSize := 1, 3
+	ng.MinSize, ng.MaxSize = &minSize, &maxSize
+
+	stack := NewManagedNodeGroup(api.NewClusterConfig(), ng, "overrides-test")
+	stack.SetRawAPIOverrides(map[string]interface{}{
+		"amiType":       "CUSTOM",
+		"scalingConfig": map[string]interface{}{"maxSize": 5},
+		"updateConfig":  map[string]interface{}{"maxUnavailablePercentage": 50},
+	})
+	assert.NoError(t, stack.AddAllResources())
+
+	bytes, err := stack.RenderJSON()
+	assert.NoError(t, err)
+
+	var template struct {
+		Resources map[string]struct {
+			Properties map[string]interface{}
+		}
+	}
+	assert.NoError(t, json.Unmarshal(bytes, &template))
+
+	properties := template.Resources["ManagedNodeGroup"].Properties
+	assert.Equal(t, "ng-overrides", properties["NodegroupName"])
+	assert.Equal(t, "CUSTOM", properties["AmiType"])
+	assert.Equal(t, map[string]interface{}{"MinSize": 1.0, "MaxSize": 5.0}, properties["ScalingConfig"])
+	assert.Equal(t, map[string]interface{}{"MaxUnavailablePercentage": 50.0}, properties["UpdateConfig"])
+}
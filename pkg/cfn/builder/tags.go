@@ -0,0 +1,65 @@
+package builder
+
+import (
+	"sort"
+
+	gfn "github.com/awslabs/goformation/cloudformation"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// CloudFormation only propagates stack tags to the resources it creates itself, instances
+// launched by an ASG, their volumes and network interfaces have to be tagged through the launch template
+var launchTemplateTaggedResourceTypes = []string{"instance", "volume", "network-interface"}
+
+type launchTemplateTagSpecification struct {
+	ResourceType *gfn.Value `json:"ResourceType,omitempty"`
+	Tags         []gfn.Tag  `json:"Tags,omitempty"`
+}
+
+// makeResourceTags returns metadata.tags along with the tags that identify
+// the cluster and nodegroup, followed by the nodegroup tags, which take precedence
+func makeResourceTags(clusterSpec *api.ClusterConfig, nodeGroupName string, nodeGroupTags map[string]string) map[string]string {
+	tags := map[string]string{
+		api.ClusterNameTag:   clusterSpec.Metadata.Name,
+		api.NodeGroupNameTag: nodeGroupName,
+	}
+	for k, v := range clusterSpec.Metadata.Tags {
+		tags[k] = v
+	}
+	for k, v := range nodeGroupTags {
+		tags[k] = v
+	}
+	return tags
+}
+
+// makeTags returns tags sorted by key, to keep the template stable across updates
+func makeTags(tags map[string]string) []gfn.Tag {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var gfnTags []gfn.Tag
+	for _, k := range keys {
+		gfnTags = append(gfnTags, gfn.Tag{
+			Key:   gfn.NewString(k),
+			Value: gfn.NewString(tags[k]),
+		})
+	}
+	return gfnTags
+}
+
+func makeLaunchTemplateTagSpecifications(tags map[string]string) []launchTemplateTagSpecification {
+	gfnTags := makeTags(tags)
+
+	var tagSpecifications []launchTemplateTagSpecification
+	for _, resourceType := range launchTemplateTaggedResourceTypes {
+		tagSpecifications = append(tagSpecifications, launchTemplateTagSpecification{
+			ResourceType: gfn.NewString(resourceType),
+			Tags:         gfnTags,
+		})
+	}
+	return tagSpecifications
+}
@@ -0,0 +1,95 @@
+package builder
+
+import (
+	"encoding/json"
+
+	gfn "github.com/awslabs/goformation/cloudformation"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// TODO use goformation after support for gp3 throughput is out
+type awsEC2LaunchTemplate struct {
+	LaunchTemplateName *gfn.Value                   `json:"LaunchTemplateName,omitempty"`
+	LaunchTemplateData *nodeGroupLaunchTemplateData `json:"LaunchTemplateData,omitempty"`
+}
+
+func (t *awsEC2LaunchTemplate) MarshalJSON() ([]byte, error) {
+	type Properties awsEC2LaunchTemplate
+	return json.Marshal(&struct {
+		Type       string
+		Properties Properties
+	}{
+		Type:       "AWS::EC2::LaunchTemplate",
+		Properties: Properties(*t),
+	})
+}
+
+type awsEC2LaunchTemplateData gfn.AWSEC2LaunchTemplate_LaunchTemplateData
+
+type nodeGroupLaunchTemplateData struct {
+	*awsEC2LaunchTemplateData `json:",inline"`
+	BlockDeviceMappings       []blockDeviceMapping             `json:"BlockDeviceMappings,omitempty"`
+	TagSpecifications         []launchTemplateTagSpecification `json:"TagSpecifications,omitempty"`
+}
+
+type blockDeviceMapping struct {
+	DeviceName *gfn.Value `json:"DeviceName,omitempty"`
+	Ebs        *ebsVolume `json:"Ebs,omitempty"`
+}
+
+type awsEC2LaunchTemplateEbs gfn.AWSEC2LaunchTemplate_Ebs
+
+type ebsVolume struct {
+	*awsEC2LaunchTemplateEbs `json:",inline"`
+	Throughput               *gfn.Value `json:"Throughput,omitempty"`
+}
+
+// newEBSVolume returns an EBS volume, with the size of the snapshot of the AMI when size is 0
+func newEBSVolume(size int, volumeType string, iops, throughput *int, encrypted *bool, kmsKeyID *string) *ebsVolume {
+	volume := &ebsVolume{
+		awsEC2LaunchTemplateEbs: &awsEC2LaunchTemplateEbs{
+			VolumeType: gfn.NewString(volumeType),
+			Encrypted:  gfn.NewBoolean(api.IsEnabled(encrypted)),
+		},
+	}
+	if size > 0 {
+		volume.VolumeSize = gfn.NewInteger(size)
+	}
+	if api.IsEnabled(encrypted) && api.IsSetAndNonEmptyString(kmsKeyID) {
+		volume.KmsKeyId = gfn.NewString(*kmsKeyID)
+	}
+	if iops != nil && (volumeType == api.NodeVolumeTypeIO1 || volumeType == api.NodeVolumeTypeGP3) {
+		volume.Iops = gfn.NewInteger(*iops)
+	}
+	if throughput != nil && volumeType == api.NodeVolumeTypeGP3 {
+		volume.Throughput = gfn.NewInteger(*throughput)
+	}
+	return volume
+}
+
+// makeBlockDeviceMappings returns the mappings for the root volume, when its size or
+// encryption is set explicitly, followed by the additional volumes of the nodegroup
+func makeBlockDeviceMappings(ng *api.NodeGroup) []blockDeviceMapping {
+	var mappings []blockDeviceMapping
+
+	var volumeSize int
+	if ng.VolumeSize != nil {
+		volumeSize = *ng.VolumeSize
+	}
+	if (volumeSize > 0 || api.IsEnabled(ng.VolumeEncrypted)) && api.IsSetAndNonEmptyString(ng.VolumeName) {
+		mappings = append(mappings, blockDeviceMapping{
+			DeviceName: gfn.NewString(*ng.VolumeName),
+			Ebs:        newEBSVolume(volumeSize, *ng.VolumeType, ng.VolumeIOPS, ng.VolumeThroughput, ng.VolumeEncrypted, ng.VolumeKmsKeyID),
+		})
+	}
+
+	for _, volume := range ng.AdditionalVolumes {
+		mappings = append(mappings, blockDeviceMapping{
+			DeviceName: gfn.NewString(volume.Name),
+			Ebs:        newEBSVolume(*volume.Size, *volume.Type, volume.IOPS, volume.Throughput, ng.VolumeEncrypted, ng.VolumeKmsKeyID),
+		})
+	}
+
+	return mappings
+}
@@ -124,6 +124,7 @@ func (c *ClusterResourceSet) addResourcesForVPC() error {
 	}
 
 	c.addSubnets(nil, api.SubnetTopologyPrivate, c.spec.VPC.Subnets.Private)
+	c.addResourcesForVPCAttachments(refPublicRT)
 	return nil
 }
 
@@ -195,15 +196,23 @@ var (
 func (c *ClusterResourceSet) addResourcesForSecurityGroups() {
 	var refControlPlaneSG, refClusterSharedNodeSG *gfn.Value
 
-	if c.spec.VPC.SecurityGroup == "" {
-		refControlPlaneSG = c.newResource(cfnControlPlaneSGResource, &gfn.AWSEC2SecurityGroup{
-			GroupDescription: gfn.NewString("Communication between the control plane and worker nodegroups"),
-			VpcId:            c.vpc,
-		})
+	if len(c.spec.VPC.SecurityGroupIDs) > 0 {
+		// the user's SGs are passed to EKS API as they are, rules of nodegroups go to the first one
+		for _, id := range c.spec.VPC.SecurityGroupIDs {
+			c.securityGroups = append(c.securityGroups, gfn.NewString(id))
+		}
+		refControlPlaneSG = c.securityGroups[0]
 	} else {
-		refControlPlaneSG = gfn.NewString(c.spec.VPC.SecurityGroup)
+		if c.spec.VPC.SecurityGroup == "" {
+			refControlPlaneSG = c.newResource(cfnControlPlaneSGResource, &gfn.AWSEC2SecurityGroup{
+				GroupDescription: gfn.NewString("Communication between the control plane and worker nodegroups"),
+				VpcId:            c.vpc,
+			})
+		} else {
+			refControlPlaneSG = gfn.NewString(c.spec.VPC.SecurityGroup)
+		}
+		c.securityGroups = []*gfn.Value{refControlPlaneSG} // only this one SG is passed to EKS API, nodes are isolated
 	}
-	c.securityGroups = []*gfn.Value{refControlPlaneSG} // only this one SG is passed to EKS API, nodes are isolated
 
 	if c.spec.VPC.SharedNodeSecurityGroup == "" {
 		refClusterSharedNodeSG = c.newResource(cfnSharedNodeSGResource, &gfn.AWSEC2SecurityGroup{
@@ -256,7 +265,7 @@ func (c *ClusterResourceSet) addResourcesForSecurityGroups() {
 	})
 }
 
-func (n *NodeGroupResourceSet) addResourcesForSecurityGroups() {
+func (n *NodeGroupResourceSet) addResourcesForSecurityGroups() error {
 	for _, id := range n.spec.SecurityGroups.AttachIDs {
 		n.securityGroups = append(n.securityGroups, gfn.NewString(id))
 	}
@@ -267,7 +276,7 @@ func (n *NodeGroupResourceSet) addResourcesForSecurityGroups() {
 	}
 
 	if api.IsDisabled(n.spec.SecurityGroups.WithLocal) {
-		return
+		return nil
 	}
 
 	desc := "worker nodes in group " + n.nodeGroupName
@@ -275,9 +284,20 @@ func (n *NodeGroupResourceSet) addResourcesForSecurityGroups() {
 	allInternalIPv4 := gfn.NewString(n.clusterSpec.VPC.CIDR.String())
 
 	refControlPlaneSG := makeImportValue(n.clusterStackName, outputs.ClusterSecurityGroup)
+	// the rules of the control plane security group of a nodegroup in another account are
+	// added by the access stack in the account of the cluster
+	var refControlPlaneSGOwner *gfn.Value
+	if n.spec.Account != nil {
+		clusterAccountID, err := clusterAccountID(n.clusterSpec)
+		if err != nil {
+			return err
+		}
+		refControlPlaneSG = gfn.NewString(n.clusterSpec.VPC.SecurityGroup)
+		refControlPlaneSGOwner = gfn.NewString(clusterAccountID)
+	}
 
 	refNodeGroupLocalSG := n.newResource("SG", &gfn.AWSEC2SecurityGroup{
-		VpcId:            makeImportValue(n.clusterStackName, outputs.ClusterVPC),
+		VpcId:            n.vpc,
 		GroupDescription: gfn.NewString("Communication between the control plane and " + desc),
 		Tags: []gfn.Tag{{
 			Key:   gfn.NewString("kubernetes.io/cluster/" + n.clusterSpec.Metadata.Name),
@@ -288,74 +308,97 @@ func (n *NodeGroupResourceSet) addResourcesForSecurityGroups() {
 	n.securityGroups = append(n.securityGroups, refNodeGroupLocalSG)
 
 	n.newResource("IngressInterCluster", &gfn.AWSEC2SecurityGroupIngress{
-		GroupId:               refNodeGroupLocalSG,
-		SourceSecurityGroupId: refControlPlaneSG,
-		Description:           gfn.NewString("Allow " + desc + " to communicate with control plane (kubelet and workload TCP ports)"),
-		IpProtocol:            sgProtoTCP,
-		FromPort:              sgMinNodePort,
-		ToPort:                sgMaxNodePort,
+		GroupId:                    refNodeGroupLocalSG,
+		SourceSecurityGroupId:      refControlPlaneSG,
+		SourceSecurityGroupOwnerId: refControlPlaneSGOwner,
+		Description:                gfn.NewString("Allow " + desc + " to communicate with control plane (kubelet and workload TCP ports)"),
+		IpProtocol:                 sgProtoTCP,
+		FromPort:                   sgMinNodePort,
+		ToPort:                     sgMaxNodePort,
 	})
-	n.newResource("EgressInterCluster", &gfn.AWSEC2SecurityGroupEgress{
+	n.newResource("IngressInterClusterAPI", &gfn.AWSEC2SecurityGroupIngress{
+		GroupId:                    refNodeGroupLocalSG,
+		SourceSecurityGroupId:      refControlPlaneSG,
+		SourceSecurityGroupOwnerId: refControlPlaneSGOwner,
+		Description:                gfn.NewString("Allow " + desc + " to communicate with control plane (workloads using HTTPS port, commonly used with extension API servers)"),
+		IpProtocol:                 sgProtoTCP,
+		FromPort:                   sgPortHTTPS,
+		ToPort:                     sgPortHTTPS,
+	})
+	if n.spec.Account != nil {
+		n.rs.defineOutput(outputs.NodeGroupSecurityGroup, refNodeGroupLocalSG, false, func(v string) error {
+			n.securityGroupID = v
+			return nil
+		})
+	} else {
+		addControlPlaneRulesForNodeGroup(n.rs, refControlPlaneSG, refNodeGroupLocalSG, nil, desc)
+	}
+	n.addSSHRules(allInternalIPv4, refNodeGroupLocalSG, desc)
+	return nil
+}
+
+// addControlPlaneRulesForNodeGroup allows the control plane to reach the nodes of a nodegroup and
+// to receive their API requests, refNodeGroupSGOwner is only set when the nodegroup belongs to
+// another account than the control plane
+func addControlPlaneRulesForNodeGroup(rs *resourceSet, refControlPlaneSG, refNodeGroupSG, refNodeGroupSGOwner *gfn.Value, desc string) {
+	rs.newResource("EgressInterCluster", &gfn.AWSEC2SecurityGroupEgress{
 		GroupId:                    refControlPlaneSG,
-		DestinationSecurityGroupId: refNodeGroupLocalSG,
+		DestinationSecurityGroupId: refNodeGroupSG,
 		Description:                gfn.NewString("Allow control plane to communicate with " + desc + " (kubelet and workload TCP ports)"),
 		IpProtocol:                 sgProtoTCP,
 		FromPort:                   sgMinNodePort,
 		ToPort:                     sgMaxNodePort,
 	})
-	n.newResource("IngressInterClusterAPI", &gfn.AWSEC2SecurityGroupIngress{
-		GroupId:               refNodeGroupLocalSG,
-		SourceSecurityGroupId: refControlPlaneSG,
-		Description:           gfn.NewString("Allow " + desc + " to communicate with control plane (workloads using HTTPS port, commonly used with extension API servers)"),
-		IpProtocol:            sgProtoTCP,
-		FromPort:              sgPortHTTPS,
-		ToPort:                sgPortHTTPS,
-	})
-	n.newResource("EgressInterClusterAPI", &gfn.AWSEC2SecurityGroupEgress{
+	rs.newResource("EgressInterClusterAPI", &gfn.AWSEC2SecurityGroupEgress{
 		GroupId:                    refControlPlaneSG,
-		DestinationSecurityGroupId: refNodeGroupLocalSG,
+		DestinationSecurityGroupId: refNodeGroupSG,
 		Description:                gfn.NewString("Allow control plane to communicate with " + desc + " (workloads using HTTPS port, commonly used with extension API servers)"),
 		IpProtocol:                 sgProtoTCP,
 		FromPort:                   sgPortHTTPS,
 		ToPort:                     sgPortHTTPS,
 	})
-	n.newResource("IngressInterClusterCP", &gfn.AWSEC2SecurityGroupIngress{
-		GroupId:               refControlPlaneSG,
-		SourceSecurityGroupId: refNodeGroupLocalSG,
-		Description:           gfn.NewString("Allow control plane to receive API requests from " + desc),
-		IpProtocol:            sgProtoTCP,
-		FromPort:              sgPortHTTPS,
-		ToPort:                sgPortHTTPS,
+	rs.newResource("IngressInterClusterCP", &gfn.AWSEC2SecurityGroupIngress{
+		GroupId:                    refControlPlaneSG,
+		SourceSecurityGroupId:      refNodeGroupSG,
+		SourceSecurityGroupOwnerId: refNodeGroupSGOwner,
+		Description:                gfn.NewString("Allow control plane to receive API requests from " + desc),
+		IpProtocol:                 sgProtoTCP,
+		FromPort:                   sgPortHTTPS,
+		ToPort:                     sgPortHTTPS,
 	})
-	if *n.spec.SSH.Allow {
-		if n.spec.PrivateNetworking {
-			n.newResource("SSHIPv4", &gfn.AWSEC2SecurityGroupIngress{
-				GroupId:     refNodeGroupLocalSG,
-				CidrIp:      allInternalIPv4,
-				Description: gfn.NewString("Allow SSH access to " + desc + " (private, only inside VPC)"),
-				IpProtocol:  sgProtoTCP,
-				FromPort:    sgPortSSH,
-				ToPort:      sgPortSSH,
-			})
-		} else {
-			n.newResource("SSHIPv4", &gfn.AWSEC2SecurityGroupIngress{
-				GroupId:     refNodeGroupLocalSG,
-				CidrIp:      sgSourceAnywhereIPv4,
-				Description: gfn.NewString("Allow SSH access to " + desc),
-				IpProtocol:  sgProtoTCP,
-				FromPort:    sgPortSSH,
-				ToPort:      sgPortSSH,
-			})
-			n.newResource("SSHIPv6", &gfn.AWSEC2SecurityGroupIngress{
-				GroupId:     refNodeGroupLocalSG,
-				CidrIpv6:    sgSourceAnywhereIPv6,
-				Description: gfn.NewString("Allow SSH access to " + desc),
-				IpProtocol:  sgProtoTCP,
-				FromPort:    sgPortSSH,
-				ToPort:      sgPortSSH,
-			})
-		}
+}
+
+func (n *NodeGroupResourceSet) addSSHRules(allInternalIPv4, refNodeGroupLocalSG *gfn.Value, desc string) {
+	if !*n.spec.SSH.Allow {
+		return
 	}
+	if n.spec.PrivateNetworking {
+		n.newResource("SSHIPv4", &gfn.AWSEC2SecurityGroupIngress{
+			GroupId:     refNodeGroupLocalSG,
+			CidrIp:      allInternalIPv4,
+			Description: gfn.NewString("Allow SSH access to " + desc + " (private, only inside VPC)"),
+			IpProtocol:  sgProtoTCP,
+			FromPort:    sgPortSSH,
+			ToPort:      sgPortSSH,
+		})
+		return
+	}
+	n.newResource("SSHIPv4", &gfn.AWSEC2SecurityGroupIngress{
+		GroupId:     refNodeGroupLocalSG,
+		CidrIp:      sgSourceAnywhereIPv4,
+		Description: gfn.NewString("Allow SSH access to " + desc),
+		IpProtocol:  sgProtoTCP,
+		FromPort:    sgPortSSH,
+		ToPort:      sgPortSSH,
+	})
+	n.newResource("SSHIPv6", &gfn.AWSEC2SecurityGroupIngress{
+		GroupId:     refNodeGroupLocalSG,
+		CidrIpv6:    sgSourceAnywhereIPv6,
+		Description: gfn.NewString("Allow SSH access to " + desc),
+		IpProtocol:  sgProtoTCP,
+		FromPort:    sgPortSSH,
+		ToPort:      sgPortSSH,
+	})
 }
 
 func (c *ClusterResourceSet) haNAT() {
@@ -372,6 +415,7 @@ func (c *ClusterResourceSet) haNAT() {
 			AllocationId: gfn.MakeFnGetAttString("NATIP" + alphanumericUpperAZ + ".AllocationId"),
 			SubnetId:     gfn.MakeRef("SubnetPublic" + alphanumericUpperAZ),
 		})
+		c.natGateways = append(c.natGateways, refNG)
 
 		// Allocate a routing table for the private subnet
 		refRT := c.newResource("PrivateRouteTable"+alphanumericUpperAZ, &gfn.AWSEC2RouteTable{
@@ -404,6 +448,7 @@ func (c *ClusterResourceSet) singleNAT() {
 		AllocationId: gfn.MakeFnGetAttString("NATIP.AllocationId"),
 		SubnetId:     gfn.MakeRef("SubnetPublic" + firstUpperAZ),
 	})
+	c.natGateways = append(c.natGateways, refNG)
 
 	for _, az := range c.spec.AvailabilityZones {
 		alphanumericUpperAZ := strings.ToUpper(strings.Join(strings.Split(az, "-"), ""))
@@ -0,0 +1,156 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	gfn "github.com/awslabs/goformation/cloudformation"
+)
+
+// TODO use goformation after support is out
+type awsEC2TransitGatewayAttachment struct {
+	TransitGatewayId *gfn.Value   `json:"TransitGatewayId"`
+	VpcId            *gfn.Value   `json:"VpcId"`
+	SubnetIds        []*gfn.Value `json:"SubnetIds"`
+	Tags             []gfn.Tag    `json:"Tags,omitempty"`
+}
+
+func (a *awsEC2TransitGatewayAttachment) MarshalJSON() ([]byte, error) {
+	type Properties awsEC2TransitGatewayAttachment
+	return json.Marshal(&struct {
+		Type       string
+		Properties Properties
+	}{
+		Type:       "AWS::EC2::TransitGatewayAttachment",
+		Properties: Properties(*a),
+	})
+}
+
+// TODO use goformation after support is out
+type awsEC2VPCPeeringConnection struct {
+	VpcId       *gfn.Value `json:"VpcId"`
+	PeerVpcId   *gfn.Value `json:"PeerVpcId"`
+	PeerOwnerId *gfn.Value `json:"PeerOwnerId,omitempty"`
+	PeerRegion  *gfn.Value `json:"PeerRegion,omitempty"`
+	PeerRoleArn *gfn.Value `json:"PeerRoleArn,omitempty"`
+	Tags        []gfn.Tag  `json:"Tags,omitempty"`
+}
+
+func (p *awsEC2VPCPeeringConnection) MarshalJSON() ([]byte, error) {
+	type Properties awsEC2VPCPeeringConnection
+	return json.Marshal(&struct {
+		Type       string
+		Properties Properties
+	}{
+		Type:       "AWS::EC2::VPCPeeringConnection",
+		Properties: Properties(*p),
+	})
+}
+
+// awsEC2AttachmentRoute is a route through a transit gateway or a peering connection
+// TODO use goformation after support is out
+type awsEC2AttachmentRoute struct {
+	RouteTableId           *gfn.Value `json:"RouteTableId"`
+	DestinationCidrBlock   *gfn.Value `json:"DestinationCidrBlock"`
+	TransitGatewayId       *gfn.Value `json:"TransitGatewayId,omitempty"`
+	VpcPeeringConnectionId *gfn.Value `json:"VpcPeeringConnectionId,omitempty"`
+
+	dependsOn []string
+}
+
+func (r *awsEC2AttachmentRoute) MarshalJSON() ([]byte, error) {
+	type Properties awsEC2AttachmentRoute
+	return json.Marshal(&struct {
+		Type       string
+		Properties Properties
+		DependsOn  []string `json:",omitempty"`
+	}{
+		Type:       "AWS::EC2::Route",
+		Properties: Properties(*r),
+		DependsOn:  r.dependsOn,
+	})
+}
+
+// addResourcesForVPCAttachments attaches the VPC to the transit gateway and peers it with
+// the VPCs of the config, routing their destination CIDRs from the public route table and
+// the private route tables of all AZs
+func (c *ClusterResourceSet) addResourcesForVPCAttachments(refPublicRT *gfn.Value) {
+	vpc := c.spec.VPC
+	if vpc.TransitGatewayID == "" && len(vpc.Peering) == 0 {
+		return
+	}
+
+	routeTables := map[string]*gfn.Value{"Public": refPublicRT}
+	var privateSubnets []*gfn.Value
+	for _, az := range c.spec.AvailabilityZones {
+		alphanumericUpperAZ := strings.ToUpper(strings.Join(strings.Split(az, "-"), ""))
+		routeTables["Private"+alphanumericUpperAZ] = gfn.MakeRef("PrivateRouteTable" + alphanumericUpperAZ)
+		privateSubnets = append(privateSubnets, gfn.MakeRef("SubnetPrivate"+alphanumericUpperAZ))
+	}
+	var routeTableAliases []string
+	for alias := range routeTables {
+		routeTableAliases = append(routeTableAliases, alias)
+	}
+	sort.Strings(routeTableAliases)
+
+	addRoutes := func(prefix string, cidrs []string, route func(*awsEC2AttachmentRoute)) {
+		for _, alias := range routeTableAliases {
+			for i, cidr := range cidrs {
+				r := &awsEC2AttachmentRoute{
+					RouteTableId:         routeTables[alias],
+					DestinationCidrBlock: gfn.NewString(cidr),
+				}
+				route(r)
+				c.newResource(fmt.Sprintf("%sRoute%s%d", prefix, alias, i), r)
+			}
+		}
+	}
+
+	if vpc.TransitGatewayID != "" {
+		// one subnet per AZ, the private ones as nodes are usually there
+		c.newTaggedResource("TransitGatewayAttachment", &awsEC2TransitGatewayAttachment{
+			TransitGatewayId: gfn.NewString(vpc.TransitGatewayID),
+			VpcId:            c.vpc,
+			SubnetIds:        privateSubnets,
+			Tags:             makeTags(vpc.TransitGatewayTags),
+		}, vpc.TransitGatewayTags)
+		addRoutes("TransitGateway", vpc.TransitGatewayCIDRs, func(r *awsEC2AttachmentRoute) {
+			r.TransitGatewayId = gfn.NewString(vpc.TransitGatewayID)
+			// routes to a transit gateway fail until the VPC is attached to it
+			r.dependsOn = []string{"TransitGatewayAttachment"}
+		})
+	}
+
+	for i, peering := range vpc.Peering {
+		connection := &awsEC2VPCPeeringConnection{
+			VpcId:     c.vpc,
+			PeerVpcId: gfn.NewString(peering.PeerVPCID),
+			Tags:      makeTags(peering.Tags),
+		}
+		if peering.PeerOwnerID != "" {
+			connection.PeerOwnerId = gfn.NewString(peering.PeerOwnerID)
+		}
+		if peering.PeerRegion != "" {
+			connection.PeerRegion = gfn.NewString(peering.PeerRegion)
+		}
+		if peering.PeerRoleARN != "" {
+			connection.PeerRoleArn = gfn.NewString(peering.PeerRoleARN)
+		}
+		refConnection := c.newTaggedResource(fmt.Sprintf("VPCPeeringConnection%d", i), connection, peering.Tags)
+		addRoutes(fmt.Sprintf("VPCPeering%d", i), peering.DestinationCIDRs, func(r *awsEC2AttachmentRoute) {
+			r.VpcPeeringConnectionId = refConnection
+		})
+	}
+}
+
+// newTaggedResource adds a resource with the tags of the config, a Name tag among them replaces
+// the one newResource adds
+func (c *ClusterResourceSet) newTaggedResource(name string, resource interface{}, tags map[string]string) *gfn.Value {
+	if _, ok := tags["Name"]; !ok {
+		return c.newResource(name, resource)
+	}
+	c.rs.template.Resources[name] = resource
+	return gfn.MakeRef(name)
+}
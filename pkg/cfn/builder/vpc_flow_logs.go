@@ -0,0 +1,82 @@
+package builder
+
+import (
+	gfn "github.com/awslabs/goformation/cloudformation"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	cft "github.com/weaveworks/eksctl/pkg/cfn/template"
+)
+
+const vpcFlowLogsDestinationResource = "FlowLogsDestination"
+
+// addResourcesForVPCFlowLogs adds the flow log of the VPC, along with the log group and the role
+// it publishes to or the bucket it delivers to
+func (c *ClusterResourceSet) addResourcesForVPCFlowLogs() {
+	flowLogs := c.spec.VPC.FlowLogs
+	properties := map[string]interface{}{
+		"ResourceId":   c.vpc,
+		"ResourceType": "VPC",
+		"TrafficType":  flowLogs.TrafficType,
+	}
+	if flowLogs.AggregationInterval != nil {
+		properties["MaxAggregationInterval"] = *flowLogs.AggregationInterval
+	}
+
+	switch flowLogs.Destination {
+	case api.VPCFlowLogsDestinationCloudWatch:
+		refLogGroup := c.newResource(vpcFlowLogsDestinationResource, &awsCloudFormationResource{
+			Type:       "AWS::Logs::LogGroup",
+			Properties: map[string]interface{}{},
+		})
+
+		c.rs.withIAM = true
+		refRole := c.newResource("FlowLogsRole", &gfn.AWSIAMRole{
+			AssumeRolePolicyDocument: cft.MakeAssumeRolePolicyDocumentForServices(
+				MakeServiceRef("VPCFlowLogs"),
+			),
+		})
+		c.rs.attachAllowPolicy("PolicyFlowLogs", refRole, gfn.MakeFnGetAttString(vpcFlowLogsDestinationResource+".Arn"), []string{
+			"logs:CreateLogStream",
+			"logs:PutLogEvents",
+			"logs:DescribeLogGroups",
+			"logs:DescribeLogStreams",
+		})
+
+		properties["LogDestinationType"] = "cloud-watch-logs"
+		properties["LogGroupName"] = refLogGroup
+		properties["DeliverLogsPermissionArn"] = gfn.MakeFnGetAttString("FlowLogsRole.Arn")
+
+	case api.VPCFlowLogsDestinationS3:
+		// the bucket is retained, as the stack can't delete it once it holds flow logs;
+		// the flow logs service adds the bucket policy that allows it to deliver them
+		c.newResource(vpcFlowLogsDestinationResource, &awsCloudFormationResource{
+			Type: "AWS::S3::Bucket",
+			Properties: map[string]interface{}{
+				"BucketEncryption": map[string]interface{}{
+					"ServerSideEncryptionConfiguration": []interface{}{
+						map[string]interface{}{
+							"ServerSideEncryptionByDefault": map[string]string{
+								"SSEAlgorithm": "AES256",
+							},
+						},
+					},
+				},
+				"PublicAccessBlockConfiguration": map[string]bool{
+					"BlockPublicAcls":       true,
+					"BlockPublicPolicy":     true,
+					"IgnorePublicAcls":      true,
+					"RestrictPublicBuckets": true,
+				},
+			},
+			DeletionPolicy: "Retain",
+		})
+
+		properties["LogDestinationType"] = "s3"
+		properties["LogDestination"] = gfn.MakeFnGetAttString(vpcFlowLogsDestinationResource + ".Arn")
+	}
+
+	c.newResource("FlowLogs", &awsCloudFormationResource{
+		Type:       "AWS::EC2::FlowLog",
+		Properties: properties,
+	})
+}
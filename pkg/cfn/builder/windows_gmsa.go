@@ -0,0 +1,39 @@
+package builder
+
+import (
+	gfn "github.com/awslabs/goformation/cloudformation"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+const (
+	iamPolicyAmazonSSMManagedInstanceCore    = "AmazonSSMManagedInstanceCore"
+	iamPolicyAmazonSSMDirectoryServiceAccess = "AmazonSSMDirectoryServiceAccess"
+)
+
+// addResourcesForWindowsGMSA allows the CCG plugin of the nodes to read the credentials of the
+// domain user in domainless mode, and Systems Manager to join the nodes to an AWS Directory
+// Service domain in domain-joined mode
+func (n *NodeGroupResourceSet) addResourcesForWindowsGMSA() {
+	gmsa := n.spec.GMSA
+	refIR := gfn.MakeRef(cfnIAMInstanceRoleName)
+
+	if gmsa.DomainlessSecretARN != "" {
+		n.rs.attachAllowPolicy("PolicyGMSASecret", refIR, gmsa.DomainlessSecretARN, []string{
+			"secretsmanager:GetSecretValue",
+		})
+		if gmsa.SecretKMSKeyARN != "" {
+			n.rs.attachAllowPolicy("PolicyGMSASecretKMS", refIR, gmsa.SecretKMSKeyARN, []string{
+				"kms:Decrypt",
+			})
+		}
+	}
+
+	if api.IsEnabled(gmsa.DirectoryServiceAccess) {
+		role := n.rs.template.Resources[cfnIAMInstanceRoleName].(*gfn.AWSIAMRole)
+		role.ManagedPolicyArns = append(role.ManagedPolicyArns, makePolicyARNs(
+			iamPolicyAmazonSSMManagedInstanceCore,
+			iamPolicyAmazonSSMDirectoryServiceAccess,
+		)...)
+	}
+}
@@ -0,0 +1,109 @@
+package manager
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/builder"
+	iamoidc "github.com/weaveworks/eksctl/pkg/iam/oidc"
+)
+
+// makeAddonStackName generates the name of the stack of the IAM role of an EKS add-on
+func (c *StackCollection) makeAddonStackName(addonName string) string {
+	return fmt.Sprintf("eksctl-%s-addon-%s", c.spec.Metadata.Name, addonName)
+}
+
+// CreateAddonRole creates the stack of the IAM role of the service account of an EKS add-on,
+// as defined by sa, and returns the ARN of the role
+func (c *StackCollection) CreateAddonRole(addonName string, sa *api.ClusterIAMServiceAccount, oidc *iamoidc.OpenIDConnectManager) (string, error) {
+	name := c.makeAddonStackName(addonName)
+	logger.Info("building IAM role stack %q of EKS add-on %q", name, addonName)
+	stack := builder.NewIAMServiceAccountResourceSet(sa, c.spec.Metadata.Name, oidc)
+	if err := stack.AddAllResources(); err != nil {
+		return "", err
+	}
+
+	errs := make(chan error)
+	addonStacks := c.forPhase(api.TimeoutPhaseAddons)
+	if err := addonStacks.CreateStack(name, stack, map[string]string{api.AddonNameTag: addonName}, nil, errs); err != nil {
+		return "", err
+	}
+	if err := <-errs; err != nil {
+		return "", err
+	}
+	return aws.StringValue(sa.Status.RoleARN), nil
+}
+
+// DeleteAddonRole deletes the stack of the IAM role eksctl created for an EKS add-on, it does
+// nothing if there's no such stack
+func (c *StackCollection) DeleteAddonRole(addonName string, wait bool) error {
+	name := c.makeAddonStackName(addonName)
+	stacks, err := c.ListStacksMatching(fmt.Sprintf("^%s$", regexp.QuoteMeta(name)))
+	if err != nil {
+		return err
+	}
+	if len(stacks) == 0 {
+		return nil
+	}
+	if !wait {
+		_, err := c.DeleteStackBySpec(stacks[0])
+		return err
+	}
+	errs := make(chan error)
+	if err := c.DeleteStackBySpecSync(stacks[0], errs); err != nil {
+		return err
+	}
+	return <-errs
+}
+
+// DescribeAddonRoleStacks returns the stacks of the IAM roles eksctl created for EKS add-ons
+func (c *StackCollection) DescribeAddonRoleStacks() ([]*Stack, error) {
+	stacks, err := c.DescribeStacks()
+	if err != nil {
+		return nil, err
+	}
+
+	var addonStacks []*Stack
+	for _, s := range stacks {
+		if *s.StackStatus == cfn.StackStatusDeleteComplete {
+			continue
+		}
+		if GetAddonName(s) != "" {
+			addonStacks = append(addonStacks, s)
+		}
+	}
+	return addonStacks, nil
+}
+
+// GetAddonRoleARNs returns the ARNs of the IAM roles eksctl created for EKS add-ons, by name of
+// the add-on
+func (c *StackCollection) GetAddonRoleARNs() (map[string]string, error) {
+	stacks, err := c.DescribeAddonRoleStacks()
+	if err != nil {
+		return nil, err
+	}
+
+	roleARNs := map[string]string{}
+	for _, s := range stacks {
+		for _, output := range s.Outputs {
+			if aws.StringValue(output.OutputKey) == "Role1" {
+				roleARNs[GetAddonName(s)] = aws.StringValue(output.OutputValue)
+			}
+		}
+	}
+	return roleARNs, nil
+}
+
+// GetAddonName returns the name of the EKS add-on whose IAM role the stack defines
+func GetAddonName(s *Stack) string {
+	for _, tag := range s.Tags {
+		if *tag.Key == api.AddonNameTag {
+			return *tag.Value
+		}
+	}
+	return ""
+}
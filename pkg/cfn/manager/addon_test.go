@@ -0,0 +1,75 @@
+package manager
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("StackCollection add-on roles", func() {
+	var (
+		sc *StackCollection
+		p  *mockprovider.MockProvider
+	)
+
+	newStack := func(name, status string, tags ...*cfn.Tag) *cfn.Stack {
+		return &cfn.Stack{
+			StackName:   aws.String(name),
+			StackId:     aws.String("arn:aws:cloudformation:us-west-2:123456789012:stack/" + name + "/1"),
+			StackStatus: aws.String(status),
+			Tags:        append(tags, &cfn.Tag{Key: aws.String(api.ClusterNameTag), Value: aws.String("test-cluster")}),
+		}
+	}
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = "test-cluster"
+		cfg.Metadata.Region = "us-west-2"
+		sc = NewStackCollection(p, cfg)
+
+		ebsStack := newStack("eksctl-test-cluster-addon-aws-ebs-csi-driver", cfn.StackStatusCreateComplete,
+			&cfn.Tag{Key: aws.String(api.AddonNameTag), Value: aws.String(api.EBSCSIDriverAddon)})
+		ebsStack.Outputs = []*cfn.Output{{
+			OutputKey:   aws.String("Role1"),
+			OutputValue: aws.String("arn:aws:iam::123456789012:role/ebs-csi"),
+		}}
+		stacks := []*cfn.Stack{
+			newStack("eksctl-test-cluster-cluster", cfn.StackStatusCreateComplete),
+			newStack("eksctl-test-cluster-addon-iamserviceaccount-default-sa-1", cfn.StackStatusCreateComplete,
+				&cfn.Tag{Key: aws.String(api.IAMServiceAccountNameTag), Value: aws.String("default/sa-1")}),
+			ebsStack,
+		}
+		p.MockCloudFormation().On("ListStacksPages", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			consume := args[1].(func(p *cfn.ListStacksOutput, last bool) (shouldContinue bool))
+			out := &cfn.ListStacksOutput{}
+			for _, s := range stacks {
+				out.StackSummaries = append(out.StackSummaries, &cfn.StackSummary{StackName: s.StackName, StackId: s.StackId})
+			}
+			consume(out, true)
+		}).Return(nil)
+		for _, s := range stacks {
+			stack := s
+			p.MockCloudFormation().On("DescribeStacks", mock.MatchedBy(func(input *cfn.DescribeStacksInput) bool {
+				return *input.StackName == *stack.StackId
+			})).Return(&cfn.DescribeStacksOutput{Stacks: []*cfn.Stack{stack}}, nil)
+		}
+	})
+
+	It("returns the roles eksctl created for add-ons", func() {
+		roleARNs, err := sc.GetAddonRoleARNs()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(roleARNs).To(Equal(map[string]string{api.EBSCSIDriverAddon: "arn:aws:iam::123456789012:role/ebs-csi"}))
+	})
+
+	It("deletes the roles of add-ons", func() {
+		tasks, err := sc.NewTasksToDeleteAddonRoles(true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks.Describe()).To(Equal(`1 task: { delete IAM role of EKS add-on "aws-ebs-csi-driver" }`))
+	})
+})
@@ -9,7 +9,6 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/cloudtrail"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
@@ -42,11 +41,21 @@ type ChangeSet = cloudformation.DescribeChangeSetOutput
 
 // StackCollection stores the CloudFormation stack information
 type StackCollection struct {
-	provider   api.ClusterProvider
-	spec       *api.ClusterConfig
-	sharedTags []*cloudformation.Tag
+	provider          api.ClusterProvider
+	spec              *api.ClusterConfig
+	sharedTags        []*cloudformation.Tag
+	changeSetReviewer ChangeSetReviewer
+	rawAPIOverrides   *builder.RawAPIOverrides
+	accountProvider   AccountProvider
+	// timeoutPhase is the phase whose timeout the waits for stacks use, the overall timeout
+	// when empty
+	timeoutPhase api.TimeoutPhase
 }
 
+// AccountProvider returns the services of the account of a nodegroup created in another account
+// than the control plane
+type AccountProvider func(*api.NodeGroupAccount) (api.ClusterProvider, error)
+
 func newTag(key, value string) *cloudformation.Tag {
 	return &cloudformation.Tag{Key: &key, Value: &value}
 }
@@ -68,6 +77,40 @@ func NewStackCollection(provider api.ClusterProvider, spec *api.ClusterConfig) *
 	}
 }
 
+// SetRawAPIOverrides merges overrides into the control plane and managed nodegroups of the
+// stacks created from now on
+func (c *StackCollection) SetRawAPIOverrides(overrides *builder.RawAPIOverrides) {
+	c.rawAPIOverrides = overrides
+}
+
+// SetAccountProvider sets how the stacks of the nodegroups in other accounts than the control
+// plane reach their account
+func (c *StackCollection) SetAccountProvider(accountProvider AccountProvider) {
+	c.accountProvider = accountProvider
+}
+
+// forNodeGroupAccount returns a StackCollection managing the stacks of the cluster in the account
+// of a nodegroup
+func (c *StackCollection) forNodeGroupAccount(ng *api.NodeGroup) (*StackCollection, error) {
+	if c.accountProvider == nil {
+		return nil, fmt.Errorf("nodegroup %q can't be managed in account of role %q", ng.Name, ng.Account.RoleARN)
+	}
+	provider, err := c.accountProvider(ng.Account)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting credentials for the account of nodegroup %q", ng.Name)
+	}
+	accountStacks := *c
+	accountStacks.provider = provider
+	return &accountStacks, nil
+}
+
+// forPhase returns a StackCollection waiting for stacks within the timeout of a phase
+func (c *StackCollection) forPhase(phase api.TimeoutPhase) *StackCollection {
+	phaseStacks := *c
+	phaseStacks.timeoutPhase = phase
+	return &phaseStacks
+}
+
 // DoCreateStackRequest requests the creation of a CloudFormation stack
 func (c *StackCollection) DoCreateStackRequest(i *Stack, templateBody []byte, tags, parameters map[string]string, withIAM bool, withNamedIAM bool) error {
 	input := &cloudformation.CreateStackInput{
@@ -100,6 +143,21 @@ func (c *StackCollection) DoCreateStackRequest(i *Stack, templateBody []byte, ta
 		input.Parameters = append(input.Parameters, p)
 	}
 
+	if cf := c.spec.CloudFormation; cf != nil {
+		if api.IsEnabled(cf.DisableRollback) {
+			input.SetDisableRollback(true)
+		}
+		if api.IsEnabled(cf.TerminationProtection) {
+			input.SetEnableTerminationProtection(true)
+		}
+		if api.IsEnabled(cf.ProtectClusterResources) && *i.StackName == c.makeClusterStackName() {
+			input.SetStackPolicyBody(clusterStackPolicy)
+		}
+	}
+	if api.IsEnabled(c.spec.Metadata.DeletionProtection) && *i.StackName == c.makeClusterStackName() {
+		input.SetEnableTerminationProtection(true)
+	}
+
 	logger.Debug("CreateStackInput = %#v", input)
 	s, err := c.provider.CloudFormation().CreateStack(input)
 	if err != nil {
@@ -133,9 +191,14 @@ func (c *StackCollection) CreateStack(name string, stack builder.ResourceSet, ta
 
 // UpdateStack will update a CloudFormation stack by creating and executing a ChangeSet
 func (c *StackCollection) UpdateStack(stackName, changeSetName, description string, template []byte, parameters map[string]string) error {
+	return c.updateStack(stackName, changeSetName, description, template, parameters, stackCapabilitiesIAM)
+}
+
+// updateStack is UpdateStack with the capabilities the template requires
+func (c *StackCollection) updateStack(stackName, changeSetName, description string, template []byte, parameters map[string]string, capabilities []*string) error {
 	logger.Info(description)
 	i := &Stack{StackName: &stackName}
-	if err := c.doCreateChangeSetRequest(i, changeSetName, description, template, parameters, true); err != nil {
+	if err := c.doCreateChangeSetRequest(i, changeSetName, description, template, parameters, capabilities); err != nil {
 		return err
 	}
 	if err := c.doWaitUntilChangeSetIsCreated(i, changeSetName); err != nil {
@@ -148,7 +211,14 @@ func (c *StackCollection) UpdateStack(stackName, changeSetName, description stri
 	if err != nil {
 		return err
 	}
-	logger.Debug("changes = %#v", changeSet.Changes)
+	logger.Info("changes to stack %q:\n%s", stackName, FormatChangeSet(changeSet))
+	if c.changeSetReviewer != nil {
+		approved, err := c.changeSetReviewer(stackName, changeSet)
+		if !approved || err != nil {
+			c.deleteChangeSet(stackName, changeSetName)
+			return err
+		}
+	}
 	if err := c.doExecuteChangeSet(stackName, changeSetName); err != nil {
 		logger.Warning("error executing Cloudformation changeSet %s in stack %s. Check the Cloudformation console for further details", changeSetName, stackName)
 		return err
@@ -342,6 +412,10 @@ func (c *StackCollection) DeleteStackByNameSync(name string, errs chan error) er
 	if err != nil {
 		return err
 	}
+	if i == nil {
+		close(errs)
+		return nil
+	}
 
 	logger.Info("waiting for stack %q to get deleted", *i.StackName)
 
@@ -362,6 +436,9 @@ func (c *StackCollection) DeleteStackBySpec(s *Stack) (*Stack, error) {
 				input = input.SetRoleARN(cfnRole)
 			}
 
+			if err := c.disableTerminationProtection(s); err != nil {
+				return nil, err
+			}
 			if _, err := c.provider.CloudFormation().DeleteStack(input); err != nil {
 				return nil, errors.Wrapf(err, "not able to delete stack %q", *s.StackName)
 			}
@@ -375,6 +452,22 @@ func (c *StackCollection) DeleteStackBySpec(s *Stack) (*Stack, error) {
 		fmt.Sprintf("%s:%s", api.ClusterNameTag, c.spec.Metadata.Name))
 }
 
+// disableTerminationProtection disables the termination protection of a stack eksctl is about to delete
+func (c *StackCollection) disableTerminationProtection(s *Stack) error {
+	if !aws.BoolValue(s.EnableTerminationProtection) {
+		return nil
+	}
+	input := &cloudformation.UpdateTerminationProtectionInput{
+		StackName:                   s.StackId,
+		EnableTerminationProtection: aws.Bool(false),
+	}
+	if _, err := c.provider.CloudFormation().UpdateTerminationProtection(input); err != nil {
+		return errors.Wrapf(err, "disabling termination protection of stack %q", *s.StackName)
+	}
+	logger.Info("disabled termination protection of stack %q", *s.StackName)
+	return nil
+}
+
 func matchesClusterName(key, value, name string) bool {
 	if key == api.ClusterNameTag && value == name {
 		return true
@@ -468,7 +561,7 @@ func (c *StackCollection) LookupCloudTrailEvents(i *Stack) ([]*cloudtrail.Event,
 }
 
 func (c *StackCollection) doCreateChangeSetRequest(i *Stack, changeSetName string, description string, templateBody []byte,
-	parameters map[string]string, withIAM bool) error {
+	parameters map[string]string, capabilities []*string) error {
 	input := &cloudformation.CreateChangeSetInput{
 		StackName:     i.StackName,
 		ChangeSetName: &changeSetName,
@@ -479,8 +572,8 @@ func (c *StackCollection) doCreateChangeSetRequest(i *Stack, changeSetName strin
 
 	input.SetTemplateBody(string(templateBody))
 
-	if withIAM {
-		input.SetCapabilities(stackCapabilitiesIAM)
+	if len(capabilities) > 0 {
+		input.SetCapabilities(capabilities)
 	}
 
 	if cfnRole := c.provider.CloudFormationRoleARN(); cfnRole != "" {
@@ -0,0 +1,101 @@
+package manager
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/pkg/errors"
+)
+
+// ChangeSetReviewer is called with the ChangeSet of a stack update before it's executed, and
+// returns whether to execute it; the ChangeSet is deleted when it returns false
+type ChangeSetReviewer func(stackName string, changeSet *ChangeSet) (bool, error)
+
+// SetChangeSetReviewer makes stack updates ask reviewer before executing their ChangeSets
+func (c *StackCollection) SetChangeSetReviewer(reviewer ChangeSetReviewer) {
+	c.changeSetReviewer = reviewer
+}
+
+// PreviewStackUpdate creates a ChangeSet for the update of a stack, logs its changes and
+// deletes it, without updating the stack
+func (c *StackCollection) PreviewStackUpdate(stackName, changeSetName, description string, template []byte, parameters map[string]string) error {
+	logger.Info("(plan) %s", description)
+	i := &Stack{StackName: &stackName}
+	if err := c.doCreateChangeSetRequest(i, changeSetName, description, template, parameters, stackCapabilitiesIAM); err != nil {
+		return err
+	}
+	defer c.deleteChangeSet(stackName, changeSetName)
+	if err := c.doWaitUntilChangeSetIsCreated(i, changeSetName); err != nil {
+		if _, ok := err.(*noChangeError); ok {
+			logger.Info("(plan) no changes to stack %q", stackName)
+			return nil
+		}
+		return err
+	}
+	changeSet, err := c.DescribeStackChangeSet(i, changeSetName)
+	if err != nil {
+		return err
+	}
+	logger.Info("(plan) changes to stack %q:\n%s", stackName, FormatChangeSet(changeSet))
+	return nil
+}
+
+func (c *StackCollection) deleteChangeSet(stackName, changeSetName string) {
+	input := &cloudformation.DeleteChangeSetInput{
+		StackName:     &stackName,
+		ChangeSetName: &changeSetName,
+	}
+	if _, err := c.provider.CloudFormation().DeleteChangeSet(input); err != nil {
+		logger.Warning("%s", errors.Wrapf(err, "deleting ChangeSet %q of stack %q", changeSetName, stackName).Error())
+	}
+}
+
+// FormatChangeSet renders the resource-level changes of a ChangeSet as a table, showing which
+// resources are added, modified or removed, and whether modified resources are replaced
+func FormatChangeSet(changeSet *ChangeSet) string {
+	if len(changeSet.Changes) == 0 {
+		return "\tno resource changes"
+	}
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "\tACTION\tRESOURCE\tTYPE\tREPLACEMENT\tCHANGED")
+	for _, change := range changeSet.Changes {
+		rc := change.ResourceChange
+		if rc == nil {
+			continue
+		}
+		replacement := "-"
+		if aws.StringValue(rc.Action) == cloudformation.ChangeActionModify {
+			replacement = aws.StringValue(rc.Replacement)
+		}
+		fmt.Fprintf(w, "\t%s\t%s\t%s\t%s\t%s\n", aws.StringValue(rc.Action), aws.StringValue(rc.LogicalResourceId),
+			aws.StringValue(rc.ResourceType), replacement, changedProperties(rc))
+	}
+	_ = w.Flush()
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func changedProperties(rc *cloudformation.ResourceChange) string {
+	var names []string
+	seen := map[string]bool{}
+	for _, detail := range rc.Details {
+		if detail.Target == nil {
+			continue
+		}
+		name := aws.StringValue(detail.Target.Name)
+		if name == "" {
+			name = aws.StringValue(detail.Target.Attribute)
+		}
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "-"
+	}
+	return strings.Join(names, ",")
+}
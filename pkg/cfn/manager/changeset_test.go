@@ -0,0 +1,46 @@
+package manager
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FormatChangeSet", func() {
+	It("renders the resource-level changes", func() {
+		changeSet := &ChangeSet{
+			Changes: []*cfn.Change{
+				{
+					ResourceChange: &cfn.ResourceChange{
+						Action:            aws.String(cfn.ChangeActionAdd),
+						LogicalResourceId: aws.String("IngressNodeToDefaultClusterSG"),
+						ResourceType:      aws.String("AWS::EC2::SecurityGroupIngress"),
+					},
+				},
+				{
+					ResourceChange: &cfn.ResourceChange{
+						Action:            aws.String(cfn.ChangeActionModify),
+						LogicalResourceId: aws.String("ManagedNodeGroup"),
+						ResourceType:      aws.String("AWS::EKS::Nodegroup"),
+						Replacement:       aws.String(cfn.ReplacementTrue),
+						Details: []*cfn.ResourceChangeDetail{
+							{Target: &cfn.ResourceTargetDefinition{Attribute: aws.String("Properties"), Name: aws.String("ReleaseVersion")}},
+							{Target: &cfn.ResourceTargetDefinition{Attribute: aws.String("Properties"), Name: aws.String("Labels")}},
+							{Target: &cfn.ResourceTargetDefinition{Attribute: aws.String("Properties"), Name: aws.String("Labels")}},
+						},
+					},
+				},
+			},
+		}
+
+		lines := FormatChangeSet(changeSet)
+		Expect(lines).To(MatchRegexp(`ACTION\s+RESOURCE\s+TYPE\s+REPLACEMENT\s+CHANGED`))
+		Expect(lines).To(MatchRegexp(`Add\s+IngressNodeToDefaultClusterSG\s+AWS::EC2::SecurityGroupIngress\s+-\s+-`))
+		Expect(lines).To(MatchRegexp(`Modify\s+ManagedNodeGroup\s+AWS::EKS::Nodegroup\s+True\s+ReleaseVersion,Labels`))
+	})
+
+	It("renders ChangeSets without changes", func() {
+		Expect(FormatChangeSet(&ChangeSet{})).To(Equal("\tno resource changes"))
+	})
+})
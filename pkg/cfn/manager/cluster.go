@@ -5,7 +5,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
@@ -22,6 +21,25 @@ func (c *StackCollection) MakeChangeSetName(action string) string {
 	return fmt.Sprintf("eksctl-%s-%d", action, time.Now().Unix())
 }
 
+// clusterStackPolicy forbids stack updates from replacing or deleting the VPC and the control
+// plane, which would destroy the cluster
+const clusterStackPolicy = `{
+  "Statement": [
+    {
+      "Effect": "Deny",
+      "Action": ["Update:Replace", "Update:Delete"],
+      "Principal": "*",
+      "Resource": ["LogicalResourceId/ControlPlane", "LogicalResourceId/VPC"]
+    },
+    {
+      "Effect": "Allow",
+      "Action": "Update:*",
+      "Principal": "*",
+      "Resource": "*"
+    }
+  ]
+}`
+
 func (c *StackCollection) makeClusterStackName() string {
 	return "eksctl-" + c.spec.Metadata.Name + "-cluster"
 }
@@ -31,20 +49,53 @@ func (c *StackCollection) createClusterTask(errs chan error, supportsManagedNode
 	name := c.makeClusterStackName()
 	logger.Info("building cluster stack %q", name)
 	stack := builder.NewClusterResourceSet(c.provider, c.spec, supportsManagedNodes, nil)
+	if c.rawAPIOverrides != nil {
+		stack.SetRawAPIOverrides(c.rawAPIOverrides.CreateCluster)
+	}
 	if err := stack.AddAllResources(); err != nil {
 		return err
 	}
 
 	// Unlike with `createNodeGroupTask`, all tags are already set for the cluster stack
-	return c.CreateStack(name, stack, nil, nil, errs)
+	var tags map[string]string
+	if api.IsEnabled(c.spec.Metadata.DeletionProtection) {
+		tags = map[string]string{api.DeletionProtectionTag: "true"}
+	}
+	return c.CreateStack(name, stack, tags, nil, errs)
+}
+
+// HasDeletionProtection tells whether the cluster was created with deletion protection
+func (c *StackCollection) HasDeletionProtection() (bool, error) {
+	stack, err := c.GetClusterStackIfExists()
+	if err != nil || stack == nil {
+		return false, err
+	}
+	for _, tag := range stack.Tags {
+		if *tag.Key == api.DeletionProtectionTag {
+			return *tag.Value == "true", nil
+		}
+	}
+	return false, nil
 }
 
 // DescribeClusterStack calls DescribeStacks and filters out cluster stack
 func (c *StackCollection) DescribeClusterStack() (*Stack, error) {
-	stacks, err := c.DescribeStacks()
+	stack, err := c.GetClusterStackIfExists()
 	if err != nil {
 		return nil, err
 	}
+	if stack == nil {
+		return nil, c.errStackNotFound()
+	}
+	return stack, nil
+}
+
+// GetClusterStackIfExists returns the cluster stack owned by eksctl, or nil if it doesn't exist
+func (c *StackCollection) GetClusterStackIfExists() (*Stack, error) {
+	stacks, err := c.ListStacks()
+	if err != nil {
+		return nil, errors.Wrapf(err, "describing CloudFormation stacks for %q", c.spec.Metadata.Name)
+	}
 
 	for _, s := range stacks {
 		if *s.StackStatus == cfn.StackStatusDeleteComplete {
@@ -54,7 +105,7 @@ func (c *StackCollection) DescribeClusterStack() (*Stack, error) {
 			return s, nil
 		}
 	}
-	return nil, c.errStackNotFound()
+	return nil, nil
 }
 
 // RefreshFargatePodExecutionRoleARN reads the CloudFormation stacks and
@@ -89,6 +140,26 @@ func (c *StackCollection) RefreshFargatePodExecutionRoleARN() error {
 	return outputs.Collect(*stack, fargateOutputs, nil)
 }
 
+// GetNodeTerminationHandlerQueueURL reads the URL of the queue that AWS Node Termination Handler
+// processes in queue mode from the cluster stack outputs
+func (c *StackCollection) GetNodeTerminationHandlerQueueURL() (string, error) {
+	stack, err := c.DescribeClusterStack()
+	if err != nil {
+		return "", err
+	}
+	var queueURL string
+	queueOutputs := map[string]outputs.Collector{
+		outputs.ClusterNodeTerminationHandlerQueueURL: func(v string) error {
+			queueURL = v
+			return nil
+		},
+	}
+	if err := outputs.Collect(*stack, queueOutputs, nil); err != nil {
+		return "", errors.Wrap(err, "cluster stack has no Node Termination Handler queue, it can be added with 'eksctl update cluster'")
+	}
+	return queueURL, nil
+}
+
 // AppendNewClusterStackResource will update cluster
 // stack with new resources in append-only way
 func (c *StackCollection) AppendNewClusterStackResource(plan, supportsManagedNodes bool) (bool, error) {
@@ -180,8 +251,7 @@ func (c *StackCollection) AppendNewClusterStackResource(plan, supportsManagedNod
 
 	describeUpdate := fmt.Sprintf("updating stack to add new resources %v and outputs %v", addResources, addOutputs)
 	if plan {
-		logger.Info("(plan) %s", describeUpdate)
-		return false, nil
+		return true, c.PreviewStackUpdate(name, c.MakeChangeSetName("update-cluster"), describeUpdate, []byte(currentTemplate), nil)
 	}
 	return true, c.UpdateStack(name, c.MakeChangeSetName("update-cluster"), describeUpdate, []byte(currentTemplate), nil)
 }
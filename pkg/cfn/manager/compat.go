@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	gfn "github.com/awslabs/goformation/cloudformation"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
@@ -18,11 +18,23 @@ func (c *StackCollection) NewTasksToCreateClusterWithNodeGroups(nodeGroups []*ap
 	tasks.Append(
 		&createClusterTask{
 			info:                 fmt.Sprintf("create cluster control plane %q", c.spec.Metadata.Name),
-			stackCollection:      c,
+			stackCollection:      c.forPhase(api.TimeoutPhaseControlPlane),
 			supportsManagedNodes: supportsManagedNodes,
 		},
 	)
 
+	nodeGroupTasks := c.NewTasksToCreateAllNodeGroups(nodeGroups, managedNodeGroups, supportsManagedNodes)
+	if nodeGroupTasks.Len() > 0 {
+		nodeGroupTasks.IsSubTask = true
+		tasks.Append(nodeGroupTasks)
+	}
+
+	return tasks
+}
+
+// NewTasksToCreateAllNodeGroups defines tasks required to create both unmanaged and managed
+// nodegroups, in batches when cloudFormation.nodeGroupBatchSize is set
+func (c *StackCollection) NewTasksToCreateAllNodeGroups(nodeGroups []*api.NodeGroup, managedNodeGroups []*api.ManagedNodeGroup, supportsManagedNodes bool) *TaskTree {
 	nodeGroupTasks := c.NewTasksToCreateNodeGroups(nodeGroups, supportsManagedNodes)
 
 	managedNodeGroupTasks := c.NewManagedNodeGroupTask(managedNodeGroups)
@@ -30,23 +42,28 @@ func (c *StackCollection) NewTasksToCreateClusterWithNodeGroups(nodeGroups []*ap
 		nodeGroupTasks.Append(managedNodeGroupTasks.tasks...)
 	}
 
-	if nodeGroupTasks.Len() > 0 {
-		nodeGroupTasks.IsSubTask = true
-		tasks.Append(nodeGroupTasks)
-	}
+	return nodeGroupTasks.batched(c.nodeGroupBatchSize())
+}
 
-	return tasks
+// nodeGroupBatchSize returns how many nodegroup stacks may be created or deleted at the same
+// time, 0 means no limit
+func (c *StackCollection) nodeGroupBatchSize() int {
+	if c.spec.CloudFormation == nil || c.spec.CloudFormation.NodeGroupBatchSize == nil {
+		return 0
+	}
+	return *c.spec.CloudFormation.NodeGroupBatchSize
 }
 
 // NewTasksToCreateNodeGroups defines tasks required to create all of the nodegroups
 func (c *StackCollection) NewTasksToCreateNodeGroups(nodeGroups []*api.NodeGroup, supportsManagedNodes bool) *TaskTree {
 	tasks := &TaskTree{Parallel: true}
+	phaseStacks := c.forPhase(api.TimeoutPhaseNodeGroups)
 
 	for _, ng := range nodeGroups {
 		tasks.Append(&nodeGroupTask{
 			info:                 fmt.Sprintf("create nodegroup %q", ng.NameString()),
 			nodeGroup:            ng,
-			stackCollection:      c,
+			stackCollection:      phaseStacks,
 			supportsManagedNodes: supportsManagedNodes,
 		})
 		// TODO: move authconfigmap tasks here using kubernetesTask and kubernetes.CallbackClientSet
@@ -58,9 +75,10 @@ func (c *StackCollection) NewTasksToCreateNodeGroups(nodeGroups []*api.NodeGroup
 // NewManagedNodeGroupTask defines tasks required to create managed nodegroups
 func (c *StackCollection) NewManagedNodeGroupTask(nodeGroups []*api.ManagedNodeGroup) *TaskTree {
 	tasks := &TaskTree{Parallel: true}
+	phaseStacks := c.forPhase(api.TimeoutPhaseNodeGroups)
 	for _, ng := range nodeGroups {
 		tasks.Append(&managedNodeGroupTask{
-			stackCollection: c,
+			stackCollection: phaseStacks,
 			nodeGroup:       ng,
 			info:            fmt.Sprintf("create managed nodegroup %q", ng.Name),
 		})
@@ -77,9 +95,42 @@ func (c *StackCollection) NewClusterCompatTask() Task {
 	}
 }
 
-// NewTasksToCreateIAMServiceAccounts defines tasks required to create all of the IAM ServiceAccounts
+// defaultIAMServiceAccountConcurrency is how many iamserviceaccounts are created at the same
+// time when cloudFormation.iamServiceAccountConcurrency is unset
+const defaultIAMServiceAccountConcurrency = 10
+
+// iamServiceAccountConcurrency returns how many iamserviceaccounts may be created at the same time
+func (c *StackCollection) iamServiceAccountConcurrency() int {
+	if c.spec.CloudFormation == nil || c.spec.CloudFormation.IAMServiceAccountConcurrency == nil {
+		return defaultIAMServiceAccountConcurrency
+	}
+	return *c.spec.CloudFormation.IAMServiceAccountConcurrency
+}
+
+// NewTasksToCreateIAMServiceAccounts defines tasks required to create all of the IAM ServiceAccounts,
+// at most cloudFormation.iamServiceAccountConcurrency at the same time
 func (c *StackCollection) NewTasksToCreateIAMServiceAccounts(serviceAccounts []*api.ClusterIAMServiceAccount, oidc *iamoidc.OpenIDConnectManager, clientSetGetter kubernetes.ClientSetGetter) *TaskTree {
-	tasks := &TaskTree{Parallel: true}
+	return c.newTasksToCreateIAMServiceAccounts(serviceAccounts, oidc, clientSetGetter, nil)
+}
+
+// NewTasksToCreateOrAdoptIAMServiceAccounts is NewTasksToCreateIAMServiceAccounts for when the
+// stacks of some of the iamserviceaccounts may already exist, e.g. when a previous run failed;
+// those stacks are adopted and updated if the role changed, or re-created if their creation
+// failed, and the serviceaccounts are created or updated in any case
+func (c *StackCollection) NewTasksToCreateOrAdoptIAMServiceAccounts(serviceAccounts []*api.ClusterIAMServiceAccount, oidc *iamoidc.OpenIDConnectManager, clientSetGetter kubernetes.ClientSetGetter) (*TaskTree, error) {
+	stacks, err := c.DescribeIAMServiceAccountStacks()
+	if err != nil {
+		return nil, err
+	}
+	existingStacks := map[string]*Stack{}
+	for _, s := range stacks {
+		existingStacks[c.GetIAMServiceAccountName(s)] = s
+	}
+	return c.newTasksToCreateIAMServiceAccounts(serviceAccounts, oidc, clientSetGetter, existingStacks), nil
+}
+
+func (c *StackCollection) newTasksToCreateIAMServiceAccounts(serviceAccounts []*api.ClusterIAMServiceAccount, oidc *iamoidc.OpenIDConnectManager, clientSetGetter kubernetes.ClientSetGetter, existingStacks map[string]*Stack) *TaskTree {
+	tasks := &TaskTree{Parallel: true, Concurrency: c.iamServiceAccountConcurrency()}
 
 	for i := range serviceAccounts {
 		sa := serviceAccounts[i]
@@ -88,23 +139,59 @@ func (c *StackCollection) NewTasksToCreateIAMServiceAccounts(serviceAccounts []*
 			IsSubTask: true,
 		}
 
-		saTasks.Append(&taskWithClusterIAMServiceAccountSpec{
-			info:           fmt.Sprintf("create IAM role for serviceaccount %q", sa.NameString()),
-			serviceAccount: sa,
-			oidc:           oidc,
-			call:           c.createIAMServiceAccountTask,
-		})
+		if sa.AttachRoleARN != "" {
+			saTasks.Append(&taskWithClusterIAMServiceAccountSpec{
+				info:           fmt.Sprintf("attach IAM role %q to serviceaccount %q", sa.AttachRoleARN, sa.NameString()),
+				serviceAccount: sa,
+				oidc:           oidc,
+				call:           attachIAMServiceAccountRoleTask,
+			})
+		} else if s, ok := existingStacks[sa.NameString()]; ok {
+			saTasks.Append(&taskWithClusterIAMServiceAccountSpec{
+				info:           fmt.Sprintf("adopt IAM role stack of serviceaccount %q", sa.NameString()),
+				serviceAccount: sa,
+				oidc:           oidc,
+				call: func(errs chan error, sa *api.ClusterIAMServiceAccount, oidc *iamoidc.OpenIDConnectManager) error {
+					return c.adoptIAMServiceAccountTask(errs, sa, oidc, s)
+				},
+			})
+		} else {
+			saTasks.Append(&taskWithClusterIAMServiceAccountSpec{
+				info:           fmt.Sprintf("create IAM role for serviceaccount %q", sa.NameString()),
+				serviceAccount: sa,
+				oidc:           oidc,
+				call:           c.createIAMServiceAccountTask,
+			})
+		}
+
+		report := &reportingTask{Task: saTasks, subject: fmt.Sprintf("iamserviceaccount %q", sa.NameString())}
+
+		if api.IsEnabled(sa.RoleOnly) {
+			tasks.Append(report)
+			continue
+		}
 
 		saTasks.Append(&kubernetesTask{
 			info:       fmt.Sprintf("create serviceaccount %q", sa.NameString()),
 			kubernetes: clientSetGetter,
 			call: func(clientSet kubernetes.Interface) error {
+				if api.IsDisabled(sa.CreateNamespace) {
+					exists, err := kubernetes.CheckNamespaceExists(clientSet, sa.Namespace)
+					if err != nil {
+						return err
+					}
+					if !exists {
+						return fmt.Errorf("namespace %q of serviceaccount %q doesn't exist, and its creation is disabled", sa.Namespace, sa.NameString())
+					}
+				}
 				sa.SetAnnotations()
-				return kubernetes.MaybeCreateServiceAccountOrUpdateMetadata(clientSet, sa.ObjectMeta)
+				serviceAccount := kubernetes.NewServiceAccount(sa.ObjectMeta)
+				serviceAccount.AutomountServiceAccountToken = sa.AutomountServiceAccountToken
+				return kubernetes.MaybeCreateServiceAccountOrUpdate(clientSet, serviceAccount)
 			},
 		})
 
-		tasks.Append(saTasks)
+		tasks.Append(report)
 	}
 	return tasks
 }
@@ -8,13 +8,21 @@ import (
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	iamoidc "github.com/weaveworks/eksctl/pkg/iam/oidc"
 	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 func deleteAll(_ string) bool { return true }
 
-// NewTasksToDeleteClusterWithNodeGroups defines tasks required to delete the given cluster along with all of its resources
-func (c *StackCollection) NewTasksToDeleteClusterWithNodeGroups(deleteOIDCProvider bool, oidc *iamoidc.OpenIDConnectManager, clientSetGetter kubernetes.ClientSetGetter, wait bool, cleanup func(chan error, string) error) (*TaskTree, error) {
+// NewTasksToDeleteClusterWithNodeGroups defines tasks required to delete the given cluster along with all of its resources;
+// the nodegroups, the iamserviceaccounts and the roles of add-ons are deleted at the same time, at most parallelism stacks
+// of nodegroups and of iamserviceaccounts when it isn't 0, the IAM OIDC provider after the iamserviceaccounts and the roles
+// of add-ons, unless keepOIDCProvider is set, and the cluster stack last
+func (c *StackCollection) NewTasksToDeleteClusterWithNodeGroups(deleteOIDCProvider, keepOIDCProvider bool, oidc *iamoidc.OpenIDConnectManager, clientSetGetter kubernetes.ClientSetGetter, wait bool, parallelism int, cleanup func(chan error, string) error, concurrentTasks ...Task) (*TaskTree, error) {
 	tasks := &TaskTree{Parallel: false}
+	dependentTasks := &TaskTree{Parallel: true, IsSubTask: true}
+	// tasks deleting resources outside of the stacks, e.g. the Fargate profiles, run along with
+	// the deletion of the nodegroups
+	dependentTasks.Append(concurrentTasks...)
 
 	nodeGroupTasks, err := c.NewTasksToDeleteNodeGroups(deleteAll, true, cleanup)
 
@@ -23,19 +31,39 @@ func (c *StackCollection) NewTasksToDeleteClusterWithNodeGroups(deleteOIDCProvid
 	}
 	if nodeGroupTasks.Len() > 0 {
 		nodeGroupTasks.IsSubTask = true
-		tasks.Append(nodeGroupTasks)
+		nodeGroupTasks.Concurrency = parallelism
+		dependentTasks.Append(nodeGroupTasks)
+	}
+
+	addonRoleTasks, err := c.NewTasksToDeleteAddonRoles(wait)
+	if err != nil {
+		return nil, err
+	}
+	if addonRoleTasks.Len() > 0 {
+		addonRoleTasks.IsSubTask = true
 	}
 
 	if deleteOIDCProvider {
-		serviceAccountAndOIDCTasks, err := c.NewTasksToDeleteOIDCProviderWithIAMServiceAccounts(oidc, clientSetGetter)
+		// the add-ons keep using the IAM OIDC provider until their roles are deleted
+		serviceAccountAndOIDCTasks, err := c.NewTasksToDeleteOIDCProviderWithIAMServiceAccounts(oidc, clientSetGetter, parallelism, keepOIDCProvider, addonRoleTasks)
 		if err != nil {
 			return nil, err
 		}
 
 		if serviceAccountAndOIDCTasks.Len() > 0 {
 			serviceAccountAndOIDCTasks.IsSubTask = true
-			tasks.Append(serviceAccountAndOIDCTasks)
+			dependentTasks.Append(serviceAccountAndOIDCTasks)
 		}
+	} else if addonRoleTasks.Len() > 0 {
+		dependentTasks.Append(addonRoleTasks)
+	}
+
+	switch dependentTasks.Len() {
+	case 0:
+	case 1:
+		tasks.Append(dependentTasks.tasks[0])
+	default:
+		tasks.Append(dependentTasks)
 	}
 
 	clusterStack, err := c.DescribeClusterStack()
@@ -61,6 +89,33 @@ func (c *StackCollection) NewTasksToDeleteClusterWithNodeGroups(deleteOIDCProvid
 	return tasks, nil
 }
 
+// NewTasksToDeleteAddonRoles defines tasks deleting the IAM roles eksctl created for EKS add-ons
+func (c *StackCollection) NewTasksToDeleteAddonRoles(wait bool) (*TaskTree, error) {
+	addonStacks, err := c.DescribeAddonRoleStacks()
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := &TaskTree{Parallel: true}
+	for _, s := range addonStacks {
+		info := fmt.Sprintf("delete IAM role of EKS add-on %q", GetAddonName(s))
+		if wait {
+			tasks.Append(&taskWithStackSpec{
+				info:  info,
+				stack: s,
+				call:  c.DeleteStackBySpecSync,
+			})
+		} else {
+			tasks.Append(&asyncTaskWithStackSpec{
+				info:  info,
+				stack: s,
+				call:  c.DeleteStackBySpec,
+			})
+		}
+	}
+	return tasks, nil
+}
+
 // NewTasksToDeleteNodeGroups defines tasks required to delete all of the nodegroups
 func (c *StackCollection) NewTasksToDeleteNodeGroups(shouldDelete func(string) bool, wait bool, cleanup func(chan error, string) error) (*TaskTree, error) {
 	nodeGroupStacks, err := c.DescribeNodeGroupStacks()
@@ -76,35 +131,105 @@ func (c *StackCollection) NewTasksToDeleteNodeGroups(shouldDelete func(string) b
 		if !shouldDelete(name) {
 			continue
 		}
-		if *s.StackStatus == cloudformation.StackStatusDeleteFailed && cleanup != nil {
-			tasks.Append(&taskWithNameParam{
-				info: fmt.Sprintf("cleanup for nodegroup %q", name),
-				call: cleanup,
-			})
-		}
 		info := fmt.Sprintf("delete nodegroup %q", name)
+		var deleteTask Task
 		if wait {
-			tasks.Append(&taskWithStackSpec{
+			deleteTask = &taskWithStackSpec{
 				info:  info,
 				stack: s,
 				call:  c.DeleteStackBySpecSync,
-			})
+			}
 		} else {
-			tasks.Append(&asyncTaskWithStackSpec{
+			deleteTask = &asyncTaskWithStackSpec{
 				info:  info,
 				stack: s,
 				call:  c.DeleteStackBySpec,
+			}
+		}
+		if *s.StackStatus == cloudformation.StackStatusDeleteFailed && cleanup != nil {
+			// the resources blocking the previous deletion must be cleaned up before deleting again
+			tasks.Append(&TaskTree{
+				Parallel:  false,
+				IsSubTask: true,
+				tasks: []Task{
+					&taskWithNameParam{
+						info: fmt.Sprintf("cleanup for nodegroup %q", name),
+						name: name,
+						call: cleanup,
+					},
+					deleteTask,
+				},
 			})
+			continue
 		}
+		tasks.Append(deleteTask)
+	}
+
+	crossAccountTasks, err := c.newTasksToDeleteCrossAccountNodeGroups(shouldDelete)
+	if err != nil {
+		return nil, err
+	}
+	tasks.Append(crossAccountTasks...)
+
+	return tasks.batched(c.nodeGroupBatchSize()), nil
+}
+
+// newTasksToDeleteCrossAccountNodeGroups defines tasks deleting the nodegroups in other accounts
+// than the control plane, their access stack is deleted first, as it refers to their security group
+func (c *StackCollection) newTasksToDeleteCrossAccountNodeGroups(shouldDelete func(string) bool) ([]Task, error) {
+	accessStacks, err := c.DescribeNodeGroupAccessStacks()
+	if err != nil {
+		return nil, err
 	}
 
+	var tasks []Task
+	for _, s := range accessStacks {
+		name := getNodeGroupAccessName(s)
+		if !shouldDelete(name) {
+			continue
+		}
+		var ng *api.NodeGroup
+		for _, configNodeGroup := range c.spec.NodeGroups {
+			if configNodeGroup.Name == name {
+				ng = configNodeGroup
+			}
+		}
+		if ng == nil || ng.Account == nil {
+			return nil, fmt.Errorf("nodegroup %q belongs to another account, its account must be set in the config file to delete it", name)
+		}
+		accountStacks, err := c.forNodeGroupAccount(ng)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, &TaskTree{
+			Parallel:  false,
+			IsSubTask: true,
+			tasks: []Task{
+				&taskWithStackSpec{
+					info:  fmt.Sprintf("delete control plane access of nodegroup %q", name),
+					stack: s,
+					call:  c.DeleteStackBySpecSync,
+				},
+				&taskWithNameParam{
+					info: fmt.Sprintf("delete nodegroup %q in the account of role %q", name, ng.Account.RoleARN),
+					name: c.makeNodeGroupStackName(name),
+					call: func(errs chan error, stackName string) error {
+						return accountStacks.DeleteStackByNameSync(stackName, errs)
+					},
+				},
+			},
+		})
+	}
 	return tasks, nil
 }
 
-// NewTasksToDeleteOIDCProviderWithIAMServiceAccounts defines tasks required to delete all of the iamserviceaccounts
-// along with associated IAM ODIC provider
-func (c *StackCollection) NewTasksToDeleteOIDCProviderWithIAMServiceAccounts(oidc *iamoidc.OpenIDConnectManager, clientSetGetter kubernetes.ClientSetGetter) (*TaskTree, error) {
+// NewTasksToDeleteOIDCProviderWithIAMServiceAccounts defines tasks required to delete all of the iamserviceaccounts,
+// at most parallelism at the same time when it isn't 0, along with associated IAM ODIC provider unless keepProvider is set;
+// roleTasks, e.g. deleting the roles of add-ons, run along with the iamserviceaccounts, before the provider is deleted;
+// without oidc, e.g. when the provider of a cluster that can't be reached isn't found, only the stacks are deleted
+func (c *StackCollection) NewTasksToDeleteOIDCProviderWithIAMServiceAccounts(oidc *iamoidc.OpenIDConnectManager, clientSetGetter kubernetes.ClientSetGetter, parallelism int, keepProvider bool, roleTasks ...*TaskTree) (*TaskTree, error) {
 	tasks := &TaskTree{Parallel: false}
+	allRoleTasks := &TaskTree{Parallel: true, IsSubTask: true}
 
 	saTasks, err := c.NewTasksToDeleteIAMServiceAccounts(deleteAll, oidc, clientSetGetter, true)
 	if err != nil {
@@ -113,7 +238,25 @@ func (c *StackCollection) NewTasksToDeleteOIDCProviderWithIAMServiceAccounts(oid
 
 	if saTasks.Len() > 0 {
 		saTasks.IsSubTask = true
-		tasks.Append(saTasks)
+		saTasks.Concurrency = parallelism
+		allRoleTasks.Append(saTasks)
+	}
+	for _, t := range roleTasks {
+		if t.Len() > 0 {
+			allRoleTasks.Append(t)
+		}
+	}
+
+	switch allRoleTasks.Len() {
+	case 0:
+	case 1:
+		tasks.Append(allRoleTasks.tasks[0])
+	default:
+		tasks.Append(allRoleTasks)
+	}
+
+	if oidc == nil {
+		return tasks, nil
 	}
 
 	providerExists, err := oidc.CheckProviderExists()
@@ -122,6 +265,17 @@ func (c *StackCollection) NewTasksToDeleteOIDCProviderWithIAMServiceAccounts(oid
 	}
 
 	if providerExists {
+		if keepProvider {
+			logger.Info("keeping IAM OIDC provider %q", oidc.ProviderARN)
+			return tasks, nil
+		}
+		owned, err := oidc.IsProviderOwned()
+		if err != nil {
+			logger.Warning("unable to determine whether IAM OIDC provider %q was created by eksctl, deleting it: %s", oidc.ProviderARN, err.Error())
+		} else if !owned {
+			logger.Info("keeping IAM OIDC provider %q, which wasn't created by eksctl", oidc.ProviderARN)
+			return tasks, nil
+		}
 		tasks.Append(&asyncTaskWithoutParams{
 			info: "delete IAM OIDC provider",
 			call: oidc.DeleteProvider,
@@ -131,7 +285,8 @@ func (c *StackCollection) NewTasksToDeleteOIDCProviderWithIAMServiceAccounts(oid
 	return tasks, nil
 }
 
-// NewTasksToDeleteIAMServiceAccounts defines tasks required to delete all of the iamserviceaccounts
+// NewTasksToDeleteIAMServiceAccounts defines tasks required to delete all of the iamserviceaccounts;
+// the serviceaccounts are left in the cluster without clientSetGetter, e.g. when it can't be reached
 func (c *StackCollection) NewTasksToDeleteIAMServiceAccounts(shouldDelete func(string) bool, oidc *iamoidc.OpenIDConnectManager, clientSetGetter kubernetes.ClientSetGetter, wait bool) (*TaskTree, error) {
 	serviceAccountStacks, err := c.DescribeIAMServiceAccountStacks()
 	if err != nil {
@@ -165,6 +320,10 @@ func (c *StackCollection) NewTasksToDeleteIAMServiceAccounts(shouldDelete func(s
 				call:  c.DeleteStackBySpec,
 			})
 		}
+		if isIAMServiceAccountRoleOnly(s) || clientSetGetter == nil {
+			tasks.Append(saTasks)
+			continue
+		}
 		saTasks.Append(&kubernetesTask{
 			info:       fmt.Sprintf("delete serviceaccount %q", name),
 			kubernetes: clientSetGetter,
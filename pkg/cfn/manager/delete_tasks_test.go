@@ -0,0 +1,128 @@
+package manager
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/iam"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	iamoidc "github.com/weaveworks/eksctl/pkg/iam/oidc"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("StackCollection cluster deletion tasks", func() {
+	var (
+		sc   *StackCollection
+		p    *mockprovider.MockProvider
+		oidc *iamoidc.OpenIDConnectManager
+	)
+
+	newStack := func(name string, tags ...*cfn.Tag) *cfn.Stack {
+		return &cfn.Stack{
+			StackName:   aws.String(name),
+			StackId:     aws.String("arn:aws:cloudformation:us-west-2:123456789012:stack/" + name + "/1"),
+			StackStatus: aws.String(cfn.StackStatusCreateComplete),
+			Tags:        append(tags, &cfn.Tag{Key: aws.String(api.ClusterNameTag), Value: aws.String("test-cluster")}),
+		}
+	}
+
+	tag := func(key, value string) *cfn.Tag {
+		return &cfn.Tag{Key: aws.String(key), Value: aws.String(value)}
+	}
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = "test-cluster"
+		cfg.Metadata.Region = "us-west-2"
+		sc = NewStackCollection(p, cfg)
+
+		stacks := []*cfn.Stack{
+			newStack("eksctl-test-cluster-cluster"),
+			newStack("eksctl-test-cluster-nodegroup-ng-1", tag(api.NodeGroupNameTag, "ng-1")),
+			newStack("eksctl-test-cluster-nodegroup-ng-2", tag(api.NodeGroupNameTag, "ng-2")),
+			newStack("eksctl-test-cluster-addon-iamserviceaccount-default-sa-1",
+				tag(api.IAMServiceAccountNameTag, "default/sa-1"), tag(api.IAMServiceAccountRoleOnlyTag, "true")),
+			newStack("eksctl-test-cluster-addon-aws-ebs-csi-driver", tag(api.AddonNameTag, api.EBSCSIDriverAddon)),
+		}
+		p.MockCloudFormation().On("ListStacksPages", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			consume := args[1].(func(p *cfn.ListStacksOutput, last bool) (shouldContinue bool))
+			out := &cfn.ListStacksOutput{}
+			for _, s := range stacks {
+				out.StackSummaries = append(out.StackSummaries, &cfn.StackSummary{StackName: s.StackName, StackId: s.StackId})
+			}
+			consume(out, true)
+		}).Return(nil)
+		for _, s := range stacks {
+			stack := s
+			p.MockCloudFormation().On("DescribeStacks", mock.MatchedBy(func(input *cfn.DescribeStacksInput) bool {
+				return *input.StackName == *stack.StackId
+			})).Return(&cfn.DescribeStacksOutput{Stacks: []*cfn.Stack{stack}}, nil)
+		}
+
+		p.MockIAM().On("GetOpenIDConnectProvider", mock.Anything).Return(&iam.GetOpenIDConnectProviderOutput{}, nil)
+		var err error
+		oidc, err = iamoidc.NewOpenIDConnectManager(p.IAM(), "123456789012", "https://oidc.eks.us-west-2.amazonaws.com/id/A39A2842863C47208955D753DE205E6E", "aws")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("deletes the nodegroups, the iamserviceaccounts and the roles of add-ons at the same time, before the OIDC provider", func() {
+		tasks, err := sc.NewTasksToDeleteClusterWithNodeGroups(true, false, oidc, nil, true, 5, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks.Describe()).To(Equal(`2 sequential tasks: { ` +
+			`2 parallel sub-tasks: { ` +
+			`2 parallel sub-tasks: { delete nodegroup "ng-1", delete nodegroup "ng-2" }, ` +
+			`2 sequential sub-tasks: { ` +
+			`2 parallel sub-tasks: { delete IAM role for serviceaccount "default/sa-1", delete IAM role of EKS add-on "aws-ebs-csi-driver" }, ` +
+			`delete IAM OIDC provider ` +
+			`} ` +
+			`}, ` +
+			`delete cluster control plane "test-cluster" ` +
+			`}`))
+	})
+
+	It("limits how many nodegroups and iamserviceaccounts are deleted at the same time", func() {
+		tasks, err := sc.NewTasksToDeleteClusterWithNodeGroups(true, false, oidc, nil, true, 5, nil)
+		Expect(err).ToNot(HaveOccurred())
+		dependentTasks := tasks.tasks[0].(*TaskTree)
+		Expect(dependentTasks.Parallel).To(BeTrue())
+		Expect(dependentTasks.Concurrency).To(Equal(0))
+
+		nodeGroupTasks := dependentTasks.tasks[0].(*TaskTree)
+		Expect(nodeGroupTasks.Parallel).To(BeTrue())
+		Expect(nodeGroupTasks.Concurrency).To(Equal(5))
+
+		roleTasks := dependentTasks.tasks[1].(*TaskTree).tasks[0].(*TaskTree)
+		Expect(roleTasks.Parallel).To(BeTrue())
+		Expect(roleTasks.tasks[0].(*TaskTree).Concurrency).To(Equal(5))
+	})
+
+	It("keeps the OIDC provider", func() {
+		tasks, err := sc.NewTasksToDeleteClusterWithNodeGroups(true, true, oidc, nil, true, 5, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks.Describe()).ToNot(ContainSubstring("delete IAM OIDC provider"))
+		Expect(tasks.Describe()).To(ContainSubstring(`2 parallel sub-tasks: { delete IAM role for serviceaccount "default/sa-1", delete IAM role of EKS add-on "aws-ebs-csi-driver" }`))
+	})
+
+	It("deletes the iamserviceaccounts of a cluster that can't be reached without its OIDC provider", func() {
+		tasks, err := sc.NewTasksToDeleteClusterWithNodeGroups(true, false, nil, nil, true, 5, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks.Describe()).To(ContainSubstring(`2 parallel sub-tasks: { delete IAM role for serviceaccount "default/sa-1", delete IAM role of EKS add-on "aws-ebs-csi-driver" }`))
+		Expect(tasks.Describe()).ToNot(ContainSubstring("delete IAM OIDC provider"))
+	})
+
+	It("deletes the roles of add-ons along with the nodegroups without the OIDC provider", func() {
+		tasks, err := sc.NewTasksToDeleteClusterWithNodeGroups(false, false, oidc, nil, true, 5, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks.Describe()).To(Equal(`2 sequential tasks: { ` +
+			`2 parallel sub-tasks: { ` +
+			`2 parallel sub-tasks: { delete nodegroup "ng-1", delete nodegroup "ng-2" }, ` +
+			`delete IAM role of EKS add-on "aws-ebs-csi-driver" ` +
+			`}, ` +
+			`delete cluster control plane "test-cluster" ` +
+			`}`))
+	})
+})
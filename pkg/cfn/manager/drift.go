@@ -0,0 +1,120 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/utils/retry"
+)
+
+// StackDrift is the result of the drift detection of a stack, along with the resources that
+// differ from the template, or were deleted
+type StackDrift struct {
+	Stack            *Stack
+	DriftStatus      string
+	DriftedResources []*cfn.StackResourceDrift
+}
+
+// IsDrifted determines if any resources of the stack were changed outside of CloudFormation
+func (d *StackDrift) IsDrifted() bool {
+	return d.DriftStatus == cfn.StackDriftStatusDrifted
+}
+
+// DetectDrift runs the drift detection of every stack of the cluster and waits for the results;
+// the stacks being created, updated or deleted are skipped as their drift can't be detected
+func (c *StackCollection) DetectDrift(ctx context.Context) ([]*StackDrift, error) {
+	stacks, err := c.DescribeStacks()
+	if err != nil {
+		return nil, err
+	}
+
+	detectionIDs := map[string]string{}
+	var detectedStacks []*Stack
+	for _, s := range stacks {
+		if !c.isOwnedStack(s) {
+			logger.Debug("ignoring stack %q as it doesn't bare the tags of cluster %q", *s.StackName, c.spec.Metadata.Name)
+			continue
+		}
+		if !c.StackStatusIsNotTransitional(s) {
+			logger.Warning("skipping drift detection of stack %q as it's in %q state", *s.StackName, *s.StackStatus)
+			continue
+		}
+		output, err := c.provider.CloudFormation().DetectStackDrift(&cfn.DetectStackDriftInput{
+			StackName: s.StackName,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "detecting drift of stack %q", *s.StackName)
+		}
+		detectionIDs[*s.StackName] = aws.StringValue(output.StackDriftDetectionId)
+		detectedStacks = append(detectedStacks, s)
+	}
+
+	var drifts []*StackDrift
+	for _, s := range detectedStacks {
+		drift, err := c.waitForDriftDetection(ctx, s, detectionIDs[*s.StackName])
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, drift)
+	}
+	return drifts, nil
+}
+
+func (c *StackCollection) waitForDriftDetection(ctx context.Context, s *Stack, detectionID string) (*StackDrift, error) {
+	logger.Info("waiting for drift detection of stack %q", *s.StackName)
+	var output *cfn.DescribeStackDriftDetectionStatusOutput
+	err := retry.DefaultPoller.Poll(ctx, func() (bool, error) {
+		var err error
+		output, err = c.provider.CloudFormation().DescribeStackDriftDetectionStatus(&cfn.DescribeStackDriftDetectionStatusInput{
+			StackDriftDetectionId: &detectionID,
+		})
+		if err != nil {
+			return false, errors.Wrapf(err, "describing drift detection of stack %q", *s.StackName)
+		}
+		return aws.StringValue(output.DetectionStatus) != cfn.StackDriftDetectionStatusDetectionInProgress, nil
+	})
+	if err == context.DeadlineExceeded {
+		return nil, fmt.Errorf("timed out while waiting for drift detection of stack %q", *s.StackName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if aws.StringValue(output.DetectionStatus) == cfn.StackDriftDetectionStatusDetectionFailed {
+		// the drift of the other resources is still detected
+		logger.Warning("drift detection of stack %q failed for some resources: %s", *s.StackName, aws.StringValue(output.DetectionStatusReason))
+	}
+
+	drifted, err := c.describeDriftedResources(s)
+	if err != nil {
+		return nil, err
+	}
+	return &StackDrift{
+		Stack:            s,
+		DriftStatus:      aws.StringValue(output.StackDriftStatus),
+		DriftedResources: drifted,
+	}, nil
+}
+
+func (c *StackCollection) describeDriftedResources(s *Stack) ([]*cfn.StackResourceDrift, error) {
+	input := &cfn.DescribeStackResourceDriftsInput{
+		StackName:                       s.StackName,
+		StackResourceDriftStatusFilters: aws.StringSlice([]string{cfn.StackResourceDriftStatusModified, cfn.StackResourceDriftStatusDeleted}),
+	}
+	var drifted []*cfn.StackResourceDrift
+	for {
+		output, err := c.provider.CloudFormation().DescribeStackResourceDrifts(input)
+		if err != nil {
+			return nil, errors.Wrapf(err, "describing drifted resources of stack %q", *s.StackName)
+		}
+		drifted = append(drifted, output.StackResourceDrifts...)
+		if output.NextToken == nil {
+			return drifted, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
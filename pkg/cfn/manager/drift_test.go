@@ -0,0 +1,110 @@
+package manager
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("StackCollection drift detection", func() {
+	var (
+		sc *StackCollection
+		p  *mockprovider.MockProvider
+	)
+
+	newStack := func(name, status string) *cfn.Stack {
+		return &cfn.Stack{
+			StackName:   aws.String(name),
+			StackId:     aws.String("arn:aws:cloudformation:us-west-2:123456789012:stack/" + name + "/1"),
+			StackStatus: aws.String(status),
+			Tags: []*cfn.Tag{
+				{Key: aws.String(api.ClusterNameTag), Value: aws.String("test-cluster")},
+			},
+		}
+	}
+
+	mockStacks := func(stacks ...*cfn.Stack) {
+		p.MockCloudFormation().On("ListStacksPages", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			consume := args[1].(func(p *cfn.ListStacksOutput, last bool) (shouldContinue bool))
+			out := &cfn.ListStacksOutput{}
+			for _, s := range stacks {
+				out.StackSummaries = append(out.StackSummaries, &cfn.StackSummary{
+					StackName: s.StackName,
+					StackId:   s.StackId,
+				})
+			}
+			consume(out, true)
+		}).Return(nil)
+
+		for _, s := range stacks {
+			stack := s
+			p.MockCloudFormation().On("DescribeStacks", mock.MatchedBy(func(input *cfn.DescribeStacksInput) bool {
+				return *input.StackName == *stack.StackId
+			})).Return(&cfn.DescribeStacksOutput{Stacks: []*cfn.Stack{stack}}, nil)
+		}
+	}
+
+	mockDetection := func(stack *cfn.Stack, driftStatus string, drifts ...*cfn.StackResourceDrift) {
+		detectionID := *stack.StackName + "-detection"
+		p.MockCloudFormation().On("DetectStackDrift", &cfn.DetectStackDriftInput{StackName: stack.StackName}).
+			Return(&cfn.DetectStackDriftOutput{StackDriftDetectionId: aws.String(detectionID)}, nil).Once()
+		p.MockCloudFormation().On("DescribeStackDriftDetectionStatus", &cfn.DescribeStackDriftDetectionStatusInput{StackDriftDetectionId: aws.String(detectionID)}).
+			Return(&cfn.DescribeStackDriftDetectionStatusOutput{
+				DetectionStatus:  aws.String(cfn.StackDriftDetectionStatusDetectionComplete),
+				StackDriftStatus: aws.String(driftStatus),
+			}, nil).Once()
+		p.MockCloudFormation().On("DescribeStackResourceDrifts", mock.MatchedBy(func(input *cfn.DescribeStackResourceDriftsInput) bool {
+			return *input.StackName == *stack.StackName && len(input.StackResourceDriftStatusFilters) == 2
+		})).Return(&cfn.DescribeStackResourceDriftsOutput{StackResourceDrifts: drifts}, nil).Once()
+	}
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = "test-cluster"
+		cfg.Metadata.Region = "us-west-2"
+		sc = NewStackCollection(p, cfg)
+	})
+
+	It("reports the drifted resources of the stacks that can be checked", func() {
+		clusterStack := newStack("eksctl-test-cluster-cluster", cfn.StackStatusUpdateComplete)
+		nodeGroupStack := newStack("eksctl-test-cluster-nodegroup-ng-1", cfn.StackStatusCreateComplete)
+		updatingStack := newStack("eksctl-test-cluster-nodegroup-ng-2", cfn.StackStatusUpdateInProgress)
+		mockStacks(clusterStack, nodeGroupStack, updatingStack)
+
+		securityGroupDrift := &cfn.StackResourceDrift{
+			LogicalResourceId:        aws.String("ControlPlaneSecurityGroup"),
+			ResourceType:             aws.String("AWS::EC2::SecurityGroup"),
+			StackResourceDriftStatus: aws.String(cfn.StackResourceDriftStatusModified),
+			PropertyDifferences: []*cfn.PropertyDifference{{
+				PropertyPath:   aws.String("/SecurityGroupIngress/0/CidrIp"),
+				ExpectedValue:  aws.String("10.0.0.0/16"),
+				ActualValue:    aws.String("0.0.0.0/0"),
+				DifferenceType: aws.String(cfn.DifferenceTypeNotEqual),
+			}},
+		}
+		mockDetection(clusterStack, cfn.StackDriftStatusDrifted, securityGroupDrift)
+		mockDetection(nodeGroupStack, cfn.StackDriftStatusInSync)
+
+		drifts, err := sc.DetectDrift(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(drifts).To(HaveLen(2))
+
+		Expect(drifts[0].Stack).To(Equal(clusterStack))
+		Expect(drifts[0].IsDrifted()).To(BeTrue())
+		Expect(drifts[0].DriftedResources).To(ConsistOf(securityGroupDrift))
+
+		Expect(drifts[1].Stack).To(Equal(nodeGroupStack))
+		Expect(drifts[1].IsDrifted()).To(BeFalse())
+		Expect(drifts[1].DriftedResources).To(BeEmpty())
+
+		p.MockCloudFormation().AssertNotCalled(GinkgoT(), "DetectStackDrift", &cfn.DetectStackDriftInput{StackName: updatingStack.StackName})
+	})
+})
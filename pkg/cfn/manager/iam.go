@@ -1,10 +1,12 @@
 package manager
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
 
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
-	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/builder"
@@ -21,16 +23,117 @@ func (c *StackCollection) makeIAMServiceAccountStackName(namespace, name string)
 func (c *StackCollection) createIAMServiceAccountTask(errs chan error, spec *api.ClusterIAMServiceAccount, oidc *iamoidc.OpenIDConnectManager) error {
 	name := c.makeIAMServiceAccountStackName(spec.Namespace, spec.Name)
 	logger.Info("building iamserviceaccount stack %q", name)
-	stack := builder.NewIAMServiceAccountResourceSet(spec, oidc)
+	stack := builder.NewIAMServiceAccountResourceSet(spec, c.spec.Metadata.Name, oidc)
 	if err := stack.AddAllResources(); err != nil {
 		return err
 	}
 
 	tags := map[string]string{api.IAMServiceAccountNameTag: spec.NameString()}
+	if api.IsEnabled(spec.RoleOnly) {
+		tags[api.IAMServiceAccountRoleOnlyTag] = "true"
+	}
 
 	return c.CreateStack(name, stack, tags, nil, errs)
 }
 
+// adoptIAMServiceAccountTask takes over the existing stack s of an iamserviceaccount: the stack is
+// updated when the role it defines changed, e.g. its attached policies, and re-created when its
+// creation failed
+func (c *StackCollection) adoptIAMServiceAccountTask(errs chan error, spec *api.ClusterIAMServiceAccount, oidc *iamoidc.OpenIDConnectManager, s *Stack) error {
+	name := *s.StackName
+	switch status := *s.StackStatus; status {
+	case cfn.StackStatusCreateComplete, cfn.StackStatusUpdateComplete, cfn.StackStatusUpdateRollbackComplete:
+		defer close(errs)
+		return c.updateIAMServiceAccountStack(spec, oidc, s)
+	case cfn.StackStatusCreateFailed, cfn.StackStatusRollbackComplete, cfn.StackStatusRollbackFailed:
+		logger.Info("re-creating iamserviceaccount stack %q, as its creation failed with status %s", name, status)
+		deleteErrs := make(chan error)
+		if err := c.DeleteStackBySpecSync(s, deleteErrs); err != nil {
+			return err
+		}
+		if err := <-deleteErrs; err != nil {
+			return err
+		}
+		return c.createIAMServiceAccountTask(errs, spec, oidc)
+	default:
+		return fmt.Errorf("iamserviceaccount stack %q is in status %s, run the command again once the stack is settled", name, status)
+	}
+}
+
+// updateIAMServiceAccountStack updates the stack s of an iamserviceaccount when the resources
+// rendered from its spec differ from those of the stack, and collects the outputs of the stack
+func (c *StackCollection) updateIAMServiceAccountStack(spec *api.ClusterIAMServiceAccount, oidc *iamoidc.OpenIDConnectManager, s *Stack) error {
+	name := *s.StackName
+	stack := builder.NewIAMServiceAccountResourceSet(spec, c.spec.Metadata.Name, oidc)
+	if err := stack.AddAllResources(); err != nil {
+		return err
+	}
+	template, err := stack.RenderJSON()
+	if err != nil {
+		return errors.Wrapf(err, "rendering template for %q stack", name)
+	}
+	currentTemplate, err := c.GetStackTemplate(name)
+	if err != nil {
+		return errors.Wrapf(err, "getting the template of stack %q", name)
+	}
+
+	if resourcesChanged(currentTemplate, template) {
+		capabilities := stackCapabilitiesIAM
+		if stack.WithNamedIAM() {
+			capabilities = stackCapabilitiesNamedIAM
+		}
+		description := fmt.Sprintf("update the IAM role of iamserviceaccount %q", spec.NameString())
+		if err := c.updateStack(name, c.MakeChangeSetName("update-iamserviceaccount"), description, template, nil, capabilities); err != nil {
+			return err
+		}
+	} else {
+		logger.Info("adopting iamserviceaccount stack %q, its IAM role is up to date", name)
+	}
+
+	current, err := c.DescribeStack(&Stack{StackName: &name})
+	if err != nil {
+		return err
+	}
+	if err := stack.GetAllOutputs(*current); err != nil {
+		return errors.Wrapf(err, "getting stack %q outputs", name)
+	}
+	return nil
+}
+
+// resourcesChanged tells whether the resources of the desired template differ from those of the
+// current one, templates that can't be compared are considered changed
+func resourcesChanged(currentTemplate string, desiredTemplate []byte) bool {
+	var current, desired struct {
+		Resources map[string]interface{}
+	}
+	if err := json.Unmarshal([]byte(currentTemplate), &current); err != nil {
+		return true
+	}
+	if err := json.Unmarshal(desiredTemplate, &desired); err != nil {
+		return true
+	}
+	return !reflect.DeepEqual(current.Resources, desired.Resources)
+}
+
+// attachIAMServiceAccountRoleTask uses the existing role of the iamserviceaccount instead of
+// creating one, it warns when the trust policy of the role doesn't allow the serviceaccount
+// to assume it
+func attachIAMServiceAccountRoleTask(errs chan error, spec *api.ClusterIAMServiceAccount, oidc *iamoidc.OpenIDConnectManager) error {
+	defer close(errs)
+	roleARN := spec.AttachRoleARN
+	spec.Status = &api.ClusterIAMServiceAccountStatus{RoleARN: &roleARN}
+
+	problems, err := oidc.CheckRoleTrust(roleARN, spec.Namespace, spec.Name)
+	if err != nil {
+		logger.Warning("unable to check the trust policy of IAM role %q: %s", roleARN, err.Error())
+		return nil
+	}
+	for _, problem := range problems {
+		logger.Warning("%s", problem)
+	}
+	return nil
+}
+
 // DescribeIAMServiceAccountStacks calls DescribeStacks and filters out iamserviceaccounts
 func (c *StackCollection) DescribeIAMServiceAccountStacks() ([]*Stack, error) {
 	stacks, err := c.DescribeStacks()
@@ -105,6 +208,17 @@ func (c *StackCollection) GetIAMServiceAccounts() ([]*api.ClusterIAMServiceAccou
 	return results, nil
 }
 
+// isIAMServiceAccountRoleOnly tells whether eksctl only created the role of the iamserviceaccount,
+// and left the serviceaccount to other tools
+func isIAMServiceAccountRoleOnly(s *Stack) bool {
+	for _, tag := range s.Tags {
+		if *tag.Key == api.IAMServiceAccountRoleOnlyTag {
+			return *tag.Value == "true"
+		}
+	}
+	return false
+}
+
 // GetIAMServiceAccountName will return iamserviceaccount name based on tags
 func (*StackCollection) GetIAMServiceAccountName(s *Stack) string {
 	for _, tag := range s.Tags {
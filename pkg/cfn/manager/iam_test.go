@@ -0,0 +1,82 @@
+package manager
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("StackCollection iamserviceaccounts", func() {
+	var (
+		sc *StackCollection
+		p  *mockprovider.MockProvider
+	)
+
+	newStack := func(name, status string, tags ...*cfn.Tag) *cfn.Stack {
+		return &cfn.Stack{
+			StackName:   aws.String(name),
+			StackId:     aws.String("arn:aws:cloudformation:us-west-2:123456789012:stack/" + name + "/1"),
+			StackStatus: aws.String(status),
+			Tags:        append(tags, &cfn.Tag{Key: aws.String(api.ClusterNameTag), Value: aws.String("test-cluster")}),
+		}
+	}
+
+	newServiceAccount := func(name string) *api.ClusterIAMServiceAccount {
+		return &api.ClusterIAMServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	}
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = "test-cluster"
+		cfg.Metadata.Region = "us-west-2"
+		sc = NewStackCollection(p, cfg)
+	})
+
+	It("adopts the existing stacks of iamserviceaccounts", func() {
+		stacks := []*cfn.Stack{
+			newStack("eksctl-test-cluster-cluster", cfn.StackStatusCreateComplete),
+			newStack("eksctl-test-cluster-addon-iamserviceaccount-default-sa-1", cfn.StackStatusCreateComplete,
+				&cfn.Tag{Key: aws.String(api.IAMServiceAccountNameTag), Value: aws.String("default/sa-1")}),
+		}
+		p.MockCloudFormation().On("ListStacksPages", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			consume := args[1].(func(p *cfn.ListStacksOutput, last bool) (shouldContinue bool))
+			out := &cfn.ListStacksOutput{}
+			for _, s := range stacks {
+				out.StackSummaries = append(out.StackSummaries, &cfn.StackSummary{StackName: s.StackName, StackId: s.StackId})
+			}
+			consume(out, true)
+		}).Return(nil)
+		for _, s := range stacks {
+			stack := s
+			p.MockCloudFormation().On("DescribeStacks", mock.MatchedBy(func(input *cfn.DescribeStacksInput) bool {
+				return *input.StackName == *stack.StackId
+			})).Return(&cfn.DescribeStacksOutput{Stacks: []*cfn.Stack{stack}}, nil)
+		}
+
+		tasks, err := sc.NewTasksToCreateOrAdoptIAMServiceAccounts([]*api.ClusterIAMServiceAccount{newServiceAccount("sa-1"), newServiceAccount("sa-2")}, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tasks.Describe()).To(Equal(`2 parallel tasks: { ` +
+			`2 sequential sub-tasks: { adopt IAM role stack of serviceaccount "default/sa-1", create serviceaccount "default/sa-1" }, ` +
+			`2 sequential sub-tasks: { create IAM role for serviceaccount "default/sa-2", create serviceaccount "default/sa-2" } }`))
+	})
+
+	It("doesn't adopt stacks with an operation in progress", func() {
+		s := newStack("eksctl-test-cluster-addon-iamserviceaccount-default-sa-1", cfn.StackStatusUpdateInProgress)
+		err := sc.adoptIAMServiceAccountTask(make(chan error), newServiceAccount("sa-1"), nil, s)
+		Expect(err).To(MatchError(`iamserviceaccount stack "eksctl-test-cluster-addon-iamserviceaccount-default-sa-1" is in status UPDATE_IN_PROGRESS, run the command again once the stack is settled`))
+	})
+
+	It("detects changes of the resources of templates", func() {
+		current := `{"Description": "old", "Resources": {"Role1": {"Type": "AWS::IAM::Role", "Properties": {"ManagedPolicyArns": ["arn:aws:iam::aws:policy/A"]}}}}`
+		Expect(resourcesChanged(current, []byte(`{"Description": "new", "Resources": {"Role1": {"Type": "AWS::IAM::Role", "Properties": {"ManagedPolicyArns": ["arn:aws:iam::aws:policy/A"]}}}}`))).To(BeFalse())
+		Expect(resourcesChanged(current, []byte(`{"Resources": {"Role1": {"Type": "AWS::IAM::Role", "Properties": {"ManagedPolicyArns": ["arn:aws:iam::aws:policy/B"]}}}}`))).To(BeTrue())
+		Expect(resourcesChanged("Resources: {}", []byte(`{"Resources": {}}`))).To(BeTrue())
+	})
+})
@@ -0,0 +1,94 @@
+package manager
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/pkg/errors"
+)
+
+// Kinds of OwnedResource
+const (
+	OwnedResourceKindStack                 = "stack"
+	OwnedResourceKindStackResource         = "resource"
+	OwnedResourceKindIAMServiceAccountRole = "iamserviceaccount-role"
+	OwnedResourceKindAddon                 = "addon"
+	OwnedResourceKindOIDCProvider          = "oidc-provider"
+)
+
+// OwnedResource is a resource eksctl owns in a cluster, either a stack, a resource of one of
+// the stacks or a resource eksctl manages without CloudFormation
+type OwnedResource struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Type is the CloudFormation type of the resource
+	Type       string `json:"type,omitempty"`
+	Stack      string `json:"stack,omitempty"`
+	PhysicalID string `json:"physicalID,omitempty"`
+	Status     string `json:"status,omitempty"`
+	// DriftStatus is the result of the last drift detection of the stack or resource, it's
+	// NOT_CHECKED until a drift detection has run
+	DriftStatus string            `json:"driftStatus,omitempty"`
+	CreatedAt   *time.Time        `json:"createdAt,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+// ListOwnedStackResources lists the stacks of the cluster along with their resources; the
+// roles of iamserviceaccount stacks are listed as iamserviceaccount-role
+func (c *StackCollection) ListOwnedStackResources() ([]*OwnedResource, error) {
+	stacks, err := c.DescribeStacks()
+	if err != nil {
+		return nil, err
+	}
+
+	var owned []*OwnedResource
+	for _, s := range stacks {
+		if !c.isOwnedStack(s) {
+			logger.Debug("ignoring stack %q as it doesn't bare the tags of cluster %q", *s.StackName, c.spec.Metadata.Name)
+			continue
+		}
+		stack := &OwnedResource{
+			Kind:       OwnedResourceKindStack,
+			Name:       aws.StringValue(s.StackName),
+			Type:       "AWS::CloudFormation::Stack",
+			PhysicalID: aws.StringValue(s.StackId),
+			Status:     aws.StringValue(s.StackStatus),
+			CreatedAt:  s.CreationTime,
+			Tags:       map[string]string{},
+		}
+		if s.DriftInformation != nil {
+			stack.DriftStatus = aws.StringValue(s.DriftInformation.StackDriftStatus)
+		}
+		for _, tag := range s.Tags {
+			stack.Tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+		owned = append(owned, stack)
+
+		output, err := c.provider.CloudFormation().DescribeStackResources(&cfn.DescribeStackResourcesInput{
+			StackName: s.StackId,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "describing resources of stack %q", *s.StackName)
+		}
+		isIAMServiceAccountStack := c.GetIAMServiceAccountName(s) != ""
+		for _, r := range output.StackResources {
+			resource := &OwnedResource{
+				Kind:       OwnedResourceKindStackResource,
+				Name:       aws.StringValue(r.LogicalResourceId),
+				Type:       aws.StringValue(r.ResourceType),
+				Stack:      stack.Name,
+				PhysicalID: aws.StringValue(r.PhysicalResourceId),
+				Status:     aws.StringValue(r.ResourceStatus),
+			}
+			if isIAMServiceAccountStack && resource.Type == "AWS::IAM::Role" {
+				resource.Kind = OwnedResourceKindIAMServiceAccountRole
+			}
+			if r.DriftInformation != nil {
+				resource.DriftStatus = aws.StringValue(r.DriftInformation.StackResourceDriftStatus)
+			}
+			owned = append(owned, resource)
+		}
+	}
+	return owned, nil
+}
@@ -0,0 +1,117 @@
+package manager
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("StackCollection owned resources", func() {
+	var (
+		sc *StackCollection
+		p  *mockprovider.MockProvider
+	)
+
+	newStack := func(name, clusterName string, tags ...*cfn.Tag) *cfn.Stack {
+		return &cfn.Stack{
+			StackName:    aws.String(name),
+			StackId:      aws.String("arn:aws:cloudformation:us-west-2:123456789012:stack/" + name + "/1"),
+			StackStatus:  aws.String(cfn.StackStatusCreateComplete),
+			CreationTime: aws.Time(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)),
+			DriftInformation: &cfn.StackDriftInformation{
+				StackDriftStatus: aws.String(cfn.StackDriftStatusInSync),
+			},
+			Tags: append([]*cfn.Tag{
+				{Key: aws.String(api.ClusterNameTag), Value: aws.String(clusterName)},
+			}, tags...),
+		}
+	}
+
+	mockStacks := func(stacks ...*cfn.Stack) {
+		p.MockCloudFormation().On("ListStacksPages", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			consume := args[1].(func(p *cfn.ListStacksOutput, last bool) (shouldContinue bool))
+			out := &cfn.ListStacksOutput{}
+			for _, s := range stacks {
+				out.StackSummaries = append(out.StackSummaries, &cfn.StackSummary{
+					StackName: s.StackName,
+					StackId:   s.StackId,
+				})
+			}
+			consume(out, true)
+		}).Return(nil)
+
+		for _, s := range stacks {
+			stack := s
+			p.MockCloudFormation().On("DescribeStacks", mock.MatchedBy(func(input *cfn.DescribeStacksInput) bool {
+				return *input.StackName == *stack.StackId
+			})).Return(&cfn.DescribeStacksOutput{Stacks: []*cfn.Stack{stack}}, nil)
+		}
+	}
+
+	mockResources := func(stack *cfn.Stack, resources ...*cfn.StackResource) {
+		p.MockCloudFormation().On("DescribeStackResources", mock.MatchedBy(func(input *cfn.DescribeStackResourcesInput) bool {
+			return *input.StackName == *stack.StackId
+		})).Return(&cfn.DescribeStackResourcesOutput{StackResources: resources}, nil)
+	}
+
+	newResource := func(logicalID, resourceType string) *cfn.StackResource {
+		return &cfn.StackResource{
+			LogicalResourceId:  aws.String(logicalID),
+			PhysicalResourceId: aws.String(logicalID + "-physical"),
+			ResourceType:       aws.String(resourceType),
+			ResourceStatus:     aws.String(cfn.ResourceStatusCreateComplete),
+			DriftInformation: &cfn.StackResourceDriftInformation{
+				StackResourceDriftStatus: aws.String(cfn.StackResourceDriftStatusModified),
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = "test-cluster"
+		cfg.Metadata.Region = "us-west-2"
+		sc = NewStackCollection(p, cfg)
+	})
+
+	It("lists the owned stacks along with their resources", func() {
+		clusterStack := newStack("eksctl-test-cluster-cluster", "test-cluster")
+		serviceAccountStack := newStack("eksctl-test-cluster-addon-iamserviceaccount-kube-system-aws-node", "test-cluster",
+			&cfn.Tag{Key: aws.String(api.IAMServiceAccountNameTag), Value: aws.String("kube-system/aws-node")})
+		otherStack := newStack("eksctl-test-cluster-nodegroup-ng-1", "other-cluster")
+		mockStacks(clusterStack, serviceAccountStack, otherStack)
+		mockResources(clusterStack, newResource("ControlPlane", "AWS::EKS::Cluster"))
+		mockResources(serviceAccountStack, newResource("Role1", "AWS::IAM::Role"))
+
+		owned, err := sc.ListOwnedStackResources()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(owned).To(HaveLen(4))
+
+		Expect(owned[0].Kind).To(Equal(OwnedResourceKindStack))
+		Expect(owned[0].Name).To(Equal("eksctl-test-cluster-cluster"))
+		Expect(owned[0].DriftStatus).To(Equal(cfn.StackDriftStatusInSync))
+		Expect(owned[0].CreatedAt.Year()).To(Equal(2020))
+		Expect(owned[0].Tags).To(HaveKeyWithValue(api.ClusterNameTag, "test-cluster"))
+
+		Expect(owned[1]).To(Equal(&OwnedResource{
+			Kind:        OwnedResourceKindStackResource,
+			Name:        "ControlPlane",
+			Type:        "AWS::EKS::Cluster",
+			Stack:       "eksctl-test-cluster-cluster",
+			PhysicalID:  "ControlPlane-physical",
+			Status:      cfn.ResourceStatusCreateComplete,
+			DriftStatus: cfn.StackResourceDriftStatusModified,
+		}))
+
+		Expect(owned[2].Kind).To(Equal(OwnedResourceKindStack))
+		Expect(owned[3].Kind).To(Equal(OwnedResourceKindIAMServiceAccountRole))
+		Expect(owned[3].Stack).To(Equal("eksctl-test-cluster-addon-iamserviceaccount-kube-system-aws-node"))
+	})
+})
@@ -6,7 +6,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
@@ -26,6 +25,7 @@ const (
 type NodeGroupSummary struct {
 	StackName           string
 	Cluster             string
+	Region              string
 	Name                string
 	MaxSize             int
 	MinSize             int
@@ -47,8 +47,17 @@ func (c *StackCollection) makeNodeGroupStackName(name string) string {
 	return fmt.Sprintf("eksctl-%s-nodegroup-%s", c.spec.Metadata.Name, name)
 }
 
+// makeNodeGroupAccessStackName generates the name of the stack in the account of the cluster
+// allowing a nodegroup in another account to reach the control plane
+func (c *StackCollection) makeNodeGroupAccessStackName(name string) string {
+	return fmt.Sprintf("eksctl-%s-nodegroup-%s-access", c.spec.Metadata.Name, name)
+}
+
 // createNodeGroupTask creates the nodegroup
 func (c *StackCollection) createNodeGroupTask(errs chan error, ng *api.NodeGroup, supportsManagedNodes bool) error {
+	if ng.Account != nil {
+		return c.createCrossAccountNodeGroupTask(errs, ng, supportsManagedNodes)
+	}
 	name := c.makeNodeGroupStackName(ng.Name)
 	logger.Info("building nodegroup stack %q", name)
 	stack := builder.NewNodeGroupResourceSet(c.provider, c.spec, c.makeClusterStackName(), ng, supportsManagedNodes)
@@ -56,20 +65,58 @@ func (c *StackCollection) createNodeGroupTask(errs chan error, ng *api.NodeGroup
 		return err
 	}
 
+	return c.CreateStack(name, stack, nodeGroupTags(ng), nil, errs)
+}
+
+// createCrossAccountNodeGroupTask creates the stack of a nodegroup in its account, then the stack
+// allowing it to reach the control plane in the account of the cluster
+func (c *StackCollection) createCrossAccountNodeGroupTask(errs chan error, ng *api.NodeGroup, supportsManagedNodes bool) error {
+	accountStacks, err := c.forNodeGroupAccount(ng)
+	if err != nil {
+		return err
+	}
+
+	name := c.makeNodeGroupStackName(ng.Name)
+	logger.Info("building nodegroup stack %q in the account of role %q", name, ng.Account.RoleARN)
+	stack := builder.NewNodeGroupResourceSet(accountStacks.provider, c.spec, c.makeClusterStackName(), ng, supportsManagedNodes)
+	if err := stack.AddAllResources(); err != nil {
+		return err
+	}
+
+	nodeGroupErrs := make(chan error)
+	if err := accountStacks.CreateStack(name, stack, nodeGroupTags(ng), nil, nodeGroupErrs); err != nil {
+		return err
+	}
+	if err := <-nodeGroupErrs; err != nil {
+		return err
+	}
+
+	accessName := c.makeNodeGroupAccessStackName(ng.Name)
+	logger.Info("building stack %q allowing nodegroup %q to reach the control plane", accessName, ng.Name)
+	access := builder.NewNodeGroupAccessResourceSet(c.spec, ng, stack.SecurityGroupID())
+	if err := access.AddAllResources(); err != nil {
+		return err
+	}
+	return c.CreateStack(accessName, access, map[string]string{api.NodeGroupAccessTag: ng.Name}, nil, errs)
+}
+
+func nodeGroupTags(ng *api.NodeGroup) map[string]string {
 	if ng.Tags == nil {
 		ng.Tags = make(map[string]string)
 	}
 	ng.Tags[api.NodeGroupNameTag] = ng.Name
 	ng.Tags[api.OldNodeGroupNameTag] = ng.Name
 	ng.Tags[api.NodeGroupTypeTag] = string(api.NodeGroupTypeUnmanaged)
-
-	return c.CreateStack(name, stack, ng.Tags, nil, errs)
+	return ng.Tags
 }
 
 func (c *StackCollection) createManagedNodeGroupTask(errorCh chan error, ng *api.ManagedNodeGroup) error {
 	name := c.makeNodeGroupStackName(ng.Name)
 	logger.Info("building managed nodegroup stack %q", name)
 	stack := builder.NewManagedNodeGroup(c.spec, ng, c.makeClusterStackName())
+	if c.rawAPIOverrides != nil {
+		stack.SetRawAPIOverrides(c.rawAPIOverrides.CreateNodegroup)
+	}
 	if err := stack.AddAllResources(); err != nil {
 		return err
 	}
@@ -113,9 +160,61 @@ func (c *StackCollection) ListNodeGroupStacks() ([]NodeGroupStack, error) {
 			Type:          nodeGroupType,
 		})
 	}
+
+	// the nodegroups in other accounts are known by their access stack
+	accessStacks, err := c.DescribeNodeGroupAccessStacks()
+	if err != nil {
+		return nil, err
+	}
+	for _, stack := range accessStacks {
+		nodeGroupStacks = append(nodeGroupStacks, NodeGroupStack{
+			NodeGroupName: getNodeGroupAccessName(stack),
+			Type:          api.NodeGroupTypeUnmanaged,
+		})
+	}
 	return nodeGroupStacks, nil
 }
 
+// DescribeNodeGroupAccessStacks returns the stacks allowing nodegroups in other accounts than the
+// control plane to reach it
+func (c *StackCollection) DescribeNodeGroupAccessStacks() ([]*Stack, error) {
+	stacks, err := c.DescribeStacks()
+	if err != nil {
+		return nil, err
+	}
+
+	var accessStacks []*Stack
+	for _, s := range stacks {
+		if *s.StackStatus == cfn.StackStatusDeleteComplete {
+			continue
+		}
+		if getNodeGroupAccessName(s) != "" {
+			accessStacks = append(accessStacks, s)
+		}
+	}
+	return accessStacks, nil
+}
+
+// DescribeCrossAccountNodeGroupStack describes the stack of a nodegroup in another account than
+// the control plane
+func (c *StackCollection) DescribeCrossAccountNodeGroupStack(ng *api.NodeGroup) (*Stack, error) {
+	accountStacks, err := c.forNodeGroupAccount(ng)
+	if err != nil {
+		return nil, err
+	}
+	name := c.makeNodeGroupStackName(ng.Name)
+	return accountStacks.DescribeStack(&Stack{StackName: &name})
+}
+
+func getNodeGroupAccessName(s *Stack) string {
+	for _, tag := range s.Tags {
+		if *tag.Key == api.NodeGroupAccessTag {
+			return *tag.Value
+		}
+	}
+	return ""
+}
+
 // DescribeNodeGroupStacksAndResources calls DescribeNodeGroupStacks and fetches all resources,
 // then returns it in a map by nodegroup name
 func (c *StackCollection) DescribeNodeGroupStacksAndResources() (map[string]StackInfo, error) {
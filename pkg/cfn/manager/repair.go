@@ -0,0 +1,144 @@
+package manager
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/pkg/errors"
+)
+
+// FailedStack is a stack that can't make progress without intervention,
+// along with the resources that caused it to fail
+type FailedStack struct {
+	Stack           *Stack
+	FailedResources []*cfn.StackResource
+}
+
+// NeedsRollback returns true when the stack is stuck in UPDATE_ROLLBACK_FAILED
+func (s *FailedStack) NeedsRollback() bool {
+	return *s.Stack.StackStatus == cfn.StackStatusUpdateRollbackFailed
+}
+
+// FailedLogicalResourceIDs returns the logical IDs of the failed resources
+func (s *FailedStack) FailedLogicalResourceIDs() []string {
+	var ids []string
+	for _, r := range s.FailedResources {
+		ids = append(ids, *r.LogicalResourceId)
+	}
+	return ids
+}
+
+// ListFailedStacks lists the stacks of the cluster that are either
+// in UPDATE_ROLLBACK_FAILED or DELETE_FAILED state
+func (c *StackCollection) ListFailedStacks() ([]*FailedStack, error) {
+	stacks, err := c.ListStacksMatching(fmtStacksRegexForCluster(c.spec.Metadata.Name),
+		cfn.StackStatusUpdateRollbackFailed,
+		cfn.StackStatusDeleteFailed,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing failed CloudFormation stacks for %q", c.spec.Metadata.Name)
+	}
+
+	failedStacks := []*FailedStack{}
+	for _, s := range stacks {
+		if !c.isOwnedStack(s) {
+			logger.Debug("ignoring stack %q as it doesn't bare the tags of cluster %q", *s.StackName, c.spec.Metadata.Name)
+			continue
+		}
+		resources, err := c.describeFailedStackResources(s)
+		if err != nil {
+			return nil, err
+		}
+		failedStacks = append(failedStacks, &FailedStack{
+			Stack:           s,
+			FailedResources: resources,
+		})
+	}
+	return failedStacks, nil
+}
+
+func (c *StackCollection) isOwnedStack(s *Stack) bool {
+	for _, tag := range s.Tags {
+		if matchesClusterName(*tag.Key, *tag.Value, c.spec.Metadata.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *StackCollection) describeFailedStackResources(s *Stack) ([]*cfn.StackResource, error) {
+	input := &cfn.DescribeStackResourcesInput{
+		StackName: s.StackId,
+	}
+	output, err := c.provider.CloudFormation().DescribeStackResources(input)
+	if err != nil {
+		return nil, errors.Wrapf(err, "describing resources of stack %q", *s.StackName)
+	}
+
+	// only resources that failed to update can be skipped in a rollback,
+	// and only resources that failed to delete can be retained
+	failedStatus := cfn.ResourceStatusDeleteFailed
+	if *s.StackStatus == cfn.StackStatusUpdateRollbackFailed {
+		failedStatus = cfn.ResourceStatusUpdateFailed
+	}
+
+	var failed []*cfn.StackResource
+	for _, r := range output.StackResources {
+		if r.ResourceStatus != nil && *r.ResourceStatus == failedStatus {
+			failed = append(failed, r)
+		}
+	}
+	return failed, nil
+}
+
+// ContinueUpdateRollback continues the rollback of a stack in UPDATE_ROLLBACK_FAILED
+// state, skipping the resources that failed to update, and waits for it to complete
+func (c *StackCollection) ContinueUpdateRollback(s *FailedStack) error {
+	if !s.NeedsRollback() {
+		return fmt.Errorf("cannot continue rollback of stack %q in %q state", *s.Stack.StackName, *s.Stack.StackStatus)
+	}
+
+	input := &cfn.ContinueUpdateRollbackInput{
+		StackName: s.Stack.StackName,
+	}
+	if ids := s.FailedLogicalResourceIDs(); len(ids) > 0 {
+		input.ResourcesToSkip = aws.StringSlice(ids)
+	}
+	if cfnRole := c.provider.CloudFormationRoleARN(); cfnRole != "" {
+		input = input.SetRoleARN(cfnRole)
+	}
+
+	if _, err := c.provider.CloudFormation().ContinueUpdateRollback(input); err != nil {
+		return errors.Wrapf(err, "continuing rollback of stack %q", *s.Stack.StackName)
+	}
+	logger.Info("waiting for rollback of stack %q to complete", *s.Stack.StackName)
+	return c.doWaitUntilUpdateRollbackIsComplete(s.Stack)
+}
+
+// DeleteFailedStack retries the deletion of a stack in DELETE_FAILED state,
+// retaining the resources that failed to delete, and waits for it to be deleted
+func (c *StackCollection) DeleteFailedStack(s *FailedStack) error {
+	if *s.Stack.StackStatus != cfn.StackStatusDeleteFailed {
+		return fmt.Errorf("cannot retry deletion of stack %q in %q state", *s.Stack.StackName, *s.Stack.StackStatus)
+	}
+
+	input := &cfn.DeleteStackInput{
+		StackName: s.Stack.StackId,
+	}
+	if ids := s.FailedLogicalResourceIDs(); len(ids) > 0 {
+		input.RetainResources = aws.StringSlice(ids)
+	}
+	if cfnRole := c.provider.CloudFormationRoleARN(); cfnRole != "" {
+		input = input.SetRoleARN(cfnRole)
+	}
+
+	if err := c.disableTerminationProtection(s.Stack); err != nil {
+		return err
+	}
+	if _, err := c.provider.CloudFormation().DeleteStack(input); err != nil {
+		return errors.Wrapf(err, "not able to delete stack %q", *s.Stack.StackName)
+	}
+	logger.Info("waiting for stack %q to get deleted", *s.Stack.StackName)
+	return c.doWaitUntilStackIsDeleted(s.Stack)
+}
@@ -0,0 +1,121 @@
+package manager
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("StackCollection failed stacks", func() {
+	var (
+		sc *StackCollection
+		p  *mockprovider.MockProvider
+	)
+
+	newStack := func(name, status, clusterName string) *cfn.Stack {
+		return &cfn.Stack{
+			StackName:   aws.String(name),
+			StackId:     aws.String("arn:aws:cloudformation:us-west-2:123456789012:stack/" + name + "/1"),
+			StackStatus: aws.String(status),
+			Tags: []*cfn.Tag{
+				{Key: aws.String(api.ClusterNameTag), Value: aws.String(clusterName)},
+			},
+		}
+	}
+
+	mockStacks := func(stacks ...*cfn.Stack) {
+		p.MockCloudFormation().On("ListStacksPages", mock.MatchedBy(func(input *cfn.ListStacksInput) bool {
+			return len(input.StackStatusFilter) == 2 &&
+				*input.StackStatusFilter[0] == cfn.StackStatusUpdateRollbackFailed &&
+				*input.StackStatusFilter[1] == cfn.StackStatusDeleteFailed
+		}), mock.Anything).Run(func(args mock.Arguments) {
+			consume := args[1].(func(p *cfn.ListStacksOutput, last bool) (shouldContinue bool))
+			out := &cfn.ListStacksOutput{}
+			for _, s := range stacks {
+				out.StackSummaries = append(out.StackSummaries, &cfn.StackSummary{
+					StackName: s.StackName,
+					StackId:   s.StackId,
+				})
+			}
+			consume(out, true)
+		}).Return(nil)
+
+		for _, s := range stacks {
+			stack := s
+			p.MockCloudFormation().On("DescribeStacks", mock.MatchedBy(func(input *cfn.DescribeStacksInput) bool {
+				return *input.StackName == *stack.StackId
+			})).Return(&cfn.DescribeStacksOutput{Stacks: []*cfn.Stack{stack}}, nil)
+		}
+	}
+
+	mockResources := func(stack *cfn.Stack, resources ...*cfn.StackResource) {
+		p.MockCloudFormation().On("DescribeStackResources", mock.MatchedBy(func(input *cfn.DescribeStackResourcesInput) bool {
+			return *input.StackName == *stack.StackId
+		})).Return(&cfn.DescribeStackResourcesOutput{StackResources: resources}, nil)
+	}
+
+	newResource := func(logicalID, status string) *cfn.StackResource {
+		return &cfn.StackResource{
+			LogicalResourceId:  aws.String(logicalID),
+			PhysicalResourceId: aws.String(logicalID + "-physical"),
+			ResourceType:       aws.String("AWS::EC2::SecurityGroup"),
+			ResourceStatus:     aws.String(status),
+		}
+	}
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = "test-cluster"
+		cfg.Metadata.Region = "us-west-2"
+		sc = NewStackCollection(p, cfg)
+	})
+
+	It("should list the failed resources of each failed stack", func() {
+		rollbackFailed := newStack("eksctl-test-cluster-cluster", cfn.StackStatusUpdateRollbackFailed, "test-cluster")
+		deleteFailed := newStack("eksctl-test-cluster-nodegroup-ng-1", cfn.StackStatusDeleteFailed, "test-cluster")
+		mockStacks(rollbackFailed, deleteFailed)
+
+		mockResources(rollbackFailed,
+			newResource("ControlPlaneSecurityGroup", cfn.ResourceStatusUpdateFailed),
+			newResource("ClusterSharedNodeSecurityGroup", cfn.ResourceStatusUpdateComplete),
+		)
+		mockResources(deleteFailed,
+			newResource("SG", cfn.ResourceStatusDeleteFailed),
+			newResource("NodeInstanceRole", cfn.ResourceStatusDeleteComplete),
+		)
+
+		failedStacks, err := sc.ListFailedStacks()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(failedStacks).To(HaveLen(2))
+
+		Expect(failedStacks[0].NeedsRollback()).To(BeTrue())
+		Expect(failedStacks[0].FailedLogicalResourceIDs()).To(Equal([]string{"ControlPlaneSecurityGroup"}))
+
+		Expect(failedStacks[1].NeedsRollback()).To(BeFalse())
+		Expect(failedStacks[1].FailedLogicalResourceIDs()).To(Equal([]string{"SG"}))
+	})
+
+	It("should ignore stacks of other clusters", func() {
+		mockStacks(newStack("eksctl-test-cluster-cluster", cfn.StackStatusDeleteFailed, "other-cluster"))
+
+		failedStacks, err := sc.ListFailedStacks()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(failedStacks).To(BeEmpty())
+	})
+
+	It("should refuse to repair stacks in the wrong state", func() {
+		s := &FailedStack{
+			Stack: newStack("eksctl-test-cluster-cluster", cfn.StackStatusDeleteFailed, "test-cluster"),
+		}
+		Expect(sc.ContinueUpdateRollback(s)).To(MatchError(ContainSubstring("cannot continue rollback")))
+
+		s.Stack.StackStatus = aws.String(cfn.StackStatusUpdateRollbackFailed)
+		Expect(sc.DeleteFailedStack(s)).To(MatchError(ContainSubstring("cannot retry deletion")))
+	})
+})
@@ -0,0 +1,161 @@
+package manager
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// ReleasedFromClusterTag is added to the resources kept when a cluster is deleted, in place
+// of the cluster name tags, with the name of the cluster as its value
+const ReleasedFromClusterTag = "eksctl.io/released-from-cluster"
+
+// VPCResourceTypes are the types of the resources of the cluster stack that make up its VPC
+var VPCResourceTypes = []string{
+	"AWS::EC2::VPC",
+	"AWS::EC2::VPCCidrBlock",
+	"AWS::EC2::Subnet",
+	"AWS::EC2::SubnetCidrBlock",
+	"AWS::EC2::InternetGateway",
+	"AWS::EC2::EgressOnlyInternetGateway",
+	"AWS::EC2::VPCGatewayAttachment",
+	"AWS::EC2::RouteTable",
+	"AWS::EC2::Route",
+	"AWS::EC2::SubnetRouteTableAssociation",
+	"AWS::EC2::NatGateway",
+	"AWS::EC2::EIP",
+}
+
+// RetainStackResources sets the DeletionPolicy of the resources of the stack whose type is in
+// resourceTypes, or of all its resources if resourceTypes is empty, to Retain, and updates the
+// stack, so that the resources are kept when the stack is deleted; it returns the kept resources
+func (c *StackCollection) RetainStackResources(s *Stack, resourceTypes ...string) ([]*cfn.StackResource, error) {
+	stackName := *s.StackName
+	template, err := c.GetStackTemplate(stackName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting template of stack %q", stackName)
+	}
+	template, logicalIDs, err := retainResources(template, resourceTypes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "updating template of stack %q", stackName)
+	}
+	if len(logicalIDs) == 0 {
+		return nil, nil
+	}
+
+	description := fmt.Sprintf("retain %d resource(s) of stack %q when it is deleted", len(logicalIDs), stackName)
+	if err := c.UpdateStack(stackName, c.MakeChangeSetName("retain-resources"), description, []byte(template), nil); err != nil {
+		return nil, errors.Wrapf(err, "updating stack %q", stackName)
+	}
+	// the stack isn't updated when CloudFormation finds no changes, the resources would be deleted then
+	updated, err := c.GetStackTemplate(stackName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting template of stack %q", stackName)
+	}
+	if _, pending, _ := retainResources(updated, resourceTypes); len(pending) > 0 {
+		return nil, fmt.Errorf("stack %q wasn't updated to retain resources %v", stackName, pending)
+	}
+
+	output, err := c.provider.CloudFormation().DescribeStackResources(&cfn.DescribeStackResourcesInput{
+		StackName: s.StackId,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "describing resources of stack %q", stackName)
+	}
+	retained := map[string]struct{}{}
+	for _, id := range logicalIDs {
+		retained[id] = struct{}{}
+	}
+	var resources []*cfn.StackResource
+	for _, r := range output.StackResources {
+		if _, ok := retained[*r.LogicalResourceId]; ok {
+			resources = append(resources, r)
+		}
+	}
+	return resources, nil
+}
+
+// retainResources sets the DeletionPolicy of the resources of the template with one of the
+// types to Retain, returning the updated template and the logical IDs of the resources whose
+// DeletionPolicy wasn't Retain already
+func retainResources(template string, resourceTypes []string) (string, []string, error) {
+	types := map[string]struct{}{}
+	for _, t := range resourceTypes {
+		types[t] = struct{}{}
+	}
+
+	var logicalIDs []string
+	gjson.Get(template, resourcesRootPath).ForEach(func(key, resource gjson.Result) bool {
+		if _, ok := types[resource.Get("Type").String()]; len(types) > 0 && !ok {
+			return true
+		}
+		if resource.Get("DeletionPolicy").String() != "Retain" {
+			logicalIDs = append(logicalIDs, key.String())
+		}
+		return true
+	})
+	sort.Strings(logicalIDs)
+
+	for _, id := range logicalIDs {
+		var err error
+		template, err = sjson.Set(template, fmt.Sprintf("%s.%s.DeletionPolicy", resourcesRootPath, id), "Retain")
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "setting DeletionPolicy of resource %q", id)
+		}
+	}
+	return template, logicalIDs, nil
+}
+
+// ReleaseResources tags the resources kept from the stacks of the cluster as released from it,
+// removing the cluster name tags, so that they aren't taken for resources of the cluster
+func (c *StackCollection) ReleaseResources(resources []*cfn.StackResource) error {
+	clusterName := c.spec.Metadata.Name
+	var ec2IDs []string
+	for _, r := range resources {
+		id := aws.StringValue(r.PhysicalResourceId)
+		switch aws.StringValue(r.ResourceType) {
+		case "AWS::EC2::VPC", "AWS::EC2::Subnet", "AWS::EC2::InternetGateway", "AWS::EC2::EgressOnlyInternetGateway",
+			"AWS::EC2::RouteTable", "AWS::EC2::NatGateway":
+			ec2IDs = append(ec2IDs, id)
+		case "AWS::IAM::Role":
+			if _, err := c.provider.IAM().TagRole(&iam.TagRoleInput{
+				RoleName: aws.String(id),
+				Tags:     []*iam.Tag{{Key: aws.String(ReleasedFromClusterTag), Value: aws.String(clusterName)}},
+			}); err != nil {
+				return errors.Wrapf(err, "tagging IAM role %q", id)
+			}
+			if _, err := c.provider.IAM().UntagRole(&iam.UntagRoleInput{
+				RoleName: aws.String(id),
+				TagKeys:  aws.StringSlice([]string{api.ClusterNameTag, api.OldClusterNameTag}),
+			}); err != nil {
+				return errors.Wrapf(err, "untagging IAM role %q", id)
+			}
+		}
+	}
+	if len(ec2IDs) == 0 {
+		return nil
+	}
+
+	if _, err := c.provider.EC2().CreateTags(&ec2.CreateTagsInput{
+		Resources: aws.StringSlice(ec2IDs),
+		Tags:      []*ec2.Tag{{Key: aws.String(ReleasedFromClusterTag), Value: aws.String(clusterName)}},
+	}); err != nil {
+		return errors.Wrapf(err, "tagging %v", ec2IDs)
+	}
+	if _, err := c.provider.EC2().DeleteTags(&ec2.DeleteTagsInput{
+		Resources: aws.StringSlice(ec2IDs),
+		Tags:      []*ec2.Tag{{Key: aws.String(api.ClusterNameTag)}, {Key: aws.String(api.OldClusterNameTag)}},
+	}); err != nil {
+		return errors.Wrapf(err, "untagging %v", ec2IDs)
+	}
+	return nil
+}
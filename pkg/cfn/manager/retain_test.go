@@ -0,0 +1,33 @@
+package manager
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tidwall/gjson"
+)
+
+var _ = Describe("retaining stack resources", func() {
+	template := `{
+		"Resources": {
+			"VPC": {"Type": "AWS::EC2::VPC", "Properties": {"CidrBlock": "192.168.0.0/16"}},
+			"SubnetPublicUSWEST2A": {"Type": "AWS::EC2::Subnet", "DeletionPolicy": "Retain"},
+			"ControlPlane": {"Type": "AWS::EKS::Cluster"}
+		}
+	}`
+
+	It("retains the resources of the given types", func() {
+		updated, logicalIDs, err := retainResources(template, VPCResourceTypes)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(logicalIDs).To(Equal([]string{"VPC"}))
+		Expect(gjson.Get(updated, "Resources.VPC.DeletionPolicy").String()).To(Equal("Retain"))
+		Expect(gjson.Get(updated, "Resources.VPC.Properties.CidrBlock").String()).To(Equal("192.168.0.0/16"))
+		Expect(gjson.Get(updated, "Resources.ControlPlane.DeletionPolicy").Exists()).To(BeFalse())
+	})
+
+	It("retains all resources without types", func() {
+		updated, logicalIDs, err := retainResources(template, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(logicalIDs).To(Equal([]string{"ControlPlane", "VPC"}))
+		Expect(gjson.Get(updated, "Resources.ControlPlane.DeletionPolicy").String()).To(Equal("Retain"))
+	})
+})
@@ -0,0 +1,104 @@
+package manager
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("StackCollection stack protection", func() {
+	var (
+		cfg *api.ClusterConfig
+		p   *mockprovider.MockProvider
+		sc  *StackCollection
+	)
+
+	BeforeEach(func() {
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "test-cluster"
+		cfg.CloudFormation = &api.ClusterCloudFormation{
+			DisableRollback:         api.Enabled(),
+			TerminationProtection:   api.Enabled(),
+			ProtectClusterResources: api.Enabled(),
+		}
+		p = mockprovider.NewMockProvider()
+		sc = NewStackCollection(p, cfg)
+	})
+
+	createStackInput := func(stackName string) *cfn.CreateStackInput {
+		var input *cfn.CreateStackInput
+		p.MockCloudFormation().On("CreateStack", mock.Anything).Run(func(args mock.Arguments) {
+			input = args[0].(*cfn.CreateStackInput)
+		}).Return(&cfn.CreateStackOutput{StackId: aws.String("stack-id")}, nil)
+
+		Expect(sc.DoCreateStackRequest(&Stack{StackName: aws.String(stackName)}, []byte("{}"), nil, nil, false, false)).To(Succeed())
+		return input
+	}
+
+	It("disables rollback, protects stacks and sets the policy of the cluster stack", func() {
+		input := createStackInput("eksctl-test-cluster-cluster")
+		Expect(*input.DisableRollback).To(BeTrue())
+		Expect(*input.EnableTerminationProtection).To(BeTrue())
+		Expect(*input.StackPolicyBody).To(ContainSubstring("LogicalResourceId/ControlPlane"))
+	})
+
+	It("only sets the stack policy on the cluster stack", func() {
+		input := createStackInput("eksctl-test-cluster-nodegroup-ng-1")
+		Expect(*input.EnableTerminationProtection).To(BeTrue())
+		Expect(input.StackPolicyBody).To(BeNil())
+	})
+
+	It("protects only the cluster stack of clusters with deletion protection", func() {
+		cfg.CloudFormation = nil
+		cfg.Metadata.DeletionProtection = api.Enabled()
+
+		input := createStackInput("eksctl-test-cluster-cluster")
+		Expect(*input.EnableTerminationProtection).To(BeTrue())
+
+		input = createStackInput("eksctl-test-cluster-nodegroup-ng-1")
+		Expect(input.EnableTerminationProtection).To(BeNil())
+	})
+
+	It("tells whether the cluster has deletion protection", func() {
+		p.MockCloudFormation().On("ListStacksPages", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			consume := args[1].(func(p *cfn.ListStacksOutput, last bool) (shouldContinue bool))
+			consume(&cfn.ListStacksOutput{
+				StackSummaries: []*cfn.StackSummary{{StackName: aws.String("eksctl-test-cluster-cluster")}},
+			}, true)
+		}).Return(nil)
+		p.MockCloudFormation().On("DescribeStacks", mock.Anything).Return(&cfn.DescribeStacksOutput{
+			Stacks: []*cfn.Stack{{
+				StackName:   aws.String("eksctl-test-cluster-cluster"),
+				StackId:     aws.String("stack-id"),
+				StackStatus: aws.String(cfn.StackStatusCreateComplete),
+				Tags: []*cfn.Tag{
+					newTag(api.ClusterNameTag, "test-cluster"),
+					newTag(api.DeletionProtectionTag, "true"),
+				},
+			}},
+		}, nil)
+
+		Expect(sc.HasDeletionProtection()).To(BeTrue())
+	})
+
+	It("disables termination protection before deleting stacks", func() {
+		p.MockCloudFormation().On("UpdateTerminationProtection", mock.MatchedBy(func(input *cfn.UpdateTerminationProtectionInput) bool {
+			return *input.StackName == "stack-id" && !*input.EnableTerminationProtection
+		})).Return(&cfn.UpdateTerminationProtectionOutput{}, nil)
+		p.MockCloudFormation().On("DeleteStack", mock.Anything).Return(&cfn.DeleteStackOutput{}, nil)
+
+		_, err := sc.DeleteStackBySpec(&Stack{
+			StackName:                   aws.String("eksctl-test-cluster-cluster"),
+			StackId:                     aws.String("stack-id"),
+			EnableTerminationProtection: aws.Bool(true),
+			Tags:                        []*cfn.Tag{newTag(api.ClusterNameTag, "test-cluster")},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		p.MockCloudFormation().AssertNumberOfCalls(GinkgoT(), "UpdateTerminationProtection", 1)
+	})
+})
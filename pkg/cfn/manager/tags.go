@@ -0,0 +1,83 @@
+package manager
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// StackTagsDiff returns the tags of the cluster that are either missing from
+// the stack or have a different value, i.e. metadata.tags and the cluster name tags
+func (c *StackCollection) StackTagsDiff(s *Stack) map[string]string {
+	existing := map[string]string{}
+	for _, tag := range s.Tags {
+		existing[*tag.Key] = *tag.Value
+	}
+
+	desired := map[string]string{
+		api.ClusterNameTag:    c.spec.Metadata.Name,
+		api.OldClusterNameTag: c.spec.Metadata.Name,
+	}
+	for k, v := range c.spec.Metadata.Tags {
+		desired[k] = v
+	}
+
+	diff := map[string]string{}
+	for k, v := range desired {
+		if value, ok := existing[k]; !ok || value != v {
+			diff[k] = v
+		}
+	}
+	return diff
+}
+
+// UpdateStackTags adds the given tags to the stack, without changing its template or parameters,
+// and waits for the update to complete; CloudFormation propagates stack tags to all the resources
+// of the stack that support tagging
+func (c *StackCollection) UpdateStackTags(s *Stack, tags map[string]string) error {
+	if !c.StackStatusIsNotTransitional(s) || *s.StackStatus == cloudformation.StackStatusRollbackComplete {
+		return fmt.Errorf("cannot update tags of stack %q in %q state", *s.StackName, *s.StackStatus)
+	}
+
+	merged := map[string]string{}
+	for _, tag := range s.Tags {
+		merged[*tag.Key] = *tag.Value
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	keys := make([]string, 0, len(merged))
+	for k := range merged {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	input := &cloudformation.UpdateStackInput{
+		StackName:           s.StackName,
+		UsePreviousTemplate: aws.Bool(true),
+		Capabilities:        s.Capabilities,
+	}
+	for _, k := range keys {
+		input.Tags = append(input.Tags, newTag(k, merged[k]))
+	}
+	for _, p := range s.Parameters {
+		input.Parameters = append(input.Parameters, &cloudformation.Parameter{
+			ParameterKey:     p.ParameterKey,
+			UsePreviousValue: aws.Bool(true),
+		})
+	}
+	if cfnRole := c.provider.CloudFormationRoleARN(); cfnRole != "" {
+		input = input.SetRoleARN(cfnRole)
+	}
+
+	if _, err := c.provider.CloudFormation().UpdateStack(input); err != nil {
+		return errors.Wrapf(err, "updating tags of stack %q", *s.StackName)
+	}
+	logger.Info("waiting for tags of stack %q to get updated", *s.StackName)
+	return c.doWaitUntilStackIsUpdated(s)
+}
@@ -0,0 +1,61 @@
+package manager
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("StackCollection tags", func() {
+	var (
+		cfg   *api.ClusterConfig
+		sc    *StackCollection
+		stack *cfn.Stack
+	)
+
+	BeforeEach(func() {
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "test-cluster"
+		cfg.Metadata.Region = "us-west-2"
+		cfg.Metadata.Tags = map[string]string{
+			"team":        "platform",
+			"cost-center": "1234",
+		}
+		sc = NewStackCollection(mockprovider.NewMockProvider(), cfg)
+
+		stack = &cfn.Stack{
+			StackName:   aws.String("eksctl-test-cluster-cluster"),
+			StackStatus: aws.String(cfn.StackStatusCreateComplete),
+			Tags: []*cfn.Tag{
+				newTag(api.ClusterNameTag, "test-cluster"),
+				newTag(api.OldClusterNameTag, "test-cluster"),
+				newTag("team", "infra"),
+			},
+		}
+	})
+
+	It("should only return the tags that are missing or different", func() {
+		Expect(sc.StackTagsDiff(stack)).To(Equal(map[string]string{
+			"team":        "platform",
+			"cost-center": "1234",
+		}))
+	})
+
+	It("should return no tags when the stack is up to date", func() {
+		stack.Tags = append(stack.Tags, newTag("cost-center", "1234"))
+		stack.Tags[2] = newTag("team", "platform")
+
+		Expect(sc.StackTagsDiff(stack)).To(BeEmpty())
+	})
+
+	It("should refuse to update the tags of a stack that can't be updated", func() {
+		stack.StackStatus = aws.String(cfn.StackStatusUpdateRollbackFailed)
+
+		err := sc.UpdateStackTags(stack, sc.StackTagsDiff(stack))
+		Expect(err).To(MatchError(ContainSubstring("cannot update tags")))
+	})
+})
@@ -4,8 +4,9 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 
-	"github.com/kris-nova/logger"
+	"github.com/pkg/errors"
 	"k8s.io/client-go/kubernetes"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
@@ -25,6 +26,11 @@ type TaskTree struct {
 	Parallel  bool
 	PlanMode  bool
 	IsSubTask bool
+	// Concurrency limits how many tasks of a parallel tree run at the same time, 0 is no limit
+	Concurrency int
+	// StopOnError stops starting the tasks of a parallel tree once one of them failed, the tasks
+	// that already started are completed
+	StopOnError bool
 }
 
 // Append new tasks to the set
@@ -74,6 +80,23 @@ func (t *TaskTree) Describe() string {
 	return msg
 }
 
+// batched splits the tasks of a parallel tree into a sequence of parallel batches of at most
+// size tasks, to limit how many run at the same time; it returns t when no batching is needed
+func (t *TaskTree) batched(size int) *TaskTree {
+	if !t.Parallel || size <= 0 || t.Len() <= size {
+		return t
+	}
+	batches := &TaskTree{Parallel: false, IsSubTask: t.IsSubTask}
+	for start := 0; start < len(t.tasks); start += size {
+		end := start + size
+		if end > len(t.tasks) {
+			end = len(t.tasks)
+		}
+		batches.Append(&TaskTree{Parallel: true, IsSubTask: true, tasks: t.tasks[start:end]})
+	}
+	return batches
+}
+
 // Do will run through the set in the background, it may return an error immediately,
 // or eventually write to the errs channel; it will close the channel once all tasks
 // are completed
@@ -87,7 +110,7 @@ func (t *TaskTree) Do(allErrs chan error) error {
 	errs := make(chan error)
 
 	if t.Parallel {
-		go doParallelTasks(errs, t.tasks)
+		go doParallelTasks(errs, t.tasks, t.Concurrency, t.StopOnError)
 	} else {
 		go doSequentialTasks(errs, t.tasks)
 	}
@@ -113,7 +136,7 @@ func (t *TaskTree) DoAllSync() []error {
 	errs := make(chan error)
 
 	if t.Parallel {
-		go doParallelTasks(errs, t.tasks)
+		go doParallelTasks(errs, t.tasks, t.Concurrency, t.StopOnError)
 	} else {
 		go doSequentialTasks(errs, t.tasks)
 	}
@@ -190,6 +213,37 @@ func (t *clusterCompatTask) Do(errorCh chan error) error {
 	return t.stackCollection.FixClusterCompatibility()
 }
 
+// reportingTask logs whether the task succeeded as soon as it completes, and prefixes its
+// errors with what it creates, so that the outcome of each of many parallel tasks is clear
+type reportingTask struct {
+	Task
+	subject string
+}
+
+func (t *reportingTask) Do(allErrs chan error) error {
+	errs := make(chan error)
+	if err := t.Task.Do(errs); err != nil {
+		logger.Warning("failed to create %s", t.subject)
+		return errors.Wrapf(err, "creating %s", t.subject)
+	}
+	go func() {
+		defer close(allErrs)
+		failed := false
+		for err := range errs {
+			if err == nil {
+				continue
+			}
+			failed = true
+			logger.Warning("failed to create %s", t.subject)
+			allErrs <- errors.Wrapf(err, "creating %s", t.subject)
+		}
+		if !failed {
+			logger.Success("created %s", t.subject)
+		}
+	}()
+	return nil
+}
+
 type taskWithClusterIAMServiceAccountSpec struct {
 	info           string
 	serviceAccount *api.ClusterIAMServiceAccount
@@ -226,6 +280,12 @@ func (t *asyncTaskWithStackSpec) Do(errs chan error) error {
 	return err
 }
 
+// NewFuncTask returns a task calling call, for the work done outside of CloudFormation to be
+// part of a task tree
+func NewFuncTask(info string, call func() error) Task {
+	return &asyncTaskWithoutParams{info: info, call: call}
+}
+
 type asyncTaskWithoutParams struct {
 	info string
 	call func() error
@@ -274,13 +334,25 @@ func doSingleTask(allErrs chan error, task Task) bool {
 	return true
 }
 
-func doParallelTasks(allErrs chan error, tasks []Task) {
+func doParallelTasks(allErrs chan error, tasks []Task, concurrency int, stopOnError bool) {
 	wg := &sync.WaitGroup{}
-	wg.Add(len(tasks))
+	if concurrency <= 0 {
+		concurrency = len(tasks)
+	}
+	slots := make(chan struct{}, concurrency)
+	var failed int32
 	for t := range tasks {
+		slots <- struct{}{}
+		if stopOnError && atomic.LoadInt32(&failed) != 0 {
+			logger.Warning("not starting %d remaining task(s) as a parallel task failed", len(tasks)-t)
+			break
+		}
+		wg.Add(1)
 		go func(t int) {
 			defer wg.Done()
+			defer func() { <-slots }()
 			if ok := doSingleTask(allErrs, tasks[t]); !ok {
+				atomic.StoreInt32(&failed, 1)
 				logger.Debug("failed task: %s (will continue until other parallel tasks are completed)", tasks[t].Describe())
 			}
 		}(t)
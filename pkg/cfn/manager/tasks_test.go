@@ -624,6 +624,65 @@ var _ = Describe("StackCollection Tasks", func() {
 			})
 		})
 
+		Context("With bounded parallel tasks", func() {
+			var (
+				running, maxRunning, started int32
+			)
+
+			newTask := func(name string, fail bool) Task {
+				return &taskWithoutParams{
+					info: name,
+					call: func(errs chan error) error {
+						atomic.AddInt32(&started, 1)
+						n := atomic.AddInt32(&running, 1)
+						for {
+							m := atomic.LoadInt32(&maxRunning)
+							if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+								break
+							}
+						}
+						go func() {
+							time.Sleep(50 * time.Millisecond)
+							atomic.AddInt32(&running, -1)
+							if fail {
+								errs <- fmt.Errorf("%s always fails", name)
+							}
+							close(errs)
+						}()
+						return nil
+					},
+				}
+			}
+
+			BeforeEach(func() {
+				running, maxRunning, started = 0, 0, 0
+			})
+
+			It("should run at most the given number of tasks at the same time", func() {
+				tasks := &TaskTree{Parallel: true, Concurrency: 2}
+				for i := 0; i < 6; i++ {
+					tasks.Append(newTask(fmt.Sprintf("t%d", i), i == 1))
+				}
+
+				errs := tasks.DoAllSync()
+				Expect(errs).To(HaveLen(1))
+				Expect(atomic.LoadInt32(&started)).To(Equal(int32(6)))
+				Expect(atomic.LoadInt32(&maxRunning)).To(Equal(int32(2)))
+			})
+
+			It("should stop starting tasks once one failed", func() {
+				tasks := &TaskTree{Parallel: true, Concurrency: 1, StopOnError: true}
+				for i := 0; i < 4; i++ {
+					tasks.Append(&reportingTask{Task: newTask(fmt.Sprintf("t%d", i), i == 1), subject: fmt.Sprintf("t%d", i)})
+				}
+
+				errs := tasks.DoAllSync()
+				Expect(errs).To(HaveLen(1))
+				Expect(errs[0].Error()).To(Equal("creating t1: t1 always fails"))
+				Expect(atomic.LoadInt32(&started)).To(Equal(int32(2)))
+			})
+		})
+
 		Context("With real tasks", func() {
 
 			BeforeEach(func() {
@@ -695,6 +754,15 @@ var _ = Describe("StackCollection Tasks", func() {
 					tasks := stackManager.NewTasksToCreateClusterWithNodeGroups(makeNodeGroups("foo"), makeManagedNodeGroups("m1"), true)
 					Expect(tasks.Describe()).To(Equal(`2 sequential tasks: { create cluster control plane "test-cluster", 2 parallel sub-tasks: { create nodegroup "foo", create managed nodegroup "m1" } }`))
 				}
+				{
+					batchSize := 2
+					cfg.CloudFormation = &api.ClusterCloudFormation{NodeGroupBatchSize: &batchSize}
+					tasks := stackManager.NewTasksToCreateClusterWithNodeGroups(makeNodeGroups("bar", "foo"), makeManagedNodeGroups("m1"), true)
+					Expect(tasks.Describe()).To(Equal(`2 sequential tasks: { create cluster control plane "test-cluster", 2 sequential sub-tasks: { 2 parallel sub-tasks: { create nodegroup "bar", create nodegroup "foo" }, create managed nodegroup "m1" } }`))
+
+					tasks = stackManager.NewTasksToCreateAllNodeGroups(makeNodeGroups("bar"), makeManagedNodeGroups("m1"), true)
+					Expect(tasks.Describe()).To(Equal(`2 parallel tasks: { create nodegroup "bar", create managed nodegroup "m1" }`))
+				}
 			})
 		})
 
@@ -3,14 +3,15 @@ package manager
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/request"
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/builder"
+	"github.com/weaveworks/eksctl/pkg/metrics"
 	"github.com/weaveworks/eksctl/pkg/utils/waiters"
 )
 
@@ -23,7 +24,7 @@ const (
 // so this is custom version that is more suitable for our use, as there is no way to add any
 // custom acceptors
 
-func (c *StackCollection) waitWithAcceptors(i *Stack, acceptors []request.WaiterAcceptor) error {
+func (c *StackCollection) waitWithAcceptors(i *Stack, operation string, acceptors []request.WaiterAcceptor) error {
 	msg := fmt.Sprintf("waiting for CloudFormation stack %q", *i.StackName)
 
 	newRequest := func() *request.Request {
@@ -48,7 +49,10 @@ func (c *StackCollection) waitWithAcceptors(i *Stack, acceptors []request.Waiter
 		return nil
 	}
 
-	return waiters.Wait(*i.StackName, msg, acceptors, newRequest, c.provider.WaitTimeout(), troubleshoot)
+	span := metrics.StartSpan("cloudformation.stack."+operation, map[string]string{"stack": *i.StackName})
+	err := waiters.Wait(*i.StackName, msg, acceptors, newRequest, c.waitTimeout(), troubleshoot)
+	span.End(err)
+	return c.attributeTimeout(err)
 }
 
 type noChangeError struct {
@@ -86,7 +90,20 @@ func (c *StackCollection) waitWithAcceptorsChangeSet(i *Stack, changesetName str
 		return nil
 	}
 
-	return waiters.Wait(*i.StackName, msg, acceptors, newRequest, c.provider.WaitTimeout(), troubleshoot)
+	return c.attributeTimeout(waiters.Wait(*i.StackName, msg, acceptors, newRequest, c.waitTimeout(), troubleshoot))
+}
+
+// waitTimeout returns the timeout of the phase the stacks are managed in
+func (c *StackCollection) waitTimeout() time.Duration {
+	return c.provider.Timeout(c.timeoutPhase)
+}
+
+// attributeTimeout names the timeout to raise when waiting timed out
+func (c *StackCollection) attributeTimeout(err error) error {
+	if !waiters.IsTimeout(err) {
+		return err
+	}
+	return c.timeoutPhase.WrapTimeout(err, c.waitTimeout())
 }
 
 func (c *StackCollection) troubleshootStackFailureCause(i *Stack, desiredStatus string) {
@@ -129,7 +146,7 @@ func (c *StackCollection) troubleshootStackFailureCause(i *Stack, desiredStatus
 // DoWaitUntilStackIsCreated blocks until the given stack's
 // creation has completed.
 func (c *StackCollection) DoWaitUntilStackIsCreated(i *Stack) error {
-	return c.waitWithAcceptors(i,
+	return c.waitWithAcceptors(i, "create",
 		waiters.MakeAcceptors(
 			stackStatus,
 			cfn.StackStatusCreateComplete,
@@ -171,7 +188,7 @@ func (c *StackCollection) waitUntilStackIsCreated(i *Stack, stack builder.Resour
 }
 
 func (c *StackCollection) doWaitUntilStackIsDeleted(i *Stack) error {
-	return c.waitWithAcceptors(i,
+	return c.waitWithAcceptors(i, "delete",
 		waiters.MakeAcceptors(
 			stackStatus,
 			cfn.StackStatusDeleteComplete,
@@ -215,7 +232,7 @@ func (c *StackCollection) waitUntilStackIsDeleted(i *Stack, errs chan error) {
 }
 
 func (c *StackCollection) doWaitUntilStackIsUpdated(i *Stack) error {
-	return c.waitWithAcceptors(i,
+	return c.waitWithAcceptors(i, "update",
 		waiters.MakeAcceptors(
 			stackStatus,
 			cfn.StackStatusUpdateComplete,
@@ -239,6 +256,26 @@ func (c *StackCollection) doWaitUntilStackIsUpdated(i *Stack) error {
 	)
 }
 
+func (c *StackCollection) doWaitUntilUpdateRollbackIsComplete(i *Stack) error {
+	return c.waitWithAcceptors(i, "rollback",
+		waiters.MakeAcceptors(
+			stackStatus,
+			cfn.StackStatusUpdateRollbackComplete,
+			[]string{
+				cfn.StackStatusUpdateRollbackFailed,
+				cfn.StackStatusDeleteInProgress,
+				cfn.StackStatusDeleteFailed,
+				cfn.StackStatusDeleteComplete,
+			},
+			request.WaiterAcceptor{
+				State:    request.FailureWaiterState,
+				Matcher:  request.ErrorWaiterMatch,
+				Expected: "ValidationError",
+			},
+		),
+	)
+}
+
 func (c *StackCollection) doWaitUntilChangeSetIsCreated(i *Stack, changesetName string) error {
 	return c.waitWithAcceptorsChangeSet(i, changesetName,
 		waiters.MakeAcceptors(
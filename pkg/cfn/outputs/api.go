@@ -29,9 +29,13 @@ const (
 	ClusterFeatureNATMode           = "FeatureNATMode"
 	ClusterFeatureEndpointAccess    = "FeatureEndpointAccess"
 
+	ClusterNodeTerminationHandlerQueueURL = "NodeTerminationHandlerQueueURL"
+	ClusterAlarmsTopicARN                 = "AlarmsTopicARN"
+
 	// outputs from nodegroup stack
 	NodeGroupInstanceRoleARN    = "InstanceRoleARN"
 	NodeGroupInstanceProfileARN = "InstanceProfileARN"
+	NodeGroupSecurityGroup      = "SecurityGroup"
 
 	// outputs to indicate configuration attributes that may have critical effect
 	// on critical effect on forward-compatibility with respect to overall functionality
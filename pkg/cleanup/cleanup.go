@@ -0,0 +1,266 @@
+// Package cleanup runs cleaners of resources that aren't part of the CloudFormation
+// stacks of a cluster, but that have to be deleted before the stacks can be, e.g.
+// load balancers of Kubernetes services; programs embedding eksctl can register
+// their own cleaners to run when a cluster is deleted
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// Params are passed to cleaners
+type Params struct {
+	ClusterConfig *api.ClusterConfig
+	Provider      api.ClusterProvider
+	// ClientSet is nil if the Kubernetes API of the cluster can't be reached,
+	// e.g. when the control plane failed to be created
+	ClientSet kubernetes.Interface
+}
+
+// Cleaner deletes resources before the stacks of a cluster are deleted
+type Cleaner interface {
+	// Description describes the resources the cleaner deletes, e.g. "LoadBalancer services"
+	Description() string
+	// Cleanup deletes the resources, returning an error aborts the deletion of the cluster
+	Cleanup(ctx context.Context, params Params) error
+}
+
+// PhasedCleaner is a Cleaner split into phases; Run calls the phases instead of Cleanup, so
+// that the report says which resources were found and which were left behind. The phases
+// of a run are called on the same cleaner, which can keep what it discovered in between
+type PhasedCleaner interface {
+	Cleaner
+	// Discover finds the resources to delete, returning their names
+	Discover(ctx context.Context, params Params) ([]string, error)
+	// Delete starts deleting the discovered resources
+	Delete(ctx context.Context, params Params) error
+	// Wait waits for the discovered resources to be deleted, returning the names of the
+	// ones still left when ctx is done
+	Wait(ctx context.Context, params Params) ([]string, error)
+}
+
+// Statuses of a Result
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	// StatusSkipped is the status of the cleaners that didn't run because an earlier one failed
+	StatusSkipped = "skipped"
+)
+
+// Statuses of a ResourceResult
+const (
+	ResourceDeleted = "deleted"
+	ResourceFailed  = "failed"
+)
+
+// Result is the outcome of running a cleaner
+type Result struct {
+	Cleaner string `json:"cleaner"`
+	Status  string `json:"status"`
+	// Found, Remaining and Resources are only set for a PhasedCleaner
+	Found     []string         `json:"found,omitempty"`
+	Remaining []string         `json:"remaining,omitempty"`
+	Resources []ResourceResult `json:"resources,omitempty"`
+	Duration  string           `json:"duration,omitempty"`
+	Error     string           `json:"error,omitempty"`
+}
+
+// ResourceResult is the outcome of the cleanup of a resource found by a PhasedCleaner
+type ResourceResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// Reason is why the resource wasn't deleted
+	Reason string `json:"reason,omitempty"`
+}
+
+// Count returns the number of resources with the status
+func (r Result) Count(status string) int {
+	var n int
+	for _, resource := range r.Resources {
+		if resource.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Summary summarises the result in a line
+func (r Result) Summary() string {
+	summary := fmt.Sprintf("%s: %s", r.Cleaner, r.Status)
+	if len(r.Found) > 0 {
+		summary += fmt.Sprintf(", %d found, %d deleted, %d failed", len(r.Found), r.Count(ResourceDeleted), r.Count(ResourceFailed))
+	}
+	if r.Duration != "" {
+		summary += " in " + r.Duration
+	}
+	return summary
+}
+
+// Report holds the results of the cleaners, in the order they were given to Run
+type Report struct {
+	Results []Result `json:"results"`
+}
+
+// Options of Run
+type Options struct {
+	// Parallelism is the number of cleaners run at the same time; cleaners run in order,
+	// stopping at the first one that fails, unless it is greater than 1
+	Parallelism int
+}
+
+var (
+	mu       sync.Mutex
+	cleaners []Cleaner
+)
+
+// Register registers a cleaner to run after the built-in cleaners
+func Register(cleaner Cleaner) {
+	mu.Lock()
+	defer mu.Unlock()
+	cleaners = append(cleaners, cleaner)
+}
+
+// Registered returns the registered cleaners
+func Registered() []Cleaner {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]Cleaner{}, cleaners...)
+}
+
+// Run runs the cleaners, returning their results along with the error of the first one that
+// failed. When run in order, the time left until the deadline of ctx is split evenly
+// between the cleaners yet to run, so that the time one doesn't use goes to the next ones;
+// cleaners run in parallel share the deadline
+func Run(ctx context.Context, params Params, cleaners []Cleaner, options Options) (*Report, error) {
+	report := &Report{
+		Results: make([]Result, len(cleaners)),
+	}
+	errs := make([]error, len(cleaners))
+
+	if options.Parallelism > 1 {
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, options.Parallelism)
+		for i, cleaner := range cleaners {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(i int, cleaner Cleaner) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				report.Results[i], errs[i] = runCleaner(ctx, params, cleaner)
+			}(i, cleaner)
+		}
+		wg.Wait()
+	} else {
+		var failed bool
+		for i, cleaner := range cleaners {
+			if failed {
+				report.Results[i] = Result{Cleaner: cleaner.Description(), Status: StatusSkipped}
+				continue
+			}
+			cleanerCtx, cancel := budget(ctx, len(cleaners)-i)
+			report.Results[i], errs[i] = runCleaner(cleanerCtx, params, cleaner)
+			cancel()
+			failed = errs[i] != nil
+		}
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// budget returns a context with an even share of the time left until the deadline of ctx
+// between the remaining cleaners
+func budget(ctx context.Context, remainingCleaners int) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Until(deadline)/time.Duration(remainingCleaners))
+}
+
+func runCleaner(ctx context.Context, params Params, cleaner Cleaner) (Result, error) {
+	logger.Info("cleaning up %s", cleaner.Description())
+	result := Result{
+		Cleaner: cleaner.Description(),
+		Status:  StatusSucceeded,
+	}
+	start := time.Now()
+	var err error
+	if phased, ok := cleaner.(PhasedCleaner); ok {
+		err = runPhases(ctx, params, phased, &result)
+		result.Resources = resourceResults(result, err)
+	} else {
+		err = cleaner.Cleanup(ctx, params)
+	}
+	result.Duration = time.Since(start).Round(time.Millisecond).String()
+	if err != nil {
+		err = errors.Wrapf(err, "cleaning up %s", cleaner.Description())
+		result.Status = StatusFailed
+		result.Error = err.Error()
+		return result, err
+	}
+	return result, nil
+}
+
+func runPhases(ctx context.Context, params Params, cleaner PhasedCleaner, result *Result) error {
+	found, err := cleaner.Discover(ctx, params)
+	if err != nil {
+		return errors.Wrap(err, "discovering resources")
+	}
+	result.Found = found
+	logger.Debug("found %d resource(s) of %s: %v", len(found), cleaner.Description(), found)
+
+	if err := cleaner.Delete(ctx, params); err != nil {
+		return errors.Wrap(err, "deleting resources")
+	}
+
+	remaining, err := cleaner.Wait(ctx, params)
+	result.Remaining = remaining
+	if err != nil {
+		return errors.Wrap(err, "waiting for resources to be deleted")
+	}
+	if len(remaining) > 0 {
+		return fmt.Errorf("%d resource(s) were still being deleted at the deadline: %s", len(remaining), strings.Join(remaining, ", "))
+	}
+	return nil
+}
+
+// resourceResults returns the outcome of each resource found by a PhasedCleaner; a resource
+// the cleaner didn't get to wait for failed with the error of the cleaner
+func resourceResults(result Result, err error) []ResourceResult {
+	remaining := map[string]struct{}{}
+	for _, name := range result.Remaining {
+		remaining[name] = struct{}{}
+	}
+	waited := err == nil || len(result.Remaining) > 0
+
+	var resources []ResourceResult
+	for _, name := range result.Found {
+		resource := ResourceResult{Name: name, Status: ResourceDeleted}
+		if _, ok := remaining[name]; ok {
+			resource.Status = ResourceFailed
+			resource.Reason = "still being deleted at the deadline"
+		} else if !waited {
+			resource.Status = ResourceFailed
+			resource.Reason = err.Error()
+		}
+		resources = append(resources, resource)
+	}
+	return resources
+}
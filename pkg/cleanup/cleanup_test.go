@@ -0,0 +1,157 @@
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type fakeCleaner struct {
+	description string
+	err         error
+	calls       *[]string
+}
+
+func (c *fakeCleaner) Description() string { return c.description }
+
+func (c *fakeCleaner) Cleanup(_ context.Context, _ Params) error {
+	*c.calls = append(*c.calls, c.description)
+	return c.err
+}
+
+type fakePhasedCleaner struct {
+	fakeCleaner
+	found     []string
+	remaining []string
+	deadline  time.Time
+}
+
+func (c *fakePhasedCleaner) Discover(ctx context.Context, _ Params) ([]string, error) {
+	c.deadline, _ = ctx.Deadline()
+	*c.calls = append(*c.calls, "discover")
+	return c.found, nil
+}
+
+func (c *fakePhasedCleaner) Delete(_ context.Context, _ Params) error {
+	*c.calls = append(*c.calls, "delete")
+	return nil
+}
+
+func (c *fakePhasedCleaner) Wait(_ context.Context, _ Params) ([]string, error) {
+	*c.calls = append(*c.calls, "wait")
+	return c.remaining, c.err
+}
+
+type blockingCleaner struct {
+	description string
+	started     *sync.WaitGroup
+}
+
+func (c *blockingCleaner) Description() string { return c.description }
+
+func (c *blockingCleaner) Cleanup(_ context.Context, _ Params) error {
+	c.started.Done()
+	// both cleaners have to be running for either to finish
+	c.started.Wait()
+	return nil
+}
+
+var _ = Describe("cleanup", func() {
+	var calls []string
+
+	BeforeEach(func() {
+		calls = nil
+	})
+
+	AfterEach(func() {
+		cleaners = nil
+	})
+
+	It("runs cleaners in order", func() {
+		Register(&fakeCleaner{description: "EBS volumes", calls: &calls})
+		Register(&fakeCleaner{description: "DNS records", calls: &calls})
+
+		builtIn := &fakeCleaner{description: "LoadBalancer services", calls: &calls}
+		report, err := Run(context.Background(), Params{}, append([]Cleaner{builtIn}, Registered()...), Options{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(calls).To(Equal([]string{"LoadBalancer services", "EBS volumes", "DNS records"}))
+		Expect(report.Results).To(HaveLen(3))
+		Expect(report.Results[0].Cleaner).To(Equal("LoadBalancer services"))
+		Expect(report.Results[0].Status).To(Equal(StatusSucceeded))
+	})
+
+	It("stops at the first failing cleaner", func() {
+		Register(&fakeCleaner{description: "EBS volumes", err: errors.New("volume in use"), calls: &calls})
+		Register(&fakeCleaner{description: "DNS records", calls: &calls})
+
+		report, err := Run(context.Background(), Params{}, Registered(), Options{})
+		Expect(err).To(MatchError("cleaning up EBS volumes: volume in use"))
+		Expect(calls).To(Equal([]string{"EBS volumes"}))
+		Expect(report.Results[0].Status).To(Equal(StatusFailed))
+		Expect(report.Results[0].Error).To(Equal("cleaning up EBS volumes: volume in use"))
+		Expect(report.Results[1].Status).To(Equal(StatusSkipped))
+	})
+
+	It("runs the phases of a phased cleaner and reports what they found", func() {
+		cleaner := &fakePhasedCleaner{
+			fakeCleaner: fakeCleaner{description: "LoadBalancer services", calls: &calls},
+			found:       []string{"default/web", "default/api"},
+		}
+
+		report, err := Run(context.Background(), Params{}, []Cleaner{cleaner}, Options{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(calls).To(Equal([]string{"discover", "delete", "wait"}))
+		Expect(report.Results[0].Found).To(Equal([]string{"default/web", "default/api"}))
+		Expect(report.Results[0].Count(ResourceDeleted)).To(Equal(2))
+	})
+
+	It("fails when resources are left after waiting", func() {
+		cleaner := &fakePhasedCleaner{
+			fakeCleaner: fakeCleaner{description: "LoadBalancer services", calls: &calls},
+			found:       []string{"default/web", "default/api"},
+			remaining:   []string{"default/api"},
+		}
+
+		report, err := Run(context.Background(), Params{}, []Cleaner{cleaner}, Options{})
+		Expect(err).To(MatchError("cleaning up LoadBalancer services: 1 resource(s) were still being deleted at the deadline: default/api"))
+		Expect(report.Results[0].Status).To(Equal(StatusFailed))
+		Expect(report.Results[0].Remaining).To(Equal([]string{"default/api"}))
+		Expect(report.Results[0].Resources).To(Equal([]ResourceResult{
+			{Name: "default/web", Status: ResourceDeleted},
+			{Name: "default/api", Status: ResourceFailed, Reason: "still being deleted at the deadline"},
+		}))
+		Expect(report.Results[0].Summary()).To(HavePrefix("LoadBalancer services: failed, 2 found, 1 deleted, 1 failed in "))
+	})
+
+	It("splits the deadline between the cleaners run in order", func() {
+		first := &fakePhasedCleaner{fakeCleaner: fakeCleaner{description: "first", calls: &calls}}
+		second := &fakePhasedCleaner{fakeCleaner: fakeCleaner{description: "second", calls: &calls}}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		deadline, _ := ctx.Deadline()
+
+		_, err := Run(ctx, Params{}, []Cleaner{first, second}, Options{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(first.deadline).To(BeTemporally("~", deadline.Add(-5*time.Minute), time.Second))
+		// the second cleaner gets the time the first didn't use
+		Expect(second.deadline).To(BeTemporally("~", deadline, time.Second))
+	})
+
+	It("runs cleaners in parallel", func() {
+		var started sync.WaitGroup
+		started.Add(2)
+
+		report, err := Run(context.Background(), Params{}, []Cleaner{
+			&blockingCleaner{description: "EBS volumes", started: &started},
+			&blockingCleaner{description: "DNS records", started: &started},
+		}, Options{Parallelism: 2})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Results[0].Cleaner).To(Equal("EBS volumes"))
+		Expect(report.Results[1].Cleaner).To(Equal("DNS records"))
+	})
+})
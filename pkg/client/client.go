@@ -0,0 +1,229 @@
+// Package client is a Go API to manage the lifecycle of EKS clusters with eksctl,
+// for programs that embed eksctl instead of running the binary; unlike the other
+// packages of eksctl, the API of this package is kept stable across releases
+package client
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/ctl/create"
+	"github.com/weaveworks/eksctl/pkg/ctl/delete"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/metrics"
+	"github.com/weaveworks/eksctl/pkg/utils/prompt"
+)
+
+// EventType is the type of a progress event
+type EventType string
+
+// Types of progress events
+const (
+	// OperationStarted is sent when an operation starts
+	OperationStarted EventType = "OperationStarted"
+	// OperationFinished is sent when an operation completes successfully
+	OperationFinished EventType = "OperationFinished"
+	// OperationFailed is sent when an operation fails
+	OperationFailed EventType = "OperationFailed"
+)
+
+// Operations reported in progress events, besides the operations of the client,
+// e.g. "CreateCluster"
+const (
+	OperationCreateStack   = "cloudformation.stack.create"
+	OperationUpdateStack   = "cloudformation.stack.update"
+	OperationDeleteStack   = "cloudformation.stack.delete"
+	OperationRollbackStack = "cloudformation.stack.rollback"
+)
+
+// Event reports the progress of an operation
+type Event struct {
+	Type EventType
+	// Operation is either the client operation, e.g. "CreateCluster", or one of the
+	// operations it's made of, e.g. OperationCreateStack
+	Operation string
+	// Stack is the name of the CloudFormation stack of stack operations
+	Stack string
+	// Duration is the duration of finished and failed operations
+	Duration time.Duration
+	// Err is the error failed operations failed with
+	Err error
+}
+
+// Options configure a client
+type Options struct {
+	// Profile is the AWS credentials profile, the default credential chain is used if empty
+	Profile string
+	// WaitTimeout is the maximum time to wait for each operation, e.g. creating a stack,
+	// it defaults to api.DefaultWaitTimeout
+	WaitTimeout time.Duration
+	// OnProgress is called with the progress of operations, if set
+	OnProgress func(Event)
+}
+
+// Client manages the lifecycle of clusters; as eksctl keeps state in global variables,
+// e.g. the log level, operations must not run concurrently, even with different clients
+type Client struct {
+	options Options
+}
+
+// New creates a client
+func New(options Options) *Client {
+	if options.WaitTimeout == 0 {
+		options.WaitTimeout = api.DefaultWaitTimeout
+	}
+	return &Client{options: options}
+}
+
+// CreateCluster creates the cluster along with its nodegroups and Fargate profiles, and waits
+// for it to be ready, like `eksctl create cluster --config-file`; cfg isn't modified
+func (c *Client) CreateCluster(cfg *api.ClusterConfig) error {
+	return c.run("CreateCluster", cfg, create.Command, "cluster", "--write-kubeconfig=false")
+}
+
+// DeleteCluster deletes the cluster and waits for all of its resources to be deleted,
+// like `eksctl delete cluster --config-file --wait`
+func (c *Client) DeleteCluster(cfg *api.ClusterConfig) error {
+	return c.run("DeleteCluster", cfg, delete.Command, "cluster", "--wait")
+}
+
+// CreateNodegroup creates the named nodegroups of the config, or all of them if
+// no names are given, like `eksctl create nodegroup --config-file --include`
+func (c *Client) CreateNodegroup(cfg *api.ClusterConfig, names ...string) error {
+	args := []string{"nodegroup"}
+	for _, name := range names {
+		args = append(args, "--include="+name)
+	}
+	return c.run("CreateNodegroup", cfg, create.Command, args...)
+}
+
+// ApplyConfig creates the cluster if it doesn't exist, otherwise it creates the
+// nodegroups of the config that don't exist yet
+func (c *Client) ApplyConfig(cfg *api.ClusterConfig) error {
+	exists, err := c.clusterExists(cfg)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return c.CreateCluster(cfg)
+	}
+	return c.run("ApplyConfig", cfg, create.Command, "nodegroup", "--only-missing")
+}
+
+func (c *Client) clusterExists(cfg *api.ClusterConfig) (bool, error) {
+	if cfg.Metadata == nil {
+		return false, cmdutils.ErrMustBeSet("metadata")
+	}
+	ctl := eks.New(c.providerConfig(cfg), cfg)
+	_, err := ctl.Provider.EKS().DescribeCluster(&awseks.DescribeClusterInput{
+		Name: aws.String(cfg.Metadata.Name),
+	})
+	if err != nil {
+		if awsErr, ok := errors.Cause(err).(awserr.Error); ok && awsErr.Code() == awseks.ErrCodeResourceNotFoundException {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "describing cluster %q", cfg.Metadata.Name)
+	}
+	return true, nil
+}
+
+func (c *Client) providerConfig(cfg *api.ClusterConfig) *api.ProviderConfig {
+	return &api.ProviderConfig{
+		Region:      cfg.Metadata.Region,
+		Profile:     c.options.Profile,
+		WaitTimeout: c.options.WaitTimeout,
+	}
+}
+
+// run runs the eksctl command with the config, reporting the progress of the operation;
+// the config is written to a file, so that it's loaded and validated like config files are
+func (c *Client) run(operation string, cfg *api.ClusterConfig, newVerbCmd func(*cmdutils.FlagGrouping) *cobra.Command, args ...string) (err error) {
+	c.report(Event{Type: OperationStarted, Operation: operation})
+	start := time.Now()
+	defer func() {
+		event := Event{Type: OperationFinished, Operation: operation, Duration: time.Since(start), Err: err}
+		if err != nil {
+			event.Type = OperationFailed
+		}
+		c.report(event)
+	}()
+
+	configFile, err := writeConfigFile(cfg)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(configFile)
+
+	if c.options.OnProgress != nil {
+		removeObserver := metrics.Default.AddObserver(c.reportSpan)
+		defer removeObserver()
+	}
+
+	nonInteractive := prompt.Default.NonInteractive
+	prompt.Default.NonInteractive = true
+	defer func() { prompt.Default.NonInteractive = nonInteractive }()
+
+	verbCmd := newVerbCmd(cmdutils.NewGrouping())
+	verbCmd.SilenceErrors = true
+	verbCmd.SilenceUsage = true
+	args = append(args, "--config-file="+configFile, "--timeout="+c.options.WaitTimeout.String())
+	if c.options.Profile != "" {
+		args = append(args, "--profile="+c.options.Profile)
+	}
+	verbCmd.SetArgs(args)
+	return verbCmd.Execute()
+}
+
+func writeConfigFile(cfg *api.ClusterConfig) (string, error) {
+	cfgCopy := cfg.DeepCopy()
+	cfgCopy.TypeMeta = api.ClusterConfigTypeMeta()
+	cfgCopy.Status = nil
+	data, err := json.Marshal(cfgCopy)
+	if err != nil {
+		return "", errors.Wrap(err, "serialising config")
+	}
+
+	file, err := ioutil.TempFile("", "eksctl-config-*.json")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	if _, err := file.Write(data); err != nil {
+		os.Remove(file.Name())
+		return "", errors.Wrap(err, "writing config file")
+	}
+	return file.Name(), nil
+}
+
+func (c *Client) reportSpan(span metrics.SpanEvent) {
+	event := Event{
+		Type:      OperationStarted,
+		Operation: span.Name,
+		Stack:     span.Attributes["stack"],
+	}
+	if span.Ended {
+		event.Type = OperationFinished
+		event.Duration = span.Duration
+		if span.Err != nil {
+			event.Type = OperationFailed
+			event.Err = span.Err
+		}
+	}
+	c.report(event)
+}
+
+func (c *Client) report(event Event) {
+	if c.options.OnProgress != nil {
+		c.options.OnProgress(event)
+	}
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/metrics"
+)
+
+var _ = Describe("client", func() {
+	It("writes the config to a file that can be loaded", func() {
+		cfg := api.NewClusterConfig()
+		cfg.TypeMeta.Kind = ""
+		cfg.Metadata.Name = "cluster-1"
+		cfg.Metadata.Region = "us-west-2"
+		cfg.Status = &api.ClusterStatus{Endpoint: "https://example.com"}
+
+		path, err := writeConfigFile(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		defer os.Remove(path)
+
+		loaded, err := eks.LoadConfigFromFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded.Metadata.Name).To(Equal("cluster-1"))
+		Expect(loaded.Metadata.Region).To(Equal("us-west-2"))
+		Expect(loaded.Status).To(BeNil())
+
+		Expect(cfg.TypeMeta.Kind).To(BeEmpty())
+		Expect(cfg.Status).NotTo(BeNil())
+	})
+
+	It("reports the progress of stack operations", func() {
+		var events []Event
+		client := New(Options{OnProgress: func(event Event) {
+			events = append(events, event)
+		}})
+
+		recorder := metrics.NewRecorder()
+		removeObserver := recorder.AddObserver(client.reportSpan)
+		defer removeObserver()
+
+		span := recorder.StartSpan(OperationCreateStack, map[string]string{"stack": "eksctl-cluster-1-cluster"})
+		span.End(errors.New("ROLLBACK_COMPLETE"))
+
+		Expect(events).To(HaveLen(2))
+		Expect(events[0]).To(Equal(Event{Type: OperationStarted, Operation: OperationCreateStack, Stack: "eksctl-cluster-1-cluster"}))
+		Expect(events[1].Type).To(Equal(OperationFailed))
+		Expect(events[1].Stack).To(Equal("eksctl-cluster-1-cluster"))
+		Expect(events[1].Err).To(MatchError("ROLLBACK_COMPLETE"))
+	})
+
+	It("defaults the wait timeout", func() {
+		Expect(New(Options{}).options.WaitTimeout).To(Equal(api.DefaultWaitTimeout))
+		Expect(New(Options{WaitTimeout: time.Hour}).options.WaitTimeout).To(Equal(time.Hour))
+	})
+})
@@ -8,9 +8,9 @@ import (
 	"io"
 	"io/ioutil"
 
-	"github.com/kris-nova/logger"
-
 	"sigs.k8s.io/yaml"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 const (
@@ -0,0 +1,132 @@
+// Package cost estimates the monthly cost of a cluster from the on-demand prices of the AWS Price
+// List API, to keep clusters within a budget; spot instances are estimated at on-demand prices,
+// and Fargate, EBS volumes, load balancers and data transfer aren't included
+package cost
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+const (
+	// HoursPerMonth is the number of hours AWS bills a month for
+	HoursPerMonth = 730
+	// ControlPlaneHourlyUSD is the hourly price of the control plane of a cluster
+	ControlPlaneHourlyUSD = 0.10
+)
+
+// Pricer looks up the hourly on-demand prices of the resources of a cluster in its region
+type Pricer interface {
+	InstanceHourlyUSD(instanceType string) (float64, error)
+	NATGatewayHourlyUSD() (float64, error)
+}
+
+// NodeGroup is what the estimate needs to know about a nodegroup
+type NodeGroup struct {
+	Name            string
+	InstanceType    string
+	DesiredCapacity int
+}
+
+// Inputs are the resources of the cluster to estimate the cost of
+type Inputs struct {
+	ClusterName string
+	NATGateways int
+	NodeGroups  []NodeGroup
+}
+
+// Item is the cost of a kind of resource of the cluster
+type Item struct {
+	Description string
+	Quantity    int
+	HourlyUSD   float64
+}
+
+// MonthlyUSD returns the monthly cost of the item
+func (i Item) MonthlyUSD() float64 {
+	return float64(i.Quantity) * i.HourlyUSD * HoursPerMonth
+}
+
+// Estimate is the estimated monthly cost of a cluster
+type Estimate struct {
+	ClusterName string
+	Items       []Item
+}
+
+// MonthlyUSD returns the estimated monthly cost of the cluster
+func (e *Estimate) MonthlyUSD() float64 {
+	var total float64
+	for _, item := range e.Items {
+		total += item.MonthlyUSD()
+	}
+	return total
+}
+
+// Log logs the cost of each item and the total
+func (e *Estimate) Log() {
+	for _, item := range e.Items {
+		logger.Info("%s: %d x $%.4f/hour = $%.2f/month", item.Description, item.Quantity, item.HourlyUSD, item.MonthlyUSD())
+	}
+	logger.Info("estimated cost of cluster %q: $%.2f/month", e.ClusterName, e.MonthlyUSD())
+}
+
+// EstimateCost estimates the monthly cost of the control plane, the NAT gateways and the nodegroups
+// of the cluster; nodegroups scaled to zero cost nothing
+func EstimateCost(pricer Pricer, inputs Inputs) (*Estimate, error) {
+	estimate := &Estimate{
+		ClusterName: inputs.ClusterName,
+		Items: []Item{{
+			Description: "control plane",
+			Quantity:    1,
+			HourlyUSD:   ControlPlaneHourlyUSD,
+		}},
+	}
+
+	if inputs.NATGateways > 0 {
+		price, err := pricer.NATGatewayHourlyUSD()
+		if err != nil {
+			return nil, err
+		}
+		estimate.Items = append(estimate.Items, Item{
+			Description: "NAT gateways",
+			Quantity:    inputs.NATGateways,
+			HourlyUSD:   price,
+		})
+	}
+
+	nodeGroups := append([]NodeGroup(nil), inputs.NodeGroups...)
+	sort.Slice(nodeGroups, func(i, j int) bool { return nodeGroups[i].Name < nodeGroups[j].Name })
+	for _, ng := range nodeGroups {
+		if ng.DesiredCapacity == 0 {
+			continue
+		}
+		price, err := pricer.InstanceHourlyUSD(ng.InstanceType)
+		if err != nil {
+			return nil, err
+		}
+		estimate.Items = append(estimate.Items, Item{
+			Description: fmt.Sprintf("nodegroup %q (%s)", ng.Name, ng.InstanceType),
+			Quantity:    ng.DesiredCapacity,
+			HourlyUSD:   price,
+		})
+	}
+	return estimate, nil
+}
+
+// CheckBudget returns an error if the estimate exceeds the budget, unless it's overridden, in which
+// case a warning is logged
+func CheckBudget(estimate *Estimate, maxMonthlyUSD float64, override bool) error {
+	monthlyUSD := estimate.MonthlyUSD()
+	if monthlyUSD <= maxMonthlyUSD {
+		logger.Info("estimated cost of $%.2f/month is within the budget of $%.2f/month", monthlyUSD, maxMonthlyUSD)
+		return nil
+	}
+	if override {
+		logger.Warning("estimated cost of $%.2f/month exceeds the budget of $%.2f/month, proceeding as --override-budget was set", monthlyUSD, maxMonthlyUSD)
+		return nil
+	}
+	return fmt.Errorf("estimated cost of cluster %q of $%.2f/month exceeds budget.maxMonthlyUSD of $%.2f/month, use --override-budget to proceed anyway",
+		estimate.ClusterName, monthlyUSD, maxMonthlyUSD)
+}
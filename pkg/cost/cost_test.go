@@ -0,0 +1,158 @@
+package cost_test
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/aws/aws-sdk-go/service/pricing/pricingiface"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/weaveworks/eksctl/pkg/cost"
+)
+
+type fakePricer struct {
+	instancePrices map[string]float64
+	natGatewayUSD  float64
+}
+
+func (f *fakePricer) InstanceHourlyUSD(instanceType string) (float64, error) {
+	price, ok := f.instancePrices[instanceType]
+	if !ok {
+		return 0, fmt.Errorf("no price for %q", instanceType)
+	}
+	return price, nil
+}
+
+func (f *fakePricer) NATGatewayHourlyUSD() (float64, error) {
+	return f.natGatewayUSD, nil
+}
+
+type fakePricingAPI struct {
+	pricingiface.PricingAPI
+	inputs    []*pricing.GetProductsInput
+	priceList []aws.JSONValue
+}
+
+func (f *fakePricingAPI) GetProductsPages(input *pricing.GetProductsInput, fn func(*pricing.GetProductsOutput, bool) bool) error {
+	f.inputs = append(f.inputs, input)
+	fn(&pricing.GetProductsOutput{PriceList: f.priceList}, true)
+	return nil
+}
+
+func product(unit, usd string) aws.JSONValue {
+	return aws.JSONValue{
+		"terms": map[string]interface{}{
+			"OnDemand": map[string]interface{}{
+				"OFFER.TERM": map[string]interface{}{
+					"priceDimensions": map[string]interface{}{
+						"OFFER.TERM.RATE": map[string]interface{}{
+							"unit":         unit,
+							"pricePerUnit": map[string]interface{}{"USD": usd},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+var _ = Describe("Cost estimation", func() {
+	pricer := &fakePricer{
+		instancePrices: map[string]float64{"m5.large": 0.096, "t3.medium": 0.0416},
+		natGatewayUSD:  0.045,
+	}
+
+	It("estimates the control plane, the NAT gateways and the nodegroups", func() {
+		estimate, err := EstimateCost(pricer, Inputs{
+			ClusterName: "cluster-1",
+			NATGateways: 1,
+			NodeGroups: []NodeGroup{
+				{Name: "ng-2", InstanceType: "t3.medium", DesiredCapacity: 3},
+				{Name: "ng-1", InstanceType: "m5.large", DesiredCapacity: 2},
+				{Name: "ng-3", InstanceType: "p3.2xlarge", DesiredCapacity: 0},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(estimate.Items).To(Equal([]Item{
+			{Description: "control plane", Quantity: 1, HourlyUSD: 0.10},
+			{Description: "NAT gateways", Quantity: 1, HourlyUSD: 0.045},
+			{Description: `nodegroup "ng-1" (m5.large)`, Quantity: 2, HourlyUSD: 0.096},
+			{Description: `nodegroup "ng-2" (t3.medium)`, Quantity: 3, HourlyUSD: 0.0416},
+		}))
+		Expect(estimate.MonthlyUSD()).To(BeNumerically("~", (0.10+0.045+2*0.096+3*0.0416)*HoursPerMonth, 0.001))
+	})
+
+	It("fails when an instance type has no price", func() {
+		_, err := EstimateCost(pricer, Inputs{
+			NodeGroups: []NodeGroup{{Name: "ng-1", InstanceType: "x9.huge", DesiredCapacity: 1}},
+		})
+		Expect(err).To(MatchError(`no price for "x9.huge"`))
+	})
+
+	Describe("budget", func() {
+		estimate := &Estimate{
+			ClusterName: "cluster-1",
+			Items:       []Item{{Description: "control plane", Quantity: 1, HourlyUSD: 0.10}},
+		}
+
+		It("accepts an estimate within the budget", func() {
+			Expect(CheckBudget(estimate, 100, false)).To(Succeed())
+		})
+
+		It("refuses an estimate exceeding the budget", func() {
+			Expect(CheckBudget(estimate, 50, false)).To(MatchError(
+				`estimated cost of cluster "cluster-1" of $73.00/month exceeds budget.maxMonthlyUSD of $50.00/month, use --override-budget to proceed anyway`))
+		})
+
+		It("accepts an estimate exceeding the budget when overridden", func() {
+			Expect(CheckBudget(estimate, 50, true)).To(Succeed())
+		})
+	})
+
+	Describe("price list", func() {
+		It("finds the hourly on-demand price of an instance type in the region", func() {
+			api := &fakePricingAPI{priceList: []aws.JSONValue{product("Hrs", "0.0960000000")}}
+			price, err := NewPricer(api, "us-west-2").InstanceHourlyUSD("m5.large")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(price).To(Equal(0.096))
+
+			Expect(api.inputs).To(HaveLen(1))
+			Expect(*api.inputs[0].ServiceCode).To(Equal("AmazonEC2"))
+			Expect(api.inputs[0].Filters).To(ContainElement(&pricing.Filter{
+				Type:  aws.String(pricing.FilterTypeTermMatch),
+				Field: aws.String("regionCode"),
+				Value: aws.String("us-west-2"),
+			}))
+			Expect(api.inputs[0].Filters).To(ContainElement(&pricing.Filter{
+				Type:  aws.String(pricing.FilterTypeTermMatch),
+				Field: aws.String("instanceType"),
+				Value: aws.String("m5.large"),
+			}))
+		})
+
+		It("caches the prices of instance types", func() {
+			api := &fakePricingAPI{priceList: []aws.JSONValue{product("Hrs", "0.0960000000")}}
+			pricer := NewPricer(api, "us-west-2")
+			for i := 0; i < 2; i++ {
+				_, err := pricer.InstanceHourlyUSD("m5.large")
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(api.inputs).To(HaveLen(1))
+		})
+
+		It("takes the hourly price of NAT gateways rather than the price of data processing", func() {
+			api := &fakePricingAPI{priceList: []aws.JSONValue{product("GB", "0.045"), product("Hrs", "0.048")}}
+			price, err := NewPricer(api, "eu-west-1").NATGatewayHourlyUSD()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(price).To(Equal(0.048))
+		})
+
+		It("fails when no price is found", func() {
+			api := &fakePricingAPI{}
+			_, err := NewPricer(api, "us-west-2").InstanceHourlyUSD("x9.huge")
+			Expect(err).To(MatchError(`getting the price of instance type "x9.huge": no hourly on-demand price found in region "us-west-2"`))
+		})
+	})
+})
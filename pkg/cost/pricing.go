@@ -0,0 +1,125 @@
+package cost
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/aws/aws-sdk-go/service/pricing/pricingiface"
+	"github.com/pkg/errors"
+)
+
+// PricingRegion is the region the Price List API is called in, it serves the prices of all regions
+const PricingRegion = "us-east-1"
+
+type priceList struct {
+	client pricingiface.PricingAPI
+	region string
+	// instancePrices caches the prices of instance types, as nodegroups often share them
+	instancePrices map[string]float64
+}
+
+// NewPricer returns a Pricer looking up the prices of the given region in the Price List API
+func NewPricer(client pricingiface.PricingAPI, region string) Pricer {
+	return &priceList{
+		client:         client,
+		region:         region,
+		instancePrices: map[string]float64{},
+	}
+}
+
+// InstanceHourlyUSD returns the hourly on-demand price of Linux instances of the instance type
+func (p *priceList) InstanceHourlyUSD(instanceType string) (float64, error) {
+	if price, ok := p.instancePrices[instanceType]; ok {
+		return price, nil
+	}
+	price, err := p.hourlyUSD(map[string]string{
+		"instanceType":    instanceType,
+		"operatingSystem": "Linux",
+		"tenancy":         "Shared",
+		"preInstalledSw":  "NA",
+		"capacitystatus":  "Used",
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "getting the price of instance type %q", instanceType)
+	}
+	p.instancePrices[instanceType] = price
+	return price, nil
+}
+
+// NATGatewayHourlyUSD returns the hourly price of a NAT gateway, not including data processing
+func (p *priceList) NATGatewayHourlyUSD() (float64, error) {
+	price, err := p.hourlyUSD(map[string]string{
+		"productFamily": "NAT Gateway",
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "getting the price of NAT gateways")
+	}
+	return price, nil
+}
+
+// hourlyUSD returns the first hourly on-demand price of the EC2 products matching the attributes
+// in the region
+func (p *priceList) hourlyUSD(attributes map[string]string) (float64, error) {
+	input := &pricing.GetProductsInput{
+		ServiceCode: aws.String("AmazonEC2"),
+		Filters: []*pricing.Filter{{
+			Type:  aws.String(pricing.FilterTypeTermMatch),
+			Field: aws.String("regionCode"),
+			Value: aws.String(p.region),
+		}},
+	}
+	for field, value := range attributes {
+		input.Filters = append(input.Filters, &pricing.Filter{
+			Type:  aws.String(pricing.FilterTypeTermMatch),
+			Field: aws.String(field),
+			Value: aws.String(value),
+		})
+	}
+
+	var (
+		price float64
+		found bool
+	)
+	err := p.client.GetProductsPages(input, func(output *pricing.GetProductsOutput, _ bool) bool {
+		for _, product := range output.PriceList {
+			if price, found = onDemandHourlyUSD(product); found {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, fmt.Errorf("no hourly on-demand price found in region %q", p.region)
+	}
+	return price, nil
+}
+
+// onDemandHourlyUSD finds the hourly price in the on-demand terms of a product of the price list,
+// i.e. terms.OnDemand.<offer>.priceDimensions.<rate>.pricePerUnit.USD
+func onDemandHourlyUSD(product aws.JSONValue) (float64, bool) {
+	terms, _ := product["terms"].(map[string]interface{})
+	onDemand, _ := terms["OnDemand"].(map[string]interface{})
+	for _, offer := range onDemand {
+		offer, _ := offer.(map[string]interface{})
+		dimensions, _ := offer["priceDimensions"].(map[string]interface{})
+		for _, dimension := range dimensions {
+			dimension, _ := dimension.(map[string]interface{})
+			if dimension["unit"] != "Hrs" {
+				continue
+			}
+			pricePerUnit, _ := dimension["pricePerUnit"].(map[string]interface{})
+			usd, _ := pricePerUnit["USD"].(string)
+			price, err := strconv.ParseFloat(usd, 64)
+			if err != nil {
+				continue
+			}
+			return price, true
+		}
+	}
+	return 0, false
+}
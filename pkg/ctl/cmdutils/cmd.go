@@ -1,11 +1,11 @@
 package cmdutils
 
 import (
-	"github.com/kris-nova/logger"
 	"github.com/spf13/cobra"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 // Cmd holds attributes that are common between commands;
@@ -18,7 +18,12 @@ type Cmd struct {
 
 	NameArg string
 
-	ClusterConfigFile string
+	ClusterConfigFile     string
+	ClusterConfigVars     map[string]string
+	ClusterConfigTemplate bool
+	ClusterConfigChecksum string
+	// ClusterConfigAllowHooks allows the command hooks of a remote config file without a checksum
+	ClusterConfigAllowHooks bool
 
 	ProviderConfig *api.ProviderConfig
 	ClusterConfig  *api.ClusterConfig
@@ -26,6 +31,15 @@ type Cmd struct {
 	Include, Exclude []string
 }
 
+func (c *Cmd) configFileOptions() eks.ConfigFileOptions {
+	return eks.ConfigFileOptions{
+		Template:   c.ClusterConfigTemplate,
+		Vars:       c.ClusterConfigVars,
+		Checksum:   c.ClusterConfigChecksum,
+		AllowHooks: c.ClusterConfigAllowHooks,
+	}
+}
+
 // NewCtl performs common defaulting and validation and constructs a new
 // instance of eks.ClusterProvider, it may return an error if configuration
 // is invalid or region is not supported
@@ -58,6 +72,16 @@ func (c *Cmd) NewCtl() (*eks.ClusterProvider, error) {
 		}
 	}
 
+	if err := eks.ValidateEndpoints(c.ProviderConfig); err != nil {
+		return nil, err
+	}
+	if err := eks.ValidateCABundle(c.ProviderConfig); err != nil {
+		return nil, err
+	}
+	if err := eks.ValidateAssumeRole(c.ProviderConfig); err != nil {
+		return nil, err
+	}
+
 	ctl := eks.New(c.ProviderConfig, c.ClusterConfig)
 
 	if !ctl.IsSupportedRegion() {
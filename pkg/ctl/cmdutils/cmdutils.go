@@ -6,13 +6,15 @@ import (
 	"strings"
 	"time"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/printers"
 	"github.com/weaveworks/eksctl/pkg/utils/kubeconfig"
+	"github.com/weaveworks/eksctl/pkg/utils/prompt"
 	"github.com/weaveworks/eksctl/pkg/version"
 )
 
@@ -87,10 +89,30 @@ func AddApproveFlag(fs *pflag.FlagSet, cmd *Cmd) {
 	AddPreRun(cmd.CobraCommand, func(cobraCmd *cobra.Command, args []string) {
 		if cobraCmd.Flag("approve").Changed {
 			cmd.Plan = !*approve
+		} else if prompt.Default.AssumeYes {
+			cmd.Plan = false
 		}
 	})
 }
 
+// ConfirmChangeSet returns a reviewer of stack updates that applies them when approved is set,
+// e.g. with `--approve`, and otherwise asks for confirmation after the changes are shown
+func ConfirmChangeSet(approved bool) manager.ChangeSetReviewer {
+	return func(stackName string, _ *manager.ChangeSet) (bool, error) {
+		if approved {
+			return true, nil
+		}
+		confirmed, err := prompt.Confirm("apply-stack-changes", fmt.Sprintf("apply the changes to stack %q", stackName))
+		if err != nil {
+			return false, err
+		}
+		if !confirmed {
+			return false, fmt.Errorf("the changes to stack %q were not applied", stackName)
+		}
+		return true, nil
+	}
+}
+
 // GetNameArg tests to ensure there is only 1 name argument
 func GetNameArg(args []string) string {
 	if len(args) > 1 {
@@ -116,6 +138,19 @@ func AddCommonFlagsForAWS(group *NamedFlagSetGroup, p *api.ProviderConfig, cfnRo
 		if cfnRole {
 			fs.StringVar(&p.CloudFormationRoleARN, "cfn-role-arn", "", "IAM role used by CloudFormation to call AWS API on your behalf")
 		}
+		fs.StringVar(&p.EndpointURL, "endpoint-url", "", "override the endpoints of all AWS services (overrides the AWS_ENDPOINT_URL environment variable)")
+		fs.StringVar(&p.CABundle, "ca-bundle", "", "path to PEM certificates to trust when connecting to AWS and Kubernetes APIs, e.g. of a TLS-intercepting proxy (overrides the AWS_CA_BUNDLE environment variable)")
+		fs.BoolVar(&p.UseFIPSEndpoints, "use-fips-endpoints", false, "use the FIPS endpoints of all AWS services (overrides the AWS_USE_FIPS_ENDPOINT environment variable)")
+		fs.DurationVar(&p.CacheTTL, "cache-ttl", 0, "cache AWS metadata that rarely changes, e.g. AMIs, instance types and availability zones, for the given duration (overrides the EKSCTL_CACHE_TTL environment variable)")
+		fs.StringVar(&p.CacheDir, "cache-dir", "", "directory of the cache enabled with --cache-ttl (overrides the EKSCTL_CACHE_DIR environment variable, defaults to the eksctl directory in the user cache directory)")
+		fs.StringToStringVar(&p.ServiceEndpoints, "service-endpoint", nil, "override the endpoint of an AWS service, e.g. sts=https://sts.example.com (overrides the AWS_ENDPOINT_URL_<SERVICE> environment variables)")
+		fs.StringVar(&p.AssumeRole.RoleARN, "assume-role-arn", "", "IAM role to assume for all AWS calls, with the credentials of the profile or environment")
+		fs.StringVar(&p.AssumeRole.ExternalID, "assume-role-external-id", "", "external ID of the role of --assume-role-arn")
+		fs.StringVar(&p.AssumeRole.SessionName, "assume-role-session-name", "", "session name of the assumed roles, defaults to eksctl-<timestamp>")
+		fs.DurationVar(&p.AssumeRole.Duration, "assume-role-duration", 0, "duration of the session of --assume-role-arn, between 15m and 12h (default 30m)")
+		fs.StringVar(&p.AssumeRole.ChainedRoleARN, "assume-role-chained-arn", "", "IAM role to assume with the credentials of --assume-role-arn, e.g. a role of another account")
+		fs.StringVar(&p.AssumeRole.ChainedExternalID, "assume-role-chained-external-id", "", "external ID of the role of --assume-role-chained-arn")
+		fs.DurationVar(&p.AssumeRole.ChainedDuration, "assume-role-chained-duration", 0, "duration of the session of --assume-role-chained-arn, between 15m and 1h (default 30m)")
 	})
 }
 
@@ -129,6 +164,23 @@ func AddTimeoutFlag(fs *pflag.FlagSet, p *time.Duration) {
 	AddTimeoutFlagWithValue(fs, p, api.DefaultWaitTimeout)
 }
 
+// AddPhaseTimeoutFlags configures the flags of the timeouts of phases, which take precedence
+// over the timeout flag for their phase.
+func AddPhaseTimeoutFlags(fs *pflag.FlagSet, timeouts *api.PhaseTimeouts, phases ...api.TimeoutPhase) {
+	for _, phase := range phases {
+		switch phase {
+		case api.TimeoutPhaseControlPlane:
+			fs.DurationVar(&timeouts.ControlPlane, phase.FlagName(), 0, "maximum waiting time for the control plane to be created and reachable (defaults to --timeout)")
+		case api.TimeoutPhaseNodeGroups:
+			fs.DurationVar(&timeouts.NodeGroups, phase.FlagName(), 0, "maximum waiting time for each nodegroup to be created and its nodes to be ready (defaults to --timeout)")
+		case api.TimeoutPhaseAddons:
+			fs.DurationVar(&timeouts.Addons, phase.FlagName(), 0, "maximum waiting time for each EKS add-on to become active (defaults to --timeout)")
+		case api.TimeoutPhaseCleanup:
+			fs.DurationVar(&timeouts.Cleanup, phase.FlagName(), api.DefaultCleanupTimeout, "maximum time to clean up the resources left by Kubernetes, e.g. the load balancers of services")
+		}
+	}
+}
+
 // AddClusterFlag adds a common --cluster flag for cluster name.
 // Use this for commands whose principal resource is *not* a cluster.
 func AddClusterFlag(fs *pflag.FlagSet, meta *api.ClusterMeta) {
@@ -182,6 +234,16 @@ func AddUpdateAuthConfigMap(fs *pflag.FlagSet, updateAuthConfigMap *bool, descri
 	fs.BoolVar(updateAuthConfigMap, "update-auth-configmap", true, description)
 }
 
+// AddReportAMIVulnerabilitiesFlag adds common --report-ami-vulnerabilities flag
+func AddReportAMIVulnerabilitiesFlag(fs *pflag.FlagSet, report *bool) {
+	fs.BoolVar(report, "report-ami-vulnerabilities", false, "after creating nodegroups, report the vulnerabilities Amazon Inspector found in the AMIs of their nodes")
+}
+
+// AddOverrideBudgetFlag adds common --override-budget flag
+func AddOverrideBudgetFlag(fs *pflag.FlagSet, override *bool) {
+	fs.BoolVar(override, "override-budget", false, "proceed even if the estimated monthly cost of the cluster exceeds budget.maxMonthlyUSD")
+}
+
 // AddCommonFlagsForKubeconfig adds common flags for controlling how output kubeconfig is written
 func AddCommonFlagsForKubeconfig(fs *pflag.FlagSet, outputPath, authenticatorRoleARN *string, setContext, autoPath *bool, exampleName string) {
 	fs.StringVar(outputPath, "kubeconfig", kubeconfig.DefaultPath, "path to write kubeconfig (incompatible with --auto-kubeconfig)")
@@ -232,3 +294,22 @@ func ErrUnsupportedManagedFlag(flag string) error {
 func ErrUnsupportedNameArg() error {
 	return errors.New("name argument is not supported")
 }
+
+// ExitCodeError makes eksctl exit with Code instead of 1, e.g. to tell failed checks
+// apart from errors running a command
+type ExitCodeError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitCodeError) Error() string {
+	return e.Err.Error()
+}
+
+// ExitCode returns the code eksctl exits with after err
+func ExitCode(err error) int {
+	if exitCodeErr, ok := err.(*ExitCodeError); ok {
+		return exitCodeErr.Code
+	}
+	return 1
+}
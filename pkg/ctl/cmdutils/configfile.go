@@ -14,9 +14,13 @@ import (
 	"github.com/weaveworks/eksctl/pkg/utils/names"
 )
 
-// AddConfigFileFlag adds common --config-file flag
-func AddConfigFileFlag(fs *pflag.FlagSet, path *string) {
-	fs.StringVarP(path, "config-file", "f", "", "load configuration from a file (or stdin if set to '-')")
+// AddConfigFileFlag adds common --config-file, --config-file-checksum, --set, --template and --allow-hooks flags
+func AddConfigFileFlag(fs *pflag.FlagSet, cmd *Cmd) {
+	fs.StringVarP(&cmd.ClusterConfigFile, "config-file", "f", "", "load configuration from a file (or stdin if set to '-'), or from an s3://, https:// or oci:// URL")
+	fs.StringVar(&cmd.ClusterConfigChecksum, "config-file-checksum", "", "expected SHA-256 digest of the config file (e.g. sha256:<hex>)")
+	fs.StringToStringVar(&cmd.ClusterConfigVars, "set", map[string]string{}, `variables used to render the config file as a template (e.g. "env=prod,instanceType=m5.large")`)
+	fs.BoolVar(&cmd.ClusterConfigTemplate, "template", false, "render the config file as a template without variables, e.g. to read environment variables (implied by --set)")
+	fs.BoolVar(&cmd.ClusterConfigAllowHooks, "allow-hooks", false, "run the command hooks of a config file from an s3://, https:// or oci:// URL without --config-file-checksum")
 }
 
 // ClusterConfigLoader is an interface that loaders should implement
@@ -45,6 +49,10 @@ var (
 		"include",
 		"exclude",
 		"only-missing",
+		"set",
+		"template",
+		"config-file-checksum",
+		"allow-hooks",
 	)
 )
 
@@ -81,7 +89,7 @@ func (l *commonClusterConfigLoader) Load() error {
 	// The reference to ClusterConfig should only be reassigned if ClusterConfigFile is specified
 	// because other parts of the code store the pointer locally and access it directly instead of via
 	// the Cmd reference
-	if l.ClusterConfig, err = eks.LoadConfigFromFile(l.ClusterConfigFile); err != nil {
+	if l.ClusterConfig, err = eks.LoadConfigFromFileWithOptions(l.ClusterConfigFile, l.configFileOptions()); err != nil {
 		return err
 	}
 	meta := l.ClusterConfig.Metadata
@@ -175,6 +183,9 @@ func NewCreateClusterLoader(cmd *Cmd, ngFilter *NodeGroupFilter, ng *api.NodeGro
 		"vpc-cidr",
 		"vpc-nat-mode",
 		"vpc-from-kops-cluster",
+		"like",
+		"like-region",
+		"activate-cost-allocation-tags",
 	)
 
 	l.flagsIncompatibleWithoutConfigFile.Insert("install-vpc-controllers")
@@ -228,7 +239,13 @@ func NewCreateClusterLoader(cmd *Cmd, ngFilter *NodeGroupFilter, ng *api.NodeGro
 
 		// prevent creation of invalid config object with irrelevant nodegroup
 		// that may or may not be constructed correctly
-		if !params.WithoutNodeGroup {
+		if params.Like != "" {
+			for _, f := range []string{"managed", "fargate", "nodegroup-name"} {
+				if flag := l.CobraCommand.Flag(f); flag != nil && flag.Changed {
+					return fmt.Errorf("--like and --%s %s", f, IncompatibleFlags)
+				}
+			}
+		} else if !params.WithoutNodeGroup {
 			if params.Managed {
 				l.ClusterConfig.ManagedNodeGroups = []*api.ManagedNodeGroup{makeManagedNodegroup(ng)}
 			} else {
@@ -242,6 +259,10 @@ func NewCreateClusterLoader(cmd *Cmd, ngFilter *NodeGroupFilter, ng *api.NodeGro
 			l.ClusterConfig.NodeGroups = []*api.NodeGroup{}
 		}
 
+		if params.ActivateCostAllocationTags {
+			l.ClusterConfig.Billing = &api.Billing{ActivateCostAllocationTags: api.Enabled()}
+		}
+
 		for _, ng := range l.ClusterConfig.NodeGroups {
 			// generate nodegroup name or use flag
 			ng.Name = names.ForNodeGroup(ng.Name, "")
@@ -333,6 +354,32 @@ func NewCreateNodeGroupLoader(cmd *Cmd, ng *api.NodeGroup, ngFilter *NodeGroupFi
 	return l
 }
 
+// NewReplaceNodeGroupLoader loads the config file defining the nodegroup that replaces the
+// nodegroup named oldNodeGroupName; unlike other commands, --cluster and --name can be used
+// with the config file, as --name refers to the nodegroup being replaced
+func NewReplaceNodeGroupLoader(cmd *Cmd, oldNodeGroupName string, ngFilter *NodeGroupFilter) ClusterConfigLoader {
+	l := newCommonClusterConfigLoader(cmd)
+
+	clusterName := cmd.ClusterConfig.Metadata.Name
+	l.flagsIncompatibleWithConfigFile = sets.NewString("region", "version")
+
+	l.validateWithoutConfigFile = func() error {
+		return ErrMustBeSet("--config-file/-f with the new nodegroup")
+	}
+
+	l.validateWithConfigFile = func() error {
+		if oldNodeGroupName == "" {
+			return ErrMustBeSet("--name")
+		}
+		if clusterName != "" && clusterName != l.ClusterConfig.Metadata.Name {
+			return fmt.Errorf("--cluster=%s doesn't match metadata.name of the config file (%s)", clusterName, l.ClusterConfig.Metadata.Name)
+		}
+		return ngFilter.AppendGlobs(l.Include, l.Exclude, getAllNodeGroupNames(l.ClusterConfig))
+	}
+
+	return l
+}
+
 func makeManagedNodegroup(nodeGroup *api.NodeGroup) *api.ManagedNodeGroup {
 	return &api.ManagedNodeGroup{
 		AvailabilityZones: nodeGroup.AvailabilityZones,
@@ -409,6 +456,107 @@ func NewDeleteNodeGroupLoader(cmd *Cmd, ng *api.NodeGroup, ngFilter *NodeGroupFi
 	return l
 }
 
+// NewScaleNodeGroupLoader will load config or use flags for 'eksctl scale nodegroup'
+func NewScaleNodeGroupLoader(cmd *Cmd, ng *api.NodeGroup, ngFilter *NodeGroupFilter) ClusterConfigLoader {
+	l := newCommonClusterConfigLoader(cmd)
+
+	l.flagsIncompatibleWithConfigFile.Insert(
+		"nodes",
+	)
+
+	l.validateWithConfigFile = func() error {
+		return ngFilter.AppendGlobs(l.Include, l.Exclude, getAllNodeGroupNames(l.ClusterConfig))
+	}
+
+	l.validateWithoutConfigFile = func() error {
+		if l.ClusterConfig.Metadata.Name == "" {
+			return ErrMustBeSet(ClusterNameFlag(cmd))
+		}
+
+		if ng.Name != "" && l.NameArg != "" {
+			return ErrFlagAndArg("--name", ng.Name, l.NameArg)
+		}
+
+		if l.NameArg != "" {
+			ng.Name = l.NameArg
+		}
+
+		if ng.Name == "" {
+			return ErrMustBeSet("--name")
+		}
+
+		if ng.DesiredCapacity == nil || *ng.DesiredCapacity < 0 {
+			return fmt.Errorf("number of nodes must be 0 or greater. Use the --nodes/-N flag")
+		}
+
+		l.ClusterConfig.NodeGroups = []*api.NodeGroup{ng}
+
+		return nil
+	}
+
+	return l
+}
+
+// NewUpgradeNodeGroupLoader will load config or use flags for 'eksctl upgrade nodegroup'
+func NewUpgradeNodeGroupLoader(cmd *Cmd, ngName *string, ngFilter *NodeGroupFilter) ClusterConfigLoader {
+	l := newCommonClusterConfigLoader(cmd)
+
+	l.validateWithConfigFile = func() error {
+		return ngFilter.AppendGlobs(l.Include, l.Exclude, getAllNodeGroupNames(l.ClusterConfig))
+	}
+
+	l.validateWithoutConfigFile = func() error {
+		if l.ClusterConfig.Metadata.Name == "" {
+			return ErrMustBeSet(ClusterNameFlag(cmd))
+		}
+
+		if *ngName != "" && l.NameArg != "" {
+			return ErrFlagAndArg("--name", *ngName, l.NameArg)
+		}
+
+		if l.NameArg != "" {
+			*ngName = l.NameArg
+		}
+
+		if *ngName == "" {
+			return ErrMustBeSet("--name")
+		}
+
+		l.ClusterConfig.ManagedNodeGroups = []*api.ManagedNodeGroup{{Name: *ngName}}
+
+		return nil
+	}
+
+	return l
+}
+
+// NewRollbackNodeGroupLoader will use flags for 'eksctl rollback nodegroup'
+func NewRollbackNodeGroupLoader(cmd *Cmd, ngName *string) ClusterConfigLoader {
+	l := newCommonClusterConfigLoader(cmd)
+
+	l.validateWithoutConfigFile = func() error {
+		if l.ClusterConfig.Metadata.Name == "" {
+			return ErrMustBeSet(ClusterNameFlag(cmd))
+		}
+
+		if *ngName != "" && l.NameArg != "" {
+			return ErrFlagAndArg("--name", *ngName, l.NameArg)
+		}
+
+		if l.NameArg != "" {
+			*ngName = l.NameArg
+		}
+
+		if *ngName == "" {
+			return ErrMustBeSet("--name")
+		}
+
+		return nil
+	}
+
+	return l
+}
+
 // NewUtilsEnableLoggingLoader will load config or use flags for 'eksctl utils update-cluster-logging'
 func NewUtilsEnableLoggingLoader(cmd *Cmd) ClusterConfigLoader {
 	l := newCommonClusterConfigLoader(cmd)
@@ -430,6 +578,8 @@ func NewUtilsEnableEndpointAccessLoader(cmd *Cmd, privateAccess, publicAccess bo
 	l.flagsIncompatibleWithConfigFile.Insert(
 		"private-access",
 		"public-access",
+		"add-public-access-cidrs",
+		"remove-public-access-cidrs",
 	)
 	l.validateWithoutConfigFile = func() error {
 		if err := l.validateMetadataWithoutConfigFile(); err != nil {
@@ -502,6 +652,67 @@ func NewUtilsPublicAccessCIDRsLoader(cmd *Cmd) ClusterConfigLoader {
 	return l
 }
 
+// NewUtilsUpdateClusterVPCConfigLoader will load config or use flags for 'eksctl utils update-cluster-vpc-config'
+func NewUtilsUpdateClusterVPCConfigLoader(cmd *Cmd) ClusterConfigLoader {
+	l := newCommonClusterConfigLoader(cmd)
+
+	l.flagsIncompatibleWithConfigFile.Insert("subnets", "security-groups")
+
+	l.validateWithConfigFile = func() error {
+		if l.ClusterConfig.VPC == nil || (len(l.ClusterConfig.VPC.ControlPlaneSubnetIDs) == 0 && len(l.ClusterConfig.VPC.SecurityGroupIDs) == 0) {
+			return errors.New("field vpc.controlPlaneSubnetIDs or vpc.securityGroupIDs is required")
+		}
+		return nil
+	}
+
+	l.validateWithoutConfigFile = func() error {
+		if len(l.ClusterConfig.VPC.ControlPlaneSubnetIDs) == 0 && len(l.ClusterConfig.VPC.SecurityGroupIDs) == 0 {
+			return errors.New("--subnets or --security-groups must be set")
+		}
+		return l.validateMetadataWithoutConfigFile()
+	}
+	return l
+}
+
+// NewUtilsRetagClusterLoader will load config or use flags for 'eksctl utils retag-cluster'
+func NewUtilsRetagClusterLoader(cmd *Cmd) ClusterConfigLoader {
+	l := newCommonClusterConfigLoader(cmd)
+
+	l.flagsIncompatibleWithConfigFile.Insert("tags")
+
+	l.validateWithoutConfigFile = func() error {
+		if len(l.ClusterConfig.Metadata.Tags) == 0 {
+			return ErrMustBeSet("--tags")
+		}
+		return l.validateMetadataWithoutConfigFile()
+	}
+
+	return l
+}
+
+// NewUtilsUpdateAuthenticationModeLoader will load config or use flags for 'eksctl utils update-authentication-mode'
+func NewUtilsUpdateAuthenticationModeLoader(cmd *Cmd) ClusterConfigLoader {
+	l := newCommonClusterConfigLoader(cmd)
+
+	l.flagsIncompatibleWithConfigFile.Insert("authentication-mode")
+
+	l.validateWithConfigFile = func() error {
+		if !l.ClusterConfig.HasAuthenticationMode() {
+			return errors.New("field accessConfig.authenticationMode is required")
+		}
+		return nil
+	}
+
+	l.validateWithoutConfigFile = func() error {
+		if !l.ClusterConfig.HasAuthenticationMode() {
+			return ErrMustBeSet("--authentication-mode")
+		}
+		return l.validateMetadataWithoutConfigFile()
+	}
+
+	return l
+}
+
 func parseCIDRs(arg string) ([]string, error) {
 	reader := strings.NewReader(arg)
 	csvReader := csv.NewReader(reader)
@@ -514,6 +725,9 @@ func NewCreateIAMServiceAccountLoader(cmd *Cmd, saFilter *IAMServiceAccountFilte
 
 	l.flagsIncompatibleWithConfigFile.Insert(
 		"policy-arn",
+		"role-name",
+		"attach-role-arn",
+		"role-only",
 	)
 
 	l.validateWithConfigFile = func() error {
@@ -535,8 +749,8 @@ func NewCreateIAMServiceAccountLoader(cmd *Cmd, saFilter *IAMServiceAccountFilte
 			return ErrMustBeSet(ClusterNameFlag(cmd))
 		}
 
-		if len(serviceAccount.AttachPolicyARNs) == 0 {
-			return ErrMustBeSet("--attach-policy-arn")
+		if len(serviceAccount.AttachPolicyARNs) == 0 && serviceAccount.AttachRoleARN == "" {
+			return ErrMustBeSet("--attach-policy-arn or --attach-role-arn")
 		}
 
 		return nil
@@ -620,3 +834,64 @@ func NewDeleteIAMServiceAccountLoader(cmd *Cmd, sa *api.ClusterIAMServiceAccount
 
 	return l
 }
+
+// NewCreateAddonLoader will load config or use flags for 'eksctl create addon'
+func NewCreateAddonLoader(cmd *Cmd, addon *api.Addon) ClusterConfigLoader {
+	l := newCommonClusterConfigLoader(cmd)
+
+	l.flagsIncompatibleWithConfigFile.Insert(
+		"service-account-role-arn",
+		"attach-policy-arn",
+		"role-name",
+		"force",
+	)
+
+	l.validateWithConfigFile = func() error {
+		if !l.ClusterConfig.HasAddons() {
+			return ErrMustBeSet("addons")
+		}
+		return nil
+	}
+
+	l.validateWithoutConfigFile = func() error {
+		if l.ClusterConfig.Metadata.Name == "" {
+			return ErrMustBeSet(ClusterNameFlag(cmd))
+		}
+		if addon.Name == "" {
+			return ErrMustBeSet("--name")
+		}
+		l.ClusterConfig.Addons = []*api.Addon{addon}
+		return nil
+	}
+
+	return l
+}
+
+// NewDeleteAddonLoader will load config or use flags for 'eksctl delete addon'
+func NewDeleteAddonLoader(cmd *Cmd, name *string) ClusterConfigLoader {
+	l := newCommonClusterConfigLoader(cmd)
+
+	validateName := func() error {
+		if *name != "" && l.NameArg != "" {
+			return ErrFlagAndArg("--name", *name, l.NameArg)
+		}
+		if l.NameArg != "" {
+			*name = l.NameArg
+		}
+		if *name == "" {
+			return ErrMustBeSet("--name")
+		}
+		return nil
+	}
+
+	l.validateWithConfigFile = validateName
+
+	l.validateWithoutConfigFile = func() error {
+		if l.ClusterConfig.Metadata.Name == "" {
+			return ErrMustBeSet(ClusterNameFlag(cmd))
+		}
+		return validateName()
+	}
+
+	return l
+}
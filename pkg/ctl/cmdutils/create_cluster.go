@@ -1,7 +1,11 @@
 package cmdutils
 
 import (
+	"time"
+
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/builder"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
 )
 
 // CreateClusterCmdParams groups CLI options for the create cluster command.
@@ -18,4 +22,31 @@ type CreateClusterCmdParams struct {
 	WithoutNodeGroup            bool
 	Managed                     bool
 	Fargate                     bool
+	WaitForReady                bool
+	WaitForDeployments          []string
+	ReadyTimeout                time.Duration
+	Like                        string
+	LikeRegion                  string
+	ActivateCostAllocationTags  bool
+	ReportAMIVulnerabilities    bool
+	Verify                      bool
+	VerifyTimeout               time.Duration
+	Phases                      []string
+	OverrideBudget              bool
+	RawAPIOverridesFile         string
+	// RawAPIOverrides are loaded from RawAPIOverridesFile
+	RawAPIOverrides *builder.RawAPIOverrides
+}
+
+// ReadinessGates returns the readiness gates the cluster has to pass after creation
+func (p *CreateClusterCmdParams) ReadinessGates() (kubernetes.ReadinessGates, error) {
+	deployments, err := kubernetes.ParseNamespacedNames(p.WaitForDeployments)
+	if err != nil {
+		return kubernetes.ReadinessGates{}, err
+	}
+	return kubernetes.ReadinessGates{
+		Nodes:            p.WaitForReady,
+		SystemComponents: p.WaitForReady,
+		Deployments:      deployments,
+	}, nil
 }
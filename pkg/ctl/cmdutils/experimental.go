@@ -0,0 +1,47 @@
+package cmdutils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+
+	"github.com/weaveworks/eksctl/pkg/cfn/builder"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// ExperimentalEnvVar is the environment variable enabling experimental commands and flags
+const ExperimentalEnvVar = "EKSCTL_EXPERIMENTAL"
+
+// ExperimentalEnabled determines if experimental commands and flags are enabled
+func ExperimentalEnabled() bool {
+	return os.Getenv(ExperimentalEnvVar) == "true"
+}
+
+// AddRawAPIOverridesFlag adds the experimental --raw-api-overrides flag
+func AddRawAPIOverridesFlag(fs *pflag.FlagSet, path *string) {
+	fs.StringVar(path, "raw-api-overrides", "", fmt.Sprintf("experimental, requires %s=true: JSON file with createCluster and createNodegroup fields of the EKS API, merged as they are into the control plane and managed nodegroups", ExperimentalEnvVar))
+}
+
+// LoadRawAPIOverrides loads the file given with --raw-api-overrides, there are no overrides
+// when path is empty
+func LoadRawAPIOverrides(path string) (*builder.RawAPIOverrides, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if !ExperimentalEnabled() {
+		return nil, fmt.Errorf("--raw-api-overrides is experimental, set %s=true to use it", ExperimentalEnvVar)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading --raw-api-overrides")
+	}
+	overrides, err := builder.ParseRawAPIOverrides(data)
+	if err != nil {
+		return nil, err
+	}
+	logger.Warning("--raw-api-overrides is experimental, the fields are sent to EKS without being validated and take precedence over the ones set by eksctl")
+	return overrides, nil
+}
@@ -14,6 +14,7 @@ const (
 	fargateProfileSelectorNamespace = "namespace" // Fargate profile selector's namespace.
 	fargateProfileSelectorLabels    = "labels"    // Fargate profile selector's labels.
 	fargateProfileTags              = "tags"      // Fargate profile tags.
+	fargatePodExecutionRoleARN      = "pod-execution-role-arn"
 )
 
 // AddFlagsForFargate configures the flags required to interact with Fargate.
@@ -34,6 +35,9 @@ func AddFlagsForFargateProfileCreation(fs *pflag.FlagSet, options *fargate.Creat
 
 	fs.StringToStringVarP(&options.Tags, fargateProfileTags, "t", map[string]string{},
 		`A list of KV pairs used to tag the AWS resources (e.g. "Owner=John Doe,Team=Some Team")`)
+
+	fs.StringVar(&options.PodExecutionRoleARN, fargatePodExecutionRoleARN, "",
+		"ARN of an existing pod execution role of the profile (defaults to the role created with the cluster)")
 }
 
 func addFargateProfileName(fs *pflag.FlagSet, profileName *string) {
@@ -47,6 +51,7 @@ var fargateProfileFlagsIncompatibleWithConfigFile = []string{
 	fargateProfileName,
 	fargateProfileSelectorNamespace,
 	fargateProfileSelectorLabels,
+	fargatePodExecutionRoleARN,
 }
 
 // Flags which also require a ClusterConfig file to be provided.
@@ -5,16 +5,21 @@ import (
 	"strings"
 
 	"github.com/gobwas/glob"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 
 	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 // Filter holds filter configuration
 type Filter struct {
 	ExcludeAll bool // highest priority
 
+	// existing resources excluded in only-missing mode, these
+	// are excluded even if they match the include filters
+	existingNames sets.String
+
 	// include filters take precedence
 	includeNames    sets.String
 	includeGlobs    []glob.Glob
@@ -78,6 +83,10 @@ func (f *Filter) Match(name string) bool {
 		return false // force exclude
 	}
 
+	if f.existingNames.Has(name) {
+		return false
+	}
+
 	hasIncludeRules := f.hasIncludeRules()
 	hasExcludeRules := f.hasExcludeRules()
 
@@ -159,6 +168,19 @@ func (f *Filter) doSetExcludeExistingFilter(names []string, resource string) err
 	return nil
 }
 
+// doSetOnlyMissingFilter excludes the existing resources, unlike doSetExcludeExistingFilter
+// it doesn't fail when the include filter matches any of them
+func (f *Filter) doSetOnlyMissingFilter(names []string, resource string) {
+	uniqueNames := sets.NewString(names...).List()
+	if f.existingNames == nil {
+		f.existingNames = sets.NewString()
+	}
+	f.existingNames.Insert(uniqueNames...)
+	if len(uniqueNames) != 0 {
+		logger.Info("%d %s(s) that already exist (%s) will be skipped", len(uniqueNames), resource, strings.Join(uniqueNames, ","))
+	}
+}
+
 func (f *Filter) includeGlobsMatchAnything(names []string, resource string) error {
 	if len(f.includeGlobs) == 0 {
 		return nil
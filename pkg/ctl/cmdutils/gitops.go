@@ -109,7 +109,7 @@ func NewGitOpsConfigLoader(cmd *Cmd) ClusterConfigLoader {
 			"version",
 			"cluster",
 		),
-		flagsIncompatibleWithoutConfigFile: sets.NewString(),
+		flagsIncompatibleWithoutConfigFile: sets.NewString("set", "template", "config-file-checksum", "allow-hooks"),
 	}
 
 	l.validateWithoutConfigFile = func() error {
@@ -159,7 +159,7 @@ func (l *gitOpsConfigLoader) Load() error {
 	// The reference to ClusterConfig should only be reassigned if ClusterConfigFile is specified
 	// because other parts of the code store the pointer locally and access it directly instead of via
 	// the Cmd reference
-	if l.cmd.ClusterConfig, err = eks.LoadConfigFromFile(l.cmd.ClusterConfigFile); err != nil {
+	if l.cmd.ClusterConfig, err = eks.LoadConfigFromFileWithOptions(l.cmd.ClusterConfigFile, l.cmd.configFileOptions()); err != nil {
 		return err
 	}
 	meta := l.cmd.ClusterConfig.Metadata
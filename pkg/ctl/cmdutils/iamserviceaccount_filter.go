@@ -3,10 +3,10 @@ package cmdutils
 import (
 	"k8s.io/apimachinery/pkg/util/sets"
 
-	"github.com/kris-nova/logger"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 // IAMServiceAccountFilter holds filter configuration
@@ -38,10 +38,11 @@ func (f *IAMServiceAccountFilter) AppendIncludeGlobs(serviceAccounts []*api.Clus
 	return f.doAppendIncludeGlobs(f.collectNames(serviceAccounts), "iamserviceaccount", globExprs...)
 }
 
-// SetExcludeExistingFilter uses stackManager to list existing nodegroup stacks and configures
-// the filter accordingly
+// SetExcludeExistingFilter uses stackManager to list existing iamserviceaccount stacks and configures
+// the filter accordingly; with overrideExistingServiceAccounts, nothing is excluded, the existing
+// stacks and serviceaccounts are adopted
 func (f *IAMServiceAccountFilter) SetExcludeExistingFilter(stackManager *manager.StackCollection, clientSet kubernetes.Interface, serviceAccounts []*api.ClusterIAMServiceAccount, overrideExistingServiceAccounts bool) error {
-	if f.ExcludeAll {
+	if f.ExcludeAll || overrideExistingServiceAccounts {
 		return nil
 	}
 
@@ -50,20 +51,22 @@ func (f *IAMServiceAccountFilter) SetExcludeExistingFilter(stackManager *manager
 		return err
 	}
 
-	if !overrideExistingServiceAccounts {
-		err := f.ForEach(serviceAccounts, func(_ int, sa *api.ClusterIAMServiceAccount) error {
-			exists, err := kubernetes.CheckServiceAccountExists(clientSet, sa.ObjectMeta)
-			if err != nil {
-				return err
-			}
-			if exists {
-				existing = append(existing, sa.NameString())
-			}
+	err = f.ForEach(serviceAccounts, func(_ int, sa *api.ClusterIAMServiceAccount) error {
+		// the serviceaccount of a role-only iamserviceaccount is left to other tools
+		if api.IsEnabled(sa.RoleOnly) {
 			return nil
-		})
+		}
+		exists, err := kubernetes.CheckServiceAccountExists(clientSet, sa.ObjectMeta)
 		if err != nil {
 			return err
 		}
+		if exists {
+			existing = append(existing, sa.NameString())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	return f.doSetExcludeExistingFilter(existing, "iamserviceaccount")
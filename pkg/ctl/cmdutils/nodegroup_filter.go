@@ -1,11 +1,11 @@
 package cmdutils
 
 import (
-	"github.com/kris-nova/logger"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 // NodeGroupFilter holds filter configuration
@@ -62,6 +62,27 @@ func (f *NodeGroupFilter) SetExcludeExistingFilter(lister stackLister) error {
 	return f.doSetExcludeExistingFilter(ngNames, "nodegroup")
 }
 
+// SetOnlyMissingFilter uses stackManager to list existing nodegroup stacks and configures
+// the filter to skip them, regardless of the include rules
+func (f *NodeGroupFilter) SetOnlyMissingFilter(lister stackLister) error {
+	if f.ExcludeAll {
+		return nil
+	}
+
+	existingStacks, err := lister.ListNodeGroupStacks()
+	if err != nil {
+		return err
+	}
+
+	var ngNames []string
+	for _, s := range existingStacks {
+		ngNames = append(ngNames, s.NodeGroupName)
+	}
+
+	f.doSetOnlyMissingFilter(ngNames, "nodegroup")
+	return nil
+}
+
 // SetIncludeOrExcludeMissingFilter uses stackLister to list existing nodegroup stacks and configures
 // the filter to either explicitly exclude or include nodegroups that are missing from given nodeGroups
 func (f *NodeGroupFilter) SetIncludeOrExcludeMissingFilter(lister stackLister, includeOnlyMissing bool, clusterConfig *api.ClusterConfig) error {
@@ -7,11 +7,20 @@ import (
 	. "github.com/onsi/gomega"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 	"github.com/weaveworks/eksctl/pkg/printers"
 
 	. "github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
 )
 
+type mockStackLister struct {
+	nodeGroupStacks []manager.NodeGroupStack
+}
+
+func (s *mockStackLister) ListNodeGroupStacks() ([]manager.NodeGroupStack, error) {
+	return s.nodeGroupStacks, nil
+}
+
 var _ = Describe("nodegroup filter", func() {
 
 	getNodeGroupNames := func(clusterConfig *api.ClusterConfig) []string {
@@ -135,6 +144,44 @@ var _ = Describe("nodegroup filter", func() {
 		})
 	})
 
+	Context("existing nodegroups", func() {
+		var (
+			filter *NodeGroupFilter
+			cfg    *api.ClusterConfig
+			lister *mockStackLister
+		)
+
+		BeforeEach(func() {
+			cfg = newClusterConfig()
+			addGroupA(cfg)
+
+			filter = NewNodeGroupFilter()
+			lister = &mockStackLister{
+				nodeGroupStacks: []manager.NodeGroupStack{{NodeGroupName: "test-ng1a"}},
+			}
+		})
+
+		It("should fail to exclude existing nodegroups that match the include filter", func() {
+			err := filter.AppendIncludeGlobs(getNodeGroupNames(cfg), "test-ng?a")
+			Expect(err).ToNot(HaveOccurred())
+
+			err = filter.SetExcludeExistingFilter(lister)
+			Expect(err).To(MatchError(`existing nodegroup "test-ng1a" should be excluded, but matches include filter: test-ng?a`))
+		})
+
+		It("should skip existing nodegroups that match the include filter in only-missing mode", func() {
+			err := filter.AppendIncludeGlobs(getNodeGroupNames(cfg), "test-ng?a")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(filter.SetOnlyMissingFilter(lister)).To(Succeed())
+
+			included, excluded := filter.MatchAll(cfg.NodeGroups)
+			Expect(included).To(HaveLen(2))
+			Expect(included.HasAll("test-ng2a", "test-ng3a")).To(BeTrue())
+			Expect(excluded.List()).To(ConsistOf("test-ng1a"))
+		})
+	})
+
 	Context("ForEach", func() {
 
 		It("should iterate over unique nodegroups, apply defaults and validate", func() {
@@ -0,0 +1,74 @@
+package create
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+)
+
+func createAddonCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	addon := &api.Addon{}
+	var force bool
+
+	cmd.SetDescription("addon", "Create EKS add-on(s)",
+		"Create an EKS add-on, or with a config file, the add-ons in its addons section")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		if cmd.NameArg != "" {
+			return cmdutils.ErrUnsupportedNameArg()
+		}
+		if force {
+			addon.ResolveConflicts = api.ResolveConflictsOverwrite
+		}
+		return doCreateAddon(cmd, addon)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("Addon", func(fs *pflag.FlagSet) {
+		fs.StringVar(&addon.Name, "name", "", "name of the add-on, e.g. aws-ebs-csi-driver")
+		fs.StringVar(&addon.Version, "version", "", "version of the add-on, defaults to the default version for the Kubernetes version of the cluster")
+		fs.StringVar(&addon.ServiceAccountRoleARN, "service-account-role-arn", "", "ARN of the IAM role of the service account of the add-on")
+		fs.StringSliceVar(&addon.AttachPolicyARNs, "attach-policy-arn", nil, "ARN of a policy to attach to the IAM role eksctl creates for the service account of the add-on, along with the policy the add-on needs")
+		fs.StringVar(&addon.RoleName, "role-name", "", "name of the IAM role eksctl creates for the service account of the add-on, may contain {ClusterName}, {Namespace} and {Name}")
+		fs.BoolVar(&force, "force", false, "overwrite the Kubernetes objects of the add-on that already exist")
+	})
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doCreateAddon(cmd *cmdutils.Cmd, addon *api.Addon) error {
+	if err := cmdutils.NewCreateAddonLoader(cmd, addon).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(meta)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	if ok, err := ctl.CanOperate(cfg); !ok {
+		return err
+	}
+
+	return ctl.CreateAddons(cfg)
+}
@@ -3,8 +3,8 @@ package create
 import (
 	"fmt"
 	"strings"
+	"time"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -15,7 +15,11 @@ import (
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/authconfigmap"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/hooks"
 	"github.com/weaveworks/eksctl/pkg/kops"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/notifications"
 	"github.com/weaveworks/eksctl/pkg/printers"
 	"github.com/weaveworks/eksctl/pkg/utils"
 	"github.com/weaveworks/eksctl/pkg/utils/kubeconfig"
@@ -52,11 +56,27 @@ func createClusterCmdWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.C
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
 		fs.StringSliceVar(&params.AvailabilityZones, "zones", nil, "(auto-select if unspecified)")
 		cmdutils.AddVersionFlag(fs, cfg.Metadata, "")
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddConfigFileFlag(fs, cmd)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+		cmdutils.AddPhaseTimeoutFlags(fs, &cmd.ProviderConfig.Timeouts, api.TimeoutPhaseControlPlane, api.TimeoutPhaseNodeGroups, api.TimeoutPhaseAddons)
 		fs.BoolVarP(&params.InstallWindowsVPCController, "install-vpc-controllers", "", false, "Install VPC controller that's required for Windows workloads")
 		fs.BoolVarP(&params.Managed, "managed", "", false, "Create EKS-managed nodegroup")
 		fs.BoolVarP(&params.Fargate, "fargate", "", false, "Create a Fargate profile scheduling pods in the default and kube-system namespaces onto Fargate")
+		fs.StringVar(&params.Like, "like", "", "name of an existing cluster to copy the version, networking, logging, OIDC provider, nodegroups and Fargate profiles of")
+		fs.StringVar(&params.LikeRegion, "like-region", "", "region of the cluster given with --like (defaults to the region of the new cluster)")
+		cmdutils.AddReportAMIVulnerabilitiesFlag(fs, &params.ReportAMIVulnerabilities)
+		cmdutils.AddOverrideBudgetFlag(fs, &params.OverrideBudget)
+		fs.BoolVar(&params.ActivateCostAllocationTags, "activate-cost-allocation-tags", false, "activate the eks:cluster-name cost allocation tag in the payer account, to group costs by cluster in the Billing console")
+		fs.StringSliceVar(&params.Phases, "phases", nil, fmt.Sprintf("only run the given phases of the creation, out of %s; requires --config-file, the phases that already ran are detected from the cluster", strings.Join(orderedCreatePhases, ",")))
+		cmdutils.AddRawAPIOverridesFlag(fs, &params.RawAPIOverridesFile)
+	})
+
+	cmd.FlagSetGroup.InFlagSet("Readiness gates", func(fs *pflag.FlagSet) {
+		fs.BoolVar(&params.WaitForReady, "wait-for-ready", false, "wait for all nodes to be ready and the coredns, kube-proxy and aws-node rollouts to complete")
+		fs.StringSliceVar(&params.WaitForDeployments, "wait-for-deployments", nil, "wait for the given <namespace>/<name> deployments to be rolled out and available")
+		fs.DurationVar(&params.ReadyTimeout, "ready-timeout", 10*time.Minute, "maximum time to wait for the readiness gates to pass")
+		fs.BoolVar(&params.Verify, "verify", false, "after creating the cluster, run a test workload checking scheduling on every nodegroup, DNS, pulling from ECR and volume provisioning")
+		fs.DurationVar(&params.VerifyTimeout, "verify-timeout", 5*time.Minute, "maximum time to wait for the checks of --verify")
 	})
 
 	cmd.FlagSetGroup.InFlagSet("Initial nodegroup", func(fs *pflag.FlagSet) {
@@ -93,9 +113,35 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 		return err
 	}
 
+	if params.Like != "" {
+		if err := applyLike(cmd, params); err != nil {
+			return err
+		}
+	}
+
 	cfg := cmd.ClusterConfig
 	meta := cmd.ClusterConfig.Metadata
 
+	readinessGates, err := params.ReadinessGates()
+	if err != nil {
+		return err
+	}
+
+	phases, err := parseCreatePhases(params.Phases)
+	if err != nil {
+		return err
+	}
+	if phases.isPartial() && cmd.ClusterConfigFile == "" {
+		return errors.New("--phases requires --config-file, the later phases read the nodegroups, service accounts and add-ons from it")
+	}
+	if phases.isPartial() {
+		logger.Info("running the %s phase(s) of the cluster creation", phases)
+	}
+
+	if params.RawAPIOverrides, err = cmdutils.LoadRawAPIOverrides(params.RawAPIOverridesFile); err != nil {
+		return err
+	}
+
 	printer := printers.NewJSONPrinter()
 
 	ctl, err := cmd.NewCtl()
@@ -108,8 +154,8 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 		cfg.Metadata.Version = api.DefaultVersion
 	}
 	if cfg.Metadata.Version != api.DefaultVersion {
-		if !isValidVersion(cfg.Metadata.Version) {
-			if isDeprecatedVersion(cfg.Metadata.Version) {
+		if !api.IsSupportedVersion(cfg.Metadata.Version) {
+			if api.IsDeprecatedVersion(cfg.Metadata.Version) {
 				return fmt.Errorf("invalid version, %s is no longer supported, supported values: %s\nsee also: https://docs.aws.amazon.com/eks/latest/userguide/kubernetes-versions.html", cfg.Metadata.Version, strings.Join(api.SupportedVersions(), ", "))
 			}
 			return fmt.Errorf("invalid version, supported values: %s", strings.Join(api.SupportedVersions(), ", "))
@@ -131,6 +177,19 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 		params.KubeconfigPath = kubeconfig.AutoPath(meta.Name)
 	}
 
+	if cmd.ClusterConfigFile != "" {
+		clusterStack, err := ctl.NewStackManager(cfg).GetClusterStackIfExists()
+		if err != nil {
+			return err
+		}
+		if clusterStack != nil {
+			return createMissingClusterResources(cmd, ctl, ngFilter, params, clusterStack, phases)
+		}
+		if !phases.has(phaseControlPlane) {
+			return fmt.Errorf("cluster stack for %q doesn't exist yet, run the %s and %s phases first", meta.Name, phaseVPC, phaseControlPlane)
+		}
+	}
+
 	if checkSubnetsGivenAsFlags(params) {
 		// undo defaulting and reset it, as it's not set via config file;
 		// default value here causes errors as vpc.ImportVPC doesn't
@@ -149,6 +208,9 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 	if err := eks.ValidateFeatureCompatibility(cfg, kubeNodeGroups); err != nil {
 		return err
 	}
+	if err := eks.ValidateVolumeEncryptionKeys(ctl.Provider, cfg.NodeGroups, cfg.ManagedNodeGroups); err != nil {
+		return err
+	}
 	if params.InstallWindowsVPCController {
 		if !eks.SupportsWindowsWorkloads(kubeNodeGroups) {
 			return errors.New("running Windows workloads requires having both Windows and Linux (AmazonLinux2) node groups")
@@ -156,6 +218,7 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 	} else {
 		eks.LogWindowsCompatibility(kubeNodeGroups, cfg.Metadata)
 	}
+	eks.LogNodeTerminationHandlerCompatibility(cfg)
 
 	subnetsGiven := cfg.HasAnySubnets() // this will be false when neither flags nor config has any subnets
 
@@ -252,6 +315,26 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 		return err
 	}
 
+	if err := vpc.ValidateControlPlaneSubnets(ctl.Provider, cfg); err != nil {
+		return err
+	}
+
+	if err := vpc.ValidateSecurityGroups(ctl.Provider, cfg, cfg.NodeGroups); err != nil {
+		return err
+	}
+
+	nodeGroupService := eks.NewNodeGroupService(cfg, ctl.Provider.EC2())
+	// the instance types are needed to resolve AMIs, e.g. for GPU instances
+	if err := nodeGroupService.ExpandInstanceSelectors(cfg.NodeGroups); err != nil {
+		return err
+	}
+	if err := nodeGroupService.ExpandManagedInstanceSelectors(cfg.ManagedNodeGroups); err != nil {
+		return err
+	}
+	if err := nodeGroupService.ValidatePodSecurityGroupsSupport(cfg.NodeGroups, cfg.ManagedNodeGroups); err != nil {
+		return err
+	}
+
 	for _, ng := range cfg.NodeGroups {
 		// resolve AMI
 		if err := eks.EnsureAMI(ctl.Provider, meta.Version, ng); err != nil {
@@ -272,7 +355,6 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 		}
 	}
 
-	nodeGroupService := eks.NewNodeGroupService(cfg, ctl.Provider.EC2())
 	if err := nodeGroupService.NormalizeManaged(cfg.ManagedNodeGroups); err != nil {
 		return err
 	}
@@ -288,8 +370,28 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 		return err
 	}
 
+	if cfg.HasBudget() {
+		pricer, err := ctl.NewPricer()
+		if err != nil {
+			return err
+		}
+		if err := ctl.CheckClusterBudget(pricer, cfg, params.OverrideBudget); err != nil {
+			return err
+		}
+	}
+
+	hookRunner := hooks.NewRunner(cfg, func() (*kubernetes.RawClient, error) {
+		return ctl.NewRawClient(cfg)
+	})
+	if err := hookRunner.Run(api.HookPreCreate, ""); err != nil {
+		return err
+	}
+
+	notifier := notifications.NewNotifier(cfg, ctl.Provider)
+
 	{ // core action
 		stackManager := ctl.NewStackManager(cfg)
+		stackManager.SetRawAPIOverrides(params.RawAPIOverrides)
 		if cmd.ClusterConfigFile == "" {
 			logMsg := func(resource string) {
 				logger.Info("will create 2 separate CloudFormation stacks for cluster itself and the initial %s", resource)
@@ -305,8 +407,12 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 			}
 			logFiltered()
 
-			logMsg("nodegroup", len(cfg.NodeGroups))
-			logMsg("managed nodegroup", len(cfg.ManagedNodeGroups))
+			if phases.has(phaseNodeGroups) {
+				logMsg("nodegroup", len(cfg.NodeGroups))
+				logMsg("managed nodegroup", len(cfg.ManagedNodeGroups))
+			} else {
+				logMsg("nodegroup", 0)
+			}
 		}
 
 		logger.Info("if you encounter any issues, check CloudFormation console or try 'eksctl utils describe-stacks --region=%s --cluster=%s'", meta.Region, meta.Name)
@@ -314,16 +420,33 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 		if err != nil {
 			return err
 		}
-		tasks := stackManager.NewTasksToCreateClusterWithNodeGroups(cfg.NodeGroups, cfg.ManagedNodeGroups, supportsManagedNodes)
-		ctl.AppendExtraClusterConfigTasks(cfg, params.InstallWindowsVPCController, tasks)
+		nodeGroups, managedNodeGroups := cfg.NodeGroups, cfg.ManagedNodeGroups
+		if !phases.has(phaseNodeGroups) {
+			nodeGroups, managedNodeGroups = nil, nil
+		}
+		tasks := stackManager.NewTasksToCreateClusterWithNodeGroups(nodeGroups, managedNodeGroups, supportsManagedNodes)
+		if phases.has(phaseOIDC) {
+			ctl.AppendExtraClusterConfigTasks(cfg, params.InstallWindowsVPCController, tasks)
+		} else {
+			// the IAM OIDC provider and the iamserviceaccounts are left to the oidc phase
+			withOIDC := cfg.IAM.WithOIDC
+			cfg.IAM.WithOIDC = api.Disabled()
+			ctl.AppendExtraClusterConfigTasks(cfg, params.InstallWindowsVPCController, tasks)
+			cfg.IAM.WithOIDC = withOIDC
+		}
 
 		logger.Info(tasks.Describe())
 		if errs := tasks.DoAllSync(); len(errs) > 0 {
 			logger.Info("%d error(s) occurred and cluster hasn't been created properly, you may wish to check CloudFormation console", len(errs))
 			logger.Info("to cleanup resources, run 'eksctl delete cluster --region=%s --name=%s'", meta.Region, meta.Name)
+			var messages []string
 			for _, err := range errs {
 				logger.Critical("%s\n", err.Error())
+				messages = append(messages, err.Error())
 			}
+			notifier.Notify(notifications.ClusterCreateFailed, "failed to create cluster", map[string]interface{}{
+				"errors": messages,
+			})
 			return fmt.Errorf("failed to create cluster %q", meta.Name)
 		}
 	}
@@ -359,7 +482,7 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 			return err
 		}
 
-		for _, ng := range cfg.NodeGroups {
+		for _, ng := range nodeGroupsOf(cfg, phases) {
 			// authorise nodes to join
 			if err = authconfigmap.AddNodeGroup(clientSet, ng); err != nil {
 				return err
@@ -378,19 +501,44 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 
 		}
 
-		for _, ng := range cfg.ManagedNodeGroups {
+		for _, ng := range managedNodeGroupsOf(cfg, phases) {
 			if err := ctl.WaitForNodes(clientSet, ng); err != nil {
 				return err
 			}
 		}
 
-		if cfg.IsFargateEnabled() {
+		if cfg.IsFargateEnabled() && phases.has(phaseNodeGroups) {
 			if err := doCreateFargateProfiles(cmd, ctl); err != nil {
 				return err
 			}
 			if err := scheduleCoreDNSOnFargateIfRelevant(cmd, clientSet); err != nil {
 				return err
 			}
+			if err := configureFargateLoggingIfRelevant(cmd, clientSet); err != nil {
+				return err
+			}
+		}
+
+		// add-ons are created once nodes have joined, EKS reports them as degraded until their
+		// pods are running
+		if phases.has(phaseAddons) {
+			if cfg.HasAddons() {
+				if err := ctl.CreateAddons(cfg); err != nil {
+					return err
+				}
+			}
+			// the add-ons phase is the last one, the cluster is only complete once it ran
+			if err := finishCreation(ctl, cfg, clientSet, params, readinessGates, hookRunner); err != nil {
+				return err
+			}
+		}
+
+		if params.ReportAMIVulnerabilities {
+			ctl.LogAMIVulnerabilities(meta.Name, cmdutils.ToKubeNodeGroups(cfg))
+		}
+
+		if cfg.HasCostAllocationTags() {
+			ctl.ActivateCostAllocationTags(api.EKSClusterNameTag)
 		}
 
 		// check kubectl version, and offer install instructions if missing or old
@@ -406,7 +554,14 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 		}
 	}
 
+	if remaining := phases.remaining(); len(remaining) > 0 {
+		logRemainingPhases(cmd, remaining)
+		return nil
+	}
 	logger.Success("%s is ready", meta.LogString())
+	notifier.Notify(notifications.ClusterCreated, "cluster is ready", map[string]interface{}{
+		"version": cfg.Metadata.Version,
+	})
 
 	if err := printer.LogObj(logger.Debug, "cfg.json = \\\n%s\n", cfg); err != nil {
 		return err
@@ -414,3 +569,57 @@ func doCreateCluster(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *cmdutils.Crea
 
 	return nil
 }
+
+// applyLike copies the configuration of the cluster given with --like into the config of the new
+// cluster; the version, the VPC CIDR and the NAT mode are only copied when not set with flags
+func applyLike(cmd *cmdutils.Cmd, params *cmdutils.CreateClusterCmdParams) error {
+	likeProviderConfig := *cmd.ProviderConfig
+	if params.LikeRegion != "" {
+		likeProviderConfig.Region = params.LikeRegion
+	}
+	likeCtl := eks.New(&likeProviderConfig, nil)
+	if err := likeCtl.CheckAuth(); err != nil {
+		return err
+	}
+
+	logger.Info("using the configuration of cluster %q in %q", params.Like, likeCtl.Provider.Region())
+	like, err := likeCtl.ClusterConfigLike(params.Like)
+	if err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	flagChanged := func(name string) bool {
+		flag := cmd.CobraCommand.Flag(name)
+		return flag != nil && flag.Changed
+	}
+
+	if !flagChanged("version") {
+		cfg.Metadata.Version = like.Metadata.Version
+	}
+	if cfg.Metadata.Tags == nil {
+		cfg.Metadata.Tags = map[string]string{}
+	}
+	for key, value := range like.Metadata.Tags {
+		if _, ok := cfg.Metadata.Tags[key]; !ok {
+			cfg.Metadata.Tags[key] = value
+		}
+	}
+
+	if !flagChanged("vpc-cidr") {
+		cfg.VPC.CIDR = like.VPC.CIDR
+	}
+	if !flagChanged("vpc-nat-mode") {
+		cfg.VPC.NAT = like.VPC.NAT
+	}
+	cfg.VPC.ClusterEndpoints = like.VPC.ClusterEndpoints
+	cfg.VPC.PublicAccessCIDRs = like.VPC.PublicAccessCIDRs
+	cfg.CloudWatch = like.CloudWatch
+	cfg.IAM.WithOIDC = like.IAM.WithOIDC
+	cfg.FargateProfiles = like.FargateProfiles
+	if !params.WithoutNodeGroup {
+		cfg.NodeGroups = like.NodeGroups
+		cfg.ManagedNodeGroups = like.ManagedNodeGroups
+	}
+	return nil
+}
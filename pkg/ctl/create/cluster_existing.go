@@ -0,0 +1,203 @@
+package create
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/fargate"
+	"github.com/weaveworks/eksctl/pkg/hooks"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/utils/kubeconfig"
+)
+
+// createMissingClusterResources makes re-running 'eksctl create cluster -f' safe after a partial
+// failure: the existing cluster stack is checked against the config file, and only the IAM OIDC
+// provider, iamserviceaccounts, nodegroups, Fargate profiles and add-ons that don't exist yet are
+// created; when phases are selected, it runs the later phases of a creation started with --phases
+func createMissingClusterResources(cmd *cmdutils.Cmd, ctl *eks.ClusterProvider, ngFilter *cmdutils.NodeGroupFilter, params *cmdutils.CreateClusterCmdParams, clusterStack *manager.Stack, phases createPhases) error {
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	if err := ctl.ValidateExistingCluster(cfg, clusterStack); err != nil {
+		return err
+	}
+	logger.Info("cluster stack %q already exists, skipping creation of the control plane", aws.StringValue(clusterStack.StackName))
+	if !phases.isPartial() {
+		logger.Info("skipping the cluster configuration updates and the %s and %s hooks, use 'eksctl utils' commands to apply changes to the existing cluster", api.HookPreCreate, api.HookPostCreate)
+	}
+
+	if phases.has(phaseOIDC) && api.IsEnabled(cfg.IAM.WithOIDC) {
+		if err := createMissingIAMServiceAccounts(cmd, ctl); err != nil {
+			return err
+		}
+	}
+
+	if phases.has(phaseNodeGroups) {
+		if err := createNodeGroups(cmd, ctl, ngFilter, createNodeGroupParams{
+			updateAuthConfigMap:      true,
+			onlyMissing:              true,
+			reportAMIVulnerabilities: params.ReportAMIVulnerabilities,
+			rawAPIOverrides:          params.RawAPIOverrides,
+		}); err != nil {
+			return err
+		}
+
+		if cfg.IsFargateEnabled() {
+			if err := createMissingFargateProfiles(cmd, ctl); err != nil {
+				return err
+			}
+			clientSet, err := ctl.NewStdClientSet(cfg)
+			if err != nil {
+				return err
+			}
+			if err := scheduleCoreDNSOnFargateIfRelevant(cmd, clientSet); err != nil {
+				return err
+			}
+			if err := configureFargateLoggingIfRelevant(cmd, clientSet); err != nil {
+				return err
+			}
+		}
+	}
+
+	if phases.has(phaseAddons) {
+		if err := createMissingAddons(ctl, cfg); err != nil {
+			return err
+		}
+		if phases.isPartial() {
+			readinessGates, err := params.ReadinessGates()
+			if err != nil {
+				return err
+			}
+			clientSet, err := ctl.NewStdClientSet(cfg)
+			if err != nil {
+				return err
+			}
+			hookRunner := hooks.NewRunner(cfg, func() (*kubernetes.RawClient, error) {
+				return ctl.NewRawClient(cfg)
+			})
+			if err := finishCreation(ctl, cfg, clientSet, params, readinessGates, hookRunner); err != nil {
+				return err
+			}
+		}
+	}
+
+	if params.WriteKubeconfig {
+		kubectlConfig := kubeconfig.NewForKubectl(cfg, ctl.GetUsername(), params.AuthenticatorRoleARN, ctl.Provider.Profile())
+		path, err := kubeconfig.Write(params.KubeconfigPath, *kubectlConfig, params.SetContext)
+		if err != nil {
+			logger.Warning("unable to write kubeconfig %s, please retry with 'eksctl utils write-kubeconfig -n %s': %v", params.KubeconfigPath, meta.Name, err)
+		} else {
+			logger.Success("saved kubeconfig as %q", path)
+		}
+	}
+
+	if remaining := phases.remaining(); len(remaining) > 0 {
+		logRemainingPhases(cmd, remaining)
+		return nil
+	}
+	logger.Success("%s is ready", meta.LogString())
+	return nil
+}
+
+// createMissingIAMServiceAccounts associates the IAM OIDC provider if it isn't yet, and creates
+// the iamserviceaccounts that don't exist yet
+func createMissingIAMServiceAccounts(cmd *cmdutils.Cmd, ctl *eks.ClusterProvider) error {
+	cfg := cmd.ClusterConfig
+
+	oidc, err := ctl.NewOpenIDConnectManager(cfg)
+	if err != nil {
+		return err
+	}
+	if err := oidc.EnsureProvider(); err != nil {
+		return err
+	}
+
+	clientSet, err := ctl.NewStdClientSet(cfg)
+	if err != nil {
+		return err
+	}
+	stackManager := ctl.NewStackManager(cfg)
+	saFilter := cmdutils.NewIAMServiceAccountFilter()
+	if err := saFilter.SetExcludeExistingFilter(stackManager, clientSet, cfg.IAM.ServiceAccounts, false); err != nil {
+		return err
+	}
+	serviceAccounts := saFilter.FilterMatching(cfg.IAM.ServiceAccounts)
+	saFilter.LogInfo(cfg.IAM.ServiceAccounts)
+
+	tasks := stackManager.NewTasksToCreateIAMServiceAccounts(serviceAccounts, oidc, kubernetes.NewCachedClientSet(clientSet))
+	logger.Info(tasks.Describe())
+	if errs := tasks.DoAllSync(); len(errs) > 0 {
+		for _, err := range errs {
+			logger.Critical("%s\n", err.Error())
+		}
+		return fmt.Errorf("failed to create iamserviceaccount(s)")
+	}
+	return nil
+}
+
+// createMissingAddons creates the add-ons of the config that aren't installed yet
+func createMissingAddons(ctl *eks.ClusterProvider, cfg *api.ClusterConfig) error {
+	if !cfg.HasAddons() {
+		return nil
+	}
+	installed, err := ctl.ListInstalledAddons(cfg)
+	if err != nil {
+		return err
+	}
+	existing := sets.NewString()
+	for _, addon := range installed {
+		existing.Insert(aws.StringValue(addon.AddonName))
+	}
+
+	var missing []*api.Addon
+	for _, addon := range cfg.Addons {
+		if existing.Has(addon.Name) {
+			logger.Info("EKS add-on %q already exists, skipping it", addon.Name)
+			continue
+		}
+		missing = append(missing, addon)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	allAddons := cfg.Addons
+	cfg.Addons = missing
+	defer func() { cfg.Addons = allAddons }()
+	return ctl.CreateAddons(cfg)
+}
+
+func createMissingFargateProfiles(cmd *cmdutils.Cmd, ctl *eks.ClusterProvider) error {
+	cfg := cmd.ClusterConfig
+
+	if err := ctl.NewStackManager(cfg).RefreshFargatePodExecutionRoleARN(); err != nil {
+		return err
+	}
+
+	existingNames, err := fargate.NewClient(cfg.Metadata.Name, ctl.Provider.EKS()).ListProfiles()
+	if err != nil {
+		return err
+	}
+	existing := sets.NewString(aws.StringValueSlice(existingNames)...)
+
+	var missing []*api.FargateProfile
+	for _, profile := range cfg.FargateProfiles {
+		if existing.Has(profile.Name) {
+			logger.Info("Fargate profile %q already exists, skipping it", profile.Name)
+			continue
+		}
+		missing = append(missing, profile)
+	}
+
+	allProfiles := cfg.FargateProfiles
+	cfg.FargateProfiles = missing
+	defer func() { cfg.FargateProfiles = allProfiles }()
+	return doCreateFargateProfiles(cmd, ctl)
+}
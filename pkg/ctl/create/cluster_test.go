@@ -32,6 +32,10 @@ var _ = Describe("create cluster", func() {
 			Entry("without cluster name", ""),
 			Entry("with cluster name as flag", "--name", "clusterName"),
 			Entry("with cluster name as argument", "clusterName"),
+			// timeout flags
+			Entry("with control-plane-timeout flag", "--control-plane-timeout", "40m"),
+			Entry("with nodegroups-timeout flag", "--nodegroups-timeout", "20m"),
+			Entry("with addons-timeout flag", "--addons-timeout", "10m"),
 			// vpc networking flags
 			Entry("with vpc-cidr flag", "--vpc-cidr", "10.0.0.0/20"),
 			Entry("with vpc-private-subnets flag", "--vpc-private-subnets", "10.0.0.0/24"),
@@ -15,6 +15,7 @@ func Command(flagGrouping *cmdutils.FlagGrouping) *cobra.Command {
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, createIAMServiceAccountCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, createIAMIdentityMappingCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, createFargateProfile)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, createAddonCmd)
 
 	return verbCmd
 }
@@ -2,9 +2,7 @@ package create
 
 import (
 	"fmt"
-	"time"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -13,6 +11,7 @@ import (
 	"github.com/weaveworks/eksctl/pkg/eks"
 	"github.com/weaveworks/eksctl/pkg/fargate"
 	"github.com/weaveworks/eksctl/pkg/fargate/coredns"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/utils/retry"
 	"github.com/weaveworks/eksctl/pkg/utils/strings"
 	"k8s.io/client-go/kubernetes"
@@ -48,7 +47,7 @@ func configureCreateFargateProfileCmd(cmd *cmdutils.Cmd) *fargate.CreateOptions
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
 		cmdutils.AddClusterFlag(fs, cmd.ClusterConfig.Metadata)
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddConfigFileFlag(fs, cmd)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
 	})
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
@@ -92,7 +91,10 @@ func doCreateFargateProfile(cmd *cmdutils.Cmd) error {
 	if err != nil {
 		return err
 	}
-	return scheduleCoreDNSOnFargateIfRelevant(cmd, clientSet)
+	if err := scheduleCoreDNSOnFargateIfRelevant(cmd, clientSet); err != nil {
+		return err
+	}
+	return configureFargateLoggingIfRelevant(cmd, clientSet)
 }
 
 func clientSet(cfg *api.ClusterConfig, ctl *eks.ClusterProvider) (kubernetes.Interface, error) {
@@ -117,6 +119,9 @@ func doCreateFargateProfiles(cmd *cmdutils.Cmd, ctl *eks.ClusterProvider) error
 	awsClient := fargate.NewClientWithWaitTimeout(clusterName, ctl.Provider.EKS(), cmd.ProviderConfig.WaitTimeout)
 	for _, profile := range cmd.ClusterConfig.FargateProfiles {
 		logger.Info("creating Fargate profile %q on EKS cluster %q", profile.Name, clusterName)
+		if profile.Logging != nil && profile.PodExecutionRoleARN != "" {
+			logger.Warning("Fargate profile %q uses its own pod execution role, which needs permissions to write logs to %s", profile.Name, profile.Logging.Destination)
+		}
 
 		// Default the pod execution role ARN to be the same as the cluster
 		// role defined in CloudFormation:
@@ -146,14 +151,19 @@ func scheduleCoreDNSOnFargateIfRelevant(cmd *cmdutils.Cmd, clientSet kubernetes.
 			if err := coredns.ScheduleOnFargate(clientSet); err != nil {
 				return err
 			}
-			retryPolicy := &retry.TimingOutExponentialBackoff{
-				Timeout:  cmd.ProviderConfig.WaitTimeout,
-				TimeUnit: time.Second,
-			}
-			if err := coredns.WaitForScheduleOnFargate(clientSet, retryPolicy); err != nil {
+			if err := coredns.WaitForScheduleOnFargate(clientSet, retry.DefaultPoller, cmd.ProviderConfig.WaitTimeout); err != nil {
 				return err
 			}
 		}
 	}
 	return nil
 }
+
+func configureFargateLoggingIfRelevant(cmd *cmdutils.Cmd, clientSet kubernetes.Interface) error {
+	logging := cmd.ClusterConfig.FargateLogging()
+	if logging == nil {
+		return nil
+	}
+	meta := cmd.ClusterConfig.Metadata
+	return fargate.ApplyLoggingConfig(clientSet, logging, meta.Name, meta.Region)
+}
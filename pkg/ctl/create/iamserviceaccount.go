@@ -4,13 +4,14 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/kris-nova/logger"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
 	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/printers"
 )
 
@@ -18,17 +19,22 @@ func createIAMServiceAccountCmd(cmd *cmdutils.Cmd) {
 	cfg := api.NewClusterConfig()
 	cmd.ClusterConfig = cfg
 
-	serviceAccount := &api.ClusterIAMServiceAccount{}
+	serviceAccount := &api.ClusterIAMServiceAccount{
+		RoleOnly: api.Disabled(),
+	}
 
 	cfg.IAM.WithOIDC = api.Enabled()
 	cfg.IAM.ServiceAccounts = append(cfg.IAM.ServiceAccounts, serviceAccount)
 
-	var overrideExistingServiceAccounts bool
+	var (
+		overrideExistingServiceAccounts bool
+		continueOnError                 bool
+	)
 
 	cmd.SetDescription("iamserviceaccount", "Create an iamserviceaccount - AWS IAM role bound to a Kubernetes service account", "")
 
 	cmd.CobraCommand.RunE = func(_ *cobra.Command, _ []string) error {
-		return doCreateIAMServiceAccount(cmd, overrideExistingServiceAccounts)
+		return doCreateIAMServiceAccount(cmd, overrideExistingServiceAccounts, continueOnError)
 	}
 
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
@@ -37,20 +43,24 @@ func createIAMServiceAccountCmd(cmd *cmdutils.Cmd) {
 		fs.StringVar(&serviceAccount.Name, "name", "", "name of the iamserviceaccount to create")
 		fs.StringVar(&serviceAccount.Namespace, "namespace", "default", "namespace where to create the iamserviceaccount")
 		fs.StringSliceVar(&serviceAccount.AttachPolicyARNs, "attach-policy-arn", []string{}, "ARN of the policy where to create the iamserviceaccount")
+		fs.StringVar(&serviceAccount.RoleName, "role-name", "", "name of the IAM role to create, may contain {ClusterName}, {Namespace} and {Name}")
+		fs.StringVar(&serviceAccount.AttachRoleARN, "attach-role-arn", "", "ARN of an existing IAM role to annotate the serviceaccount with instead of creating one")
+		fs.BoolVar(serviceAccount.RoleOnly, "role-only", false, "only create the IAM role, without creating or annotating the serviceaccount")
 
-		fs.BoolVar(&overrideExistingServiceAccounts, "override-existing-serviceaccounts", false, "create IAM roles for existing serviceaccounts and update the serviceaccount")
+		fs.BoolVar(&overrideExistingServiceAccounts, "override-existing-serviceaccounts", false, "create IAM roles for existing serviceaccounts and update the serviceaccount, and adopt existing IAM role stacks, updating them if the role changed")
+		fs.BoolVar(&continueOnError, "continue-on-error", false, "keep creating the other iamserviceaccounts when one fails, and summarize the failures at the end")
 
 		cmdutils.AddIAMServiceAccountFilterFlags(fs, &cmd.Include, &cmd.Exclude)
 		cmdutils.AddApproveFlag(fs, cmd)
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddConfigFileFlag(fs, cmd)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
 	})
 
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, true)
 }
 
-func doCreateIAMServiceAccount(cmd *cmdutils.Cmd, overrideExistingServiceAccounts bool) error {
+func doCreateIAMServiceAccount(cmd *cmdutils.Cmd, overrideExistingServiceAccounts, continueOnError bool) error {
 	saFilter := cmdutils.NewIAMServiceAccountFilter()
 
 	if err := cmdutils.NewCreateIAMServiceAccountLoader(cmd, saFilter).Load(); err != nil {
@@ -104,14 +114,19 @@ func doCreateIAMServiceAccount(cmd *cmdutils.Cmd, overrideExistingServiceAccount
 
 	filteredServiceAccounts := saFilter.FilterMatching(cfg.IAM.ServiceAccounts)
 	saFilter.LogInfo(cfg.IAM.ServiceAccounts)
+	var tasks *manager.TaskTree
 	if !overrideExistingServiceAccounts {
 		logger.Warning("serviceaccounts that exists in Kubernetes will be excluded, use --override-existing-serviceaccounts to override")
+		tasks = stackManager.NewTasksToCreateIAMServiceAccounts(filteredServiceAccounts, oidc, kubernetes.NewCachedClientSet(clientSet))
 	} else {
-		logger.Warning("metadata of serviceaccounts that exist in Kubernetes will be updated, as --override-existing-serviceaccounts was set")
+		logger.Warning("metadata of serviceaccounts that exist in Kubernetes will be updated, and existing IAM role stacks adopted, as --override-existing-serviceaccounts was set")
+		tasks, err = stackManager.NewTasksToCreateOrAdoptIAMServiceAccounts(filteredServiceAccounts, oidc, kubernetes.NewCachedClientSet(clientSet))
+		if err != nil {
+			return err
+		}
 	}
-
-	tasks := stackManager.NewTasksToCreateIAMServiceAccounts(filteredServiceAccounts, oidc, kubernetes.NewCachedClientSet(clientSet))
 	tasks.PlanMode = cmd.Plan
+	tasks.StopOnError = !continueOnError
 
 	if err := printer.LogObj(logger.Debug, "cfg.json = \\\n%s\n", cfg); err != nil {
 		return err
@@ -119,10 +134,13 @@ func doCreateIAMServiceAccount(cmd *cmdutils.Cmd, overrideExistingServiceAccount
 
 	logger.Info(tasks.Describe())
 	if errs := tasks.DoAllSync(); len(errs) > 0 {
-		logger.Info("%d error(s) occurred and IAM Role stacks haven't been created properly, you may wish to check CloudFormation console", len(errs))
+		logger.Info("%d of %d iamserviceaccount(s) failed and their IAM Role stacks haven't been created properly, you may wish to check CloudFormation console", len(errs), len(filteredServiceAccounts))
 		for _, err := range errs {
 			logger.Critical("%s\n", err.Error())
 		}
+		if !continueOnError {
+			logger.Info("the iamserviceaccounts that weren't started can be created by running the command again, use --continue-on-error to create all of them despite failures")
+		}
 		return fmt.Errorf("failed to create iamserviceaccount(s)")
 	}
 
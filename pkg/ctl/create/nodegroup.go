@@ -3,10 +3,10 @@ package create
 import (
 	"fmt"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"github.com/weaveworks/eksctl/pkg/cfn/builder"
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 	"github.com/weaveworks/eksctl/pkg/eks"
 	"github.com/weaveworks/eksctl/pkg/ssh"
@@ -16,13 +16,21 @@ import (
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/authconfigmap"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/hooks"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/printers"
 	"github.com/weaveworks/eksctl/pkg/utils"
 )
 
 type createNodeGroupParams struct {
-	updateAuthConfigMap bool
-	managed             bool
+	updateAuthConfigMap      bool
+	managed                  bool
+	onlyMissing              bool
+	reportAMIVulnerabilities bool
+	overrideBudget           bool
+	rawAPIOverridesFile      string
+	rawAPIOverrides          *builder.RawAPIOverrides
 }
 
 func createNodeGroupCmd(cmd *cmdutils.Cmd) {
@@ -54,9 +62,12 @@ func createNodeGroupCmdWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils
 		fs.StringToStringVarP(&cfg.Metadata.Tags, "tags", "", map[string]string{}, `A list of KV pairs used to tag the AWS resources (e.g. "Owner=John Doe,Team=Some Team")`)
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
 		cmdutils.AddVersionFlag(fs, cfg.Metadata, `for nodegroups "auto" and "latest" can be used to automatically inherit version from the control plane or force latest`)
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddConfigFileFlag(fs, cmd)
 		cmdutils.AddNodeGroupFilterFlags(fs, &cmd.Include, &cmd.Exclude)
+		fs.BoolVar(&params.onlyMissing, "only-missing", false, "Only create nodegroups from the given config file that don't exist yet, even if they match --include")
 		cmdutils.AddUpdateAuthConfigMap(fs, &params.updateAuthConfigMap, "Add nodegroup IAM role to aws-auth configmap")
+		cmdutils.AddReportAMIVulnerabilitiesFlag(fs, &params.reportAMIVulnerabilities)
+		cmdutils.AddOverrideBudgetFlag(fs, &params.overrideBudget)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
 	})
 
@@ -64,6 +75,7 @@ func createNodeGroupCmdWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils
 		fs.StringVarP(&ng.Name, "name", "n", "", fmt.Sprintf("name of the new nodegroup (generated if unspecified, e.g. %q)", exampleNodeGroupName))
 		cmdutils.AddCommonCreateNodeGroupFlags(fs, cmd, ng)
 		fs.BoolVarP(&params.managed, "managed", "", false, "Create EKS-managed nodegroup")
+		cmdutils.AddRawAPIOverridesFlag(fs, &params.rawAPIOverridesFile)
 	})
 
 	cmd.FlagSetGroup.InFlagSet("IAM addons", func(fs *pflag.FlagSet) {
@@ -80,16 +92,19 @@ func doCreateNodeGroups(cmd *cmdutils.Cmd, ng *api.NodeGroup, params createNodeG
 		return err
 	}
 
-	cfg := cmd.ClusterConfig
-	meta := cmd.ClusterConfig.Metadata
+	rawAPIOverrides, err := cmdutils.LoadRawAPIOverrides(params.rawAPIOverridesFile)
+	if err != nil {
+		return err
+	}
+	params.rawAPIOverrides = rawAPIOverrides
 
-	printer := printers.NewJSONPrinter()
+	cfg := cmd.ClusterConfig
 
 	ctl, err := cmd.NewCtl()
 	if err != nil {
 		return err
 	}
-	cmdutils.LogRegionAndVersionInfo(meta)
+	cmdutils.LogRegionAndVersionInfo(cfg.Metadata)
 
 	if err := ctl.CheckAuth(); err != nil {
 		return err
@@ -99,6 +114,21 @@ func doCreateNodeGroups(cmd *cmdutils.Cmd, ng *api.NodeGroup, params createNodeG
 		return err
 	}
 
+	return createNodeGroups(cmd, ctl, ngFilter, params)
+}
+
+// NodeGroups creates the nodegroups of the loaded config of cmd that match ngFilter, and waits
+// for their nodes to join the cluster, for commands that create nodegroups as one of their steps
+func NodeGroups(cmd *cmdutils.Cmd, ctl *eks.ClusterProvider, ngFilter *cmdutils.NodeGroupFilter) error {
+	return createNodeGroups(cmd, ctl, ngFilter, createNodeGroupParams{updateAuthConfigMap: true})
+}
+
+func createNodeGroups(cmd *cmdutils.Cmd, ctl *eks.ClusterProvider, ngFilter *cmdutils.NodeGroupFilter, params createNodeGroupParams) error {
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	printer := printers.NewJSONPrinter()
+
 	if err := checkVersion(cmd, ctl, cfg.Metadata); err != nil {
 		return err
 	}
@@ -107,9 +137,18 @@ func doCreateNodeGroups(cmd *cmdutils.Cmd, ng *api.NodeGroup, params createNodeG
 		return errors.Wrapf(err, "getting VPC configuration for cluster %q", cfg.Metadata.Name)
 	}
 
+	if err := ctl.LoadKubernetesNetworkConfig(cfg); err != nil {
+		return err
+	}
+
 	stackManager := ctl.NewStackManager(cfg)
+	stackManager.SetRawAPIOverrides(params.rawAPIOverrides)
 
-	if err := ngFilter.SetExcludeExistingFilter(stackManager); err != nil {
+	if params.onlyMissing {
+		if err := ngFilter.SetOnlyMissingFilter(stackManager); err != nil {
+			return err
+		}
+	} else if err := ngFilter.SetExcludeExistingFilter(stackManager); err != nil {
 		return err
 	}
 
@@ -129,6 +168,25 @@ func doCreateNodeGroups(cmd *cmdutils.Cmd, ng *api.NodeGroup, params createNodeG
 		return err
 	}
 
+	if err := eks.ValidateVolumeEncryptionKeys(ctl.Provider, cfg.NodeGroups, cfg.ManagedNodeGroups); err != nil {
+		return err
+	}
+
+	nodeGroupService := eks.NewNodeGroupService(cfg, ctl.Provider.EC2())
+	// the instance types are needed to resolve AMIs, e.g. for GPU instances
+	if err := nodeGroupService.ExpandInstanceSelectors(cfg.NodeGroups); err != nil {
+		return err
+	}
+	if err := nodeGroupService.ExpandManagedInstanceSelectors(cfg.ManagedNodeGroups); err != nil {
+		return err
+	}
+	if err := nodeGroupService.ValidatePodSecurityGroupsSupport(cfg.NodeGroups, cfg.ManagedNodeGroups); err != nil {
+		return err
+	}
+	if err := vpc.ValidateSecurityGroups(ctl.Provider, cfg, cfg.NodeGroups); err != nil {
+		return err
+	}
+
 	for _, ng := range cfg.NodeGroups {
 		// resolve AMI
 		if err := eks.EnsureAMI(ctl.Provider, meta.Version, ng); err != nil {
@@ -149,8 +207,7 @@ func doCreateNodeGroups(cmd *cmdutils.Cmd, ng *api.NodeGroup, params createNodeG
 		}
 	}
 
-	managedService := eks.NewNodeGroupService(cfg, ctl.Provider.EC2())
-	if err := managedService.NormalizeManaged(cfg.ManagedNodeGroups); err != nil {
+	if err := nodeGroupService.NormalizeManaged(cfg.ManagedNodeGroups); err != nil {
 		return err
 	}
 
@@ -158,6 +215,16 @@ func doCreateNodeGroups(cmd *cmdutils.Cmd, ng *api.NodeGroup, params createNodeG
 		return err
 	}
 
+	if cfg.HasBudget() {
+		pricer, err := ctl.NewPricer()
+		if err != nil {
+			return err
+		}
+		if err := ctl.CheckNodeGroupsBudget(pricer, cfg, eks.CostNodeGroups(cfg), params.overrideBudget); err != nil {
+			return err
+		}
+	}
+
 	// TODO
 	if err := ctl.ValidateClusterForCompatibility(cfg, stackManager); err != nil {
 		return errors.Wrap(err, "cluster compatibility check failed")
@@ -187,19 +254,7 @@ func doCreateNodeGroups(cmd *cmdutils.Cmd, ng *api.NodeGroup, params createNodeG
 			tasks.Append(stackManager.NewClusterCompatTask())
 		}
 
-		allNodeGroupTasks := &manager.TaskTree{
-			Parallel: true,
-		}
-		nodeGroupTasks := stackManager.NewTasksToCreateNodeGroups(cfg.NodeGroups, supportsManagedNodes)
-		if nodeGroupTasks.Len() > 0 {
-			allNodeGroupTasks.Append(nodeGroupTasks)
-		}
-		managedTasks := stackManager.NewManagedNodeGroupTask(cfg.ManagedNodeGroups)
-		if managedTasks.Len() > 0 {
-			allNodeGroupTasks.Append(managedTasks)
-		}
-
-		tasks.Append(allNodeGroupTasks)
+		tasks.Append(stackManager.NewTasksToCreateAllNodeGroups(cfg.NodeGroups, cfg.ManagedNodeGroups, supportsManagedNodes))
 		logger.Info(tasks.Describe())
 		errs := tasks.DoAllSync()
 		if len(errs) > 0 {
@@ -238,6 +293,10 @@ func doCreateNodeGroups(cmd *cmdutils.Cmd, ng *api.NodeGroup, params createNodeG
 				logger.Info("as you are using a GPU optimized instance type you will need to install NVIDIA Kubernetes device plugin.")
 				logger.Info("\t see the following page for instructions: https://github.com/NVIDIA/k8s-device-plugin")
 			}
+
+			if ng.GMSA != nil {
+				logger.Info("nodegroup %q uses gMSA, if the gMSA webhook isn't installed yet run 'eksctl utils install-gmsa-webhook --region=%s --cluster=%s'", ng.Name, cfg.Metadata.Region, cfg.Metadata.Name)
+			}
 		}
 		logger.Success("created %d nodegroup(s) in cluster %q", len(cfg.NodeGroups), cfg.Metadata.Name)
 
@@ -248,6 +307,19 @@ func doCreateNodeGroups(cmd *cmdutils.Cmd, ng *api.NodeGroup, params createNodeG
 		}
 
 		logger.Success("created %d managed nodegroup(s) in cluster %q", len(cfg.ManagedNodeGroups), cfg.Metadata.Name)
+
+		hookRunner := hooks.NewRunner(cfg, func() (*kubernetes.RawClient, error) {
+			return ctl.NewRawClient(cfg)
+		})
+		for _, ng := range cmdutils.ToKubeNodeGroups(cfg) {
+			if err := hookRunner.Run(api.HookPostNodeGroupCreate, ng.NameString()); err != nil {
+				return err
+			}
+		}
+
+		if params.reportAMIVulnerabilities {
+			ctl.LogAMIVulnerabilities(meta.Name, cmdutils.ToKubeNodeGroups(cfg))
+		}
 	}
 
 	if err := ctl.ValidateExistingNodeGroupsForCompatibility(cfg, stackManager); err != nil {
@@ -0,0 +1,126 @@
+package create
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/hooks"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/verify"
+)
+
+// Phases of 'eksctl create cluster', in the order they run
+const (
+	phaseVPC          = "vpc"
+	phaseControlPlane = "controlplane"
+	phaseOIDC         = "oidc"
+	phaseNodeGroups   = "nodegroups"
+	phaseAddons       = "addons"
+	phaseGitOps       = "gitops"
+)
+
+var orderedCreatePhases = []string{phaseVPC, phaseControlPlane, phaseOIDC, phaseNodeGroups, phaseAddons}
+
+// createPhases are the phases selected with --phases, none selected means all of them run
+type createPhases struct {
+	selected sets.String
+}
+
+func parseCreatePhases(phases []string) (createPhases, error) {
+	selected := sets.NewString()
+	for _, phase := range phases {
+		phase = strings.TrimSpace(phase)
+		switch phase {
+		case phaseVPC, phaseControlPlane, phaseOIDC, phaseNodeGroups, phaseAddons:
+			selected.Insert(phase)
+		case phaseGitOps:
+			return createPhases{}, errors.New("the gitops phase isn't supported by 'eksctl create cluster', set up GitOps with 'eksctl enable repo' once the cluster is created")
+		default:
+			return createPhases{}, fmt.Errorf("unknown phase %q, valid phases are: %s", phase, strings.Join(orderedCreatePhases, ", "))
+		}
+	}
+	// the VPC is part of the cluster stack, it can't be created on its own
+	if selected.Has(phaseVPC) != selected.Has(phaseControlPlane) {
+		return createPhases{}, fmt.Errorf("the %s and %s phases are created by the same CloudFormation stack and have to be run together", phaseVPC, phaseControlPlane)
+	}
+	return createPhases{selected: selected}, nil
+}
+
+// isPartial determines if only some of the phases were selected
+func (p createPhases) isPartial() bool {
+	return p.selected.Len() > 0 && p.selected.Len() < len(orderedCreatePhases)
+}
+
+func (p createPhases) has(phase string) bool {
+	return p.selected.Len() == 0 || p.selected.Has(phase)
+}
+
+// remaining returns the phases that come after the last selected one
+func (p createPhases) remaining() []string {
+	var remaining []string
+	for i := len(orderedCreatePhases) - 1; i >= 0; i-- {
+		if p.has(orderedCreatePhases[i]) {
+			break
+		}
+		remaining = append([]string{orderedCreatePhases[i]}, remaining...)
+	}
+	return remaining
+}
+
+func (p createPhases) String() string {
+	var phases []string
+	for _, phase := range orderedCreatePhases {
+		if p.has(phase) {
+			phases = append(phases, phase)
+		}
+	}
+	return strings.Join(phases, ",")
+}
+
+func nodeGroupsOf(cfg *api.ClusterConfig, phases createPhases) []*api.NodeGroup {
+	if !phases.has(phaseNodeGroups) {
+		return nil
+	}
+	return cfg.NodeGroups
+}
+
+func managedNodeGroupsOf(cfg *api.ClusterConfig, phases createPhases) []*api.ManagedNodeGroup {
+	if !phases.has(phaseNodeGroups) {
+		return nil
+	}
+	return cfg.ManagedNodeGroups
+}
+
+// finishCreation waits for the readiness gates, verifies the cluster and runs the post-create hooks
+func finishCreation(ctl *eks.ClusterProvider, cfg *api.ClusterConfig, clientSet kubernetes.Interface, params *cmdutils.CreateClusterCmdParams, readinessGates kubernetes.ReadinessGates, hookRunner *hooks.Runner) error {
+	if !readinessGates.IsEmpty() {
+		if err := kubernetes.WaitForReadinessGates(clientSet, readinessGates, params.ReadyTimeout); err != nil {
+			return err
+		}
+	}
+
+	if params.Verify {
+		report, err := ctl.VerifyCluster(cfg, verify.Options{Timeout: params.VerifyTimeout})
+		if err != nil {
+			return errors.Wrap(err, "verifying cluster")
+		}
+		if err := eks.LogVerification(report); err != nil {
+			return err
+		}
+	}
+
+	return hookRunner.Run(api.HookPostCreate, "")
+}
+
+func logRemainingPhases(cmd *cmdutils.Cmd, remaining []string) {
+	meta := cmd.ClusterConfig.Metadata
+	logger.Success("the selected phases of %s have been run", meta.LogString())
+	logger.Info("to continue, run 'eksctl create cluster -f %s --phases=%s'", cmd.ClusterConfigFile, strings.Join(remaining, ","))
+}
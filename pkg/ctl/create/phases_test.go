@@ -0,0 +1,40 @@
+package create
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("create cluster phases", func() {
+	It("runs all phases when none are selected", func() {
+		phases, err := parseCreatePhases(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phases.isPartial()).To(BeFalse())
+		Expect(phases.has(phaseAddons)).To(BeTrue())
+		Expect(phases.remaining()).To(BeEmpty())
+	})
+
+	It("returns the phases left to run after the selected ones", func() {
+		phases, err := parseCreatePhases([]string{"controlplane", "vpc"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phases.isPartial()).To(BeTrue())
+		Expect(phases.has(phaseNodeGroups)).To(BeFalse())
+		Expect(phases.String()).To(Equal("vpc,controlplane"))
+		Expect(phases.remaining()).To(Equal([]string{phaseOIDC, phaseNodeGroups, phaseAddons}))
+
+		phases, err = parseCreatePhases([]string{"oidc", "nodegroups"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(phases.remaining()).To(Equal([]string{phaseAddons}))
+	})
+
+	It("rejects invalid selections", func() {
+		_, err := parseCreatePhases([]string{"vpc"})
+		Expect(err).To(MatchError("the vpc and controlplane phases are created by the same CloudFormation stack and have to be run together"))
+
+		_, err = parseCreatePhases([]string{"gitops"})
+		Expect(err).To(MatchError(ContainSubstring("the gitops phase isn't supported")))
+
+		_, err = parseCreatePhases([]string{"network"})
+		Expect(err).To(MatchError(`unknown phase "network", valid phases are: vpc, controlplane, oidc, nodegroups, addons`))
+	})
+})
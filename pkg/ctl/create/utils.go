@@ -4,11 +4,10 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/kris-nova/logger"
-
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
 	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 func checkSubnetsGivenAsFlags(params *cmdutils.CreateClusterCmdParams) bool {
@@ -28,8 +27,8 @@ func checkVersion(cmd *cmdutils.Cmd, ctl *eks.ClusterProvider, meta *api.Cluster
 		meta.Version = api.LatestVersion
 		logger.Info("will use latest version (%s) for new nodegroup(s)", meta.Version)
 	default:
-		if !isValidVersion(meta.Version) {
-			if isDeprecatedVersion(meta.Version) {
+		if !api.IsSupportedVersion(meta.Version) {
+			if api.IsDeprecatedVersion(meta.Version) {
 				return fmt.Errorf("invalid version, %s is no longer supported, supported values: auto, default, latest, %s\nsee also: https://docs.aws.amazon.com/eks/latest/userguide/kubernetes-versions.html", meta.Version, strings.Join(api.SupportedVersions(), ", "))
 			}
 			return fmt.Errorf("invalid version %s, supported values: auto, default, latest, %s", meta.Version, strings.Join(api.SupportedVersions(), ", "))
@@ -51,21 +50,3 @@ func checkVersion(cmd *cmdutils.Cmd, ctl *eks.ClusterProvider, meta *api.Cluster
 
 	return nil
 }
-
-func isValidVersion(version string) bool {
-	for _, v := range api.SupportedVersions() {
-		if version == v {
-			return true
-		}
-	}
-	return false
-}
-
-func isDeprecatedVersion(version string) bool {
-	for _, v := range api.DeprecatedVersions() {
-		if version == v {
-			return true
-		}
-	}
-	return false
-}
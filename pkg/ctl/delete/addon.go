@@ -0,0 +1,74 @@
+package delete
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+type deleteAddonOptions struct {
+	name     string
+	preserve bool
+}
+
+func deleteAddonWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd, options *deleteAddonOptions) error) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var options deleteAddonOptions
+
+	cmd.SetDescription("addon", "Delete an EKS add-on",
+		"Delete an EKS add-on; with --preserve, EKS stops managing the add-on but its Kubernetes objects keep running in the cluster")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		if err := cmdutils.NewDeleteAddonLoader(cmd, &options.name).Load(); err != nil {
+			return err
+		}
+		return runFunc(cmd, &options)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("Addon", func(fs *pflag.FlagSet) {
+		fs.StringVar(&options.name, "name", "", "name of the add-on, e.g. vpc-cni")
+		fs.BoolVar(&options.preserve, "preserve", false, "keep the Kubernetes objects of the add-on running in the cluster, e.g. to manage them with Helm")
+	})
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		cmdutils.AddWaitFlag(fs, &cmd.Wait, "wait for the deletion of the add-on")
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func deleteAddonCmd(cmd *cmdutils.Cmd) {
+	deleteAddonWithRunFunc(cmd, doDeleteAddon)
+}
+
+func doDeleteAddon(cmd *cmdutils.Cmd, options *deleteAddonOptions) error {
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(meta)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	if options.preserve {
+		logger.Info("deleting EKS add-on %q of %s, keeping its Kubernetes objects", options.name, meta.LogString())
+	} else {
+		logger.Info("deleting EKS add-on %q of %s", options.name, meta.LogString())
+	}
+	return ctl.DeleteAddon(cfg, options.name, options.preserve, cmd.Wait)
+}
@@ -0,0 +1,50 @@
+package delete
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+)
+
+var _ = Describe("delete addon", func() {
+	run := func(args ...string) (*deleteAddonOptions, error) {
+		var options *deleteAddonOptions
+		grouping := cmdutils.NewGrouping()
+		parentCmd := cmdutils.NewVerbCmd("delete", "", "")
+		cmdutils.AddResourceCmd(grouping, parentCmd, func(cmd *cmdutils.Cmd) {
+			deleteAddonWithRunFunc(cmd, func(_ *cmdutils.Cmd, o *deleteAddonOptions) error {
+				options = o
+				return nil
+			})
+		})
+		parentCmd.SetArgs(append([]string{"addon"}, args...))
+		parentCmd.SetOut(new(bytes.Buffer))
+		err := parentCmd.Execute()
+		return options, err
+	}
+
+	It("requires the cluster and add-on names", func() {
+		_, err := run()
+		Expect(err).To(MatchError("--cluster must be set"))
+
+		_, err = run("--cluster", "foo")
+		Expect(err).To(MatchError("--name must be set"))
+	})
+
+	It("accepts the add-on name as an argument", func() {
+		options, err := run("--cluster", "foo", "vpc-cni")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options.name).To(Equal("vpc-cni"))
+		Expect(options.preserve).To(BeFalse())
+	})
+
+	It("preserves the Kubernetes objects of the add-on with --preserve", func() {
+		options, err := run("--cluster", "foo", "--name", "vpc-cni", "--preserve")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options.name).To(Equal("vpc-cni"))
+		Expect(options.preserve).To(BeTrue())
+	})
+})
@@ -3,9 +3,10 @@ package delete
 import (
 	"context"
 	"fmt"
-	"time"
+	"os"
 
-	"github.com/kris-nova/logger"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -14,10 +15,15 @@ import (
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/cleanup"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/drain"
 	"github.com/weaveworks/eksctl/pkg/elb"
+	"github.com/weaveworks/eksctl/pkg/hooks"
 	iamoidc "github.com/weaveworks/eksctl/pkg/iam/oidc"
 	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/notifications"
 	"github.com/weaveworks/eksctl/pkg/printers"
 	ssh "github.com/weaveworks/eksctl/pkg/ssh/client"
 	"github.com/weaveworks/eksctl/pkg/utils/kubeconfig"
@@ -28,11 +34,25 @@ func deleteClusterCmd(cmd *cmdutils.Cmd) {
 	cfg := api.NewClusterConfig()
 	cmd.ClusterConfig = cfg
 
+	var (
+		drainNodeGroups           bool
+		disableDeletionProtection bool
+		parallelism               int
+		cleanupOptions            cleanupOptions
+		retainOptions             retainOptions
+	)
+
 	cmd.SetDescription("cluster", "Delete a cluster", "")
 
 	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
 		cmd.NameArg = cmdutils.GetNameArg(args)
-		return doDeleteCluster(cmd)
+		if parallelism < 1 {
+			return fmt.Errorf("--parallel must be at least 1 (was %d)", parallelism)
+		}
+		if cleanupOptions.parallelism < 1 {
+			return fmt.Errorf("--cleanup-parallel must be at least 1 (was %d)", cleanupOptions.parallelism)
+		}
+		return doDeleteCluster(cmd, drainNodeGroups, disableDeletionProtection, parallelism, cleanupOptions, retainOptions)
 	}
 
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
@@ -42,8 +62,18 @@ func deleteClusterCmd(cmd *cmdutils.Cmd) {
 		cmd.Wait = false
 		cmdutils.AddWaitFlag(fs, &cmd.Wait, "deletion of all resources")
 
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		fs.BoolVar(&drainNodeGroups, "drain-nodegroups", false, "Drain the nodes of the nodegroups before deleting them, evicting the pods with respect to their disruption budgets, which can take up to --timeout")
+		fs.BoolVar(&disableDeletionProtection, "disable-deletion-protection", false, "Delete the cluster even though it was created with metadata.deletionProtection")
+		fs.IntVar(&parallelism, "parallel", 20, "Number of nodegroups to drain, and of nodegroup and iamserviceaccount stacks to delete, at the same time")
+		fs.IntVar(&cleanupOptions.parallelism, "cleanup-parallel", 1, "Number of cleaners of resources outside the stacks, e.g. the load balancers of services, to run at the same time; they run in order, stopping at the first failure, if set to 1")
+		fs.StringVar(&cleanupOptions.reportPath, "cleanup-report", "", "Write a JSON report of the cleanup of resources outside the stacks to this file")
+		fs.BoolVar(&retainOptions.keepVPC, "keep-vpc", false, "Keep the VPC created with the cluster, e.g. to reuse it for another cluster")
+		fs.BoolVar(&retainOptions.keepOIDCProvider, "keep-oidc-provider", false, "Keep the IAM OIDC provider of the cluster")
+		fs.StringSliceVar(&retainOptions.keepIAMServiceAccountRoles, "keep-iamserviceaccount-roles", nil, "Keep the IAM roles of these iamserviceaccounts, given as <namespace>/<name>, e.g. roles shared with other clusters")
+
+		cmdutils.AddConfigFileFlag(fs, cmd)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+		cmdutils.AddPhaseTimeoutFlags(fs, &cmd.ProviderConfig.Timeouts, api.TimeoutPhaseCleanup)
 	})
 
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, true)
@@ -57,6 +87,31 @@ func handleErrors(errs []error, subject string) error {
 	return fmt.Errorf("failed to delete %s", subject)
 }
 
+// notifyDeleteFailed publishes the errors along with the resources of the
+// cluster stacks that failed to delete
+func notifyDeleteFailed(notifier *notifications.Notifier, stackManager *manager.StackCollection, errs []error) {
+	if !notifier.Enabled() {
+		return
+	}
+	var messages, failedResources []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	failedStacks, err := stackManager.ListFailedStacks()
+	if err != nil {
+		logger.Warning("unable to list failed stacks: %v", err)
+	}
+	for _, s := range failedStacks {
+		for _, id := range s.FailedLogicalResourceIDs() {
+			failedResources = append(failedResources, fmt.Sprintf("%s/%s", *s.Stack.StackName, id))
+		}
+	}
+	notifier.Notify(notifications.ClusterDeleteFailed, "failed to delete cluster", map[string]interface{}{
+		"errors":          messages,
+		"failedResources": failedResources,
+	})
+}
+
 func deleteDeprecatedStacks(stackManager *manager.StackCollection) (bool, error) {
 	tasks, err := stackManager.DeleteTasksForDeprecatedStacks()
 	if err != nil {
@@ -73,7 +128,20 @@ func deleteDeprecatedStacks(stackManager *manager.StackCollection) (bool, error)
 	return false, nil
 }
 
-func doDeleteCluster(cmd *cmdutils.Cmd) error {
+// cleanupOptions are the options of the cleanup of resources outside the stacks of the cluster
+type cleanupOptions struct {
+	parallelism int
+	reportPath  string
+}
+
+// retainOptions are the resources of the cluster kept when it is deleted
+type retainOptions struct {
+	keepVPC                    bool
+	keepOIDCProvider           bool
+	keepIAMServiceAccountRoles []string
+}
+
+func doDeleteCluster(cmd *cmdutils.Cmd, drainNodeGroups, disableDeletionProtection bool, parallelism int, cleanupOptions cleanupOptions, retainOptions retainOptions) error {
 	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
 		return err
 	}
@@ -98,8 +166,27 @@ func doDeleteCluster(cmd *cmdutils.Cmd) error {
 		return err
 	}
 
+	// a control plane deleted outside of eksctl, e.g. in the console, leaves the stacks behind;
+	// they are deleted without the cleanup that needs the Kubernetes API
+	controlPlaneExists := true
 	if ok, err := ctl.CanDelete(cfg); !ok {
-		return err
+		if !eks.IsControlPlaneNotFound(err) {
+			return err
+		}
+		controlPlaneExists = false
+		logger.Warning("the control plane of cluster %q doesn't exist, its Kubernetes resources can't be cleaned up; deleting its stacks and the load balancers tagged with it", meta.Name)
+	}
+
+	stackManager := ctl.NewStackManager(cfg)
+
+	if !disableDeletionProtection {
+		protected, err := stackManager.HasDeletionProtection()
+		if err != nil {
+			return err
+		}
+		if protected {
+			return fmt.Errorf("cluster %q has deletion protection enabled, use --disable-deletion-protection to delete it", meta.Name)
+		}
 	}
 
 	var (
@@ -107,7 +194,10 @@ func doDeleteCluster(cmd *cmdutils.Cmd) error {
 		oidc      *iamoidc.OpenIDConnectManager
 	)
 
-	clusterOperable, _ := ctl.CanOperate(cfg)
+	var clusterOperable bool
+	if controlPlaneExists {
+		clusterOperable, _ = ctl.CanOperate(cfg)
+	}
 	oidcSupported := true
 	if clusterOperable {
 		clientSet, err = ctl.NewStdClientSet(cfg)
@@ -124,12 +214,50 @@ func doDeleteCluster(cmd *cmdutils.Cmd) error {
 		}
 	}
 
-	stackManager := ctl.NewStackManager(cfg)
+	var clientSetGetter kubernetes.ClientSetGetter
+	if clusterOperable {
+		clientSetGetter = kubernetes.NewCachedClientSet(clientSet)
+	} else {
+		// the iamserviceaccounts of a cluster that can't be reached are deleted without their
+		// serviceaccounts, and its IAM OIDC provider is found by the tag eksctl adds to it
+		oidc, err = iamoidc.FindTaggedProvider(ctl.Provider.IAM(), map[string]string{api.ClusterNameTag: meta.Name})
+		if err != nil {
+			logger.Warning("unable to find the IAM OIDC provider of cluster %q, it won't be deleted: %s", meta.Name, err.Error())
+		}
+	}
 
-	if err := deleteFargateProfiles(cmd, ctl); err != nil {
+	var newRawClient hooks.RawClientGetter
+	if clusterOperable {
+		newRawClient = func() (*kubernetes.RawClient, error) {
+			return ctl.NewRawClient(cfg)
+		}
+	}
+	hookRunner := hooks.NewRunner(cfg, newRawClient)
+	if err := hookRunner.Run(api.HookPreDelete, ""); err != nil {
 		return err
 	}
 
+	notifier := notifications.NewNotifier(cfg, ctl.Provider)
+
+	// load balancers are cleaned up while the nodes and Fargate profiles still exist, for the
+	// AWS Load Balancer Controller to delete the ALBs of Ingresses, and so that they aren't left
+	// behind when deleting the stacks fails
+	if err := runCleaners(ctl, cfg, clientSet, clusterOperable, controlPlaneExists, cleanupOptions); err != nil {
+		return err
+	}
+
+	// the Fargate profiles are deleted along with the nodegroups, before the control plane
+	var fargateTasks []manager.Task
+	if controlPlaneExists {
+		task, err := newTaskToDeleteFargateProfiles(cmd, ctl)
+		if err != nil {
+			return err
+		}
+		if task != nil {
+			fargateTasks = append(fargateTasks, task)
+		}
+	}
+
 	ssh.DeleteKeys(meta.Name, ctl.Provider.EC2())
 
 	kubeconfig.MaybeDeleteConfig(meta)
@@ -141,20 +269,22 @@ func doDeleteCluster(cmd *cmdutils.Cmd) error {
 		return nil
 	}
 
+	if clusterOperable {
+		if err := ctl.DeletePrivateHostedZone(cfg); err != nil {
+			logger.Warning("unable to delete the private hosted zone of the API server endpoint: %s", err.Error())
+		}
+	}
+
 	{
-		// only need to cleanup ELBs if the cluster has already been created.
-		if clusterOperable {
-			ctx, cleanup := context.WithTimeout(context.Background(), 10*time.Minute)
-			defer cleanup()
+		if clusterOperable && drainNodeGroups {
+			drainNodeGroups(ctl, cfg, clientSet, parallelism)
+		}
 
-			logger.Info("cleaning up LoadBalancer services")
-			if err := elb.Cleanup(ctx, ctl.Provider.EC2(), ctl.Provider.ELB(), ctl.Provider.ELBV2(), clientSet, cfg); err != nil {
-				return err
-			}
+		if err := retainResources(stackManager, retainOptions); err != nil {
+			return err
 		}
 
-		deleteOIDCProvider := clusterOperable && oidcSupported
-		tasks, err := stackManager.NewTasksToDeleteClusterWithNodeGroups(deleteOIDCProvider, oidc, kubernetes.NewCachedClientSet(clientSet), cmd.Wait, func(errs chan error, _ string) error {
+		tasks, err := stackManager.NewTasksToDeleteClusterWithNodeGroups(oidcSupported, retainOptions.keepOIDCProvider, oidc, clientSetGetter, cmd.Wait, parallelism, func(errs chan error, _ string) error {
 			logger.Info("trying to cleanup dangling network interfaces")
 			if err := ctl.LoadClusterVPC(cfg); err != nil {
 				return errors.Wrapf(err, "getting VPC configuration for cluster %q", cfg.Metadata.Name)
@@ -165,7 +295,7 @@ func doDeleteCluster(cmd *cmdutils.Cmd) error {
 				close(errs)
 			}()
 			return nil
-		})
+		}, fargateTasks...)
 
 		if err != nil {
 			return err
@@ -178,16 +308,152 @@ func doDeleteCluster(cmd *cmdutils.Cmd) error {
 
 		logger.Info(tasks.Describe())
 		if errs := tasks.DoAllSync(); len(errs) > 0 {
+			notifyDeleteFailed(notifier, stackManager, errs)
 			return handleErrors(errs, "cluster with nodegroup(s)")
 		}
 
 		logger.Success("all cluster resources were deleted")
+		notifier.Notify(notifications.ClusterDeleted, "all cluster resources were deleted", nil)
+
+		if err := hookRunner.Run(api.HookPostDelete, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// retainResources updates the stacks to keep the resources to retain when they're deleted, and
+// tags them as released from the cluster
+func retainResources(stackManager *manager.StackCollection, options retainOptions) error {
+	var retained []*cloudformation.StackResource
+
+	if options.keepVPC {
+		clusterStack, err := stackManager.DescribeClusterStack()
+		if err != nil {
+			return err
+		}
+		resources, err := stackManager.RetainStackResources(clusterStack, manager.VPCResourceTypes...)
+		if err != nil {
+			return errors.Wrap(err, "keeping the VPC")
+		}
+		if len(resources) == 0 {
+			logger.Warning("the cluster stack has no VPC resources to keep, the VPC wasn't created by eksctl")
+		}
+		retained = append(retained, resources...)
+	}
+
+	if len(options.keepIAMServiceAccountRoles) > 0 {
+		stacks, err := stackManager.DescribeIAMServiceAccountStacks()
+		if err != nil {
+			return err
+		}
+		stacksByName := map[string]*manager.Stack{}
+		for _, s := range stacks {
+			stacksByName[stackManager.GetIAMServiceAccountName(s)] = s
+		}
+		for _, name := range options.keepIAMServiceAccountRoles {
+			s, ok := stacksByName[name]
+			if !ok {
+				return fmt.Errorf("iamserviceaccount %q to keep the role of doesn't exist", name)
+			}
+			resources, err := stackManager.RetainStackResources(s)
+			if err != nil {
+				return errors.Wrapf(err, "keeping the IAM role of iamserviceaccount %q", name)
+			}
+			retained = append(retained, resources...)
+		}
+	}
+
+	if len(retained) == 0 {
+		return nil
+	}
+	if err := stackManager.ReleaseResources(retained); err != nil {
+		return errors.Wrap(err, "tagging the kept resources as released from the cluster")
+	}
+	for _, r := range retained {
+		logger.Info("keeping %s %q", *r.ResourceType, *r.PhysicalResourceId)
+	}
+	return nil
+}
+
+func runCleaners(ctl *eks.ClusterProvider, cfg *api.ClusterConfig, clientSet kubernetes.Interface, clusterOperable, controlPlaneExists bool, cleanupOptions cleanupOptions) error {
+	// the built-in cleaners only need to run if the cluster has already been created,
+	// registered cleaners always run and are given a nil client set in that case; without
+	// a control plane, the load balancers of Services and Ingresses are found by their tags
+	cleaners := cleanup.Registered()
+	if clusterOperable {
+		cleaners = append([]cleanup.Cleaner{&elb.IngressCleaner{}, &elb.Cleaner{}}, cleaners...)
+	} else if !controlPlaneExists {
+		cleaners = append([]cleanup.Cleaner{&elb.OrphanCleaner{}}, cleaners...)
+	}
+	if len(cleaners) == 0 {
+		return nil
 	}
 
+	timeout := ctl.Provider.Timeout(api.TimeoutPhaseCleanup)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	params := cleanup.Params{
+		ClusterConfig: cfg,
+		Provider:      ctl.Provider,
+		ClientSet:     clientSet,
+	}
+	report, err := cleanup.Run(ctx, params, cleaners, cleanup.Options{Parallelism: cleanupOptions.parallelism})
+	for _, result := range report.Results {
+		logger.Info("cleanup of %s", result.Summary())
+	}
+	if cleanupOptions.reportPath != "" {
+		if err := writeCleanupReport(report, cleanupOptions.reportPath); err != nil {
+			logger.Warning(err.Error())
+		}
+	}
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return api.TimeoutPhaseCleanup.WrapTimeout(err, timeout)
+	}
+	return err
+}
+
+// writeCleanupReport writes the report of the cleaners as JSON to the file at path
+func writeCleanupReport(report *cleanup.Report, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.Wrapf(err, "creating the cleanup report %q", path)
+	}
+	defer file.Close()
+	if err := printers.NewJSONPrinter().PrintObj(report, file); err != nil {
+		return errors.Wrapf(err, "writing the cleanup report %q", path)
+	}
+	logger.Info("wrote the cleanup report to %q", path)
 	return nil
 }
 
-func deleteFargateProfiles(cmd *cmdutils.Cmd, ctl *eks.ClusterProvider) error {
+// drainNodeGroups evicts the pods of all nodegroups, respecting their disruption budgets, so that
+// workloads shut down gracefully before the nodes are deleted; nodegroups that fail to drain are
+// deleted anyway
+func drainNodeGroups(ctl *eks.ClusterProvider, cfg *api.ClusterConfig, clientSet kubernetes.Interface, parallelism int) {
+	// the nodegroups of the config file are needed to delete those in other accounts
+	existingCfg := cfg.DeepCopy()
+	existingCfg.NodeGroups = nil
+	existingCfg.ManagedNodeGroups = nil
+	if err := ctl.AddExistingNodeGroups(existingCfg); err != nil {
+		logger.Warning("unable to list the nodegroups to drain: %s", err.Error())
+		return
+	}
+	allNodeGroups := cmdutils.ToKubeNodeGroups(existingCfg)
+	if len(allNodeGroups) == 0 {
+		return
+	}
+	logger.Info("draining %d nodegroup(s) in cluster %q, %d at a time", len(allNodeGroups), cfg.Metadata.Name, parallelism)
+	for _, err := range drain.NodeGroups(clientSet, allNodeGroups, parallelism, ctl.Provider.WaitTimeout(), false) {
+		logger.Warning("%s, deleting it anyway", err.Error())
+	}
+}
+
+// newTaskToDeleteFargateProfiles returns a task deleting the Fargate profiles of the cluster, nil
+// if it has none
+func newTaskToDeleteFargateProfiles(cmd *cmdutils.Cmd, ctl *eks.ClusterProvider) (manager.Task, error) {
 	awsClient := fargate.NewClientWithWaitTimeout(
 		cmd.ClusterConfig.Metadata.Name,
 		ctl.Provider.EKS(),
@@ -199,27 +465,23 @@ func deleteFargateProfiles(cmd *cmdutils.Cmd, ctl *eks.ClusterProvider) error {
 			logger.Debug("Fargate: unauthorized error: %v", err)
 			logger.Info("either account is not authorized to use Fargate or region %s is not supported. Ignoring error",
 				cmd.ClusterConfig.Metadata.Region)
-			return nil
+			return nil, nil
 		}
-		return err
+		return nil, err
+	}
+	if len(profileNames) == 0 {
+		return nil, nil
 	}
 
-	// Linearise the deleting of Fargate profiles by passing as the API
-	// otherwise errors out with:
-	//   ResourceInUseException: Cannot delete Fargate Profile ${name2} because
-	//   cluster ${clusterName} currently has Fargate profile ${name1} in
-	//   status DELETING
-
-	for _, profileName := range profileNames {
-		logger.Info("deleting Fargate profile %q", *profileName)
-		// All Fargate profiles must be completely deleted by waiting for the deletion to complete, before deleting
-		// the cluster itself, otherwise it can result in this error:
-		//   Cannot delete because cluster <cluster> currently has Fargate profile <profile> in status DELETING
-		if err := awsClient.DeleteProfile(*profileName, true); err != nil {
+	// All Fargate profiles must be completely deleted before deleting the cluster itself,
+	// otherwise it can result in this error:
+	//   Cannot delete because cluster <cluster> currently has Fargate profile <profile> in status DELETING
+	info := fmt.Sprintf("delete %d Fargate profile(s)", len(profileNames))
+	return manager.NewFuncTask(info, func() error {
+		if err := awsClient.DeleteProfiles(aws.StringValueSlice(profileNames)); err != nil {
 			return err
 		}
-		logger.Info("deleted Fargate profile %q", *profileName)
-	}
-	logger.Info("deleted %v Fargate profile(s)", len(profileNames))
-	return nil
+		logger.Info("deleted %v Fargate profile(s)", len(profileNames))
+		return nil
+	}), nil
 }
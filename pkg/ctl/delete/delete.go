@@ -15,6 +15,7 @@ func Command(flagGrouping *cmdutils.FlagGrouping) *cobra.Command {
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, deleteIAMServiceAccountCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, deleteIAMIdentityMappingCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, deleteFargateProfile)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, deleteAddonCmd)
 
 	return verbCmd
 }
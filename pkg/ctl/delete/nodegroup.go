@@ -1,7 +1,10 @@
 package delete
 
 import (
-	"github.com/kris-nova/logger"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
@@ -9,6 +12,8 @@ import (
 	"github.com/weaveworks/eksctl/pkg/authconfigmap"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
 	"github.com/weaveworks/eksctl/pkg/drain"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/vpc"
 )
 
 func deleteNodeGroupCmd(cmd *cmdutils.Cmd) {
@@ -35,7 +40,7 @@ func deleteNodeGroupWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cm
 		fs.StringVar(&cfg.Metadata.Name, "cluster", "", "EKS cluster name")
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
 		fs.StringVarP(&ng.Name, "name", "n", "", "Name of the nodegroup to delete")
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddConfigFileFlag(fs, cmd)
 		cmdutils.AddApproveFlag(fs, cmd)
 		cmdutils.AddNodeGroupFilterFlags(fs, &cmd.Include, &cmd.Exclude)
 		fs.BoolVar(&onlyMissing, "only-missing", false, "Only delete nodegroups that are not defined in the given config file")
@@ -149,15 +154,41 @@ func doDeleteNodeGroup(cmd *cmdutils.Cmd, ng *api.NodeGroup, updateAuthConfigMap
 			return false
 		}
 
-		tasks, err := stackManager.NewTasksToDeleteNodeGroups(shouldDelete, cmd.Wait, nil)
+		var (
+			loadVPCOnce sync.Once
+			loadVPCErr  error
+		)
+		cleanup := func(errs chan error, ngName string) error {
+			logger.Info("trying to cleanup resources left by nodegroup %q", ngName)
+			loadVPCOnce.Do(func() { loadVPCErr = ctl.LoadClusterVPC(cfg) })
+			if loadVPCErr != nil {
+				return errors.Wrapf(loadVPCErr, "getting VPC configuration for cluster %q", cfg.Metadata.Name)
+			}
+
+			go func() {
+				errs <- vpc.CleanupNodeGroupResources(ctl.Provider.EC2(), cfg, ngName)
+				close(errs)
+			}()
+			return nil
+		}
+
+		tasks, err := stackManager.NewTasksToDeleteNodeGroups(shouldDelete, cmd.Wait, cleanup)
 		if err != nil {
 			return err
 		}
 		tasks.PlanMode = cmd.Plan
 		logger.Info(tasks.Describe())
 		if errs := tasks.DoAllSync(); len(errs) > 0 {
+			logger.Info("if a nodegroup stack is in state %s, run this command again to release the resources blocking its deletion", cloudformation.StackStatusDeleteFailed)
 			return handleErrors(errs, "nodegroup(s)")
 		}
+		if cmd.Wait && !cmd.Plan {
+			for _, ng := range allNodeGroups {
+				if err := vpc.DeleteNodeGroupLaunchTemplate(ctl.Provider.EC2(), cfg, ng.NameString()); err != nil {
+					logger.Warning(err.Error())
+				}
+			}
+		}
 		cmdutils.LogCompletedAction(cmd.Plan, "deleted %d nodegroup(s) from cluster %q", len(allNodeGroups), cfg.Metadata.Name)
 	}
 
@@ -0,0 +1,108 @@
+package drain
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/drain"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+func drainClusterCmd(cmd *cmdutils.Cmd) {
+	drainClusterWithRunFunc(cmd, func(cmd *cmdutils.Cmd, parallelism int, undo bool) error {
+		return doDrainCluster(cmd, parallelism, undo)
+	})
+}
+
+func drainClusterWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd, parallelism int, undo bool) error) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var (
+		parallelism int
+		undo        bool
+	)
+
+	cmd.SetDescription("cluster", "Cordon and drain all nodegroups of a cluster",
+		"Cordon and drain the nodes of all managed and unmanaged nodegroups, without deleting anything, e.g. before a maintenance window")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		if parallelism < 1 {
+			return fmt.Errorf("--parallel must be at least 1 (was %d)", parallelism)
+		}
+		return runFunc(cmd, parallelism, undo)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		fs.StringVarP(&cfg.Metadata.Name, "name", "n", "", "EKS cluster name")
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		cmdutils.AddApproveFlag(fs, cmd)
+		fs.IntVar(&parallelism, "parallel", 1, "Number of nodegroups to drain at the same time")
+		fs.BoolVar(&undo, "undo", false, "Uncordon the nodegroups")
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, true)
+}
+
+func doDrainCluster(cmd *cmdutils.Cmd, parallelism int, undo bool) error {
+	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(cfg.Metadata)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	if ok, err := ctl.CanOperate(cfg); !ok {
+		return err
+	}
+
+	// all nodegroups of the cluster are drained, not only those in the config file
+	cfg.NodeGroups = nil
+	cfg.ManagedNodeGroups = nil
+	if err := ctl.AddExistingNodeGroups(cfg); err != nil {
+		return err
+	}
+
+	verb := "drain"
+	if undo {
+		verb = "uncordon"
+	}
+	cmdutils.LogIntendedAction(cmd.Plan, "%s %d nodegroup(s) and %d managed nodegroup(s) in cluster %q, %d at a time", verb, len(cfg.NodeGroups), len(cfg.ManagedNodeGroups), cfg.Metadata.Name, parallelism)
+
+	allNodeGroups := cmdutils.ToKubeNodeGroups(cfg)
+	cmdutils.LogPlanModeWarning(cmd.Plan && len(allNodeGroups) > 0)
+
+	if cmd.Plan || len(allNodeGroups) == 0 {
+		return nil
+	}
+
+	clientSet, err := ctl.NewStdClientSet(cfg)
+	if err != nil {
+		return err
+	}
+
+	if errs := drain.NodeGroups(clientSet, allNodeGroups, parallelism, ctl.Provider.WaitTimeout(), undo); len(errs) > 0 {
+		for _, err := range errs {
+			logger.Critical("%s\n", err.Error())
+		}
+		return fmt.Errorf("failed to %s %d of %d nodegroup(s) in cluster %q", verb, len(errs), len(allNodeGroups), cfg.Metadata.Name)
+	}
+	logger.Success("%sed all nodegroups in cluster %q", verb, cfg.Metadata.Name)
+	return nil
+}
@@ -0,0 +1,56 @@
+package drain
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+)
+
+var _ = Describe("drain cluster", func() {
+	DescribeTable("drain cluster successfully",
+		func(expectedParallelism int, expectedUndo bool, args ...string) {
+			cmd := newMockEmptyCmd(args...)
+			count := 0
+			cmdutils.AddResourceCmd(cmdutils.NewGrouping(), cmd.parentCmd, func(cmd *cmdutils.Cmd) {
+				drainClusterWithRunFunc(cmd, func(cmd *cmdutils.Cmd, parallelism int, undo bool) error {
+					Expect(cmd.ClusterConfig.Metadata.Name).To(Equal("clusterName"))
+					Expect(parallelism).To(Equal(expectedParallelism))
+					Expect(undo).To(Equal(expectedUndo))
+					count++
+					return nil
+				})
+			})
+			_, err := cmd.execute()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(1))
+		},
+		Entry("with valid details", 1, false, "cluster", "--name", "clusterName"),
+		Entry("with parallelism", 4, false, "cluster", "--name", "clusterName", "--parallel", "4"),
+		Entry("with --undo", 1, true, "cluster", "--name", "clusterName", "--undo"),
+	)
+
+	DescribeTable("invalid flags or arguments",
+		func(c invalidParamsCase) {
+			cmd := newDefaultCmd(c.args...)
+			_, err := cmd.execute()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(c.error.Error()))
+		},
+		Entry("missing required flag --name", invalidParamsCase{
+			args:  []string{"cluster"},
+			error: fmt.Errorf("--name must be set"),
+		}),
+		Entry("setting --name and argument at the same time", invalidParamsCase{
+			args:  []string{"cluster", "clusterName", "--name", "clusterName"},
+			error: fmt.Errorf("--name=clusterName and argument clusterName cannot be used at the same time"),
+		}),
+		Entry("invalid parallelism", invalidParamsCase{
+			args:  []string{"cluster", "--name", "clusterName", "--parallel", "0"},
+			error: fmt.Errorf("--parallel must be at least 1 (was 0)"),
+		}),
+	)
+})
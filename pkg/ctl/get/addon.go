@@ -0,0 +1,155 @@
+package get
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eksaddons"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/printers"
+)
+
+type addonParams struct {
+	output    printers.Type
+	available bool
+}
+
+func getAddonCmd(cmd *cmdutils.Cmd) {
+	getAddonWithRunFunc(cmd, func(cmd *cmdutils.Cmd, params *addonParams) error {
+		return doGetAddon(cmd, params)
+	})
+}
+
+func getAddonWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd, params *addonParams) error) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	params := &addonParams{}
+
+	cmd.SetDescription("addon", "Get EKS add-on(s)",
+		"Get the EKS add-ons installed in a cluster, or with --available, all the add-ons available for the Kubernetes version of the cluster", "addons")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		if cfg.Metadata.Name == "" {
+			return cmdutils.ErrMustBeSet(cmdutils.ClusterNameFlag(cmd))
+		}
+		if cmd.NameArg != "" {
+			return cmdutils.ErrUnsupportedNameArg()
+		}
+		return runFunc(cmd, params)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		fs.BoolVar(&params.available, "available", false, "list the add-ons available for the Kubernetes version of the cluster, with their default and latest versions")
+		fs.StringVarP(&params.output, "output", "o", "table", "specifies the output format (valid option: table, json, yaml)")
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doGetAddon(cmd *cmdutils.Cmd, params *addonParams) error {
+	cfg := cmd.ClusterConfig
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	printer, err := printers.NewPrinter(params.output)
+	if err != nil {
+		return err
+	}
+
+	if params.available {
+		addons, err := ctl.ListAvailableAddons(cfg)
+		if err != nil {
+			return err
+		}
+		if params.output == printers.TableType {
+			addAvailableAddonTableColumns(printer.(*printers.TablePrinter))
+		}
+		return printer.PrintObjWithKind("addons", addons, os.Stdout)
+	}
+
+	addons, err := ctl.ListInstalledAddons(cfg)
+	if err != nil {
+		return err
+	}
+	if params.output == printers.TableType {
+		roleARNs, err := ctl.NewStackManager(cfg).GetAddonRoleARNs()
+		if err != nil {
+			// clusters eksctl didn't create have no stacks
+			logger.Debug("listing the IAM roles eksctl created for add-ons: %v", err)
+		}
+		addAddonTableColumns(printer.(*printers.TablePrinter), roleARNs)
+	}
+	return printer.PrintObjWithKind("addons", addons, os.Stdout)
+}
+
+// addAddonTableColumns adds the columns of installed add-ons, roleARNs are the roles eksctl
+// created for add-ons by name
+func addAddonTableColumns(printer *printers.TablePrinter, roleARNs map[string]string) {
+	printer.AddColumn("NAME", func(a *eksaddons.Addon) string {
+		return aws.StringValue(a.AddonName)
+	})
+	printer.AddColumn("VERSION", func(a *eksaddons.Addon) string {
+		return aws.StringValue(a.AddonVersion)
+	})
+	printer.AddColumn("STATUS", func(a *eksaddons.Addon) string {
+		return aws.StringValue(a.Status)
+	})
+	printer.AddColumn("IAM ROLE", func(a *eksaddons.Addon) string {
+		if a.ServiceAccountRoleArn == nil {
+			return roleARNs[aws.StringValue(a.AddonName)]
+		}
+		return aws.StringValue(a.ServiceAccountRoleArn)
+	})
+	printer.AddColumn("ROLE CREATED BY EKSCTL", func(a *eksaddons.Addon) string {
+		roleARN, ok := roleARNs[aws.StringValue(a.AddonName)]
+		return strconv.FormatBool(ok && (a.ServiceAccountRoleArn == nil || roleARN == *a.ServiceAccountRoleArn))
+	})
+}
+
+func addAvailableAddonTableColumns(printer *printers.TablePrinter) {
+	orDash := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+	printer.AddColumn("NAME", func(a *eksaddons.AvailableAddon) string {
+		return a.Name
+	})
+	printer.AddColumn("TYPE", func(a *eksaddons.AvailableAddon) string {
+		return a.Type
+	})
+	printer.AddColumn("PUBLISHER", func(a *eksaddons.AvailableAddon) string {
+		return a.Publisher
+	})
+	printer.AddColumn("DEFAULT VERSION", func(a *eksaddons.AvailableAddon) string {
+		return orDash(a.DefaultVersion)
+	})
+	printer.AddColumn("LATEST VERSION", func(a *eksaddons.AvailableAddon) string {
+		return a.LatestVersion
+	})
+	printer.AddColumn("POD IDENTITY", func(a *eksaddons.AvailableAddon) string {
+		return strconv.FormatBool(a.PodIdentity)
+	})
+	printer.AddColumn("INSTALLED VERSION", func(a *eksaddons.AvailableAddon) string {
+		return orDash(a.InstalledVersion)
+	})
+}
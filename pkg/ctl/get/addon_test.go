@@ -0,0 +1,38 @@
+package get
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("get", func() {
+	Describe("addon", func() {
+		It("missing required flag --cluster", func() {
+			cmd := newMockCmd("addon", "--available")
+			_, err := cmd.execute()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("--cluster must be set"))
+		})
+
+		It("setting name argument", func() {
+			cmd := newMockCmd("addon", "--cluster", "dummy", "vpc-cni")
+			_, err := cmd.execute()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("name argument is not supported"))
+		})
+	})
+
+	Describe("resources", func() {
+		It("missing required flag --cluster", func() {
+			cmd := newMockCmd("resources")
+			_, err := cmd.execute()
+			Expect(err).To(MatchError("--cluster must be set"))
+		})
+
+		It("setting name argument", func() {
+			cmd := newMockCmd("resources", "--cluster", "dummy", "eksctl-dummy-cluster")
+			_, err := cmd.execute()
+			Expect(err).To(MatchError("name argument is not supported"))
+		})
+	})
+})
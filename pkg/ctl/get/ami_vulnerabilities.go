@@ -0,0 +1,135 @@
+package get
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/inspector"
+	"github.com/weaveworks/eksctl/pkg/printers"
+)
+
+type amiVulnerabilitiesParams struct {
+	output         printers.Type
+	failOnSeverity string
+}
+
+func getAMIVulnerabilitiesCmd(cmd *cmdutils.Cmd) {
+	getAMIVulnerabilitiesWithRunFunc(cmd, func(cmd *cmdutils.Cmd, params *amiVulnerabilitiesParams) error {
+		return doGetAMIVulnerabilities(cmd, params)
+	})
+}
+
+func getAMIVulnerabilitiesWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd, params *amiVulnerabilitiesParams) error) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	params := &amiVulnerabilitiesParams{}
+
+	cmd.SetDescription("ami-vulnerabilities", "Get the vulnerabilities of the AMIs of the nodes",
+		"Get the AMIs of the nodes of a cluster and the numbers of vulnerabilities Amazon Inspector found in them; EC2 scanning has to be enabled in the account")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		if cfg.Metadata.Name == "" {
+			return cmdutils.ErrMustBeSet(cmdutils.ClusterNameFlag(cmd))
+		}
+		if cmd.NameArg != "" {
+			return cmdutils.ErrUnsupportedNameArg()
+		}
+		if params.failOnSeverity != "" {
+			if err := inspector.ValidateSeverity(params.failOnSeverity); err != nil {
+				return err
+			}
+		}
+		return runFunc(cmd, params)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		fs.StringVarP(&params.output, "output", "o", "table", "specifies the output format (valid option: table, json, yaml)")
+		fs.StringVar(&params.failOnSeverity, "fail-on-severity", "", fmt.Sprintf("exit with an error if an AMI has vulnerabilities of this severity or a more severe one (valid options: %s, %s, %s)", inspector.SeverityCritical, inspector.SeverityHigh, inspector.SeverityMedium))
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doGetAMIVulnerabilities(cmd *cmdutils.Cmd, params *amiVulnerabilitiesParams) error {
+	cfg := cmd.ClusterConfig
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	reports, err := ctl.GetAMIVulnerabilities(cfg.Metadata.Name)
+	if err != nil {
+		return err
+	}
+
+	printer, err := printers.NewPrinter(params.output)
+	if err != nil {
+		return err
+	}
+	if params.output == printers.TableType {
+		addAMIVulnerabilitiesTableColumns(printer.(*printers.TablePrinter))
+	}
+	if err := printer.PrintObjWithKind("ami-vulnerabilities", reports, os.Stdout); err != nil {
+		return err
+	}
+
+	if params.failOnSeverity != "" {
+		var vulnerable []string
+		for _, report := range reports {
+			if report.HasFindings(params.failOnSeverity) {
+				vulnerable = append(vulnerable, report.ImageID)
+			}
+		}
+		if len(vulnerable) > 0 {
+			return fmt.Errorf("AMI(s) %s of cluster %q have vulnerabilities of severity %s or higher", strings.Join(vulnerable, ", "), cfg.Metadata.Name, params.failOnSeverity)
+		}
+	}
+	return nil
+}
+
+func addAMIVulnerabilitiesTableColumns(printer *printers.TablePrinter) {
+	printer.AddColumn("IMAGE ID", func(r *inspector.AMIReport) string {
+		return r.ImageID
+	})
+	printer.AddColumn("NODEGROUPS", func(r *inspector.AMIReport) string {
+		return strings.Join(r.NodeGroups, ",")
+	})
+	printer.AddColumn("INSTANCES", func(r *inspector.AMIReport) string {
+		return strconv.Itoa(r.Instances)
+	})
+	count := func(r *inspector.AMIReport, n int64) string {
+		if !r.Scanned {
+			return "-"
+		}
+		return strconv.FormatInt(n, 10)
+	}
+	printer.AddColumn("CRITICAL", func(r *inspector.AMIReport) string {
+		return count(r, r.Critical)
+	})
+	printer.AddColumn("HIGH", func(r *inspector.AMIReport) string {
+		return count(r, r.High)
+	})
+	printer.AddColumn("MEDIUM", func(r *inspector.AMIReport) string {
+		return count(r, r.Medium)
+	})
+	printer.AddColumn("TOTAL", func(r *inspector.AMIReport) string {
+		return count(r, r.All)
+	})
+}
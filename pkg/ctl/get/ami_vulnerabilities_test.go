@@ -0,0 +1,31 @@
+package get
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("get", func() {
+	Describe("ami-vulnerabilities", func() {
+		It("missing required flag --cluster", func() {
+			cmd := newMockCmd("ami-vulnerabilities")
+			_, err := cmd.execute()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("--cluster must be set"))
+		})
+
+		It("setting name argument", func() {
+			cmd := newMockCmd("ami-vulnerabilities", "--cluster", "dummy", "dummyName")
+			_, err := cmd.execute()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("name argument is not supported"))
+		})
+
+		It("invalid --fail-on-severity", func() {
+			cmd := newMockCmd("ami-vulnerabilities", "--cluster", "dummy", "--fail-on-severity", "low")
+			_, err := cmd.execute()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(`unknown severity "low", valid severities are: critical, high, medium`))
+		})
+	})
+})
@@ -4,17 +4,19 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/kris-nova/logger"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
 	"github.com/weaveworks/eksctl/pkg/fargate"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 type options struct {
 	fargate.Options
 	getCmdParams
+	withPodCount bool
 }
 
 func getFargateProfileWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd, options *options) error) {
@@ -44,11 +46,12 @@ func configureGetFargateProfileCmd(cmd *cmdutils.Cmd) *options {
 	var options options
 	cmd.FlagSetGroup.InFlagSet("Fargate", func(fs *pflag.FlagSet) {
 		cmdutils.AddFlagsForFargate(fs, &options.Options)
+		fs.BoolVar(&options.withPodCount, "with-pod-count", false, "Count the running pods of each Fargate profile, to find the profiles that aren't used")
 	})
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
 		cmdutils.AddClusterFlag(fs, cmd.ClusterConfig.Metadata)
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddConfigFileFlag(fs, cmd)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
 		cmdutils.AddCommonFlagsForGetCmd(fs, &options.chunkSize, &options.output)
 	})
@@ -81,7 +84,26 @@ func doGetFargateProfile(cmd *cmdutils.Cmd, options *options) error {
 	if err != nil {
 		return err
 	}
-	return fargate.PrintProfiles(profiles, os.Stdout, options.output)
+	if !options.withPodCount {
+		return fargate.PrintProfiles(profiles, os.Stdout, options.output)
+	}
+
+	runningPods, err := countRunningPods(ctl, cmd.ClusterConfig)
+	if err != nil {
+		return err
+	}
+	return fargate.PrintProfilesWithPodCounts(profiles, runningPods, os.Stdout, options.output)
+}
+
+func countRunningPods(ctl *eks.ClusterProvider, cfg *api.ClusterConfig) (map[string]int, error) {
+	if ok, err := ctl.CanOperate(cfg); !ok {
+		return nil, err
+	}
+	clientSet, err := ctl.NewStdClientSet(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return fargate.CountRunningPods(clientSet)
 }
 
 func getProfiles(awsClient *fargate.Client, name string) ([]*api.FargateProfile, error) {
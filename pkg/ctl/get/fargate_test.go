@@ -25,6 +25,14 @@ var _ = Describe("get", func() {
 			Expect(err).To(Not(HaveOccurred()))
 			Expect(cmd.cmd.ClusterConfig.Metadata.Name).To(Equal("foo"))
 			Expect(cmd.options.ProfileName).To(Equal(""))
+			Expect(cmd.options.withPodCount).To(BeFalse())
+		})
+
+		It("optionally counts the running pods of the profiles", func() {
+			cmd := newMockGetFargateProfileCmd("fargateprofile", "--cluster", "foo", "--with-pod-count", "-o", "json")
+			_, err := cmd.execute()
+			Expect(err).To(Not(HaveOccurred()))
+			Expect(cmd.options.withPodCount).To(BeTrue())
 		})
 
 		It("optionally accepts a profile name, which can be provided as an argument", func() {
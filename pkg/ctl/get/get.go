@@ -1,15 +1,27 @@
 package get
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
 	"github.com/weaveworks/eksctl/pkg/printers"
 )
 
 type getCmdParams struct {
-	chunkSize int
-	output    printers.Type
+	chunkSize  int
+	output     printers.Type
+	allRegions bool
+	regions    []string
+}
+
+// addAllRegionsFlags adds --all-regions, and --regions to restrict the regions it queries
+func addAllRegionsFlags(fs *pflag.FlagSet, params *getCmdParams, usage string) {
+	fs.BoolVarP(&params.allRegions, "all-regions", "A", false, usage)
+	fs.StringSliceVar(&params.regions, "regions", nil, fmt.Sprintf("regions to query with --all-regions (defaults to $%s, or all supported regions)", eks.RegionsEnvVar))
 }
 
 // Command will create the `get` commands
@@ -22,6 +34,9 @@ func Command(flagGrouping *cmdutils.FlagGrouping) *cobra.Command {
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, getIAMIdentityMappingCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, getLabelsCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, getFargateProfile)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, getAMIVulnerabilitiesCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, getAddonCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, getResourcesCmd)
 
 	return verbCmd
 }
@@ -3,6 +3,7 @@ package get
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -11,6 +12,9 @@ import (
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/iam"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/printers"
 )
 
@@ -39,7 +43,7 @@ func getIAMServiceAccountCmd(cmd *cmdutils.Cmd) {
 		fs.StringVar(&serviceAccount.Namespace, "namespace", "default", "namespace where to delete the iamserviceaccount")
 
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddConfigFileFlag(fs, cmd)
 
 		cmdutils.AddCommonFlagsForGetCmd(fs, &params.chunkSize, &params.output)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
@@ -111,6 +115,10 @@ func doGetIAMServiceAccount(cmd *cmdutils.Cmd, serviceAccount *api.ClusterIAMSer
 		return err
 	}
 
+	if err := describeIAMServiceAccountRoles(ctl, cfg); err != nil {
+		return err
+	}
+
 	printer, err := printers.NewPrinter(params.output)
 	if err != nil {
 		return err
@@ -136,4 +144,45 @@ func addIAMServiceAccountSummaryTableColumns(printer *printers.TablePrinter) {
 	printer.AddColumn("ROLE ARN", func(sa *api.ClusterIAMServiceAccount) string {
 		return *sa.Status.RoleARN
 	})
+	printer.AddColumn("POLICIES", func(sa *api.ClusterIAMServiceAccount) string {
+		return strings.Join(append(append([]string{}, sa.Status.AttachedPolicyARNs...), sa.Status.InlinePolicyNames...), ",")
+	})
+	printer.AddColumn("STATUS", func(sa *api.ClusterIAMServiceAccount) string {
+		if len(sa.Status.Problems) > 0 {
+			return "broken: " + strings.Join(sa.Status.Problems, "; ")
+		}
+		return "ok"
+	})
+}
+
+// describeIAMServiceAccountRoles adds the policies of the roles and the problems that prevent
+// pods from assuming them to the status of the iamserviceaccounts
+func describeIAMServiceAccountRoles(ctl *eks.ClusterProvider, cfg *api.ClusterConfig) error {
+	if len(cfg.IAM.ServiceAccounts) == 0 {
+		return nil
+	}
+
+	clientSet, err := ctl.NewStdClientSet(cfg)
+	if err != nil {
+		return err
+	}
+
+	oidc, err := ctl.NewOpenIDConnectManager(cfg)
+	if err != nil {
+		return err
+	}
+	providerExists, err := oidc.CheckProviderExists()
+	if err != nil {
+		return err
+	}
+	if !providerExists {
+		oidc = nil
+	}
+
+	for _, sa := range cfg.IAM.ServiceAccounts {
+		if err := iam.DescribeServiceAccountRole(ctl.Provider.IAM(), oidc, clientSet, sa); err != nil {
+			logger.Warning("unable to check the role of iamserviceaccount %q: %s", sa.NameString(), err.Error())
+		}
+	}
+	return nil
 }
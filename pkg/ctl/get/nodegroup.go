@@ -1,6 +1,7 @@
 package get
 
 import (
+	"fmt"
 	"os"
 	"strconv"
 	"time"
@@ -12,6 +13,7 @@ import (
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
 	"github.com/weaveworks/eksctl/pkg/printers"
 )
 
@@ -30,9 +32,10 @@ func getNodeGroupCmd(cmd *cmdutils.Cmd) {
 	}
 
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
-		fs.StringVar(&cfg.Metadata.Name, "cluster", "", "EKS cluster name")
+		fs.StringVar(&cfg.Metadata.Name, "cluster", "", "EKS cluster name (all clusters when unspecified with --all-regions)")
 		fs.StringVarP(&ng.Name, "name", "n", "", "Name of the nodegroup")
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		addAllRegionsFlags(fs, params, "List nodegroups across all supported regions")
 		cmdutils.AddCommonFlagsForGetCmd(fs, &params.chunkSize, &params.output)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
 	})
@@ -44,9 +47,12 @@ func doGetNodeGroup(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *getCmdParams)
 	cfg := cmd.ClusterConfig
 
 	// TODO: move this into a loader when --config-file gets added to this command
-	if cfg.Metadata.Name == "" {
+	if cfg.Metadata.Name == "" && !params.allRegions {
 		return cmdutils.ErrMustBeSet(cmdutils.ClusterNameFlag(cmd))
 	}
+	if len(params.regions) > 0 && !params.allRegions {
+		return fmt.Errorf("--regions can only be used with --all-regions")
+	}
 
 	if ng.Name != "" && cmd.NameArg != "" {
 		return cmdutils.ErrFlagAndArg("--name", ng.Name, cmd.NameArg)
@@ -70,10 +76,18 @@ func doGetNodeGroup(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *getCmdParams)
 		return err
 	}
 
-	manager := ctl.NewStackManager(cfg)
-	summaries, err := manager.GetNodeGroupSummaries(ng.Name)
-	if err != nil {
-		return errors.Wrap(err, "getting nodegroup stack summaries")
+	var summaries []*manager.NodeGroupSummary
+	if params.allRegions {
+		regions, err := eks.Regions(params.regions)
+		if err != nil {
+			return err
+		}
+		summaries = ctl.GetNodeGroupSummariesInRegions(cfg.Metadata.Name, ng.Name, params.chunkSize, regions)
+	} else {
+		summaries, err = ctl.NewStackManager(cfg).GetNodeGroupSummaries(ng.Name)
+		if err != nil {
+			return errors.Wrap(err, "getting nodegroup stack summaries")
+		}
 	}
 
 	printer, err := printers.NewPrinter(params.output)
@@ -82,7 +96,7 @@ func doGetNodeGroup(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *getCmdParams)
 	}
 
 	if params.output == "table" {
-		addSummaryTableColumns(printer.(*printers.TablePrinter))
+		addSummaryTableColumns(printer.(*printers.TablePrinter), params.allRegions)
 	}
 
 	if err := printer.PrintObjWithKind("nodegroups", summaries, os.Stdout); err != nil {
@@ -92,10 +106,15 @@ func doGetNodeGroup(cmd *cmdutils.Cmd, ng *api.NodeGroup, params *getCmdParams)
 	return nil
 }
 
-func addSummaryTableColumns(printer *printers.TablePrinter) {
+func addSummaryTableColumns(printer *printers.TablePrinter, withRegion bool) {
 	printer.AddColumn("CLUSTER", func(s *manager.NodeGroupSummary) string {
 		return s.Cluster
 	})
+	if withRegion {
+		printer.AddColumn("REGION", func(s *manager.NodeGroupSummary) string {
+			return s.Region
+		})
+	}
 	printer.AddColumn("NODEGROUP", func(s *manager.NodeGroupSummary) string {
 		return s.Name
 	})
@@ -0,0 +1,104 @@
+package get
+
+import (
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/printers"
+)
+
+func getResourcesCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var output printers.Type
+
+	cmd.SetDescription("resources", "Get the resources eksctl owns in a cluster",
+		"Get the CloudFormation stacks of a cluster along with their resources, the roles of its iamserviceaccounts, its EKS add-ons and its IAM OIDC provider, with their tags, creation times and the result of the last drift detection of the stacks")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		if cfg.Metadata.Name == "" {
+			return cmdutils.ErrMustBeSet(cmdutils.ClusterNameFlag(cmd))
+		}
+		if cmd.NameArg != "" {
+			return cmdutils.ErrUnsupportedNameArg()
+		}
+		return doGetResources(cmd, output)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		fs.StringVarP(&output, "output", "o", "table", "specifies the output format (valid option: table, json, yaml)")
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doGetResources(cmd *cmdutils.Cmd, output printers.Type) error {
+	cfg := cmd.ClusterConfig
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	printer, err := printers.NewPrinter(output)
+	if err != nil {
+		return err
+	}
+
+	resources, err := ctl.ListOwnedResources(cfg)
+	if err != nil {
+		return err
+	}
+	if output == printers.TableType {
+		addOwnedResourceTableColumns(printer.(*printers.TablePrinter))
+	}
+	return printer.PrintObjWithKind("resources", resources, os.Stdout)
+}
+
+func addOwnedResourceTableColumns(printer *printers.TablePrinter) {
+	orDash := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+	printer.AddColumn("KIND", func(r *manager.OwnedResource) string {
+		return r.Kind
+	})
+	printer.AddColumn("NAME", func(r *manager.OwnedResource) string {
+		return r.Name
+	})
+	printer.AddColumn("TYPE", func(r *manager.OwnedResource) string {
+		return r.Type
+	})
+	printer.AddColumn("STACK", func(r *manager.OwnedResource) string {
+		return orDash(r.Stack)
+	})
+	printer.AddColumn("STATUS", func(r *manager.OwnedResource) string {
+		return orDash(r.Status)
+	})
+	printer.AddColumn("DRIFT", func(r *manager.OwnedResource) string {
+		return orDash(r.DriftStatus)
+	})
+	printer.AddColumn("CREATED", func(r *manager.OwnedResource) string {
+		if r.CreatedAt == nil {
+			return "-"
+		}
+		return r.CreatedAt.Format(time.RFC3339)
+	})
+}
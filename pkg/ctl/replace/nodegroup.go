@@ -0,0 +1,166 @@
+package replace
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/kubernetes"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/authconfigmap"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/ctl/create"
+	"github.com/weaveworks/eksctl/pkg/drain"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+func replaceNodeGroupCmd(cmd *cmdutils.Cmd) {
+	replaceNodeGroupWithRunFunc(cmd, func(cmd *cmdutils.Cmd, oldNodeGroupName string) error {
+		return doReplaceNodeGroup(cmd, oldNodeGroupName)
+	})
+}
+
+func replaceNodeGroupWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd, oldNodeGroupName string) error) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var oldNodeGroupName string
+
+	cmd.SetDescription("nodegroup", "Replace a nodegroup with a new one",
+		"Create the nodegroup defined in the config file, wait for its nodes to join the cluster, then drain and delete the nodegroup it replaces, e.g. to migrate to another instance type or AMI family", "ng")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		if nameArg := cmdutils.GetNameArg(args); nameArg != "" {
+			if oldNodeGroupName != "" {
+				return cmdutils.ErrFlagAndArg("--name", oldNodeGroupName, nameArg)
+			}
+			oldNodeGroupName = nameArg
+		}
+		return runFunc(cmd, oldNodeGroupName)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		fs.StringVar(&cfg.Metadata.Name, "cluster", "", "EKS cluster name")
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		fs.StringVarP(&oldNodeGroupName, "name", "n", "", "Name of the nodegroup to replace")
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		cmdutils.AddNodeGroupFilterFlags(fs, &cmd.Include, &cmd.Exclude)
+		cmdutils.AddApproveFlag(fs, cmd)
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, true)
+}
+
+func doReplaceNodeGroup(cmd *cmdutils.Cmd, oldNodeGroupName string) error {
+	ngFilter := cmdutils.NewNodeGroupFilter()
+
+	if err := cmdutils.NewReplaceNodeGroupLoader(cmd, oldNodeGroupName, ngFilter).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	cmdutils.ApplyFilter(cfg, ngFilter)()
+
+	newNodeGroups := cmdutils.ToKubeNodeGroups(cfg)
+	if len(newNodeGroups) != 1 {
+		return fmt.Errorf("the config file must define exactly one nodegroup to replace nodegroup %q with, use --include to select it (found %d)", oldNodeGroupName, len(newNodeGroups))
+	}
+	newNodeGroupName := newNodeGroups[0].NameString()
+	if newNodeGroupName == oldNodeGroupName {
+		return fmt.Errorf("the new nodegroup must have a different name than nodegroup %q", oldNodeGroupName)
+	}
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(cfg.Metadata)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	if ok, err := ctl.CanOperate(cfg); !ok {
+		return err
+	}
+
+	stackManager := ctl.NewStackManager(cfg)
+	oldNodeGroupType, err := stackManager.GetNodeGroupStackType(oldNodeGroupName)
+	if err != nil {
+		return errors.Wrapf(err, "finding nodegroup %q", oldNodeGroupName)
+	}
+	newNodeGroupExists := false
+	if _, err := stackManager.GetNodeGroupStackType(newNodeGroupName); err == nil {
+		newNodeGroupExists = true
+	}
+
+	cmdutils.LogIntendedAction(cmd.Plan, "replace nodegroup %q with nodegroup %q in cluster %q", oldNodeGroupName, newNodeGroupName, cfg.Metadata.Name)
+	if cmd.Plan {
+		cmdutils.LogPlanModeWarning(true)
+		return nil
+	}
+
+	if newNodeGroupExists {
+		// e.g. when a previous replacement failed to drain the old nodegroup
+		logger.Info("nodegroup %q exists already, continuing with draining nodegroup %q", newNodeGroupName, oldNodeGroupName)
+	} else if err := create.NodeGroups(cmd, ctl, cmdutils.NewNodeGroupFilter()); err != nil {
+		return err
+	}
+
+	clientSet, err := ctl.NewStdClientSet(cfg)
+	if err != nil {
+		return err
+	}
+
+	oldNodeGroup := api.NewNodeGroup()
+	oldNodeGroup.Name = oldNodeGroupName
+	if err := drain.NodeGroup(clientSet, oldNodeGroup, ctl.Provider.WaitTimeout(), false); err != nil {
+		return errors.Wrapf(err, "draining nodegroup %q, nodegroup %q was kept and can be drained and deleted once the error is resolved", oldNodeGroupName, newNodeGroupName)
+	}
+
+	if oldNodeGroupType == api.NodeGroupTypeUnmanaged {
+		removeFromAuthConfigMap(ctl, clientSet, cfg, oldNodeGroup)
+	}
+
+	tasks, err := stackManager.NewTasksToDeleteNodeGroups(func(name string) bool { return name == oldNodeGroupName }, true, nil)
+	if err != nil {
+		return err
+	}
+	logger.Info(tasks.Describe())
+	if errs := tasks.DoAllSync(); len(errs) > 0 {
+		for _, err := range errs {
+			logger.Critical("%s\n", err.Error())
+		}
+		return fmt.Errorf("failed to delete nodegroup %q", oldNodeGroupName)
+	}
+
+	logger.Success("replaced nodegroup %q with nodegroup %q in cluster %q", oldNodeGroupName, newNodeGroupName, cfg.Metadata.Name)
+	return nil
+}
+
+// removeFromAuthConfigMap removes the role of the replaced nodegroup from the aws-auth ConfigMap,
+// unless the new nodegroup uses the same role
+func removeFromAuthConfigMap(ctl *eks.ClusterProvider, clientSet kubernetes.Interface, cfg *api.ClusterConfig, oldNodeGroup *api.NodeGroup) {
+	if err := ctl.GetNodeGroupIAM(ctl.NewStackManager(cfg), cfg, oldNodeGroup); err != nil {
+		logger.Warning("error getting instance role ARN for nodegroup %q: %v", oldNodeGroup.Name, err)
+		return
+	}
+	for _, ng := range cfg.NodeGroups {
+		if ng.IAM != nil && ng.IAM.InstanceRoleARN == oldNodeGroup.IAM.InstanceRoleARN {
+			return
+		}
+	}
+	for _, ng := range cfg.ManagedNodeGroups {
+		if ng.IAM != nil && ng.IAM.InstanceRoleARN == oldNodeGroup.IAM.InstanceRoleARN {
+			return
+		}
+	}
+
+	if err := authconfigmap.RemoveNodeGroup(clientSet, oldNodeGroup); err != nil {
+		logger.Warning(err.Error())
+	}
+}
@@ -0,0 +1,87 @@
+package replace
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+)
+
+const newNodeGroupConfig = `apiVersion: eksctl.io/v1alpha5
+kind: ClusterConfig
+metadata:
+  name: cluster-1
+  region: us-west-2
+nodeGroups:
+  - name: ng-2
+    instanceType: m6g.large
+`
+
+var _ = Describe("replace nodegroup", func() {
+	var configFile string
+
+	BeforeEach(func() {
+		file, err := ioutil.TempFile("", "replace-nodegroup-*.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = file.WriteString(newNodeGroupConfig)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(file.Close()).To(Succeed())
+		configFile = file.Name()
+	})
+
+	AfterEach(func() {
+		os.Remove(configFile)
+	})
+
+	execute := func(args ...string) error {
+		cmd := Command(cmdutils.NewGrouping())
+		cmd.SetArgs(args)
+		cmd.SetOut(new(bytes.Buffer))
+		return cmd.Execute()
+	}
+
+	It("accepts the name of the old nodegroup as flag or argument", func() {
+		var oldNodeGroupNames []string
+		for _, args := range [][]string{{"nodegroup", "--name", "ng-1"}, {"nodegroup", "ng-1"}} {
+			verbCmd := cmdutils.NewVerbCmd("replace", "", "")
+			cmdutils.AddResourceCmd(cmdutils.NewGrouping(), verbCmd, func(cmd *cmdutils.Cmd) {
+				replaceNodeGroupWithRunFunc(cmd, func(_ *cmdutils.Cmd, oldNodeGroupName string) error {
+					oldNodeGroupNames = append(oldNodeGroupNames, oldNodeGroupName)
+					return nil
+				})
+			})
+			verbCmd.SetArgs(append(args, "--config-file", configFile))
+			Expect(verbCmd.Execute()).To(Succeed())
+		}
+		Expect(oldNodeGroupNames).To(Equal([]string{"ng-1", "ng-1"}))
+	})
+
+	It("requires a config file", func() {
+		err := execute("nodegroup", "--cluster", "cluster-1", "--name", "ng-1")
+		Expect(err).To(MatchError("--config-file/-f with the new nodegroup must be set"))
+	})
+
+	It("requires the name of the old nodegroup", func() {
+		err := execute("nodegroup", "--config-file", configFile)
+		Expect(err).To(MatchError("--name must be set"))
+	})
+
+	It("rejects a cluster that doesn't match the config file", func() {
+		err := execute("nodegroup", "--cluster", "cluster-2", "--name", "ng-1", "--config-file", configFile)
+		Expect(err).To(MatchError("--cluster=cluster-2 doesn't match metadata.name of the config file (cluster-1)"))
+	})
+
+	It("rejects the same name for the old and the new nodegroup", func() {
+		err := execute("nodegroup", "--name", "ng-2", "--config-file", configFile)
+		Expect(err).To(MatchError(`the new nodegroup must have a different name than nodegroup "ng-2"`))
+	})
+
+	It("rejects setting --name and an argument", func() {
+		err := execute("nodegroup", "ng-1", "--name", "ng-1", "--config-file", configFile)
+		Expect(err).To(MatchError("--name=ng-1 and argument ng-1 cannot be used at the same time"))
+	})
+})
@@ -0,0 +1,72 @@
+package rollback
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/managed"
+)
+
+type rollbackOptions struct {
+	managed.RollbackOptions
+	nodeGroupName string
+	approve       bool
+}
+
+func rollbackNodeGroupWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd, options *rollbackOptions) error) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	cmd.SetDescription("nodegroup", "Roll back a managed nodegroup",
+		"Roll back a managed nodegroup to an earlier AMI release version, or version of its launch template; EKS replaces the nodes one at a time, draining each of them first")
+
+	var options rollbackOptions
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		if err := cmdutils.NewRollbackNodeGroupLoader(cmd, &options.nodeGroupName).Load(); err != nil {
+			return err
+		}
+		if (options.ReleaseVersion == "") == (options.LaunchTemplateVersion == "") {
+			return errors.New("exactly one of --to-release-version and --to-launch-template-version must be set")
+		}
+		return runFunc(cmd, &options)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		fs.StringVar(&options.nodeGroupName, "name", "", "Nodegroup name")
+		fs.StringVar(&options.ReleaseVersion, "to-release-version", "", "AMI release version to roll back to, e.g. 1.14.8-20191213")
+		fs.StringVar(&options.LaunchTemplateVersion, "to-launch-template-version", "", "version of the launch template of the nodegroup to roll back to")
+		fs.BoolVar(&options.ForceUpdate, "force", false, "replace the nodes even if their pods can't be evicted because of a PodDisruptionBudget")
+		fs.BoolVar(&options.approve, "approve", false, "apply the changes to the nodegroup stack without asking for confirmation")
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func rollbackNodeGroupCmd(cmd *cmdutils.Cmd) {
+	rollbackNodeGroupWithRunFunc(cmd, doRollbackNodeGroup)
+}
+
+func doRollbackNodeGroup(cmd *cmdutils.Cmd, options *rollbackOptions) error {
+	cfg := cmd.ClusterConfig
+
+	ctl := eks.New(cmd.ProviderConfig, cfg)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	stackCollection := manager.NewStackCollection(ctl.Provider, cfg)
+	stackCollection.SetChangeSetReviewer(cmdutils.ConfirmChangeSet(options.approve))
+	managedService := managed.NewService(ctl.Provider, stackCollection, cfg.Metadata.Name)
+	return managedService.RollbackNodeGroup(options.nodeGroupName, options.RollbackOptions)
+}
@@ -0,0 +1,60 @@
+package rollback
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+)
+
+var _ = Describe("rollback nodegroup", func() {
+	run := func(args ...string) (*rollbackOptions, error) {
+		var options *rollbackOptions
+		grouping := cmdutils.NewGrouping()
+		parentCmd := cmdutils.NewVerbCmd("rollback", "", "")
+		cmdutils.AddResourceCmd(grouping, parentCmd, func(cmd *cmdutils.Cmd) {
+			rollbackNodeGroupWithRunFunc(cmd, func(_ *cmdutils.Cmd, o *rollbackOptions) error {
+				options = o
+				return nil
+			})
+		})
+		parentCmd.SetArgs(append([]string{"nodegroup"}, args...))
+		parentCmd.SetOut(new(bytes.Buffer))
+		err := parentCmd.Execute()
+		return options, err
+	}
+
+	It("requires the cluster and nodegroup names", func() {
+		_, err := run("--to-release-version", "1.14.8-20191213")
+		Expect(err).To(MatchError("--cluster must be set"))
+
+		_, err = run("--cluster", "foo", "--to-release-version", "1.14.8-20191213")
+		Expect(err).To(MatchError("--name must be set"))
+	})
+
+	It("requires exactly one version to roll back to", func() {
+		_, err := run("--cluster", "foo", "ng-1")
+		Expect(err).To(MatchError("exactly one of --to-release-version and --to-launch-template-version must be set"))
+
+		_, err = run("--cluster", "foo", "ng-1", "--to-release-version", "1.14.8-20191213", "--to-launch-template-version", "2")
+		Expect(err).To(MatchError("exactly one of --to-release-version and --to-launch-template-version must be set"))
+	})
+
+	It("rolls back to a release version", func() {
+		options, err := run("--cluster", "foo", "--name", "ng-1", "--to-release-version", "1.14.8-20191213", "--force")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options.nodeGroupName).To(Equal("ng-1"))
+		Expect(options.ReleaseVersion).To(Equal("1.14.8-20191213"))
+		Expect(options.ForceUpdate).To(BeTrue())
+	})
+
+	It("rolls back to a launch template version", func() {
+		options, err := run("--cluster", "foo", "ng-1", "--to-launch-template-version", "2")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options.nodeGroupName).To(Equal("ng-1"))
+		Expect(options.LaunchTemplateVersion).To(Equal("2"))
+		Expect(options.ForceUpdate).To(BeFalse())
+	})
+})
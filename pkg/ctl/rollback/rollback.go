@@ -0,0 +1,16 @@
+package rollback
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+)
+
+// Command will create the `rollback` commands
+func Command(flagGrouping *cmdutils.FlagGrouping) *cobra.Command {
+	verbCmd := cmdutils.NewVerbCmd("rollback", "Roll back resource(s)", "")
+
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, rollbackNodeGroupCmd)
+
+	return verbCmd
+}
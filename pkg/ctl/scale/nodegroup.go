@@ -7,7 +7,9 @@ import (
 	"github.com/spf13/pflag"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cost"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 func scaleNodeGroupCmd(cmd *cmdutils.Cmd) {
@@ -15,16 +17,20 @@ func scaleNodeGroupCmd(cmd *cmdutils.Cmd) {
 	ng := cfg.NewNodeGroup()
 	cmd.ClusterConfig = cfg
 
+	var overrideBudget bool
+
 	cmd.SetDescription("nodegroup", "Scale a nodegroup", "", "ng")
 
 	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
 		cmd.NameArg = cmdutils.GetNameArg(args)
-		return doScaleNodeGroup(cmd, ng)
+		return doScaleNodeGroup(cmd, ng, overrideBudget)
 	}
 
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
 		fs.StringVar(&cfg.Metadata.Name, "cluster", "", "EKS cluster name")
 		fs.StringVarP(&ng.Name, "name", "n", "", "Name of the nodegroup to scale")
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		cmdutils.AddNodeGroupFilterFlags(fs, &cmd.Include, &cmd.Exclude)
 
 		desiredCapacity := fs.IntP("nodes", "N", -1, "total number of nodes (scale to this number)")
 		cmdutils.AddPreRun(cmd.CobraCommand, func(cobraCmd *cobra.Command, args []string) {
@@ -35,30 +41,20 @@ func scaleNodeGroupCmd(cmd *cmdutils.Cmd) {
 
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+		cmdutils.AddOverrideBudgetFlag(fs, &overrideBudget)
 	})
 
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, true)
 }
 
-func doScaleNodeGroup(cmd *cmdutils.Cmd, ng *api.NodeGroup) error {
-	cfg := cmd.ClusterConfig
+func doScaleNodeGroup(cmd *cmdutils.Cmd, ng *api.NodeGroup, overrideBudget bool) error {
+	ngFilter := cmdutils.NewNodeGroupFilter()
 
-	// TODO: move this into a loader when --config-file gets added to this command
-	if cfg.Metadata.Name == "" {
-		return cmdutils.ErrMustBeSet(cmdutils.ClusterNameFlag(cmd))
-	}
-
-	if ng.Name != "" && cmd.NameArg != "" {
-		return cmdutils.ErrFlagAndArg("--name", ng.Name, cmd.NameArg)
-	}
-
-	if cmd.NameArg != "" {
-		ng.Name = cmd.NameArg
+	if err := cmdutils.NewScaleNodeGroupLoader(cmd, ng, ngFilter).Load(); err != nil {
+		return err
 	}
 
-	if ng.Name == "" {
-		return cmdutils.ErrMustBeSet("--name")
-	}
+	cfg := cmd.ClusterConfig
 
 	ctl, err := cmd.NewCtl()
 	if err != nil {
@@ -69,14 +65,52 @@ func doScaleNodeGroup(cmd *cmdutils.Cmd, ng *api.NodeGroup) error {
 		return err
 	}
 
-	if ng.DesiredCapacity == nil || *ng.DesiredCapacity < 0 {
-		return fmt.Errorf("number of nodes must be 0 or greater. Use the --nodes/-N flag")
+	logFiltered := cmdutils.ApplyFilter(cfg, ngFilter)
+	if cmd.ClusterConfigFile != "" {
+		logFiltered()
+	}
+
+	// with a config file, the desired capacity of each nodegroup is taken from it
+	var nodeGroups []*api.NodeGroup
+	for _, ng := range cfg.NodeGroups {
+		nodeGroups = append(nodeGroups, ng)
+	}
+	for _, ng := range cfg.ManagedNodeGroups {
+		nodeGroups = append(nodeGroups, &api.NodeGroup{
+			Name:            ng.Name,
+			DesiredCapacity: ng.DesiredCapacity,
+		})
+	}
+
+	if cfg.HasBudget() {
+		// scaling doesn't change the instance types, which are taken from the existing nodegroups
+		var scaled []cost.NodeGroup
+		for _, ng := range nodeGroups {
+			if ng.DesiredCapacity != nil {
+				scaled = append(scaled, cost.NodeGroup{Name: ng.Name, DesiredCapacity: *ng.DesiredCapacity})
+			}
+		}
+		pricer, err := ctl.NewPricer()
+		if err != nil {
+			return err
+		}
+		if err := ctl.CheckNodeGroupsBudget(pricer, cfg, scaled, overrideBudget); err != nil {
+			return err
+		}
 	}
 
 	stackManager := ctl.NewStackManager(cfg)
-	err = stackManager.ScaleNodeGroup(ng)
-	if err != nil {
-		return fmt.Errorf("failed to scale nodegroup for cluster %q, error %v", cfg.Metadata.Name, err)
+	for _, ng := range nodeGroups {
+		if ng.DesiredCapacity == nil {
+			logger.Info("skipping nodegroup %q as it doesn't set desiredCapacity", ng.Name)
+			continue
+		}
+		if *ng.DesiredCapacity < 0 {
+			return fmt.Errorf("desiredCapacity of nodegroup %q must be 0 or greater", ng.Name)
+		}
+		if err := stackManager.ScaleNodeGroup(ng); err != nil {
+			return fmt.Errorf("failed to scale nodegroup %q for cluster %q, error %v", ng.Name, cfg.Metadata.Name, err)
+		}
 	}
 
 	return nil
@@ -0,0 +1,155 @@
+package top
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/printers"
+	"github.com/weaveworks/eksctl/pkg/utilization"
+)
+
+type topOptions struct {
+	nodeGroupName string
+	output        string
+}
+
+func topNodeGroupWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd, options *topOptions) error) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	cmd.SetDescription("nodegroup", "Display the resource utilization of nodegroups",
+		"Display the CPU and memory requests, limits and usage of the pods of each nodegroup against what its nodes can allocate, along with the size of the nodegroup, and suggest smaller or larger instance types for under- and over-utilized nodegroups; the usage is reported when metrics-server is installed", "ng")
+
+	var options topOptions
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		// the name argument is the name of the nodegroup, the cluster is set with --cluster
+		if nameArg := cmdutils.GetNameArg(args); nameArg != "" {
+			if options.nodeGroupName != "" {
+				return cmdutils.ErrFlagAndArg("--name", options.nodeGroupName, nameArg)
+			}
+			options.nodeGroupName = nameArg
+		}
+		if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
+			return err
+		}
+		return runFunc(cmd, &options)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		fs.StringVarP(&options.nodeGroupName, "name", "n", "", "Name of the nodegroup, all nodegroups are displayed when it isn't set")
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		fs.StringVarP(&options.output, "output", "o", "table", "specifies the output format (valid option: table, json, yaml)")
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func topNodeGroupCmd(cmd *cmdutils.Cmd) {
+	topNodeGroupWithRunFunc(cmd, doTopNodeGroup)
+}
+
+func doTopNodeGroup(cmd *cmdutils.Cmd, options *topOptions) error {
+	cfg := cmd.ClusterConfig
+
+	printer, err := printers.NewPrinter(options.output)
+	if err != nil {
+		return err
+	}
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(cfg.Metadata)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	if ok, err := ctl.CanOperate(cfg); !ok {
+		return err
+	}
+
+	clientSet, err := ctl.NewStdClientSet(cfg)
+	if err != nil {
+		return err
+	}
+
+	inputs, err := ctl.GetUtilizationInputs(cfg, clientSet, options.nodeGroupName)
+	if err != nil {
+		return err
+	}
+	report := utilization.Compute(inputs)
+
+	if options.output != printers.TableType {
+		return printer.PrintObjWithKind("report", report, os.Stdout)
+	}
+	addTopNodeGroupTableColumns(printer.(*printers.TablePrinter), report.UsageAvailable)
+	return printer.PrintObjWithKind("nodegroups", report.NodeGroups, os.Stdout)
+}
+
+func addTopNodeGroupTableColumns(printer *printers.TablePrinter, usageAvailable bool) {
+	printer.AddColumn("NODEGROUP", func(ng *utilization.NodeGroupUtilization) string {
+		return ng.Name
+	})
+	printer.AddColumn("INSTANCE TYPE", func(ng *utilization.NodeGroupUtilization) string {
+		return ng.InstanceType
+	})
+	printer.AddColumn("NODES", func(ng *utilization.NodeGroupUtilization) string {
+		return strconv.Itoa(ng.Nodes)
+	})
+	printer.AddColumn("MIN/DESIRED/MAX", func(ng *utilization.NodeGroupUtilization) string {
+		return fmt.Sprintf("%d/%d/%d", ng.MinSize, ng.DesiredCapacity, ng.MaxSize)
+	})
+	printer.AddColumn("CPU REQUESTS", func(ng *utilization.NodeGroupUtilization) string {
+		return fmt.Sprintf("%s (%d%%)", formatCPU(ng.CPU.Requests), ng.CPU.RequestsPercent())
+	})
+	printer.AddColumn("CPU LIMITS", func(ng *utilization.NodeGroupUtilization) string {
+		return formatCPU(ng.CPU.Limits)
+	})
+	if usageAvailable {
+		printer.AddColumn("CPU USAGE", func(ng *utilization.NodeGroupUtilization) string {
+			return fmt.Sprintf("%s (%d%%)", formatCPU(*ng.CPU.Usage), ng.CPU.UsagePercent())
+		})
+	}
+	printer.AddColumn("CPU HEADROOM", func(ng *utilization.NodeGroupUtilization) string {
+		return formatCPU(ng.CPU.Headroom)
+	})
+	printer.AddColumn("MEMORY REQUESTS", func(ng *utilization.NodeGroupUtilization) string {
+		return fmt.Sprintf("%s (%d%%)", formatMemory(ng.Memory.Requests), ng.Memory.RequestsPercent())
+	})
+	printer.AddColumn("MEMORY LIMITS", func(ng *utilization.NodeGroupUtilization) string {
+		return formatMemory(ng.Memory.Limits)
+	})
+	if usageAvailable {
+		printer.AddColumn("MEMORY USAGE", func(ng *utilization.NodeGroupUtilization) string {
+			return fmt.Sprintf("%s (%d%%)", formatMemory(*ng.Memory.Usage), ng.Memory.UsagePercent())
+		})
+	}
+	printer.AddColumn("MEMORY HEADROOM", func(ng *utilization.NodeGroupUtilization) string {
+		return formatMemory(ng.Memory.Headroom)
+	})
+	printer.AddColumn("SUGGESTION", func(ng *utilization.NodeGroupUtilization) string {
+		if ng.Suggestion == "" {
+			return "-"
+		}
+		return ng.Suggestion
+	})
+}
+
+func formatCPU(millicores int64) string {
+	return resource.NewMilliQuantity(millicores, resource.DecimalSI).String()
+}
+
+func formatMemory(bytes int64) string {
+	return fmt.Sprintf("%.1fGi", float64(bytes)/(1<<30))
+}
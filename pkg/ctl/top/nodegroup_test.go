@@ -0,0 +1,54 @@
+package top
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+)
+
+var _ = Describe("top nodegroup", func() {
+	run := func(args ...string) (*topOptions, error) {
+		var options *topOptions
+		grouping := cmdutils.NewGrouping()
+		parentCmd := cmdutils.NewVerbCmd("top", "", "")
+		cmdutils.AddResourceCmd(grouping, parentCmd, func(cmd *cmdutils.Cmd) {
+			topNodeGroupWithRunFunc(cmd, func(_ *cmdutils.Cmd, o *topOptions) error {
+				options = o
+				return nil
+			})
+		})
+		parentCmd.SetArgs(append([]string{"nodegroup"}, args...))
+		parentCmd.SetOut(new(bytes.Buffer))
+		err := parentCmd.Execute()
+		return options, err
+	}
+
+	It("requires the cluster name", func() {
+		_, err := run("ng-1")
+		Expect(err).To(MatchError("--cluster must be set"))
+	})
+
+	It("displays all nodegroups by default", func() {
+		options, err := run("--cluster", "foo")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options.nodeGroupName).To(BeEmpty())
+		Expect(options.output).To(Equal("table"))
+	})
+
+	It("takes the nodegroup name from the flag or the argument", func() {
+		options, err := run("--cluster", "foo", "--name", "ng-1", "-o", "json")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options.nodeGroupName).To(Equal("ng-1"))
+		Expect(options.output).To(Equal("json"))
+
+		options, err = run("--cluster", "foo", "ng-2")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options.nodeGroupName).To(Equal("ng-2"))
+
+		_, err = run("--cluster", "foo", "--name", "ng-1", "ng-2")
+		Expect(err).To(MatchError(ContainSubstring("--name=ng-1 and argument ng-2")))
+	})
+})
@@ -0,0 +1,16 @@
+package top
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+)
+
+// Command will create the `top` commands
+func Command(flagGrouping *cmdutils.FlagGrouping) *cobra.Command {
+	verbCmd := cmdutils.NewVerbCmd("top", "Display the resource utilization of resource(s)", "")
+
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, topNodeGroupCmd)
+
+	return verbCmd
+}
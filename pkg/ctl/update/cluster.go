@@ -2,14 +2,19 @@ package update
 
 import (
 	"fmt"
+	"os"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
+	defaultaddons "github.com/weaveworks/eksctl/pkg/addons/default"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/notifications"
+	"github.com/weaveworks/eksctl/pkg/preflight"
 	"github.com/weaveworks/eksctl/pkg/printers"
 )
 
@@ -20,18 +25,24 @@ func updateClusterCmd(cmd *cmdutils.Cmd) {
 	cmd.SetDescription("cluster", "Upgrade control plane to the next version",
 		"Upgrade control plane to the next Kubernetes version if available. Will also perform any updates needed in the cluster stack if resources are missing.")
 
+	var updateCoreComponents, skipPreflightChecks, installEBSCSIDriver bool
+
 	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
 		cmd.NameArg = cmdutils.GetNameArg(args)
-		return doUpdateClusterCmd(cmd)
+		return doUpdateClusterCmd(cmd, updateCoreComponents, skipPreflightChecks, installEBSCSIDriver)
 	}
 
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
 		fs.StringVarP(&cfg.Metadata.Name, "name", "n", "", "EKS cluster name")
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddConfigFileFlag(fs, cmd)
 
 		// cmdutils.AddVersionFlag(fs, cfg.Metadata, `"next" and "latest" can be used to automatically increment version by one, or force latest`)
 
+		fs.BoolVar(&updateCoreComponents, "update-core-components", false, "update kube-proxy, aws-node and coredns to match the control plane version, whether they are EKS add-ons or self-managed")
+		fs.BoolVar(&skipPreflightChecks, "skip-preflight-checks", false, "upgrade the control plane even when the checks of add-ons, deprecated APIs, nodegroup versions and pod disruption budgets fail")
+		fs.BoolVar(&installEBSCSIDriver, "install-ebs-csi-driver", false, "install the aws-ebs-csi-driver EKS add-on before upgrading when PersistentVolumes use the in-tree EBS plugin and the driver isn't installed")
+
 		cmdutils.AddApproveFlag(fs, cmd)
 		fs.BoolVar(&cmd.Plan, "dry-run", cmd.Plan, "")
 		_ = fs.MarkDeprecated("dry-run", "see --approve")
@@ -45,7 +56,7 @@ func updateClusterCmd(cmd *cmdutils.Cmd) {
 
 }
 
-func doUpdateClusterCmd(cmd *cmdutils.Cmd) error {
+func doUpdateClusterCmd(cmd *cmdutils.Cmd, updateCoreComponents, skipPreflightChecks, installEBSCSIDriver bool) error {
 	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
 		return err
 	}
@@ -105,13 +116,27 @@ func doUpdateClusterCmd(cmd *cmdutils.Cmd) error {
 
 	stackManager := ctl.NewStackManager(cfg)
 
+	if versionUpdateRequired && !skipPreflightChecks {
+		if err := runPreflightChecks(ctl, cfg, currentVersion, installEBSCSIDriver, cmd.Plan); err != nil {
+			return err
+		}
+	}
+
 	if versionUpdateRequired {
 		msgNodeGroupsAndAddons := "you will need to follow the upgrade procedure for all of nodegroups and add-ons"
 		cmdutils.LogIntendedAction(cmd.Plan, "upgrade cluster %q control plane from current version %q to %q", cfg.Metadata.Name, currentVersion, cfg.Metadata.Version)
 		if !cmd.Plan {
+			notifier := notifications.NewNotifier(cfg, ctl.Provider)
+			versions := map[string]interface{}{
+				"fromVersion": currentVersion,
+				"toVersion":   cfg.Metadata.Version,
+			}
+			notifier.Notify(notifications.ClusterUpgradeStarted, "control plane upgrade started", versions)
 			if err := ctl.UpdateClusterVersionBlocking(cfg); err != nil {
+				notifier.Notify(notifications.ClusterUpgradeFailed, err.Error(), versions)
 				return err
 			}
+			notifier.Notify(notifications.ClusterUpgradeFinished, "control plane upgrade finished", versions)
 			logger.Success("cluster %q control plane has been upgraded to version %q", cfg.Metadata.Name, cfg.Metadata.Version)
 			logger.Info(msgNodeGroupsAndAddons)
 		}
@@ -131,11 +156,86 @@ func doUpdateClusterCmd(cmd *cmdutils.Cmd) error {
 		return err
 	}
 
+	coreComponentsUpdateRequired := false
+	if updateCoreComponents {
+		if cmd.Plan && versionUpdateRequired {
+			logger.Info("(plan) kube-proxy, aws-node and coredns would be updated to match version %q after the control plane is upgraded", cfg.Metadata.Version)
+		} else if coreComponentsUpdateRequired, err = doUpdateCoreComponents(ctl, cfg, cmd.Plan); err != nil {
+			return err
+		}
+	}
+
 	if err := ctl.ValidateExistingNodeGroupsForCompatibility(cfg, stackManager); err != nil {
 		logger.Critical("failed checking nodegroups", err.Error())
 	}
 
-	cmdutils.LogPlanModeWarning(cmd.Plan && (stackUpdateRequired || versionUpdateRequired))
+	cmdutils.LogPlanModeWarning(cmd.Plan && (stackUpdateRequired || versionUpdateRequired || coreComponentsUpdateRequired))
+
+	return nil
+}
+
+// runPreflightChecks renders the go/no-go report of upgrading the control plane to the version
+// of cfg, and fails unless it's a go or in plan mode; the EBS CSI driver is installed first
+// when requested and volumes of the in-tree EBS plugin need it
+func runPreflightChecks(ctl *eks.ClusterProvider, cfg *api.ClusterConfig, currentVersion string, installEBSCSIDriver, plan bool) error {
+	logger.Info("checking whether cluster %q is ready to be upgraded to %q", cfg.Metadata.Name, cfg.Metadata.Version)
+	inputs, err := ctl.GetPreflightInputs(cfg, currentVersion, cfg.Metadata.Version)
+	if err != nil {
+		return errors.Wrap(err, "gathering the inputs of the pre-flight checks, use --skip-preflight-checks to upgrade without them")
+	}
+
+	if installEBSCSIDriver && inputs.NeedsEBSCSIDriver() {
+		cmdutils.LogIntendedAction(plan, "install the %s add-on for %d PersistentVolumes of the in-tree EBS plugin", api.EBSCSIDriverAddon, len(inputs.InTreeEBSVolumes))
+		if !plan {
+			if err := ctl.InstallEBSCSIDriver(cfg); err != nil {
+				return err
+			}
+			inputs.EBSCSIDriverInstalled = true
+		}
+	}
+	report := preflight.Check(inputs)
+
+	printer := printers.NewTablePrinter()
+	addPreflightTableColumns(printer.(*printers.TablePrinter))
+	if err := printer.PrintObjWithKind("checks", report.Results, os.Stdout); err != nil {
+		return err
+	}
 
+	switch {
+	case report.Go:
+		logger.Success("go: cluster %q passed the pre-flight checks for Kubernetes %q", cfg.Metadata.Name, cfg.Metadata.Version)
+	case plan:
+		logger.Critical("(plan) no-go: cluster %q failed the pre-flight checks for Kubernetes %q, the upgrade would not start", cfg.Metadata.Name, cfg.Metadata.Version)
+	default:
+		return fmt.Errorf("no-go: cluster %q failed the pre-flight checks for Kubernetes %q, address the failed checks or use --skip-preflight-checks", cfg.Metadata.Name, cfg.Metadata.Version)
+	}
 	return nil
 }
+
+func addPreflightTableColumns(printer *printers.TablePrinter) {
+	printer.AddColumn("CHECK", func(r preflight.Result) string {
+		return r.Name
+	})
+	printer.AddColumn("STATUS", func(r preflight.Result) string {
+		return r.Status
+	})
+	printer.AddColumn("MESSAGE", func(r preflight.Result) string {
+		return r.Message
+	})
+}
+
+func doUpdateCoreComponents(ctl *eks.ClusterProvider, cfg *api.ClusterConfig, plan bool) (bool, error) {
+	rawClient, err := ctl.NewRawClient(cfg)
+	if err != nil {
+		return false, err
+	}
+	kubernetesVersion, err := rawClient.ServerVersion()
+	if err != nil {
+		return false, err
+	}
+	addonsAPI, err := ctl.NewAddonsAPI()
+	if err != nil {
+		return false, err
+	}
+	return defaultaddons.UpdateCoreComponents(rawClient, addonsAPI, cfg.Metadata.Name, cfg.Metadata.Region, cfg.ContainerRegistryOverride, kubernetesVersion, plan)
+}
@@ -9,11 +9,13 @@ import (
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
 	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 type upgradeOptions struct {
 	nodeGroupName     string
 	kubernetesVersion string
+	approve           bool
 }
 
 func upgradeNodeGroupCmd(cmd *cmdutils.Cmd) {
@@ -33,7 +35,9 @@ func upgradeNodeGroupCmd(cmd *cmdutils.Cmd) {
 		fs.StringVarP(&options.nodeGroupName, "name", "", "", "Nodegroup name")
 		fs.StringVarP(&options.kubernetesVersion, "kubernetes-version", "", "", "Kubernetes version")
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		cmdutils.AddNodeGroupFilterFlags(fs, &cmd.Include, &cmd.Exclude)
+		fs.BoolVar(&options.approve, "approve", false, "apply the changes to the nodegroup stacks without asking for confirmation")
 
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
 	})
@@ -43,30 +47,35 @@ func upgradeNodeGroupCmd(cmd *cmdutils.Cmd) {
 }
 
 func upgradeNodeGroup(cmd *cmdutils.Cmd, options upgradeOptions) error {
-	cfg := cmd.ClusterConfig
-	if cfg.Metadata.Name == "" {
-		return cmdutils.ErrMustBeSet(cmdutils.ClusterNameFlag(cmd))
-	}
+	ngFilter := cmdutils.NewNodeGroupFilter()
 
-	if options.nodeGroupName != "" && cmd.NameArg != "" {
-		return cmdutils.ErrFlagAndArg("--name", options.nodeGroupName, cmd.NameArg)
-	}
-
-	if cmd.NameArg != "" {
-		options.nodeGroupName = cmd.NameArg
+	if err := cmdutils.NewUpgradeNodeGroupLoader(cmd, &options.nodeGroupName, ngFilter).Load(); err != nil {
+		return err
 	}
 
-	if options.nodeGroupName == "" {
-		return cmdutils.ErrMustBeSet("name")
-	}
+	cfg := cmd.ClusterConfig
 
-	ctl := eks.New(cmd.ProviderConfig, cmd.ClusterConfig)
+	ctl := eks.New(cmd.ProviderConfig, cfg)
 
 	if err := ctl.CheckAuth(); err != nil {
 		return err
 	}
 
+	logFiltered := cmdutils.ApplyFilter(cfg, ngFilter)
+	if cmd.ClusterConfigFile != "" {
+		logFiltered()
+		if len(cfg.NodeGroups) > 0 {
+			logger.Warning("only managed nodegroups can be upgraded, %d unmanaged nodegroup(s) will be skipped", len(cfg.NodeGroups))
+		}
+	}
+
 	stackCollection := manager.NewStackCollection(ctl.Provider, cfg)
+	stackCollection.SetChangeSetReviewer(cmdutils.ConfirmChangeSet(options.approve))
 	managedService := managed.NewService(ctl.Provider, stackCollection, cfg.Metadata.Name)
-	return managedService.UpgradeNodeGroup(options.nodeGroupName, options.kubernetesVersion)
+	for _, ng := range cfg.ManagedNodeGroups {
+		if err := managedService.UpgradeNodeGroup(ng.Name, options.kubernetesVersion); err != nil {
+			return err
+		}
+	}
+	return nil
 }
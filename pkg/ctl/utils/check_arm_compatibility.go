@@ -0,0 +1,187 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/arm"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/printers"
+)
+
+// gravitonNodeGroupSuffix is appended to the names of the nodegroups replacing compatible nodegroups
+const gravitonNodeGroupSuffix = "-arm64"
+
+func checkARMCompatibilityCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var output, generateConfig string
+
+	cmd.SetDescription("check-arm-compatibility", "Check which nodegroups can be migrated to Graviton instances",
+		"Inspect the images and the node selection of the workloads running on each nodegroup, and report which nodegroups can be replaced with nodegroups of Graviton (arm64) instances")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doCheckARMCompatibility(cmd, output, generateConfig)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		fs.StringVarP(&output, "output", "o", "table", "specifies the output format (valid option: table, json, yaml)")
+		fs.StringVar(&generateConfig, "generate-config", "", "write a config file with Graviton nodegroups replacing the compatible nodegroups to the given path")
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doCheckARMCompatibility(cmd *cmdutils.Cmd, output, generateConfig string) error {
+	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	printer, err := printers.NewPrinter(output)
+	if err != nil {
+		return err
+	}
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(meta)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	if ok, err := ctl.CanOperate(cfg); !ok {
+		return err
+	}
+
+	clientSet, err := ctl.NewStdClientSet(cfg)
+	if err != nil {
+		return err
+	}
+
+	// the nodegroups of the cluster are checked, rather than those of the config file
+	cfg.NodeGroups, cfg.ManagedNodeGroups = nil, nil
+	if err := ctl.AddExistingNodeGroups(cfg); err != nil {
+		return err
+	}
+
+	checker := arm.NewChecker(clientSet, arm.NewRegistryInspector(ctl.NewECRClient))
+	reports := []*arm.NodeGroupReport{}
+	compatible := map[string]string{}
+	check := func(ng arm.NodeGroup, instanceType string) error {
+		logger.Info("checking the workloads of nodegroup %q", ng.NameString())
+		report, err := checker.Check(ng, instanceType)
+		if err != nil {
+			return err
+		}
+		if report.Status == arm.StatusCompatible {
+			compatible[report.Name] = report.GravitonInstanceType
+		}
+		reports = append(reports, report)
+		return nil
+	}
+	for _, ng := range cfg.NodeGroups {
+		if err := check(ng, ng.InstanceType); err != nil {
+			return err
+		}
+	}
+	for _, ng := range cfg.ManagedNodeGroups {
+		if err := check(ng, ng.InstanceType); err != nil {
+			return err
+		}
+	}
+
+	if output == printers.TableType {
+		addARMCompatibilityTableColumns(printer.(*printers.TablePrinter))
+	}
+	if err := printer.PrintObjWithKind("nodegroups", reports, os.Stdout); err != nil {
+		return err
+	}
+	if output == printers.TableType {
+		for _, report := range reports {
+			if len(report.Blockers) > 0 {
+				logger.Warning("nodegroup %q cannot be migrated to Graviton instances:\n\t%s", report.Name, strings.Join(report.Blockers, "\n\t"))
+			}
+		}
+	}
+
+	if generateConfig == "" {
+		return nil
+	}
+	if len(compatible) == 0 {
+		logger.Warning("none of the nodegroups can be migrated to Graviton instances, not writing %q", generateConfig)
+		return nil
+	}
+	return writeGravitonConfig(cfg, compatible, generateConfig)
+}
+
+// writeGravitonConfig writes a config of the nodegroups replacing the compatible nodegroups, which
+// are copies of them with the equivalent Graviton instance types
+func writeGravitonConfig(cfg *api.ClusterConfig, compatible map[string]string, path string) error {
+	gravitonConfig := api.NewClusterConfig()
+	gravitonConfig.Metadata.Name = cfg.Metadata.Name
+	gravitonConfig.Metadata.Region = cfg.Metadata.Region
+
+	for _, ng := range cfg.NodeGroups {
+		if instanceType, ok := compatible[ng.Name]; ok {
+			ng.Name += gravitonNodeGroupSuffix
+			ng.InstanceType = instanceType
+			gravitonConfig.NodeGroups = append(gravitonConfig.NodeGroups, ng)
+		}
+	}
+	for _, ng := range cfg.ManagedNodeGroups {
+		if instanceType, ok := compatible[ng.Name]; ok {
+			ng.Name += gravitonNodeGroupSuffix
+			ng.InstanceType = instanceType
+			gravitonConfig.ManagedNodeGroups = append(gravitonConfig.ManagedNodeGroups, ng)
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.Wrapf(err, "creating %q", path)
+	}
+	defer file.Close()
+	if err := printers.NewYAMLPrinter().PrintObj(gravitonConfig, file); err != nil {
+		return errors.Wrapf(err, "writing %q", path)
+	}
+
+	logger.Success("wrote the config of %d Graviton nodegroups to %q", len(compatible), path)
+	logger.Info("create them with 'eksctl create nodegroup --config-file=%s', then drain and delete the nodegroups they replace", path)
+	return nil
+}
+
+func addARMCompatibilityTableColumns(printer *printers.TablePrinter) {
+	printer.AddColumn("NODEGROUP", func(r *arm.NodeGroupReport) string {
+		return r.Name
+	})
+	printer.AddColumn("INSTANCE TYPE", func(r *arm.NodeGroupReport) string {
+		return r.InstanceType
+	})
+	printer.AddColumn("GRAVITON INSTANCE TYPE", func(r *arm.NodeGroupReport) string {
+		return r.GravitonInstanceType
+	})
+	printer.AddColumn("STATUS", func(r *arm.NodeGroupReport) string {
+		return r.Status
+	})
+	printer.AddColumn("BLOCKERS", func(r *arm.NodeGroupReport) string {
+		return fmt.Sprintf("%d", len(r.Blockers))
+	})
+}
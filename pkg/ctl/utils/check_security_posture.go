@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/posture"
+	"github.com/weaveworks/eksctl/pkg/printers"
+)
+
+// securityPostureFailedExitCode is the exit code when checks fail, so that policy pipelines can
+// tell them apart from errors running the checks
+const securityPostureFailedExitCode = 2
+
+func checkSecurityPostureCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var output string
+
+	cmd.SetDescription("check-security-posture", "Check the security posture of a cluster",
+		fmt.Sprintf("Check secrets encryption, public endpoint access, control plane logging, IMDSv2 on the nodes and the IAM OIDC provider of a cluster; exits with %d when any check fails", securityPostureFailedExitCode))
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doCheckSecurityPosture(cmd, output)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		fs.StringVarP(&output, "output", "o", "table", "specifies the output format (valid option: table, json, yaml)")
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doCheckSecurityPosture(cmd *cmdutils.Cmd, output string) error {
+	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	printer, err := printers.NewPrinter(output)
+	if err != nil {
+		return err
+	}
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(meta)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	inputs, err := ctl.GetSecurityPostureInputs(cfg)
+	if err != nil {
+		return err
+	}
+	report := posture.Check(inputs)
+
+	if output == printers.TableType {
+		addSecurityPostureTableColumns(printer.(*printers.TablePrinter))
+		if err := printer.PrintObjWithKind("checks", report.Results, os.Stdout); err != nil {
+			return err
+		}
+	} else if err := printer.PrintObjWithKind("report", report, os.Stdout); err != nil {
+		return err
+	}
+
+	if !report.Passed {
+		return &cmdutils.ExitCodeError{
+			Code: securityPostureFailedExitCode,
+			Err:  fmt.Errorf("cluster %q failed security posture checks", meta.Name),
+		}
+	}
+	logger.Success("cluster %q passed all security posture checks", meta.Name)
+	return nil
+}
+
+func addSecurityPostureTableColumns(printer *printers.TablePrinter) {
+	printer.AddColumn("CHECK", func(r posture.Result) string {
+		return r.Name
+	})
+	printer.AddColumn("STATUS", func(r posture.Result) string {
+		return r.Status
+	})
+	printer.AddColumn("MESSAGE", func(r posture.Result) string {
+		return r.Message
+	})
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+func detectDriftCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	cmd.SetDescription("detect-drift", "Detect the resources of the CloudFormation stacks of a cluster that were changed outside of CloudFormation",
+		"Run CloudFormation drift detection on every stack of a cluster and report the resources that were changed or deleted, e.g. in the AWS console, as they routinely break later updates of the stacks")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doDetectDrift(cmd)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlagWithDeprecated(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doDetectDrift(cmd *cmdutils.Cmd) error {
+	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	logger.Info("using region %s", meta.Region)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cmd.ProviderConfig.WaitTimeout)
+	defer cancel()
+	drifts, err := ctl.NewStackManager(cfg).DetectDrift(ctx)
+	if err != nil {
+		return err
+	}
+
+	drifted := 0
+	for _, drift := range drifts {
+		if !drift.IsDrifted() {
+			logger.Info("stack %q is in sync with its template", *drift.Stack.StackName)
+			continue
+		}
+		drifted++
+		logDriftedResources(drift)
+	}
+	if drifted > 0 {
+		logger.Warning("%d stack(s) of cluster %q have drifted, updating them may fail or revert the changes", drifted, meta.Name)
+	} else {
+		logger.Success("no stacks of cluster %q have drifted", meta.Name)
+	}
+	return nil
+}
+
+func logDriftedResources(drift *manager.StackDrift) {
+	logger.Warning("the following resources of stack %q have drifted:", *drift.Stack.StackName)
+	for _, r := range drift.DriftedResources {
+		logger.Warning("%s/%s (%s): %s", aws.StringValue(r.ResourceType), aws.StringValue(r.LogicalResourceId),
+			aws.StringValue(r.PhysicalResourceId), aws.StringValue(r.StackResourceDriftStatus))
+		for _, d := range r.PropertyDifferences {
+			logger.Warning("  %s %s: expected %s, actual %s", aws.StringValue(d.PropertyPath), aws.StringValue(d.DifferenceType),
+				aws.StringValue(d.ExpectedValue), aws.StringValue(d.ActualValue))
+		}
+	}
+}
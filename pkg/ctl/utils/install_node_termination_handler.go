@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+func installNodeTerminationHandlerCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	cmd.SetDescription("install-node-termination-handler", "Install AWS Node Termination Handler to drain nodes of unmanaged nodegroups before they are interrupted", "")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doInstallNodeTerminationHandler(cmd)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlagWithDeprecated(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		cmdutils.AddApproveFlag(fs, cmd)
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doInstallNodeTerminationHandler(cmd *cmdutils.Cmd) error {
+	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	if cfg.NodeTerminationHandler == nil {
+		// without a config file, the handler is installed in IMDS mode
+		cfg.NodeTerminationHandler = &api.NodeTerminationHandler{
+			Enabled: api.Enabled(),
+		}
+	} else if !cfg.HasNodeTerminationHandler() {
+		return fmt.Errorf("nodeTerminationHandler.enabled must be enabled explicitly")
+	}
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	logger.Info("using region %s", meta.Region)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	if ok, err := ctl.CanUpdate(cfg); !ok {
+		return err
+	}
+
+	eks.LogNodeTerminationHandlerCompatibility(cfg)
+
+	if err := ctl.InstallNodeTerminationHandler(cfg, cmd.Plan); err != nil {
+		return errors.Wrap(err, "error installing Node Termination Handler")
+	}
+
+	cmdutils.LogPlanModeWarning(cmd.Plan)
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/ipusage"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/printers"
+)
+
+func ipUsageCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var (
+		output        string
+		warnThreshold int64
+	)
+
+	cmd.SetDescription("ip-usage", "Report the IP usage of the subnets of a cluster",
+		"Report the free IPs of each subnet, the ENIs and IPs consumed by each nodegroup, whether prefix delegation is enabled and the pod capacity of the nodes, and warn about subnets close to exhaustion")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doIPUsage(cmd, output, warnThreshold)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		fs.StringVarP(&output, "output", "o", "table", "specifies the output format (valid option: table, json, yaml)")
+		fs.Int64Var(&warnThreshold, "warn-threshold", 80, "percentage of used IPs from which a subnet is reported as close to exhaustion")
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doIPUsage(cmd *cmdutils.Cmd, output string, warnThreshold int64) error {
+	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	if warnThreshold < 0 || warnThreshold > 100 {
+		return fmt.Errorf("--warn-threshold must be between 0 and 100")
+	}
+
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	printer, err := printers.NewPrinter(output)
+	if err != nil {
+		return err
+	}
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(meta)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	inputs, err := ctl.GetIPUsageInputs(cfg)
+	if err != nil {
+		return err
+	}
+	report := ipusage.Compute(inputs, warnThreshold)
+
+	if output == printers.TableType {
+		switch {
+		case report.PrefixDelegation == nil:
+			logger.Info("prefix delegation status is unknown, pod capacity assumes it is disabled")
+		case *report.PrefixDelegation:
+			logger.Info("prefix delegation is enabled")
+		default:
+			logger.Info("prefix delegation is disabled")
+		}
+
+		addIPUsageSubnetTableColumns(printer.(*printers.TablePrinter))
+		if err := printer.PrintObjWithKind("subnets", report.Subnets, os.Stdout); err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stdout)
+
+		printer, err = printers.NewPrinter(output)
+		if err != nil {
+			return err
+		}
+		addIPUsageNodeGroupTableColumns(printer.(*printers.TablePrinter))
+		if err := printer.PrintObjWithKind("nodegroups", report.NodeGroups, os.Stdout); err != nil {
+			return err
+		}
+	} else if err := printer.PrintObjWithKind("report", report, os.Stdout); err != nil {
+		return err
+	}
+
+	for _, subnet := range report.Subnets {
+		if subnet.NearExhaustion {
+			logger.Warning("subnet %q in %s has %d free IPs left (%d%% used)", subnet.SubnetID, subnet.AvailabilityZone, subnet.FreeIPs, subnet.UsedPercent)
+		}
+	}
+	return nil
+}
+
+func addIPUsageSubnetTableColumns(printer *printers.TablePrinter) {
+	printer.AddColumn("SUBNET", func(s *ipusage.SubnetUsage) string {
+		return s.SubnetID
+	})
+	printer.AddColumn("AVAILABILITY ZONE", func(s *ipusage.SubnetUsage) string {
+		return s.AvailabilityZone
+	})
+	printer.AddColumn("CIDR", func(s *ipusage.SubnetUsage) string {
+		return s.CIDR
+	})
+	printer.AddColumn("TOTAL IPS", func(s *ipusage.SubnetUsage) string {
+		return strconv.FormatInt(s.TotalIPs, 10)
+	})
+	printer.AddColumn("FREE IPS", func(s *ipusage.SubnetUsage) string {
+		return strconv.FormatInt(s.FreeIPs, 10)
+	})
+	printer.AddColumn("USED", func(s *ipusage.SubnetUsage) string {
+		return fmt.Sprintf("%d%%", s.UsedPercent)
+	})
+}
+
+func addIPUsageNodeGroupTableColumns(printer *printers.TablePrinter) {
+	printer.AddColumn("NODEGROUP", func(ng *ipusage.NodeGroupUsage) string {
+		return ng.Name
+	})
+	printer.AddColumn("NODES", func(ng *ipusage.NodeGroupUsage) string {
+		return strconv.Itoa(ng.Nodes)
+	})
+	printer.AddColumn("ENIS", func(ng *ipusage.NodeGroupUsage) string {
+		return strconv.Itoa(ng.ENIs)
+	})
+	printer.AddColumn("IPS", func(ng *ipusage.NodeGroupUsage) string {
+		return strconv.Itoa(ng.IPs)
+	})
+	printer.AddColumn("POD CAPACITY", func(ng *ipusage.NodeGroupUsage) string {
+		return strconv.Itoa(ng.PodCapacity)
+	})
+}
@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/weaveworks/eksctl/pkg/addons"
+	defaultaddons "github.com/weaveworks/eksctl/pkg/addons/default"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/printers"
+	"github.com/weaveworks/eksctl/pkg/utils"
+)
+
+// pauseImageFormat is the pause image the bootstrap scripts of unmanaged nodegroups configure kubelet with
+const pauseImageFormat = "%%s.dkr.ecr.%%s.%%s/eks/pause-%s:3.1"
+
+// requiredImage is an image a cluster pulls, and where it's pulled from with containerRegistryOverride
+type requiredImage struct {
+	Image  string `json:"image"`
+	Mirror string `json:"mirror,omitempty"`
+}
+
+func listImagesCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var (
+		output               string
+		installVPCController bool
+	)
+
+	cmd.SetDescription("list-images", "List the images a cluster config requires",
+		"List the images of the core components, the add-ons and the node bootstrap a cluster created from a config file pulls, and the images of containerRegistryOverride they have to be mirrored to")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, _ []string) error {
+		return doListImages(cmd, output, installVPCController)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		fs.BoolVar(&installVPCController, "install-vpc-controllers", false, "include the images of the VPC controller that's required for Windows workloads")
+		fs.StringVarP(&output, "output", "o", "table", "specifies the output format (valid option: table, json, yaml)")
+	})
+}
+
+func doListImages(cmd *cmdutils.Cmd, output string, installVPCController bool) error {
+	if cmd.ClusterConfigFile == "" {
+		return cmdutils.ErrMustBeSet("--config-file/-f")
+	}
+	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	region := cfg.Metadata.Region
+	version := cfg.Metadata.Version
+	switch version {
+	case "", "auto":
+		version = api.DefaultVersion
+	case "latest":
+		version = api.LatestVersion
+	}
+
+	printer, err := printers.NewPrinter(output)
+	if err != nil {
+		return err
+	}
+
+	images, err := defaultaddons.CoreComponentImages(region, version)
+	if err != nil {
+		return errors.Wrap(err, "listing the images of the core components")
+	}
+	addonImages, err := addons.Images(cfg, region, installVPCController)
+	if err != nil {
+		return errors.Wrap(err, "listing the images of the add-ons")
+	}
+	images = append(images, addonImages...)
+	pauseImages, err := pauseImages(cfg, region)
+	if err != nil {
+		return err
+	}
+	images = append(images, pauseImages...)
+
+	required := make([]requiredImage, len(images))
+	for i, image := range images {
+		required[i] = requiredImage{Image: image}
+		if cfg.HasContainerRegistryOverride() {
+			required[i].Mirror = api.OverrideImageRegistry(image, cfg.ContainerRegistryOverride)
+		}
+	}
+
+	if output == printers.TableType {
+		addListImagesTableColumns(printer.(*printers.TablePrinter))
+	}
+	if err := printer.PrintObjWithKind("images", required, os.Stdout); err != nil {
+		return err
+	}
+	if output == printers.TableType {
+		logger.Info("kube-proxy is tagged with the platform version of the cluster, e.g. v%s.9, mirror the tag matching it", version)
+		if cfg.HasAddons() || len(cfg.ManagedNodeGroups) > 0 {
+			logger.Warning("the images of EKS add-ons and managed nodegroups are pulled by EKS, containerRegistryOverride doesn't apply to them")
+		}
+	}
+	return nil
+}
+
+// pauseImages returns the pause images of the architectures of the unmanaged nodegroups
+func pauseImages(cfg *api.ClusterConfig, region string) ([]string, error) {
+	var archs []string
+	hasARM, hasAMD64 := false, false
+	for _, ng := range cfg.NodeGroups {
+		if api.IsWindowsImage(ng.AMIFamily) || ng.AMIFamily == api.NodeImageFamilyBottlerocket {
+			continue
+		}
+		if utils.IsARMInstanceType(ng.InstanceType) {
+			hasARM = true
+		} else {
+			hasAMD64 = true
+		}
+	}
+	if hasAMD64 {
+		archs = append(archs, "amd64")
+	}
+	if hasARM {
+		archs = append(archs, "arm64")
+	}
+
+	var images []string
+	for _, arch := range archs {
+		image, err := addons.RegionalImage(fmt.Sprintf(pauseImageFormat, arch), region)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, image)
+	}
+	return images, nil
+}
+
+func addListImagesTableColumns(printer *printers.TablePrinter) {
+	printer.AddColumn("IMAGE", func(r requiredImage) string {
+		return r.Image
+	})
+	printer.AddColumn("MIRROR", func(r requiredImage) string {
+		return r.Mirror
+	})
+}
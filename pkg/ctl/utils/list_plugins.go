@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/plugins"
+	"github.com/weaveworks/eksctl/pkg/printers"
+)
+
+func listPluginsCmd(cmd *cmdutils.Cmd) {
+	cmd.SetDescription("list-plugins", "List plugins on PATH",
+		"List the eksctl-<name> executables on PATH, which are run as 'eksctl <name>'.")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, _ []string) error {
+		return doListPlugins(cmd)
+	}
+}
+
+func doListPlugins(cmd *cmdutils.Cmd) error {
+	list := plugins.List()
+	if len(list) == 0 {
+		logger.Info("no plugins found on PATH")
+		return nil
+	}
+
+	rootCmd := cmd.CobraCommand.Root()
+	for _, plugin := range list {
+		if c, _, err := rootCmd.Find([]string{plugin.Name}); err == nil && c != rootCmd {
+			logger.Warning("plugin %q is overshadowed by the built-in command %q", plugin.Path, c.CommandPath())
+		}
+		for _, path := range plugin.Shadowed {
+			logger.Warning("plugin %q is overshadowed by %q, which comes first on PATH", path, plugin.Path)
+		}
+	}
+
+	printer := printers.NewTablePrinter()
+	table := printer.(*printers.TablePrinter)
+	table.AddColumn("NAME", func(p *plugins.Plugin) string {
+		return p.Name
+	})
+	table.AddColumn("PATH", func(p *plugins.Plugin) string {
+		return p.Path
+	})
+	return printer.PrintObjWithKind("plugins", list, os.Stdout)
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	defaultaddons "github.com/weaveworks/eksctl/pkg/addons/default"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+func migrateToManagedAddonsCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	cmd.SetDescription("migrate-to-managed-addons", "Migrate kube-proxy, aws-node and coredns to EKS add-ons",
+		"Adopt the self-managed kube-proxy, aws-node and coredns of a cluster into EKS add-ons, preserving the configuration the add-ons accept and reporting what they override")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doMigrateToManagedAddons(cmd)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		cmdutils.AddApproveFlag(fs, cmd)
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doMigrateToManagedAddons(cmd *cmdutils.Cmd) error {
+	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(meta)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	if ok, err := ctl.CanUpdate(cfg); !ok {
+		return err
+	}
+
+	clientSet, err := ctl.NewStdClientSet(cfg)
+	if err != nil {
+		return err
+	}
+	addonsAPI, err := ctl.NewAddonsAPI()
+	if err != nil {
+		return err
+	}
+
+	migrations, err := defaultaddons.PlanManagedAddonsMigration(clientSet, addonsAPI, meta.Name, ctl.ControlPlaneVersion())
+	if err != nil {
+		return err
+	}
+	if len(migrations) == 0 {
+		logger.Success("there are no self-managed core components to migrate in cluster %q", meta.Name)
+		return nil
+	}
+
+	for _, migration := range migrations {
+		cmdutils.LogIntendedAction(cmd.Plan, "migrate %q to EKS add-on version %s", migration.AddonName, migration.Version)
+		if len(migration.Preserved) > 0 {
+			logger.Info("%s: preserving %s", migration.AddonName, strings.Join(migration.Preserved, ", "))
+		}
+		if len(migration.Overridden) > 0 {
+			logger.Warning("%s: the add-on overrides %s", migration.AddonName, strings.Join(migration.Overridden, ", "))
+		}
+	}
+
+	if !cmd.Plan {
+		for _, migration := range migrations {
+			if err := defaultaddons.MigrateToManagedAddon(addonsAPI, meta.Name, migration, ctl.Provider.WaitTimeout()); err != nil {
+				return err
+			}
+			logger.Success("%q is now an EKS add-on", migration.AddonName)
+		}
+		cmdutils.LogCompletedAction(false, "migrated %d core component(s) of cluster %q to EKS add-ons", len(migrations), meta.Name)
+	}
+	cmdutils.LogPlanModeWarning(cmd.Plan)
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/ipv6migration"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/printers"
+)
+
+func previewIPv6MigrationCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var output, generateConfig string
+
+	cmd.SetDescription("preview-ipv6-migration", "Preview migrating an IPv4 cluster to IPv6",
+		"Translate the config of an IPv4 cluster into the config of an equivalent IPv6 cluster, and report the settings that change and the add-ons and workloads that aren't compatible with IPv6")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doPreviewIPv6Migration(cmd, output, generateConfig)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		fs.StringVarP(&output, "output", "o", "table", "specifies the output format (valid option: table, json, yaml)")
+		fs.StringVar(&generateConfig, "generate-config", "", "write the config of the IPv6 cluster to the given path")
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doPreviewIPv6Migration(cmd *cmdutils.Cmd, output, generateConfig string) error {
+	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	printer, err := printers.NewPrinter(output)
+	if err != nil {
+		return err
+	}
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(meta)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	if ok, err := ctl.CanOperate(cfg); !ok {
+		return err
+	}
+	meta.Version = ctl.ControlPlaneVersion()
+
+	if cfg.IPv6Enabled() {
+		return errors.Errorf("cluster %q already uses %s", meta.Name, api.IPV6Family)
+	}
+
+	// without a config file, the nodegroups of the cluster are translated
+	if cmd.ClusterConfigFile == "" {
+		if err := ctl.AddExistingNodeGroups(cfg); err != nil {
+			return err
+		}
+	}
+
+	inputs, err := ctl.GetIPv6MigrationInputs(cfg)
+	if err != nil {
+		return err
+	}
+	report := ipv6migration.Preview(cfg, inputs)
+
+	if output == printers.TableType {
+		addIPv6MigrationTableColumns(printer.(*printers.TablePrinter))
+		if err := printer.PrintObjWithKind("findings", report.Findings, os.Stdout); err != nil {
+			return err
+		}
+	} else if err := printer.PrintObjWithKind("report", report, os.Stdout); err != nil {
+		return err
+	}
+
+	if report.Ready {
+		logger.Success("nothing blocks migrating cluster %q to %s", meta.Name, api.IPV6Family)
+	} else {
+		logger.Warning("cluster %q has workloads or nodegroups that don't work with %s, see the blockers above", meta.Name, api.IPV6Family)
+	}
+
+	if generateConfig == "" {
+		logger.Info("to write the config of the %s cluster, re-run with --generate-config", api.IPV6Family)
+		return nil
+	}
+	return writeIPv6Config(report.Config, generateConfig)
+}
+
+func writeIPv6Config(cfg *api.ClusterConfig, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.Wrapf(err, "creating %q", path)
+	}
+	defer file.Close()
+	if err := printers.NewYAMLPrinter().PrintObj(cfg, file); err != nil {
+		return errors.Wrapf(err, "writing %q", path)
+	}
+
+	logger.Success("wrote the config of %s cluster %q to %q", api.IPV6Family, cfg.Metadata.Name, path)
+	logger.Info("create it with 'eksctl create cluster --config-file=%s', then move the workloads to it", path)
+	return nil
+}
+
+func addIPv6MigrationTableColumns(printer *printers.TablePrinter) {
+	printer.AddColumn("COMPONENT", func(f ipv6migration.Finding) string {
+		return f.Component
+	})
+	printer.AddColumn("SEVERITY", func(f ipv6migration.Finding) string {
+		return f.Severity
+	})
+	printer.AddColumn("MESSAGE", func(f ipv6migration.Finding) string {
+		return f.Message
+	})
+}
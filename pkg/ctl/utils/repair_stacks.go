@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+func repairStacksCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	cmd.SetDescription("repair-stacks", "Repair CloudFormation stacks of a cluster stuck in UPDATE_ROLLBACK_FAILED or DELETE_FAILED state", "")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doRepairStacks(cmd)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlagWithDeprecated(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		cmdutils.AddApproveFlag(fs, cmd)
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doRepairStacks(cmd *cmdutils.Cmd) error {
+	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	logger.Info("using region %s", meta.Region)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	stackManager := ctl.NewStackManager(cfg)
+
+	failedStacks, err := stackManager.ListFailedStacks()
+	if err != nil {
+		return err
+	}
+
+	if len(failedStacks) == 0 {
+		logger.Info("no stacks of cluster %q need repairing", meta.Name)
+		return nil
+	}
+
+	for _, s := range failedStacks {
+		if err := repairStack(stackManager, s, cmd.Plan); err != nil {
+			return err
+		}
+	}
+
+	cmdutils.LogPlanModeWarning(cmd.Plan)
+
+	return nil
+}
+
+func repairStack(stackManager *manager.StackCollection, s *manager.FailedStack, plan bool) error {
+	stackName := *s.Stack.StackName
+	logger.Info("stack %q is in %q state", stackName, *s.Stack.StackStatus)
+
+	if s.NeedsRollback() {
+		logFailedResources(s, "will be skipped in the rollback and left in their current state")
+		cmdutils.LogIntendedAction(plan, "continue rollback of stack %q", stackName)
+		if plan {
+			return nil
+		}
+		if err := stackManager.ContinueUpdateRollback(s); err != nil {
+			return err
+		}
+		cmdutils.LogCompletedAction(plan, "rolled back stack %q", stackName)
+	} else {
+		logFailedResources(s, "will be retained and have to be deleted manually")
+		cmdutils.LogIntendedAction(plan, "retry deletion of stack %q", stackName)
+		if plan {
+			return nil
+		}
+		if err := stackManager.DeleteFailedStack(s); err != nil {
+			return err
+		}
+		cmdutils.LogCompletedAction(plan, "deleted stack %q", stackName)
+	}
+	return nil
+}
+
+func logFailedResources(s *manager.FailedStack, outcome string) {
+	if len(s.FailedResources) == 0 {
+		return
+	}
+	logger.Warning("the following resources of stack %q %s:", *s.Stack.StackName, outcome)
+	for _, r := range s.FailedResources {
+		logger.Warning("%s/%s (%s): %s", aws.StringValue(r.ResourceType), aws.StringValue(r.LogicalResourceId),
+			aws.StringValue(r.PhysicalResourceId), aws.StringValue(r.ResourceStatusReason))
+	}
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/iam"
+	"github.com/weaveworks/eksctl/pkg/printers"
+)
+
+func requiredIAMCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var (
+		operations []string
+		accountID  string
+	)
+
+	cmd.SetDescription("required-iam", "Generate the IAM policy required to create or delete a cluster",
+		"Generate the minimal IAM policy document the caller needs to create or delete the cluster, nodegroups and add-ons of a config file")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, _ []string) error {
+		return doRequiredIAM(cmd, operations, accountID)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		fs.StringSliceVar(&operations, "operations", iam.SupportedOperations(), "operations to generate the policy for, out of create and delete")
+		fs.StringVar(&accountID, "account-id", "*", "ID of the AWS account the resources of the policy are scoped to")
+	})
+}
+
+func doRequiredIAM(cmd *cmdutils.Cmd, operations []string, accountID string) error {
+	if cmd.ClusterConfigFile == "" {
+		return cmdutils.ErrMustBeSet("--config-file/-f")
+	}
+	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	policy, err := iam.RequiredPolicy(cmd.ClusterConfig, accountID, operations)
+	if err != nil {
+		return err
+	}
+	return printers.NewJSONPrinter().PrintObj(policy, os.Stdout)
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	iamoidc "github.com/weaveworks/eksctl/pkg/iam/oidc"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+func retagClusterCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	cmd.SetDescription("retag-cluster", "Apply metadata.tags to all the CloudFormation stacks of an existing cluster and their resources, and to its IAM OIDC provider", "")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doRetagCluster(cmd)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlagWithDeprecated(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		fs.StringToStringVarP(&cfg.Metadata.Tags, "tags", "", map[string]string{}, `A list of KV pairs used to tag the AWS resources (e.g. "Owner=John Doe,Team=Some Team")`)
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		cmdutils.AddApproveFlag(fs, cmd)
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doRetagCluster(cmd *cmdutils.Cmd) error {
+	if err := cmdutils.NewUtilsRetagClusterLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	logger.Info("using region %s", meta.Region)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	stackManager := ctl.NewStackManager(cfg)
+
+	stacks, err := stackManager.DescribeStacks()
+	if err != nil {
+		return err
+	}
+
+	updated := 0
+	for _, s := range stacks {
+		tags := stackManager.StackTagsDiff(s)
+		if len(tags) == 0 {
+			logger.Debug("stack %q already has all the tags", *s.StackName)
+			continue
+		}
+		updated++
+		cmdutils.LogIntendedAction(cmd.Plan, "update tags of stack %q with %v", *s.StackName, tags)
+		if cmd.Plan {
+			continue
+		}
+		if err := stackManager.UpdateStackTags(s, tags); err != nil {
+			return err
+		}
+		cmdutils.LogCompletedAction(cmd.Plan, "updated tags of stack %q", *s.StackName)
+	}
+
+	// the IAM OIDC provider isn't part of the stacks, it's found by the tag eksctl adds to it
+	oidc, err := iamoidc.FindTaggedProvider(ctl.Provider.IAM(), map[string]string{api.ClusterNameTag: meta.Name})
+	if err != nil {
+		return err
+	}
+	if oidc != nil {
+		providerTags, err := oidc.ProviderTags()
+		if err != nil {
+			return err
+		}
+		tags := map[string]string{}
+		for key, value := range meta.Tags {
+			if current, ok := providerTags[key]; !ok || current != value {
+				tags[key] = value
+			}
+		}
+		if len(tags) > 0 {
+			updated++
+			cmdutils.LogIntendedAction(cmd.Plan, "update tags of IAM OIDC provider %q with %v", oidc.ProviderARN, tags)
+			if !cmd.Plan {
+				if err := oidc.TagProvider(tags); err != nil {
+					return err
+				}
+				cmdutils.LogCompletedAction(cmd.Plan, "updated tags of IAM OIDC provider %q", oidc.ProviderARN)
+			}
+		}
+	}
+
+	if updated == 0 {
+		logger.Info("all stacks of cluster %q are already tagged", meta.Name)
+		return nil
+	}
+
+	cmdutils.LogPlanModeWarning(cmd.Plan)
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"encoding/base64"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/utils/kubeconfig"
+)
+
+func rotateCertificatesCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var (
+		kubeconfigPath string
+		prune          bool
+	)
+
+	cmd.SetDescription("rotate-certificates", "Refresh the endpoint and CA data of all clusters of a region in a kubeconfig file",
+		"Stale entries pointing at deleted clusters are reported and can be removed with --prune")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			return cmdutils.ErrUnsupportedNameArg()
+		}
+		return doRotateCertificates(cmd, kubeconfigPath, prune)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		fs.StringVar(&kubeconfigPath, "kubeconfig", kubeconfig.DefaultPath, "path to the kubeconfig file to refresh")
+		fs.BoolVar(&prune, "prune", false, "remove entries of clusters that no longer exist from the kubeconfig file")
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doRotateCertificates(cmd *cmdutils.Cmd, kubeconfigPath string, prune bool) error {
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	region := ctl.Provider.Region()
+	logger.Info("using region %s", region)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	config, err := kubeconfig.Read(kubeconfigPath)
+	if err != nil {
+		return err
+	}
+
+	entries := kubeconfig.ClustersInRegion(config, region)
+	if len(entries) == 0 {
+		logger.Info("no clusters of region %s found in kubeconfig %q", region, kubeconfigPath)
+		return nil
+	}
+
+	var updated, stale, pruned int
+	for _, entry := range entries {
+		cluster, err := ctl.DescribeControlPlane(&api.ClusterMeta{Name: entry.Name, Region: region})
+		if err != nil {
+			if !isClusterNotFound(err) {
+				return err
+			}
+			stale++
+			if prune {
+				kubeconfig.DeleteClusterInfo(config, entry.Key)
+				pruned++
+				logger.Info("removed %q from kubeconfig, as cluster %q no longer exists", entry.Key, entry.Name)
+			} else {
+				logger.Warning("%q in kubeconfig refers to cluster %q, which no longer exists", entry.Key, entry.Name)
+			}
+			continue
+		}
+
+		if cluster.Endpoint == nil || cluster.CertificateAuthority == nil || cluster.CertificateAuthority.Data == nil {
+			logger.Warning("skipping %q, as cluster %q is in %q state", entry.Key, entry.Name, aws.StringValue(cluster.Status))
+			continue
+		}
+		caData, err := base64.StdEncoding.DecodeString(*cluster.CertificateAuthority.Data)
+		if err != nil {
+			return errors.Wrapf(err, "decoding certificate authority data of cluster %q", entry.Name)
+		}
+		if kubeconfig.UpdateClusterInfo(config, entry.Key, *cluster.Endpoint, caData) {
+			updated++
+			logger.Info("refreshed endpoint and CA data of %q", entry.Key)
+		} else {
+			logger.Debug("%q is up-to-date", entry.Key)
+		}
+	}
+
+	if stale > pruned {
+		logger.Warning("found %d stale entries, use --prune to remove them", stale-pruned)
+	}
+
+	if updated == 0 && pruned == 0 {
+		logger.Success("kubeconfig %q is up-to-date", kubeconfigPath)
+		return nil
+	}
+
+	if err := kubeconfig.Save(kubeconfigPath, config); err != nil {
+		return err
+	}
+	logger.Success("refreshed %d and removed %d clusters in kubeconfig %q", updated, pruned, kubeconfigPath)
+	return nil
+}
+
+func isClusterNotFound(err error) bool {
+	awsError, ok := errors.Cause(err).(awserr.Error)
+	return ok && awsError.Code() == awseks.ErrCodeResourceNotFoundException
+}
@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+func updateAuthenticationModeCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var (
+		authenticationMode string
+		force              bool
+	)
+
+	cmd.SetDescription("update-authentication-mode", "Update the authentication mode of a cluster",
+		"Update how IAM principals are authenticated to a cluster: with the aws-auth ConfigMap (CONFIG_MAP), EKS access entries (API) or both (API_AND_CONFIG_MAP)")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		if authenticationMode != "" {
+			cfg.AccessConfig = &api.AccessConfig{AuthenticationMode: authenticationMode}
+		}
+		return doUpdateAuthenticationMode(cmd, force)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		fs.StringVar(&authenticationMode, "authentication-mode", "", fmt.Sprintf("authentication mode to use (valid options: %s)", strings.Join(api.SupportedAuthenticationModes(), ", ")))
+		fs.BoolVar(&force, "force", false, "switch to API even if roles mapped in the aws-auth ConfigMap still exist")
+		cmdutils.AddApproveFlag(fs, cmd)
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doUpdateAuthenticationMode(cmd *cmdutils.Cmd, force bool) error {
+	if err := cmdutils.NewUtilsUpdateAuthenticationModeLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(meta)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	if ok, err := ctl.CanUpdate(cfg); !ok {
+		return err
+	}
+
+	current, err := ctl.GetAuthenticationMode(meta.Name)
+	if err != nil {
+		return err
+	}
+	desired := cfg.AccessConfig.AuthenticationMode
+	if current == desired {
+		logger.Success("the authentication mode of cluster %q is already %s", meta.Name, desired)
+		return nil
+	}
+	if err := eks.ValidateAuthenticationModeChange(current, desired); err != nil {
+		return err
+	}
+
+	if desired == api.AuthenticationModeAPI {
+		clientSet, err := ctl.NewStdClientSet(cfg)
+		if err != nil {
+			return err
+		}
+		roles, err := eks.ActiveMappedRoles(clientSet, ctl.Provider.IAM())
+		if err != nil {
+			return err
+		}
+		if len(roles) > 0 {
+			msg := fmt.Sprintf("the aws-auth ConfigMap still maps %d existing roles, which will lose access to the cluster unless they have access entries:\n\t%s",
+				len(roles), strings.Join(roles, "\n\t"))
+			if !force {
+				return fmt.Errorf("%s\nuse --force to switch to %s anyway", msg, api.AuthenticationModeAPI)
+			}
+			logger.Warning(msg)
+		}
+	}
+
+	cmdutils.LogIntendedAction(cmd.Plan, "update the authentication mode of cluster %q in %q from %s to %s",
+		meta.Name, meta.Region, current, desired)
+	if desired == api.AuthenticationModeAPI {
+		logger.Warning("the authentication mode cannot be changed back from %s", api.AuthenticationModeAPI)
+	}
+
+	if !cmd.Plan {
+		if err := ctl.UpdateAuthenticationMode(cfg); err != nil {
+			return err
+		}
+		cmdutils.LogCompletedAction(false, "the authentication mode of cluster %q in %q has been updated to %s",
+			meta.Name, meta.Region, desired)
+	}
+	cmdutils.LogPlanModeWarning(cmd.Plan)
+
+	return nil
+}
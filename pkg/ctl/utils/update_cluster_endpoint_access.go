@@ -1,12 +1,16 @@
 package utils
 
 import (
-	"github.com/kris-nova/logger"
+	"fmt"
+	"net"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 var (
@@ -18,16 +22,18 @@ func updateClusterEndpointsCmd(cmd *cmdutils.Cmd) {
 	cfg := api.NewClusterConfig()
 	cmd.ClusterConfig = cfg
 
+	var addCIDRs, removeCIDRs []string
+
 	cmd.SetDescription("update-cluster-endpoints", "Update Kubernetes API endpoint access configuration", "")
 
 	cmd.CobraCommand.RunE = func(_ *cobra.Command, _ []string) error {
-		return doUpdateClusterEndpoints(cmd, private, public)
+		return doUpdateClusterEndpoints(cmd, private, public, addCIDRs, removeCIDRs)
 	}
 
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
 		cmdutils.AddClusterFlagWithDeprecated(fs, cfg.Metadata)
 		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
-		cmdutils.AddConfigFileFlag(fs, &cmd.ClusterConfigFile)
+		cmdutils.AddConfigFileFlag(fs, cmd)
 		cmdutils.AddApproveFlag(fs, cmd)
 		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
 	})
@@ -36,11 +42,13 @@ func updateClusterEndpointsCmd(cmd *cmdutils.Cmd) {
 		func(fs *pflag.FlagSet) {
 			fs.BoolVar(&private, "private-access", false, "access for private (VPC) clients")
 			fs.BoolVar(&public, "public-access", false, "access for public clients")
+			fs.StringSliceVar(&addCIDRs, "add-public-access-cidrs", nil, "CIDR blocks to add to the ones allowed to access the public endpoint")
+			fs.StringSliceVar(&removeCIDRs, "remove-public-access-cidrs", nil, "CIDR blocks to remove from the ones allowed to access the public endpoint")
 		})
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
 }
 
-func doUpdateClusterEndpoints(cmd *cmdutils.Cmd, newPrivate bool, newPublic bool) error {
+func doUpdateClusterEndpoints(cmd *cmdutils.Cmd, newPrivate bool, newPublic bool, addCIDRs, removeCIDRs []string) error {
 	if err := cmdutils.NewUtilsEnableEndpointAccessLoader(cmd, newPrivate, newPublic).Load(); err != nil {
 		return err
 	}
@@ -83,39 +91,120 @@ func doUpdateClusterEndpoints(cmd *cmdutils.Cmd, newPrivate bool, newPublic bool
 		newPublic = *cfg.VPC.ClusterEndpoints.PublicAccess
 	}
 
+	newCIDRs, err := desiredPublicAccessCIDRs(clusterVPCConfig.PublicAccessCIDRs, cfg.VPC.PublicAccessCIDRs, addCIDRs, removeCIDRs)
+	if err != nil {
+		return err
+	}
+	added, removed := diffCIDRs(clusterVPCConfig.PublicAccessCIDRs, newCIDRs)
+	cidrsChanged := len(added) > 0 || len(removed) > 0
+
 	// Nothing changed?
-	if newPrivate == curPrivate && newPublic == curPublic {
+	if newPrivate == curPrivate && newPublic == curPublic && !cidrsChanged {
 		logger.Success("Kubernetes API endpoint access for cluster %q in %q is already up to date",
 			meta.Name, meta.Region)
 		return nil
 	}
 
+	if cidrsChanged && !newPublic {
+		return fmt.Errorf("public access CIDRs can only be updated when public access is enabled")
+	}
+
 	cfg.VPC.ClusterEndpoints.PrivateAccess = &newPrivate
 	cfg.VPC.ClusterEndpoints.PublicAccess = &newPublic
 
-	cmdutils.LogIntendedAction(
-		cmd.Plan, "update Kubernetes API endpoint access for cluster %q in %q to: privateAccess=%v, publicAccess=%v",
-		meta.Name, meta.Region, newPrivate, newPublic)
+	if newPrivate != curPrivate || newPublic != curPublic {
+		cmdutils.LogIntendedAction(
+			cmd.Plan, "update Kubernetes API endpoint access for cluster %q in %q to: privateAccess=%v, publicAccess=%v",
+			meta.Name, meta.Region, newPrivate, newPublic)
 
-	if err := cfg.ValidateClusterEndpointConfig(); err != nil {
-		// Error for everything except private-only (which leaves the cluster accessible)
-		if err != api.ErrClusterEndpointPrivateOnly {
-			return err
+		if err := cfg.ValidateClusterEndpointConfig(); err != nil {
+			// Error for everything except private-only (which leaves the cluster accessible)
+			if err != api.ErrClusterEndpointPrivateOnly {
+				return err
+			}
+			logger.Warning(err.Error())
+		}
+
+		if !cmd.Plan {
+			if err := ctl.UpdateClusterConfigForEndpoints(cfg); err != nil {
+				return err
+			}
+			cmdutils.LogCompletedAction(
+				false,
+				"the Kubernetes API endpoint access for cluster %q in %q has been updated to: "+
+					"privateAccess=%v, publicAccess=%v",
+				meta.Name, meta.Region, newPrivate, newPublic)
 		}
-		logger.Warning(err.Error())
 	}
 
-	if !cmd.Plan {
-		if err := ctl.UpdateClusterConfigForEndpoints(cfg); err != nil {
-			return err
+	if cidrsChanged {
+		cmdutils.LogIntendedAction(cmd.Plan, "update public access CIDRs for cluster %q in %q", meta.Name, meta.Region)
+		for _, cidr := range added {
+			logger.Info("+ %s", cidr)
+		}
+		for _, cidr := range removed {
+			logger.Info("- %s", cidr)
+		}
+
+		if !cmd.Plan {
+			cfg.VPC.PublicAccessCIDRs = newCIDRs
+			if err := ctl.UpdatePublicAccessCIDRs(cfg); err != nil {
+				return err
+			}
+			cmdutils.LogCompletedAction(false, "public access CIDRs for cluster %q in %q have been updated to: %v",
+				meta.Name, meta.Region, newCIDRs)
 		}
-		cmdutils.LogCompletedAction(
-			false,
-			"the Kubernetes API endpoint access for cluster %q in %q has been updated to: "+
-				"privateAccess=%v, publicAccess=%v",
-			meta.Name, meta.Region, newPrivate, newPublic)
 	}
 	cmdutils.LogPlanModeWarning(cmd.Plan)
 
 	return nil
 }
+
+// desiredPublicAccessCIDRs returns the public access CIDRs the cluster should have, either
+// the full set given in the config file, or the current set with the given CIDRs added and
+// removed; it returns current when no changes are requested
+func desiredPublicAccessCIDRs(current, fromConfig, add, remove []string) ([]string, error) {
+	if fromConfig != nil {
+		return fromConfig, nil
+	}
+	if len(add) == 0 && len(remove) == 0 {
+		return current, nil
+	}
+
+	add, err := normalizeCIDRs(add)
+	if err != nil {
+		return nil, err
+	}
+	remove, err = normalizeCIDRs(remove)
+	if err != nil {
+		return nil, err
+	}
+	if both := sets.NewString(add...).Intersection(sets.NewString(remove...)); both.Len() > 0 {
+		return nil, fmt.Errorf("CIDRs %v cannot be both added and removed", both.List())
+	}
+
+	cidrs := sets.NewString(current...).Insert(add...).Delete(remove...)
+	if cidrs.Len() == 0 {
+		return nil, fmt.Errorf("cannot remove all public access CIDRs, disable public access instead")
+	}
+	return cidrs.List(), nil
+}
+
+func normalizeCIDRs(cidrs []string) ([]string, error) {
+	var normalized []string
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, ipNet.String())
+	}
+	return normalized, nil
+}
+
+// diffCIDRs returns the CIDRs of desired that are not in current and
+// the CIDRs of current that are not in desired
+func diffCIDRs(current, desired []string) (added, removed []string) {
+	currentSet, desiredSet := sets.NewString(current...), sets.NewString(desired...)
+	return desiredSet.Difference(currentSet).List(), currentSet.Difference(desiredSet).List()
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("update-cluster-endpoints public access CIDRs", func() {
+	current := []string{"1.1.1.1/32", "2.2.2.0/24"}
+
+	It("keeps the current CIDRs when no changes are requested", func() {
+		cidrs, err := desiredPublicAccessCIDRs(current, nil, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cidrs).To(Equal(current))
+	})
+
+	It("syncs the full set from the config file", func() {
+		cidrs, err := desiredPublicAccessCIDRs(current, []string{"3.3.3.3/32"}, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cidrs).To(Equal([]string{"3.3.3.3/32"}))
+	})
+
+	It("adds and removes normalized CIDRs", func() {
+		cidrs, err := desiredPublicAccessCIDRs(current, nil, []string{"3.3.3.3/24"}, []string{"1.1.1.1/32"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cidrs).To(Equal([]string{"2.2.2.0/24", "3.3.3.0/24"}))
+
+		added, removed := diffCIDRs(current, cidrs)
+		Expect(added).To(Equal([]string{"3.3.3.0/24"}))
+		Expect(removed).To(Equal([]string{"1.1.1.1/32"}))
+	})
+
+	It("rejects invalid changes", func() {
+		_, err := desiredPublicAccessCIDRs(current, nil, []string{"3.3.3.3"}, nil)
+		Expect(err).To(MatchError(ContainSubstring("invalid CIDR address")))
+
+		_, err = desiredPublicAccessCIDRs(current, nil, []string{"3.3.3.3/32"}, []string{"3.3.3.3/32"})
+		Expect(err).To(MatchError("CIDRs [3.3.3.3/32] cannot be both added and removed"))
+
+		_, err = desiredPublicAccessCIDRs(current, nil, nil, current)
+		Expect(err).To(MatchError("cannot remove all public access CIDRs, disable public access instead"))
+	})
+})
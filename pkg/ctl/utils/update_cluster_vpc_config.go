@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/vpc"
+)
+
+func updateClusterVPCConfigCmd(cmd *cmdutils.Cmd) {
+	updateClusterVPCConfigWithRunFunc(cmd, doUpdateClusterVPCConfig)
+}
+
+func updateClusterVPCConfigWithRunFunc(cmd *cmdutils.Cmd, runFunc func(cmd *cmdutils.Cmd) error) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	cmd.SetDescription("update-cluster-vpc-config", "Update the subnets and security groups of the control plane",
+		"Move the network interfaces of the control plane to other subnets of the VPC of the cluster, or attach other security groups to them")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		if err := cmdutils.NewUtilsUpdateClusterVPCConfigLoader(cmd).Load(); err != nil {
+			return err
+		}
+		return runFunc(cmd)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		cmdutils.AddApproveFlag(fs, cmd)
+		cmdutils.AddTimeoutFlag(fs, &cmd.ProviderConfig.WaitTimeout)
+	})
+
+	cmd.FlagSetGroup.InFlagSet("VPC", func(fs *pflag.FlagSet) {
+		fs.StringSliceVar(&cfg.VPC.ControlPlaneSubnetIDs, "subnets", nil, "subnets of the VPC of the cluster to place the network interfaces of the control plane in, spanning at least 2 of its availability zones")
+		fs.StringSliceVar(&cfg.VPC.SecurityGroupIDs, "security-groups", nil, "security groups of the VPC of the cluster to attach to the network interfaces of the control plane")
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doUpdateClusterVPCConfig(cmd *cmdutils.Cmd) error {
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	logger.Info("using region %s", meta.Region)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	if ok, err := ctl.CanUpdate(cfg); !ok {
+		return err
+	}
+
+	clusterVPCConfig, err := ctl.GetCurrentClusterVPCConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("current subnets: %v, security groups: %v", clusterVPCConfig.SubnetIDs, clusterVPCConfig.SecurityGroupIDs)
+
+	subnetIDs := changedIDs(clusterVPCConfig.SubnetIDs, cfg.VPC.ControlPlaneSubnetIDs)
+	securityGroupIDs := changedIDs(clusterVPCConfig.SecurityGroupIDs, cfg.VPC.SecurityGroupIDs)
+	if subnetIDs == nil && securityGroupIDs == nil {
+		logger.Success("the VPC configuration of cluster %q in %q is already up to date", meta.Name, meta.Region)
+		return nil
+	}
+
+	if err := vpc.ValidateClusterVPCConfigUpdate(ctl.Provider, clusterVPCConfig.VPCID, clusterVPCConfig.SubnetIDs, subnetIDs, securityGroupIDs); err != nil {
+		return err
+	}
+
+	if subnetIDs != nil {
+		cmdutils.LogIntendedAction(cmd.Plan, "update the subnets of cluster %q in %q to: %v", meta.Name, meta.Region, subnetIDs)
+	}
+	if securityGroupIDs != nil {
+		cmdutils.LogIntendedAction(cmd.Plan, "update the security groups of cluster %q in %q to: %v", meta.Name, meta.Region, securityGroupIDs)
+	}
+
+	if !cmd.Plan {
+		if err := ctl.UpdateClusterVPCConfig(cfg, subnetIDs, securityGroupIDs); err != nil {
+			return errors.Wrap(err, "updating the VPC configuration of the cluster")
+		}
+		cmdutils.LogCompletedAction(false, "the VPC configuration of cluster %q in %q has been updated", meta.Name, meta.Region)
+	}
+	cmdutils.LogPlanModeWarning(cmd.Plan)
+	return nil
+}
+
+// changedIDs returns desired when it's set and differs from current, and nil otherwise
+func changedIDs(current, desired []string) []string {
+	if len(desired) == 0 || sets.NewString(current...).Equal(sets.NewString(desired...)) {
+		return nil
+	}
+	return desired
+}
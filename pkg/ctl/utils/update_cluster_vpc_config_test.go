@@ -0,0 +1,19 @@
+package utils
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("update-cluster-vpc-config", func() {
+	current := []string{"subnet-1", "subnet-2"}
+
+	It("leaves unset and unchanged IDs as they are", func() {
+		Expect(changedIDs(current, nil)).To(BeNil())
+		Expect(changedIDs(current, []string{"subnet-2", "subnet-1"})).To(BeNil())
+	})
+
+	It("returns the IDs that changed", func() {
+		Expect(changedIDs(current, []string{"subnet-1", "subnet-3"})).To(Equal([]string{"subnet-1", "subnet-3"}))
+	})
+})
@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+func updateOIDCThumbprintsCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	cmd.SetDescription("update-oidc-thumbprints", "Update the thumbprints of the IAM OIDC provider of a cluster",
+		"Re-fetch the CA thumbprints of the OIDC issuer of the cluster and set them on the IAM OIDC provider, e.g. after the certificate chain of the issuer changed")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doUpdateOIDCThumbprints(cmd)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		cmdutils.AddApproveFlag(fs, cmd)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doUpdateOIDCThumbprints(cmd *cmdutils.Cmd) error {
+	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(meta)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	if ok, err := ctl.CanOperate(cfg); !ok {
+		return err
+	}
+
+	oidc, err := ctl.NewOpenIDConnectManager(cfg)
+	if err != nil {
+		return err
+	}
+
+	providerExists, err := oidc.CheckProviderExists()
+	if err != nil {
+		return err
+	}
+	if !providerExists {
+		return fmt.Errorf("no IAM OIDC provider is associated with cluster %q in %q, use 'eksctl utils associate-iam-oidc-provider' to create it", meta.Name, meta.Region)
+	}
+
+	current := oidc.ProviderThumbprints()
+	thumbprints, err := oidc.IssuerCAThumbprints()
+	if err != nil {
+		return err
+	}
+	if sameThumbprints(current, thumbprints) {
+		logger.Info("the thumbprints of the IAM OIDC provider of cluster %q in %q are up to date", meta.Name, meta.Region)
+		return nil
+	}
+
+	cmdutils.LogIntendedAction(cmd.Plan, "update the thumbprints of the IAM OIDC provider of cluster %q in %q from [%s] to [%s]",
+		meta.Name, meta.Region, strings.Join(current, ", "), strings.Join(thumbprints, ", "))
+	if !cmd.Plan {
+		if err := oidc.UpdateThumbprints(); err != nil {
+			return err
+		}
+		logger.Success("updated the thumbprints of the IAM OIDC provider of cluster %q in %q", meta.Name, meta.Region)
+	}
+
+	cmdutils.LogPlanModeWarning(cmd.Plan)
+
+	return nil
+}
+
+func sameThumbprints(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = append([]string{}, a...), append([]string{}, b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if !strings.EqualFold(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
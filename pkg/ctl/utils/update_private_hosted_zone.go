@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+func updatePrivateHostedZoneCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var vpcIDs []string
+
+	cmd.SetDescription("update-private-hosted-zone", "Create or update the private hosted zone of the API server endpoint",
+		"Point the API server endpoint to its current private IPs in a Route 53 private hosted zone associated with other VPCs, e.g. peered ones, creating the hosted zone if needed")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doUpdatePrivateHostedZone(cmd, vpcIDs)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		fs.StringSliceVar(&vpcIDs, "vpc-ids", nil, "VPCs to associate the hosted zone with, instead of vpc.privateHostedZone.associatedVPCIDs")
+		cmdutils.AddApproveFlag(fs, cmd)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doUpdatePrivateHostedZone(cmd *cmdutils.Cmd, vpcIDs []string) error {
+	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	if len(vpcIDs) > 0 {
+		cfg.VPC.PrivateHostedZone = &api.PrivateHostedZone{AssociatedVPCIDs: vpcIDs}
+	}
+	if !cfg.HasPrivateHostedZone() || len(cfg.VPC.PrivateHostedZone.AssociatedVPCIDs) == 0 {
+		return fmt.Errorf("--vpc-ids or vpc.privateHostedZone.associatedVPCIDs must be set")
+	}
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(meta)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	if ok, err := ctl.CanUpdate(cfg); !ok {
+		return err
+	}
+
+	if cmd.Plan {
+		logger.Info("(plan) would point the API server endpoint of cluster %q to its private IPs in VPCs %v", meta.Name, cfg.VPC.PrivateHostedZone.AssociatedVPCIDs)
+		cmdutils.LogPlanModeWarning(true)
+		return nil
+	}
+
+	if err := ctl.SyncPrivateHostedZone(cfg); err != nil {
+		return errors.Wrapf(err, "updating the private hosted zone of cluster %q", meta.Name)
+	}
+	return nil
+}
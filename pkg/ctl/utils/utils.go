@@ -16,12 +16,31 @@ func Command(flagGrouping *cmdutils.FlagGrouping) *cobra.Command {
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, updateKubeProxyCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, updateAWSNodeCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, updateCoreDNSCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, migrateToManagedAddonsCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, updateLegacySubnetSettings)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, enableLoggingCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, associateIAMOIDCProviderCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, updateOIDCThumbprintsCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, installWindowsVPCController)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, installNodeTerminationHandlerCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, installGMSAWebhookCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, updateClusterEndpointsCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, updateAuthenticationModeCmd)
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, publicAccessCIDRsCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, updateClusterVPCConfigCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, updatePrivateHostedZoneCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, repairStacksCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, detectDriftCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, retagClusterCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, rotateCertificatesCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, listPluginsCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, checkARMCompatibilityCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, checkSecurityPostureCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, ipUsageCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, previewIPv6MigrationCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, verifyClusterCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, listImagesCmd)
+	cmdutils.AddResourceCmd(flagGrouping, verbCmd, requiredIAMCmd)
 
 	cmdutils.AddResourceCmd(flagGrouping, verbCmd, nodeGroupHealthCmd)
 
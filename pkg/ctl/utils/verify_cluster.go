@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/printers"
+	"github.com/weaveworks/eksctl/pkg/verify"
+)
+
+func verifyClusterCmd(cmd *cmdutils.Cmd) {
+	cfg := api.NewClusterConfig()
+	cmd.ClusterConfig = cfg
+
+	var output string
+	options := verify.Options{}
+
+	cmd.SetDescription("verify-cluster", "Run smoke tests against a cluster",
+		"Run a short-lived test workload that checks pods get scheduled on every nodegroup and availability zone, resolve DNS, pull images from ECR and get volumes from the default storage class")
+
+	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
+		cmd.NameArg = cmdutils.GetNameArg(args)
+		return doVerifyCluster(cmd, options, output)
+	}
+
+	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
+		cmdutils.AddClusterFlag(fs, cfg.Metadata)
+		cmdutils.AddRegionFlag(fs, cmd.ProviderConfig)
+		cmdutils.AddConfigFileFlag(fs, cmd)
+		fs.StringVarP(&output, "output", "o", "table", "specifies the output format (valid option: table, json, yaml)")
+		fs.StringVar(&options.Namespace, "namespace", verify.DefaultNamespace, "namespace to run the test workload in, it's deleted afterwards")
+		fs.StringVar(&options.Image, "image", verify.DefaultImage, "image of the test pods, it must provide nslookup and sh")
+		fs.StringVar(&options.ECRImage, "ecr-image", "", "image to check pulling from ECR with (defaults to the EKS pause image of the region)")
+		cmdutils.AddTimeoutFlagWithValue(fs, &options.Timeout, 5*time.Minute)
+	})
+
+	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
+}
+
+func doVerifyCluster(cmd *cmdutils.Cmd, options verify.Options, output string) error {
+	if err := cmdutils.NewMetadataLoader(cmd).Load(); err != nil {
+		return err
+	}
+
+	cfg := cmd.ClusterConfig
+	meta := cmd.ClusterConfig.Metadata
+
+	printer, err := printers.NewPrinter(output)
+	if err != nil {
+		return err
+	}
+
+	ctl, err := cmd.NewCtl()
+	if err != nil {
+		return err
+	}
+	cmdutils.LogRegionAndVersionInfo(meta)
+
+	if err := ctl.CheckAuth(); err != nil {
+		return err
+	}
+
+	if ok, err := ctl.CanOperate(cfg); !ok {
+		return err
+	}
+
+	report, err := ctl.VerifyCluster(cfg, options)
+	if err != nil {
+		return err
+	}
+
+	if output == printers.TableType {
+		addVerifyClusterTableColumns(printer.(*printers.TablePrinter))
+		if err := printer.PrintObjWithKind("checks", report.Results, os.Stdout); err != nil {
+			return err
+		}
+	} else if err := printer.PrintObjWithKind("report", report, os.Stdout); err != nil {
+		return err
+	}
+
+	if !report.Passed {
+		return fmt.Errorf("cluster %q failed verification", meta.Name)
+	}
+	logger.Success("cluster %q passed verification", meta.Name)
+	return nil
+}
+
+func addVerifyClusterTableColumns(printer *printers.TablePrinter) {
+	printer.AddColumn("CHECK", func(r verify.Result) string {
+		return r.Name
+	})
+	printer.AddColumn("TARGET", func(r verify.Result) string {
+		return r.Target
+	})
+	printer.AddColumn("STATUS", func(r verify.Result) string {
+		return r.Status
+	})
+	printer.AddColumn("MESSAGE", func(r verify.Result) string {
+		return r.Message
+	})
+}
@@ -2,32 +2,44 @@ package utils
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/ctl/cmdutils"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/utils/kubeconfig"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
+// writeKubeconfigOptions holds the flag values of write-kubeconfig
+type writeKubeconfigOptions struct {
+	outputPath           string
+	authenticator        string
+	authenticatorRoleARN string
+	authenticatorProfile string
+	contextNameTemplate  string
+	serviceAccount       string
+	setContext, autoPath bool
+}
+
 func writeKubeconfigCmd(cmd *cmdutils.Cmd) {
 	cfg := api.NewClusterConfig()
 	cmd.ClusterConfig = cfg
 
-	var (
-		outputPath           string
-		authenticatorRoleARN string
-		setContext, autoPath bool
-	)
+	var options writeKubeconfigOptions
 
 	cmd.SetDescription("write-kubeconfig", "Write kubeconfig file for a given cluster", "")
 
 	cmd.CobraCommand.RunE = func(_ *cobra.Command, args []string) error {
 		cmd.NameArg = cmdutils.GetNameArg(args)
-		return doWriteKubeconfigCmd(cmd, outputPath, authenticatorRoleARN, setContext, autoPath)
+		return doWriteKubeconfigCmd(cmd, options)
 	}
 
 	cmd.FlagSetGroup.InFlagSet("General", func(fs *pflag.FlagSet) {
@@ -37,13 +49,17 @@ func writeKubeconfigCmd(cmd *cmdutils.Cmd) {
 	})
 
 	cmd.FlagSetGroup.InFlagSet("Output kubeconfig", func(fs *pflag.FlagSet) {
-		cmdutils.AddCommonFlagsForKubeconfig(fs, &outputPath, &authenticatorRoleARN, &setContext, &autoPath, "<name>")
+		cmdutils.AddCommonFlagsForKubeconfig(fs, &options.outputPath, &options.authenticatorRoleARN, &options.setContext, &options.autoPath, "<name>")
+		fs.StringVar(&options.authenticator, "authenticator", "", fmt.Sprintf("authenticator command to use in kubeconfig, one of: %s (default: detected from PATH)", strings.Join(kubeconfig.AuthenticatorCommands(), ", ")))
+		fs.StringVar(&options.authenticatorProfile, "authenticator-profile", "", "AWS profile to pin in the authenticator environment (default: the value of --profile)")
+		fs.StringVar(&options.contextNameTemplate, "context-name-template", "", "Go template for the context name, with the fields .ClusterName, .Region and .Username, e.g. \"{{ .ClusterName }}-{{ .Region }}\"")
+		fs.StringVar(&options.serviceAccount, "service-account", "", "write a kubeconfig authenticating with the token of the given <namespace>/<name> serviceaccount, which is created if it doesn't exist; intended for CI systems")
 	})
 
 	cmdutils.AddCommonFlagsForAWS(cmd.FlagSetGroup, cmd.ProviderConfig, false)
 }
 
-func doWriteKubeconfigCmd(cmd *cmdutils.Cmd, outputPath, roleARN string, setContext, autoPath bool) error {
+func doWriteKubeconfigCmd(cmd *cmdutils.Cmd, options writeKubeconfigOptions) error {
 	cfg := cmd.ClusterConfig
 
 	// TODO: move this into a loader when --config-file gets added to this command
@@ -59,18 +75,40 @@ func doWriteKubeconfigCmd(cmd *cmdutils.Cmd, outputPath, roleARN string, setCont
 		return cmdutils.ErrMustBeSet(cmdutils.ClusterNameFlag(cmd))
 	}
 
-	if autoPath {
+	outputPath := options.outputPath
+	if options.autoPath {
 		if outputPath != kubeconfig.DefaultPath {
 			return fmt.Errorf("--kubeconfig and --auto-kubeconfig %s", cmdutils.IncompatibleFlags)
 		}
 		outputPath = kubeconfig.AutoPath(cfg.Metadata.Name)
 	}
+	toStdout := outputPath == "-"
+
+	if options.authenticator != "" && !isAuthenticatorCommand(options.authenticator) {
+		return fmt.Errorf("--authenticator must be one of: %s", strings.Join(kubeconfig.AuthenticatorCommands(), ", "))
+	}
+
+	var serviceAccount metav1.ObjectMeta
+	if options.serviceAccount != "" {
+		parts := strings.Split(options.serviceAccount, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("--service-account must be given as <namespace>/<name>")
+		}
+		serviceAccount = metav1.ObjectMeta{Namespace: parts[0], Name: parts[1]}
+
+		if options.authenticator != "" || options.authenticatorRoleARN != "" || options.authenticatorProfile != "" {
+			return fmt.Errorf("--service-account and --authenticator, --authenticator-role-arn or --authenticator-profile %s", cmdutils.IncompatibleFlags)
+		}
+	}
 
 	ctl, err := cmd.NewCtl()
 	if err != nil {
 		return err
 	}
-	cmdutils.LogRegionAndVersionInfo(cfg.Metadata)
+	// the kubeconfig is the only output when writing to stdout
+	if !toStdout {
+		cmdutils.LogRegionAndVersionInfo(cfg.Metadata)
+	}
 
 	if err := ctl.CheckAuth(); err != nil {
 		return err
@@ -80,8 +118,44 @@ func doWriteKubeconfigCmd(cmd *cmdutils.Cmd, outputPath, roleARN string, setCont
 		return err
 	}
 
-	kubectlConfig := kubeconfig.NewForKubectl(cfg, ctl.GetUsername(), roleARN, ctl.Provider.Profile())
-	filename, err := kubeconfig.Write(outputPath, *kubectlConfig, setContext)
+	var kubectlConfig *clientcmdapi.Config
+	if options.serviceAccount != "" {
+		clientSet, err := ctl.NewStdClientSet(cfg)
+		if err != nil {
+			return err
+		}
+		if err := kubernetes.MaybeCreateServiceAccountOrUpdateMetadata(clientSet, serviceAccount); err != nil {
+			return err
+		}
+		token, err := kubernetes.WaitForServiceAccountToken(clientSet, serviceAccount, ctl.Provider.WaitTimeout())
+		if err != nil {
+			return err
+		}
+		kubectlConfig = kubeconfig.NewForServiceAccountToken(cfg, options.serviceAccount, token)
+	} else {
+		profile := options.authenticatorProfile
+		if profile == "" {
+			profile = ctl.Provider.Profile()
+		}
+		kubectlConfig = kubeconfig.NewForAuthenticator(cfg, ctl.GetUsername(), options.authenticator, options.authenticatorRoleARN, profile)
+	}
+
+	if options.contextNameTemplate != "" {
+		data := kubeconfig.ContextNameData{
+			ClusterName: cfg.Metadata.Name,
+			Region:      cfg.Metadata.Region,
+			Username:    ctl.GetUsername(),
+		}
+		if err := kubeconfig.RenameContext(kubectlConfig, options.contextNameTemplate, data); err != nil {
+			return err
+		}
+	}
+
+	if toStdout {
+		return kubeconfig.WriteTo(os.Stdout, *kubectlConfig)
+	}
+
+	filename, err := kubeconfig.Write(outputPath, *kubectlConfig, options.setContext)
 	if err != nil {
 		return errors.Wrap(err, "writing kubeconfig")
 	}
@@ -90,3 +164,12 @@ func doWriteKubeconfigCmd(cmd *cmdutils.Cmd, outputPath, roleARN string, setCont
 
 	return nil
 }
+
+func isAuthenticatorCommand(authenticator string) bool {
+	for _, command := range kubeconfig.AuthenticatorCommands() {
+		if authenticator == command {
+			return true
+		}
+	}
+	return false
+}
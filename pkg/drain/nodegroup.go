@@ -2,11 +2,12 @@ package drain
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -164,6 +165,42 @@ func NodeGroup(clientSet kubernetes.Interface, ng eks.KubeNodeGroup, waitTimeout
 	}
 }
 
+// NodeGroups drains the nodegroups, up to parallelism of them at the same time; a nodegroup
+// that fails to drain doesn't stop the others, and all errors are returned
+func NodeGroups(clientSet kubernetes.Interface, nodeGroups []eks.KubeNodeGroup, parallelism int, waitTimeout time.Duration, undo bool) []error {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	action := "draining"
+	if undo {
+		action = "uncordoning"
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		errs      []error
+		semaphore = make(chan struct{}, parallelism)
+	)
+	for _, ng := range nodeGroups {
+		wg.Add(1)
+		go func(ng eks.KubeNodeGroup) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			if err := NodeGroup(clientSet, ng, waitTimeout, undo); err != nil {
+				mu.Lock()
+				errs = append(errs, errors.Wrapf(err, "%s nodegroup %q", action, ng.NameString()))
+				mu.Unlock()
+			}
+		}(ng)
+	}
+	wg.Wait()
+	return errs
+}
+
 func cordonStatus(desired bool) string {
 	if desired {
 		return "cordon"
@@ -0,0 +1,171 @@
+package eks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	awsiam "github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/authconfigmap"
+	"github.com/weaveworks/eksctl/pkg/iam"
+)
+
+// the access config of clusters isn't part of the version of aws-sdk-go eksctl uses yet
+
+type accessConfigResponse struct {
+	_ struct{} `type:"structure"`
+
+	AuthenticationMode *string `locationName:"authenticationMode" type:"string"`
+}
+
+type clusterAccessConfig struct {
+	_ struct{} `type:"structure"`
+
+	AccessConfig *accessConfigResponse `locationName:"accessConfig" type:"structure"`
+}
+
+type describeClusterAccessConfigInput struct {
+	_ struct{} `type:"structure"`
+
+	Name *string `location:"uri" locationName:"name" type:"string" required:"true"`
+}
+
+type describeClusterAccessConfigOutput struct {
+	_ struct{} `type:"structure"`
+
+	Cluster *clusterAccessConfig `locationName:"cluster" type:"structure"`
+}
+
+type updateAccessConfigRequest struct {
+	_ struct{} `type:"structure"`
+
+	AuthenticationMode *string `locationName:"authenticationMode" type:"string"`
+}
+
+type updateClusterAccessConfigInput struct {
+	_ struct{} `type:"structure"`
+
+	Name         *string                    `location:"uri" locationName:"name" type:"string" required:"true"`
+	AccessConfig *updateAccessConfigRequest `locationName:"accessConfig" type:"structure"`
+}
+
+type updateClusterAccessConfigOutput struct {
+	_ struct{} `type:"structure"`
+
+	Update *awseks.Update `locationName:"update" type:"structure"`
+}
+
+func (c *ClusterProvider) eksClient() (*awseks.EKS, error) {
+	client, ok := c.Provider.EKS().(*awseks.EKS)
+	if !ok {
+		return nil, errors.New("the EKS client doesn't support raw API requests")
+	}
+	return client, nil
+}
+
+// GetAuthenticationMode returns the authentication mode of the cluster, clusters created
+// before access entries were introduced use the aws-auth ConfigMap only
+func (c *ClusterProvider) GetAuthenticationMode(clusterName string) (string, error) {
+	client, err := c.eksClient()
+	if err != nil {
+		return "", err
+	}
+	op := &request.Operation{
+		Name:       "DescribeCluster",
+		HTTPMethod: "GET",
+		HTTPPath:   "/clusters/{name}",
+	}
+	output := &describeClusterAccessConfigOutput{}
+	if err := client.NewRequest(op, &describeClusterAccessConfigInput{Name: &clusterName}, output).Send(); err != nil {
+		return "", errors.Wrapf(err, "describing cluster %q", clusterName)
+	}
+	if output.Cluster == nil || output.Cluster.AccessConfig == nil || output.Cluster.AccessConfig.AuthenticationMode == nil {
+		return api.AuthenticationModeConfigMap, nil
+	}
+	return *output.Cluster.AccessConfig.AuthenticationMode, nil
+}
+
+// UpdateAuthenticationMode calls eks.UpdateClusterConfig and updates the authentication mode
+// to cfg.AccessConfig.AuthenticationMode
+func (c *ClusterProvider) UpdateAuthenticationMode(cfg *api.ClusterConfig) error {
+	client, err := c.eksClient()
+	if err != nil {
+		return err
+	}
+	op := &request.Operation{
+		Name:       "UpdateClusterConfig",
+		HTTPMethod: "POST",
+		HTTPPath:   "/clusters/{name}/update-config",
+	}
+	input := &updateClusterAccessConfigInput{
+		Name: &cfg.Metadata.Name,
+		AccessConfig: &updateAccessConfigRequest{
+			AuthenticationMode: &cfg.AccessConfig.AuthenticationMode,
+		},
+	}
+	output := &updateClusterAccessConfigOutput{}
+	if err := client.NewRequest(op, input, output).Send(); err != nil {
+		return err
+	}
+	return c.waitForUpdateToSucceed(cfg.Metadata.Name, output.Update)
+}
+
+// ValidateAuthenticationModeChange checks that the authentication mode of a cluster can be
+// changed from current to desired, which EKS only allows towards API
+func ValidateAuthenticationModeChange(current, desired string) error {
+	index := func(mode string) int {
+		for i, m := range api.SupportedAuthenticationModes() {
+			if m == mode {
+				return i
+			}
+		}
+		return -1
+	}
+	if index(desired) < index(current) {
+		return fmt.Errorf("cannot change the authentication mode from %s to %s, it can only be changed towards %s",
+			current, desired, api.AuthenticationModeAPI)
+	}
+	return nil
+}
+
+// ActiveMappedRoles returns the ARNs of the roles mapped in the aws-auth ConfigMap that
+// still exist, which lose access to the cluster once the authentication mode is API
+func ActiveMappedRoles(clientSet kubernetes.Interface, iamAPI iamiface.IAMAPI) ([]string, error) {
+	acm, err := authconfigmap.NewFromClientSet(clientSet)
+	if err != nil {
+		return nil, err
+	}
+	identities, err := acm.Identities()
+	if err != nil {
+		return nil, err
+	}
+
+	var active []string
+	for _, identity := range identities {
+		if identity.Type() != iam.ResourceTypeRole {
+			continue
+		}
+		roleARN, err := iam.Parse(identity.ARN())
+		if err != nil {
+			return nil, err
+		}
+		// the resource is role/<path>/<name>
+		roleName := roleARN.Resource[strings.LastIndex(roleARN.Resource, "/")+1:]
+		if _, err := iamAPI.GetRole(&awsiam.GetRoleInput{RoleName: aws.String(roleName)}); err != nil {
+			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == awsiam.ErrCodeNoSuchEntityException {
+				continue
+			}
+			return nil, errors.Wrapf(err, "getting role %q", roleName)
+		}
+		active = append(active, identity.ARN())
+	}
+	return active, nil
+}
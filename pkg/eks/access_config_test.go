@@ -0,0 +1,79 @@
+package eks_test
+
+import (
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsiam "github.com/aws/aws-sdk-go/service/iam"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/authconfigmap"
+	. "github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("Access config", func() {
+	Describe("ValidateAuthenticationModeChange", func() {
+		It("allows changes towards API", func() {
+			Expect(ValidateAuthenticationModeChange(api.AuthenticationModeConfigMap, api.AuthenticationModeAPIAndConfigMap)).To(Succeed())
+			Expect(ValidateAuthenticationModeChange(api.AuthenticationModeAPIAndConfigMap, api.AuthenticationModeAPI)).To(Succeed())
+			Expect(ValidateAuthenticationModeChange(api.AuthenticationModeConfigMap, api.AuthenticationModeAPI)).To(Succeed())
+		})
+
+		It("rejects changes away from API", func() {
+			err := ValidateAuthenticationModeChange(api.AuthenticationModeAPI, api.AuthenticationModeAPIAndConfigMap)
+			Expect(err).To(MatchError("cannot change the authentication mode from API to API_AND_CONFIG_MAP, it can only be changed towards API"))
+			Expect(ValidateAuthenticationModeChange(api.AuthenticationModeAPIAndConfigMap, api.AuthenticationModeConfigMap)).NotTo(Succeed())
+		})
+	})
+
+	Describe("ActiveMappedRoles", func() {
+		var p *mockprovider.MockProvider
+
+		BeforeEach(func() {
+			p = mockprovider.NewMockProvider()
+		})
+
+		mockGetRole := func(roleName string, err error) {
+			p.MockIAM().On("GetRole", mock.MatchedBy(func(input *awsiam.GetRoleInput) bool {
+				return *input.RoleName == roleName
+			})).Return(&awsiam.GetRoleOutput{}, err)
+		}
+
+		It("returns the mapped roles that still exist", func() {
+			clientSet := fake.NewSimpleClientset(&corev1.ConfigMap{
+				ObjectMeta: authconfigmap.ObjectMeta(),
+				Data: map[string]string{
+					"mapRoles": `
+- rolearn: arn:aws:iam::123456789012:role/eksctl/node-role
+  username: system:node:{{EC2PrivateDNSName}}
+  groups: [system:bootstrappers, system:nodes]
+- rolearn: arn:aws:iam::123456789012:role/deleted-role
+  username: admin
+  groups: [system:masters]
+`,
+					"mapUsers": `
+- userarn: arn:aws:iam::123456789012:user/alice
+  username: alice
+  groups: [system:masters]
+`,
+				},
+			})
+			mockGetRole("node-role", nil)
+			mockGetRole("deleted-role", awserr.New(awsiam.ErrCodeNoSuchEntityException, "not found", nil))
+
+			roles, err := ActiveMappedRoles(clientSet, p.IAM())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(roles).To(Equal([]string{"arn:aws:iam::123456789012:role/eksctl/node-role"}))
+		})
+
+		It("returns no roles without the aws-auth ConfigMap", func() {
+			roles, err := ActiveMappedRoles(fake.NewSimpleClientset(), p.IAM())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(roles).To(BeEmpty())
+		})
+	})
+})
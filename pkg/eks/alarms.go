@@ -0,0 +1,60 @@
+package eks
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/builder"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+const (
+	apiServerErrorsFilterName = "eksctl-api-server-errors"
+	// apiServerErrorsFilterPattern matches the audit events of requests the API server failed
+	apiServerErrorsFilterPattern = "{ $.responseStatus.code >= 500 }"
+)
+
+// PutAPIServerErrorsMetricFilter counts the 5xx responses of the API server in the audit logs of
+// the control plane, in the metric of the API server alarm
+func (c *ClusterProvider) PutAPIServerErrorsMetricFilter(cfg *api.ClusterConfig) error {
+	return putAPIServerErrorsMetricFilter(c.Provider.CloudWatchLogs(), cfg.Metadata.Name)
+}
+
+func putAPIServerErrorsMetricFilter(cloudWatchLogs cloudwatchlogsiface.CloudWatchLogsAPI, clusterName string) error {
+	// EKS creates the log group once it delivers the first logs, the filter can't wait for it
+	logGroupName := fmt.Sprintf("/aws/eks/%s/cluster", clusterName)
+	_, err := cloudWatchLogs.CreateLogGroup(&cloudwatchlogs.CreateLogGroupInput{
+		LogGroupName: aws.String(logGroupName),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); !ok || awsErr.Code() != cloudwatchlogs.ErrCodeResourceAlreadyExistsException {
+			return errors.Wrapf(err, "creating log group %q", logGroupName)
+		}
+	}
+
+	namespace, metricName := builder.APIServerErrorsMetric(clusterName)
+	_, err = cloudWatchLogs.PutMetricFilter(&cloudwatchlogs.PutMetricFilterInput{
+		LogGroupName:  aws.String(logGroupName),
+		FilterName:    aws.String(apiServerErrorsFilterName),
+		FilterPattern: aws.String(apiServerErrorsFilterPattern),
+		MetricTransformations: []*cloudwatchlogs.MetricTransformation{
+			{
+				MetricNamespace: aws.String(namespace),
+				MetricName:      aws.String(metricName),
+				MetricValue:     aws.String("1"),
+				DefaultValue:    aws.Float64(0),
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "creating metric filter of API server errors in log group %q", logGroupName)
+	}
+	logger.Info("created metric filter %q of API server errors in log group %q", apiServerErrorsFilterName, logGroupName)
+	return nil
+}
@@ -1,9 +1,8 @@
 package eks
 
 import (
+	"bytes"
 	"fmt"
-	"io/ioutil"
-	"os"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -17,6 +16,9 @@ import (
 	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
 	"github.com/aws/aws-sdk-go/service/cloudtrail"
 	"github.com/aws/aws-sdk-go/service/cloudtrail/cloudtrailiface"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+	"github.com/aws/aws-sdk-go/service/costexplorer"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	awseks "github.com/aws/aws-sdk-go/service/eks"
@@ -25,13 +27,22 @@ import (
 	"github.com/aws/aws-sdk-go/service/elb/elbiface"
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/aws/aws-sdk-go/service/pricing/pricingiface"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
 	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -40,8 +51,17 @@ import (
 	"github.com/weaveworks/eksctl/pkg/ami"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/az"
+	"github.com/weaveworks/eksctl/pkg/billing"
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/cost"
+	"github.com/weaveworks/eksctl/pkg/eksaddons"
+	"github.com/weaveworks/eksctl/pkg/hooks"
+	"github.com/weaveworks/eksctl/pkg/inspector"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/metrics"
 	"github.com/weaveworks/eksctl/pkg/utils"
+	"github.com/weaveworks/eksctl/pkg/utils/configsource"
+	"github.com/weaveworks/eksctl/pkg/utils/prompt"
 	"github.com/weaveworks/eksctl/pkg/version"
 )
 
@@ -53,9 +73,21 @@ type ClusterProvider struct {
 	Status *ProviderStatus
 }
 
+// ExtendedProvider is implemented by the providers of the APIs eksctl defines itself, as the
+// version of aws-sdk-go it uses predates them; they aren't part of api.ClusterProvider as
+// the packages defining them depend on the api package
+type ExtendedProvider interface {
+	CostAllocationTags() billing.CostAllocationTagsAPI
+	Inspector() inspector.API
+	Addons() eksaddons.API
+}
+
 // ProviderServices stores the used APIs
 type ProviderServices struct {
-	spec  *api.ProviderConfig
+	spec *api.ProviderConfig
+	// session creates the clients of services used in other regions than spec.Region
+	session *session.Session
+
 	cfn   cloudformationiface.CloudFormationAPI
 	eks   eksiface.EKSAPI
 	ec2   ec2iface.EC2API
@@ -64,8 +96,19 @@ type ProviderServices struct {
 	sts   stsiface.STSAPI
 	ssm   ssmiface.SSMAPI
 	iam   iamiface.IAMAPI
+	kms   kmsiface.KMSAPI
+	sns   snsiface.SNSAPI
+
+	cloudtrail  cloudtrailiface.CloudTrailAPI
+	eventBridge eventbridgeiface.EventBridgeAPI
 
-	cloudtrail cloudtrailiface.CloudTrailAPI
+	costAllocationTags billing.CostAllocationTagsAPI
+	inspector          inspector.API
+	addons             eksaddons.API
+	route53            route53iface.Route53API
+
+	cloudWatchLogs cloudwatchlogsiface.CloudWatchLogsAPI
+	pricing        pricingiface.PricingAPI
 }
 
 // CloudFormation returns a representation of the CloudFormation API
@@ -98,6 +141,37 @@ func (p ProviderServices) IAM() iamiface.IAMAPI { return p.iam }
 // CloudTrail returns a representation of the CloudTrail API
 func (p ProviderServices) CloudTrail() cloudtrailiface.CloudTrailAPI { return p.cloudtrail }
 
+// KMS returns a representation of the KMS API
+func (p ProviderServices) KMS() kmsiface.KMSAPI { return p.kms }
+
+// SNS returns a representation of the SNS API
+func (p ProviderServices) SNS() snsiface.SNSAPI { return p.sns }
+
+// EventBridge returns a representation of the EventBridge API
+func (p ProviderServices) EventBridge() eventbridgeiface.EventBridgeAPI { return p.eventBridge }
+
+// Route53 returns a representation of the Route 53 API
+func (p ProviderServices) Route53() route53iface.Route53API { return p.route53 }
+
+// CloudWatchLogs returns a representation of the CloudWatch Logs API
+func (p ProviderServices) CloudWatchLogs() cloudwatchlogsiface.CloudWatchLogsAPI {
+	return p.cloudWatchLogs
+}
+
+// Pricing returns a representation of the Price List API
+func (p ProviderServices) Pricing() pricingiface.PricingAPI { return p.pricing }
+
+// CostAllocationTags returns a representation of the cost allocation tags operation of the Cost Explorer API
+func (p ProviderServices) CostAllocationTags() billing.CostAllocationTagsAPI {
+	return p.costAllocationTags
+}
+
+// Inspector returns a representation of the Amazon Inspector API
+func (p ProviderServices) Inspector() inspector.API { return p.inspector }
+
+// Addons returns a representation of the EKS add-ons API
+func (p ProviderServices) Addons() eksaddons.API { return p.addons }
+
 // Region returns provider-level region setting
 func (p ProviderServices) Region() string { return p.spec.Region }
 
@@ -107,11 +181,18 @@ func (p ProviderServices) Profile() string { return p.spec.Profile }
 // WaitTimeout returns provider-level duration after which any wait operation has to timeout
 func (p ProviderServices) WaitTimeout() time.Duration { return p.spec.WaitTimeout }
 
+// Timeout returns the duration after which the wait operations of a phase have to timeout
+func (p ProviderServices) Timeout(phase api.TimeoutPhase) time.Duration { return p.spec.Timeout(phase) }
+
 // ProviderStatus stores information about the used IAM role and the resulting session
 type ProviderStatus struct {
 	iamRoleARN   string
 	sessionCreds *credentials.Credentials
 	clusterInfo  *clusterInfo
+	// caBundle is trusted in addition to the CA of the cluster by Kubernetes clients
+	caBundle []byte
+	// checkedVersion is the control plane version the version support was last checked for
+	checkedVersion string
 }
 
 // New creates a new setup of the used AWS APIs
@@ -122,67 +203,53 @@ func New(spec *api.ProviderConfig, clusterSpec *api.ClusterConfig) *ClusterProvi
 	c := &ClusterProvider{
 		Provider: provider,
 	}
+	caBundle, err := readCABundle(spec)
+	if err != nil {
+		logger.Warning("ignoring CA bundle: %s", err.Error())
+	}
+
 	// Create a new session and save credentials for possible
 	// later re-use if overriding sessions due to custom URL
-	s := c.newSession(spec)
-
-	provider.cfn = cloudformation.New(s)
-	provider.eks = awseks.New(s)
-	provider.ec2 = ec2.New(s)
-	provider.elb = elb.New(s)
-	provider.elbv2 = elbv2.New(s)
+	s := c.newSession(spec, caBundle)
+	provider.session = s
+
+	provider.cfn = cloudformation.New(s, serviceConfig(s, spec, ServiceCloudFormation))
+	eksClient := awseks.New(s, serviceConfig(s, spec, ServiceEKS))
+	provider.eks = eksClient
+	provider.addons = eksaddons.New(eksClient)
+	provider.ec2 = ec2.New(s, serviceConfig(s, spec, ServiceEC2))
+	provider.elb = elb.New(s, serviceConfig(s, spec, ServiceELB))
+	provider.elbv2 = elbv2.New(s, serviceConfig(s, spec, ServiceELBV2))
 	provider.sts = sts.New(s,
 		// STS retrier has to be disabled, as it's not very helpful
 		// (see https://github.com/weaveworks/eksctl/issues/705)
-		request.WithRetryer(s.Config.Copy(),
+		request.WithRetryer(serviceConfig(s, spec, ServiceSTS),
 			&client.DefaultRetryer{
 				NumMaxRetries: 1,
 			},
 		),
 	)
-	provider.ssm = ssm.New(s)
-	provider.iam = iam.New(s)
-	provider.cloudtrail = cloudtrail.New(s)
+	provider.ssm = ssm.New(s, serviceConfig(s, spec, ServiceSSM))
+	provider.iam = iam.New(s, serviceConfig(s, spec, ServiceIAM))
+	provider.cloudtrail = cloudtrail.New(s, serviceConfig(s, spec, ServiceCloudTrail))
+	provider.kms = kms.New(s, serviceConfig(s, spec, ServiceKMS))
+	provider.sns = sns.New(s, serviceConfig(s, spec, ServiceSNS))
+	provider.eventBridge = eventbridge.New(s, serviceConfig(s, spec, ServiceEventBridge))
+	provider.costAllocationTags = billing.NewCostAllocationTagsAPI(costexplorer.New(s, serviceConfig(s, spec, ServiceCostExplorer)))
+	provider.inspector = inspector.New(s, serviceConfig(s, spec, ServiceInspector))
+	provider.route53 = route53.New(s, serviceConfig(s, spec, ServiceRoute53))
+	provider.cloudWatchLogs = cloudwatchlogs.New(s, serviceConfig(s, spec, ServiceCloudWatchLogs))
+	provider.pricing = pricing.New(s, serviceConfig(s, spec, ServicePricing).WithRegion(cost.PricingRegion))
+
+	if apiCache, ok := newAPICache(spec); ok {
+		scope := cacheScope(spec)
+		provider.ec2 = &cachingEC2{EC2API: provider.ec2, cache: apiCache, scope: scope}
+		provider.ssm = &cachingSSM{SSMAPI: provider.ssm, cache: apiCache, scope: scope}
+	}
 
 	c.Status = &ProviderStatus{
 		sessionCreds: s.Config.Credentials,
-	}
-
-	// override sessions if any custom endpoints specified
-	if endpoint, ok := os.LookupEnv("AWS_CLOUDFORMATION_ENDPOINT"); ok {
-		logger.Debug("Setting CloudFormation endpoint to %s", endpoint)
-		provider.cfn = cloudformation.New(s, s.Config.Copy().WithEndpoint(endpoint))
-	}
-	if endpoint, ok := os.LookupEnv("AWS_EKS_ENDPOINT"); ok {
-		logger.Debug("Setting EKS endpoint to %s", endpoint)
-		provider.eks = awseks.New(s, s.Config.Copy().WithEndpoint(endpoint))
-	}
-	if endpoint, ok := os.LookupEnv("AWS_EC2_ENDPOINT"); ok {
-		logger.Debug("Setting EC2 endpoint to %s", endpoint)
-		provider.ec2 = ec2.New(s, s.Config.Copy().WithEndpoint(endpoint))
-
-	}
-	if endpoint, ok := os.LookupEnv("AWS_ELB_ENDPOINT"); ok {
-		logger.Debug("Setting ELB endpoint to %s", endpoint)
-		provider.elb = elb.New(s, s.Config.Copy().WithEndpoint(endpoint))
-
-	}
-	if endpoint, ok := os.LookupEnv("AWS_ELBV2_ENDPOINT"); ok {
-		logger.Debug("Setting ELBV2 endpoint to %s", endpoint)
-		provider.elbv2 = elbv2.New(s, s.Config.Copy().WithEndpoint(endpoint))
-
-	}
-	if endpoint, ok := os.LookupEnv("AWS_STS_ENDPOINT"); ok {
-		logger.Debug("Setting STS endpoint to %s", endpoint)
-		provider.sts = sts.New(s, s.Config.Copy().WithEndpoint(endpoint))
-	}
-	if endpoint, ok := os.LookupEnv("AWS_IAM_ENDPOINT"); ok {
-		logger.Debug("Setting IAM endpoint to %s", endpoint)
-		provider.iam = iam.New(s, s.Config.Copy().WithEndpoint(endpoint))
-	}
-	if endpoint, ok := os.LookupEnv("AWS_CLOUDTRAIL_ENDPOINT"); ok {
-		logger.Debug("Setting CloudTrail endpoint to %s", endpoint)
-		provider.cloudtrail = cloudtrail.New(s, s.Config.Copy().WithEndpoint(endpoint))
+		caBundle:     caBundle,
 	}
 
 	if clusterSpec != nil {
@@ -192,13 +259,50 @@ func New(spec *api.ProviderConfig, clusterSpec *api.ClusterConfig) *ClusterProvi
 	return c
 }
 
+// serviceConfig returns the config of the clients of the service, which
+// overrides the endpoint of the service when a custom one is specified
+func serviceConfig(s *session.Session, spec *api.ProviderConfig, service string) *aws.Config {
+	config := s.Config.Copy()
+	if endpoint, ok := serviceEndpoint(spec, service); ok {
+		logger.Debug("setting %s endpoint to %s", service, endpoint)
+		config = config.WithEndpoint(endpoint)
+	}
+	return config
+}
+
+// ConfigFileOptions are the options for loading a config file
+type ConfigFileOptions struct {
+	// Template renders the config file as a template, which is implied by Vars
+	Template bool
+	// Vars are the variables used to render the config file as a template
+	Vars map[string]string
+	// Checksum is the expected SHA-256 digest of the config file
+	Checksum string
+	// AllowHooks allows the command hooks of a config file from a remote source without Checksum
+	AllowHooks bool
+}
+
 // LoadConfigFromFile loads ClusterConfig from configFile
 func LoadConfigFromFile(configFile string) (*api.ClusterConfig, error) {
-	data, err := readConfig(configFile)
+	return LoadConfigFromFileWithOptions(configFile, ConfigFileOptions{})
+}
+
+// LoadConfigFromFileWithOptions loads ClusterConfig from configFile, which can also be a
+// remote source; it's only rendered as a template when asked to or given variables, as
+// plain config files may contain "{{", e.g. in preBootstrapCommands
+func LoadConfigFromFileWithOptions(configFile string, options ConfigFileOptions) (*api.ClusterConfig, error) {
+	data, err := configsource.Read(configFile, options.Checksum)
 	if err != nil {
 		return nil, errors.Wrapf(err, "reading config file %q", configFile)
 	}
 
+	if options.Template || len(options.Vars) > 0 {
+		data, err = renderConfigTemplate(configFile, data, options.Vars)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// strict mode is not available in runtime.Decode, so we use the parser
 	// directly; we don't store the resulting object, this is just the means
 	// of detecting any unknown keys
@@ -217,14 +321,10 @@ func LoadConfigFromFile(configFile string) (*api.ClusterConfig, error) {
 	if !ok {
 		return nil, fmt.Errorf("expected to decode object of type %T; got %T", &api.ClusterConfig{}, cfg)
 	}
-	return cfg, nil
-}
-
-func readConfig(configFile string) ([]byte, error) {
-	if configFile == "-" {
-		return ioutil.ReadAll(os.Stdin)
+	if err := hooks.CheckConfigSource(cfg, configFile, options.Checksum != "", options.AllowHooks); err != nil {
+		return nil, err
 	}
-	return ioutil.ReadFile(configFile)
+	return cfg, nil
 }
 
 // IsSupportedRegion check if given region is supported
@@ -353,7 +453,7 @@ func (c *ClusterProvider) SetAvailabilityZones(spec *api.ClusterConfig, given []
 	return nil
 }
 
-func (c *ClusterProvider) newSession(spec *api.ProviderConfig) *session.Session {
+func (c *ClusterProvider) newSession(spec *api.ProviderConfig, caBundle []byte) *session.Session {
 	// we might want to use bits from kops, although right now it seems like too many thing we
 	// don't want yet
 	// https://github.com/kubernetes/kops/blob/master/upup/pkg/fi/cloudup/awsup/aws_cloud.go#L179
@@ -363,6 +463,11 @@ func (c *ClusterProvider) newSession(spec *api.ProviderConfig) *session.Session
 		config = config.WithRegion(c.Provider.Region()).WithSTSRegionalEndpoint(endpoints.RegionalSTSEndpoint)
 	}
 
+	if useFIPSEndpoints(spec) {
+		// FIPS endpoints are regional, so STS always uses the regional endpoint
+		config = config.WithEndpointResolver(fipsEndpointResolver()).WithSTSRegionalEndpoint(endpoints.RegionalSTSEndpoint)
+	}
+
 	config = request.WithRetryer(config, newLoggingRetryer())
 	if logger.Level >= api.AWSDebugLevel {
 		config = config.WithLogLevel(aws.LogDebug |
@@ -380,7 +485,10 @@ func (c *ClusterProvider) newSession(spec *api.ProviderConfig) *session.Session
 		Config:                  *config,
 		SharedConfigState:       session.SharedConfigEnable,
 		Profile:                 spec.Profile,
-		AssumeRoleTokenProvider: stscreds.StdinTokenProvider,
+		AssumeRoleTokenProvider: mfaTokenProvider,
+	}
+	if caBundle != nil {
+		opts.CustomCABundle = bytes.NewReader(caBundle)
 	}
 
 	stscreds.DefaultDuration = 30 * time.Minute
@@ -392,6 +500,7 @@ func (c *ClusterProvider) newSession(spec *api.ProviderConfig) *session.Session
 		Fn: request.MakeAddToUserAgentHandler(
 			"eksctl", version.String()),
 	})
+	metrics.InstrumentHandlers(&s.Handlers)
 
 	if spec.Region == "" {
 		if api.IsSetAndNonEmptyString(s.Config.Region) {
@@ -401,14 +510,22 @@ func (c *ClusterProvider) newSession(spec *api.ProviderConfig) *session.Session
 			// if session config doesn't have region set, make recursive call forcing default region
 			logger.Debug("no region specified in flags or config, setting to %s", api.DefaultRegion)
 			spec.Region = api.DefaultRegion
-			return c.newSession(spec)
+			return c.newSession(spec, caBundle)
 		}
 	}
 
-	return s
+	return assumeRoles(s, spec)
+}
+
+// mfaTokenProvider asks for the MFA token code of assumed roles, which can be
+// given up front with --answer=mfa-token=<code>
+func mfaTokenProvider() (string, error) {
+	return prompt.Ask("mfa-token", "Assume Role MFA token code")
 }
 
 // NewStackManager returns a new stack manager
 func (c *ClusterProvider) NewStackManager(spec *api.ClusterConfig) *manager.StackCollection {
-	return manager.NewStackCollection(c.Provider, spec)
+	stackManager := manager.NewStackCollection(c.Provider, spec)
+	stackManager.SetAccountProvider(c.NewAccountProvider)
+	return stackManager
 }
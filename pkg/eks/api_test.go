@@ -1,6 +1,8 @@
 package eks_test
 
 import (
+	"os"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ssm"
@@ -67,6 +69,67 @@ var _ = Describe("eksctl API", func() {
 			Expect(err.Error()).To(HavePrefix(`loading config file "testdata/old-version.json": no kind "ClusterConfig" is registered for version "eksctl.io/v1alpha3" in scheme`))
 		})
 
+		Context("with templates", func() {
+			BeforeEach(func() {
+				os.Setenv("EKSCTL_TEST_REGION", "eu-north-1")
+			})
+
+			AfterEach(func() {
+				os.Unsetenv("EKSCTL_TEST_REGION")
+			})
+
+			It("should render variables, environment variables and defaults", func() {
+				cfg, err := LoadConfigFromFileWithOptions("testdata/template.yaml", ConfigFileOptions{Vars: map[string]string{"env": "prod"}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cfg.Metadata.Name).To(Equal("cluster-prod"))
+				Expect(cfg.Metadata.Region).To(Equal("eu-north-1"))
+				Expect(cfg.NodeGroups).To(HaveLen(1))
+				Expect(cfg.NodeGroups[0].InstanceType).To(Equal("m5.large"))
+			})
+
+			It("should override defaults", func() {
+				cfg, err := LoadConfigFromFileWithOptions("testdata/template.yaml", ConfigFileOptions{Vars: map[string]string{"env": "dev", "instanceType": "t3.large"}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cfg.NodeGroups[0].InstanceType).To(Equal("t3.large"))
+			})
+
+			It("should load a plain config file containing {{ as it is", func() {
+				cfg, err := LoadConfigFromFile("testdata/braces.yaml")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cfg.NodeGroups[0].PreBootstrapCommands).To(ConsistOf(`echo '{{ not a template }}' > /etc/motd`))
+			})
+
+			It("should leave the placeholders of role names to be replaced after rendering", func() {
+				cfg, err := LoadConfigFromFileWithOptions("testdata/role-names.yaml", ConfigFileOptions{Vars: map[string]string{"env": "prod"}})
+				Expect(err).ToNot(HaveOccurred())
+				sa := cfg.IAM.ServiceAccounts[0]
+				Expect(sa.RoleName).To(Equal("{ClusterName}-{Namespace}-{Name}"))
+				Expect(sa.RenderRoleName(cfg.Metadata.Name)).To(Equal("cluster-prod-backend-apps-s3-reader"))
+			})
+
+			It("should leave the placeholders of the role names of add-ons to be replaced after rendering", func() {
+				cfg, err := LoadConfigFromFileWithOptions("testdata/role-names.yaml", ConfigFileOptions{Vars: map[string]string{"env": "prod"}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cfg.Addons).To(HaveLen(1))
+				Expect(cfg.Addons[0].RoleName).To(Equal("{ClusterName}-ebs-csi-driver"))
+				sa := cfg.Addons[0].IAMServiceAccount("aws")
+				Expect(sa.RenderRoleName(cfg.Metadata.Name)).To(Equal("cluster-prod-ebs-csi-driver"))
+			})
+
+			It("should load the placeholders of role names from plain config files", func() {
+				cfg, err := LoadConfigFromFile("testdata/addon-role-name.yaml")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cfg.Addons[0].RoleName).To(Equal("{ClusterName}-ebs-csi-driver"))
+			})
+
+			It("should error when a variable isn't set", func() {
+				_, err := LoadConfigFromFileWithOptions("testdata/template.yaml", ConfigFileOptions{Template: true})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(HavePrefix(`rendering config file "testdata/template.yaml"`))
+				Expect(err.Error()).To(ContainSubstring(`map has no entry for key "env"`))
+			})
+		})
+
 		It("should error when cannot read a file", func() {
 			_, err := LoadConfigFromFile("../../examples/nothing.xml")
 			Expect(err).To(HaveOccurred())
@@ -0,0 +1,141 @@
+package eks
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/utils/cache"
+)
+
+// Environment variables configuring the cache of AWS API calls, so that it can be
+// enabled once for all commands of e.g. a CI pipeline
+const (
+	cacheTTLEnvVar = "EKSCTL_CACHE_TTL"
+	cacheDirEnvVar = "EKSCTL_CACHE_DIR"
+)
+
+// publicSSMParameterPrefix is the prefix of the public parameters published by AWS,
+// e.g. the IDs of the EKS-optimized AMIs; other parameters are never cached
+const publicSSMParameterPrefix = "/aws/service/"
+
+// newAPICache returns the cache of AWS API calls, if it's enabled with a TTL
+func newAPICache(spec *api.ProviderConfig) (*cache.Cache, bool) {
+	ttl, dir := spec.CacheTTL, spec.CacheDir
+	if ttl == 0 {
+		if value := os.Getenv(cacheTTLEnvVar); value != "" {
+			var err error
+			if ttl, err = time.ParseDuration(value); err != nil {
+				logger.Warning("ignoring %s: %s", cacheTTLEnvVar, err.Error())
+				return nil, false
+			}
+		}
+	}
+	if ttl <= 0 {
+		return nil, false
+	}
+	if dir == "" {
+		dir = os.Getenv(cacheDirEnvVar)
+	}
+	if dir == "" {
+		dir = cache.DefaultDir()
+	}
+	logger.Debug("caching AWS metadata in %s for %s", dir, ttl)
+	return cache.New(dir, ttl), true
+}
+
+// cacheScope separates the entries of profiles, assumed roles and regions, as e.g. private
+// AMIs and the availability zones differ between accounts
+func cacheScope(spec *api.ProviderConfig) string {
+	scope := spec.Profile + "/" + spec.Region
+	if spec.AssumeRole.ChainedRoleARN != "" {
+		return scope + "/" + spec.AssumeRole.ChainedRoleARN
+	}
+	if spec.AssumeRole.RoleARN != "" {
+		return scope + "/" + spec.AssumeRole.RoleARN
+	}
+	return scope
+}
+
+// cachedCall decodes the cached output of the operation into output, and otherwise
+// calls the operation and caches its output; errors of the cache only disable it
+func cachedCall(c *cache.Cache, operation, scope string, input, output interface{}, call func() error) error {
+	key, err := cache.Key(operation, scope, input)
+	if err != nil {
+		logger.Debug("not caching %s: %s", operation, err.Error())
+		return call()
+	}
+	if c.Get(key, output) {
+		logger.Debug("using cached output of %s", operation)
+		return nil
+	}
+	if err := call(); err != nil {
+		return err
+	}
+	if err := c.Set(key, output); err != nil {
+		logger.Debug("not caching %s: %s", operation, err.Error())
+	}
+	return nil
+}
+
+// cachingEC2 caches the operations of EC2 describing metadata that rarely changes,
+// i.e. availability zones, instance types and images
+type cachingEC2 struct {
+	ec2iface.EC2API
+	cache *cache.Cache
+	scope string
+}
+
+func (c *cachingEC2) DescribeAvailabilityZones(input *ec2.DescribeAvailabilityZonesInput) (*ec2.DescribeAvailabilityZonesOutput, error) {
+	var output *ec2.DescribeAvailabilityZonesOutput
+	err := cachedCall(c.cache, "ec2.DescribeAvailabilityZones", c.scope, input, &output, func() (err error) {
+		output, err = c.EC2API.DescribeAvailabilityZones(input)
+		return err
+	})
+	return output, err
+}
+
+func (c *cachingEC2) DescribeInstanceTypes(input *ec2.DescribeInstanceTypesInput) (*ec2.DescribeInstanceTypesOutput, error) {
+	var output *ec2.DescribeInstanceTypesOutput
+	err := cachedCall(c.cache, "ec2.DescribeInstanceTypes", c.scope, input, &output, func() (err error) {
+		output, err = c.EC2API.DescribeInstanceTypes(input)
+		return err
+	})
+	return output, err
+}
+
+func (c *cachingEC2) DescribeImages(input *ec2.DescribeImagesInput) (*ec2.DescribeImagesOutput, error) {
+	var output *ec2.DescribeImagesOutput
+	err := cachedCall(c.cache, "ec2.DescribeImages", c.scope, input, &output, func() (err error) {
+		output, err = c.EC2API.DescribeImages(input)
+		return err
+	})
+	return output, err
+}
+
+// cachingSSM caches the lookups of public parameters, e.g. of AMIs
+type cachingSSM struct {
+	ssmiface.SSMAPI
+	cache *cache.Cache
+	scope string
+}
+
+func (c *cachingSSM) GetParameter(input *ssm.GetParameterInput) (*ssm.GetParameterOutput, error) {
+	if !strings.HasPrefix(aws.StringValue(input.Name), publicSSMParameterPrefix) {
+		return c.SSMAPI.GetParameter(input)
+	}
+	var output *ssm.GetParameterOutput
+	err := cachedCall(c.cache, "ssm.GetParameter", c.scope, input, &output, func() (err error) {
+		output, err = c.SSMAPI.GetParameter(input)
+		return err
+	})
+	return output, err
+}
@@ -0,0 +1,98 @@
+package eks_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/eks"
+)
+
+var _ = Describe("Caching AWS metadata", func() {
+	var (
+		server   *httptest.Server
+		requests int
+		cacheDir string
+		spec     *api.ProviderConfig
+	)
+
+	describeZones := func() []string {
+		output, err := New(spec, nil).Provider.EC2().DescribeAvailabilityZones(&ec2.DescribeAvailabilityZonesInput{})
+		Expect(err).NotTo(HaveOccurred())
+		var zones []string
+		for _, zone := range output.AvailabilityZones {
+			zones = append(zones, aws.StringValue(zone.ZoneName))
+		}
+		return zones
+	}
+
+	BeforeEach(func() {
+		requests = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			fmt.Fprint(w, `<DescribeAvailabilityZonesResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">
+  <requestId>1</requestId>
+  <availabilityZoneInfo>
+    <item><zoneName>us-west-2a</zoneName><zoneState>available</zoneState><regionName>us-west-2</regionName></item>
+  </availabilityZoneInfo>
+</DescribeAvailabilityZonesResponse>`)
+		}))
+
+		var err error
+		cacheDir, err = ioutil.TempDir("", "eksctl-cache")
+		Expect(err).NotTo(HaveOccurred())
+
+		os.Setenv("AWS_ACCESS_KEY_ID", "AKIAEXAMPLE")
+		os.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+		spec = &api.ProviderConfig{
+			Region:           "us-west-2",
+			ServiceEndpoints: map[string]string{ServiceEC2: server.URL},
+			CacheDir:         cacheDir,
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+		os.RemoveAll(cacheDir)
+		for _, envVar := range []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "EKSCTL_CACHE_TTL"} {
+			os.Unsetenv(envVar)
+		}
+	})
+
+	It("doesn't cache by default", func() {
+		Expect(describeZones()).To(Equal([]string{"us-west-2a"}))
+		Expect(describeZones()).To(Equal([]string{"us-west-2a"}))
+		Expect(requests).To(Equal(2))
+	})
+
+	It("caches the output of calls with a TTL", func() {
+		spec.CacheTTL = time.Hour
+		Expect(describeZones()).To(Equal([]string{"us-west-2a"}))
+		Expect(describeZones()).To(Equal([]string{"us-west-2a"}))
+		Expect(requests).To(Equal(1))
+	})
+
+	It("honours the TTL of the environment", func() {
+		os.Setenv("EKSCTL_CACHE_TTL", "10m")
+		describeZones()
+		describeZones()
+		Expect(requests).To(Equal(1))
+	})
+
+	It("separates the entries of regions", func() {
+		spec.CacheTTL = time.Hour
+		describeZones()
+		spec.Region = "eu-west-1"
+		describeZones()
+		Expect(requests).To(Equal(2))
+	})
+})
@@ -0,0 +1,135 @@
+package eks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+const (
+	minAssumeRoleDuration = 15 * time.Minute
+	maxAssumeRoleDuration = 12 * time.Hour
+	// defaultAssumeRoleDuration is the duration of the sessions when none is set, longer than
+	// the 15 minutes of stscreds so that long waits don't refresh the credentials as often
+	defaultAssumeRoleDuration = 30 * time.Minute
+	// maxChainedRoleDuration is the longest session STS grants a role assumed with the
+	// credentials of another assumed role
+	maxChainedRoleDuration = time.Hour
+)
+
+var roleSessionNameRegex = regexp.MustCompile(`^[\w+=,.@-]{2,64}$`)
+
+// ValidateAssumeRole validates the roles to assume for all the AWS calls
+func ValidateAssumeRole(spec *api.ProviderConfig) error {
+	assumeRole := spec.AssumeRole
+	if assumeRole.RoleARN == "" {
+		if assumeRole.ChainedRoleARN != "" || assumeRole.ExternalID != "" || assumeRole.ChainedExternalID != "" || assumeRole.SessionName != "" || assumeRole.Duration != 0 || assumeRole.ChainedDuration != 0 {
+			return errors.New("--assume-role-arn must be set to assume a role")
+		}
+		return nil
+	}
+	if err := validateRoleARN("--assume-role-arn", assumeRole.RoleARN); err != nil {
+		return err
+	}
+	if assumeRole.ChainedRoleARN != "" {
+		if err := validateRoleARN("--assume-role-chained-arn", assumeRole.ChainedRoleARN); err != nil {
+			return err
+		}
+	} else if assumeRole.ChainedExternalID != "" {
+		return errors.New("--assume-role-chained-external-id requires --assume-role-chained-arn")
+	} else if assumeRole.ChainedDuration != 0 {
+		return errors.New("--assume-role-chained-duration requires --assume-role-chained-arn")
+	}
+	if assumeRole.SessionName != "" && !roleSessionNameRegex.MatchString(assumeRole.SessionName) {
+		return fmt.Errorf("invalid --assume-role-session-name %q, it must be 2 to 64 letters, digits or any of +=,.@_-", assumeRole.SessionName)
+	}
+	if assumeRole.Duration != 0 && (assumeRole.Duration < minAssumeRoleDuration || assumeRole.Duration > maxAssumeRoleDuration) {
+		return fmt.Errorf("--assume-role-duration must be between %s and %s", minAssumeRoleDuration, maxAssumeRoleDuration)
+	}
+	if assumeRole.ChainedDuration != 0 && (assumeRole.ChainedDuration < minAssumeRoleDuration || assumeRole.ChainedDuration > maxChainedRoleDuration) {
+		return fmt.Errorf("--assume-role-chained-duration must be between %s and %s, as STS limits the sessions of chained roles", minAssumeRoleDuration, maxChainedRoleDuration)
+	}
+	return nil
+}
+
+func validateRoleARN(flag, roleARN string) error {
+	parsed, err := arn.Parse(roleARN)
+	if err != nil {
+		return errors.Wrapf(err, "invalid %s %q", flag, roleARN)
+	}
+	if parsed.Service != "iam" || !strings.HasPrefix(parsed.Resource, "role/") {
+		return fmt.Errorf("invalid %s %q, it must be the ARN of an IAM role", flag, roleARN)
+	}
+	return nil
+}
+
+// assumeRoles returns a session making all the calls as the roles to assume, when any; the
+// credentials of s are used to assume the first one, which assumes the chained role
+func assumeRoles(s *session.Session, spec *api.ProviderConfig) *session.Session {
+	assumeRole := spec.AssumeRole
+	if assumeRole.RoleARN == "" {
+		return s
+	}
+	sessionName := assumeRole.SessionName
+	if sessionName == "" {
+		sessionName = fmt.Sprintf("eksctl-%d", time.Now().Unix())
+	}
+
+	s = assumeRoleSession(s, assumeRole.RoleARN, assumeRole.ExternalID, sessionName, sessionDuration(assumeRole.Duration))
+	logger.Debug("assuming role %q for all AWS calls", assumeRole.RoleARN)
+	if assumeRole.ChainedRoleARN != "" {
+		s = assumeRoleSession(s, assumeRole.ChainedRoleARN, assumeRole.ChainedExternalID, sessionName, sessionDuration(assumeRole.ChainedDuration))
+		logger.Debug("assuming role %q with the credentials of %q", assumeRole.ChainedRoleARN, assumeRole.RoleARN)
+	}
+	return s
+}
+
+func sessionDuration(duration time.Duration) time.Duration {
+	if duration == 0 {
+		return defaultAssumeRoleDuration
+	}
+	return duration
+}
+
+func assumeRoleSession(s *session.Session, roleARN, externalID, sessionName string, duration time.Duration) *session.Session {
+	credentials := stscreds.NewCredentials(s, roleARN, func(p *stscreds.AssumeRoleProvider) {
+		p.RoleSessionName = sessionName
+		if externalID != "" {
+			p.ExternalID = aws.String(externalID)
+		}
+		p.Duration = duration
+	})
+	return s.Copy(&aws.Config{Credentials: credentials})
+}
+
+// NewAccountProvider returns the services of the account of a nodegroup created in another account
+// than the control plane, the role of the nodegroup is assumed after the roles assumed for all the
+// AWS calls, if any
+func (c *ClusterProvider) NewAccountProvider(account *api.NodeGroupAccount) (api.ClusterProvider, error) {
+	provider, ok := c.Provider.(*ProviderServices)
+	if !ok {
+		return nil, fmt.Errorf("unexpected provider type %T", c.Provider)
+	}
+	spec := *provider.spec
+	switch {
+	case spec.AssumeRole.RoleARN == "":
+		spec.AssumeRole.RoleARN = account.RoleARN
+		spec.AssumeRole.ExternalID = account.ExternalID
+	case spec.AssumeRole.ChainedRoleARN == "":
+		spec.AssumeRole.ChainedRoleARN = account.RoleARN
+		spec.AssumeRole.ChainedExternalID = account.ExternalID
+	default:
+		return nil, fmt.Errorf("role %q can't be assumed after --assume-role-chained-arn, eksctl chains at most two roles", account.RoleARN)
+	}
+	return New(&spec, nil).Provider, nil
+}
@@ -0,0 +1,49 @@
+package eks_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/eks"
+)
+
+var _ = Describe("Assume role", func() {
+	const (
+		pipelineRole = "arn:aws:iam::111111111111:role/pipeline"
+		clusterRole  = "arn:aws:iam::222222222222:role/cluster-admin"
+	)
+
+	validate := func(assumeRole api.AssumeRoleConfig) error {
+		return ValidateAssumeRole(&api.ProviderConfig{AssumeRole: assumeRole})
+	}
+
+	It("accepts a role with an external ID and a chained role", func() {
+		Expect(validate(api.AssumeRoleConfig{})).To(Succeed())
+		Expect(validate(api.AssumeRoleConfig{
+			RoleARN:           pipelineRole,
+			ExternalID:        "id-1",
+			SessionName:       "pipeline@build-1",
+			Duration:          time.Hour,
+			ChainedRoleARN:    clusterRole,
+			ChainedExternalID: "id-2",
+			ChainedDuration:   time.Hour,
+		})).To(Succeed())
+		Expect(validate(api.AssumeRoleConfig{RoleARN: pipelineRole, ChainedRoleARN: clusterRole, Duration: 2 * time.Hour})).To(Succeed())
+	})
+
+	It("rejects options without a role", func() {
+		Expect(validate(api.AssumeRoleConfig{ChainedRoleARN: clusterRole})).To(MatchError("--assume-role-arn must be set to assume a role"))
+		Expect(validate(api.AssumeRoleConfig{RoleARN: pipelineRole, ChainedExternalID: "id-2"})).To(MatchError("--assume-role-chained-external-id requires --assume-role-chained-arn"))
+		Expect(validate(api.AssumeRoleConfig{RoleARN: pipelineRole, ChainedDuration: time.Hour})).To(MatchError("--assume-role-chained-duration requires --assume-role-chained-arn"))
+	})
+
+	It("rejects invalid roles, session names and durations", func() {
+		Expect(validate(api.AssumeRoleConfig{RoleARN: "arn:aws:iam::111111111111:user/pipeline"})).To(MatchError(ContainSubstring("it must be the ARN of an IAM role")))
+		Expect(validate(api.AssumeRoleConfig{RoleARN: pipelineRole, SessionName: "build 1"})).To(MatchError(ContainSubstring("invalid --assume-role-session-name")))
+		Expect(validate(api.AssumeRoleConfig{RoleARN: pipelineRole, Duration: 13 * time.Hour})).To(MatchError("--assume-role-duration must be between 15m0s and 12h0m0s"))
+		Expect(validate(api.AssumeRoleConfig{RoleARN: pipelineRole, ChainedRoleARN: clusterRole, ChainedDuration: 2 * time.Hour})).To(MatchError(ContainSubstring("--assume-role-chained-duration must be between 15m0s and 1h0m0s")))
+	})
+})
@@ -0,0 +1,23 @@
+package eks
+
+import (
+	"github.com/weaveworks/eksctl/pkg/billing"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// ActivateCostAllocationTags activates the tags as cost allocation tags, e.g. eks:cluster-name
+// to group costs by cluster; as this fails when a tag hasn't been seen in billing data yet, or
+// the credentials belong to a member account, instructions are logged instead of an error
+func (c *ClusterProvider) ActivateCostAllocationTags(tagKeys ...string) {
+	p, ok := c.Provider.(ExtendedProvider)
+	if !ok {
+		logger.Warning(billing.Instructions(tagKeys...))
+		return
+	}
+	if err := billing.ActivateCostAllocationTags(p.CostAllocationTags(), tagKeys...); err != nil {
+		logger.Warning("unable to activate cost allocation tags: %s", err.Error())
+		logger.Warning(billing.Instructions(tagKeys...))
+		return
+	}
+	logger.Success("activated cost allocation tags %v", tagKeys)
+}
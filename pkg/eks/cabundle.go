@@ -0,0 +1,43 @@
+package eks
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// caBundleEnvVar is the environment variable the AWS SDKs read the path of a CA bundle from
+const caBundleEnvVar = "AWS_CA_BUNDLE"
+
+// ValidateCABundle checks that the CA bundle, if any, can be read and contains PEM certificates
+func ValidateCABundle(spec *api.ProviderConfig) error {
+	_, err := readCABundle(spec)
+	return err
+}
+
+// readCABundle reads the CA bundle given on the command line or in the environment,
+// which is trusted by the clients of both the AWS and the Kubernetes APIs, e.g. to
+// connect through a TLS-intercepting proxy
+func readCABundle(spec *api.ProviderConfig) ([]byte, error) {
+	path := spec.CABundle
+	if path == "" {
+		path = os.Getenv(caBundleEnvVar)
+	}
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading CA bundle")
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates found in CA bundle %q", path)
+	}
+	return data, nil
+}
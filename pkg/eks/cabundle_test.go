@@ -0,0 +1,65 @@
+package eks_test
+
+import (
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/eks"
+)
+
+var _ = Describe("CA bundle", func() {
+	var (
+		spec *api.ProviderConfig
+		path string
+	)
+
+	writeBundle := func(content string) {
+		file, err := ioutil.TempFile("", "ca-bundle-*.pem")
+		Expect(err).NotTo(HaveOccurred())
+		defer file.Close()
+		_, err = file.WriteString(content)
+		Expect(err).NotTo(HaveOccurred())
+		path = file.Name()
+	}
+
+	BeforeEach(func() {
+		spec = &api.ProviderConfig{}
+		path = ""
+	})
+
+	AfterEach(func() {
+		if path != "" {
+			os.Remove(path)
+		}
+		os.Unsetenv("AWS_CA_BUNDLE")
+	})
+
+	It("accepts no CA bundle", func() {
+		Expect(ValidateCABundle(spec)).To(Succeed())
+	})
+
+	It("accepts PEM certificates", func() {
+		data, err := ioutil.ReadFile("../iam/oidc/testdata/ca.pem")
+		Expect(err).NotTo(HaveOccurred())
+		writeBundle(string(data))
+
+		spec.CABundle = path
+		Expect(ValidateCABundle(spec)).To(Succeed())
+	})
+
+	It("rejects a bundle without certificates from the environment", func() {
+		writeBundle("not a certificate")
+
+		os.Setenv("AWS_CA_BUNDLE", path)
+		Expect(ValidateCABundle(spec)).To(MatchError(HavePrefix("no PEM certificates found in CA bundle")))
+	})
+
+	It("rejects a missing bundle", func() {
+		spec.CABundle = "/nonexistent/ca.pem"
+		Expect(ValidateCABundle(spec)).To(MatchError(HavePrefix("reading CA bundle")))
+	})
+})
@@ -25,6 +25,7 @@ type Client struct {
 	ContextName string
 
 	rawConfig *restclient.Config
+	caBundle  []byte
 }
 
 // NewClient creates a new client config by embedding the STS token
@@ -34,6 +35,7 @@ func (c *ClusterProvider) NewClient(spec *api.ClusterConfig) (*Client, error) {
 	config := &Client{
 		Config:      clientConfig,
 		ContextName: contextName,
+		caBundle:    c.Status.caBundle,
 	}
 
 	return config.new(spec, c.Provider.STS())
@@ -57,6 +59,10 @@ func (c *Client) new(spec *api.ClusterConfig, stsClient stsiface.STSAPI) (*Clien
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create API client configuration from client config")
 	}
+	if len(c.caBundle) > 0 {
+		// the CA bundle is only trusted by the clients of eksctl, and not written to kubeconfig files
+		rawConfig.CAData = append(append(rawConfig.CAData, '\n'), c.caBundle...)
+	}
 	c.rawConfig = rawConfig
 
 	return c, nil
@@ -0,0 +1,43 @@
+package eks
+
+import (
+	"bytes"
+	"os"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// configTemplateFuncs are the functions available to config file templates, in
+// addition to the variables passed with --set, which are accessed as {{ .name }}
+var configTemplateFuncs = template.FuncMap{
+	// env returns the value of an environment variable, e.g. {{ env "AWS_PROFILE" }}
+	"env": os.Getenv,
+	// default returns the given default value when value is empty,
+	// e.g. {{ index . "env" | default "dev" }}
+	"default": func(defaultValue, value string) string {
+		if value == "" {
+			return defaultValue
+		}
+		return value
+	},
+}
+
+// renderConfigTemplate renders a config file as a Go template with the given variables,
+// referencing a variable that isn't set is an error, unless it's looked up with index
+func renderConfigTemplate(configFile string, data []byte, vars map[string]string) ([]byte, error) {
+	if vars == nil {
+		vars = map[string]string{}
+	}
+
+	tmpl, err := template.New(configFile).Option("missingkey=error").Funcs(configTemplateFuncs).Parse(string(data))
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing config file %q as a template", configFile)
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, vars); err != nil {
+		return nil, errors.Wrapf(err, "rendering config file %q", configFile)
+	}
+	return out.Bytes(), nil
+}
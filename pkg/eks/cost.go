@@ -0,0 +1,176 @@
+package eks
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cost"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// NewPricer returns a pricer looking up the on-demand prices of the region of the cluster in the
+// Price List API, which isn't available in the China and GovCloud partitions
+func (c *ClusterProvider) NewPricer() (cost.Pricer, error) {
+	region := c.Provider.Region()
+	if partition, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region); ok && partition.ID() != endpoints.AwsPartitionID {
+		return nil, fmt.Errorf("budget.maxMonthlyUSD is not supported in region %q, as the AWS Price List API is not available in partition %q", region, partition.ID())
+	}
+	return cost.NewPricer(c.Provider.Pricing(), region), nil
+}
+
+// CheckClusterBudget estimates the monthly cost of the cluster about to be created from the config,
+// and refuses it if the estimate exceeds the budget, unless override is set
+func (c *ClusterProvider) CheckClusterBudget(pricer cost.Pricer, cfg *api.ClusterConfig, override bool) error {
+	inputs := cost.Inputs{
+		ClusterName: cfg.Metadata.Name,
+		NATGateways: natGateways(cfg),
+		NodeGroups:  CostNodeGroups(cfg),
+	}
+	return checkBudget(pricer, cfg, inputs, override)
+}
+
+// CheckNodeGroupsBudget estimates the monthly cost of the existing cluster once the given nodegroups
+// are created or scaled, and refuses it if the estimate exceeds the budget, unless override is set;
+// the nodegroups replace the existing ones of the same name, taking their instance type when unset
+func (c *ClusterProvider) CheckNodeGroupsBudget(pricer cost.Pricer, cfg *api.ClusterConfig, nodeGroups []cost.NodeGroup, override bool) error {
+	summaries, err := c.NewStackManager(cfg).GetNodeGroupSummaries("")
+	if err != nil {
+		return err
+	}
+	byName := map[string]cost.NodeGroup{}
+	for _, summary := range summaries {
+		byName[summary.Name] = cost.NodeGroup{
+			Name:            summary.Name,
+			InstanceType:    summary.InstanceType,
+			DesiredCapacity: summary.DesiredCapacity,
+		}
+	}
+	for _, ng := range nodeGroups {
+		if existing, ok := byName[ng.Name]; ok && ng.InstanceType == "" {
+			ng.InstanceType = existing.InstanceType
+		}
+		byName[ng.Name] = ng
+	}
+
+	natGateways, err := c.countNATGateways(cfg)
+	if err != nil {
+		return err
+	}
+	inputs := cost.Inputs{
+		ClusterName: cfg.Metadata.Name,
+		NATGateways: natGateways,
+	}
+	for _, ng := range byName {
+		inputs.NodeGroups = append(inputs.NodeGroups, ng)
+	}
+	return checkBudget(pricer, cfg, inputs, override)
+}
+
+// countNATGateways returns the number of NAT gateways of the existing cluster, the NAT gateways of a
+// VPC eksctl didn't create aren't part of the cost of the cluster
+func (c *ClusterProvider) countNATGateways(cfg *api.ClusterConfig) (int, error) {
+	input := &ec2.DescribeNatGatewaysInput{
+		Filter: []*ec2.Filter{
+			{
+				Name:   aws.String("tag:" + api.ClusterNameTag),
+				Values: aws.StringSlice([]string{cfg.Metadata.Name}),
+			},
+			{
+				Name:   aws.String("state"),
+				Values: aws.StringSlice([]string{ec2.NatGatewayStatePending, ec2.NatGatewayStateAvailable}),
+			},
+		},
+	}
+	count := 0
+	for {
+		output, err := c.Provider.EC2().DescribeNatGateways(input)
+		if err != nil {
+			return 0, errors.Wrapf(err, "describing the NAT gateways of cluster %q", cfg.Metadata.Name)
+		}
+		count += len(output.NatGateways)
+		if output.NextToken == nil {
+			return count, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+func checkBudget(pricer cost.Pricer, cfg *api.ClusterConfig, inputs cost.Inputs, override bool) error {
+	var nodeGroups []cost.NodeGroup
+	for _, ng := range inputs.NodeGroups {
+		if ng.InstanceType == "" || ng.InstanceType == "mixed" {
+			logger.Warning("the cost of nodegroup %q isn't included in the estimate, as its instance type isn't known", ng.Name)
+			continue
+		}
+		nodeGroups = append(nodeGroups, ng)
+	}
+	inputs.NodeGroups = nodeGroups
+
+	estimate, err := cost.EstimateCost(pricer, inputs)
+	if err != nil {
+		return err
+	}
+	estimate.Log()
+	return cost.CheckBudget(estimate, *cfg.Budget.MaxMonthlyUSD, override)
+}
+
+// CostNodeGroups returns the nodegroups of the config as estimated, mixed instances are estimated at
+// the price of their first instance type
+func CostNodeGroups(cfg *api.ClusterConfig) []cost.NodeGroup {
+	var nodeGroups []cost.NodeGroup
+	for _, ng := range cfg.NodeGroups {
+		instanceType := ng.InstanceType
+		if api.HasMixedInstances(ng) {
+			instanceType = ng.InstancesDistribution.InstanceTypes[0]
+		}
+		nodeGroups = append(nodeGroups, cost.NodeGroup{
+			Name:            ng.Name,
+			InstanceType:    instanceType,
+			DesiredCapacity: desiredCapacity(ng.DesiredCapacity, ng.MinSize),
+		})
+	}
+	for _, ng := range cfg.ManagedNodeGroups {
+		var desired, minSize *int
+		if ng.ScalingConfig != nil {
+			desired, minSize = ng.DesiredCapacity, ng.MinSize
+		}
+		nodeGroups = append(nodeGroups, cost.NodeGroup{
+			Name:            ng.Name,
+			InstanceType:    ng.InstanceType,
+			DesiredCapacity: desiredCapacity(desired, minSize),
+		})
+	}
+	return nodeGroups
+}
+
+// desiredCapacity returns the number of nodes a nodegroup starts with
+func desiredCapacity(desired, minSize *int) int {
+	switch {
+	case desired != nil:
+		return *desired
+	case minSize != nil:
+		return *minSize
+	default:
+		return api.DefaultNodeCount
+	}
+}
+
+// natGateways returns the number of NAT gateways of the VPC eksctl creates for the cluster
+func natGateways(cfg *api.ClusterConfig) int {
+	if cfg.VPC == nil || cfg.VPC.ID != "" || cfg.VPC.NAT == nil || cfg.VPC.NAT.Gateway == nil {
+		return 0
+	}
+	switch *cfg.VPC.NAT.Gateway {
+	case api.ClusterSingleNAT:
+		return 1
+	case api.ClusterHighlyAvailableNAT:
+		return len(cfg.AvailabilityZones)
+	default:
+		return 0
+	}
+}
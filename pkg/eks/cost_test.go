@@ -0,0 +1,75 @@
+package eks_test
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cost"
+	. "github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+type fakePricer struct{}
+
+func (fakePricer) InstanceHourlyUSD(instanceType string) (float64, error) { return 0.10, nil }
+
+func (fakePricer) NATGatewayHourlyUSD() (float64, error) { return 0.05, nil }
+
+var _ = Describe("Budget", func() {
+	var (
+		p   *mockprovider.MockProvider
+		c   *ClusterProvider
+		cfg *api.ClusterConfig
+	)
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		c = &ClusterProvider{Provider: p, Status: &ProviderStatus{}}
+
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "cluster-1"
+		cfg.Budget = &api.Budget{MaxMonthlyUSD: aws.Float64(250)}
+	})
+
+	It("refuses clusters above the budget", func() {
+		ng := cfg.NewNodeGroup()
+		ng.Name = "ng-1"
+		ng.InstanceType = "m5.large"
+		ng.DesiredCapacity = aws.Int(2)
+		gateway := api.ClusterHighlyAvailableNAT
+		cfg.VPC.NAT = &api.ClusterNAT{Gateway: &gateway}
+		cfg.AvailabilityZones = []string{"us-west-2a", "us-west-2b"}
+
+		// $73 for the control plane, $73 for the NAT gateways and $146 for the nodes
+		Expect(c.CheckClusterBudget(fakePricer{}, cfg, false)).To(MatchError(ContainSubstring("$292.00/month exceeds budget.maxMonthlyUSD")))
+		Expect(c.CheckClusterBudget(fakePricer{}, cfg, true)).To(Succeed())
+	})
+
+	It("includes the NAT gateways of existing clusters", func() {
+		p.MockCloudFormation().On("ListStacksPages", mock.Anything, mock.Anything).Return(nil)
+		p.MockEC2().On("DescribeNatGateways", mock.MatchedBy(func(input *ec2.DescribeNatGatewaysInput) bool {
+			return *input.Filter[0].Name == "tag:"+api.ClusterNameTag && *input.Filter[0].Values[0] == "cluster-1"
+		})).Return(&ec2.DescribeNatGatewaysOutput{
+			NatGateways: []*ec2.NatGateway{{}, {}},
+		}, nil)
+
+		nodeGroups := []cost.NodeGroup{{Name: "ng-1", InstanceType: "m5.large", DesiredCapacity: 1}}
+		Expect(c.CheckNodeGroupsBudget(fakePricer{}, cfg, nodeGroups, false)).To(Succeed())
+
+		nodeGroups[0].DesiredCapacity = 2
+		Expect(c.CheckNodeGroupsBudget(fakePricer{}, cfg, nodeGroups, false)).To(MatchError(ContainSubstring("$292.00/month exceeds budget.maxMonthlyUSD")))
+	})
+
+	It("isn't supported without the Price List API", func() {
+		region := mockprovider.ProviderConfig.Region
+		defer func() { mockprovider.ProviderConfig.Region = region }()
+		mockprovider.ProviderConfig.Region = "cn-north-1"
+
+		_, err := c.NewPricer()
+		Expect(err).To(MatchError(`budget.maxMonthlyUSD is not supported in region "cn-north-1", as the AWS Price List API is not available in partition "aws-cn"`))
+	})
+})
@@ -0,0 +1,18 @@
+package eks
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
+)
+
+// NewECRClient returns a client of the ECR API in the given region, e.g. the region of an image
+// registry, as ECR authorization tokens are only valid in the region they're requested in
+func (c *ClusterProvider) NewECRClient(region string) (ecriface.ECRAPI, error) {
+	p, ok := c.Provider.(*ProviderServices)
+	if !ok {
+		return nil, fmt.Errorf("ECR is not supported by this provider")
+	}
+	return ecr.New(p.session, p.session.Config.Copy().WithRegion(region)), nil
+}
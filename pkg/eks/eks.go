@@ -9,8 +9,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/arn"
-	"github.com/kris-nova/logger"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/pkg/errors"
 
 	awseks "github.com/aws/aws-sdk-go/service/eks"
@@ -21,8 +22,10 @@ import (
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/fargate"
 	iamoidc "github.com/weaveworks/eksctl/pkg/iam/oidc"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/printers"
 	"github.com/weaveworks/eksctl/pkg/utils"
+	"github.com/weaveworks/eksctl/pkg/utils/kubectl"
 	"github.com/weaveworks/eksctl/pkg/vpc"
 )
 
@@ -38,6 +41,13 @@ func (c *ClusterProvider) DescribeControlPlane(meta *api.ClusterMeta) (*awseks.C
 	return output.Cluster, nil
 }
 
+// IsControlPlaneNotFound determines if err, returned by DescribeControlPlane or the methods
+// refreshing the cluster status, is because the EKS cluster doesn't exist
+func IsControlPlaneNotFound(err error) bool {
+	awsErr, ok := errors.Cause(err).(awserr.Error)
+	return ok && awsErr.Code() == awseks.ErrCodeResourceNotFoundException
+}
+
 // RefreshClusterStatus calls c.DescribeControlPlane and caches the results;
 // it parses the credentials (endpoint, CA certificate) and stores them in spec.Status,
 // so that a Kubernetes client can be constructed; additionally it caches Kubernetes
@@ -55,6 +65,7 @@ func (c *ClusterProvider) RefreshClusterStatus(spec *api.ClusterConfig) error {
 	}
 
 	c.setClusterInfo(cluster)
+	c.checkVersionSupport(cluster)
 
 	switch *cluster.Status {
 	case awseks.ClusterStatusCreating, awseks.ClusterStatusDeleting, awseks.ClusterStatusFailed:
@@ -167,6 +178,27 @@ func PlatformVersion(platformVersion string) (int, error) {
 	return version, nil
 }
 
+// checkVersionSupport warns when this build of eksctl doesn't support the version of the control
+// plane, or kubectl is too far from it, once per version
+func (c *ClusterProvider) checkVersionSupport(cluster *awseks.Cluster) {
+	version := aws.StringValue(cluster.Version)
+	if version == "" || version == c.Status.checkedVersion {
+		return
+	}
+	c.Status.checkedVersion = version
+
+	if err := api.CheckVersionSupport(version); err != nil {
+		logger.Warning("cluster %q: %s", aws.StringValue(cluster.Name), err)
+	}
+
+	clientVersion, err := kubectl.ClientVersion(nil)
+	if err != nil {
+		logger.Debug("skipping the kubectl version skew check: %s", err)
+		return
+	}
+	kubectl.CheckVersionSkew(clientVersion, version)
+}
+
 func (c *ClusterProvider) maybeRefreshClusterStatus(spec *api.ClusterConfig) error {
 	if c.clusterInfoNeedsUpdate() {
 		return c.RefreshClusterStatus(spec)
@@ -257,7 +289,14 @@ func (c *ClusterProvider) NewOpenIDConnectManager(spec *api.ClusterConfig) (*iam
 		return nil, fmt.Errorf("unknown EKS ARN: %q", spec.Status.ARN)
 	}
 
-	return iamoidc.NewOpenIDConnectManager(c.Provider.IAM(), parsedARN.AccountID, *c.Status.clusterInfo.cluster.Identity.Oidc.Issuer, parsedARN.Partition)
+	oidc, err := iamoidc.NewOpenIDConnectManager(c.Provider.IAM(), parsedARN.AccountID, *c.Status.clusterInfo.cluster.Identity.Oidc.Issuer, parsedARN.Partition)
+	if err != nil {
+		return nil, err
+	}
+	oidc.CABundle = c.Status.caBundle
+	oidc.Tags = map[string]string{api.ClusterNameTag: spec.Metadata.Name}
+	oidc.ResourceTags = spec.Metadata.Tags
+	return oidc, nil
 }
 
 // LoadClusterVPC loads the VPC configuration
@@ -271,7 +310,7 @@ func (c *ClusterProvider) LoadClusterVPC(spec *api.ClusterConfig) error {
 }
 
 // ListClusters display details of all the EKS cluster in your account
-func (c *ClusterProvider) ListClusters(clusterName string, chunkSize int, output printers.Type, eachRegion bool) error {
+func (c *ClusterProvider) ListClusters(clusterName string, chunkSize int, output printers.Type, regions []string) error {
 	// NOTE: this needs to be reworked in the future so that the functionality
 	// is combined. This require the ability to return details of all clusters
 	// in a single call.
@@ -291,7 +330,7 @@ func (c *ClusterProvider) ListClusters(clusterName string, chunkSize int, output
 		addListTableColumns(printer.(*printers.TablePrinter))
 	}
 	allClusters := []*api.ClusterMeta{}
-	if err := c.doListClusters(int64(chunkSize), printer, &allClusters, eachRegion); err != nil {
+	if err := c.doListClusters(int64(chunkSize), &allClusters, regions); err != nil {
 		return err
 	}
 	return printer.PrintObjWithKind("clusters", allClusters, os.Stdout)
@@ -309,18 +348,15 @@ func (c *ClusterProvider) getClustersRequest(chunkSize int64, nextToken string)
 	return output.Clusters, output.NextToken, nil
 }
 
-func (c *ClusterProvider) doListClusters(chunkSize int64, printer printers.OutputPrinter, allClusters *[]*api.ClusterMeta, eachRegion bool) error {
-	if eachRegion {
-		// reset region and re-create the client, then make a recursive call
-		for _, region := range api.SupportedRegions() {
-			spec := &api.ProviderConfig{
-				Region:      region,
-				Profile:     c.Provider.Profile(),
-				WaitTimeout: c.Provider.WaitTimeout(),
-			}
-			if err := New(spec, nil).doListClusters(chunkSize, printer, allClusters, false); err != nil {
-				logger.Critical("error listing clusters in %q region: %s", region, err.Error())
-			}
+func (c *ClusterProvider) doListClusters(chunkSize int64, allClusters *[]*api.ClusterMeta, regions []string) error {
+	if len(regions) > 0 {
+		// query the regions concurrently, and merge the clusters in the order of the regions
+		results := make([][]*api.ClusterMeta, len(regions))
+		c.ForEachRegion(regions, func(i int, ctl *ClusterProvider) error {
+			return ctl.doListClusters(chunkSize, &results[i], nil)
+		})
+		for _, clusters := range results {
+			*allClusters = append(*allClusters, clusters...)
 		}
 		return nil
 	}
@@ -388,7 +424,8 @@ func (c *ClusterProvider) WaitForControlPlane(meta *api.ClusterMeta, clientSet *
 	ticker := time.NewTicker(20 * time.Second)
 	defer ticker.Stop()
 
-	timer := time.NewTimer(c.Provider.WaitTimeout())
+	timeout := c.Provider.Timeout(api.TimeoutPhaseControlPlane)
+	timer := time.NewTimer(timeout)
 	defer timer.Stop()
 
 	for {
@@ -400,7 +437,7 @@ func (c *ClusterProvider) WaitForControlPlane(meta *api.ClusterMeta, clientSet *
 			}
 			logger.Debug("control plane not ready yet – %s", err.Error())
 		case <-timer.C:
-			return fmt.Errorf("timed out waiting for control plane %q after %s", meta.Name, c.Provider.WaitTimeout())
+			return api.TimeoutPhaseControlPlane.WrapTimeout(fmt.Errorf("timed out waiting for control plane %q after %s", meta.Name, timeout), timeout)
 		}
 	}
 }
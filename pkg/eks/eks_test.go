@@ -9,8 +9,6 @@ import (
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
 	awseks "github.com/aws/aws-sdk-go/service/eks"
 
-	"github.com/kris-nova/logger"
-
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
@@ -18,6 +16,7 @@ import (
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	. "github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/testutils"
 	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
 )
@@ -62,7 +61,7 @@ var _ = Describe("EKS API wrapper", func() {
 				})
 
 				JustBeforeEach(func() {
-					err = c.ListClusters(clusterName, 100, output, false)
+					err = c.ListClusters(clusterName, 100, output, nil)
 				})
 
 				It("should not error", func() {
@@ -114,7 +113,7 @@ var _ = Describe("EKS API wrapper", func() {
 				})
 
 				JustBeforeEach(func() {
-					err = c.ListClusters(clusterName, 100, output, false)
+					err = c.ListClusters(clusterName, 100, output, nil)
 				})
 
 				It("should not error", func() {
@@ -162,7 +161,7 @@ var _ = Describe("EKS API wrapper", func() {
 			})
 
 			JustBeforeEach(func() {
-				err = c.ListClusters(clusterName, 100, output, false)
+				err = c.ListClusters(clusterName, 100, output, nil)
 			})
 
 			AfterEach(func() {
@@ -233,7 +232,7 @@ var _ = Describe("EKS API wrapper", func() {
 				})
 
 				JustBeforeEach(func() {
-					err = c.ListClusters("", chunkSize, output, false)
+					err = c.ListClusters("", chunkSize, output, nil)
 				})
 
 				It("should not error", func() {
@@ -267,7 +266,7 @@ var _ = Describe("EKS API wrapper", func() {
 				})
 
 				JustBeforeEach(func() {
-					err = c.ListClusters("", chunkSize, output, false)
+					err = c.ListClusters("", chunkSize, output, nil)
 				})
 
 				It("should not error", func() {
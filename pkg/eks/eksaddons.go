@@ -0,0 +1,202 @@
+package eks
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/addons"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/eksaddons"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/utils/waiters"
+)
+
+// NewAddonsAPI returns a client of the EKS add-ons API
+func (c *ClusterProvider) NewAddonsAPI() (eksaddons.API, error) {
+	p, ok := c.Provider.(ExtendedProvider)
+	if !ok {
+		return nil, errors.New("the EKS add-ons API is not supported by this provider")
+	}
+	return p.Addons(), nil
+}
+
+// ListAvailableAddons returns the add-ons available for the Kubernetes version of the
+// cluster, along with the versions of them that are installed
+func (c *ClusterProvider) ListAvailableAddons(spec *api.ClusterConfig) ([]*eksaddons.AvailableAddon, error) {
+	if err := c.RefreshClusterStatus(spec); err != nil {
+		return nil, err
+	}
+	addonsAPI, err := c.NewAddonsAPI()
+	if err != nil {
+		return nil, err
+	}
+	return eksaddons.ListAvailable(addonsAPI, spec.Metadata.Name, c.ControlPlaneVersion())
+}
+
+// ListInstalledAddons returns the add-ons installed in the cluster
+func (c *ClusterProvider) ListInstalledAddons(spec *api.ClusterConfig) ([]*eksaddons.Addon, error) {
+	addonsAPI, err := c.NewAddonsAPI()
+	if err != nil {
+		return nil, err
+	}
+	return eksaddons.ListInstalled(addonsAPI, spec.Metadata.Name)
+}
+
+// CreateAddons creates the EKS add-ons of the config and waits for them to become active, then
+// configures the EBS CSI driver
+func (c *ClusterProvider) CreateAddons(cfg *api.ClusterConfig) error {
+	if err := c.RefreshClusterStatus(cfg); err != nil {
+		return err
+	}
+	addonsAPI, err := c.NewAddonsAPI()
+	if err != nil {
+		return err
+	}
+	for _, addon := range cfg.Addons {
+		if err := c.createAddon(cfg, addonsAPI, addon); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteAddon deletes an EKS add-on of the cluster along with the IAM role eksctl created for
+// it; with preserve, its Kubernetes objects and role keep running in the cluster
+func (c *ClusterProvider) DeleteAddon(cfg *api.ClusterConfig, name string, preserve, wait bool) error {
+	addonsAPI, err := c.NewAddonsAPI()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.Provider.WaitTimeout())
+	defer cancel()
+	if err := eksaddons.Delete(ctx, addonsAPI, cfg.Metadata.Name, name, preserve, wait); err != nil {
+		return err
+	}
+	// the role of the service account is kept along with the Kubernetes objects of the add-on
+	if !preserve {
+		if err := c.NewStackManager(cfg).DeleteAddonRole(name, wait); err != nil {
+			return errors.Wrapf(err, "deleting the IAM role of EKS add-on %q", name)
+		}
+	}
+	if preserve {
+		logger.Info("EKS add-on %q is no longer managed by EKS, its Kubernetes objects have been kept in the cluster", name)
+	} else {
+		logger.Info("deleted EKS add-on %q", name)
+	}
+	return nil
+}
+
+// InstallEBSCSIDriver creates the EBS CSI driver add-on, configured as in the addons of the
+// config if it's there, so that the volumes of the in-tree EBS plugin keep working once EKS
+// migrates them to the driver
+func (c *ClusterProvider) InstallEBSCSIDriver(cfg *api.ClusterConfig) error {
+	addon := &api.Addon{Name: api.EBSCSIDriverAddon}
+	for _, a := range cfg.Addons {
+		if a.Name == api.EBSCSIDriverAddon {
+			addon = a
+		}
+	}
+	if addon.ServiceAccountRoleARN == "" && !addon.CreatesRole(api.IsEnabled(cfg.IAM.WithOIDC)) {
+		logger.Warning("%q has no service account role, the driver uses the IAM permissions of the nodes to manage volumes", addon.Name)
+	}
+	addonsAPI, err := c.NewAddonsAPI()
+	if err != nil {
+		return err
+	}
+	return c.createAddon(cfg, addonsAPI, addon)
+}
+
+func (c *ClusterProvider) createAddon(cfg *api.ClusterConfig, addonsAPI eksaddons.API, addon *api.Addon) error {
+	roleARN := addon.ServiceAccountRoleARN
+	if addon.CreatesRole(api.IsEnabled(cfg.IAM.WithOIDC)) {
+		var err error
+		if roleARN, err = c.createAddonRole(cfg, addon); err != nil {
+			return errors.Wrapf(err, "creating the IAM role of EKS add-on %q", addon.Name)
+		}
+		logger.Info("created IAM role %q for EKS add-on %q", roleARN, addon.Name)
+	}
+
+	logger.Info("creating EKS add-on %q", addon.Name)
+	timeout := c.Provider.Timeout(api.TimeoutPhaseAddons)
+	if err := eksaddons.Create(addonsAPI, makeCreateAddonInput(cfg.Metadata.Name, addon, roleARN), timeout); err != nil {
+		if waiters.IsTimeout(err) {
+			return api.TimeoutPhaseAddons.WrapTimeout(err, timeout)
+		}
+		return err
+	}
+	logger.Info("created EKS add-on %q", addon.Name)
+
+	if addon.Name == api.EBSCSIDriverAddon {
+		return c.configureEBSCSIDriver(cfg, addon)
+	}
+	return nil
+}
+
+// createAddonRole creates the IAM role of the service account of an add-on with CloudFormation,
+// trusting the OIDC provider of the cluster
+func (c *ClusterProvider) createAddonRole(cfg *api.ClusterConfig, addon *api.Addon) (string, error) {
+	oidc, err := c.NewOpenIDConnectManager(cfg)
+	if err != nil {
+		return "", err
+	}
+	exists, err := oidc.CheckProviderExists()
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", errors.New("the cluster has no IAM OIDC provider, create it with 'eksctl utils associate-iam-oidc-provider'")
+	}
+	parsedARN, err := arn.Parse(cfg.Status.ARN)
+	if err != nil {
+		return "", errors.Wrapf(err, "unexpected invalid ARN: %q", cfg.Status.ARN)
+	}
+	return c.NewStackManager(cfg).CreateAddonRole(addon.Name, addon.IAMServiceAccount(parsedARN.Partition), oidc)
+}
+
+// configureEBSCSIDriver installs what the EBS CSI driver add-on leaves out: the snapshot
+// controller and an encrypted gp3 StorageClass
+func (c *ClusterProvider) configureEBSCSIDriver(cfg *api.ClusterConfig, addon *api.Addon) error {
+	if api.IsEnabled(addon.SnapshotController) {
+		rawClient, err := c.NewRawClient(cfg)
+		if err != nil {
+			return err
+		}
+		if err := addons.NewSnapshotController(rawClient, c.ControlPlaneVersion(), cfg.ContainerRegistryOverride, false).Deploy(); err != nil {
+			return err
+		}
+	}
+	if addon.StorageClass != nil {
+		clientSet, err := c.NewStdClientSet(cfg)
+		if err != nil {
+			return err
+		}
+		if err := addons.CreateEBSStorageClass(clientSet, addon.StorageClass); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func makeCreateAddonInput(clusterName string, addon *api.Addon, roleARN string) *eksaddons.CreateAddonInput {
+	input := &eksaddons.CreateAddonInput{
+		ClusterName: &clusterName,
+		AddonName:   aws.String(addon.Name),
+	}
+	if addon.Version != "" {
+		input.AddonVersion = aws.String(addon.Version)
+	}
+	if roleARN != "" {
+		input.ServiceAccountRoleArn = aws.String(roleARN)
+	}
+	if addon.ConfigurationValues != "" {
+		input.ConfigurationValues = aws.String(addon.ConfigurationValues)
+	}
+	if addon.ResolveConflicts != "" {
+		input.ResolveConflicts = aws.String(strings.ToUpper(addon.ResolveConflicts))
+	}
+	return input
+}
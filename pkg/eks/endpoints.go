@@ -0,0 +1,162 @@
+package eks
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// Services whose endpoints can be overridden, named as in --service-endpoint
+const (
+	ServiceCloudFormation = "cloudformation"
+	ServiceEKS            = "eks"
+	ServiceEC2            = "ec2"
+	ServiceELB            = "elb"
+	ServiceELBV2          = "elbv2"
+	ServiceSTS            = "sts"
+	ServiceSSM            = "ssm"
+	ServiceIAM            = "iam"
+	ServiceCloudTrail     = "cloudtrail"
+	ServiceKMS            = "kms"
+	ServiceSNS            = "sns"
+	ServiceEventBridge    = "eventbridge"
+	ServiceCostExplorer   = "ce"
+	ServiceInspector      = "inspector2"
+	ServiceRoute53        = "route53"
+	ServiceCloudWatchLogs = "logs"
+	ServicePricing        = "pricing"
+)
+
+// endpointEnvVar is the prefix of the environment variables overriding endpoints,
+// AWS_ENDPOINT_URL applies to all services and AWS_ENDPOINT_URL_<service ID> to one
+const endpointEnvVar = "AWS_ENDPOINT_URL"
+
+// serviceEndpointEnvVars are the environment variables overriding the endpoint of each service;
+// the first one follows the AWS SDKs, naming services after their service ID, and the second
+// one is the variable supported by earlier versions of eksctl
+var serviceEndpointEnvVars = map[string][]string{
+	ServiceCloudFormation: {"AWS_ENDPOINT_URL_CLOUDFORMATION", "AWS_CLOUDFORMATION_ENDPOINT"},
+	ServiceEKS:            {"AWS_ENDPOINT_URL_EKS", "AWS_EKS_ENDPOINT"},
+	ServiceEC2:            {"AWS_ENDPOINT_URL_EC2", "AWS_EC2_ENDPOINT"},
+	ServiceELB:            {"AWS_ENDPOINT_URL_ELASTIC_LOAD_BALANCING", "AWS_ELB_ENDPOINT"},
+	ServiceELBV2:          {"AWS_ENDPOINT_URL_ELASTIC_LOAD_BALANCING_V2", "AWS_ELBV2_ENDPOINT"},
+	ServiceSTS:            {"AWS_ENDPOINT_URL_STS", "AWS_STS_ENDPOINT"},
+	ServiceSSM:            {"AWS_ENDPOINT_URL_SSM"},
+	ServiceIAM:            {"AWS_ENDPOINT_URL_IAM", "AWS_IAM_ENDPOINT"},
+	ServiceCloudTrail:     {"AWS_ENDPOINT_URL_CLOUDTRAIL", "AWS_CLOUDTRAIL_ENDPOINT"},
+	ServiceKMS:            {"AWS_ENDPOINT_URL_KMS", "AWS_KMS_ENDPOINT"},
+	ServiceSNS:            {"AWS_ENDPOINT_URL_SNS", "AWS_SNS_ENDPOINT"},
+	ServiceEventBridge:    {"AWS_ENDPOINT_URL_EVENTBRIDGE", "AWS_EVENTBRIDGE_ENDPOINT"},
+	ServiceCostExplorer:   {"AWS_ENDPOINT_URL_COST_EXPLORER"},
+	ServiceInspector:      {"AWS_ENDPOINT_URL_INSPECTOR2"},
+	ServiceRoute53:        {"AWS_ENDPOINT_URL_ROUTE_53"},
+	ServiceCloudWatchLogs: {"AWS_ENDPOINT_URL_CLOUDWATCH_LOGS"},
+	ServicePricing:        {"AWS_ENDPOINT_URL_PRICING"},
+}
+
+// ValidateEndpoints checks that endpoint overrides are URLs of known services
+func ValidateEndpoints(spec *api.ProviderConfig) error {
+	if spec.EndpointURL != "" {
+		if err := validateEndpointURL(spec.EndpointURL); err != nil {
+			return err
+		}
+	}
+	for service, endpoint := range spec.ServiceEndpoints {
+		if _, ok := serviceEndpointEnvVars[service]; !ok {
+			return fmt.Errorf("unknown service %q in endpoint overrides, supported services are: %s",
+				service, strings.Join(endpointServices(), ", "))
+		}
+		if err := validateEndpointURL(endpoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateEndpointURL(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid endpoint URL %q, expected e.g. https://localhost:4566", endpoint)
+	}
+	return nil
+}
+
+func endpointServices() []string {
+	var services []string
+	for service := range serviceEndpointEnvVars {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	return services
+}
+
+// serviceEndpoint returns the endpoint of the service if it's overridden; the command
+// line takes precedence over the environment, and the endpoint of the service over
+// the endpoint of all services
+func serviceEndpoint(spec *api.ProviderConfig, service string) (string, bool) {
+	if endpoint, ok := spec.ServiceEndpoints[service]; ok {
+		return endpoint, true
+	}
+	if spec.EndpointURL != "" {
+		return spec.EndpointURL, true
+	}
+	for _, envVar := range serviceEndpointEnvVars[service] {
+		if endpoint, ok := os.LookupEnv(envVar); ok && endpoint != "" {
+			return endpoint, true
+		}
+	}
+	if endpoint, ok := os.LookupEnv(endpointEnvVar); ok && endpoint != "" {
+		return endpoint, true
+	}
+	return "", false
+}
+
+// fipsEndpointEnvVar enables FIPS endpoints, like in the AWS SDKs
+const fipsEndpointEnvVar = "AWS_USE_FIPS_ENDPOINT"
+
+// useFIPSEndpoints returns true if the command line or the environment enable FIPS endpoints
+func useFIPSEndpoints(spec *api.ProviderConfig) bool {
+	return spec.UseFIPSEndpoints || strings.EqualFold(os.Getenv(fipsEndpointEnvVar), "true")
+}
+
+// fipsEndpointResolver resolves the FIPS endpoints of services; the endpoints of the
+// SDK are used when they include FIPS endpoints for the region, otherwise the endpoints
+// are derived from the regular ones, e.g. ec2-fips.us-east-1.amazonaws.com
+func fipsEndpointResolver() endpoints.Resolver {
+	return endpoints.ResolverFunc(func(service, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+		strictOpts := append([]func(*endpoints.Options){endpoints.StrictMatchingOption}, opts...)
+		for _, fipsRegion := range []string{"fips-" + region, region + "-fips"} {
+			resolved, err := endpoints.DefaultResolver().EndpointFor(service, fipsRegion, strictOpts...)
+			if err == nil {
+				return resolved, nil
+			}
+		}
+
+		resolved, err := endpoints.DefaultResolver().EndpointFor(service, region, opts...)
+		if err != nil {
+			return resolved, err
+		}
+		// IAM endpoints are FIPS compliant in GovCloud
+		if service == iam.EndpointsID && resolved.PartitionID == endpoints.AwsUsGovPartitionID {
+			return resolved, nil
+		}
+		u, err := url.Parse(resolved.URL)
+		if err != nil {
+			return resolved, err
+		}
+		labels := strings.SplitN(u.Host, ".", 2)
+		if len(labels) != 2 || strings.HasSuffix(labels[0], "-fips") {
+			return resolved, nil
+		}
+		u.Host = labels[0] + "-fips." + labels[1]
+		resolved.URL = u.String()
+		return resolved, nil
+	})
+}
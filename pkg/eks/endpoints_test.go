@@ -0,0 +1,114 @@
+package eks_test
+
+import (
+	"os"
+
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/eks"
+)
+
+var _ = Describe("Endpoint overrides", func() {
+	var spec *api.ProviderConfig
+
+	endpoints := func() (string, string) {
+		ctl := New(spec, nil)
+		return ctl.Provider.EKS().(*awseks.EKS).Endpoint, ctl.Provider.STS().(*sts.STS).Endpoint
+	}
+
+	BeforeEach(func() {
+		spec = &api.ProviderConfig{Region: "eu-north-1"}
+	})
+
+	AfterEach(func() {
+		for _, envVar := range []string{"AWS_ENDPOINT_URL", "AWS_ENDPOINT_URL_EKS", "AWS_STS_ENDPOINT", "AWS_USE_FIPS_ENDPOINT"} {
+			os.Unsetenv(envVar)
+		}
+	})
+
+	It("uses the default endpoints", func() {
+		eksEndpoint, stsEndpoint := endpoints()
+		Expect(eksEndpoint).To(Equal("https://eks.eu-north-1.amazonaws.com"))
+		Expect(stsEndpoint).To(Equal("https://sts.eu-north-1.amazonaws.com"))
+	})
+
+	It("honours the environment, preferring the endpoint of the service", func() {
+		os.Setenv("AWS_ENDPOINT_URL", "http://localhost:4566")
+		os.Setenv("AWS_ENDPOINT_URL_EKS", "http://localhost:5000")
+
+		eksEndpoint, stsEndpoint := endpoints()
+		Expect(eksEndpoint).To(Equal("http://localhost:5000"))
+		Expect(stsEndpoint).To(Equal("http://localhost:4566"))
+	})
+
+	It("honours the environment variables of earlier versions", func() {
+		os.Setenv("AWS_STS_ENDPOINT", "https://sts.example.com")
+
+		_, stsEndpoint := endpoints()
+		Expect(stsEndpoint).To(Equal("https://sts.example.com"))
+	})
+
+	It("prefers the command line over the environment", func() {
+		os.Setenv("AWS_ENDPOINT_URL_EKS", "http://localhost:5000")
+		spec.EndpointURL = "http://localhost:4566"
+		spec.ServiceEndpoints = map[string]string{ServiceSTS: "https://sts.example.com"}
+
+		eksEndpoint, stsEndpoint := endpoints()
+		Expect(eksEndpoint).To(Equal("http://localhost:4566"))
+		Expect(stsEndpoint).To(Equal("https://sts.example.com"))
+	})
+
+	Context("with FIPS endpoints", func() {
+		iamEndpoint := func() string {
+			return New(spec, nil).Provider.IAM().(*iam.IAM).Endpoint
+		}
+
+		BeforeEach(func() {
+			spec.Region = "us-east-1"
+			spec.UseFIPSEndpoints = true
+		})
+
+		It("uses the FIPS endpoints of all services", func() {
+			eksEndpoint, stsEndpoint := endpoints()
+			Expect(eksEndpoint).To(Equal("https://eks-fips.us-east-1.amazonaws.com"))
+			Expect(stsEndpoint).To(Equal("https://sts-fips.us-east-1.amazonaws.com"))
+			Expect(iamEndpoint()).To(Equal("https://iam-fips.amazonaws.com"))
+		})
+
+		It("uses the IAM endpoint of GovCloud", func() {
+			spec.Region = "us-gov-west-1"
+			Expect(iamEndpoint()).To(Equal("https://iam.us-gov.amazonaws.com"))
+		})
+
+		It("honours the environment", func() {
+			spec.UseFIPSEndpoints = false
+			os.Setenv("AWS_USE_FIPS_ENDPOINT", "true")
+
+			eksEndpoint, _ := endpoints()
+			Expect(eksEndpoint).To(Equal("https://eks-fips.us-east-1.amazonaws.com"))
+		})
+
+		It("prefers custom endpoints", func() {
+			spec.ServiceEndpoints = map[string]string{ServiceEKS: "https://eks.example.com"}
+
+			eksEndpoint, _ := endpoints()
+			Expect(eksEndpoint).To(Equal("https://eks.example.com"))
+		})
+	})
+
+	It("rejects unknown services and invalid URLs", func() {
+		spec.ServiceEndpoints = map[string]string{"lambda": "http://localhost:4566"}
+		Expect(ValidateEndpoints(spec)).To(MatchError(HavePrefix(`unknown service "lambda"`)))
+
+		spec.ServiceEndpoints = map[string]string{ServiceEKS: "localhost"}
+		Expect(ValidateEndpoints(spec)).To(MatchError(HavePrefix(`invalid endpoint URL "localhost"`)))
+
+		spec.ServiceEndpoints = map[string]string{ServiceEKS: "http://localhost:5000"}
+		Expect(ValidateEndpoints(spec)).To(Succeed())
+	})
+})
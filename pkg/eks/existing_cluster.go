@@ -0,0 +1,56 @@
+package eks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+)
+
+// ValidateExistingCluster checks that the cluster of an existing cluster stack can be reused
+// and matches cfg, so that creating the cluster again only creates what is missing
+func (c *ClusterProvider) ValidateExistingCluster(cfg *api.ClusterConfig, stack *manager.Stack) error {
+	if err := ValidateExistingClusterStack(cfg.Metadata.Name, stack); err != nil {
+		return err
+	}
+	if ok, err := c.CanOperate(cfg); !ok {
+		return err
+	}
+	return CheckClusterEquivalence(cfg, c.Status.clusterInfo.cluster)
+}
+
+// ValidateExistingClusterStack checks that the cluster stack was created successfully
+func ValidateExistingClusterStack(clusterName string, stack *manager.Stack) error {
+	status := aws.StringValue(stack.StackStatus)
+	switch status {
+	case cfn.StackStatusCreateComplete, cfn.StackStatusUpdateComplete, cfn.StackStatusUpdateRollbackComplete:
+		return nil
+	}
+	if strings.HasSuffix(status, "_IN_PROGRESS") {
+		return fmt.Errorf("cluster stack %q is in state %s, wait for it to complete and try again", aws.StringValue(stack.StackName), status)
+	}
+	return fmt.Errorf("cluster stack %q is in state %s and cannot be reused, delete the cluster with 'eksctl delete cluster --name=%s' and create it again",
+		aws.StringValue(stack.StackName), status, clusterName)
+}
+
+// CheckClusterEquivalence checks that the version and the VPC of the cluster match cfg
+func CheckClusterEquivalence(cfg *api.ClusterConfig, cluster *awseks.Cluster) error {
+	var mismatches []string
+	if version := aws.StringValue(cluster.Version); cfg.Metadata.Version != "" && cfg.Metadata.Version != "auto" && cfg.Metadata.Version != version {
+		mismatches = append(mismatches, fmt.Sprintf("metadata.version is %s, but the cluster runs %s", cfg.Metadata.Version, version))
+	}
+	if cfg.VPC != nil && cfg.VPC.ID != "" && cluster.ResourcesVpcConfig != nil {
+		if vpcID := aws.StringValue(cluster.ResourcesVpcConfig.VpcId); cfg.VPC.ID != vpcID {
+			mismatches = append(mismatches, fmt.Sprintf("vpc.id is %s, but the cluster uses %s", cfg.VPC.ID, vpcID))
+		}
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("cluster %q already exists and doesn't match the config file: %s", cfg.Metadata.Name, strings.Join(mismatches, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package eks_test
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/eks"
+)
+
+var _ = Describe("Existing cluster", func() {
+	Describe("ValidateExistingClusterStack", func() {
+		stack := func(status string) *cfn.Stack {
+			return &cfn.Stack{StackName: aws.String("eksctl-cluster-1-cluster"), StackStatus: aws.String(status)}
+		}
+
+		It("accepts completed stacks", func() {
+			for _, status := range []string{cfn.StackStatusCreateComplete, cfn.StackStatusUpdateComplete, cfn.StackStatusUpdateRollbackComplete} {
+				Expect(ValidateExistingClusterStack("cluster-1", stack(status))).To(Succeed())
+			}
+		})
+
+		It("asks to wait for stacks in progress", func() {
+			err := ValidateExistingClusterStack("cluster-1", stack(cfn.StackStatusCreateInProgress))
+			Expect(err).To(MatchError(`cluster stack "eksctl-cluster-1-cluster" is in state CREATE_IN_PROGRESS, wait for it to complete and try again`))
+		})
+
+		It("rejects stacks that failed to be created", func() {
+			err := ValidateExistingClusterStack("cluster-1", stack(cfn.StackStatusRollbackComplete))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("'eksctl delete cluster --name=cluster-1'"))
+		})
+	})
+
+	Describe("CheckClusterEquivalence", func() {
+		var (
+			cfg     *api.ClusterConfig
+			cluster *awseks.Cluster
+		)
+
+		BeforeEach(func() {
+			cfg = api.NewClusterConfig()
+			cfg.Metadata.Name = "cluster-1"
+			cfg.Metadata.Version = "1.15"
+			cluster = &awseks.Cluster{
+				Version:            aws.String("1.15"),
+				ResourcesVpcConfig: &awseks.VpcConfigResponse{VpcId: aws.String("vpc-1")},
+			}
+		})
+
+		It("accepts a matching cluster", func() {
+			Expect(CheckClusterEquivalence(cfg, cluster)).To(Succeed())
+			cfg.VPC.ID = "vpc-1"
+			Expect(CheckClusterEquivalence(cfg, cluster)).To(Succeed())
+		})
+
+		It("rejects a different version and VPC", func() {
+			cfg.Metadata.Version = "1.14"
+			cfg.VPC.ID = "vpc-2"
+			err := CheckClusterEquivalence(cfg, cluster)
+			Expect(err).To(MatchError(`cluster "cluster-1" already exists and doesn't match the config file: ` +
+				"metadata.version is 1.14, but the cluster runs 1.15; vpc.id is vpc-2, but the cluster uses vpc-1"))
+		})
+	})
+})
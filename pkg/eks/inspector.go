@@ -0,0 +1,54 @@
+package eks
+
+import (
+	"fmt"
+
+	"github.com/weaveworks/eksctl/pkg/inspector"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// GetAMIVulnerabilities returns the AMIs of the nodes of the cluster, with the numbers of
+// vulnerabilities Amazon Inspector found in each of them
+func (c *ClusterProvider) GetAMIVulnerabilities(clusterName string) ([]*inspector.AMIReport, error) {
+	p, ok := c.Provider.(ExtendedProvider)
+	if !ok {
+		return nil, fmt.Errorf("Amazon Inspector is not supported by this provider")
+	}
+
+	reports, err := inspector.NodeAMIs(c.Provider.EC2(), clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if err := inspector.AddFindings(p.Inspector(), reports); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// LogAMIVulnerabilities logs the AMIs of the nodes of the nodegroups, with the numbers of
+// vulnerabilities found in them; failing to get them doesn't fail the operation
+func (c *ClusterProvider) LogAMIVulnerabilities(clusterName string, nodeGroups []KubeNodeGroup) {
+	reports, err := c.GetAMIVulnerabilities(clusterName)
+	if err != nil {
+		logger.Warning("unable to report the vulnerabilities of node AMIs: %s", err.Error())
+		return
+	}
+
+	wanted := map[string]bool{}
+	for _, ng := range nodeGroups {
+		wanted[ng.NameString()] = true
+	}
+	for _, report := range reports {
+		for _, name := range report.NodeGroups {
+			if !wanted[name] {
+				continue
+			}
+			if !report.Scanned {
+				logger.Info("nodegroup %q uses AMI %q, which hasn't been scanned by Amazon Inspector yet", name, report.ImageID)
+				continue
+			}
+			logger.Info("nodegroup %q uses AMI %q with %d critical, %d high and %d medium vulnerabilities (%d in total)",
+				name, report.ImageID, report.Critical, report.High, report.Medium, report.All)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package eks
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// maxSelectedInstanceTypes is the maximum number of instance types of a mixed nodegroup
+const maxSelectedInstanceTypes = 20
+
+// ExpandInstanceSelectors resolves the instance selectors of the nodegroups to instance types; a
+// nodegroup with several matching instance types becomes a mixed nodegroup
+func (m *NodeGroupService) ExpandInstanceSelectors(nodeGroups []*api.NodeGroup) error {
+	for _, ng := range nodeGroups {
+		if ng.InstanceSelector == nil {
+			continue
+		}
+		instanceTypes, err := m.selectInstanceTypes(ng.InstanceSelector)
+		if err != nil {
+			return errors.Wrapf(err, "resolving the instance selector of nodegroup %q", ng.Name)
+		}
+		if len(instanceTypes) == 1 && ng.InstancesDistribution == nil {
+			ng.InstanceType = instanceTypes[0]
+		} else {
+			ng.InstanceType = "mixed"
+			if ng.InstancesDistribution == nil {
+				ng.InstancesDistribution = &api.NodeGroupInstancesDistribution{}
+			}
+			ng.InstancesDistribution.InstanceTypes = instanceTypes
+		}
+		logger.Info("nodegroup %q will use instance types %v", ng.Name, instanceTypes)
+	}
+	return nil
+}
+
+// ExpandManagedInstanceSelectors resolves the instance selectors of the managed nodegroups; as
+// managed nodegroups have a single instance type, the first matching instance type is used
+func (m *NodeGroupService) ExpandManagedInstanceSelectors(nodeGroups []*api.ManagedNodeGroup) error {
+	for _, ng := range nodeGroups {
+		if ng.InstanceSelector == nil {
+			continue
+		}
+		instanceTypes, err := m.selectInstanceTypes(ng.InstanceSelector)
+		if err != nil {
+			return errors.Wrapf(err, "resolving the instance selector of managed nodegroup %q", ng.Name)
+		}
+		ng.InstanceType = instanceTypes[0]
+		logger.Info("managed nodegroup %q will use instance type %q", ng.Name, ng.InstanceType)
+		if len(instanceTypes) > 1 {
+			logger.Info("instance types %v also match the instance selector of managed nodegroup %q", instanceTypes[1:], ng.Name)
+		}
+	}
+	return nil
+}
+
+// selectInstanceTypes returns the current generation instance types matching the selector,
+// sorted by name
+func (m *NodeGroupService) selectInstanceTypes(selector *api.InstanceSelector) ([]string, error) {
+	memory, err := selector.MemoryMiB()
+	if err != nil {
+		return nil, err
+	}
+
+	filters := []*ec2.Filter{
+		{
+			Name:   aws.String("current-generation"),
+			Values: aws.StringSlice([]string{"true"}),
+		},
+		{
+			Name:   aws.String("bare-metal"),
+			Values: aws.StringSlice([]string{"false"}),
+		},
+		{
+			Name:   aws.String("processor-info.supported-architecture"),
+			Values: aws.StringSlice([]string{selector.Architecture()}),
+		},
+	}
+	if selector.VCPUs > 0 {
+		filters = append(filters, &ec2.Filter{
+			Name:   aws.String("vcpu-info.default-vcpus"),
+			Values: aws.StringSlice([]string{strconv.Itoa(selector.VCPUs)}),
+		})
+	}
+	if memory > 0 {
+		filters = append(filters, &ec2.Filter{
+			Name:   aws.String("memory-info.size-in-mib"),
+			Values: aws.StringSlice([]string{strconv.FormatInt(memory, 10)}),
+		})
+	}
+
+	var instanceTypes []string
+	input := &ec2.DescribeInstanceTypesInput{Filters: filters}
+	for {
+		output, err := m.ec2API.DescribeInstanceTypes(input)
+		if err != nil {
+			return nil, errors.Wrap(err, "describing instance types")
+		}
+		for _, instanceType := range output.InstanceTypes {
+			if gpus(instanceType) == selector.GPUs {
+				instanceTypes = append(instanceTypes, aws.StringValue(instanceType.InstanceType))
+			}
+		}
+		if aws.StringValue(output.NextToken) == "" {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	if len(instanceTypes) == 0 {
+		return nil, fmt.Errorf("no instance types match %d vCPUs, %q memory, %d GPUs and %s architecture in region %q",
+			selector.VCPUs, selector.Memory, selector.GPUs, selector.Architecture(), m.cluster.Metadata.Region)
+	}
+	sort.Strings(instanceTypes)
+	if len(instanceTypes) > maxSelectedInstanceTypes {
+		instanceTypes = instanceTypes[:maxSelectedInstanceTypes]
+	}
+	return instanceTypes, nil
+}
+
+func gpus(instanceType *ec2.InstanceTypeInfo) int {
+	if instanceType.GpuInfo == nil {
+		return 0
+	}
+	count := 0
+	for _, gpu := range instanceType.GpuInfo.Gpus {
+		count += int(aws.Int64Value(gpu.Count))
+	}
+	return count
+}
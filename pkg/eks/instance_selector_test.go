@@ -0,0 +1,91 @@
+package eks_test
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("Instance selector", func() {
+	var (
+		p        *mockprovider.MockProvider
+		cfg      *api.ClusterConfig
+		selector *api.InstanceSelector
+		input    *ec2.DescribeInstanceTypesInput
+	)
+
+	instanceType := func(name string, gpus int64) *ec2.InstanceTypeInfo {
+		info := &ec2.InstanceTypeInfo{InstanceType: aws.String(name)}
+		if gpus > 0 {
+			info.GpuInfo = &ec2.GpuInfo{Gpus: []*ec2.GpuDeviceInfo{{Count: aws.Int64(gpus)}}}
+		}
+		return info
+	}
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "test"
+		cfg.Metadata.Region = "us-west-2"
+		selector = &api.InstanceSelector{VCPUs: 4, Memory: "16GiB"}
+
+		p.MockEC2().On("DescribeInstanceTypes", mock.MatchedBy(func(in *ec2.DescribeInstanceTypesInput) bool {
+			input = in
+			return in.NextToken == nil
+		})).Return(&ec2.DescribeInstanceTypesOutput{
+			InstanceTypes: []*ec2.InstanceTypeInfo{instanceType("m5.xlarge", 0), instanceType("g4dn.xlarge", 1)},
+			NextToken:     aws.String("page-2"),
+		}, nil)
+		p.MockEC2().On("DescribeInstanceTypes", mock.MatchedBy(func(in *ec2.DescribeInstanceTypesInput) bool {
+			return aws.StringValue(in.NextToken) == "page-2"
+		})).Return(&ec2.DescribeInstanceTypesOutput{
+			InstanceTypes: []*ec2.InstanceTypeInfo{instanceType("m4.xlarge", 0)},
+		}, nil)
+	})
+
+	It("resolves a nodegroup to a mixed nodegroup of the matching instance types", func() {
+		ng := api.NewNodeGroup()
+		ng.Name = "ng-1"
+		ng.InstanceType = ""
+		ng.InstanceSelector = selector
+
+		Expect(NewNodeGroupService(cfg, p.EC2()).ExpandInstanceSelectors([]*api.NodeGroup{ng})).To(Succeed())
+		Expect(ng.InstanceType).To(Equal("mixed"))
+		Expect(ng.InstancesDistribution.InstanceTypes).To(Equal([]string{"m4.xlarge", "m5.xlarge"}))
+
+		filters := map[string][]string{}
+		for _, filter := range input.Filters {
+			filters[*filter.Name] = aws.StringValueSlice(filter.Values)
+		}
+		Expect(filters).To(HaveKeyWithValue("vcpu-info.default-vcpus", []string{"4"}))
+		Expect(filters).To(HaveKeyWithValue("memory-info.size-in-mib", []string{"16384"}))
+		Expect(filters).To(HaveKeyWithValue("processor-info.supported-architecture", []string{"x86_64"}))
+	})
+
+	It("resolves a managed nodegroup to the first matching GPU instance type", func() {
+		ng := api.NewManagedNodeGroup()
+		ng.Name = "ng-1"
+		selector.GPUs = 1
+		ng.InstanceSelector = selector
+
+		Expect(NewNodeGroupService(cfg, p.EC2()).ExpandManagedInstanceSelectors([]*api.ManagedNodeGroup{ng})).To(Succeed())
+		Expect(ng.InstanceType).To(Equal("g4dn.xlarge"))
+	})
+
+	It("fails when no instance types match", func() {
+		ng := api.NewNodeGroup()
+		ng.Name = "ng-1"
+		ng.InstanceType = ""
+		selector.GPUs = 8
+		ng.InstanceSelector = selector
+
+		err := NewNodeGroupService(cfg, p.EC2()).ExpandInstanceSelectors([]*api.NodeGroup{ng})
+		Expect(err).To(MatchError(ContainSubstring("no instance types match")))
+	})
+})
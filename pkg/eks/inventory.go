@@ -0,0 +1,59 @@
+package eks
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+)
+
+// ListOwnedResources lists everything eksctl owns in the cluster: the stacks along with their
+// resources, the EKS add-ons and the IAM OIDC provider, unless another tool created it
+func (c *ClusterProvider) ListOwnedResources(cfg *api.ClusterConfig) ([]*manager.OwnedResource, error) {
+	owned, err := c.NewStackManager(cfg).ListOwnedStackResources()
+	if err != nil {
+		return nil, err
+	}
+
+	addons, err := c.ListInstalledAddons(cfg)
+	if err != nil {
+		return nil, err
+	}
+	for _, addon := range addons {
+		owned = append(owned, &manager.OwnedResource{
+			Kind:      manager.OwnedResourceKindAddon,
+			Name:      aws.StringValue(addon.AddonName),
+			Type:      "AWS::EKS::Addon",
+			Status:    aws.StringValue(addon.Status),
+			CreatedAt: addon.CreatedAt,
+			Tags:      aws.StringValueMap(addon.Tags),
+		})
+	}
+
+	oidc, err := c.NewOpenIDConnectManager(cfg)
+	if err != nil {
+		if _, ok := err.(*UnsupportedOIDCError); ok {
+			return owned, nil
+		}
+		return nil, err
+	}
+	exists, err := oidc.CheckProviderExists()
+	if err != nil || !exists {
+		return owned, err
+	}
+	if isOwned, err := oidc.IsProviderOwned(); err != nil || !isOwned {
+		return owned, err
+	}
+	tags, err := oidc.ProviderTags()
+	if err != nil {
+		return nil, err
+	}
+	owned = append(owned, &manager.OwnedResource{
+		Kind:       manager.OwnedResourceKindOIDCProvider,
+		Name:       cfg.Metadata.Name,
+		Type:       "AWS::IAM::OIDCProvider",
+		PhysicalID: oidc.ProviderARN,
+		Tags:       tags,
+	})
+	return owned, nil
+}
@@ -0,0 +1,106 @@
+package eks
+
+import (
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+
+	defaultaddons "github.com/weaveworks/eksctl/pkg/addons/default"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ipusage"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// GetIPUsageInputs describes the subnets, the nodes and the instance types of the nodes of the
+// cluster for the IP usage report; the subnets are those of the control plane, of the cluster
+// stack and of the nodes
+func (c *ClusterProvider) GetIPUsageInputs(spec *api.ClusterConfig) (*ipusage.Inputs, error) {
+	if err := c.RefreshClusterStatus(spec); err != nil {
+		return nil, err
+	}
+	inputs := &ipusage.Inputs{ClusterName: spec.Metadata.Name}
+
+	subnetIDs := map[string]struct{}{}
+	if vpcConfig := c.Status.clusterInfo.cluster.ResourcesVpcConfig; vpcConfig != nil {
+		for _, subnetID := range aws.StringValueSlice(vpcConfig.SubnetIds) {
+			subnetIDs[subnetID] = struct{}{}
+		}
+	}
+	if err := c.LoadClusterVPC(spec); err != nil {
+		logger.Debug("unable to load the VPC of the cluster stack: %s", err.Error())
+	} else {
+		for _, subnetID := range append(spec.PrivateSubnetIDs(), spec.PublicSubnetIDs()...) {
+			subnetIDs[subnetID] = struct{}{}
+		}
+	}
+
+	if err := c.Provider.EC2().DescribeInstancesPages(&ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("tag-key"),
+				Values: aws.StringSlice([]string{"kubernetes.io/cluster/" + spec.Metadata.Name}),
+			},
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: aws.StringSlice([]string{ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning}),
+			},
+		},
+	}, func(page *ec2.DescribeInstancesOutput, _ bool) bool {
+		for _, reservation := range page.Reservations {
+			inputs.Instances = append(inputs.Instances, reservation.Instances...)
+		}
+		return true
+	}); err != nil {
+		return nil, errors.Wrapf(err, "describing the instances of cluster %q", spec.Metadata.Name)
+	}
+
+	instanceTypes := map[string]struct{}{}
+	for _, instance := range inputs.Instances {
+		instanceTypes[aws.StringValue(instance.InstanceType)] = struct{}{}
+		for _, eni := range instance.NetworkInterfaces {
+			subnetIDs[aws.StringValue(eni.SubnetId)] = struct{}{}
+		}
+	}
+	delete(subnetIDs, "")
+
+	if len(subnetIDs) > 0 {
+		output, err := c.Provider.EC2().DescribeSubnets(&ec2.DescribeSubnetsInput{
+			SubnetIds: aws.StringSlice(sortedKeys(subnetIDs)),
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "describing the subnets of cluster %q", spec.Metadata.Name)
+		}
+		inputs.Subnets = output.Subnets
+	}
+
+	if len(instanceTypes) > 0 {
+		output, err := c.Provider.EC2().DescribeInstanceTypes(&ec2.DescribeInstanceTypesInput{
+			InstanceTypes: aws.StringSlice(sortedKeys(instanceTypes)),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "describing instance types")
+		}
+		inputs.InstanceTypes = output.InstanceTypes
+	}
+
+	if rawClient, err := c.NewRawClient(spec); err != nil {
+		logger.Warning("unable to determine whether prefix delegation is enabled: %s", err.Error())
+	} else if enabled, err := defaultaddons.PrefixDelegationEnabled(rawClient.ClientSet()); err != nil {
+		logger.Warning("unable to determine whether prefix delegation is enabled: %s", err.Error())
+	} else {
+		inputs.PrefixDelegation = &enabled
+	}
+
+	return inputs, nil
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	var keys []string
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
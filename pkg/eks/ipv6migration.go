@@ -0,0 +1,34 @@
+package eks
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/ipv6migration"
+)
+
+// GetIPv6MigrationInputs describes the EKS add-ons and the workloads of the cluster for
+// previewing its migration to IPv6
+func (c *ClusterProvider) GetIPv6MigrationInputs(spec *api.ClusterConfig) (*ipv6migration.Inputs, error) {
+	inputs := &ipv6migration.Inputs{}
+
+	installed, err := c.ListInstalledAddons(spec)
+	if err != nil {
+		return nil, err
+	}
+	for _, addon := range installed {
+		inputs.Addons = append(inputs.Addons, ipv6migration.Addon{
+			Name:    aws.StringValue(addon.AddonName),
+			Version: aws.StringValue(addon.AddonVersion),
+		})
+	}
+
+	clientSet, err := c.NewStdClientSet(spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := ipv6migration.AddClusterInputs(clientSet, inputs); err != nil {
+		return nil, err
+	}
+	return inputs, nil
+}
@@ -0,0 +1,65 @@
+package eks
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// the Kubernetes network config of clusters isn't part of the version of aws-sdk-go eksctl uses yet
+
+type kubernetesNetworkConfigResponse struct {
+	_ struct{} `type:"structure"`
+
+	ServiceIpv4Cidr *string `locationName:"serviceIpv4Cidr" type:"string"`
+}
+
+type clusterKubernetesNetworkConfig struct {
+	_ struct{} `type:"structure"`
+
+	KubernetesNetworkConfig *kubernetesNetworkConfigResponse `locationName:"kubernetesNetworkConfig" type:"structure"`
+}
+
+type describeClusterNetworkConfigOutput struct {
+	_ struct{} `type:"structure"`
+
+	Cluster *clusterKubernetesNetworkConfig `locationName:"cluster" type:"structure"`
+}
+
+// LoadKubernetesNetworkConfig sets the service CIDR of cfg from the existing cluster, so that
+// nodes are bootstrapped with the cluster DNS IP EKS assigned; it fails if cfg sets a different one
+func (c *ClusterProvider) LoadKubernetesNetworkConfig(cfg *api.ClusterConfig) error {
+	client, err := c.eksClient()
+	if err != nil {
+		return err
+	}
+	op := &request.Operation{
+		Name:       "DescribeCluster",
+		HTTPMethod: "GET",
+		HTTPPath:   "/clusters/{name}",
+	}
+	output := &describeClusterNetworkConfigOutput{}
+	if err := client.NewRequest(op, &describeClusterAccessConfigInput{Name: &cfg.Metadata.Name}, output).Send(); err != nil {
+		return errors.Wrapf(err, "describing cluster %q", cfg.Metadata.Name)
+	}
+	if output.Cluster == nil || output.Cluster.KubernetesNetworkConfig == nil || output.Cluster.KubernetesNetworkConfig.ServiceIpv4Cidr == nil {
+		// clusters created before the service CIDR could be set use the EKS default
+		return nil
+	}
+	serviceCIDR := *output.Cluster.KubernetesNetworkConfig.ServiceIpv4Cidr
+
+	if cfg.HasServiceIPv4CIDR() {
+		if cfg.KubernetesNetworkConfig.ServiceIPv4CIDR != serviceCIDR {
+			return fmt.Errorf("kubernetesNetworkConfig.serviceIPv4CIDR (%s) doesn't match the service CIDR of cluster %q (%s), it can't be changed after the cluster is created",
+				cfg.KubernetesNetworkConfig.ServiceIPv4CIDR, cfg.Metadata.Name, serviceCIDR)
+		}
+		return nil
+	}
+	logger.Debug("using service CIDR %s of cluster %q", serviceCIDR, cfg.Metadata.Name)
+	cfg.KubernetesNetworkConfig = &api.KubernetesNetworkConfig{ServiceIPv4CIDR: serviceCIDR}
+	return nil
+}
@@ -0,0 +1,253 @@
+package eks
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/fargate"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/utils/ipnet"
+)
+
+// internalTagPrefixes are the prefixes of tags set by eksctl and AWS, which aren't copied
+var internalTagPrefixes = []string{"alpha.eksctl.io/", "eksctl.cluster.k8s.io/", "eksctl.io/", "aws:", "eks:"}
+
+// ClusterConfigLike describes an existing cluster and returns a config to create a similar
+// cluster, with the same version, networking, logging, OIDC provider, nodegroups and Fargate
+// profiles; the name and region are left unset, and regional resources, i.e. subnets, AMIs,
+// SSH keys and KMS keys, aren't copied
+func (c *ClusterProvider) ClusterConfigLike(name string) (*api.ClusterConfig, error) {
+	source := api.NewClusterConfig()
+	source.Metadata.Name = name
+	source.Metadata.Region = c.Provider.Region()
+
+	if err := c.RefreshClusterStatus(source); err != nil {
+		return nil, errors.Wrapf(err, "describing cluster %q", name)
+	}
+	cluster := c.Status.clusterInfo.cluster
+
+	cfg := api.NewClusterConfig()
+	cfg.Metadata.Version = aws.StringValue(cluster.Version)
+	cfg.Metadata.Tags = userTags(cluster.Tags)
+
+	if err := c.copyNetworking(cluster, cfg); err != nil {
+		return nil, err
+	}
+
+	if cluster.Logging != nil {
+		for _, logTypeGroup := range cluster.Logging.ClusterLogging {
+			if api.IsEnabled(logTypeGroup.Enabled) {
+				cfg.CloudWatch.ClusterLogging.EnableTypes = append(cfg.CloudWatch.ClusterLogging.EnableTypes, aws.StringValueSlice(logTypeGroup.Types)...)
+			}
+		}
+	}
+
+	oidc, err := c.NewOpenIDConnectManager(source)
+	if err != nil {
+		if _, ok := err.(*UnsupportedOIDCError); !ok {
+			return nil, err
+		}
+	} else {
+		exists, err := oidc.CheckProviderExists()
+		if err != nil {
+			return nil, errors.Wrapf(err, "checking the IAM OIDC provider of cluster %q", name)
+		}
+		cfg.IAM.WithOIDC = &exists
+	}
+
+	if err := c.copyNodeGroups(source, cfg); err != nil {
+		return nil, err
+	}
+
+	profiles, err := fargate.NewClient(name, c.Provider.EKS()).ReadProfiles()
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading the Fargate profiles of cluster %q", name)
+	}
+	for _, profile := range profiles {
+		// the subnets and the pod execution role are created with the cluster
+		profile.Subnets = nil
+		profile.PodExecutionRoleARN = ""
+		cfg.FargateProfiles = append(cfg.FargateProfiles, profile)
+	}
+
+	logger.Info("copied the configuration of cluster %q, SSH keys, custom AMIs and secrets encryption aren't copied as they are regional", name)
+	return cfg, nil
+}
+
+// copyNetworking copies the CIDR and NAT mode of the VPC, and the endpoint access of the cluster
+func (c *ClusterProvider) copyNetworking(cluster *awseks.Cluster, cfg *api.ClusterConfig) error {
+	vpcConfig := cluster.ResourcesVpcConfig
+	if vpcConfig == nil {
+		return nil
+	}
+
+	cfg.VPC.ClusterEndpoints = &api.ClusterEndpoints{
+		PrivateAccess: vpcConfig.EndpointPrivateAccess,
+		PublicAccess:  vpcConfig.EndpointPublicAccess,
+	}
+	if cidrs := aws.StringValueSlice(vpcConfig.PublicAccessCidrs); !(len(cidrs) == 1 && cidrs[0] == "0.0.0.0/0") {
+		cfg.VPC.PublicAccessCIDRs = cidrs
+	}
+
+	if vpcConfig.VpcId == nil {
+		return nil
+	}
+	vpcs, err := c.Provider.EC2().DescribeVpcs(&ec2.DescribeVpcsInput{
+		VpcIds: []*string{vpcConfig.VpcId},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "describing VPC %q", *vpcConfig.VpcId)
+	}
+	if len(vpcs.Vpcs) > 0 {
+		cidr, err := ipnet.ParseCIDR(aws.StringValue(vpcs.Vpcs[0].CidrBlock))
+		if err != nil {
+			return errors.Wrapf(err, "parsing the CIDR of VPC %q", *vpcConfig.VpcId)
+		}
+		cfg.VPC.CIDR = cidr
+	}
+
+	gateways, err := c.Provider.EC2().DescribeNatGateways(&ec2.DescribeNatGatewaysInput{
+		Filter: []*ec2.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: []*string{vpcConfig.VpcId},
+			},
+			{
+				Name:   aws.String("state"),
+				Values: aws.StringSlice([]string{ec2.NatGatewayStatePending, ec2.NatGatewayStateAvailable}),
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "describing the NAT gateways of VPC %q", *vpcConfig.VpcId)
+	}
+	switch len(gateways.NatGateways) {
+	case 0:
+		*cfg.VPC.NAT.Gateway = api.ClusterDisableNAT
+	case 1:
+		*cfg.VPC.NAT.Gateway = api.ClusterSingleNAT
+	default:
+		*cfg.VPC.NAT.Gateway = api.ClusterHighlyAvailableNAT
+	}
+	return nil
+}
+
+// AddExistingNodeGroups adds the managed and unmanaged nodegroups of the cluster to cfg, with
+// the settings ClusterConfigLike copies
+func (c *ClusterProvider) AddExistingNodeGroups(cfg *api.ClusterConfig) error {
+	return c.copyNodeGroups(cfg, cfg)
+}
+
+// copyNodeGroups copies managed nodegroups from EKS, and unmanaged nodegroups from their stacks
+func (c *ClusterProvider) copyNodeGroups(source, cfg *api.ClusterConfig) error {
+	name := source.Metadata.Name
+
+	if err := c.Provider.EKS().ListNodegroupsPages(&awseks.ListNodegroupsInput{ClusterName: &name}, func(page *awseks.ListNodegroupsOutput, _ bool) bool {
+		for _, ngName := range page.Nodegroups {
+			output, err := c.Provider.EKS().DescribeNodegroup(&awseks.DescribeNodegroupInput{
+				ClusterName:   &name,
+				NodegroupName: ngName,
+			})
+			if err != nil {
+				logger.Warning("unable to describe managed nodegroup %q: %v", *ngName, err)
+				continue
+			}
+			cfg.ManagedNodeGroups = append(cfg.ManagedNodeGroups, managedNodeGroupLike(output.Nodegroup))
+		}
+		return true
+	}); err != nil {
+		return errors.Wrapf(err, "listing the managed nodegroups of cluster %q", name)
+	}
+
+	stackManager := c.NewStackManager(source)
+	clusterStacks, err := stackManager.ListStacks()
+	if err != nil {
+		return errors.Wrapf(err, "listing the stacks of cluster %q", name)
+	}
+	if len(clusterStacks) == 0 {
+		logger.Warning("cluster %q wasn't created by eksctl, its unmanaged nodegroups aren't copied", name)
+		return nil
+	}
+
+	stacks, err := stackManager.ListNodeGroupStacks()
+	if err != nil {
+		return errors.Wrapf(err, "listing the nodegroup stacks of cluster %q", name)
+	}
+	unmanaged := map[string]bool{}
+	for _, stack := range stacks {
+		unmanaged[stack.NodeGroupName] = stack.Type == api.NodeGroupTypeUnmanaged
+	}
+
+	summaries, err := stackManager.GetNodeGroupSummaries("")
+	if err != nil {
+		return err
+	}
+	for _, summary := range summaries {
+		if !unmanaged[summary.Name] {
+			continue
+		}
+		ng := api.NewNodeGroup()
+		ng.Name = summary.Name
+		ng.InstanceType = summary.InstanceType
+		ng.MinSize = aws.Int(summary.MinSize)
+		ng.MaxSize = aws.Int(summary.MaxSize)
+		ng.DesiredCapacity = aws.Int(summary.DesiredCapacity)
+		ng.SSH.PublicKeyPath = nil
+		cfg.NodeGroups = append(cfg.NodeGroups, ng)
+	}
+	return nil
+}
+
+func managedNodeGroupLike(nodegroup *awseks.Nodegroup) *api.ManagedNodeGroup {
+	ng := api.NewManagedNodeGroup()
+	ng.Name = aws.StringValue(nodegroup.NodegroupName)
+	if len(nodegroup.InstanceTypes) > 0 {
+		ng.InstanceType = aws.StringValue(nodegroup.InstanceTypes[0])
+	}
+	if nodegroup.ScalingConfig != nil {
+		ng.ScalingConfig = &api.ScalingConfig{
+			MinSize:         int64Ptr(nodegroup.ScalingConfig.MinSize),
+			MaxSize:         int64Ptr(nodegroup.ScalingConfig.MaxSize),
+			DesiredCapacity: int64Ptr(nodegroup.ScalingConfig.DesiredSize),
+		}
+	}
+	if nodegroup.DiskSize != nil {
+		ng.VolumeSize = int64Ptr(nodegroup.DiskSize)
+	}
+	ng.Labels = aws.StringValueMap(nodegroup.Labels)
+	ng.Tags = userTags(nodegroup.Tags)
+	if strings.HasPrefix(aws.StringValue(nodegroup.AmiType), "AL2_") {
+		ng.AMIFamily = api.NodeImageFamilyAmazonLinux2
+	}
+	return ng
+}
+
+func int64Ptr(v *int64) *int {
+	if v == nil {
+		return nil
+	}
+	return aws.Int(int(*v))
+}
+
+// userTags returns the tags that weren't set by eksctl or AWS
+func userTags(tags map[string]*string) map[string]string {
+	userTags := map[string]string{}
+	for key, value := range tags {
+		internal := false
+		for _, prefix := range internalTagPrefixes {
+			if strings.HasPrefix(key, prefix) {
+				internal = true
+				break
+			}
+		}
+		if !internal {
+			userTags[key] = aws.StringValue(value)
+		}
+	}
+	return userTags
+}
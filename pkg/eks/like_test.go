@@ -0,0 +1,115 @@
+package eks_test
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/testutils"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("ClusterConfigLike", func() {
+	var (
+		p *mockprovider.MockProvider
+		c *ClusterProvider
+	)
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		c = &ClusterProvider{Provider: p, Status: &ProviderStatus{}}
+
+		cluster := testutils.NewFakeCluster("prod-cluster", awseks.ClusterStatusActive)
+		cluster.Version = aws.String("1.14")
+		cluster.Tags = aws.StringMap(map[string]string{
+			"team":                     "platform",
+			api.ClusterNameTag:         "prod-cluster",
+			"aws:cloudformation:stack": "eksctl-prod-cluster-cluster",
+		})
+		cluster.ResourcesVpcConfig.EndpointPrivateAccess = aws.Bool(true)
+		cluster.ResourcesVpcConfig.EndpointPublicAccess = aws.Bool(false)
+		cluster.ResourcesVpcConfig.PublicAccessCidrs = aws.StringSlice([]string{"0.0.0.0/0"})
+		cluster.Logging = &awseks.Logging{
+			ClusterLogging: []*awseks.LogSetup{
+				{Enabled: aws.Bool(true), Types: aws.StringSlice([]string{"api", "audit"})},
+				{Enabled: aws.Bool(false), Types: aws.StringSlice([]string{"scheduler"})},
+			},
+		}
+		p.MockEKS().On("DescribeCluster", mock.Anything).Return(&awseks.DescribeClusterOutput{Cluster: cluster}, nil)
+
+		p.MockEC2().On("DescribeVpcs", mock.Anything).Return(&ec2.DescribeVpcsOutput{
+			Vpcs: []*ec2.Vpc{{VpcId: aws.String("vpc-1234"), CidrBlock: aws.String("10.10.0.0/16")}},
+		}, nil)
+		p.MockEC2().On("DescribeNatGateways", mock.Anything).Return(&ec2.DescribeNatGatewaysOutput{
+			NatGateways: []*ec2.NatGateway{{}, {}, {}},
+		}, nil)
+
+		p.MockEKS().On("ListNodegroupsPages", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			pager := args.Get(1).(func(*awseks.ListNodegroupsOutput, bool) bool)
+			pager(&awseks.ListNodegroupsOutput{Nodegroups: aws.StringSlice([]string{"ng-1"})}, true)
+		}).Return(nil)
+		p.MockEKS().On("DescribeNodegroup", mock.Anything).Return(&awseks.DescribeNodegroupOutput{
+			Nodegroup: &awseks.Nodegroup{
+				NodegroupName: aws.String("ng-1"),
+				InstanceTypes: aws.StringSlice([]string{"m5.large"}),
+				AmiType:       aws.String(awseks.AMITypesAl2X8664),
+				DiskSize:      aws.Int64(50),
+				ScalingConfig: &awseks.NodegroupScalingConfig{
+					MinSize:     aws.Int64(1),
+					MaxSize:     aws.Int64(4),
+					DesiredSize: aws.Int64(2),
+				},
+				Labels: aws.StringMap(map[string]string{"role": "workers"}),
+				Tags:   aws.StringMap(map[string]string{api.NodeGroupNameTag: "ng-1", api.EKSClusterNameTag: "prod-cluster"}),
+			},
+		}, nil)
+
+		p.MockCloudFormation().On("ListStacksPages", mock.Anything, mock.Anything).Return(nil)
+		p.MockEKS().On("ListFargateProfiles", mock.Anything).Return(&awseks.ListFargateProfilesOutput{}, nil)
+	})
+
+	It("copies the configuration of the cluster", func() {
+		cfg, err := c.ClusterConfigLike("prod-cluster")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cfg.Metadata.Name).To(BeEmpty())
+		Expect(cfg.Metadata.Version).To(Equal("1.14"))
+		Expect(cfg.Metadata.Tags).To(Equal(map[string]string{"team": "platform"}))
+
+		Expect(cfg.VPC.CIDR.String()).To(Equal("10.10.0.0/16"))
+		Expect(*cfg.VPC.NAT.Gateway).To(Equal(api.ClusterHighlyAvailableNAT))
+		Expect(*cfg.VPC.ClusterEndpoints.PrivateAccess).To(BeTrue())
+		Expect(*cfg.VPC.ClusterEndpoints.PublicAccess).To(BeFalse())
+		Expect(cfg.VPC.PublicAccessCIDRs).To(BeEmpty())
+		Expect(cfg.CloudWatch.ClusterLogging.EnableTypes).To(ConsistOf("api", "audit"))
+
+		Expect(cfg.NodeGroups).To(BeEmpty())
+		Expect(cfg.ManagedNodeGroups).To(HaveLen(1))
+		ng := cfg.ManagedNodeGroups[0]
+		Expect(ng.Name).To(Equal("ng-1"))
+		Expect(ng.InstanceType).To(Equal("m5.large"))
+		Expect(ng.AMIFamily).To(Equal(api.NodeImageFamilyAmazonLinux2))
+		Expect(*ng.VolumeSize).To(Equal(50))
+		Expect(*ng.MinSize).To(Equal(1))
+		Expect(*ng.MaxSize).To(Equal(4))
+		Expect(*ng.DesiredCapacity).To(Equal(2))
+		Expect(ng.Labels).To(Equal(map[string]string{"role": "workers"}))
+		Expect(ng.Tags).To(BeEmpty())
+	})
+
+	It("fails when the cluster doesn't exist", func() {
+		p = mockprovider.NewMockProvider()
+		c = &ClusterProvider{Provider: p, Status: &ProviderStatus{}}
+		p.MockEKS().On("DescribeCluster", mock.Anything).Return(nil, errors.New("ResourceNotFoundException: No cluster found for name: prod-cluster"))
+
+		_, err := c.ClusterConfigLike("prod-cluster")
+		Expect(err).To(MatchError(HavePrefix(`describing cluster "prod-cluster"`)))
+	})
+})
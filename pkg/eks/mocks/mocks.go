@@ -3,11 +3,18 @@ package mocks
 import (
 	_ "github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface" // used for testing
 	_ "github.com/aws/aws-sdk-go/service/cloudtrail/cloudtrailiface"
+	_ "github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
 	_ "github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	_ "github.com/aws/aws-sdk-go/service/eks/eksiface"
 	_ "github.com/aws/aws-sdk-go/service/elb/elbiface"
 	_ "github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+	_ "github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface"
 	_ "github.com/aws/aws-sdk-go/service/iam/iamiface"
+	_ "github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	_ "github.com/aws/aws-sdk-go/service/pricing/pricingiface"
+	_ "github.com/aws/aws-sdk-go/service/route53/route53iface"
+	_ "github.com/aws/aws-sdk-go/service/s3/s3iface"
+	_ "github.com/aws/aws-sdk-go/service/sns/snsiface"
 	_ "github.com/aws/aws-sdk-go/service/sts/stsiface"
 	_ "github.com/vektra/mockery"
 )
@@ -22,3 +29,15 @@ import (
 //go:generate "${GOBIN}/mockery" -tags netgo -dir=../../../vendor/github.com/aws/aws-sdk-go/service/iam/iamiface -name=IAMAPI -output=./
 //go:generate "${GOBIN}/mockery" -tags netgo -dir=../../../vendor/github.com/aws/aws-sdk-go/service/cloudtrail/cloudtrailiface -name=CloudTrailAPI -output=./
 //go:generate "${GOBIN}/mockery" -tags netgo -dir=../../../vendor/github.com/aws/aws-sdk-go/service/ssm/ssmiface -name=SSMAPI -output=./
+//go:generate "${GOBIN}/mockery" -tags netgo -dir=../../../vendor/github.com/aws/aws-sdk-go/service/kms/kmsiface -name=KMSAPI -output=./
+//go:generate "${GOBIN}/mockery" -tags netgo -dir=../../../vendor/github.com/aws/aws-sdk-go/service/sns/snsiface -name=SNSAPI -output=./
+//go:generate "${GOBIN}/mockery" -tags netgo -dir=../../../vendor/github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface -name=EventBridgeAPI -output=./
+//go:generate "${GOBIN}/mockery" -tags netgo -dir=../../../vendor/github.com/aws/aws-sdk-go/service/s3/s3iface -name=S3API -output=./
+//go:generate "${GOBIN}/mockery" -tags netgo -dir=../../../vendor/github.com/aws/aws-sdk-go/service/pricing/pricingiface -name=PricingAPI -output=./
+//go:generate "${GOBIN}/mockery" -tags netgo -dir=../../../vendor/github.com/aws/aws-sdk-go/service/route53/route53iface -name=Route53API -output=./
+//go:generate "${GOBIN}/mockery" -tags netgo -dir=../../../vendor/github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface -name=CloudWatchLogsAPI -output=./
+
+// the APIs of the services the version of aws-sdk-go eksctl uses predates are defined in eksctl
+//go:generate "${GOBIN}/mockery" -tags netgo -dir=../../inspector -name=API -structname=InspectorAPI -output=./
+//go:generate "${GOBIN}/mockery" -tags netgo -dir=../../eksaddons -name=API -structname=AddonsAPI -output=./
+//go:generate "${GOBIN}/mockery" -tags netgo -dir=../../billing -name=CostAllocationTagsAPI -output=./
@@ -5,7 +5,6 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws/arn"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
@@ -13,6 +12,7 @@ import (
 	"github.com/weaveworks/eksctl/pkg/utils"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -186,6 +186,26 @@ func LogWindowsCompatibility(nodeGroups []KubeNodeGroup, clusterMeta *api.Cluste
 	}
 }
 
+// LogNodeTerminationHandlerCompatibility warns when AWS Node Termination Handler is enabled
+// without any unmanaged nodegroup that runs Spot instances, as managed nodegroups drain
+// their nodes on interruptions and terminations by themselves
+func LogNodeTerminationHandlerCompatibility(cfg *api.ClusterConfig) {
+	if !cfg.HasNodeTerminationHandler() {
+		return
+	}
+	for _, ng := range cfg.NodeGroups {
+		// in queue mode, the lifecycle hooks of on-demand nodegroups are handled too
+		if api.HasSpotInstances(ng) || cfg.HasNodeTerminationHandlerQueue() {
+			return
+		}
+	}
+	if len(cfg.ManagedNodeGroups) > 0 {
+		logger.Warning("AWS Node Termination Handler is not needed for managed nodegroups, as they drain their nodes by themselves")
+		return
+	}
+	logger.Warning("AWS Node Termination Handler is enabled, but none of the nodegroups run Spot instances")
+}
+
 // KubeNodeGroup defines a set of Kubernetes Nodes
 type KubeNodeGroup interface {
 	// NameString returns the name
@@ -204,7 +224,7 @@ func (c *ClusterProvider) WaitForNodes(clientSet kubernetes.Interface, ng KubeNo
 	if minSize == 0 {
 		return nil
 	}
-	timer := time.After(c.Provider.WaitTimeout())
+	timer := time.After(c.Provider.Timeout(api.TimeoutPhaseNodeGroups))
 	timeout := false
 	readyNodes := sets.NewString()
 	watcher, err := clientSet.CoreV1().Nodes().Watch(ng.ListOptions())
@@ -240,7 +260,8 @@ func (c *ClusterProvider) WaitForNodes(clientSet kubernetes.Interface, ng KubeNo
 	}
 	watcher.Stop()
 	if timeout {
-		return fmt.Errorf("timed out (after %s) waiting for at least %d nodes to join the cluster and become ready in %q", c.Provider.WaitTimeout(), minSize, ng.NameString())
+		waitTimeout := c.Provider.Timeout(api.TimeoutPhaseNodeGroups)
+		return api.TimeoutPhaseNodeGroups.WrapTimeout(fmt.Errorf("timed out (after %s) waiting for at least %d nodes to join the cluster and become ready in %q", waitTimeout, minSize, ng.NameString()), waitTimeout)
 	}
 
 	if _, err = getNodes(clientSet, ng); err != nil {
@@ -252,6 +273,14 @@ func (c *ClusterProvider) WaitForNodes(clientSet kubernetes.Interface, ng KubeNo
 
 // GetNodeGroupIAM retrieves the IAM configuration of the given nodegroup
 func (c *ClusterProvider) GetNodeGroupIAM(stackManager *manager.StackCollection, spec *api.ClusterConfig, ng *api.NodeGroup) error {
+	if ng.Account != nil {
+		s, err := stackManager.DescribeCrossAccountNodeGroupStack(ng)
+		if err != nil {
+			return err
+		}
+		return iam.UseFromNodeGroup(c.Provider, s, ng)
+	}
+
 	stacks, err := stackManager.DescribeNodeGroupStacks()
 	if err != nil {
 		return err
@@ -0,0 +1,102 @@
+package eks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+
+	defaultaddons "github.com/weaveworks/eksctl/pkg/addons/default"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// ValidatePodSecurityGroupsSupport checks that the instance types of the nodegroups support ENI
+// trunking, which security groups for pods require; that is, Nitro instance types other than
+// the burstable ones
+func (m *NodeGroupService) ValidatePodSecurityGroupsSupport(nodeGroups []*api.NodeGroup, managedNodeGroups []*api.ManagedNodeGroup) error {
+	if !m.cluster.HasPodSecurityGroups() {
+		return nil
+	}
+	if api.IsSetAndNonEmptyString(m.cluster.IAM.ServiceRoleARN) {
+		logger.Warning("the AmazonEKSVPCResourceController policy must be attached to the service role %q for security groups for pods to work", *m.cluster.IAM.ServiceRoleARN)
+	}
+
+	nodeGroupsByType := map[string][]string{}
+	for _, ng := range nodeGroups {
+		if ng.InstancesDistribution != nil && len(ng.InstancesDistribution.InstanceTypes) > 0 {
+			for _, instanceType := range ng.InstancesDistribution.InstanceTypes {
+				nodeGroupsByType[instanceType] = append(nodeGroupsByType[instanceType], ng.Name)
+			}
+		} else if ng.InstanceType != "" {
+			nodeGroupsByType[ng.InstanceType] = append(nodeGroupsByType[ng.InstanceType], ng.Name)
+		}
+	}
+	for _, ng := range managedNodeGroups {
+		if ng.InstanceType != "" {
+			nodeGroupsByType[ng.InstanceType] = append(nodeGroupsByType[ng.InstanceType], ng.Name)
+		}
+	}
+	if len(nodeGroupsByType) == 0 {
+		return nil
+	}
+
+	var instanceTypes []string
+	for instanceType := range nodeGroupsByType {
+		instanceTypes = append(instanceTypes, instanceType)
+	}
+	sort.Strings(instanceTypes)
+
+	output, err := m.ec2API.DescribeInstanceTypes(&ec2.DescribeInstanceTypesInput{
+		InstanceTypes: aws.StringSlice(instanceTypes),
+	})
+	if err != nil {
+		return errors.Wrap(err, "describing instance types")
+	}
+
+	var unsupported []string
+	for _, info := range output.InstanceTypes {
+		if supportsTrunking(info) {
+			continue
+		}
+		instanceType := aws.StringValue(info.InstanceType)
+		unsupported = append(unsupported, fmt.Sprintf("%s (nodegroups %s)", instanceType, strings.Join(nodeGroupsByType[instanceType], ", ")))
+	}
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return fmt.Errorf("security groups for pods are enabled, but these instance types don't support ENI trunking: %s", strings.Join(unsupported, "; "))
+	}
+	return nil
+}
+
+func supportsTrunking(info *ec2.InstanceTypeInfo) bool {
+	return aws.StringValue(info.Hypervisor) == ec2.InstanceTypeHypervisorNitro && !aws.BoolValue(info.BurstablePerformanceSupported)
+}
+
+// EnablePodSecurityGroups enables pod ENIs in the VPC CNI plugin of the cluster, and creates the
+// sample SecurityGroupPolicy if requested
+func (c *ClusterProvider) EnablePodSecurityGroups(cfg *api.ClusterConfig) error {
+	rawClient, err := c.NewRawClient(cfg)
+	if err != nil {
+		return err
+	}
+	addonsAPI, err := c.NewAddonsAPI()
+	if err != nil {
+		return err
+	}
+	if err := defaultaddons.EnablePodENI(rawClient.ClientSet(), addonsAPI, cfg.Metadata.Name); err != nil {
+		return err
+	}
+	if !api.IsEnabled(cfg.VPC.PodSecurityGroups.CreateSamplePolicy) {
+		return nil
+	}
+	if err := defaultaddons.CreateSampleSecurityGroupPolicy(rawClient, cfg.VPC.SharedNodeSecurityGroup); err != nil {
+		return err
+	}
+	logger.Info("created SecurityGroupPolicy %q, pods labelled with security-groups-for-pods=sample in the default namespace will use security group %q",
+		defaultaddons.SampleSecurityGroupPolicy, cfg.VPC.SharedNodeSecurityGroup)
+	return nil
+}
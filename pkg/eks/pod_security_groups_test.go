@@ -0,0 +1,58 @@
+package eks_test
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("Security groups for pods", func() {
+	var (
+		p   *mockprovider.MockProvider
+		cfg *api.ClusterConfig
+		ng  *api.NodeGroup
+	)
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg = api.NewClusterConfig()
+		cfg.VPC.PodSecurityGroups = &api.PodSecurityGroups{Enabled: api.Enabled()}
+		ng = cfg.NewNodeGroup()
+		ng.Name = "ng-1"
+
+		p.MockEC2().On("DescribeInstanceTypes", mock.Anything).Return(&ec2.DescribeInstanceTypesOutput{
+			InstanceTypes: []*ec2.InstanceTypeInfo{
+				{InstanceType: aws.String("m5.large"), Hypervisor: aws.String("nitro"), BurstablePerformanceSupported: aws.Bool(false)},
+				{InstanceType: aws.String("t3.large"), Hypervisor: aws.String("nitro"), BurstablePerformanceSupported: aws.Bool(true)},
+				{InstanceType: aws.String("m4.large"), Hypervisor: aws.String("xen"), BurstablePerformanceSupported: aws.Bool(false)},
+			},
+		}, nil)
+	})
+
+	It("accepts instance types that support ENI trunking", func() {
+		ng.InstanceType = "m5.large"
+		Expect(NewNodeGroupService(cfg, p.EC2()).ValidatePodSecurityGroupsSupport(cfg.NodeGroups, nil)).To(Succeed())
+	})
+
+	It("rejects burstable and non-Nitro instance types", func() {
+		ng.InstanceType = "mixed"
+		ng.InstancesDistribution = &api.NodeGroupInstancesDistribution{InstanceTypes: []string{"m5.large", "t3.large"}}
+		mng := &api.ManagedNodeGroup{Name: "mng-1", InstanceType: "m4.large"}
+
+		err := NewNodeGroupService(cfg, p.EC2()).ValidatePodSecurityGroupsSupport(cfg.NodeGroups, []*api.ManagedNodeGroup{mng})
+		Expect(err).To(MatchError("security groups for pods are enabled, but these instance types don't support ENI trunking: m4.large (nodegroups mng-1); t3.large (nodegroups ng-1)"))
+	})
+
+	It("doesn't check instance types when security groups for pods are disabled", func() {
+		cfg.VPC.PodSecurityGroups = nil
+		ng.InstanceType = "t3.large"
+		Expect(NewNodeGroupService(cfg, p.EC2()).ValidatePodSecurityGroupsSupport(cfg.NodeGroups, nil)).To(Succeed())
+		p.MockEC2().AssertNotCalled(GinkgoT(), "DescribeInstanceTypes", mock.Anything)
+	})
+})
@@ -0,0 +1,116 @@
+package eks
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/posture"
+)
+
+// GetSecurityPostureInputs describes the cluster, its nodes and its IAM OIDC provider for
+// the security posture checks; what can't be determined is left unset, and fails the checks
+func (c *ClusterProvider) GetSecurityPostureInputs(spec *api.ClusterConfig) (*posture.Inputs, error) {
+	if err := c.RefreshClusterStatus(spec); err != nil {
+		return nil, err
+	}
+	inputs := &posture.Inputs{Cluster: c.Status.clusterInfo.cluster}
+
+	if encrypted, err := c.secretsEncryptionEnabled(spec.Metadata.Name); err != nil {
+		logger.Warning("unable to determine whether secrets are encrypted: %s", err.Error())
+	} else {
+		inputs.SecretsEncryption = &encrypted
+	}
+
+	if err := c.Provider.EC2().DescribeInstancesPages(&ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("tag-key"),
+				Values: aws.StringSlice([]string{"kubernetes.io/cluster/" + spec.Metadata.Name}),
+			},
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: aws.StringSlice([]string{ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning}),
+			},
+		},
+	}, func(page *ec2.DescribeInstancesOutput, _ bool) bool {
+		for _, reservation := range page.Reservations {
+			inputs.Instances = append(inputs.Instances, reservation.Instances...)
+		}
+		return true
+	}); err != nil {
+		return nil, errors.Wrapf(err, "describing the instances of cluster %q", spec.Metadata.Name)
+	}
+
+	oidc, err := c.NewOpenIDConnectManager(spec)
+	if err != nil {
+		if _, ok := err.(*UnsupportedOIDCError); !ok {
+			return nil, err
+		}
+		exists := false
+		inputs.OIDCProviderExists = &exists
+	} else if exists, err := oidc.CheckProviderExists(); err != nil {
+		logger.Warning("unable to determine whether the IAM OIDC provider exists: %s", err.Error())
+	} else {
+		inputs.OIDCProviderExists = &exists
+	}
+
+	return inputs, nil
+}
+
+type encryptionConfig struct {
+	_ struct{} `type:"structure"`
+
+	Resources []*string `locationName:"resources" type:"list"`
+}
+
+type clusterEncryption struct {
+	_ struct{} `type:"structure"`
+
+	EncryptionConfig []*encryptionConfig `locationName:"encryptionConfig" type:"list"`
+}
+
+type describeClusterEncryptionInput struct {
+	_ struct{} `type:"structure"`
+
+	Name *string `location:"uri" locationName:"name" type:"string" required:"true"`
+}
+
+type describeClusterEncryptionOutput struct {
+	_ struct{} `type:"structure"`
+
+	Cluster *clusterEncryption `locationName:"cluster" type:"structure"`
+}
+
+// secretsEncryptionEnabled describes the encryption config of the cluster, which isn't
+// part of the version of aws-sdk-go eksctl uses yet
+func (c *ClusterProvider) secretsEncryptionEnabled(clusterName string) (bool, error) {
+	client, ok := c.Provider.EKS().(*awseks.EKS)
+	if !ok {
+		return false, errors.New("the EKS API doesn't support describing the encryption config")
+	}
+	op := &request.Operation{
+		Name:       "DescribeCluster",
+		HTTPMethod: "GET",
+		HTTPPath:   "/clusters/{name}",
+	}
+	output := &describeClusterEncryptionOutput{}
+	if err := client.NewRequest(op, &describeClusterEncryptionInput{Name: &clusterName}, output).Send(); err != nil {
+		return false, err
+	}
+	if output.Cluster == nil {
+		return false, nil
+	}
+	for _, config := range output.Cluster.EncryptionConfig {
+		for _, resource := range config.Resources {
+			if aws.StringValue(resource) == "secrets" {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
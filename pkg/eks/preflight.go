@@ -0,0 +1,155 @@
+package eks
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/eksaddons"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/preflight"
+)
+
+// GetPreflightInputs describes the add-ons, the upgrade insights and the Kubernetes objects of
+// the cluster for the checks before upgrading the control plane to targetVersion; the checks
+// whose inputs can't be determined from the EKS API are marked as unavailable
+func (c *ClusterProvider) GetPreflightInputs(spec *api.ClusterConfig, currentVersion, targetVersion string) (*preflight.Inputs, error) {
+	inputs := &preflight.Inputs{
+		Cluster:        spec.Metadata.Name,
+		CurrentVersion: currentVersion,
+		TargetVersion:  targetVersion,
+		Unavailable:    map[string]string{},
+	}
+
+	if addons, err := c.preflightAddons(spec.Metadata.Name, targetVersion); err != nil {
+		logger.Warning("unable to determine whether the EKS add-ons support Kubernetes %s: %s", targetVersion, err.Error())
+		inputs.Unavailable[preflight.CheckAddonCompatibility] = "unable to describe the EKS add-ons of the cluster"
+	} else {
+		inputs.Addons = addons
+	}
+
+	if insights, err := c.listUpgradeInsights(spec.Metadata.Name, targetVersion); err != nil {
+		logger.Warning("unable to list the EKS upgrade insights of the cluster: %s", err.Error())
+		inputs.Unavailable[preflight.CheckUpgradeInsights] = "EKS upgrade insights aren't available for the cluster"
+	} else {
+		inputs.Insights = insights
+	}
+
+	clientSet, err := c.NewStdClientSet(spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := preflight.AddClusterInputs(clientSet, inputs); err != nil {
+		return nil, err
+	}
+	return inputs, nil
+}
+
+func (c *ClusterProvider) preflightAddons(clusterName, targetVersion string) ([]preflight.Addon, error) {
+	addonsAPI, err := c.NewAddonsAPI()
+	if err != nil {
+		return nil, err
+	}
+	installed, err := eksaddons.ListInstalled(addonsAPI, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	var addons []preflight.Addon
+	for _, addon := range installed {
+		name := aws.StringValue(addon.AddonName)
+		versions, err := eksaddons.CompatibleVersions(addonsAPI, name, targetVersion)
+		if err != nil {
+			return nil, err
+		}
+		addons = append(addons, preflight.Addon{
+			Name:               name,
+			Version:            aws.StringValue(addon.AddonVersion),
+			CompatibleVersions: versions,
+		})
+	}
+	return addons, nil
+}
+
+type insightStatus struct {
+	_ struct{} `type:"structure"`
+
+	Status *string `locationName:"status" type:"string"`
+	Reason *string `locationName:"reason" type:"string"`
+}
+
+type insightSummary struct {
+	_ struct{} `type:"structure"`
+
+	Name              *string        `locationName:"name" type:"string"`
+	Category          *string        `locationName:"category" type:"string"`
+	KubernetesVersion *string        `locationName:"kubernetesVersion" type:"string"`
+	Description       *string        `locationName:"description" type:"string"`
+	InsightStatus     *insightStatus `locationName:"insightStatus" type:"structure"`
+}
+
+type insightsFilter struct {
+	_ struct{} `type:"structure"`
+
+	Categories         []*string `locationName:"categories" type:"list"`
+	KubernetesVersions []*string `locationName:"kubernetesVersions" type:"list"`
+}
+
+type listInsightsInput struct {
+	_ struct{} `type:"structure"`
+
+	ClusterName *string         `location:"uri" locationName:"name" type:"string" required:"true"`
+	Filter      *insightsFilter `locationName:"filter" type:"structure"`
+	NextToken   *string         `locationName:"nextToken" type:"string"`
+}
+
+type listInsightsOutput struct {
+	_ struct{} `type:"structure"`
+
+	Insights  []*insightSummary `locationName:"insights" type:"list"`
+	NextToken *string           `locationName:"nextToken" type:"string"`
+}
+
+// listUpgradeInsights lists the upgrade readiness insights of EKS for targetVersion, which
+// aren't part of the version of aws-sdk-go eksctl uses yet
+func (c *ClusterProvider) listUpgradeInsights(clusterName, targetVersion string) ([]preflight.Insight, error) {
+	client, ok := c.Provider.EKS().(*awseks.EKS)
+	if !ok {
+		return nil, errors.New("the EKS API doesn't support upgrade insights")
+	}
+	op := &request.Operation{
+		Name:       "ListInsights",
+		HTTPMethod: "POST",
+		HTTPPath:   "/clusters/{name}/insights",
+	}
+	input := &listInsightsInput{
+		ClusterName: &clusterName,
+		Filter: &insightsFilter{
+			Categories:         aws.StringSlice([]string{"UPGRADE_READINESS"}),
+			KubernetesVersions: aws.StringSlice([]string{targetVersion}),
+		},
+	}
+	var insights []preflight.Insight
+	for {
+		output := &listInsightsOutput{}
+		if err := client.NewRequest(op, input, output).Send(); err != nil {
+			return nil, err
+		}
+		for _, summary := range output.Insights {
+			insight := preflight.Insight{
+				Name:        aws.StringValue(summary.Name),
+				Description: aws.StringValue(summary.Description),
+			}
+			if summary.InsightStatus != nil {
+				insight.Status = aws.StringValue(summary.InsightStatus.Status)
+				insight.Reason = aws.StringValue(summary.InsightStatus.Reason)
+			}
+			insights = append(insights, insight)
+		}
+		if output.NextToken == nil {
+			return insights, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
@@ -0,0 +1,75 @@
+package eks
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/privatedns"
+)
+
+// SyncPrivateHostedZone enables DNS in the cluster VPC and the VPCs of the private hosted
+// zone, and points the API server endpoint to its current private IPs in the hosted zone
+func (c *ClusterProvider) SyncPrivateHostedZone(cfg *api.ClusterConfig) error {
+	if err := c.RefreshClusterStatus(cfg); err != nil {
+		return err
+	}
+	cluster := c.Status.clusterInfo.cluster
+	if cluster.ResourcesVpcConfig == nil || !aws.BoolValue(cluster.ResourcesVpcConfig.EndpointPrivateAccess) {
+		return fmt.Errorf("private endpoint access isn't enabled in cluster %q", cfg.Metadata.Name)
+	}
+	hostname, err := endpointHostname(aws.StringValue(cluster.Endpoint))
+	if err != nil {
+		return err
+	}
+
+	clusterVPCID := aws.StringValue(cluster.ResourcesVpcConfig.VpcId)
+	vpcIDs := cfg.VPC.PrivateHostedZone.AssociatedVPCIDs
+	for _, vpcID := range vpcIDs {
+		if vpcID == clusterVPCID {
+			return fmt.Errorf("VPC %q is the VPC of cluster %q, where EKS already resolves the API server endpoint to its private IPs", vpcID, cfg.Metadata.Name)
+		}
+	}
+	for _, vpcID := range append([]string{clusterVPCID}, vpcIDs...) {
+		if err := privatedns.EnableVPCDNS(c.Provider.EC2(), vpcID); err != nil {
+			return err
+		}
+	}
+
+	ips, err := privatedns.EndpointIPs(c.Provider.EC2(), cfg.Metadata.Name, clusterVPCID)
+	if err != nil {
+		return err
+	}
+	if len(ips) == 0 {
+		return fmt.Errorf("no network interfaces of the API server endpoint of cluster %q were found in VPC %q", cfg.Metadata.Name, clusterVPCID)
+	}
+	return privatedns.Sync(c.Provider.Route53(), cfg.Metadata.Name, hostname, c.Provider.Region(), vpcIDs, ips)
+}
+
+// DeletePrivateHostedZone deletes the private hosted zone eksctl created for the API server
+// endpoint of the cluster, if any
+func (c *ClusterProvider) DeletePrivateHostedZone(cfg *api.ClusterConfig) error {
+	if err := c.maybeRefreshClusterStatus(cfg); err != nil {
+		return err
+	}
+	cluster := c.Status.clusterInfo.cluster
+	if cluster.ResourcesVpcConfig == nil || !aws.BoolValue(cluster.ResourcesVpcConfig.EndpointPrivateAccess) {
+		return nil
+	}
+	hostname, err := endpointHostname(aws.StringValue(cluster.Endpoint))
+	if err != nil {
+		return err
+	}
+	return privatedns.Delete(c.Provider.Route53(), cfg.Metadata.Name, hostname)
+}
+
+func endpointHostname(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Hostname() == "" {
+		return "", errors.Errorf("unable to parse the API server endpoint %q", endpoint)
+	}
+	return u.Hostname(), nil
+}
@@ -0,0 +1,129 @@
+package eks
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cfn/manager"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// RegionsEnvVar is a comma-separated list of the regions queried with --all-regions,
+// so that fleet operators don't have to pass --regions to every command
+const RegionsEnvVar = "EKSCTL_REGIONS"
+
+// maxConcurrentRegions is the number of regions queried at the same time
+const maxConcurrentRegions = 8
+
+// Regions returns the regions to query with --all-regions; the given allowlist takes
+// precedence over RegionsEnvVar, and all supported regions are queried otherwise
+func Regions(allowlist []string) ([]string, error) {
+	if len(allowlist) == 0 {
+		if regions := os.Getenv(RegionsEnvVar); regions != "" {
+			allowlist = strings.Split(regions, ",")
+		}
+	}
+	if len(allowlist) == 0 {
+		return api.SupportedRegions(), nil
+	}
+
+	supported := map[string]bool{}
+	for _, region := range api.SupportedRegions() {
+		supported[region] = true
+	}
+	var regions []string
+	seen := map[string]bool{}
+	for _, region := range allowlist {
+		region = strings.TrimSpace(region)
+		if !supported[region] {
+			return nil, fmt.Errorf("region %q is not supported - use one of: %s", region, strings.Join(api.SupportedRegions(), ", "))
+		}
+		if !seen[region] {
+			seen[region] = true
+			regions = append(regions, region)
+		}
+	}
+	return regions, nil
+}
+
+// ForEachRegion calls fn concurrently with a provider for each of the regions, which has the
+// same settings as c otherwise; fn gets the index of the region, so that results can be merged
+// in the order of the regions, and errors are logged, as one region shouldn't fail the others
+func (c *ClusterProvider) ForEachRegion(regions []string, fn func(i int, ctl *ClusterProvider) error) {
+	var (
+		wg        sync.WaitGroup
+		semaphore = make(chan struct{}, maxConcurrentRegions)
+	)
+	for i, region := range regions {
+		wg.Add(1)
+		go func(i int, region string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			if err := fn(i, c.regionalProvider(region)); err != nil {
+				logger.Critical("error querying %q region: %s", region, err.Error())
+			}
+		}(i, region)
+	}
+	wg.Wait()
+}
+
+// regionalProvider returns a provider for the region with the settings of c, e.g. the profile
+// and the endpoint overrides
+func (c *ClusterProvider) regionalProvider(region string) *ClusterProvider {
+	spec := api.ProviderConfig{
+		Profile:     c.Provider.Profile(),
+		WaitTimeout: c.Provider.WaitTimeout(),
+	}
+	if p, ok := c.Provider.(*ProviderServices); ok {
+		spec = *p.spec
+	}
+	spec.Region = region
+	return New(&spec, nil)
+}
+
+// GetNodeGroupSummariesInRegions returns the summaries of the nodegroups of the cluster in each of
+// the regions, or of all clusters when clusterName is empty; clusters that weren't created by
+// eksctl have no nodegroup stacks, and are skipped
+func (c *ClusterProvider) GetNodeGroupSummariesInRegions(clusterName, nodeGroupName string, chunkSize int, regions []string) []*manager.NodeGroupSummary {
+	results := make([][]*manager.NodeGroupSummary, len(regions))
+	c.ForEachRegion(regions, func(i int, ctl *ClusterProvider) error {
+		clusterNames := []string{clusterName}
+		if clusterName == "" {
+			clusters := []*api.ClusterMeta{}
+			if err := ctl.doListClusters(int64(chunkSize), &clusters, nil); err != nil {
+				return err
+			}
+			clusterNames = nil
+			for _, cluster := range clusters {
+				clusterNames = append(clusterNames, cluster.Name)
+			}
+		}
+
+		for _, name := range clusterNames {
+			spec := api.NewClusterConfig()
+			spec.Metadata.Name = name
+			spec.Metadata.Region = ctl.Provider.Region()
+			summaries, err := ctl.NewStackManager(spec).GetNodeGroupSummaries(nodeGroupName)
+			if err != nil {
+				logger.Debug("skipping cluster %q in %q region: %s", name, spec.Metadata.Region, err.Error())
+				continue
+			}
+			for _, summary := range summaries {
+				summary.Region = spec.Metadata.Region
+			}
+			results[i] = append(results[i], summaries...)
+		}
+		return nil
+	})
+
+	allSummaries := []*manager.NodeGroupSummary{}
+	for _, summaries := range results {
+		allSummaries = append(allSummaries, summaries...)
+	}
+	return allSummaries
+}
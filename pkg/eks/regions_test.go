@@ -0,0 +1,36 @@
+package eks_test
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/eks"
+)
+
+var _ = Describe("Regions", func() {
+	AfterEach(func() {
+		os.Unsetenv(RegionsEnvVar)
+	})
+
+	It("defaults to all supported regions", func() {
+		Expect(Regions(nil)).To(Equal(api.SupportedRegions()))
+	})
+
+	It("uses the regions of the environment", func() {
+		os.Setenv(RegionsEnvVar, "eu-west-1, us-west-2")
+		Expect(Regions(nil)).To(Equal([]string{"eu-west-1", "us-west-2"}))
+	})
+
+	It("prefers the given regions, without duplicates", func() {
+		os.Setenv(RegionsEnvVar, "eu-west-1")
+		Expect(Regions([]string{"us-east-1", "us-west-2", "us-east-1"})).To(Equal([]string{"us-east-1", "us-west-2"}))
+	})
+
+	It("rejects unsupported regions", func() {
+		_, err := Regions([]string{"mars-north-1"})
+		Expect(err).To(MatchError(HavePrefix(`region "mars-north-1" is not supported`)))
+	})
+})
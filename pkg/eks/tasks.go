@@ -1,7 +1,6 @@
 package eks
 
 import (
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"github.com/weaveworks/eksctl/pkg/addons"
 	iamoidc "github.com/weaveworks/eksctl/pkg/iam/oidc"
@@ -9,6 +8,7 @@ import (
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cfn/manager"
 	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 type clusterConfigTask struct {
@@ -39,13 +39,71 @@ func (v *vpcControllerTask) Do(errCh chan error) error {
 	if err != nil {
 		return err
 	}
-	vpcController := addons.NewVPCController(rawClient, v.spec.Status, v.clusterProvider.Provider.Region(), false)
+	vpcController := addons.NewVPCController(rawClient, v.spec.Status, v.clusterProvider.Provider.Region(), v.spec.ContainerRegistryOverride, false)
 	if err := vpcController.Deploy(); err != nil {
 		return errors.Wrap(err, "error installing VPC controller")
 	}
 	return nil
 }
 
+type nodeTerminationHandlerTask struct {
+	info            string
+	clusterProvider *ClusterProvider
+	spec            *api.ClusterConfig
+}
+
+func (n *nodeTerminationHandlerTask) Describe() string { return n.info }
+
+func (n *nodeTerminationHandlerTask) Do(errCh chan error) error {
+	defer close(errCh)
+	return n.clusterProvider.InstallNodeTerminationHandler(n.spec, false)
+}
+
+type gmsaWebhookTask struct {
+	info            string
+	clusterProvider *ClusterProvider
+	spec            *api.ClusterConfig
+}
+
+func (g *gmsaWebhookTask) Describe() string { return g.info }
+
+func (g *gmsaWebhookTask) Do(errCh chan error) error {
+	defer close(errCh)
+	return g.clusterProvider.InstallGMSAWebhook(g.spec, false)
+}
+
+// InstallGMSAWebhook deploys the GMSACredentialSpec CRD and the gMSA admission webhook, which
+// Windows pods using group Managed Service Accounts require
+func (c *ClusterProvider) InstallGMSAWebhook(cfg *api.ClusterConfig, planMode bool) error {
+	rawClient, err := c.NewRawClient(cfg)
+	if err != nil {
+		return err
+	}
+	if err := addons.NewGMSAWebhook(rawClient, cfg.Status, cfg.ContainerRegistryOverride, planMode).Deploy(); err != nil {
+		return errors.Wrap(err, "error installing gMSA webhook")
+	}
+	return nil
+}
+
+// InstallNodeTerminationHandler deploys AWS Node Termination Handler, in queue mode
+// the queue must have been created in the cluster stack
+func (c *ClusterProvider) InstallNodeTerminationHandler(cfg *api.ClusterConfig, planMode bool) error {
+	var queueURL string
+	if cfg.HasNodeTerminationHandlerQueue() {
+		var err error
+		queueURL, err = c.NewStackManager(cfg).GetNodeTerminationHandlerQueueURL()
+		if err != nil {
+			return err
+		}
+	}
+	rawClient, err := c.NewRawClient(cfg)
+	if err != nil {
+		return err
+	}
+	nodeTerminationHandler := addons.NewNodeTerminationHandler(rawClient, cfg.NodeTerminationHandler, queueURL, c.Provider.Region(), cfg.ContainerRegistryOverride, planMode)
+	return nodeTerminationHandler.Deploy()
+}
+
 // AppendExtraClusterConfigTasks returns all tasks for updating cluster configuration or nil if there are no tasks
 func (c *ClusterProvider) AppendExtraClusterConfigTasks(cfg *api.ClusterConfig, installVPCController bool, tasks *manager.TaskTree) {
 	newTasks := &manager.TaskTree{
@@ -63,6 +121,17 @@ func (c *ClusterProvider) AppendExtraClusterConfigTasks(cfg *api.ClusterConfig,
 			call: c.UpdateClusterConfigForLogging,
 		})
 	}
+	if cfg.HasAlarms() {
+		if cfg.HasClusterCloudWatchLogType("audit") {
+			newTasks.Append(&clusterConfigTask{
+				info: "create metric filter of API server errors",
+				spec: cfg,
+				call: c.PutAPIServerErrorsMetricFilter,
+			})
+		} else {
+			logger.Info("the API server error alarm of cluster %q requires the audit logs of the control plane, enable them in cloudWatch.clusterLogging.enableTypes", cfg.Metadata.Name)
+		}
+	}
 	if api.IsEnabled(cfg.IAM.WithOIDC) {
 		c.appendCreateTasksForIAMServiceAccounts(cfg, newTasks)
 	}
@@ -76,6 +145,22 @@ func (c *ClusterProvider) AppendExtraClusterConfigTasks(cfg *api.ClusterConfig,
 		})
 	}
 
+	if cfg.HasPrivateHostedZone() {
+		newTasks.Append(&clusterConfigTask{
+			info: "create private hosted zone for the API server endpoint",
+			spec: cfg,
+			call: c.SyncPrivateHostedZone,
+		})
+	}
+
+	if cfg.HasPodSecurityGroups() {
+		newTasks.Append(&clusterConfigTask{
+			info: "enable security groups for pods",
+			spec: cfg,
+			call: c.EnablePodSecurityGroups,
+		})
+	}
+
 	if installVPCController {
 		newTasks.Append(&vpcControllerTask{
 			info:            "install Windows VPC controller",
@@ -83,6 +168,21 @@ func (c *ClusterProvider) AppendExtraClusterConfigTasks(cfg *api.ClusterConfig,
 			clusterProvider: c,
 		})
 	}
+	if cfg.HasWindowsGMSA() {
+		newTasks.Append(&gmsaWebhookTask{
+			info:            "install gMSA webhook",
+			spec:            cfg,
+			clusterProvider: c,
+		})
+	}
+	if cfg.HasNodeTerminationHandler() {
+		// in queue mode this must run after the IAM service accounts have been created
+		newTasks.Append(&nodeTerminationHandlerTask{
+			info:            "install AWS Node Termination Handler",
+			spec:            cfg,
+			clusterProvider: c,
+		})
+	}
 	if newTasks.Len() > 0 {
 		tasks.Append(newTasks)
 	}
@@ -106,7 +206,7 @@ func (c *ClusterProvider) appendCreateTasksForIAMServiceAccounts(cfg *api.Cluste
 			if err != nil {
 				return err
 			}
-			if err := oidc.CreateProvider(); err != nil {
+			if err := oidc.EnsureProvider(); err != nil {
 				return err
 			}
 			*eatlyOIDC = *oidc
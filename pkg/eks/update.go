@@ -7,11 +7,11 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/request"
 	awseks "github.com/aws/aws-sdk-go/service/eks"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/utils/waiters"
 )
 
@@ -19,6 +19,9 @@ import (
 type ClusterVPCConfig struct {
 	ClusterEndpoints  *api.ClusterEndpoints
 	PublicAccessCIDRs []string
+	VPCID             string
+	SubnetIDs         []string
+	SecurityGroupIDs  []string
 }
 
 // GetCurrentClusterConfigForLogging fetches current cluster logging configuration as two sets - enabled and disabled types
@@ -111,6 +114,9 @@ func (c *ClusterProvider) GetCurrentClusterVPCConfig(spec *api.ClusterConfig) (*
 			PublicAccess:  vpcConfig.EndpointPublicAccess,
 		},
 		PublicAccessCIDRs: aws.StringValueSlice(vpcConfig.PublicAccessCidrs),
+		VPCID:             aws.StringValue(vpcConfig.VpcId),
+		SubnetIDs:         aws.StringValueSlice(vpcConfig.SubnetIds),
+		SecurityGroupIDs:  aws.StringValueSlice(vpcConfig.SecurityGroupIds),
 	}, nil
 }
 
@@ -148,6 +154,26 @@ func (c *ClusterProvider) UpdatePublicAccessCIDRs(clusterConfig *api.ClusterConf
 	return c.waitForUpdateToSucceed(clusterConfig.Metadata.Name, output.Update)
 }
 
+// UpdateClusterVPCConfig calls eks.UpdateClusterConfig and moves the control plane to the given
+// subnets and security groups, either of them is left as it is when empty
+func (c *ClusterProvider) UpdateClusterVPCConfig(cfg *api.ClusterConfig, subnetIDs, securityGroupIDs []string) error {
+	vpcConfig := &awseks.VpcConfigRequest{}
+	if len(subnetIDs) > 0 {
+		vpcConfig.SubnetIds = aws.StringSlice(subnetIDs)
+	}
+	if len(securityGroupIDs) > 0 {
+		vpcConfig.SecurityGroupIds = aws.StringSlice(securityGroupIDs)
+	}
+	output, err := c.Provider.EKS().UpdateClusterConfig(&awseks.UpdateClusterConfigInput{
+		Name:               &cfg.Metadata.Name,
+		ResourcesVpcConfig: vpcConfig,
+	})
+	if err != nil {
+		return err
+	}
+	return c.waitForUpdateToSucceed(cfg.Metadata.Name, output.Update)
+}
+
 // UpdateClusterVersion calls eks.UpdateClusterVersion and updates to cfg.Metadata.Version,
 // it will return update ID along with an error (if it occurs)
 func (c *ClusterProvider) UpdateClusterVersion(cfg *api.ClusterConfig) (*awseks.Update, error) {
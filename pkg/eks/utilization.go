@@ -0,0 +1,109 @@
+package eks
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/utilization"
+)
+
+// nodeMetricsPath is where metrics-server serves the usage of the nodes
+const nodeMetricsPath = "/apis/metrics.k8s.io/v1beta1/nodes"
+
+// GetUtilizationInputs lists the nodegroups of the cluster, or the one named nodeGroupName, with
+// the nodes and the pods of the cluster, the usage of the nodes reported by metrics-server, when
+// it is installed, and the instance types of the families of the nodegroups
+func (c *ClusterProvider) GetUtilizationInputs(spec *api.ClusterConfig, clientSet kubernetes.Interface, nodeGroupName string) (*utilization.Inputs, error) {
+	summaries, err := c.NewStackManager(spec).GetNodeGroupSummaries(nodeGroupName)
+	if err != nil {
+		return nil, err
+	}
+	if nodeGroupName != "" && len(summaries) == 0 {
+		return nil, fmt.Errorf("nodegroup %q not found in cluster %q", nodeGroupName, spec.Metadata.Name)
+	}
+
+	inputs := &utilization.Inputs{ClusterName: spec.Metadata.Name}
+	for _, summary := range summaries {
+		// the instance type of nodegroups with mixed instances is taken from their nodes
+		inputs.NodeGroups = append(inputs.NodeGroups, utilization.NodeGroup{
+			Name:            summary.Name,
+			InstanceType:    summary.InstanceType,
+			DesiredCapacity: summary.DesiredCapacity,
+			MinSize:         summary.MinSize,
+			MaxSize:         summary.MaxSize,
+		})
+	}
+
+	nodes, err := clientSet.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing nodes")
+	}
+	inputs.Nodes = nodes.Items
+
+	pods, err := clientSet.CoreV1().Pods(metav1.NamespaceAll).List(metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("status.phase!=%s,status.phase!=%s", corev1.PodSucceeded, corev1.PodFailed),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing pods")
+	}
+	inputs.Pods = pods.Items
+
+	if usage, err := nodeUsage(clientSet); err != nil {
+		logger.Warning("unable to get the usage of the nodes from metrics-server, only requests and limits are reported: %s", err.Error())
+	} else {
+		inputs.Usage = usage
+	}
+
+	for _, family := range inputs.InstanceTypeFamilies() {
+		input := &ec2.DescribeInstanceTypesInput{
+			Filters: []*ec2.Filter{{
+				Name:   aws.String("instance-type"),
+				Values: aws.StringSlice([]string{family + ".*"}),
+			}},
+		}
+		for {
+			output, err := c.Provider.EC2().DescribeInstanceTypes(input)
+			if err != nil {
+				return nil, errors.Wrapf(err, "describing the instance types of family %q", family)
+			}
+			inputs.InstanceTypes = append(inputs.InstanceTypes, output.InstanceTypes...)
+			if aws.StringValue(output.NextToken) == "" {
+				break
+			}
+			input.NextToken = output.NextToken
+		}
+	}
+
+	return inputs, nil
+}
+
+// nodeUsage gets the usage of the nodes from the metrics API served by metrics-server
+func nodeUsage(clientSet kubernetes.Interface) (map[string]corev1.ResourceList, error) {
+	body, err := clientSet.CoreV1().RESTClient().Get().AbsPath(nodeMetricsPath).DoRaw()
+	if err != nil {
+		return nil, err
+	}
+	var nodeMetricsList struct {
+		Items []struct {
+			Metadata metav1.ObjectMeta   `json:"metadata"`
+			Usage    corev1.ResourceList `json:"usage"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &nodeMetricsList); err != nil {
+		return nil, errors.Wrap(err, "decoding node metrics")
+	}
+	usage := map[string]corev1.ResourceList{}
+	for _, item := range nodeMetricsList.Items {
+		usage[item.Metadata.Name] = item.Usage
+	}
+	return usage, nil
+}
@@ -0,0 +1,61 @@
+package eks
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/weaveworks/eksctl/pkg/addons"
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/verify"
+)
+
+// verifyECRImageFormat is the pause image of the ECR repositories of EKS in the region of the cluster
+const verifyECRImageFormat = "%s.dkr.ecr.%s.%s/eks/pause:3.1-eksbuild.1"
+
+// VerifyCluster runs a short-lived test workload in the cluster and checks that it gets
+// scheduled on every nodegroup, resolves DNS, pulls images from ECR and gets volumes provisioned
+func (c *ClusterProvider) VerifyCluster(spec *api.ClusterConfig, options verify.Options) (*verify.Report, error) {
+	if options.ECRImage == "" {
+		template := &corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Image: verifyECRImageFormat}},
+			},
+		}
+		if err := addons.UseRegionalImage(template, spec.Metadata.Region); err != nil {
+			return nil, err
+		}
+		options.ECRImage = template.Spec.Containers[0].Image
+	}
+
+	clientSet, err := c.NewStdClientSet(spec)
+	if err != nil {
+		return nil, err
+	}
+	return verify.NewVerifier(clientSet, options).Verify(spec.Metadata.Name)
+}
+
+// LogVerification logs the results of verifying the cluster, and returns an error when any
+// check failed
+func LogVerification(report *verify.Report) error {
+	for _, result := range report.Results {
+		name := result.Name
+		if result.Target != "" {
+			name = fmt.Sprintf("%s (%s)", result.Name, result.Target)
+		}
+		switch result.Status {
+		case verify.StatusPass:
+			logger.Info("check %s passed: %s", name, result.Message)
+		case verify.StatusSkip:
+			logger.Info("check %s skipped: %s", name, result.Message)
+		default:
+			logger.Critical("check %s failed: %s", name, result.Message)
+		}
+	}
+	if !report.Passed {
+		return fmt.Errorf("cluster %q failed verification", report.Cluster)
+	}
+	logger.Success("cluster %q passed verification", report.Cluster)
+	return nil
+}
@@ -0,0 +1,215 @@
+package eks
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+const autoScalingServiceLinkedRoleName = "aws-service-role/autoscaling.amazonaws.com/AWSServiceRoleForAutoScaling"
+
+// autoScalingKeyActions are the actions the Auto Scaling service-linked role needs
+// in order to launch instances with volumes encrypted by a customer managed key, see
+// https://docs.aws.amazon.com/autoscaling/ec2/userguide/key-policy-requirements-EBS-encryption.html
+var autoScalingKeyActions = []string{
+	"kms:Encrypt",
+	"kms:Decrypt",
+	"kms:ReEncryptFrom",
+	"kms:ReEncryptTo",
+	"kms:GenerateDataKeyWithoutPlaintext",
+	"kms:DescribeKey",
+	"kms:CreateGrant",
+}
+
+// ValidateVolumeEncryptionKeys checks that the key policy of each customer managed key
+// used for root volume encryption allows the Auto Scaling service-linked role to use it,
+// as otherwise instances fail to launch without any obvious error in the nodegroup stack
+func ValidateVolumeEncryptionKeys(provider api.ClusterProvider, nodeGroups []*api.NodeGroup, managedNodeGroups []*api.ManagedNodeGroup) error {
+	checked := map[string]struct{}{}
+	validate := func(name string, encrypted *bool, kmsKeyID *string) error {
+		if !api.IsEnabled(encrypted) || !api.IsSetAndNonEmptyString(kmsKeyID) {
+			return nil
+		}
+		if _, ok := checked[*kmsKeyID]; ok {
+			return nil
+		}
+		checked[*kmsKeyID] = struct{}{}
+
+		return errors.Wrapf(validateVolumeEncryptionKey(provider, *kmsKeyID), "nodegroup %q", name)
+	}
+
+	for _, ng := range nodeGroups {
+		if err := validate(ng.Name, ng.VolumeEncrypted, ng.VolumeKmsKeyID); err != nil {
+			return err
+		}
+	}
+	// managed nodegroups are scaled by Auto Scaling groups too
+	for _, ng := range managedNodeGroups {
+		if err := validate(ng.Name, ng.VolumeEncrypted, ng.VolumeKmsKeyID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateVolumeEncryptionKey(provider api.ClusterProvider, keyID string) error {
+	// the key may be referenced by an alias, which GetKeyPolicy doesn't accept
+	key, err := provider.KMS().DescribeKey(&kms.DescribeKeyInput{
+		KeyId: aws.String(keyID),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "describing KMS key %q", keyID)
+	}
+
+	keyARN, err := arn.Parse(*key.KeyMetadata.Arn)
+	if err != nil {
+		return errors.Wrapf(err, "unexpected ARN of KMS key %q", keyID)
+	}
+
+	policy, err := provider.KMS().GetKeyPolicy(&kms.GetKeyPolicyInput{
+		KeyId:      key.KeyMetadata.Arn,
+		PolicyName: aws.String("default"),
+	})
+	if err != nil {
+		// only the account owning the key can read its policy, the key may
+		// well be usable through grants, so this is not treated as an error
+		logger.Warning("unable to verify the key policy of KMS key %q: %s", keyID, err.Error())
+		return nil
+	}
+
+	roleARN := arn.ARN{
+		Partition: keyARN.Partition,
+		Service:   "iam",
+		AccountID: keyARN.AccountID,
+		Resource:  "role/" + autoScalingServiceLinkedRoleName,
+	}.String()
+
+	missing, err := missingKeyPolicyActions(*policy.Policy, roleARN, autoScalingKeyActions)
+	if err != nil {
+		return errors.Wrapf(err, "parsing key policy of KMS key %q", keyID)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("key policy of KMS key %q doesn't allow %q to use it for root volume encryption (missing %s)",
+			keyID, roleARN, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+type keyPolicyDocument struct {
+	Statement keyPolicyStatements
+}
+
+type keyPolicyStatement struct {
+	Effect    string
+	Principal keyPolicyPrincipal
+	Action    stringOrSlice
+}
+
+// keyPolicyStatements can be a single statement or a list of statements
+type keyPolicyStatements []keyPolicyStatement
+
+func (s *keyPolicyStatements) UnmarshalJSON(data []byte) error {
+	var statements []keyPolicyStatement
+	if err := json.Unmarshal(data, &statements); err == nil {
+		*s = statements
+		return nil
+	}
+	var statement keyPolicyStatement
+	if err := json.Unmarshal(data, &statement); err != nil {
+		return err
+	}
+	*s = keyPolicyStatements{statement}
+	return nil
+}
+
+// keyPolicyPrincipal can be either "*" or a map of principal types
+type keyPolicyPrincipal struct {
+	Any bool
+	AWS stringOrSlice
+}
+
+func (p *keyPolicyPrincipal) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		p.Any = value == "*"
+		return nil
+	}
+	var principals struct {
+		AWS stringOrSlice
+	}
+	if err := json.Unmarshal(data, &principals); err != nil {
+		return err
+	}
+	p.AWS = principals.AWS
+	return nil
+}
+
+func (p keyPolicyPrincipal) includes(roleARN string) bool {
+	if p.Any {
+		return true
+	}
+	for _, principal := range p.AWS {
+		if principal == "*" || principal == roleARN {
+			return true
+		}
+	}
+	return false
+}
+
+type stringOrSlice []string
+
+func (s *stringOrSlice) UnmarshalJSON(data []byte) error {
+	var values []string
+	if err := json.Unmarshal(data, &values); err == nil {
+		*s = values
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = stringOrSlice{value}
+	return nil
+}
+
+// missingKeyPolicyActions returns the actions that are not allowed for roleARN by the
+// key policy; conditions are not evaluated and the account root principal is ignored,
+// as service-linked roles don't get any KMS permissions through IAM policies
+func missingKeyPolicyActions(policy, roleARN string, actions []string) ([]string, error) {
+	var doc keyPolicyDocument
+	if err := json.Unmarshal([]byte(policy), &doc); err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, action := range actions {
+		if !isActionAllowed(doc.Statement, roleARN, action) {
+			missing = append(missing, action)
+		}
+	}
+	return missing, nil
+}
+
+func isActionAllowed(statements keyPolicyStatements, roleARN, action string) bool {
+	for _, statement := range statements {
+		if statement.Effect != "Allow" || !statement.Principal.includes(roleARN) {
+			continue
+		}
+		for _, pattern := range statement.Action {
+			// actions are case-insensitive and may contain wildcards
+			if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(action)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
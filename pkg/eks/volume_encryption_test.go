@@ -0,0 +1,135 @@
+package eks_test
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/eks"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("Volume encryption key validation", func() {
+	const keyARN = "arn:aws:kms:us-west-2:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+
+	var (
+		p          *mockprovider.MockProvider
+		nodeGroups []*api.NodeGroup
+	)
+
+	mockKeyPolicy := func(policy string) {
+		p.MockKMS().On("GetKeyPolicy", mock.Anything).Return(&kms.GetKeyPolicyOutput{
+			Policy: aws.String(policy),
+		}, nil)
+	}
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		p.MockKMS().On("DescribeKey", mock.Anything).Return(func(input *kms.DescribeKeyInput) *kms.DescribeKeyOutput {
+			return &kms.DescribeKeyOutput{
+				KeyMetadata: &kms.KeyMetadata{
+					Arn:   aws.String(keyARN),
+					KeyId: input.KeyId,
+				},
+			}
+		}, nil)
+
+		ng := api.NewNodeGroup()
+		ng.Name = "ng-1"
+		ng.VolumeEncrypted = api.Enabled()
+		ng.VolumeKmsKeyID = aws.String(keyARN)
+		nodeGroups = []*api.NodeGroup{ng}
+	})
+
+	It("should accept a key policy granting access to the Auto Scaling service-linked role", func() {
+		mockKeyPolicy(`{
+			"Version": "2012-10-17",
+			"Statement": [
+				{
+					"Effect": "Allow",
+					"Principal": {"AWS": "arn:aws:iam::123456789012:root"},
+					"Action": "kms:*",
+					"Resource": "*"
+				},
+				{
+					"Effect": "Allow",
+					"Principal": {"AWS": ["arn:aws:iam::123456789012:role/aws-service-role/autoscaling.amazonaws.com/AWSServiceRoleForAutoScaling"]},
+					"Action": ["kms:Encrypt", "kms:Decrypt", "kms:ReEncrypt*", "kms:GenerateDataKey*", "kms:DescribeKey"],
+					"Resource": "*"
+				},
+				{
+					"Effect": "Allow",
+					"Principal": {"AWS": "arn:aws:iam::123456789012:role/aws-service-role/autoscaling.amazonaws.com/AWSServiceRoleForAutoScaling"},
+					"Action": "kms:CreateGrant",
+					"Resource": "*",
+					"Condition": {"Bool": {"kms:GrantIsForAWSResource": true}}
+				}
+			]
+		}`)
+
+		Expect(ValidateVolumeEncryptionKeys(p, nodeGroups, nil)).To(Succeed())
+	})
+
+	It("should reject the default key policy", func() {
+		mockKeyPolicy(`{
+			"Version": "2012-10-17",
+			"Statement": {
+				"Effect": "Allow",
+				"Principal": {"AWS": "arn:aws:iam::123456789012:root"},
+				"Action": "kms:*",
+				"Resource": "*"
+			}
+		}`)
+
+		err := ValidateVolumeEncryptionKeys(p, nodeGroups, nil)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`nodegroup "ng-1"`))
+		Expect(err.Error()).To(ContainSubstring("AWSServiceRoleForAutoScaling"))
+		Expect(err.Error()).To(ContainSubstring("kms:CreateGrant"))
+	})
+
+	It("should only warn when the key policy can't be read", func() {
+		p.MockKMS().On("GetKeyPolicy", mock.Anything).Return(nil, errors.New("AccessDeniedException"))
+
+		Expect(ValidateVolumeEncryptionKeys(p, nodeGroups, nil)).To(Succeed())
+	})
+
+	It("should check each key only once", func() {
+		mockKeyPolicy(`{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "kms:*"}]}`)
+
+		ng := api.NewNodeGroup()
+		ng.Name = "ng-2"
+		ng.VolumeEncrypted = api.Enabled()
+		ng.VolumeKmsKeyID = aws.String(keyARN)
+		nodeGroups = append(nodeGroups, ng)
+
+		Expect(ValidateVolumeEncryptionKeys(p, nodeGroups, nil)).To(Succeed())
+		p.MockKMS().AssertNumberOfCalls(GinkgoT(), "GetKeyPolicy", 1)
+	})
+
+	It("should skip nodegroups without a customer managed key", func() {
+		nodeGroups[0].VolumeKmsKeyID = nil
+
+		Expect(ValidateVolumeEncryptionKeys(p, nodeGroups, nil)).To(Succeed())
+		p.MockKMS().AssertNotCalled(GinkgoT(), "GetKeyPolicy", mock.Anything)
+	})
+
+	It("should check the keys of managed nodegroups", func() {
+		mockKeyPolicy(`{"Statement": {"Effect": "Allow", "Principal": {"AWS": "arn:aws:iam::123456789012:root"}, "Action": "kms:*"}}`)
+
+		ng := api.NewManagedNodeGroup()
+		ng.Name = "mng-1"
+		ng.VolumeEncrypted = api.Enabled()
+		ng.VolumeKmsKeyID = aws.String(keyARN)
+
+		err := ValidateVolumeEncryptionKeys(p, nil, []*api.ManagedNodeGroup{ng})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`nodegroup "mng-1"`))
+	})
+})
@@ -0,0 +1,160 @@
+package eksaddons
+
+import (
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+)
+
+// AvailableAddon is an add-on available for the Kubernetes version of a cluster
+type AvailableAddon struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Publisher string `json:"publisher"`
+	// DefaultVersion is the version EKS installs when no version is specified
+	DefaultVersion string `json:"defaultVersion"`
+	LatestVersion  string `json:"latestVersion"`
+	// PodIdentity is whether the add-on can use EKS Pod Identity for its IAM permissions
+	PodIdentity bool `json:"podIdentity"`
+	// InstalledVersion is the version installed in the cluster, if any
+	InstalledVersion string `json:"installedVersion,omitempty"`
+}
+
+// ListAvailable returns the add-ons available for kubernetesVersion, sorted by name, along with
+// the versions of them that are installed in the cluster
+func ListAvailable(api API, clusterName, kubernetesVersion string) ([]*AvailableAddon, error) {
+	installed, err := installedVersions(api, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	var available []*AvailableAddon
+	input := &DescribeAddonVersionsInput{KubernetesVersion: &kubernetesVersion}
+	for {
+		output, err := api.DescribeAddonVersions(input)
+		if err != nil {
+			return nil, errors.Wrapf(err, "describing the add-ons available for Kubernetes %s", kubernetesVersion)
+		}
+		for _, info := range output.Addons {
+			addon := &AvailableAddon{
+				Name:             aws.StringValue(info.AddonName),
+				Type:             aws.StringValue(info.Type),
+				Publisher:        aws.StringValue(info.Publisher),
+				DefaultVersion:   defaultVersion(info, kubernetesVersion),
+				LatestVersion:    latestVersion(info),
+				InstalledVersion: installed[aws.StringValue(info.AddonName)],
+			}
+			if addon.PodIdentity, err = supportsPodIdentity(api, addon); err != nil {
+				return nil, err
+			}
+			available = append(available, addon)
+		}
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	sort.Slice(available, func(i, j int) bool {
+		return available[i].Name < available[j].Name
+	})
+	return available, nil
+}
+
+// ListInstalled returns the add-ons installed in the cluster, sorted by name
+func ListInstalled(api API, clusterName string) ([]*Addon, error) {
+	var addons []*Addon
+	input := &ListAddonsInput{ClusterName: &clusterName}
+	for {
+		output, err := api.ListAddons(input)
+		if err != nil {
+			return nil, errors.Wrapf(err, "listing the add-ons of cluster %q", clusterName)
+		}
+		for _, name := range output.Addons {
+			addon, err := api.DescribeAddon(&DescribeAddonInput{ClusterName: &clusterName, AddonName: name})
+			if err != nil {
+				return nil, errors.Wrapf(err, "describing add-on %q", aws.StringValue(name))
+			}
+			addons = append(addons, addon.Addon)
+		}
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	sort.Slice(addons, func(i, j int) bool {
+		return aws.StringValue(addons[i].AddonName) < aws.StringValue(addons[j].AddonName)
+	})
+	return addons, nil
+}
+
+func installedVersions(api API, clusterName string) (map[string]string, error) {
+	addons, err := ListInstalled(api, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	versions := map[string]string{}
+	for _, addon := range addons {
+		versions[aws.StringValue(addon.AddonName)] = aws.StringValue(addon.AddonVersion)
+	}
+	return versions, nil
+}
+
+func defaultVersion(info *AddonInfo, kubernetesVersion string) string {
+	for _, version := range info.AddonVersions {
+		for _, compatibility := range version.Compatibilities {
+			if aws.StringValue(compatibility.ClusterVersion) == kubernetesVersion && aws.BoolValue(compatibility.DefaultVersion) {
+				return aws.StringValue(version.AddonVersion)
+			}
+		}
+	}
+	return ""
+}
+
+func latestVersion(info *AddonInfo) string {
+	var latest string
+	for _, version := range info.AddonVersions {
+		if v := aws.StringValue(version.AddonVersion); latest == "" || compareVersions(v, latest) > 0 {
+			latest = v
+		}
+	}
+	return latest
+}
+
+// compareVersions compares add-on versions like v1.15.1-eksbuild.1, falling back to comparing
+// them as strings when they aren't semantic versions
+func compareVersions(a, b string) int {
+	va, errA := semver.ParseTolerant(a)
+	vb, errB := semver.ParseTolerant(b)
+	if errA != nil || errB != nil {
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	}
+	return va.Compare(vb)
+}
+
+func supportsPodIdentity(api API, addon *AvailableAddon) (bool, error) {
+	version := addon.DefaultVersion
+	if version == "" {
+		version = addon.LatestVersion
+	}
+	if version == "" {
+		return false, nil
+	}
+	output, err := api.DescribeAddonConfiguration(&DescribeAddonConfigurationInput{
+		AddonName:    &addon.Name,
+		AddonVersion: &version,
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "describing the configuration of add-on %q", addon.Name)
+	}
+	return len(output.PodIdentityConfiguration) > 0, nil
+}
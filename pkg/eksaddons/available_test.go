@@ -0,0 +1,137 @@
+package eksaddons
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type fakeAddonsAPI struct {
+	API
+
+	addonVersions []*DescribeAddonVersionsOutput
+	podIdentity   map[string]bool
+	installed     map[string]string
+}
+
+func (f *fakeAddonsAPI) DescribeAddonVersions(input *DescribeAddonVersionsInput) (*DescribeAddonVersionsOutput, error) {
+	if aws.StringValue(input.KubernetesVersion) != "1.29" {
+		return nil, errors.New("unexpected Kubernetes version")
+	}
+	output := f.addonVersions[0]
+	f.addonVersions = f.addonVersions[1:]
+	return output, nil
+}
+
+func (f *fakeAddonsAPI) DescribeAddonConfiguration(input *DescribeAddonConfigurationInput) (*DescribeAddonConfigurationOutput, error) {
+	output := &DescribeAddonConfigurationOutput{}
+	if f.podIdentity[*input.AddonName+"@"+*input.AddonVersion] {
+		output.PodIdentityConfiguration = []*PodIdentityConfiguration{{ServiceAccount: aws.String("sa")}}
+	}
+	return output, nil
+}
+
+func (f *fakeAddonsAPI) ListAddons(*ListAddonsInput) (*ListAddonsOutput, error) {
+	output := &ListAddonsOutput{}
+	for name := range f.installed {
+		output.Addons = append(output.Addons, aws.String(name))
+	}
+	return output, nil
+}
+
+func (f *fakeAddonsAPI) DescribeAddon(input *DescribeAddonInput) (*DescribeAddonOutput, error) {
+	return &DescribeAddonOutput{Addon: &Addon{
+		AddonName:    input.AddonName,
+		AddonVersion: aws.String(f.installed[*input.AddonName]),
+	}}, nil
+}
+
+func addonVersion(version string, isDefault bool) *AddonVersionInfo {
+	return &AddonVersionInfo{
+		AddonVersion: aws.String(version),
+		Compatibilities: []*Compatibility{
+			{ClusterVersion: aws.String("1.28"), DefaultVersion: aws.Bool(false)},
+			{ClusterVersion: aws.String("1.29"), DefaultVersion: aws.Bool(isDefault)},
+		},
+	}
+}
+
+var _ = Describe("ListAvailable", func() {
+	It("lists the default and latest versions of the add-ons and whether they are installed", func() {
+		api := &fakeAddonsAPI{
+			addonVersions: []*DescribeAddonVersionsOutput{
+				{
+					Addons: []*AddonInfo{{
+						AddonName: aws.String("vpc-cni"),
+						Type:      aws.String("networking"),
+						Publisher: aws.String("eks"),
+						AddonVersions: []*AddonVersionInfo{
+							addonVersion("v1.16.0-eksbuild.1", false),
+							addonVersion("v1.18.1-eksbuild.3", false),
+							addonVersion("v1.18.1-eksbuild.10", false),
+							addonVersion("v1.15.1-eksbuild.1", true),
+						},
+					}},
+					NextToken: aws.String("next"),
+				},
+				{
+					Addons: []*AddonInfo{{
+						AddonName:     aws.String("coredns"),
+						Type:          aws.String("networking"),
+						Publisher:     aws.String("eks"),
+						AddonVersions: []*AddonVersionInfo{addonVersion("v1.11.1-eksbuild.4", true)},
+					}},
+				},
+			},
+			podIdentity: map[string]bool{"vpc-cni@v1.15.1-eksbuild.1": true},
+			installed:   map[string]string{"coredns": "v1.10.1-eksbuild.7"},
+		}
+
+		addons, err := ListAvailable(api, "cluster-1", "1.29")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(addons).To(Equal([]*AvailableAddon{
+			{
+				Name:             "coredns",
+				Type:             "networking",
+				Publisher:        "eks",
+				DefaultVersion:   "v1.11.1-eksbuild.4",
+				LatestVersion:    "v1.11.1-eksbuild.4",
+				InstalledVersion: "v1.10.1-eksbuild.7",
+			},
+			{
+				Name:           "vpc-cni",
+				Type:           "networking",
+				Publisher:      "eks",
+				DefaultVersion: "v1.15.1-eksbuild.1",
+				LatestVersion:  "v1.18.1-eksbuild.10",
+				PodIdentity:    true,
+			},
+		}))
+	})
+})
+
+var _ = Describe("CompatibleVersions", func() {
+	It("lists the versions of an add-on that support a Kubernetes version", func() {
+		api := &fakeAddonsAPI{
+			addonVersions: []*DescribeAddonVersionsOutput{{
+				Addons: []*AddonInfo{{
+					AddonName: aws.String("vpc-cni"),
+					AddonVersions: []*AddonVersionInfo{
+						addonVersion("v1.18.1-eksbuild.3", false),
+						{
+							AddonVersion:    aws.String("v1.12.0-eksbuild.1"),
+							Compatibilities: []*Compatibility{{ClusterVersion: aws.String("1.28")}},
+						},
+						addonVersion("v1.15.1-eksbuild.1", true),
+					},
+				}},
+			}},
+		}
+
+		versions, err := CompatibleVersions(api, "vpc-cni", "1.29")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(versions).To(Equal([]string{"v1.18.1-eksbuild.3", "v1.15.1-eksbuild.1"}))
+	})
+})
@@ -0,0 +1,78 @@
+package eksaddons
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/utils/retry"
+	"github.com/weaveworks/eksctl/pkg/utils/waiters"
+)
+
+// ConfigurationProperties returns the names of the configuration values an add-on version
+// accepts under path, e.g. the environment variables of vpc-cni under "env"
+func ConfigurationProperties(api API, addonName, addonVersion string, path ...string) (map[string]bool, error) {
+	output, err := api.DescribeAddonConfiguration(&DescribeAddonConfigurationInput{
+		AddonName:    &addonName,
+		AddonVersion: &addonVersion,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "describing the configuration of add-on %q", addonName)
+	}
+	properties := map[string]bool{}
+	if aws.StringValue(output.ConfigurationSchema) == "" {
+		return properties, nil
+	}
+
+	type schema struct {
+		Properties map[string]*schema `json:"properties"`
+	}
+	current := &schema{}
+	if err := json.Unmarshal([]byte(*output.ConfigurationSchema), current); err != nil {
+		return nil, errors.Wrapf(err, "parsing the configuration schema of add-on %q", addonName)
+	}
+	for _, name := range path {
+		next, ok := current.Properties[name]
+		if !ok || next == nil {
+			return properties, nil
+		}
+		current = next
+	}
+	for name := range current.Properties {
+		properties[name] = true
+	}
+	return properties, nil
+}
+
+// Create installs an add-on in the cluster and waits for it to become active
+func Create(api API, input *CreateAddonInput, waitTimeout time.Duration) error {
+	name := aws.StringValue(input.AddonName)
+	if _, err := api.CreateAddon(input); err != nil {
+		return errors.Wrapf(err, "creating EKS add-on %q", name)
+	}
+	return waitForActive(api, aws.StringValue(input.ClusterName), name, retry.DefaultPoller, waitTimeout)
+}
+
+func waitForActive(api API, clusterName, addonName string, poller retry.Poller, timeout time.Duration) error {
+	err := poller.PollWithTimeout(timeout, func() (bool, error) {
+		output, err := api.DescribeAddon(&DescribeAddonInput{ClusterName: &clusterName, AddonName: &addonName})
+		if err != nil {
+			return false, errors.Wrapf(err, "failed while waiting for EKS add-on %q to become active", addonName)
+		}
+		switch status := aws.StringValue(output.Addon.Status); status {
+		case AddonStatusActive:
+			return true, nil
+		case AddonStatusCreateFailed, AddonStatusDegraded:
+			return false, fmt.Errorf("EKS add-on %q is in state %s, check its health issues with 'aws eks describe-addon'", addonName, status)
+		}
+		return false, nil
+	})
+	if err == context.DeadlineExceeded {
+		return waiters.NewTimeoutError(fmt.Errorf("timed out while waiting for EKS add-on %q to become active", addonName))
+	}
+	return err
+}
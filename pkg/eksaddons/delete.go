@@ -0,0 +1,50 @@
+package eksaddons
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/utils/retry"
+)
+
+// Delete removes an add-on from the cluster; with preserve, EKS stops managing the add-on but
+// leaves its Kubernetes objects running in the cluster, e.g. to manage them with Helm instead;
+// with wait, it waits for the add-on to be gone until ctx is done
+func Delete(ctx context.Context, api API, clusterName, addonName string, preserve, wait bool) error {
+	if _, err := api.DeleteAddon(&DeleteAddonInput{
+		ClusterName: &clusterName,
+		AddonName:   &addonName,
+		Preserve:    aws.Bool(preserve),
+	}); err != nil {
+		return errors.Wrapf(err, "deleting EKS add-on %q", addonName)
+	}
+	if !wait {
+		return nil
+	}
+	return waitForDeletion(ctx, api, clusterName, addonName)
+}
+
+func waitForDeletion(ctx context.Context, api API, clusterName, addonName string) error {
+	err := retry.DefaultPoller.Poll(ctx, func() (bool, error) {
+		output, err := api.DescribeAddon(&DescribeAddonInput{ClusterName: &clusterName, AddonName: &addonName})
+		if err != nil {
+			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == awseks.ErrCodeResourceNotFoundException {
+				return true, nil
+			}
+			return false, errors.Wrapf(err, "failed while waiting for EKS add-on %q to be deleted", addonName)
+		}
+		if status := aws.StringValue(output.Addon.Status); status == AddonStatusDeleteFailed {
+			return false, fmt.Errorf("EKS add-on %q is in state %s, check its health issues with 'aws eks describe-addon'", addonName, status)
+		}
+		return false, nil
+	})
+	if err == context.DeadlineExceeded {
+		return fmt.Errorf("timed out while waiting for EKS add-on %q to be deleted", addonName)
+	}
+	return err
+}
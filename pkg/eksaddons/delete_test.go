@@ -0,0 +1,61 @@
+package eksaddons
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type fakeDeleteAddonsAPI struct {
+	API
+
+	deleted    []*DeleteAddonInput
+	statuses   []string
+	describals int
+}
+
+func (f *fakeDeleteAddonsAPI) DeleteAddon(input *DeleteAddonInput) (*DeleteAddonOutput, error) {
+	f.deleted = append(f.deleted, input)
+	return &DeleteAddonOutput{}, nil
+}
+
+func (f *fakeDeleteAddonsAPI) DescribeAddon(input *DescribeAddonInput) (*DescribeAddonOutput, error) {
+	f.describals++
+	if len(f.statuses) == 0 {
+		return nil, awserr.New(awseks.ErrCodeResourceNotFoundException, "No addon: "+*input.AddonName, nil)
+	}
+	status := f.statuses[0]
+	f.statuses = f.statuses[1:]
+	return &DescribeAddonOutput{Addon: &Addon{AddonName: input.AddonName, Status: aws.String(status)}}, nil
+}
+
+var _ = Describe("Delete", func() {
+	It("preserves the Kubernetes objects of the add-on when asked to", func() {
+		api := &fakeDeleteAddonsAPI{}
+		Expect(Delete(context.Background(), api, "test-cluster", "vpc-cni", true, false)).To(Succeed())
+
+		Expect(api.deleted).To(HaveLen(1))
+		Expect(*api.deleted[0].ClusterName).To(Equal("test-cluster"))
+		Expect(*api.deleted[0].AddonName).To(Equal("vpc-cni"))
+		Expect(*api.deleted[0].Preserve).To(BeTrue())
+		Expect(api.describals).To(BeZero())
+	})
+
+	It("waits until the add-on is gone", func() {
+		api := &fakeDeleteAddonsAPI{}
+		Expect(Delete(context.Background(), api, "test-cluster", "vpc-cni", false, true)).To(Succeed())
+
+		Expect(*api.deleted[0].Preserve).To(BeFalse())
+		Expect(api.describals).To(Equal(1))
+	})
+
+	It("fails when the deletion fails", func() {
+		api := &fakeDeleteAddonsAPI{statuses: []string{AddonStatusDeleteFailed}}
+		err := Delete(context.Background(), api, "test-cluster", "vpc-cni", false, true)
+		Expect(err).To(MatchError(`EKS add-on "vpc-cni" is in state DELETE_FAILED, check its health issues with 'aws eks describe-addon'`))
+	})
+})
@@ -0,0 +1,282 @@
+// Package eksaddons manages EKS add-ons, the Kubernetes components that EKS can install and
+// update in a cluster
+package eksaddons
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+)
+
+// API is the part of the EKS add-ons API used by eksctl; it's defined here as the version of
+// aws-sdk-go eksctl uses predates EKS add-ons
+type API interface {
+	DescribeAddonVersions(input *DescribeAddonVersionsInput) (*DescribeAddonVersionsOutput, error)
+	DescribeAddonConfiguration(input *DescribeAddonConfigurationInput) (*DescribeAddonConfigurationOutput, error)
+	ListAddons(input *ListAddonsInput) (*ListAddonsOutput, error)
+	DescribeAddon(input *DescribeAddonInput) (*DescribeAddonOutput, error)
+	UpdateAddon(input *UpdateAddonInput) (*UpdateAddonOutput, error)
+	CreateAddon(input *CreateAddonInput) (*CreateAddonOutput, error)
+	DeleteAddon(input *DeleteAddonInput) (*DeleteAddonOutput, error)
+}
+
+// Values of `UpdateAddonInput.ResolveConflicts`
+const (
+	ResolveConflictsOverwrite = "OVERWRITE"
+	ResolveConflictsPreserve  = "PRESERVE"
+)
+
+// Values of `Addon.Status`
+const (
+	AddonStatusActive       = "ACTIVE"
+	AddonStatusCreateFailed = "CREATE_FAILED"
+	AddonStatusDegraded     = "DEGRADED"
+	AddonStatusDeleteFailed = "DELETE_FAILED"
+)
+
+// Compatibility is a cluster version an add-on version is compatible with
+type Compatibility struct {
+	_ struct{} `type:"structure"`
+
+	ClusterVersion *string `locationName:"clusterVersion" type:"string"`
+	DefaultVersion *bool   `locationName:"defaultVersion" type:"boolean"`
+}
+
+// AddonVersionInfo describes a version of an add-on
+type AddonVersionInfo struct {
+	_ struct{} `type:"structure"`
+
+	AddonVersion           *string          `locationName:"addonVersion" type:"string"`
+	Compatibilities        []*Compatibility `locationName:"compatibilities" type:"list"`
+	RequiresIAMPermissions *bool            `locationName:"requiresIamPermissions" type:"boolean"`
+}
+
+// AddonInfo describes an add-on and its versions
+type AddonInfo struct {
+	_ struct{} `type:"structure"`
+
+	AddonName     *string             `locationName:"addonName" type:"string"`
+	Type          *string             `locationName:"type" type:"string"`
+	Publisher     *string             `locationName:"publisher" type:"string"`
+	AddonVersions []*AddonVersionInfo `locationName:"addonVersions" type:"list"`
+}
+
+// DescribeAddonVersionsInput is the input of DescribeAddonVersions
+type DescribeAddonVersionsInput struct {
+	_ struct{} `type:"structure"`
+
+	AddonName         *string `location:"querystring" locationName:"addonName" type:"string"`
+	KubernetesVersion *string `location:"querystring" locationName:"kubernetesVersion" type:"string"`
+	NextToken         *string `location:"querystring" locationName:"nextToken" type:"string"`
+}
+
+// DescribeAddonVersionsOutput is the output of DescribeAddonVersions
+type DescribeAddonVersionsOutput struct {
+	_ struct{} `type:"structure"`
+
+	Addons    []*AddonInfo `locationName:"addons" type:"list"`
+	NextToken *string      `locationName:"nextToken" type:"string"`
+}
+
+// DescribeAddonConfigurationInput is the input of DescribeAddonConfiguration
+type DescribeAddonConfigurationInput struct {
+	_ struct{} `type:"structure"`
+
+	AddonName    *string `location:"querystring" locationName:"addonName" type:"string" required:"true"`
+	AddonVersion *string `location:"querystring" locationName:"addonVersion" type:"string" required:"true"`
+}
+
+// PodIdentityConfiguration is a service account of an add-on that can use EKS Pod Identity
+type PodIdentityConfiguration struct {
+	_ struct{} `type:"structure"`
+
+	ServiceAccount             *string   `locationName:"serviceAccount" type:"string"`
+	RecommendedManagedPolicies []*string `locationName:"recommendedManagedPolicies" type:"list"`
+}
+
+// DescribeAddonConfigurationOutput is the output of DescribeAddonConfiguration
+type DescribeAddonConfigurationOutput struct {
+	_ struct{} `type:"structure"`
+
+	AddonName                *string                     `locationName:"addonName" type:"string"`
+	AddonVersion             *string                     `locationName:"addonVersion" type:"string"`
+	ConfigurationSchema      *string                     `locationName:"configurationSchema" type:"string"`
+	PodIdentityConfiguration []*PodIdentityConfiguration `locationName:"podIdentityConfiguration" type:"list"`
+}
+
+// ListAddonsInput is the input of ListAddons
+type ListAddonsInput struct {
+	_ struct{} `type:"structure"`
+
+	ClusterName *string `location:"uri" locationName:"name" type:"string" required:"true"`
+	NextToken   *string `location:"querystring" locationName:"nextToken" type:"string"`
+}
+
+// ListAddonsOutput is the output of ListAddons
+type ListAddonsOutput struct {
+	_ struct{} `type:"structure"`
+
+	Addons    []*string `locationName:"addons" type:"list"`
+	NextToken *string   `locationName:"nextToken" type:"string"`
+}
+
+// DescribeAddonInput is the input of DescribeAddon
+type DescribeAddonInput struct {
+	_ struct{} `type:"structure"`
+
+	ClusterName *string `location:"uri" locationName:"name" type:"string" required:"true"`
+	AddonName   *string `location:"uri" locationName:"addonName" type:"string" required:"true"`
+}
+
+// Addon is an add-on installed in a cluster
+type Addon struct {
+	_ struct{} `type:"structure"`
+
+	AddonName             *string            `locationName:"addonName" type:"string"`
+	AddonVersion          *string            `locationName:"addonVersion" type:"string"`
+	Status                *string            `locationName:"status" type:"string"`
+	ServiceAccountRoleArn *string            `locationName:"serviceAccountRoleArn" type:"string"`
+	ConfigurationValues   *string            `locationName:"configurationValues" type:"string"`
+	CreatedAt             *time.Time         `locationName:"createdAt" type:"timestamp"`
+	Tags                  map[string]*string `locationName:"tags" type:"map"`
+}
+
+// DescribeAddonOutput is the output of DescribeAddon
+type DescribeAddonOutput struct {
+	_ struct{} `type:"structure"`
+
+	Addon *Addon `locationName:"addon" type:"structure"`
+}
+
+// UpdateAddonInput is the input of UpdateAddon
+type UpdateAddonInput struct {
+	_ struct{} `type:"structure"`
+
+	ClusterName         *string `location:"uri" locationName:"name" type:"string" required:"true"`
+	AddonName           *string `location:"uri" locationName:"addonName" type:"string" required:"true"`
+	AddonVersion        *string `locationName:"addonVersion" type:"string"`
+	ResolveConflicts    *string `locationName:"resolveConflicts" type:"string"`
+	ConfigurationValues *string `locationName:"configurationValues" type:"string"`
+}
+
+// UpdateAddonOutput is the output of UpdateAddon
+type UpdateAddonOutput struct {
+	_ struct{} `type:"structure"`
+
+	Update *awseks.Update `locationName:"update" type:"structure"`
+}
+
+// CreateAddonInput is the input of CreateAddon
+type CreateAddonInput struct {
+	_ struct{} `type:"structure"`
+
+	ClusterName           *string `location:"uri" locationName:"name" type:"string" required:"true"`
+	AddonName             *string `locationName:"addonName" type:"string" required:"true"`
+	AddonVersion          *string `locationName:"addonVersion" type:"string"`
+	ResolveConflicts      *string `locationName:"resolveConflicts" type:"string"`
+	ConfigurationValues   *string `locationName:"configurationValues" type:"string"`
+	ServiceAccountRoleArn *string `locationName:"serviceAccountRoleArn" type:"string"`
+}
+
+// CreateAddonOutput is the output of CreateAddon
+type CreateAddonOutput struct {
+	_ struct{} `type:"structure"`
+
+	Addon *Addon `locationName:"addon" type:"structure"`
+}
+
+// DeleteAddonInput is the input of DeleteAddon
+type DeleteAddonInput struct {
+	_ struct{} `type:"structure"`
+
+	ClusterName *string `location:"uri" locationName:"name" type:"string" required:"true"`
+	AddonName   *string `location:"uri" locationName:"addonName" type:"string" required:"true"`
+	Preserve    *bool   `location:"querystring" locationName:"preserve" type:"boolean"`
+}
+
+// DeleteAddonOutput is the output of DeleteAddon
+type DeleteAddonOutput struct {
+	_ struct{} `type:"structure"`
+
+	Addon *Addon `locationName:"addon" type:"structure"`
+}
+
+type eksAddonsClient struct {
+	*awseks.EKS
+}
+
+// New returns a client of the EKS add-ons API, sending requests with the given EKS client
+func New(client *awseks.EKS) API {
+	return &eksAddonsClient{EKS: client}
+}
+
+func (c *eksAddonsClient) DescribeAddonVersions(input *DescribeAddonVersionsInput) (*DescribeAddonVersionsOutput, error) {
+	op := &request.Operation{
+		Name:       "DescribeAddonVersions",
+		HTTPMethod: "GET",
+		HTTPPath:   "/addons/supported-versions",
+	}
+	output := &DescribeAddonVersionsOutput{}
+	return output, c.NewRequest(op, input, output).Send()
+}
+
+func (c *eksAddonsClient) DescribeAddonConfiguration(input *DescribeAddonConfigurationInput) (*DescribeAddonConfigurationOutput, error) {
+	op := &request.Operation{
+		Name:       "DescribeAddonConfiguration",
+		HTTPMethod: "GET",
+		HTTPPath:   "/addons/configuration-schemas",
+	}
+	output := &DescribeAddonConfigurationOutput{}
+	return output, c.NewRequest(op, input, output).Send()
+}
+
+func (c *eksAddonsClient) ListAddons(input *ListAddonsInput) (*ListAddonsOutput, error) {
+	op := &request.Operation{
+		Name:       "ListAddons",
+		HTTPMethod: "GET",
+		HTTPPath:   "/clusters/{name}/addons",
+	}
+	output := &ListAddonsOutput{}
+	return output, c.NewRequest(op, input, output).Send()
+}
+
+func (c *eksAddonsClient) DescribeAddon(input *DescribeAddonInput) (*DescribeAddonOutput, error) {
+	op := &request.Operation{
+		Name:       "DescribeAddon",
+		HTTPMethod: "GET",
+		HTTPPath:   "/clusters/{name}/addons/{addonName}",
+	}
+	output := &DescribeAddonOutput{}
+	return output, c.NewRequest(op, input, output).Send()
+}
+
+func (c *eksAddonsClient) UpdateAddon(input *UpdateAddonInput) (*UpdateAddonOutput, error) {
+	op := &request.Operation{
+		Name:       "UpdateAddon",
+		HTTPMethod: "POST",
+		HTTPPath:   "/clusters/{name}/addons/{addonName}/update",
+	}
+	output := &UpdateAddonOutput{}
+	return output, c.NewRequest(op, input, output).Send()
+}
+
+func (c *eksAddonsClient) CreateAddon(input *CreateAddonInput) (*CreateAddonOutput, error) {
+	op := &request.Operation{
+		Name:       "CreateAddon",
+		HTTPMethod: "POST",
+		HTTPPath:   "/clusters/{name}/addons",
+	}
+	output := &CreateAddonOutput{}
+	return output, c.NewRequest(op, input, output).Send()
+}
+
+func (c *eksAddonsClient) DeleteAddon(input *DeleteAddonInput) (*DeleteAddonOutput, error) {
+	op := &request.Operation{
+		Name:       "DeleteAddon",
+		HTTPMethod: "DELETE",
+		HTTPPath:   "/clusters/{name}/addons/{addonName}",
+	}
+	output := &DeleteAddonOutput{}
+	return output, c.NewRequest(op, input, output).Send()
+}
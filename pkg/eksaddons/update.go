@@ -0,0 +1,81 @@
+package eksaddons
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// DefaultVersion returns the version of an add-on EKS installs by default for kubernetesVersion
+func DefaultVersion(api API, addonName, kubernetesVersion string) (string, error) {
+	output, err := api.DescribeAddonVersions(&DescribeAddonVersionsInput{
+		AddonName:         &addonName,
+		KubernetesVersion: &kubernetesVersion,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "describing the versions of add-on %q", addonName)
+	}
+	for _, info := range output.Addons {
+		if version := defaultVersion(info, kubernetesVersion); version != "" {
+			return version, nil
+		}
+	}
+	return "", fmt.Errorf("no default version of add-on %q for Kubernetes %s", addonName, kubernetesVersion)
+}
+
+// CompatibleVersions returns the versions of an add-on that support kubernetesVersion
+func CompatibleVersions(api API, addonName, kubernetesVersion string) ([]string, error) {
+	output, err := api.DescribeAddonVersions(&DescribeAddonVersionsInput{
+		AddonName:         &addonName,
+		KubernetesVersion: &kubernetesVersion,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "describing the versions of add-on %q", addonName)
+	}
+	var versions []string
+	for _, info := range output.Addons {
+		for _, version := range info.AddonVersions {
+			for _, compatibility := range version.Compatibilities {
+				if aws.StringValue(compatibility.ClusterVersion) == kubernetesVersion {
+					versions = append(versions, aws.StringValue(version.AddonVersion))
+					break
+				}
+			}
+		}
+	}
+	return versions, nil
+}
+
+// UpdateToDefaultVersion updates an installed add-on to its default version for kubernetesVersion,
+// unless it's already at that version or a later one; it returns true when an update is required
+// in plan mode
+func UpdateToDefaultVersion(api API, clusterName string, addon *Addon, kubernetesVersion string, plan bool) (bool, error) {
+	name := aws.StringValue(addon.AddonName)
+	current := aws.StringValue(addon.AddonVersion)
+
+	desired, err := DefaultVersion(api, name, kubernetesVersion)
+	if err != nil {
+		return false, err
+	}
+	if compareVersions(current, desired) >= 0 {
+		logger.Info("EKS add-on %q is already up-to-date (%s)", name, current)
+		return false, nil
+	}
+	if plan {
+		logger.Critical("(plan) EKS add-on %q is not up-to-date, it would be updated from %s to %s", name, current, desired)
+		return true, nil
+	}
+	if _, err := api.UpdateAddon(&UpdateAddonInput{
+		ClusterName:      &clusterName,
+		AddonName:        &name,
+		AddonVersion:     &desired,
+		ResolveConflicts: aws.String(ResolveConflictsPreserve),
+	}); err != nil {
+		return false, errors.Wrapf(err, "updating EKS add-on %q", name)
+	}
+	logger.Info("EKS add-on %q is being updated from %s to %s", name, current, desired)
+	return false, nil
+}
@@ -0,0 +1,57 @@
+package elb
+
+import (
+	"context"
+
+	"github.com/weaveworks/eksctl/pkg/cleanup"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// Cleaner deletes the load balancers of Kubernetes services, it implements
+// cleanup.PhasedCleaner and keeps the services it discovered until it is run again
+type Cleaner struct {
+	services *serviceCleanup
+}
+
+// Description implements cleanup.Cleaner
+func (*Cleaner) Description() string {
+	return "LoadBalancer services"
+}
+
+// Cleanup implements cleanup.Cleaner, it does nothing if the cluster can't be reached
+func (*Cleaner) Cleanup(ctx context.Context, params cleanup.Params) error {
+	if params.ClientSet == nil {
+		logger.Debug("skipping the cleanup of LoadBalancer services, as the cluster can't be reached")
+		return nil
+	}
+	p := params.Provider
+	return Cleanup(ctx, p.EC2(), p.ELB(), p.ELBV2(), params.ClientSet, params.ClusterConfig)
+}
+
+// Discover implements cleanup.PhasedCleaner, it finds nothing if the cluster can't be reached
+func (c *Cleaner) Discover(ctx context.Context, params cleanup.Params) ([]string, error) {
+	c.services = nil
+	if params.ClientSet == nil {
+		logger.Debug("skipping the cleanup of LoadBalancer services, as the cluster can't be reached")
+		return nil, nil
+	}
+	p := params.Provider
+	c.services = newServiceCleanup(p.EC2(), p.ELB(), p.ELBV2(), params.ClientSet, params.ClusterConfig)
+	return c.services.discover(ctx)
+}
+
+// Delete implements cleanup.PhasedCleaner
+func (c *Cleaner) Delete(_ context.Context, _ cleanup.Params) error {
+	if c.services == nil {
+		return nil
+	}
+	return c.services.deleteServices()
+}
+
+// Wait implements cleanup.PhasedCleaner
+func (c *Cleaner) Wait(ctx context.Context, _ cleanup.Params) ([]string, error) {
+	if c.services == nil {
+		return nil, nil
+	}
+	return c.services.wait(ctx)
+}
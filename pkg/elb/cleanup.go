@@ -16,7 +16,6 @@ import (
 	"github.com/aws/aws-sdk-go/service/elb/elbiface"
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
-	"github.com/kris-nova/logger"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -26,6 +25,8 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws/request"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/utils/retry"
 )
 
 const (
@@ -43,25 +44,64 @@ type loadBalancer struct {
 func Cleanup(ctx context.Context, ec2API ec2iface.EC2API, elbAPI elbiface.ELBAPI, elbv2API elbv2iface.ELBV2API,
 	kubernetesCS kubernetes.Interface, clusterConfig *api.ClusterConfig) error {
 
-	deadline, ok := ctx.Deadline()
-	if !ok {
+	if _, ok := ctx.Deadline(); !ok {
 		return fmt.Errorf("no context deadline set in call to elb.Cleanup()")
 	}
-	services, err := kubernetesCS.CoreV1().Services(metav1.NamespaceAll).List(metav1.ListOptions{})
+	c := newServiceCleanup(ec2API, elbAPI, elbv2API, kubernetesCS, clusterConfig)
+	if _, err := c.discover(ctx); err != nil {
+		return err
+	}
+	if err := c.deleteServices(); err != nil {
+		return err
+	}
+	_, err := c.wait(ctx)
+	return err
+}
+
+// serviceCleanup deletes the LoadBalancer services of a cluster in phases, tracking their
+// load balancers in between
+type serviceCleanup struct {
+	ec2API        ec2iface.EC2API
+	elbAPI        elbiface.ELBAPI
+	elbv2API      elbv2iface.ELBV2API
+	kubernetesCS  kubernetes.Interface
+	clusterConfig *api.ClusterConfig
+
+	services      []corev1.Service
+	loadBalancers map[string]loadBalancer
+	// serviceNames maps the names of the load balancers to their services
+	serviceNames map[string]string
+}
+
+func newServiceCleanup(ec2API ec2iface.EC2API, elbAPI elbiface.ELBAPI, elbv2API elbv2iface.ELBV2API,
+	kubernetesCS kubernetes.Interface, clusterConfig *api.ClusterConfig) *serviceCleanup {
+	return &serviceCleanup{
+		ec2API:        ec2API,
+		elbAPI:        elbAPI,
+		elbv2API:      elbv2API,
+		kubernetesCS:  kubernetesCS,
+		clusterConfig: clusterConfig,
+		loadBalancers: map[string]loadBalancer{},
+		serviceNames:  map[string]string{},
+	}
+}
+
+// discover finds the services of type 'LoadBalancer' and their ELBs, returning the names of the services
+func (c *serviceCleanup) discover(ctx context.Context) ([]string, error) {
+	services, err := c.kubernetesCS.CoreV1().Services(metav1.NamespaceAll).List(metav1.ListOptions{})
 	if err != nil {
 		errStr := fmt.Sprintf("cannot list Kubernetes Services: %s", err)
 		if k8serrors.IsForbidden(err) {
 			errStr = fmt.Sprintf("%s (deleting a cluster requires permission to list Kubernetes services)", errStr)
 		}
-		return errors.New(errStr)
+		return nil, errors.New(errStr)
 	}
 
-	// Delete Services of type 'LoadBalancer', collecting their ELBs to wait for them to be deleted later on
-	loadBalancers := map[string]loadBalancer{}
+	var names []string
 	for _, s := range services.Items {
-		lb, err := getServiceLoadBalancer(ctx, ec2API, elbAPI, clusterConfig.Metadata.Name, &s)
+		lb, err := getServiceLoadBalancer(ctx, c.ec2API, c.elbAPI, c.clusterConfig.Metadata.Name, &s)
 		if err != nil {
-			return fmt.Errorf("cannot obtain information for ELB %s from LoadBalancer service %s/%s: %s",
+			return nil, fmt.Errorf("cannot obtain information for ELB %s from LoadBalancer service %s/%s: %s",
 				cloudprovider.DefaultLoadBalancerName(&s), s.Namespace, s.Name, err)
 		}
 		if lb == nil {
@@ -69,9 +109,19 @@ func Cleanup(ctx context.Context, ec2API ec2iface.EC2API, elbAPI elbiface.ELBAPI
 		}
 		logger.Debug("tracking deletion of Load Balancer %s of kind %d with security groups %v",
 			lb.name, lb.kind, convertStringSetToSlice(lb.ownedSecurityGroupIDs))
-		loadBalancers[lb.name] = *lb
+		c.loadBalancers[lb.name] = *lb
+		c.services = append(c.services, s)
+		c.serviceNames[lb.name] = fmt.Sprintf("%s/%s", s.Namespace, s.Name)
+		names = append(names, c.serviceNames[lb.name])
+	}
+	return names, nil
+}
+
+// deleteServices deletes the discovered services, for the cloud provider to delete their ELBs
+func (c *serviceCleanup) deleteServices() error {
+	for _, s := range c.services {
 		logger.Debug("deleting 'type: LoadBalancer' service %s/%s", s.Namespace, s.Name)
-		if err := kubernetesCS.CoreV1().Services(s.Namespace).Delete(s.Name, &metav1.DeleteOptions{}); err != nil {
+		if err := c.kubernetesCS.CoreV1().Services(s.Namespace).Delete(s.Name, &metav1.DeleteOptions{}); err != nil {
 			errStr := fmt.Sprintf("cannot delete Kubernetes Service %s/%s: %s", s.Namespace, s.Name, err)
 			if k8serrors.IsForbidden(err) {
 				errStr = fmt.Sprintf("%s (deleting a cluster requires permission to delete Kubernetes services)", errStr)
@@ -79,12 +129,20 @@ func Cleanup(ctx context.Context, ec2API ec2iface.EC2API, elbAPI elbiface.ELBAPI
 			return errors.New(errStr)
 		}
 	}
+	return nil
+}
+
+// wait waits until the deadline of ctx for the ELBs of the deleted services to disappear, returning
+// the names of the services whose ELBs are left, and then deletes the orphan security groups of load balancers
+func (c *serviceCleanup) wait(ctx context.Context) ([]string, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		return nil, fmt.Errorf("no context deadline set in call to elb.Cleanup()")
+	}
 
 	// Wait for all the load balancers backing the LoadBalancer services to disappear
-	pollInterval := 2 * time.Second
-	for ; time.Now().Before(deadline) && len(loadBalancers) > 0; time.Sleep(pollInterval) {
-		for name, lb := range loadBalancers {
-			exists, err := loadBalancerExists(ctx, ec2API, elbAPI, elbv2API, lb)
+	err := retry.DefaultPoller.Poll(ctx, func() (bool, error) {
+		for name, lb := range c.loadBalancers {
+			exists, err := loadBalancerExists(ctx, c.ec2API, c.elbAPI, c.elbv2API, lb)
 			if err != nil {
 				logger.Warning("error when checking existence of load balancer %s: %s", lb.name, err)
 			}
@@ -93,20 +151,24 @@ func Cleanup(ctx context.Context, ec2API ec2iface.EC2API, elbAPI elbiface.ELBAPI
 			}
 			logger.Debug("load balancer %s and its security groups were deleted by the cloud provider", name)
 			// The load balancer and its security groups have been deleted
-			delete(loadBalancers, name)
+			delete(c.loadBalancers, name)
 		}
-	}
-
-	if len(loadBalancers) > 0 {
-		return fmt.Errorf("deadline surpased waiting for load balancers to be deleted")
+		return len(c.loadBalancers) == 0, nil
+	})
+	if err != nil {
+		remaining := make([]string, 0, len(c.loadBalancers))
+		for name := range c.loadBalancers {
+			remaining = append(remaining, c.serviceNames[name])
+		}
+		return remaining, errors.Wrap(err, "waiting for load balancers to be deleted")
 	}
 	logger.Debug("deleting Load Balancer Security Group orphans")
 	// Orphan security-group deletion is needed due to https://github.com/kubernetes/kubernetes/issues/79994
 	// and because we could have started the service deletion when a service didn't finish its creation
-	if err := deleteOrphanLoadBalancerSecurityGroups(ctx, ec2API, elbAPI, clusterConfig); err != nil {
-		return fmt.Errorf("cannot delete orphan ELB Security Groups: %s", err)
+	if err := deleteOrphanLoadBalancerSecurityGroups(ctx, c.ec2API, c.elbAPI, c.clusterConfig); err != nil {
+		return nil, fmt.Errorf("cannot delete orphan ELB Security Groups: %s", err)
 	}
-	return nil
+	return nil, nil
 }
 
 func getServiceLoadBalancer(ctx context.Context, ec2API ec2iface.EC2API, elbAPI elbiface.ELBAPI, clusterName string,
@@ -206,40 +268,29 @@ func ensureLoadBalancerDeleted(ctx context.Context, elbAPI elbiface.ELBAPI, sg *
 	}
 	loadBalancerName := match[1]
 
-	var (
-		lbDeleteTimeout = 30 * time.Second
-		lbRetryAfter    = 3 * time.Second
-	)
-
+	lbDeleteTimeout := 30 * time.Second
 	timeoutCtx, cancelFunc := context.WithTimeout(ctx, lbDeleteTimeout)
 	defer cancelFunc()
 
-	for {
-		input := &elb.DescribeLoadBalancersInput{
-			LoadBalancerNames: []*string{aws.String(loadBalancerName)},
-		}
-
+	input := &elb.DescribeLoadBalancersInput{
+		LoadBalancerNames: []*string{aws.String(loadBalancerName)},
+	}
+	err := retry.DefaultPoller.Poll(timeoutCtx, func() (bool, error) {
 		if _, err := elbAPI.DescribeLoadBalancersWithContext(timeoutCtx, input); err != nil {
 			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == elb.ErrCodeAccessPointNotFoundException {
-				return nil
-			} else if err == context.DeadlineExceeded {
-				return errors.Wrap(err, "timed out looking up load balancer")
+				return true, nil
 			} else if request.IsErrorRetryable(err) {
-				logger.Debug("retrying request after %v", lbRetryAfter)
-			} else {
-				return err
+				logger.Debug("retrying request to describe load balancer %s", loadBalancerName)
+			} else if timeoutCtx.Err() == nil {
+				return false, err
 			}
 		}
-
-		timer := time.NewTimer(lbRetryAfter)
-		select {
-		case <-timeoutCtx.Done():
-			timer.Stop()
-			return errors.Wrap(timeoutCtx.Err(), "timed out waiting for load balancer's deletion")
-		case <-timer.C:
-		}
-
+		return false, nil
+	})
+	if err == context.DeadlineExceeded || err == context.Canceled {
+		return errors.Wrap(err, "timed out waiting for load balancer's deletion")
 	}
+	return err
 }
 
 func deleteSecurityGroup(ctx context.Context, ec2API ec2iface.EC2API, sg *ec2.SecurityGroup) error {
@@ -0,0 +1,183 @@
+package elb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+	"github.com/pkg/errors"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/weaveworks/eksctl/pkg/cleanup"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/utils/retry"
+)
+
+const (
+	ingressClassAnnotation = "kubernetes.io/ingress.class"
+	albIngressClass        = "alb"
+)
+
+// IngressCleaner deletes the Ingresses of the AWS Load Balancer Controller and waits for their
+// ALBs to be deleted, it implements cleanup.PhasedCleaner. It has to run while the controller
+// is still running, i.e. before the nodes and Fargate profiles are deleted
+type IngressCleaner struct {
+	ingresses *ingressCleanup
+}
+
+// Description implements cleanup.Cleaner
+func (*IngressCleaner) Description() string {
+	return "ALB Ingresses"
+}
+
+// Cleanup implements cleanup.Cleaner
+func (c *IngressCleaner) Cleanup(ctx context.Context, params cleanup.Params) error {
+	if _, err := c.Discover(ctx, params); err != nil {
+		return err
+	}
+	if err := c.Delete(ctx, params); err != nil {
+		return err
+	}
+	remaining, err := c.Wait(ctx, params)
+	if err != nil {
+		return err
+	}
+	if len(remaining) > 0 {
+		return fmt.Errorf("the ALBs of Ingresses %s are still being deleted", strings.Join(remaining, ", "))
+	}
+	return nil
+}
+
+// Discover implements cleanup.PhasedCleaner, it finds nothing if the cluster can't be reached
+func (c *IngressCleaner) Discover(_ context.Context, params cleanup.Params) ([]string, error) {
+	c.ingresses = nil
+	if params.ClientSet == nil {
+		logger.Debug("skipping the cleanup of ALB Ingresses, as the cluster can't be reached")
+		return nil, nil
+	}
+	c.ingresses = &ingressCleanup{
+		elbv2API:     params.Provider.ELBV2(),
+		kubernetesCS: params.ClientSet,
+		hostnames:    map[string]string{},
+	}
+	return c.ingresses.discover()
+}
+
+// Delete implements cleanup.PhasedCleaner
+func (c *IngressCleaner) Delete(_ context.Context, _ cleanup.Params) error {
+	if c.ingresses == nil {
+		return nil
+	}
+	return c.ingresses.deleteIngresses()
+}
+
+// Wait implements cleanup.PhasedCleaner
+func (c *IngressCleaner) Wait(ctx context.Context, _ cleanup.Params) ([]string, error) {
+	if c.ingresses == nil {
+		return nil, nil
+	}
+	return c.ingresses.wait(ctx)
+}
+
+type ingressCleanup struct {
+	elbv2API     elbv2iface.ELBV2API
+	kubernetesCS kubernetes.Interface
+
+	ingresses []networkingv1beta1.Ingress
+	// hostnames maps the DNS names of the ALBs to their Ingresses
+	hostnames map[string]string
+}
+
+// discover finds the Ingresses of class alb, returning their names
+func (c *ingressCleanup) discover() ([]string, error) {
+	ingresses, err := c.kubernetesCS.NetworkingV1beta1().Ingresses(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		errStr := fmt.Sprintf("cannot list Kubernetes Ingresses: %s", err)
+		if k8serrors.IsForbidden(err) {
+			errStr = fmt.Sprintf("%s (deleting a cluster requires permission to list Kubernetes ingresses)", errStr)
+		}
+		return nil, errors.New(errStr)
+	}
+
+	var names []string
+	for _, ingress := range ingresses.Items {
+		if ingress.Annotations[ingressClassAnnotation] != albIngressClass {
+			continue
+		}
+		name := fmt.Sprintf("%s/%s", ingress.Namespace, ingress.Name)
+		for _, lb := range ingress.Status.LoadBalancer.Ingress {
+			if lb.Hostname != "" {
+				logger.Debug("tracking deletion of ALB %s of Ingress %s", lb.Hostname, name)
+				c.hostnames[strings.ToLower(lb.Hostname)] = name
+			}
+		}
+		c.ingresses = append(c.ingresses, ingress)
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// deleteIngresses deletes the discovered Ingresses, for the AWS Load Balancer Controller to
+// delete their ALBs
+func (c *ingressCleanup) deleteIngresses() error {
+	for _, ingress := range c.ingresses {
+		logger.Debug("deleting ALB Ingress %s/%s", ingress.Namespace, ingress.Name)
+		err := c.kubernetesCS.NetworkingV1beta1().Ingresses(ingress.Namespace).Delete(ingress.Name, &metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			errStr := fmt.Sprintf("cannot delete Kubernetes Ingress %s/%s: %s", ingress.Namespace, ingress.Name, err)
+			if k8serrors.IsForbidden(err) {
+				errStr = fmt.Sprintf("%s (deleting a cluster requires permission to delete Kubernetes ingresses)", errStr)
+			}
+			return errors.New(errStr)
+		}
+	}
+	return nil
+}
+
+// wait waits for the ALBs of the deleted Ingresses to disappear, returning the names of the
+// Ingresses whose ALBs are left when ctx is done
+func (c *ingressCleanup) wait(ctx context.Context) ([]string, error) {
+	err := retry.DefaultPoller.Poll(ctx, func() (bool, error) {
+		existing, err := c.existingHostnames(ctx)
+		if err != nil {
+			logger.Warning("error when checking existence of ALBs: %s", err)
+			return false, nil
+		}
+		for hostname, name := range c.hostnames {
+			if _, ok := existing[hostname]; !ok {
+				logger.Debug("ALB %s of Ingress %s was deleted by the AWS Load Balancer Controller", hostname, name)
+				delete(c.hostnames, hostname)
+			}
+		}
+		return len(c.hostnames) == 0, nil
+	})
+	if err != nil {
+		var remaining []string
+		seen := map[string]struct{}{}
+		for _, name := range c.hostnames {
+			if _, ok := seen[name]; !ok {
+				seen[name] = struct{}{}
+				remaining = append(remaining, name)
+			}
+		}
+		return remaining, errors.Wrap(err, "waiting for ALBs to be deleted")
+	}
+	return nil, nil
+}
+
+func (c *ingressCleanup) existingHostnames(ctx context.Context) (map[string]struct{}, error) {
+	existing := map[string]struct{}{}
+	err := c.elbv2API.DescribeLoadBalancersPagesWithContext(ctx, &elbv2.DescribeLoadBalancersInput{}, func(page *elbv2.DescribeLoadBalancersOutput, _ bool) bool {
+		for _, lb := range page.LoadBalancers {
+			existing[strings.ToLower(aws.StringValue(lb.DNSName))] = struct{}{}
+		}
+		return true
+	})
+	return existing, err
+}
@@ -0,0 +1,98 @@
+package elb
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/weaveworks/eksctl/pkg/cleanup"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+func newIngress(name, class, hostname string) *networkingv1beta1.Ingress {
+	return &networkingv1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Annotations: map[string]string{ingressClassAnnotation: class},
+		},
+		Status: networkingv1beta1.IngressStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{{Hostname: hostname}},
+			},
+		},
+	}
+}
+
+var _ = Describe("IngressCleaner", func() {
+	var (
+		provider  *mockprovider.MockProvider
+		clientSet *fake.Clientset
+		params    cleanup.Params
+		albs      []string
+	)
+
+	BeforeEach(func() {
+		provider = mockprovider.NewMockProvider()
+		clientSet = fake.NewSimpleClientset(
+			newIngress("web", "alb", "k8s-default-web-1234.us-west-2.elb.amazonaws.com"),
+			newIngress("api", "nginx", "a1b2c3.us-west-2.elb.amazonaws.com"),
+		)
+		params = cleanup.Params{Provider: provider, ClientSet: clientSet}
+		albs = nil
+
+		provider.MockELBV2().On("DescribeLoadBalancersPagesWithContext", mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				page := &elbv2.DescribeLoadBalancersOutput{}
+				for _, alb := range albs {
+					page.LoadBalancers = append(page.LoadBalancers, &elbv2.LoadBalancer{DNSName: aws.String(alb)})
+				}
+				args.Get(2).(func(*elbv2.DescribeLoadBalancersOutput, bool) bool)(page, true)
+			}).Return(nil)
+	})
+
+	It("deletes the Ingresses of class alb and waits for their ALBs", func() {
+		cleaner := &IngressCleaner{}
+		found, err := cleaner.Discover(context.Background(), params)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(Equal([]string{"default/web"}))
+
+		Expect(cleaner.Delete(context.Background(), params)).To(Succeed())
+		ingresses, err := clientSet.NetworkingV1beta1().Ingresses("default").List(metav1.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ingresses.Items).To(HaveLen(1))
+		Expect(ingresses.Items[0].Name).To(Equal("api"))
+
+		remaining, err := cleaner.Wait(context.Background(), params)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(remaining).To(BeEmpty())
+	})
+
+	It("returns the Ingresses whose ALBs are left", func() {
+		albs = []string{"K8S-default-web-1234.us-west-2.elb.amazonaws.com"}
+		cleaner := &IngressCleaner{}
+		_, err := cleaner.Discover(context.Background(), params)
+		Expect(err).ToNot(HaveOccurred())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		remaining, err := cleaner.Wait(ctx, params)
+		Expect(err).To(MatchError("waiting for ALBs to be deleted: context deadline exceeded"))
+		Expect(remaining).To(Equal([]string{"default/web"}))
+	})
+
+	It("does nothing if the cluster can't be reached", func() {
+		found, err := (&IngressCleaner{}).Discover(context.Background(), cleanup.Params{Provider: provider})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeEmpty())
+	})
+})
@@ -0,0 +1,217 @@
+package elb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+	"github.com/pkg/errors"
+	awsprovider "k8s.io/legacy-cloud-providers/aws"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cleanup"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/utils/retry"
+)
+
+const (
+	// albClusterTag is the tag the AWS Load Balancer Controller adds to its load balancers
+	albClusterTag = "elbv2.k8s.aws/cluster"
+	// maxDescribeTags is the number of load balancers DescribeTags accepts at once
+	maxDescribeTags = 20
+)
+
+// OrphanCleaner deletes the load balancers created for the Services and Ingresses of a
+// cluster whose control plane is gone, finding them by the tags the cloud provider and the
+// AWS Load Balancer Controller add, as their Services and Ingresses can't be deleted;
+// it implements cleanup.PhasedCleaner
+type OrphanCleaner struct {
+	orphans *orphanCleanup
+}
+
+// Description implements cleanup.Cleaner
+func (*OrphanCleaner) Description() string {
+	return "load balancers tagged with the cluster"
+}
+
+// Cleanup implements cleanup.Cleaner
+func (c *OrphanCleaner) Cleanup(ctx context.Context, params cleanup.Params) error {
+	if _, err := c.Discover(ctx, params); err != nil {
+		return err
+	}
+	if err := c.Delete(ctx, params); err != nil {
+		return err
+	}
+	_, err := c.Wait(ctx, params)
+	return err
+}
+
+// Discover implements cleanup.PhasedCleaner
+func (c *OrphanCleaner) Discover(ctx context.Context, params cleanup.Params) ([]string, error) {
+	p := params.Provider
+	c.orphans = &orphanCleanup{
+		ec2API:        p.EC2(),
+		elbAPI:        p.ELB(),
+		elbv2API:      p.ELBV2(),
+		clusterConfig: params.ClusterConfig,
+		loadBalancers: map[string]orphanLoadBalancer{},
+	}
+	return c.orphans.discover(ctx)
+}
+
+// Delete implements cleanup.PhasedCleaner
+func (c *OrphanCleaner) Delete(ctx context.Context, _ cleanup.Params) error {
+	return c.orphans.deleteLoadBalancers(ctx)
+}
+
+// Wait implements cleanup.PhasedCleaner
+func (c *OrphanCleaner) Wait(ctx context.Context, _ cleanup.Params) ([]string, error) {
+	return c.orphans.wait(ctx)
+}
+
+type orphanLoadBalancer struct {
+	loadBalancer
+	// arn is only set for load balancers of kind loadBalancerKindNetwork, which also covers ALBs
+	arn string
+}
+
+type orphanCleanup struct {
+	ec2API        ec2iface.EC2API
+	elbAPI        elbiface.ELBAPI
+	elbv2API      elbv2iface.ELBV2API
+	clusterConfig *api.ClusterConfig
+
+	loadBalancers map[string]orphanLoadBalancer
+}
+
+func (c *orphanCleanup) ownsLoadBalancer(key, value string) bool {
+	clusterName := c.clusterConfig.Metadata.Name
+	return key == awsprovider.TagNameKubernetesClusterPrefix+clusterName || (key == albClusterTag && value == clusterName)
+}
+
+// discover finds the classic and v2 load balancers tagged with the cluster, returning their names
+func (c *orphanCleanup) discover(ctx context.Context) ([]string, error) {
+	var classicNames []string
+	err := c.elbAPI.DescribeLoadBalancersPagesWithContext(ctx, &elb.DescribeLoadBalancersInput{}, func(page *elb.DescribeLoadBalancersOutput, _ bool) bool {
+		for _, lb := range page.LoadBalancerDescriptions {
+			classicNames = append(classicNames, aws.StringValue(lb.LoadBalancerName))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "describing classic load balancers")
+	}
+	for _, batch := range batches(classicNames) {
+		output, err := c.elbAPI.DescribeTagsWithContext(ctx, &elb.DescribeTagsInput{LoadBalancerNames: aws.StringSlice(batch)})
+		if err != nil {
+			return nil, errors.Wrap(err, "describing tags of classic load balancers")
+		}
+		for _, description := range output.TagDescriptions {
+			for _, tag := range description.Tags {
+				if c.ownsLoadBalancer(aws.StringValue(tag.Key), aws.StringValue(tag.Value)) {
+					name := aws.StringValue(description.LoadBalancerName)
+					c.loadBalancers[name] = orphanLoadBalancer{loadBalancer: loadBalancer{name: name, kind: loadBalancerKindClassic}}
+					break
+				}
+			}
+		}
+	}
+
+	namesByARN := map[string]string{}
+	var arns []string
+	err = c.elbv2API.DescribeLoadBalancersPagesWithContext(ctx, &elbv2.DescribeLoadBalancersInput{}, func(page *elbv2.DescribeLoadBalancersOutput, _ bool) bool {
+		for _, lb := range page.LoadBalancers {
+			arn := aws.StringValue(lb.LoadBalancerArn)
+			namesByARN[arn] = aws.StringValue(lb.LoadBalancerName)
+			arns = append(arns, arn)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "describing load balancers")
+	}
+	for _, batch := range batches(arns) {
+		output, err := c.elbv2API.DescribeTagsWithContext(ctx, &elbv2.DescribeTagsInput{ResourceArns: aws.StringSlice(batch)})
+		if err != nil {
+			return nil, errors.Wrap(err, "describing tags of load balancers")
+		}
+		for _, description := range output.TagDescriptions {
+			for _, tag := range description.Tags {
+				if c.ownsLoadBalancer(aws.StringValue(tag.Key), aws.StringValue(tag.Value)) {
+					arn := aws.StringValue(description.ResourceArn)
+					name := namesByARN[arn]
+					c.loadBalancers[name] = orphanLoadBalancer{loadBalancer: loadBalancer{name: name, kind: loadBalancerKindNetwork}, arn: arn}
+					break
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(c.loadBalancers))
+	for name := range c.loadBalancers {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (c *orphanCleanup) deleteLoadBalancers(ctx context.Context) error {
+	for name, lb := range c.loadBalancers {
+		logger.Info("deleting load balancer %s, which is tagged with cluster %q", name, c.clusterConfig.Metadata.Name)
+		var err error
+		if lb.kind == loadBalancerKindNetwork {
+			_, err = c.elbv2API.DeleteLoadBalancerWithContext(ctx, &elbv2.DeleteLoadBalancerInput{LoadBalancerArn: aws.String(lb.arn)})
+		} else {
+			_, err = c.elbAPI.DeleteLoadBalancerWithContext(ctx, &elb.DeleteLoadBalancerInput{LoadBalancerName: aws.String(name)})
+		}
+		if err != nil {
+			return errors.Wrapf(err, "deleting load balancer %s", name)
+		}
+	}
+	return nil
+}
+
+// wait waits for the load balancers to disappear, returning the names of the ones left when
+// ctx is done, and then deletes the security groups of classic load balancers, which the
+// cloud provider would otherwise have deleted
+func (c *orphanCleanup) wait(ctx context.Context) ([]string, error) {
+	err := retry.DefaultPoller.Poll(ctx, func() (bool, error) {
+		for name, lb := range c.loadBalancers {
+			exists, err := elbExists(ctx, c.elbAPI, c.elbv2API, name, lb.kind)
+			if err != nil {
+				logger.Warning("error when checking existence of load balancer %s: %s", name, err)
+			}
+			if !exists && err == nil {
+				delete(c.loadBalancers, name)
+			}
+		}
+		return len(c.loadBalancers) == 0, nil
+	})
+	if err != nil {
+		remaining := make([]string, 0, len(c.loadBalancers))
+		for name := range c.loadBalancers {
+			remaining = append(remaining, name)
+		}
+		return remaining, errors.Wrap(err, "waiting for load balancers to be deleted")
+	}
+	if err := deleteOrphanLoadBalancerSecurityGroups(ctx, c.ec2API, c.elbAPI, c.clusterConfig); err != nil {
+		return nil, fmt.Errorf("cannot delete orphan ELB Security Groups: %s", err)
+	}
+	return nil, nil
+}
+
+func batches(values []string) [][]string {
+	var result [][]string
+	for len(values) > maxDescribeTags {
+		result = append(result, values[:maxDescribeTags])
+		values = values[maxDescribeTags:]
+	}
+	if len(values) > 0 {
+		result = append(result, values)
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package elb
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cleanup"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("OrphanCleaner", func() {
+	It("deletes the load balancers tagged with the cluster", func() {
+		provider := mockprovider.NewMockProvider()
+		cfg := api.NewClusterConfig()
+		cfg.Metadata.Name = "test"
+
+		provider.MockELB().On("DescribeLoadBalancersPagesWithContext", mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				args.Get(2).(func(*elb.DescribeLoadBalancersOutput, bool) bool)(&elb.DescribeLoadBalancersOutput{
+					LoadBalancerDescriptions: []*elb.LoadBalancerDescription{
+						{LoadBalancerName: aws.String("a1b2c3")},
+						{LoadBalancerName: aws.String("other")},
+					},
+				}, true)
+			}).Return(nil)
+		provider.MockELB().On("DescribeTagsWithContext", mock.Anything, mock.Anything).Return(&elb.DescribeTagsOutput{
+			TagDescriptions: []*elb.TagDescription{
+				{
+					LoadBalancerName: aws.String("a1b2c3"),
+					Tags:             []*elb.Tag{{Key: aws.String("kubernetes.io/cluster/test"), Value: aws.String("owned")}},
+				},
+				{
+					LoadBalancerName: aws.String("other"),
+					Tags:             []*elb.Tag{{Key: aws.String("kubernetes.io/cluster/other"), Value: aws.String("owned")}},
+				},
+			},
+		}, nil)
+		provider.MockELB().On("DeleteLoadBalancerWithContext", mock.Anything, &elb.DeleteLoadBalancerInput{LoadBalancerName: aws.String("a1b2c3")}).
+			Return(&elb.DeleteLoadBalancerOutput{}, nil)
+		provider.MockELB().On("DescribeLoadBalancersWithContext", mock.Anything, mock.Anything).
+			Return(nil, awserr.New(elb.ErrCodeAccessPointNotFoundException, "not found", nil))
+
+		albARN := "arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/k8s-default-web/1234"
+		provider.MockELBV2().On("DescribeLoadBalancersPagesWithContext", mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				args.Get(2).(func(*elbv2.DescribeLoadBalancersOutput, bool) bool)(&elbv2.DescribeLoadBalancersOutput{
+					LoadBalancers: []*elbv2.LoadBalancer{{LoadBalancerArn: aws.String(albARN), LoadBalancerName: aws.String("k8s-default-web")}},
+				}, true)
+			}).Return(nil)
+		provider.MockELBV2().On("DescribeTagsWithContext", mock.Anything, mock.Anything).Return(&elbv2.DescribeTagsOutput{
+			TagDescriptions: []*elbv2.TagDescription{
+				{
+					ResourceArn: aws.String(albARN),
+					Tags:        []*elbv2.Tag{{Key: aws.String("elbv2.k8s.aws/cluster"), Value: aws.String("test")}},
+				},
+			},
+		}, nil)
+		provider.MockELBV2().On("DeleteLoadBalancerWithContext", mock.Anything, &elbv2.DeleteLoadBalancerInput{LoadBalancerArn: aws.String(albARN)}).
+			Return(&elbv2.DeleteLoadBalancerOutput{}, nil)
+		provider.MockELBV2().On("DescribeLoadBalancersWithContext", mock.Anything, mock.Anything).
+			Return(nil, awserr.New(elbv2.ErrCodeLoadBalancerNotFoundException, "not found", nil))
+
+		provider.MockEC2().On("DescribeSecurityGroupsWithContext", mock.Anything, mock.Anything).
+			Return(&ec2.DescribeSecurityGroupsOutput{}, nil)
+
+		params := cleanup.Params{ClusterConfig: cfg, Provider: provider}
+		report, err := cleanup.Run(context.Background(), params, []cleanup.Cleaner{&OrphanCleaner{}}, cleanup.Options{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Results[0].Found).To(ConsistOf("a1b2c3", "k8s-default-web"))
+		Expect(report.Results[0].Count(cleanup.ResourceDeleted)).To(Equal(2))
+		provider.MockELB().AssertCalled(GinkgoT(), "DeleteLoadBalancerWithContext", mock.Anything, &elb.DeleteLoadBalancerInput{LoadBalancerName: aws.String("a1b2c3")})
+		provider.MockELBV2().AssertCalled(GinkgoT(), "DeleteLoadBalancerWithContext", mock.Anything, &elbv2.DeleteLoadBalancerInput{LoadBalancerArn: aws.String(albARN)})
+	})
+})
@@ -1,15 +1,16 @@
 package fargate
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/eks"
 	"github.com/aws/aws-sdk-go/service/eks/eksiface"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/utils/retry"
 	"github.com/weaveworks/eksctl/pkg/utils/strings"
 )
@@ -26,19 +27,17 @@ func NewClient(clusterName string, api eksiface.EKSAPI) *Client {
 // NewClientWithWaitTimeout returns a new Fargate client configured with the
 // provided wait timeout for blocking/waiting operations.
 func NewClientWithWaitTimeout(clusterName string, api eksiface.EKSAPI, waitTimeout time.Duration) *Client {
-	return NewClientWithRetryPolicy(clusterName, api, &retry.TimingOutExponentialBackoff{
-		Timeout:  waitTimeout,
-		TimeUnit: time.Second,
-	})
+	return NewClientWithPoller(clusterName, api, retry.DefaultPoller, waitTimeout)
 }
 
-// NewClientWithRetryPolicy returns a new Fargate client configured with the
-// provided retry policy for blocking/waiting operations.
-func NewClientWithRetryPolicy(clusterName string, api eksiface.EKSAPI, retryPolicy retry.Policy) *Client {
+// NewClientWithPoller returns a new Fargate client configured with the
+// provided poller and wait timeout for blocking/waiting operations.
+func NewClientWithPoller(clusterName string, api eksiface.EKSAPI, poller retry.Poller, waitTimeout time.Duration) *Client {
 	return &Client{
 		clusterName: clusterName,
 		api:         api,
-		retryPolicy: retryPolicy,
+		poller:      poller,
+		waitTimeout: waitTimeout,
 	}
 }
 
@@ -46,7 +45,8 @@ func NewClientWithRetryPolicy(clusterName string, api eksiface.EKSAPI, retryPoli
 type Client struct {
 	clusterName string
 	api         eksiface.EKSAPI
-	retryPolicy retry.Policy
+	poller      retry.Poller
+	waitTimeout time.Duration
 }
 
 // IsUnauthorizedError reports whether the error is an authorization error
@@ -131,21 +131,88 @@ func (c Client) DeleteProfile(name string, waitForDeletion bool) error {
 	return nil
 }
 
+// DeleteProfiles deletes the Fargate profiles with the provided names one after the other, as
+// EKS only deletes one profile of a cluster at a time; the deletion is retried while another
+// profile of the cluster is being deleted, e.g. by another client, and the status of each profile
+// is logged until it's gone
+func (c Client) DeleteProfiles(names []string) error {
+	for i, name := range names {
+		logger.Info("deleting Fargate profile %q (%d/%d)", name, i+1, len(names))
+		if err := c.deleteProfileWhenNotInUse(name); err != nil {
+			return err
+		}
+		if err := c.waitForDeletionWithStatus(name); err != nil {
+			return err
+		}
+		logger.Info("deleted Fargate profile %q", name)
+	}
+	return nil
+}
+
+func (c Client) deleteProfileWhenNotInUse(name string) error {
+	var lastErr error
+	err := c.poller.PollWithTimeout(c.waitTimeout, func() (bool, error) {
+		out, err := c.api.DeleteFargateProfile(deleteRequest(c.clusterName, name))
+		logger.Debug("Fargate profile: delete request: received: %#v", out)
+		switch {
+		case err == nil, isAWSError(err, eks.ErrCodeResourceNotFoundException):
+			return true, nil
+		case !isAWSError(err, eks.ErrCodeResourceInUseException):
+			return false, err
+		}
+		logger.Info("another Fargate profile of cluster %q is being deleted, retrying the deletion of %q", c.clusterName, name)
+		lastErr = err
+		return false, nil
+	})
+	if err == context.DeadlineExceeded {
+		err = lastErr
+	}
+	return errors.Wrapf(err, "failed to delete Fargate profile %q", name)
+}
+
+func (c Client) waitForDeletionWithStatus(name string) error {
+	lastStatus := ""
+	err := c.poller.PollWithTimeout(c.waitTimeout, func() (bool, error) {
+		out, err := c.api.DescribeFargateProfile(describeRequest(c.clusterName, name))
+		if err != nil {
+			if isAWSError(err, eks.ErrCodeResourceNotFoundException) {
+				return true, nil
+			}
+			return false, errors.Wrapf(err, "failed while waiting for Fargate profile %q's deletion", name)
+		}
+		if status := strings.EmptyIfNil(out.FargateProfile.Status); status != lastStatus {
+			logger.Info("Fargate profile %q is %s", name, status)
+			if status == eks.FargateProfileStatusDeleteFailed {
+				return false, fmt.Errorf("failed to delete Fargate profile %q", name)
+			}
+			lastStatus = status
+		}
+		return false, nil
+	})
+	if err == context.DeadlineExceeded {
+		return fmt.Errorf("timed out while waiting for Fargate profile %q's deletion", name)
+	}
+	return err
+}
+
+func isAWSError(err error, code string) bool {
+	awsErr, ok := errors.Cause(err).(awserr.Error)
+	return ok && awsErr.Code() == code
+}
+
 func (c Client) waitForCreation(name string) error {
-	// Clone this client's policy to ensure this method is re-entrant/thread-safe:
-	retryPolicy := c.retryPolicy.Clone()
-	for !retryPolicy.Done() {
+	err := c.poller.PollWithTimeout(c.waitTimeout, func() (bool, error) {
 		out, err := c.api.DescribeFargateProfile(describeRequest(c.clusterName, name))
 		if err != nil {
-			return errors.Wrapf(err, "failed while waiting for Fargate profile %q's creation", name)
+			return false, errors.Wrapf(err, "failed while waiting for Fargate profile %q's creation", name)
 		}
 		logger.Debug("Fargate profile: describe request: received: %#v", out)
-		if created(out) {
-			return nil
-		}
-		time.Sleep(retryPolicy.Duration())
+		return created(out), nil
+	})
+	if err == context.DeadlineExceeded {
+		return fmt.Errorf("timed out while waiting for Fargate profile %q's creation", name)
 	}
-	return fmt.Errorf("timed out while waiting for Fargate profile %q's creation", name)
+	return err
 }
 
 func created(out *eks.DescribeFargateProfileOutput) bool {
@@ -156,19 +223,17 @@ func created(out *eks.DescribeFargateProfileOutput) bool {
 }
 
 func (c Client) waitForDeletion(name string) error {
-	// Clone this client's policy to ensure this method is re-entrant/thread-safe:
-	retryPolicy := c.retryPolicy.Clone()
-	for !retryPolicy.Done() {
+	err := c.poller.PollWithTimeout(c.waitTimeout, func() (bool, error) {
 		names, err := c.ListProfiles()
 		if err != nil {
-			return err
-		}
-		if !contains(names, name) {
-			return nil
+			return false, err
 		}
-		time.Sleep(retryPolicy.Duration())
+		return !contains(names, name), nil
+	})
+	if err == context.DeadlineExceeded {
+		return fmt.Errorf("timed out while waiting for Fargate profile %q's deletion", name)
 	}
-	return fmt.Errorf("timed out while waiting for Fargate profile %q's deletion", name)
+	return err
 }
 
 func contains(array []*string, target string) bool {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/eks"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -17,6 +18,13 @@ import (
 
 const clusterName = "non-existing-test-cluster"
 
+var (
+	// Poll without waiting at all, in order to speed tests up.
+	noWaitPoller = retry.Poller{}
+	// Wait longer between polls than the tests' timeouts.
+	slowPoller = retry.Poller{Interval: time.Second}
+)
+
 var _ = Describe("fargate", func() {
 	Describe("Client", func() {
 		Describe("CreateProfile", func() {
@@ -51,24 +59,17 @@ var _ = Describe("fargate", func() {
 			})
 
 			It("waits for the full creation of the profile when configured to do so", func() {
-				retryPolicy := &retry.ConstantBackoff{
-					// Retry up to 5 times, not waiting at all, in order to speed tests up.
-					Time: 0, TimeUnit: time.Second, MaxRetries: 5,
-				}
-				numRetriesAfterCreation := 3 // < MaxRetries
-				client := fargate.NewClientWithRetryPolicy(clusterName, mockForCreateFargateProfileWithWait(numRetriesAfterCreation), retryPolicy)
+				numRetriesAfterCreation := 3
+				client := fargate.NewClientWithPoller(clusterName, mockForCreateFargateProfileWithWait(numRetriesAfterCreation), noWaitPoller, time.Minute)
 				waitForCreation := true
 				err := client.CreateProfile(testFargateProfile(), waitForCreation)
 				Expect(err).To(Not(HaveOccurred()))
 			})
 
 			It("returns an error when waiting for the creation of the profile times out", func() {
-				retryPolicy := &retry.ConstantBackoff{
-					// Retry up to 5 times, not waiting at all, in order to speed tests up.
-					Time: 0, TimeUnit: time.Second, MaxRetries: 5,
-				}
-				numRetriesAfterCreation := 5 // == MaxRetries, i.e. we will time out.
-				client := fargate.NewClientWithRetryPolicy(clusterName, mockForCreateFargateProfileWithWait(numRetriesAfterCreation), retryPolicy)
+				numRetriesAfterCreation := 1
+				// Time out before the second poll.
+				client := fargate.NewClientWithPoller(clusterName, mockForCreateFargateProfileWithWait(numRetriesAfterCreation), slowPoller, time.Millisecond)
 				waitForCreation := true
 				err := client.CreateProfile(testFargateProfile(), waitForCreation)
 				Expect(err).To(HaveOccurred())
@@ -149,12 +150,8 @@ var _ = Describe("fargate", func() {
 
 			It("waits for the full deletion of the profile when configured to do so", func() {
 				profileName := "test-green"
-				retryPolicy := &retry.ConstantBackoff{
-					// Retry up to 5 times, not waiting at all, in order to speed tests up.
-					Time: 0, TimeUnit: time.Second, MaxRetries: 5,
-				}
-				numRetriesBeforeDeletion := 3 // < MaxRetries
-				client := fargate.NewClientWithRetryPolicy(clusterName, mockForDeleteFargateProfileWithWait(profileName, numRetriesBeforeDeletion), retryPolicy)
+				numRetriesBeforeDeletion := 3
+				client := fargate.NewClientWithPoller(clusterName, mockForDeleteFargateProfileWithWait(profileName, numRetriesBeforeDeletion), noWaitPoller, time.Minute)
 				waitForDeletion := true
 				err := client.DeleteProfile(profileName, waitForDeletion)
 				Expect(err).To(Not(HaveOccurred()))
@@ -162,21 +159,54 @@ var _ = Describe("fargate", func() {
 
 			It("returns an error when waiting for the full deletion of the profile times out", func() {
 				profileName := "test-green"
-				retryPolicy := &retry.ConstantBackoff{
-					// Retry up to 5 times, not waiting at all, in order to speed tests up.
-					Time: 0, TimeUnit: time.Second, MaxRetries: 5,
-				}
-				numRetriesBeforeDeletion := 5 // == MaxRetries, i.e. we will time out.
-				client := fargate.NewClientWithRetryPolicy(clusterName, mockForDeleteFargateProfileWithWait(profileName, numRetriesBeforeDeletion), retryPolicy)
+				numRetriesBeforeDeletion := 1
+				// Time out before the second poll.
+				client := fargate.NewClientWithPoller(clusterName, mockForDeleteFargateProfileWithWait(profileName, numRetriesBeforeDeletion), slowPoller, time.Millisecond)
 				waitForDeletion := true
 				err := client.DeleteProfile(profileName, waitForDeletion)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("timed out while waiting for Fargate profile \"test-green\"'s deletion"))
 			})
 		})
+
+		Describe("DeleteProfiles", func() {
+			It("deletes the profiles one after the other, retrying while another one is being deleted", func() {
+				mockClient := &mocks.EKSAPI{}
+				mockClient.Mock.On("DeleteFargateProfile", &eks.DeleteFargateProfileInput{
+					ClusterName:        strings.Pointer(clusterName),
+					FargateProfileName: strings.Pointer(testBlue),
+				}).Once().Return(nil, awserr.New(eks.ErrCodeResourceInUseException, "test-red is DELETING", nil))
+				mockDeleteFargateProfile(mockClient, testBlue)
+				mockDescribeFargateProfile(mockClient, testBlue, "DELETING")
+				mockDescribeFargateProfileNotFound(mockClient, testBlue)
+				mockDeleteFargateProfile(mockClient, testGreen)
+				mockDescribeFargateProfileNotFound(mockClient, testGreen)
+
+				client := fargate.NewClientWithPoller(clusterName, mockClient, noWaitPoller, time.Minute)
+				Expect(client.DeleteProfiles([]string{testBlue, testGreen})).To(Succeed())
+				mockClient.AssertExpectations(GinkgoT())
+			})
+
+			It("fails when the deletion of a profile failed", func() {
+				mockClient := &mocks.EKSAPI{}
+				mockDeleteFargateProfile(mockClient, testBlue)
+				mockDescribeFargateProfile(mockClient, testBlue, "DELETE_FAILED")
+
+				client := fargate.NewClientWithPoller(clusterName, mockClient, noWaitPoller, time.Minute)
+				err := client.DeleteProfiles([]string{testBlue, testGreen})
+				Expect(err).To(MatchError(`failed to delete Fargate profile "test-blue"`))
+			})
+		})
 	})
 })
 
+func mockDescribeFargateProfileNotFound(mockClient *mocks.EKSAPI, name string) {
+	mockClient.Mock.On("DescribeFargateProfile", &eks.DescribeFargateProfileInput{
+		ClusterName:        strings.Pointer(clusterName),
+		FargateProfileName: strings.Pointer(name),
+	}).Once().Return(nil, awserr.New(eks.ErrCodeResourceNotFoundException, "not found", nil))
+}
+
 func mockForCreateFargateProfile() *mocks.EKSAPI {
 	mockClient := mocks.EKSAPI{}
 	mockCreateFargateProfile(&mockClient)
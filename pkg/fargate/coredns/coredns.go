@@ -1,13 +1,14 @@
 package coredns
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/utils/retry"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -139,19 +140,13 @@ func patchDeployment(clientSet kubeclient.Interface) error {
 
 // WaitForScheduleOnFargate waits for coredns to be scheduled on Fargate.
 // It will wait until it has detected that the scheduling has been successful,
-// or until the retry policy times out, whichever happens first.
-func WaitForScheduleOnFargate(clientSet kubeclient.Interface, retryPolicy retry.Policy) error {
-	// Clone the retry policy to ensure this method is re-entrant/thread-safe:
-	retryPolicy = retryPolicy.Clone()
-	for !retryPolicy.Done() {
-		isScheduled, err := IsScheduledOnFargate(clientSet)
-		if err != nil {
-			return err
-		}
-		if isScheduled {
-			return nil
-		}
-		time.Sleep(retryPolicy.Duration())
+// or until the timeout expires, whichever happens first.
+func WaitForScheduleOnFargate(clientSet kubeclient.Interface, poller retry.Poller, timeout time.Duration) error {
+	err := poller.PollWithTimeout(timeout, func() (bool, error) {
+		return IsScheduledOnFargate(clientSet)
+	})
+	if err == context.DeadlineExceeded {
+		return fmt.Errorf("timed out while waiting for %q to be scheduled on Fargate", Name)
 	}
-	return fmt.Errorf("timed out while waiting for %q to be scheduled on Fargate", Name)
+	return err
 }
@@ -82,10 +82,8 @@ var (
 		},
 	}
 
-	retryPolicy = &retry.ConstantBackoff{
-		// Retry without waiting at all, in order to speed tests up.
-		Time: 0, TimeUnit: time.Second, MaxRetries: 1,
-	}
+	// Time out before the second poll, in order to speed tests up.
+	poller = retry.Poller{Interval: time.Second}
 )
 
 var _ = Describe("coredns", func() {
@@ -138,7 +136,7 @@ var _ = Describe("coredns", func() {
 				deployment("fargate", 2, 2), pod("fargate", v1.PodRunning), pod("fargate", v1.PodRunning),
 			)
 			// When:
-			err := coredns.WaitForScheduleOnFargate(mockClientset, retryPolicy)
+			err := coredns.WaitForScheduleOnFargate(mockClientset, poller, time.Millisecond)
 			// Then:
 			Expect(err).To(Not(HaveOccurred()))
 		})
@@ -157,7 +155,7 @@ var _ = Describe("coredns", func() {
 				// Given:
 				mockClientset := mockClientsetWith(failureCase...)
 				// When:
-				err := coredns.WaitForScheduleOnFargate(mockClientset, retryPolicy)
+				err := coredns.WaitForScheduleOnFargate(mockClientset, poller, time.Millisecond)
 				// Then:
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("timed out while waiting for \"coredns\" to be scheduled on Fargate"))
@@ -0,0 +1,136 @@
+package fargate
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclient "k8s.io/client-go/kubernetes"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+const (
+	// LoggingNamespace is the namespace EKS reads the config of the Fluent Bit log router of
+	// Fargate pods from
+	LoggingNamespace = "aws-observability"
+	// LoggingConfigMapName is the name of the ConfigMap holding the config of the log router
+	LoggingConfigMapName = "aws-logging"
+
+	loggingNamespaceLabel  = "aws-observability"
+	defaultLogStreamPrefix = "fargate-"
+	defaultIndex           = "fargate"
+)
+
+// LoggingOutput returns the Fluent Bit [OUTPUT] section shipping the logs of Fargate pods to the
+// destination of logging
+func LoggingOutput(logging *api.FargateLogging, clusterName, region string) (string, error) {
+	var lines []string
+	switch logging.Destination {
+	case api.FargateLoggingCloudWatch:
+		logGroupName := logging.LogGroupName
+		if logGroupName == "" {
+			logGroupName = fmt.Sprintf("/aws/eks/%s/fargate", clusterName)
+		}
+		logStreamPrefix := logging.LogStreamPrefix
+		if logStreamPrefix == "" {
+			logStreamPrefix = defaultLogStreamPrefix
+		}
+		lines = []string{
+			"Name cloudwatch_logs",
+			"Match *",
+			"region " + region,
+			"log_group_name " + logGroupName,
+			"log_stream_prefix " + logStreamPrefix,
+			"auto_create_group true",
+		}
+	case api.FargateLoggingFirehose:
+		lines = []string{
+			"Name kinesis_firehose",
+			"Match *",
+			"region " + region,
+			"delivery_stream " + logging.DeliveryStream,
+		}
+	case api.FargateLoggingOpenSearch:
+		endpoint, err := url.Parse(logging.Endpoint)
+		if err != nil {
+			return "", errors.Wrapf(err, "parsing OpenSearch endpoint %q", logging.Endpoint)
+		}
+		index := logging.Index
+		if index == "" {
+			index = defaultIndex
+		}
+		lines = []string{
+			"Name es",
+			"Match *",
+			"Host " + endpoint.Hostname(),
+			"Port 443",
+			"Index " + index,
+			"AWS_Auth On",
+			"AWS_Region " + region,
+			"tls On",
+		}
+	default:
+		return "", fmt.Errorf("unsupported Fargate logging destination %q", logging.Destination)
+	}
+	return "[OUTPUT]\n    " + strings.Join(lines, "\n    ") + "\n", nil
+}
+
+// ApplyLoggingConfig creates the aws-observability namespace and the ConfigMap configuring the
+// log router of Fargate pods, or updates the ConfigMap if it exists; pods only pick the config
+// up when they start
+func ApplyLoggingConfig(clientSet kubeclient.Interface, logging *api.FargateLogging, clusterName, region string) error {
+	output, err := LoggingOutput(logging, clusterName, region)
+	if err != nil {
+		return err
+	}
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   LoggingNamespace,
+			Labels: map[string]string{loggingNamespaceLabel: "enabled"},
+		},
+	}
+	if _, err := clientSet.CoreV1().Namespaces().Create(namespace); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "creating namespace %q", LoggingNamespace)
+		}
+		existing, err := clientSet.CoreV1().Namespaces().Get(LoggingNamespace, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if existing.Labels[loggingNamespaceLabel] != "enabled" {
+			if existing.Labels == nil {
+				existing.Labels = map[string]string{}
+			}
+			existing.Labels[loggingNamespaceLabel] = "enabled"
+			if _, err := clientSet.CoreV1().Namespaces().Update(existing); err != nil {
+				return errors.Wrapf(err, "labelling namespace %q", LoggingNamespace)
+			}
+		}
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      LoggingConfigMapName,
+			Namespace: LoggingNamespace,
+		},
+		Data: map[string]string{"output.conf": output},
+	}
+	configMaps := clientSet.CoreV1().ConfigMaps(LoggingNamespace)
+	if _, err := configMaps.Create(configMap); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "creating ConfigMap %s/%s", LoggingNamespace, LoggingConfigMapName)
+		}
+		if _, err := configMaps.Update(configMap); err != nil {
+			return errors.Wrapf(err, "updating ConfigMap %s/%s", LoggingNamespace, LoggingConfigMapName)
+		}
+	}
+	logger.Info("Fargate pods will ship their logs to %s, pods that are already running pick the config up once restarted", logging.Destination)
+	return nil
+}
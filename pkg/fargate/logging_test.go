@@ -0,0 +1,61 @@
+package fargate_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/fargate"
+)
+
+var _ = Describe("Fargate logging", func() {
+	It("renders the output of each destination", func() {
+		output, err := fargate.LoggingOutput(&api.FargateLogging{Destination: api.FargateLoggingCloudWatch}, "test-cluster", "us-west-2")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(Equal(`[OUTPUT]
+    Name cloudwatch_logs
+    Match *
+    region us-west-2
+    log_group_name /aws/eks/test-cluster/fargate
+    log_stream_prefix fargate-
+    auto_create_group true
+`))
+
+		output, err = fargate.LoggingOutput(&api.FargateLogging{Destination: api.FargateLoggingFirehose, DeliveryStream: "logs"}, "test-cluster", "us-west-2")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(ContainSubstring("Name kinesis_firehose"))
+		Expect(output).To(ContainSubstring("delivery_stream logs"))
+
+		output, err = fargate.LoggingOutput(&api.FargateLogging{
+			Destination: api.FargateLoggingOpenSearch,
+			DomainARN:   "arn:aws:es:us-west-2:123456789012:domain/logs",
+			Endpoint:    "https://search-logs-abc.us-west-2.es.amazonaws.com",
+		}, "test-cluster", "us-west-2")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(ContainSubstring("Host search-logs-abc.us-west-2.es.amazonaws.com"))
+		Expect(output).To(ContainSubstring("Index fargate"))
+	})
+
+	It("creates the namespace and updates an existing ConfigMap", func() {
+		clientSet := fake.NewSimpleClientset(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: fargate.LoggingNamespace}},
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: fargate.LoggingConfigMapName, Namespace: fargate.LoggingNamespace},
+				Data:       map[string]string{"output.conf": "[OUTPUT]\n    Name stdout\n"},
+			},
+		)
+		logging := &api.FargateLogging{Destination: api.FargateLoggingFirehose, DeliveryStream: "logs"}
+		Expect(fargate.ApplyLoggingConfig(clientSet, logging, "test-cluster", "us-west-2")).To(Succeed())
+
+		namespace, err := clientSet.CoreV1().Namespaces().Get(fargate.LoggingNamespace, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(namespace.Labels).To(HaveKeyWithValue("aws-observability", "enabled"))
+
+		configMap, err := clientSet.CoreV1().ConfigMaps(fargate.LoggingNamespace).Get(fargate.LoggingConfigMapName, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(configMap.Data["output.conf"]).To(ContainSubstring("delivery_stream logs"))
+	})
+})
@@ -30,6 +30,8 @@ type CreateOptions struct {
 	ProfileSelectorLabels map[string]string
 	// +optional
 	Tags map[string]string
+	// +optional
+	PodExecutionRoleARN string
 }
 
 // Validate validates this Options object's fields.
@@ -53,6 +55,7 @@ func (o CreateOptions) ToFargateProfile() *api.FargateProfile {
 				Labels:    o.ProfileSelectorLabels,
 			},
 		},
-		Tags: o.Tags,
+		Tags:                o.Tags,
+		PodExecutionRoleARN: o.PodExecutionRoleARN,
 	}
 }
@@ -0,0 +1,33 @@
+package fargate
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	kubeclient "k8s.io/client-go/kubernetes"
+)
+
+// ProfileLabel is the label EKS sets on pods running on Fargate to the name of the profile that
+// selected them
+const ProfileLabel = "eks.amazonaws.com/fargate-profile"
+
+// CountRunningPods returns the number of running pods of each Fargate profile, by profile name
+func CountRunningPods(clientSet kubeclient.Interface) (map[string]int, error) {
+	pods, err := clientSet.CoreV1().Pods(metav1.NamespaceAll).List(metav1.ListOptions{
+		LabelSelector: ProfileLabel,
+		FieldSelector: fields.OneTermEqualSelector("status.phase", string(corev1.PodRunning)).String(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing the pods running on Fargate")
+	}
+	counts := map[string]int{}
+	for _, pod := range pods.Items {
+		// the field selector isn't applied by every client, e.g. fake ones
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		counts[pod.Labels[ProfileLabel]]++
+	}
+	return counts, nil
+}
@@ -0,0 +1,37 @@
+package fargate_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/weaveworks/eksctl/pkg/fargate"
+)
+
+var _ = Describe("Fargate pods", func() {
+	It("counts the running pods of each profile", func() {
+		pod := func(name, profile string, phase corev1.PodPhase) *corev1.Pod {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Status:     corev1.PodStatus{Phase: phase},
+			}
+			if profile != "" {
+				pod.Labels = map[string]string{fargate.ProfileLabel: profile}
+			}
+			return pod
+		}
+		clientSet := fake.NewSimpleClientset(
+			pod("a", "fp-default", corev1.PodRunning),
+			pod("b", "fp-default", corev1.PodRunning),
+			pod("c", "fp-default", corev1.PodSucceeded),
+			pod("d", "fp-dev", corev1.PodPending),
+			pod("e", "", corev1.PodRunning),
+		)
+
+		counts, err := fargate.CountRunningPods(clientSet)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(counts).To(Equal(map[string]int{"fp-default": 2}))
+	})
+})
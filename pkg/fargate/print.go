@@ -2,6 +2,7 @@ package fargate
 
 import (
 	"io"
+	"strconv"
 	"strings"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
@@ -23,21 +24,52 @@ func PrintProfiles(profiles []*api.FargateProfile, writer io.Writer, printerType
 	switch printerType {
 	case printers.TableType:
 		addFargateProfileColumns(printer.(*printers.TablePrinter))
-		return printer.PrintObjWithKind(kindFargateProfiles, toTable(profiles), writer)
+		return printer.PrintObjWithKind(kindFargateProfiles, toTable(profiles, nil), writer)
 	default:
 		return printer.PrintObjWithKind(kindFargateProfiles, profiles, writer)
 	}
 }
 
+// ProfileWithPods is a Fargate profile along with the number of its running pods
+type ProfileWithPods struct {
+	*api.FargateProfile
+	RunningPods int `json:"runningPods"`
+}
+
+// PrintProfilesWithPodCounts prints the provided profiles like PrintProfiles, along with the
+// number of running pods of each of them
+func PrintProfilesWithPodCounts(profiles []*api.FargateProfile, runningPods map[string]int, writer io.Writer, printerType printers.Type) error {
+	printer, err := printers.NewPrinter(printerType)
+	if err != nil {
+		return err
+	}
+	switch printerType {
+	case printers.TableType:
+		tablePrinter := printer.(*printers.TablePrinter)
+		addFargateProfileColumns(tablePrinter)
+		tablePrinter.AddColumn("RUNNING_PODS", func(r *row) string {
+			return strconv.Itoa(r.RunningPods)
+		})
+		return printer.PrintObjWithKind(kindFargateProfiles, toTable(profiles, runningPods), writer)
+	default:
+		withPods := make([]*ProfileWithPods, len(profiles))
+		for i, profile := range profiles {
+			withPods[i] = &ProfileWithPods{FargateProfile: profile, RunningPods: runningPods[profile.Name]}
+		}
+		return printer.PrintObjWithKind(kindFargateProfiles, withPods, writer)
+	}
+}
+
 type row struct {
 	Name                string
 	PodExecutionRoleARN string
 	Subnets             []string
 	Selector            api.FargateProfileSelector
 	Tags                map[string]string
+	RunningPods         int
 }
 
-func toTable(profiles []*api.FargateProfile) []*row {
+func toTable(profiles []*api.FargateProfile, runningPods map[string]int) []*row {
 	table := []*row{}
 	for _, profile := range profiles {
 		for _, selector := range profile.Selectors {
@@ -47,6 +79,7 @@ func toTable(profiles []*api.FargateProfile) []*row {
 				Subnets:             profile.Subnets,
 				Selector:            selector,
 				Tags:                profile.Tags,
+				RunningPods:         runningPods[profile.Name],
 			})
 		}
 	}
@@ -2,6 +2,9 @@ package fargate_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"strings"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
@@ -43,6 +46,39 @@ var _ = Describe("fargate", func() {
 			Expect(err.Error()).To(Equal("unknown output printer type: expected {\"yaml\",\"json\",\"table\"} but got \"foo\""))
 		})
 	})
+
+	Describe("PrintProfilesWithPodCounts", func() {
+		runningPods := map[string]int{"fp-test": 3}
+
+		It("adds the running pods as a column of the table", func() {
+			out := bytes.NewBufferString("")
+			err := fargate.PrintProfilesWithPodCounts(sampleProfiles(), runningPods, out, printers.TableType)
+			Expect(err).To(Not(HaveOccurred()))
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			Expect(lines).To(HaveLen(4))
+			Expect(lines[0]).To(HaveSuffix("RUNNING_PODS"))
+			Expect(lines[1]).To(HavePrefix("fp-prod"))
+			Expect(lines[1]).To(HaveSuffix("0"))
+			Expect(lines[2]).To(HavePrefix("fp-test"))
+			Expect(lines[2]).To(HaveSuffix("3"))
+		})
+
+		It("adds the running pods to each profile of the JSON object", func() {
+			out := bytes.NewBufferString("")
+			err := fargate.PrintProfilesWithPodCounts(sampleProfiles(), runningPods, out, printers.JSONType)
+			Expect(err).To(Not(HaveOccurred()))
+
+			var printed []map[string]interface{}
+			Expect(json.Unmarshal(out.Bytes(), &printed)).To(Succeed())
+			Expect(printed).To(HaveLen(2))
+			Expect(printed[0]).To(HaveKeyWithValue("name", "fp-test"))
+			Expect(printed[0]).To(HaveKeyWithValue("runningPods", BeNumerically("==", 3)))
+			Expect(printed[0]).To(HaveKey("selectors"))
+			Expect(printed[1]).To(HaveKeyWithValue("name", "fp-prod"))
+			Expect(printed[1]).To(HaveKeyWithValue("runningPods", BeNumerically("==", 0)))
+			Expect(printed[1]).To(HaveKey("subnets"))
+		})
+	})
 })
 
 const expectedTable = `NAME	SELECTOR_NAMESPACE	SELECTOR_LABELS		POD_EXECUTION_ROLE_ARN		SUBNETS				TAGS
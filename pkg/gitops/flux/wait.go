@@ -9,10 +9,12 @@ import (
 	"time"
 
 	portforward "github.com/justinbarrick/go-k8s-portforward"
-	"github.com/kris-nova/logger"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubeclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/utils/retry"
 )
 
 type PublicKey struct {
@@ -91,31 +93,32 @@ func waitForPodToStart(namespace string, nameLabelValue string, port int, name s
 		DestinationPort: port,
 		Namespace:       namespace,
 	}
-	podDeadline := time.Now().Add(time.Second * 30)
-	for ; time.Now().Before(podDeadline); time.Sleep(2 * time.Second) {
+	poller := retry.Poller{Interval: 2 * time.Second}
+	err := poller.PollWithTimeout(30*time.Second, func() (bool, error) {
 		err := portforwarder.Start()
 		if err == nil {
-			defer portforwarder.Stop()
-			break
+			return true, nil
 		}
 		if !strings.Contains(err.Error(), "Could not find running pod for selector") {
 			logger.Warning("%s is not ready yet (%s), retrying ...", name, err)
 		}
-	}
-	if time.Now().After(podDeadline) {
+		return false, nil
+	})
+	if err != nil {
 		return fmt.Errorf("timed out waiting for %s's pod to be created", name)
 	}
+	defer portforwarder.Stop()
 	baseURL := fmt.Sprintf("http://127.0.0.1:%d/", portforwarder.ListenPort)
 	// Make sure it's alive
-	retryDeadline := time.Now().Add(30 * time.Second)
-	for ; time.Now().Before(retryDeadline); time.Sleep(2 * time.Second) {
+	err = poller.PollWithTimeout(30*time.Second, func() (bool, error) {
 		err := try(baseURL)
 		if err == nil {
-			break
+			return true, nil
 		}
 		logger.Warning("%s is not ready yet (%s), retrying ...", name, err)
-	}
-	if time.Now().After(retryDeadline) {
+		return false, nil
+	})
+	if err != nil {
 		return fmt.Errorf("timed out waiting for %s to be operative", name)
 	}
 	return nil
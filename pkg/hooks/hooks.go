@@ -0,0 +1,139 @@
+// Package hooks runs the commands and applies the manifests defined in the
+// hooks section of a ClusterConfig at lifecycle points of the cluster
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/utils/configsource"
+)
+
+// CheckConfigSource refuses the command hooks of a config file read from a remote source, which
+// would run code from elsewhere on this machine, unless the config file was verified with a
+// checksum or the hooks are allowed explicitly
+func CheckConfigSource(cfg *api.ClusterConfig, source string, verified, allowed bool) error {
+	if !configsource.IsRemote(source) || verified || allowed {
+		return nil
+	}
+	for _, point := range []string{api.HookPreCreate, api.HookPostCreate, api.HookPreDelete, api.HookPostDelete, api.HookPostNodeGroupCreate} {
+		for i, hook := range cfg.HooksFor(point) {
+			if len(hook.Command) > 0 {
+				return fmt.Errorf("hooks.%s[%d] runs a command from the remote config file %q, verify the config file with --config-file-checksum or pass --allow-hooks to run it", point, i, source)
+			}
+		}
+	}
+	return nil
+}
+
+// RawClientGetter returns a client to apply manifests with, it's only
+// called when a hook with a manifest is run
+type RawClientGetter func() (*kubernetes.RawClient, error)
+
+// Runner runs the hooks of a cluster
+type Runner struct {
+	cfg          *api.ClusterConfig
+	newRawClient RawClientGetter
+
+	stdout, stderr io.Writer
+}
+
+// NewRunner creates a runner for the hooks of cfg
+func NewRunner(cfg *api.ClusterConfig, newRawClient RawClientGetter) *Runner {
+	return &Runner{
+		cfg:          cfg,
+		newRawClient: newRawClient,
+		stdout:       os.Stdout,
+		stderr:       os.Stderr,
+	}
+}
+
+// Run runs the hooks of the given lifecycle point in order, stopping at the first
+// that fails; nodeGroupName is only set for postNodeGroupCreate hooks
+func (r *Runner) Run(point, nodeGroupName string) error {
+	hooks := r.cfg.HooksFor(point)
+	if len(hooks) == 0 {
+		return nil
+	}
+	logger.Info("running %d %s hook(s)", len(hooks), point)
+
+	for i, hook := range hooks {
+		var err error
+		if hook.Manifest != "" {
+			err = r.applyManifest(hook.Manifest)
+		} else {
+			err = r.runCommand(point, nodeGroupName, hook.Command)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "running hooks.%s[%d]", point, i)
+		}
+	}
+	return nil
+}
+
+func (r *Runner) runCommand(point, nodeGroupName string, command []string) error {
+	logger.Debug("running command %q", strings.Join(command, " "))
+
+	config, err := json.Marshal(r.cfg)
+	if err != nil {
+		return errors.Wrap(err, "serialising cluster config")
+	}
+
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Env = append(os.Environ(), r.env(point, nodeGroupName)...)
+	cmd.Stdin = bytes.NewReader(config)
+	cmd.Stdout = r.stdout
+	cmd.Stderr = r.stderr
+	return cmd.Run()
+}
+
+// env returns the cluster details passed to commands
+func (r *Runner) env(point, nodeGroupName string) []string {
+	meta := r.cfg.Metadata
+	env := []string{
+		"EKSCTL_HOOK=" + point,
+		"EKSCTL_CLUSTER_NAME=" + meta.Name,
+		"EKSCTL_REGION=" + meta.Region,
+	}
+	if meta.Version != "" {
+		env = append(env, "EKSCTL_CLUSTER_VERSION="+meta.Version)
+	}
+	if status := r.cfg.Status; status != nil {
+		if status.Endpoint != "" {
+			env = append(env, "EKSCTL_CLUSTER_ENDPOINT="+status.Endpoint)
+		}
+		if status.ARN != "" {
+			env = append(env, "EKSCTL_CLUSTER_ARN="+status.ARN)
+		}
+	}
+	if nodeGroupName != "" {
+		env = append(env, "EKSCTL_NODEGROUP_NAME="+nodeGroupName)
+	}
+	return env
+}
+
+func (r *Runner) applyManifest(path string) error {
+	manifest, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "reading manifest")
+	}
+	if r.newRawClient == nil {
+		return fmt.Errorf("cannot apply manifest %q without a cluster", path)
+	}
+	rawClient, err := r.newRawClient()
+	if err != nil {
+		return err
+	}
+	return rawClient.CreateOrReplace(manifest, false)
+}
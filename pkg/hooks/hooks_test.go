@@ -0,0 +1,98 @@
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+var _ = Describe("hooks", func() {
+	var (
+		cfg    *api.ClusterConfig
+		runner *Runner
+		stdout *bytes.Buffer
+		dir    string
+	)
+
+	BeforeEach(func() {
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "cluster-1"
+		cfg.Metadata.Region = "eu-north-1"
+		cfg.Status = &api.ClusterStatus{Endpoint: "https://cluster-1.eks.amazonaws.com"}
+
+		stdout = &bytes.Buffer{}
+		runner = NewRunner(cfg, nil)
+		runner.stdout = stdout
+
+		var err error
+		dir, err = ioutil.TempDir("", "hooks")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("passes the cluster details to commands", func() {
+		cfg.Hooks = &api.ClusterHooks{
+			PostNodeGroupCreate: []api.Hook{
+				{Command: []string{"sh", "-c", "echo $EKSCTL_HOOK $EKSCTL_CLUSTER_NAME $EKSCTL_REGION $EKSCTL_CLUSTER_ENDPOINT $EKSCTL_NODEGROUP_NAME"}},
+				{Command: []string{"cat"}},
+			},
+		}
+
+		Expect(runner.Run(api.HookPostNodeGroupCreate, "ng-1")).To(Succeed())
+
+		lines := bytes.SplitN(stdout.Bytes(), []byte("\n"), 2)
+		Expect(string(lines[0])).To(Equal("postNodeGroupCreate cluster-1 eu-north-1 https://cluster-1.eks.amazonaws.com ng-1"))
+
+		var received api.ClusterConfig
+		Expect(json.Unmarshal(lines[1], &received)).To(Succeed())
+		Expect(received.Metadata.Name).To(Equal("cluster-1"))
+	})
+
+	It("stops at the first failing hook", func() {
+		marker := filepath.Join(dir, "marker")
+		cfg.Hooks = &api.ClusterHooks{
+			PreDelete: []api.Hook{
+				{Command: []string{"false"}},
+				{Command: []string{"touch", marker}},
+			},
+		}
+
+		err := runner.Run(api.HookPreDelete, "")
+		Expect(err).To(MatchError(ContainSubstring("running hooks.preDelete[0]")))
+		Expect(marker).NotTo(BeAnExistingFile())
+	})
+
+	It("does nothing without hooks", func() {
+		Expect(runner.Run(api.HookPreCreate, "")).To(Succeed())
+		Expect(stdout.Len()).To(BeZero())
+	})
+
+	It("needs a cluster to apply manifests", func() {
+		manifest := filepath.Join(dir, "manifest.yaml")
+		Expect(ioutil.WriteFile(manifest, []byte("kind: Namespace"), 0600)).To(Succeed())
+		cfg.Hooks = &api.ClusterHooks{PostCreate: []api.Hook{{Manifest: manifest}}}
+
+		Expect(runner.Run(api.HookPostCreate, "")).To(MatchError(ContainSubstring("without a cluster")))
+	})
+
+	It("refuses command hooks of remote config files unless verified or allowed", func() {
+		cfg.Hooks = &api.ClusterHooks{PostCreate: []api.Hook{{Manifest: "manifest.yaml"}}}
+		Expect(CheckConfigSource(cfg, "https://example.com/cluster.yaml", false, false)).To(Succeed())
+
+		cfg.Hooks.PostDelete = []api.Hook{{Command: []string{"true"}}}
+		Expect(CheckConfigSource(cfg, "cluster.yaml", false, false)).To(Succeed())
+		Expect(CheckConfigSource(cfg, "https://example.com/cluster.yaml", false, false)).To(MatchError(ContainSubstring(`hooks.postDelete[0] runs a command from the remote config file "https://example.com/cluster.yaml"`)))
+		Expect(CheckConfigSource(cfg, "oci://example.com/cluster:v1", true, false)).To(Succeed())
+		Expect(CheckConfigSource(cfg, "s3://bucket/cluster.yaml", false, true)).To(Succeed())
+	})
+})
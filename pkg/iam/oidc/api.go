@@ -1,23 +1,33 @@
 package iamoidc
 
 import (
+	"bytes"
 	"crypto/sha1"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"path"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	awsiam "github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 	"github.com/pkg/errors"
 
 	cft "github.com/weaveworks/eksctl/pkg/cfn/template"
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 const defaultAudience = "sts.amazonaws.com"
 
+// maxThumbprints is the maximum number of thumbprints of an IAM OIDC provider
+const maxThumbprints = 5
+
 // OpenIDConnectManager hold information about IAM OIDC integration
 type OpenIDConnectManager struct {
 	accountID string
@@ -27,8 +37,20 @@ type OpenIDConnectManager struct {
 	issuerURL          *url.URL
 	insecureSkipVerify bool
 	issuerCAThumbprint string
+	// issuerCAThumbprints starts with issuerCAThumbprint, followed by the
+	// thumbprints of the other CAs of the issuer's certificate chain
+	issuerCAThumbprints []string
+	providerThumbprints []string
 
 	ProviderARN string
+	// CABundle holds PEM certificates trusted in addition to the system roots when
+	// connecting to the issuer, e.g. the CA of a TLS-intercepting proxy
+	CABundle []byte
+	// Tags are added to the providers CreateProvider creates, they tell them apart from
+	// providers created by other tools, which are reused and not deleted
+	Tags map[string]string
+	// ResourceTags, e.g. metadata.tags, are added along with Tags, but don't tell providers apart
+	ResourceTags map[string]string
 
 	iam iamiface.IAMAPI
 }
@@ -59,6 +81,38 @@ func NewOpenIDConnectManager(iamapi iamiface.IAMAPI, accountID, issuer, partitio
 	return m, nil
 }
 
+// FindTaggedProvider returns a manager of the provider that has all of the given tags, e.g. the provider
+// of a cluster whose issuer can't be described anymore, or nil when there's no such provider
+func FindTaggedProvider(iamapi iamiface.IAMAPI, tags map[string]string) (*OpenIDConnectManager, error) {
+	output, err := iamapi.ListOpenIDConnectProviders(&awsiam.ListOpenIDConnectProvidersInput{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing OIDC providers")
+	}
+	for _, provider := range output.OpenIDConnectProviderList {
+		providerARN := aws.StringValue(provider.Arn)
+		providerTags, err := (&OpenIDConnectManager{iam: iamapi, ProviderARN: providerARN}).listProviderTags()
+		if err != nil {
+			return nil, err
+		}
+		if !hasTags(providerTags, tags) {
+			continue
+		}
+		parsed, err := arn.Parse(providerARN)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing OIDC provider ARN %q", providerARN)
+		}
+		issuer := "https://" + strings.TrimPrefix(parsed.Resource, "oidc-provider/")
+		m, err := NewOpenIDConnectManager(iamapi, parsed.AccountID, issuer, parsed.Partition)
+		if err != nil {
+			return nil, err
+		}
+		m.ProviderARN = providerARN
+		m.Tags = tags
+		return m, nil
+	}
+	return nil, nil
+}
+
 // CheckProviderExists will return true when the provider exists, it may return errors
 // if it was unable to call IAM API
 func (m *OpenIDConnectManager) CheckProviderExists() (bool, error) {
@@ -67,7 +121,7 @@ func (m *OpenIDConnectManager) CheckProviderExists() (bool, error) {
 			fmt.Sprintf("arn:%s:iam::%s:oidc-provider/%s", m.partition, m.accountID, m.hostnameAndPath()),
 		),
 	}
-	_, err := m.iam.GetOpenIDConnectProvider(input)
+	output, err := m.iam.GetOpenIDConnectProvider(input)
 	if err != nil {
 		awsError := err.(awserr.Error)
 		if awsError.Code() == awsiam.ErrCodeNoSuchEntityException {
@@ -76,18 +130,21 @@ func (m *OpenIDConnectManager) CheckProviderExists() (bool, error) {
 		return false, err
 	}
 	m.ProviderARN = *input.OpenIDConnectProviderArn
+	m.providerThumbprints = aws.StringValueSlice(output.ThumbprintList)
 	return true, nil
 }
 
 // CreateProvider will retrieve CA root certificate and compute its thumbprint for the
-// by connecting to it and create the provider using IAM API
+// by connecting to it and create the provider using IAM API; the thumbprints of the other
+// CAs of the certificate chain are added too, so that the provider keeps working when the
+// issuer's chain changes
 func (m *OpenIDConnectManager) CreateProvider() error {
 	if err := m.getIssuerCAThumbprint(); err != nil {
 		return err
 	}
 	input := &awsiam.CreateOpenIDConnectProviderInput{
 		ClientIDList:   aws.StringSlice([]string{m.audience}),
-		ThumbprintList: []*string{&m.issuerCAThumbprint},
+		ThumbprintList: aws.StringSlice(m.issuerCAThumbprints),
 		// It has no name or tags, it's keyed to the URL
 		Url: aws.String(m.issuerURL.String()),
 	}
@@ -96,9 +153,114 @@ func (m *OpenIDConnectManager) CreateProvider() error {
 		return errors.Wrap(err, "creating OIDC provider")
 	}
 	m.ProviderARN = *output.OpenIDConnectProviderArn
+	tags := map[string]string{}
+	for key, value := range m.ResourceTags {
+		tags[key] = value
+	}
+	for key, value := range m.Tags {
+		tags[key] = value
+	}
+	if len(tags) > 0 {
+		if err := m.tagProvider(tags); err != nil {
+			logger.Warning("unable to tag IAM OIDC provider %q, it won't be told apart from providers created by other tools: %s", m.ProviderARN, err.Error())
+		}
+	}
+	return nil
+}
+
+// EnsureProvider reuses the provider of the issuer if it exists, e.g. when another tool created
+// it, and creates it otherwise
+func (m *OpenIDConnectManager) EnsureProvider() error {
+	exists, err := m.CheckProviderExists()
+	if err != nil {
+		return err
+	}
+	if !exists {
+		err := m.CreateProvider()
+		if err == nil {
+			return nil
+		}
+		// the provider was created since it was checked
+		if awsErr, ok := errors.Cause(err).(awserr.Error); !ok || awsErr.Code() != awsiam.ErrCodeEntityAlreadyExistsException {
+			return err
+		}
+		if exists, err = m.CheckProviderExists(); err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("IAM OIDC provider of issuer %q was created and deleted concurrently", m.issuerURL.String())
+		}
+	}
+	logger.Info("reusing existing IAM OIDC provider %q", m.ProviderARN)
+	return nil
+}
+
+// ProviderThumbprints returns the thumbprints of the provider found by CheckProviderExists
+func (m *OpenIDConnectManager) ProviderThumbprints() []string {
+	return m.providerThumbprints
+}
+
+// IssuerCAThumbprints connects to the issuer and returns the thumbprints of its CAs,
+// which UpdateThumbprints sets on the provider
+func (m *OpenIDConnectManager) IssuerCAThumbprints() ([]string, error) {
+	if err := m.getIssuerCAThumbprint(); err != nil {
+		return nil, err
+	}
+	return m.issuerCAThumbprints, nil
+}
+
+// UpdateThumbprints replaces the thumbprints of the provider found by CheckProviderExists
+// with the thumbprints of the issuer's CAs
+func (m *OpenIDConnectManager) UpdateThumbprints() error {
+	if m.issuerCAThumbprints == nil {
+		if err := m.getIssuerCAThumbprint(); err != nil {
+			return err
+		}
+	}
+	input := &awsiam.UpdateOpenIDConnectProviderThumbprintInput{
+		OpenIDConnectProviderArn: &m.ProviderARN,
+		ThumbprintList:           aws.StringSlice(m.issuerCAThumbprints),
+	}
+	if _, err := m.iam.UpdateOpenIDConnectProviderThumbprint(input); err != nil {
+		return errors.Wrap(err, "updating thumbprints of OIDC provider")
+	}
+	m.providerThumbprints = m.issuerCAThumbprints
 	return nil
 }
 
+// IsProviderOwned returns true if the provider found by CheckProviderExists has the tags
+// CreateProvider adds, or has no tags at all, as eksctl didn't tag the providers it created
+// before; the providers of other tools, which have other tags, aren't owned
+func (m *OpenIDConnectManager) IsProviderOwned() (bool, error) {
+	tags, err := m.listProviderTags()
+	if err != nil {
+		return false, err
+	}
+	if len(tags) == 0 {
+		return true, nil
+	}
+	return len(m.Tags) > 0 && hasTags(tags, m.Tags), nil
+}
+
+func hasTags(tags, wanted map[string]string) bool {
+	for key, value := range wanted {
+		if actual, ok := tags[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+// ProviderTags returns the tags of the provider found by CheckProviderExists
+func (m *OpenIDConnectManager) ProviderTags() (map[string]string, error) {
+	return m.listProviderTags()
+}
+
+// TagProvider adds the given tags to the provider found by CheckProviderExists
+func (m *OpenIDConnectManager) TagProvider(tags map[string]string) error {
+	return m.tagProvider(tags)
+}
+
 // DeleteProvider will delete the provider using IAM API, it may return an error
 // the API call fails
 func (m *OpenIDConnectManager) DeleteProvider() error {
@@ -119,12 +281,26 @@ func (m *OpenIDConnectManager) DeleteProvider() error {
 // getIssuerCAThumbprint obtains thumbprint of root CA by connecting to the
 // OIDC issuer and parsing certificates
 func (m *OpenIDConnectManager) getIssuerCAThumbprint() error {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: m.insecureSkipVerify,
+	}
+	var bundle *x509.CertPool
+	if len(m.CABundle) > 0 {
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+		bundle = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(m.CABundle) || !bundle.AppendCertsFromPEM(m.CABundle) {
+			return fmt.Errorf("no certificates found in CA bundle")
+		}
+		tlsConfig.RootCAs = roots
+	}
+
 	client := &http.Client{
 		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: m.insecureSkipVerify,
-			},
-			Proxy: http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+			Proxy:           http.ProxyFromEnvironment,
 		},
 	}
 
@@ -132,17 +308,66 @@ func (m *OpenIDConnectManager) getIssuerCAThumbprint() error {
 	if err != nil {
 		return errors.Wrap(err, "connecting to issuer OIDC")
 	}
+	defer response.Body.Close()
 
 	if response.TLS != nil {
 		if numCerts := len(response.TLS.PeerCertificates); numCerts >= 1 {
+			if bundle != nil && issuedByBundle(response.TLS, bundle) {
+				logger.Warning("the certificate of the OIDC issuer %q was issued by a CA of the CA bundle, "+
+					"e.g. by a TLS-intercepting proxy, so the thumbprint of the OIDC provider may not match the issuer", m.issuerURL.Hostname())
+			}
 			root := response.TLS.PeerCertificates[numCerts-1]
-			m.issuerCAThumbprint = fmt.Sprintf("%x", sha1.Sum(root.Raw))
+			m.issuerCAThumbprint = thumbprint(root)
+			m.issuerCAThumbprints = caThumbprints(root, response.TLS)
 			return nil
 		}
 	}
 	return fmt.Errorf("unable to get OIDC issuer's certificate")
 }
 
+func thumbprint(cert *x509.Certificate) string {
+	return fmt.Sprintf("%x", sha1.Sum(cert.Raw))
+}
+
+// caThumbprints returns the thumbprint of the root, followed by the thumbprints of the
+// roots of the verified chains and of the intermediate CAs the issuer presented
+func caThumbprints(root *x509.Certificate, state *tls.ConnectionState) []string {
+	thumbprints := []string{thumbprint(root)}
+	add := func(cert *x509.Certificate) {
+		if !cert.IsCA || len(thumbprints) == maxThumbprints {
+			return
+		}
+		value := thumbprint(cert)
+		for _, existing := range thumbprints {
+			if existing == value {
+				return
+			}
+		}
+		thumbprints = append(thumbprints, value)
+	}
+	for _, chain := range state.VerifiedChains {
+		add(chain[len(chain)-1])
+	}
+	for _, cert := range state.PeerCertificates[1:] {
+		add(cert)
+	}
+	return thumbprints
+}
+
+// issuedByBundle returns true if the root of the verified chain of the
+// connection is one of the certificates of the bundle
+func issuedByBundle(state *tls.ConnectionState, bundle *x509.CertPool) bool {
+	for _, chain := range state.VerifiedChains {
+		root := chain[len(chain)-1]
+		for _, subject := range bundle.Subjects() {
+			if bytes.Equal(root.RawSubject, subject) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // MakeAssumeRolePolicyDocument constructs a trust policy document for the given
 // provider
 func (m *OpenIDConnectManager) MakeAssumeRolePolicyDocument(serviceAccountNamespace, serviceAccountName string) cft.MapOfInterfaces {
@@ -158,3 +383,119 @@ func (m *OpenIDConnectManager) MakeAssumeRolePolicyDocument(serviceAccountNamesp
 func (m *OpenIDConnectManager) hostnameAndPath() string {
 	return m.issuerURL.Hostname() + m.issuerURL.Path
 }
+
+// policyStrings is a policy element that holds either a string or a list of strings
+type policyStrings []string
+
+func (s *policyStrings) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*s = policyStrings{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*s = list
+	return nil
+}
+
+func (s policyStrings) contains(value string) bool {
+	for _, v := range s {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+type trustPolicyStatement struct {
+	Effect    string
+	Action    policyStrings
+	Principal struct {
+		Federated policyStrings
+	}
+	Condition map[string]map[string]policyStrings
+}
+
+// CheckRoleTrust checks that the trust policy of an existing role allows the given
+// serviceaccount to assume it through the provider, it returns the problems it found
+func (m *OpenIDConnectManager) CheckRoleTrust(roleARN, serviceAccountNamespace, serviceAccountName string) ([]string, error) {
+	parsedARN, err := arn.Parse(roleARN)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing role ARN %q", roleARN)
+	}
+	// the resource is role/[path/]name
+	roleName := parsedARN.Resource[strings.LastIndex(parsedARN.Resource, "/")+1:]
+
+	output, err := m.iam.GetRole(&awsiam.GetRoleInput{RoleName: &roleName})
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting IAM role %q", roleARN)
+	}
+	document, err := url.QueryUnescape(aws.StringValue(output.Role.AssumeRolePolicyDocument))
+	if err != nil {
+		return nil, errors.Wrapf(err, "decoding the trust policy of IAM role %q", roleARN)
+	}
+	var policy struct {
+		Statement json.RawMessage
+	}
+	if err := json.Unmarshal([]byte(document), &policy); err != nil {
+		return nil, errors.Wrapf(err, "parsing the trust policy of IAM role %q", roleARN)
+	}
+	var statements []trustPolicyStatement
+	if err := json.Unmarshal(policy.Statement, &statements); err != nil {
+		var statement trustPolicyStatement
+		if err := json.Unmarshal(policy.Statement, &statement); err != nil {
+			return nil, errors.Wrapf(err, "parsing the trust policy of IAM role %q", roleARN)
+		}
+		statements = []trustPolicyStatement{statement}
+	}
+
+	subject := fmt.Sprintf("system:serviceaccount:%s:%s", serviceAccountNamespace, serviceAccountName)
+	subjectKey := m.hostnameAndPath() + ":sub"
+	audienceKey := m.hostnameAndPath() + ":aud"
+
+	var problems []string
+	for _, statement := range statements {
+		if statement.Effect != "Allow" || !statement.Action.contains("sts:AssumeRoleWithWebIdentity") || !statement.Principal.Federated.contains(m.ProviderARN) {
+			continue
+		}
+		problems = nil
+		if values := conditionValues(statement.Condition, audienceKey); values != nil && !matchesAny(values, m.audience) {
+			problems = append(problems, fmt.Sprintf("the trust policy of IAM role %q restricts the audience %q to %v, it should be %q", roleARN, audienceKey, values, m.audience))
+		}
+		if values := conditionValues(statement.Condition, subjectKey); values == nil {
+			problems = append(problems, fmt.Sprintf("the trust policy of IAM role %q has no condition on %q, any serviceaccount of the cluster can assume it", roleARN, subjectKey))
+		} else if !matchesAny(values, subject) {
+			problems = append(problems, fmt.Sprintf("the trust policy of IAM role %q restricts the subject %q to %v, it doesn't allow %q", roleARN, subjectKey, values, subject))
+		}
+		if len(problems) == 0 {
+			return nil, nil
+		}
+	}
+	if problems == nil {
+		problems = []string{fmt.Sprintf("the trust policy of IAM role %q doesn't allow sts:AssumeRoleWithWebIdentity with IAM OIDC provider %q", roleARN, m.ProviderARN)}
+	}
+	return problems, nil
+}
+
+// conditionValues returns the values a StringEquals or StringLike condition of the
+// statement allows for the key, or nil if there's no such condition
+func conditionValues(conditions map[string]map[string]policyStrings, key string) policyStrings {
+	var values policyStrings
+	for _, operator := range []string{"StringEquals", "StringLike"} {
+		values = append(values, conditions[operator][key]...)
+	}
+	return values
+}
+
+func matchesAny(patterns policyStrings, value string) bool {
+	for _, pattern := range patterns {
+		// StringLike allows * and ?, which path.Match treats the same way for values without /
+		if matched, err := path.Match(pattern, value); pattern == value || (err == nil && matched) {
+			return true
+		}
+	}
+	return false
+}
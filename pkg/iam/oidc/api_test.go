@@ -1,13 +1,19 @@
 package iamoidc
 
 import (
+	"crypto/sha1"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
 	awsiam "github.com/aws/aws-sdk-go/service/iam"
 	"github.com/stretchr/testify/mock"
 
@@ -107,6 +113,29 @@ var _ = Describe("EKS/IAM API wrapper", func() {
 
 			Expect(srv.close()).To(Succeed())
 		})
+
+		It("should trust the CA bundle when getting OIDC issuer's CA fingerprint", func() {
+			srv := httptest.NewTLSServer(http.NotFoundHandler())
+			defer srv.Close()
+
+			oidc, err := NewOpenIDConnectManager(p.IAM(), "12345", srv.URL+"/", "aws")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = oidc.getIssuerCAThumbprint()
+			Expect(err).To(MatchError(ContainSubstring("certificate signed by unknown authority")))
+
+			oidc.CABundle = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+			Expect(oidc.getIssuerCAThumbprint()).To(Succeed())
+			Expect(oidc.issuerCAThumbprint).To(Equal(fmt.Sprintf("%x", sha1.Sum(srv.Certificate().Raw))))
+		})
+
+		It("should reject an empty CA bundle", func() {
+			oidc, err := NewOpenIDConnectManager(p.IAM(), "12345", exampleIssuer, "aws")
+			Expect(err).NotTo(HaveOccurred())
+
+			oidc.CABundle = []byte("not a certificate")
+			Expect(oidc.getIssuerCAThumbprint()).To(MatchError("no certificates found in CA bundle"))
+		})
 	})
 
 	Describe("create/get/delete tests", func() {
@@ -200,6 +229,26 @@ var _ = Describe("EKS/IAM API wrapper", func() {
 			Expect(srv.close()).To(Succeed())
 		})
 
+		It("updates the thumbprints of the existing OIDC provider", func() {
+			Expect(oidc.ProviderThumbprints()).To(BeEmpty())
+			Expect(oidc.issuerCAThumbprints).To(HaveLen(1))
+			Expect(oidc.issuerCAThumbprints[0]).To(Equal(oidc.issuerCAThumbprint))
+
+			p.MockIAM().On("UpdateOpenIDConnectProviderThumbprint", &awsiam.UpdateOpenIDConnectProviderThumbprintInput{
+				OpenIDConnectProviderArn: aws.String(fakeProviderARN),
+				ThumbprintList:           aws.StringSlice([]string{"8b453cc675feb77c65163b7a9907d77994386664"}),
+			}).Return(&awsiam.UpdateOpenIDConnectProviderThumbprintOutput{}, nil)
+
+			Expect(oidc.UpdateThumbprints()).To(Succeed())
+			Expect(oidc.ProviderThumbprints()).To(Equal([]string{"8b453cc675feb77c65163b7a9907d77994386664"}))
+		})
+
+		It("reuses the existing OIDC provider", func() {
+			Expect(oidc.EnsureProvider()).To(Succeed())
+			Expect(oidc.ProviderARN).To(Equal(fakeProviderARN))
+			p.MockIAM().AssertNumberOfCalls(GinkgoT(), "CreateOpenIDConnectProvider", 1)
+		})
+
 		It("delete existing OIDC provider and check it no longer exists", func() {
 			err = oidc.DeleteProvider()
 			Expect(err).NotTo(HaveOccurred())
@@ -243,6 +292,133 @@ var _ = Describe("EKS/IAM API wrapper", func() {
 
 	})
 
+	Describe("OIDC provider tags", func() {
+		var (
+			srv        *httptest.Server
+			iamAPI     *awsiam.IAM
+			oidc       *OpenIDConnectManager
+			tags       string
+			taggedWith url.Values
+		)
+
+		BeforeEach(func() {
+			tags = ""
+			taggedWith = nil
+			srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.ParseForm()).To(Succeed())
+				action := r.Form.Get("Action")
+				if action == "ListOpenIDConnectProviders" {
+					fmt.Fprintf(w, "<%[1]sResponse><%[1]sResult><OpenIDConnectProviderList><member><Arn>%s</Arn></member></OpenIDConnectProviderList></%[1]sResult></%[1]sResponse>", action, fakeProviderARN)
+					return
+				}
+				Expect(r.Form.Get("OpenIDConnectProviderArn")).To(Equal(fakeProviderARN))
+				if action == "TagOpenIDConnectProvider" {
+					taggedWith = r.Form
+				}
+				fmt.Fprintf(w, "<%[1]sResponse><%[1]sResult><IsTruncated>false</IsTruncated><Tags>%s</Tags></%[1]sResult></%[1]sResponse>", action, tags)
+			}))
+
+			iamAPI = awsiam.New(session.Must(session.NewSession(&aws.Config{
+				Region:      aws.String("us-west-2"),
+				Endpoint:    aws.String(srv.URL),
+				Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+			})))
+			var err error
+			oidc, err = NewOpenIDConnectManager(iamAPI, "12345", exampleIssuer, "aws")
+			Expect(err).NotTo(HaveOccurred())
+			oidc.ProviderARN = fakeProviderARN
+			oidc.Tags = map[string]string{"alpha.eksctl.io/cluster-name": "test"}
+		})
+
+		AfterEach(func() {
+			srv.Close()
+		})
+
+		It("tags the provider", func() {
+			Expect(oidc.tagProvider(oidc.Tags)).To(Succeed())
+			Expect(taggedWith.Get("Tags.member.1.Key")).To(Equal("alpha.eksctl.io/cluster-name"))
+			Expect(taggedWith.Get("Tags.member.1.Value")).To(Equal("test"))
+		})
+
+		DescribeTable("tells whether eksctl created the provider",
+			func(providerTags string, owned bool) {
+				tags = providerTags
+				Expect(oidc.IsProviderOwned()).To(Equal(owned))
+			},
+			Entry("untagged", "", true),
+			Entry("tagged by eksctl", "<member><Key>alpha.eksctl.io/cluster-name</Key><Value>test</Value></member>", true),
+			Entry("tagged for another cluster", "<member><Key>alpha.eksctl.io/cluster-name</Key><Value>other</Value></member>", false),
+			Entry("tagged by another tool", "<member><Key>terraform</Key><Value>true</Value></member>", false),
+		)
+
+		It("finds the provider of a cluster by its tags", func() {
+			tags = "<member><Key>alpha.eksctl.io/cluster-name</Key><Value>test</Value></member>"
+			found, err := FindTaggedProvider(iamAPI, oidc.Tags)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).NotTo(BeNil())
+			Expect(found.Tags).To(Equal(oidc.Tags))
+			Expect(found.hostnameAndPath()).To(Equal("localhost/"))
+
+			found, err = FindTaggedProvider(iamAPI, map[string]string{"alpha.eksctl.io/cluster-name": "other"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeNil())
+		})
+	})
+
+	Describe("trust policy of existing roles", func() {
+		const (
+			roleARN  = "arn:aws:iam::12345:role/path/existing-role"
+			issuerID = "exampleIssuer.eksctl.io/id/13EBFE0C5BD60778E91DFE559E02689C"
+		)
+
+		var (
+			p    *mockprovider.MockProvider
+			oidc *OpenIDConnectManager
+		)
+
+		BeforeEach(func() {
+			p = mockprovider.NewMockProvider()
+			var err error
+			oidc, err = NewOpenIDConnectManager(p.IAM(), "12345", exampleIssuer, "aws")
+			Expect(err).NotTo(HaveOccurred())
+			oidc.ProviderARN = fakeProviderARN
+		})
+
+		DescribeTable("checks the trust policy allows the serviceaccount",
+			func(conditions string, expectedProblems int) {
+				document := fmt.Sprintf(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Federated":%q},"Action":"sts:AssumeRoleWithWebIdentity","Condition":%s}]}`, fakeProviderARN, conditions)
+				p.MockIAM().On("GetRole", &awsiam.GetRoleInput{RoleName: aws.String("existing-role")}).Return(&awsiam.GetRoleOutput{
+					Role: &awsiam.Role{
+						Arn:                      aws.String(roleARN),
+						AssumeRolePolicyDocument: aws.String(url.QueryEscape(document)),
+					},
+				}, nil)
+
+				problems, err := oidc.CheckRoleTrust(roleARN, "ns-1", "sa-1")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(problems).To(HaveLen(expectedProblems))
+			},
+			Entry("matching subject and audience", fmt.Sprintf(`{"StringEquals":{%q:"system:serviceaccount:ns-1:sa-1",%q:"sts.amazonaws.com"}}`, issuerID+":sub", issuerID+":aud"), 0),
+			Entry("subject matching a pattern", fmt.Sprintf(`{"StringLike":{%q:"system:serviceaccount:ns-1:*"}}`, issuerID+":sub"), 0),
+			Entry("another subject", fmt.Sprintf(`{"StringEquals":{%q:"system:serviceaccount:ns-1:sa-2"}}`, issuerID+":sub"), 1),
+			Entry("another audience", fmt.Sprintf(`{"StringEquals":{%q:"system:serviceaccount:ns-1:sa-1",%q:"example.com"}}`, issuerID+":sub", issuerID+":aud"), 1),
+			Entry("no subject condition", `{}`, 1),
+		)
+
+		It("reports a trust policy for another provider", func() {
+			p.MockIAM().On("GetRole", mock.Anything).Return(&awsiam.GetRoleOutput{
+				Role: &awsiam.Role{
+					Arn:                      aws.String(roleARN),
+					AssumeRolePolicyDocument: aws.String(url.QueryEscape(`{"Version":"2012-10-17","Statement":{"Effect":"Allow","Principal":{"Service":"ec2.amazonaws.com"},"Action":"sts:AssumeRole"}}`)),
+				},
+			}, nil)
+
+			problems, err := oidc.CheckRoleTrust(roleARN, "ns-1", "sa-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(problems).To(ConsistOf(ContainSubstring("doesn't allow sts:AssumeRoleWithWebIdentity")))
+		})
+	})
+
 	Describe("OIDC AWS partition test", func() {
 		var (
 			provider *mockprovider.MockProvider
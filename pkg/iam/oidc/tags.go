@@ -0,0 +1,99 @@
+package iamoidc
+
+import (
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	awsiam "github.com/aws/aws-sdk-go/service/iam"
+	"github.com/pkg/errors"
+)
+
+// the version of aws-sdk-go doesn't have the operations on the tags of OIDC providers yet
+// TODO use aws-sdk-go after upgrading it
+
+type tagOpenIDConnectProviderInput struct {
+	_ struct{} `type:"structure"`
+
+	OpenIDConnectProviderArn *string       `type:"string" required:"true"`
+	Tags                     []*awsiam.Tag `type:"list" required:"true"`
+}
+
+type tagOpenIDConnectProviderOutput struct {
+	_ struct{} `type:"structure"`
+}
+
+type listOpenIDConnectProviderTagsInput struct {
+	_ struct{} `type:"structure"`
+
+	OpenIDConnectProviderArn *string `type:"string" required:"true"`
+	Marker                   *string `type:"string"`
+}
+
+type listOpenIDConnectProviderTagsOutput struct {
+	_ struct{} `type:"structure"`
+
+	IsTruncated *bool         `type:"boolean"`
+	Marker      *string       `type:"string"`
+	Tags        []*awsiam.Tag `type:"list"`
+}
+
+func (m *OpenIDConnectManager) iamClient() (*awsiam.IAM, error) {
+	client, ok := m.iam.(*awsiam.IAM)
+	if !ok {
+		return nil, errors.New("the IAM client doesn't support raw API requests")
+	}
+	return client, nil
+}
+
+func (m *OpenIDConnectManager) tagProvider(tags map[string]string) error {
+	client, err := m.iamClient()
+	if err != nil {
+		return err
+	}
+	input := &tagOpenIDConnectProviderInput{OpenIDConnectProviderArn: &m.ProviderARN}
+	var keys []string
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		input.Tags = append(input.Tags, &awsiam.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+	op := &request.Operation{
+		Name:       "TagOpenIDConnectProvider",
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}
+	if err := client.NewRequest(op, input, &tagOpenIDConnectProviderOutput{}).Send(); err != nil {
+		return errors.Wrapf(err, "tagging OIDC provider %q", m.ProviderARN)
+	}
+	return nil
+}
+
+func (m *OpenIDConnectManager) listProviderTags() (map[string]string, error) {
+	client, err := m.iamClient()
+	if err != nil {
+		return nil, err
+	}
+	op := &request.Operation{
+		Name:       "ListOpenIDConnectProviderTags",
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}
+	tags := map[string]string{}
+	input := &listOpenIDConnectProviderTagsInput{OpenIDConnectProviderArn: &m.ProviderARN}
+	for {
+		output := &listOpenIDConnectProviderTagsOutput{}
+		if err := client.NewRequest(op, input, output).Send(); err != nil {
+			return nil, errors.Wrapf(err, "listing tags of OIDC provider %q", m.ProviderARN)
+		}
+		for _, tag := range output.Tags {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+		if !aws.BoolValue(output.IsTruncated) {
+			return tags, nil
+		}
+		input.Marker = output.Marker
+	}
+}
@@ -0,0 +1,430 @@
+package iam
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// Operations the required policy is generated for
+const (
+	OperationCreate = "create"
+	OperationDelete = "delete"
+)
+
+// SupportedOperations returns the operations a required policy can be generated for
+func SupportedOperations() []string {
+	return []string{OperationCreate, OperationDelete}
+}
+
+// PolicyDocument is an IAM policy document
+type PolicyDocument struct {
+	Version   string            `json:"Version"`
+	Statement []PolicyStatement `json:"Statement"`
+}
+
+// PolicyStatement is a statement of an IAM policy document
+type PolicyStatement struct {
+	Sid       string                       `json:"Sid"`
+	Effect    string                       `json:"Effect"`
+	Action    []string                     `json:"Action"`
+	Resource  []string                     `json:"Resource"`
+	Condition map[string]map[string]string `json:"Condition,omitempty"`
+}
+
+// requiredPolicyBuilder collects the statements of the policy required to run the operations
+// for a cluster, resources are scoped to the cluster by the names eksctl gives them
+type requiredPolicyBuilder struct {
+	cfg        *api.ClusterConfig
+	partition  string
+	accountID  string
+	forCreate  bool
+	forDelete  bool
+	statements []PolicyStatement
+}
+
+// RequiredPolicy returns the minimal policy the caller needs to run operations, out of create and
+// delete, for cfg; accountID scopes the resources to an account, "*" matches any account
+func RequiredPolicy(cfg *api.ClusterConfig, accountID string, operations []string) (*PolicyDocument, error) {
+	partition, err := partitionForRegion(cfg.Metadata.Region)
+	if err != nil {
+		return nil, err
+	}
+	b := &requiredPolicyBuilder{
+		cfg:       cfg,
+		partition: partition,
+		accountID: accountID,
+	}
+	for _, operation := range operations {
+		switch operation {
+		case OperationCreate:
+			b.forCreate = true
+		case OperationDelete:
+			b.forDelete = true
+		default:
+			return nil, fmt.Errorf("unknown operation %q, supported operations are %v", operation, SupportedOperations())
+		}
+	}
+
+	b.addCloudFormation()
+	b.addEKS()
+	b.addEC2()
+	b.addAutoScaling()
+	b.addIAM()
+	b.addOptionalServices()
+	b.add("CallerIdentity", []string{"sts:GetCallerIdentity"}, nil, []string{"*"})
+
+	return &PolicyDocument{
+		Version:   "2012-10-17",
+		Statement: b.statements,
+	}, nil
+}
+
+func partitionForRegion(region string) (string, error) {
+	for _, p := range endpoints.DefaultPartitions() {
+		if _, ok := p.Regions()[region]; ok {
+			return p.ID(), nil
+		}
+	}
+	return "", fmt.Errorf("failed to find the partition of region %s", region)
+}
+
+// add adds a statement with the actions of the create and delete operations that are run
+func (b *requiredPolicyBuilder) add(sid string, createActions, deleteActions, resources []string) {
+	b.addWithCondition(sid, createActions, deleteActions, resources, nil)
+}
+
+func (b *requiredPolicyBuilder) addWithCondition(sid string, createActions, deleteActions, resources []string, condition map[string]map[string]string) {
+	var actions []string
+	if b.forCreate {
+		actions = append(actions, createActions...)
+	}
+	if b.forDelete {
+		actions = appendMissing(actions, deleteActions...)
+	}
+	if len(actions) == 0 {
+		return
+	}
+	b.statements = append(b.statements, PolicyStatement{
+		Sid:       sid,
+		Effect:    "Allow",
+		Action:    actions,
+		Resource:  resources,
+		Condition: condition,
+	})
+}
+
+func (b *requiredPolicyBuilder) arn(service, resource string) string {
+	return fmt.Sprintf("arn:%s:%s:%s:%s:%s", b.partition, service, b.cfg.Metadata.Region, b.accountID, resource)
+}
+
+func (b *requiredPolicyBuilder) globalARN(service, resource string) string {
+	return fmt.Sprintf("arn:%s:%s::%s:%s", b.partition, service, b.accountID, resource)
+}
+
+// resourcePrefix is the prefix of the stacks eksctl creates for the cluster, and of the
+// resources CloudFormation names after them
+func (b *requiredPolicyBuilder) resourcePrefix() string {
+	return fmt.Sprintf("eksctl-%s-", b.cfg.Metadata.Name)
+}
+
+func (b *requiredPolicyBuilder) regionCondition() map[string]map[string]string {
+	return map[string]map[string]string{
+		"StringEquals": {"aws:RequestedRegion": b.cfg.Metadata.Region},
+	}
+}
+
+func (b *requiredPolicyBuilder) addCloudFormation() {
+	b.add("CloudFormationStacks",
+		[]string{
+			"cloudformation:CreateStack",
+			"cloudformation:DescribeStacks",
+			"cloudformation:DescribeStackEvents",
+			"cloudformation:DescribeStackResources",
+			"cloudformation:ListStackResources",
+			"cloudformation:GetTemplate",
+		},
+		[]string{
+			"cloudformation:DeleteStack",
+			"cloudformation:DescribeStacks",
+			"cloudformation:DescribeStackEvents",
+			"cloudformation:ListStackResources",
+			"cloudformation:GetTemplate",
+		},
+		[]string{b.arn("cloudformation", "stack/"+b.resourcePrefix()+"*/*")},
+	)
+	b.add("CloudFormationList", nil, []string{"cloudformation:ListStacks"}, []string{"*"})
+}
+
+func (b *requiredPolicyBuilder) addEKS() {
+	name := b.cfg.Metadata.Name
+	createActions := []string{
+		"eks:CreateCluster",
+		"eks:DescribeCluster",
+		"eks:DescribeUpdate",
+		"eks:TagResource",
+	}
+	deleteActions := []string{
+		"eks:DeleteCluster",
+		"eks:DescribeCluster",
+		"eks:ListNodegroups",
+		"eks:ListFargateProfiles",
+	}
+	resources := []string{b.arn("eks", "cluster/"+name)}
+	if b.cfg.HasClusterCloudWatchLogging() {
+		createActions = append(createActions, "eks:UpdateClusterConfig")
+	}
+	if len(b.cfg.ManagedNodeGroups) > 0 {
+		createActions = append(createActions, "eks:CreateNodegroup", "eks:DescribeNodegroup")
+		deleteActions = append(deleteActions, "eks:DeleteNodegroup", "eks:DescribeNodegroup")
+		resources = append(resources, b.arn("eks", fmt.Sprintf("nodegroup/%s/*/*", name)))
+	}
+	if len(b.cfg.FargateProfiles) > 0 {
+		createActions = append(createActions, "eks:CreateFargateProfile", "eks:DescribeFargateProfile", "eks:ListFargateProfiles")
+		deleteActions = append(deleteActions, "eks:DeleteFargateProfile", "eks:DescribeFargateProfile")
+		resources = append(resources, b.arn("eks", fmt.Sprintf("fargateprofile/%s/*/*", name)))
+	}
+	if b.cfg.HasAddons() {
+		createActions = append(createActions, "eks:CreateAddon", "eks:DescribeAddon")
+		deleteActions = append(deleteActions, "eks:ListAddons", "eks:DeleteAddon", "eks:DescribeAddon")
+		resources = append(resources, b.arn("eks", fmt.Sprintf("addon/%s/*/*", name)))
+	}
+	b.add("EKS", createActions, deleteActions, resources)
+	if b.cfg.HasAddons() {
+		b.add("EKSAddonVersions", []string{"eks:DescribeAddonVersions"}, nil, []string{"*"})
+	}
+}
+
+// addEC2 adds the EC2 actions, most of them can't be scoped to resources as their IDs are only
+// known once they're created, they're scoped to the region of the cluster instead
+func (b *requiredPolicyBuilder) addEC2() {
+	createActions := []string{
+		"ec2:CreateSecurityGroup",
+		"ec2:AuthorizeSecurityGroupIngress",
+		"ec2:AuthorizeSecurityGroupEgress",
+		"ec2:RevokeSecurityGroupEgress",
+		"ec2:CreateTags",
+	}
+	deleteActions := []string{
+		"ec2:DeleteSecurityGroup",
+		"ec2:RevokeSecurityGroupIngress",
+		"ec2:RevokeSecurityGroupEgress",
+		"ec2:DeleteTags",
+		"ec2:DeleteNetworkInterface",
+	}
+	if b.cfg.VPC == nil || b.cfg.VPC.ID == "" {
+		createActions = append(createActions,
+			"ec2:CreateVpc",
+			"ec2:ModifyVpcAttribute",
+			"ec2:CreateSubnet",
+			"ec2:ModifySubnetAttribute",
+			"ec2:CreateInternetGateway",
+			"ec2:AttachInternetGateway",
+			"ec2:CreateRouteTable",
+			"ec2:CreateRoute",
+			"ec2:AssociateRouteTable",
+			"ec2:AllocateAddress",
+			"ec2:CreateNatGateway",
+		)
+		deleteActions = append(deleteActions,
+			"ec2:DeleteVpc",
+			"ec2:DeleteSubnet",
+			"ec2:DetachInternetGateway",
+			"ec2:DeleteInternetGateway",
+			"ec2:DisassociateRouteTable",
+			"ec2:DeleteRoute",
+			"ec2:DeleteRouteTable",
+			"ec2:DeleteNatGateway",
+			"ec2:ReleaseAddress",
+		)
+	}
+	if len(b.cfg.NodeGroups) > 0 || len(b.cfg.ManagedNodeGroups) > 0 {
+		createActions = append(createActions,
+			"ec2:CreateLaunchTemplate",
+			"ec2:CreateLaunchTemplateVersion",
+			"ec2:RunInstances",
+			"ec2:ImportKeyPair",
+		)
+		deleteActions = append(deleteActions,
+			"ec2:DeleteLaunchTemplate",
+			"ec2:TerminateInstances",
+		)
+	}
+	b.addWithCondition("EC2", createActions, deleteActions, []string{"*"}, b.regionCondition())
+
+	describeActions := []string{
+		"ec2:DescribeAccountAttributes",
+		"ec2:DescribeAvailabilityZones",
+		"ec2:DescribeVpcs",
+		"ec2:DescribeSubnets",
+		"ec2:DescribeSecurityGroups",
+		"ec2:DescribeRouteTables",
+		"ec2:DescribeInternetGateways",
+		"ec2:DescribeNatGateways",
+		"ec2:DescribeAddresses",
+		"ec2:DescribeNetworkInterfaces",
+		"ec2:DescribeImages",
+		"ec2:DescribeKeyPairs",
+		"ec2:DescribeLaunchTemplates",
+		"ec2:DescribeLaunchTemplateVersions",
+		"ec2:DescribeInstances",
+		"ec2:DescribeInstanceTypes",
+	}
+	b.add("EC2Describe", describeActions, describeActions, []string{"*"})
+}
+
+func (b *requiredPolicyBuilder) addAutoScaling() {
+	if len(b.cfg.NodeGroups) == 0 {
+		return
+	}
+	b.add("AutoScalingGroups",
+		[]string{
+			"autoscaling:CreateAutoScalingGroup",
+			"autoscaling:UpdateAutoScalingGroup",
+			"autoscaling:CreateOrUpdateTags",
+			"autoscaling:PutLifecycleHook",
+		},
+		[]string{
+			"autoscaling:UpdateAutoScalingGroup",
+			"autoscaling:DeleteAutoScalingGroup",
+			"autoscaling:DeleteTags",
+		},
+		[]string{b.arn("autoscaling", "autoScalingGroup:*:autoScalingGroupName/"+b.resourcePrefix()+"*")},
+	)
+	describeActions := []string{
+		"autoscaling:DescribeAutoScalingGroups",
+		"autoscaling:DescribeScalingActivities",
+		"autoscaling:DescribeLaunchConfigurations",
+	}
+	b.add("AutoScalingDescribe", describeActions, describeActions, []string{"*"})
+}
+
+func (b *requiredPolicyBuilder) addIAM() {
+	roles := []string{
+		b.globalARN("iam", "role/"+b.resourcePrefix()+"*"),
+		b.globalARN("iam", "instance-profile/"+b.resourcePrefix()+"*"),
+	}
+	b.add("IAMRoles",
+		[]string{
+			"iam:CreateRole",
+			"iam:GetRole",
+			"iam:TagRole",
+			"iam:PassRole",
+			"iam:AttachRolePolicy",
+			"iam:PutRolePolicy",
+			"iam:GetRolePolicy",
+			"iam:CreateInstanceProfile",
+			"iam:AddRoleToInstanceProfile",
+			"iam:GetInstanceProfile",
+		},
+		[]string{
+			"iam:GetRole",
+			"iam:DeleteRole",
+			"iam:DetachRolePolicy",
+			"iam:DeleteRolePolicy",
+			"iam:RemoveRoleFromInstanceProfile",
+			"iam:DeleteInstanceProfile",
+		},
+		roles,
+	)
+
+	// roles of the config that eksctl doesn't create are only passed to the services
+	if existingRoles := b.existingRoles(); len(existingRoles) > 0 {
+		b.add("IAMExistingRoles", []string{"iam:GetRole", "iam:PassRole"}, nil, existingRoles)
+	}
+
+	b.addWithCondition("IAMServiceLinkedRoles",
+		[]string{"iam:CreateServiceLinkedRole"}, nil,
+		[]string{b.globalARN("iam", "role/aws-service-role/*")},
+		map[string]map[string]string{
+			"StringLike": {"iam:AWSServiceName": "eks*.amazonaws.com"},
+		},
+	)
+
+	if b.cfg.IAM != nil && api.IsEnabled(b.cfg.IAM.WithOIDC) {
+		b.add("IAMOIDCProvider",
+			[]string{
+				"iam:CreateOpenIDConnectProvider",
+				"iam:GetOpenIDConnectProvider",
+				"iam:TagOpenIDConnectProvider",
+			},
+			[]string{
+				"iam:GetOpenIDConnectProvider",
+				"iam:DeleteOpenIDConnectProvider",
+			},
+			[]string{b.globalARN("iam", fmt.Sprintf("oidc-provider/oidc.eks.%s.amazonaws.com/id/*", b.cfg.Metadata.Region))},
+		)
+	}
+}
+
+func (b *requiredPolicyBuilder) existingRoles() []string {
+	var roles []string
+	if b.cfg.IAM != nil {
+		if b.cfg.IAM.ServiceRoleARN != nil {
+			roles = appendMissing(roles, *b.cfg.IAM.ServiceRoleARN)
+		}
+		if b.cfg.IAM.FargatePodExecutionRoleARN != nil {
+			roles = appendMissing(roles, *b.cfg.IAM.FargatePodExecutionRoleARN)
+		}
+	}
+	for _, ng := range b.cfg.NodeGroups {
+		if ng.IAM != nil && ng.IAM.InstanceRoleARN != "" {
+			roles = appendMissing(roles, ng.IAM.InstanceRoleARN)
+		}
+	}
+	for _, ng := range b.cfg.ManagedNodeGroups {
+		if ng.IAM != nil && ng.IAM.InstanceRoleARN != "" {
+			roles = appendMissing(roles, ng.IAM.InstanceRoleARN)
+		}
+	}
+	for _, fp := range b.cfg.FargateProfiles {
+		if fp.PodExecutionRoleARN != "" {
+			roles = appendMissing(roles, fp.PodExecutionRoleARN)
+		}
+	}
+	return roles
+}
+
+// addOptionalServices adds the actions of the services only some features use
+func (b *requiredPolicyBuilder) addOptionalServices() {
+	if len(b.cfg.NodeGroups) > 0 || len(b.cfg.ManagedNodeGroups) > 0 {
+		b.add("SSMAMIParameters", []string{"ssm:GetParameter"}, nil, []string{
+			b.arn("ssm", "parameter/aws/service/eks/*"),
+			b.arn("ssm", "parameter/aws/service/bottlerocket/*"),
+			b.arn("ssm", "parameter/aws/service/ami-windows-latest/*"),
+		})
+	}
+	if b.cfg.SecretsEncryption != nil && b.cfg.SecretsEncryption.KeyARN != nil {
+		b.add("KMSSecretsEncryption", []string{"kms:DescribeKey", "kms:CreateGrant"}, nil, []string{*b.cfg.SecretsEncryption.KeyARN})
+	}
+	if b.cfg.HasNodeTerminationHandlerQueue() {
+		b.add("NodeTerminationHandlerQueue",
+			[]string{"sqs:CreateQueue", "sqs:GetQueueAttributes", "sqs:SetQueueAttributes", "sqs:TagQueue"},
+			[]string{"sqs:GetQueueAttributes", "sqs:DeleteQueue"},
+			[]string{b.arn("sqs", b.resourcePrefix()+"*")},
+		)
+		b.add("NodeTerminationHandlerEvents",
+			[]string{"events:PutRule", "events:PutTargets", "events:DescribeRule"},
+			[]string{"events:RemoveTargets", "events:DeleteRule", "events:DescribeRule"},
+			[]string{b.arn("events", "rule/"+b.resourcePrefix()+"*")},
+		)
+	}
+}
+
+func appendMissing(values []string, newValues ...string) []string {
+	for _, newValue := range newValues {
+		found := false
+		for _, value := range values {
+			if value == newValue {
+				found = true
+				break
+			}
+		}
+		if !found {
+			values = append(values, newValue)
+		}
+	}
+	return values
+}
@@ -0,0 +1,71 @@
+package iam
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+var _ = Describe("required policy", func() {
+	var cfg *api.ClusterConfig
+
+	statement := func(policy *PolicyDocument, sid string) *PolicyStatement {
+		for i := range policy.Statement {
+			if policy.Statement[i].Sid == sid {
+				return &policy.Statement[i]
+			}
+		}
+		return nil
+	}
+
+	BeforeEach(func() {
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "cluster-1"
+		cfg.Metadata.Region = "us-west-2"
+	})
+
+	It("scopes the stacks and roles to the cluster", func() {
+		policy, err := RequiredPolicy(cfg, "123456789012", []string{OperationCreate})
+		Expect(err).ToNot(HaveOccurred())
+
+		stacks := statement(policy, "CloudFormationStacks")
+		Expect(stacks).ToNot(BeNil())
+		Expect(stacks.Action).To(ContainElement("cloudformation:CreateStack"))
+		Expect(stacks.Action).ToNot(ContainElement("cloudformation:DeleteStack"))
+		Expect(stacks.Resource).To(ConsistOf("arn:aws:cloudformation:us-west-2:123456789012:stack/eksctl-cluster-1-*/*"))
+
+		roles := statement(policy, "IAMRoles")
+		Expect(roles.Resource).To(ConsistOf(
+			"arn:aws:iam::123456789012:role/eksctl-cluster-1-*",
+			"arn:aws:iam::123456789012:instance-profile/eksctl-cluster-1-*",
+		))
+
+		Expect(statement(policy, "EC2").Action).To(ContainElement("ec2:CreateVpc"))
+		Expect(statement(policy, "EC2").Condition).To(HaveKeyWithValue("StringEquals", HaveKeyWithValue("aws:RequestedRegion", "us-west-2")))
+		Expect(statement(policy, "AutoScalingGroups")).To(BeNil())
+		Expect(statement(policy, "IAMOIDCProvider")).To(BeNil())
+	})
+
+	It("only adds the actions of the features the config uses", func() {
+		cfg.VPC.ID = "vpc-1"
+		cfg.IAM.WithOIDC = api.Enabled()
+		cfg.NodeGroups = []*api.NodeGroup{{Name: "ng-1"}}
+		cfg.ManagedNodeGroups = []*api.ManagedNodeGroup{{Name: "mng-1", IAM: &api.NodeGroupIAM{InstanceRoleARN: "arn:aws:iam::123456789012:role/node"}}}
+
+		policy, err := RequiredPolicy(cfg, "*", SupportedOperations())
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(statement(policy, "EC2").Action).ToNot(ContainElement("ec2:CreateVpc"))
+		Expect(statement(policy, "EKS").Action).To(ContainElement("eks:CreateNodegroup"))
+		Expect(statement(policy, "EKS").Action).To(ContainElement("eks:DeleteCluster"))
+		Expect(statement(policy, "AutoScalingGroups").Resource).To(ConsistOf("arn:aws:autoscaling:us-west-2:*:autoScalingGroup:*:autoScalingGroupName/eksctl-cluster-1-*"))
+		Expect(statement(policy, "IAMOIDCProvider").Resource).To(ConsistOf("arn:aws:iam::*:oidc-provider/oidc.eks.us-west-2.amazonaws.com/id/*"))
+		Expect(statement(policy, "IAMExistingRoles").Resource).To(ConsistOf("arn:aws:iam::123456789012:role/node"))
+	})
+
+	It("rejects unknown operations", func() {
+		_, err := RequiredPolicy(cfg, "*", []string{"upgrade"})
+		Expect(err).To(MatchError(ContainSubstring(`unknown operation "upgrade"`)))
+	})
+})
@@ -0,0 +1,100 @@
+package iam
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsiam "github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	iamoidc "github.com/weaveworks/eksctl/pkg/iam/oidc"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+)
+
+// DescribeServiceAccountRole adds the policies of the role of the iamserviceaccount to its
+// status, along with the problems that prevent pods using the serviceaccount from assuming
+// the role: a trust policy that doesn't match the OIDC provider of the cluster, e.g. after the
+// cluster was recreated, or a serviceaccount that is missing or annotated with another role;
+// oidc is nil when the cluster has no OIDC provider
+func DescribeServiceAccountRole(iamAPI iamiface.IAMAPI, oidc *iamoidc.OpenIDConnectManager, clientSet kubernetes.Interface, sa *api.ClusterIAMServiceAccount) error {
+	if sa.Status == nil || sa.Status.RoleARN == nil {
+		return fmt.Errorf("iamserviceaccount %q has no role", sa.NameString())
+	}
+	roleARN := *sa.Status.RoleARN
+	roleName, err := roleNameFromARN(roleARN)
+	if err != nil {
+		return err
+	}
+
+	sa.Status.AttachedPolicyARNs = nil
+	attachedInput := &awsiam.ListAttachedRolePoliciesInput{RoleName: &roleName}
+	for {
+		output, err := iamAPI.ListAttachedRolePolicies(attachedInput)
+		if err != nil {
+			return errors.Wrapf(err, "listing the policies attached to IAM role %q", roleARN)
+		}
+		for _, policy := range output.AttachedPolicies {
+			sa.Status.AttachedPolicyARNs = append(sa.Status.AttachedPolicyARNs, aws.StringValue(policy.PolicyArn))
+		}
+		if !aws.BoolValue(output.IsTruncated) {
+			break
+		}
+		attachedInput.Marker = output.Marker
+	}
+
+	sa.Status.InlinePolicyNames = nil
+	inlineInput := &awsiam.ListRolePoliciesInput{RoleName: &roleName}
+	for {
+		output, err := iamAPI.ListRolePolicies(inlineInput)
+		if err != nil {
+			return errors.Wrapf(err, "listing the inline policies of IAM role %q", roleARN)
+		}
+		sa.Status.InlinePolicyNames = append(sa.Status.InlinePolicyNames, aws.StringValueSlice(output.PolicyNames)...)
+		if !aws.BoolValue(output.IsTruncated) {
+			break
+		}
+		inlineInput.Marker = output.Marker
+	}
+
+	var problems []string
+	if oidc == nil {
+		problems = append(problems, "the cluster has no IAM OIDC provider")
+	} else {
+		trustProblems, err := oidc.CheckRoleTrust(roleARN, sa.Namespace, sa.Name)
+		if err != nil {
+			return err
+		}
+		problems = append(problems, trustProblems...)
+	}
+
+	serviceAccount, err := clientSet.CoreV1().ServiceAccounts(sa.Namespace).Get(sa.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		problems = append(problems, fmt.Sprintf("serviceaccount %q doesn't exist", sa.NameString()))
+	case err != nil:
+		return errors.Wrapf(err, "getting serviceaccount %q", sa.NameString())
+	default:
+		if annotation := serviceAccount.Annotations[api.AnnotationEKSRoleARN]; annotation != roleARN {
+			problems = append(problems, fmt.Sprintf("serviceaccount %q is annotated with %s=%q instead of the role", sa.NameString(), api.AnnotationEKSRoleARN, annotation))
+		}
+	}
+	sa.Status.Problems = problems
+	return nil
+}
+
+// roleNameFromARN returns the name of a role from its ARN, whose resource is role/[path/]name
+func roleNameFromARN(roleARN string) (string, error) {
+	parsed, err := Parse(roleARN)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing role ARN %q", roleARN)
+	}
+	if !parsed.IsRole() {
+		return "", fmt.Errorf("%q is not the ARN of a role", roleARN)
+	}
+	return parsed.Resource[strings.LastIndex(parsed.Resource, "/")+1:], nil
+}
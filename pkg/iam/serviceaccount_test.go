@@ -0,0 +1,108 @@
+package iam
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsiam "github.com/aws/aws-sdk-go/service/iam"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	iamoidc "github.com/weaveworks/eksctl/pkg/iam/oidc"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("iamserviceaccount role", func() {
+	const (
+		roleARN     = "arn:aws:iam::12345:role/eksctl-cluster-1-addon-iamserviceaccount-Role1"
+		providerARN = "arn:aws:iam::12345:oidc-provider/oidc.eks.us-west-2.amazonaws.com/id/ABCDEF"
+		issuerID    = "oidc.eks.us-west-2.amazonaws.com/id/ABCDEF"
+	)
+
+	var (
+		p         *mockprovider.MockProvider
+		oidc      *iamoidc.OpenIDConnectManager
+		clientSet *fake.Clientset
+		sa        *api.ClusterIAMServiceAccount
+	)
+
+	trustPolicy := func(federated string) string {
+		return url.QueryEscape(fmt.Sprintf(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Federated":%q},"Action":"sts:AssumeRoleWithWebIdentity","Condition":{"StringEquals":{%q:"system:serviceaccount:ns-1:sa-1",%q:"sts.amazonaws.com"}}}]}`,
+			federated, issuerID+":sub", issuerID+":aud"))
+	}
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		var err error
+		oidc, err = iamoidc.NewOpenIDConnectManager(p.IAM(), "12345", "https://"+issuerID, "aws")
+		Expect(err).NotTo(HaveOccurred())
+		oidc.ProviderARN = providerARN
+
+		sa = &api.ClusterIAMServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: "sa-1", Namespace: "ns-1"},
+			Status:     &api.ClusterIAMServiceAccountStatus{RoleARN: aws.String(roleARN)},
+		}
+		clientSet = fake.NewSimpleClientset(&corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "sa-1",
+				Namespace:   "ns-1",
+				Annotations: map[string]string{api.AnnotationEKSRoleARN: roleARN},
+			},
+		})
+
+		p.MockIAM().On("ListAttachedRolePolicies", mock.Anything).Return(&awsiam.ListAttachedRolePoliciesOutput{
+			AttachedPolicies: []*awsiam.AttachedPolicy{{PolicyArn: aws.String("arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess")}},
+		}, nil)
+		p.MockIAM().On("ListRolePolicies", mock.Anything).Return(&awsiam.ListRolePoliciesOutput{
+			PolicyNames: aws.StringSlice([]string{"Policy1"}),
+		}, nil)
+	})
+
+	It("reports the policies of a healthy role", func() {
+		p.MockIAM().On("GetRole", mock.Anything).Return(&awsiam.GetRoleOutput{
+			Role: &awsiam.Role{AssumeRolePolicyDocument: aws.String(trustPolicy(providerARN))},
+		}, nil)
+
+		Expect(DescribeServiceAccountRole(p.IAM(), oidc, clientSet, sa)).To(Succeed())
+		Expect(sa.Status.AttachedPolicyARNs).To(ConsistOf("arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"))
+		Expect(sa.Status.InlinePolicyNames).To(ConsistOf("Policy1"))
+		Expect(sa.Status.Problems).To(BeEmpty())
+	})
+
+	It("flags a role that trusts the provider of a previous cluster", func() {
+		p.MockIAM().On("GetRole", mock.Anything).Return(&awsiam.GetRoleOutput{
+			Role: &awsiam.Role{AssumeRolePolicyDocument: aws.String(trustPolicy("arn:aws:iam::12345:oidc-provider/oidc.eks.us-west-2.amazonaws.com/id/OLD"))},
+		}, nil)
+
+		Expect(DescribeServiceAccountRole(p.IAM(), oidc, clientSet, sa)).To(Succeed())
+		Expect(sa.Status.Problems).To(ConsistOf(ContainSubstring("doesn't allow sts:AssumeRoleWithWebIdentity")))
+	})
+
+	It("flags a serviceaccount annotated with another role", func() {
+		p.MockIAM().On("GetRole", mock.Anything).Return(&awsiam.GetRoleOutput{
+			Role: &awsiam.Role{AssumeRolePolicyDocument: aws.String(trustPolicy(providerARN))},
+		}, nil)
+		clientSet = fake.NewSimpleClientset(&corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: "sa-1", Namespace: "ns-1"},
+		})
+
+		Expect(DescribeServiceAccountRole(p.IAM(), oidc, clientSet, sa)).To(Succeed())
+		Expect(sa.Status.Problems).To(ConsistOf(ContainSubstring("instead of the role")))
+	})
+
+	It("flags a missing OIDC provider and serviceaccount", func() {
+		clientSet = fake.NewSimpleClientset()
+
+		Expect(DescribeServiceAccountRole(p.IAM(), nil, clientSet, sa)).To(Succeed())
+		Expect(sa.Status.Problems).To(ConsistOf(
+			ContainSubstring("no IAM OIDC provider"),
+			ContainSubstring("doesn't exist"),
+		))
+	})
+})
@@ -0,0 +1,176 @@
+package inspector
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// eksNodeGroupNameTag is the tag EKS sets on the instances of managed nodegroups
+const eksNodeGroupNameTag = "eks:nodegroup-name"
+
+// Severities findings can be filtered by, from the most severe
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+)
+
+// maxAMIsPerRequest is the number of AMIs whose findings are aggregated in one request
+const maxAMIsPerRequest = 10
+
+// AMIReport holds the nodes using an AMI and the vulnerabilities found in it
+type AMIReport struct {
+	ImageID    string   `json:"imageID"`
+	NodeGroups []string `json:"nodeGroups"`
+	Instances  int      `json:"instances"`
+	// Scanned is false when Inspector hasn't reported findings for the AMI, e.g. when
+	// EC2 scanning isn't enabled in the account
+	Scanned  bool  `json:"scanned"`
+	Critical int64 `json:"critical"`
+	High     int64 `json:"high"`
+	Medium   int64 `json:"medium"`
+	All      int64 `json:"all"`
+}
+
+// ValidateSeverity checks that findings can be filtered by the severity
+func ValidateSeverity(severity string) error {
+	switch severity {
+	case SeverityCritical, SeverityHigh, SeverityMedium:
+		return nil
+	default:
+		return fmt.Errorf("unknown severity %q, valid severities are: %s, %s, %s", severity, SeverityCritical, SeverityHigh, SeverityMedium)
+	}
+}
+
+// HasFindings reports whether the AMI has findings of the severity or a more severe one
+func (r *AMIReport) HasFindings(severity string) bool {
+	switch severity {
+	case SeverityCritical:
+		return r.Critical > 0
+	case SeverityHigh:
+		return r.Critical+r.High > 0
+	case SeverityMedium:
+		return r.Critical+r.High+r.Medium > 0
+	}
+	return false
+}
+
+// NodeAMIs returns the AMIs of the running instances of the cluster, along with the
+// nodegroups using them
+func NodeAMIs(ec2API ec2iface.EC2API, clusterName string) ([]*AMIReport, error) {
+	input := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("tag-key"),
+				Values: aws.StringSlice([]string{"kubernetes.io/cluster/" + clusterName}),
+			},
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: aws.StringSlice([]string{ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning}),
+			},
+		},
+	}
+
+	reports := map[string]*AMIReport{}
+	nodeGroups := map[string]map[string]bool{}
+	if err := ec2API.DescribeInstancesPages(input, func(page *ec2.DescribeInstancesOutput, _ bool) bool {
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				imageID := aws.StringValue(instance.ImageId)
+				report, ok := reports[imageID]
+				if !ok {
+					report = &AMIReport{ImageID: imageID}
+					reports[imageID] = report
+					nodeGroups[imageID] = map[string]bool{}
+				}
+				report.Instances++
+				if name := nodeGroupName(instance.Tags); name != "" && !nodeGroups[imageID][name] {
+					nodeGroups[imageID][name] = true
+					report.NodeGroups = append(report.NodeGroups, name)
+				}
+			}
+		}
+		return true
+	}); err != nil {
+		return nil, errors.Wrapf(err, "describing the instances of cluster %q", clusterName)
+	}
+
+	var sorted []*AMIReport
+	for _, report := range reports {
+		sort.Strings(report.NodeGroups)
+		sorted = append(sorted, report)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ImageID < sorted[j].ImageID })
+	return sorted, nil
+}
+
+func nodeGroupName(tags []*ec2.Tag) string {
+	for _, key := range []string{api.NodeGroupNameTag, eksNodeGroupNameTag} {
+		for _, tag := range tags {
+			if aws.StringValue(tag.Key) == key {
+				return aws.StringValue(tag.Value)
+			}
+		}
+	}
+	return ""
+}
+
+// AddFindings adds the findings Inspector reported for the AMIs to the reports
+func AddFindings(inspectorAPI API, reports []*AMIReport) error {
+	byImageID := map[string]*AMIReport{}
+	for _, report := range reports {
+		byImageID[report.ImageID] = report
+	}
+
+	for start := 0; start < len(reports); start += maxAMIsPerRequest {
+		end := start + maxAMIsPerRequest
+		if end > len(reports) {
+			end = len(reports)
+		}
+		aggregation := &AMIAggregation{}
+		for _, report := range reports[start:end] {
+			aggregation.AMIs = append(aggregation.AMIs, &StringFilter{
+				Comparison: aws.String(ComparisonEquals),
+				Value:      aws.String(report.ImageID),
+			})
+		}
+		input := &ListFindingAggregationsInput{
+			AggregationType:    aws.String(AggregationTypeAMI),
+			AggregationRequest: &AggregationRequest{AMIAggregation: aggregation},
+		}
+		for {
+			output, err := inspectorAPI.ListFindingAggregations(input)
+			if err != nil {
+				return errors.Wrap(err, "listing the findings of Amazon Inspector, EC2 scanning has to be enabled in the account")
+			}
+			for _, response := range output.Responses {
+				if response.AMIAggregation == nil {
+					continue
+				}
+				report, ok := byImageID[aws.StringValue(response.AMIAggregation.AMI)]
+				if !ok {
+					continue
+				}
+				report.Scanned = true
+				if counts := response.AMIAggregation.SeverityCounts; counts != nil {
+					report.Critical = aws.Int64Value(counts.Critical)
+					report.High = aws.Int64Value(counts.High)
+					report.Medium = aws.Int64Value(counts.Medium)
+					report.All = aws.Int64Value(counts.All)
+				}
+			}
+			if output.NextToken == nil {
+				break
+			}
+			input.NextToken = output.NextToken
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package inspector_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/inspector"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+type fakeInspectorAPI struct {
+	inputs  []*ListFindingAggregationsInput
+	outputs []*ListFindingAggregationsOutput
+}
+
+func (f *fakeInspectorAPI) ListFindingAggregations(input *ListFindingAggregationsInput) (*ListFindingAggregationsOutput, error) {
+	copied := *input
+	f.inputs = append(f.inputs, &copied)
+	output := f.outputs[0]
+	f.outputs = f.outputs[1:]
+	return output, nil
+}
+
+func instance(imageID string, tags map[string]string) *ec2.Instance {
+	instance := &ec2.Instance{ImageId: aws.String(imageID)}
+	for key, value := range tags {
+		instance.Tags = append(instance.Tags, &ec2.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return instance
+}
+
+func amiFindings(imageID string, critical, high, medium, all int64) *AggregationResponse {
+	return &AggregationResponse{AMIAggregation: &AMIAggregationResponse{
+		AMI: aws.String(imageID),
+		SeverityCounts: &SeverityCounts{
+			Critical: aws.Int64(critical),
+			High:     aws.Int64(high),
+			Medium:   aws.Int64(medium),
+			All:      aws.Int64(all),
+		},
+	}}
+}
+
+var _ = Describe("AMI vulnerabilities", func() {
+	It("groups the instances of the cluster by AMI", func() {
+		p := mockprovider.NewMockProvider()
+		p.MockEC2().On("DescribeInstancesPages", mock.MatchedBy(func(input *ec2.DescribeInstancesInput) bool {
+			return *input.Filters[0].Values[0] == "kubernetes.io/cluster/cluster-1"
+		}), mock.Anything).Run(func(args mock.Arguments) {
+			pager := args.Get(1).(func(*ec2.DescribeInstancesOutput, bool) bool)
+			pager(&ec2.DescribeInstancesOutput{Reservations: []*ec2.Reservation{{Instances: []*ec2.Instance{
+				instance("ami-2", map[string]string{api.NodeGroupNameTag: "ng-1"}),
+				instance("ami-1", map[string]string{"eks:nodegroup-name": "managed-1"}),
+				instance("ami-2", map[string]string{api.NodeGroupNameTag: "ng-2"}),
+				instance("ami-2", map[string]string{api.NodeGroupNameTag: "ng-1"}),
+			}}}}, true)
+		}).Return(nil)
+
+		reports, err := NodeAMIs(p.EC2(), "cluster-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reports).To(Equal([]*AMIReport{
+			{ImageID: "ami-1", NodeGroups: []string{"managed-1"}, Instances: 1},
+			{ImageID: "ami-2", NodeGroups: []string{"ng-1", "ng-2"}, Instances: 3},
+		}))
+	})
+
+	It("adds the findings of each AMI", func() {
+		reports := []*AMIReport{{ImageID: "ami-1"}, {ImageID: "ami-2"}, {ImageID: "ami-3"}}
+		fake := &fakeInspectorAPI{outputs: []*ListFindingAggregationsOutput{
+			{Responses: []*AggregationResponse{amiFindings("ami-1", 1, 2, 3, 10)}, NextToken: aws.String("next")},
+			{Responses: []*AggregationResponse{amiFindings("ami-2", 0, 0, 1, 4)}},
+		}}
+
+		Expect(AddFindings(fake, reports)).To(Succeed())
+		Expect(fake.inputs).To(HaveLen(2))
+		Expect(fake.inputs[0].AggregationRequest.AMIAggregation.AMIs).To(HaveLen(3))
+		Expect(*fake.inputs[1].NextToken).To(Equal("next"))
+
+		Expect(*reports[0]).To(Equal(AMIReport{ImageID: "ami-1", Scanned: true, Critical: 1, High: 2, Medium: 3, All: 10}))
+		Expect(*reports[1]).To(Equal(AMIReport{ImageID: "ami-2", Scanned: true, Medium: 1, All: 4}))
+		Expect(reports[2].Scanned).To(BeFalse())
+
+		Expect(reports[0].HasFindings(SeverityCritical)).To(BeTrue())
+		Expect(reports[1].HasFindings(SeverityHigh)).To(BeFalse())
+		Expect(reports[1].HasFindings(SeverityMedium)).To(BeTrue())
+	})
+
+	It("calls the inspector2 API", func() {
+		var (
+			path string
+			body map[string]interface{}
+		)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			data, err := ioutil.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(json.Unmarshal(data, &body)).To(Succeed())
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"aggregationType":"AMI","responses":[{"amiAggregation":{"ami":"ami-1","affectedInstances":2,"severityCounts":{"all":5,"critical":1,"high":1,"medium":3}}}]}`))
+		}))
+		defer server.Close()
+
+		s := session.Must(session.NewSession(&aws.Config{
+			Region:      aws.String("us-west-2"),
+			Endpoint:    aws.String(server.URL),
+			Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		}))
+		reports := []*AMIReport{{ImageID: "ami-1"}}
+		Expect(AddFindings(New(s), reports)).To(Succeed())
+
+		Expect(path).To(Equal("/findings/aggregation/list"))
+		Expect(body).To(Equal(map[string]interface{}{
+			"aggregationType": "AMI",
+			"aggregationRequest": map[string]interface{}{
+				"amiAggregation": map[string]interface{}{
+					"amis": []interface{}{map[string]interface{}{"comparison": "EQUALS", "value": "ami-1"}},
+				},
+			},
+		}))
+		Expect(*reports[0]).To(Equal(AMIReport{ImageID: "ami-1", Scanned: true, Critical: 1, High: 1, Medium: 3, All: 5}))
+	})
+})
@@ -0,0 +1,130 @@
+// Package inspector summarises the vulnerabilities Amazon Inspector found in the AMIs
+// of the nodes of a cluster
+package inspector
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/aws/aws-sdk-go/private/protocol/restjson"
+)
+
+// API is the part of the Amazon Inspector (inspector2) API used by eksctl; it's defined
+// here as the version of aws-sdk-go eksctl uses predates inspector2
+type API interface {
+	ListFindingAggregations(input *ListFindingAggregationsInput) (*ListFindingAggregationsOutput, error)
+}
+
+// Values of `ListFindingAggregationsInput.AggregationType` and `StringFilter.Comparison`
+const (
+	AggregationTypeAMI = "AMI"
+	ComparisonEquals   = "EQUALS"
+)
+
+// StringFilter filters aggregations by a value
+type StringFilter struct {
+	_ struct{} `type:"structure"`
+
+	Comparison *string `locationName:"comparison" type:"string" required:"true"`
+	Value      *string `locationName:"value" type:"string" required:"true"`
+}
+
+// AMIAggregation selects the AMIs to aggregate findings for
+type AMIAggregation struct {
+	_ struct{} `type:"structure"`
+
+	AMIs []*StringFilter `locationName:"amis" type:"list"`
+}
+
+// AggregationRequest holds the aggregation of the type requested
+type AggregationRequest struct {
+	_ struct{} `type:"structure"`
+
+	AMIAggregation *AMIAggregation `locationName:"amiAggregation" type:"structure"`
+}
+
+// ListFindingAggregationsInput is the input of ListFindingAggregations
+type ListFindingAggregationsInput struct {
+	_ struct{} `type:"structure"`
+
+	AggregationType    *string             `locationName:"aggregationType" type:"string" required:"true"`
+	AggregationRequest *AggregationRequest `locationName:"aggregationRequest" type:"structure"`
+	MaxResults         *int64              `locationName:"maxResults" type:"integer"`
+	NextToken          *string             `locationName:"nextToken" type:"string"`
+}
+
+// SeverityCounts are the numbers of active findings by severity
+type SeverityCounts struct {
+	_ struct{} `type:"structure"`
+
+	All      *int64 `locationName:"all" type:"long"`
+	Critical *int64 `locationName:"critical" type:"long"`
+	High     *int64 `locationName:"high" type:"long"`
+	Medium   *int64 `locationName:"medium" type:"long"`
+}
+
+// AMIAggregationResponse are the findings of an AMI
+type AMIAggregationResponse struct {
+	_ struct{} `type:"structure"`
+
+	AMI               *string         `locationName:"ami" type:"string"`
+	AffectedInstances *int64          `locationName:"affectedInstances" type:"long"`
+	SeverityCounts    *SeverityCounts `locationName:"severityCounts" type:"structure"`
+}
+
+// AggregationResponse holds the aggregation of the type requested
+type AggregationResponse struct {
+	_ struct{} `type:"structure"`
+
+	AMIAggregation *AMIAggregationResponse `locationName:"amiAggregation" type:"structure"`
+}
+
+// ListFindingAggregationsOutput is the output of ListFindingAggregations
+type ListFindingAggregationsOutput struct {
+	_ struct{} `type:"structure"`
+
+	AggregationType *string                `locationName:"aggregationType" type:"string"`
+	Responses       []*AggregationResponse `locationName:"responses" type:"list"`
+	NextToken       *string                `locationName:"nextToken" type:"string"`
+}
+
+// endpointsID is the ID of inspector2 in the endpoints of the SDK, which resolve it to
+// inspector2.<region>.amazonaws.com as it's not one of the known services
+const endpointsID = "inspector2"
+
+type inspector2Client struct {
+	*client.Client
+}
+
+// New returns a client of the inspector2 API, configured like the clients of aws-sdk-go
+func New(p client.ConfigProvider, cfgs ...*aws.Config) API {
+	c := p.ClientConfig(endpointsID, cfgs...)
+	svc := &inspector2Client{
+		Client: client.New(*c.Config, metadata.ClientInfo{
+			ServiceName:   endpointsID,
+			ServiceID:     "Inspector2",
+			SigningName:   c.SigningName,
+			SigningRegion: c.SigningRegion,
+			Endpoint:      c.Endpoint,
+			APIVersion:    "2020-06-08",
+		}, c.Handlers),
+	}
+	svc.Handlers.Sign.PushBackNamed(v4.SignRequestHandler)
+	svc.Handlers.Build.PushBackNamed(restjson.BuildHandler)
+	svc.Handlers.Unmarshal.PushBackNamed(restjson.UnmarshalHandler)
+	svc.Handlers.UnmarshalMeta.PushBackNamed(restjson.UnmarshalMetaHandler)
+	svc.Handlers.UnmarshalError.PushBackNamed(restjson.UnmarshalErrorHandler)
+	return svc
+}
+
+func (c *inspector2Client) ListFindingAggregations(input *ListFindingAggregationsInput) (*ListFindingAggregationsOutput, error) {
+	op := &request.Operation{
+		Name:       "ListFindingAggregations",
+		HTTPMethod: "POST",
+		HTTPPath:   "/findings/aggregation/list",
+	}
+	output := &ListFindingAggregationsOutput{}
+	return output, c.NewRequest(op, input, output).Send()
+}
@@ -0,0 +1,171 @@
+// Package ipusage reports how the IPs of the subnets of a cluster are consumed by its nodes, to
+// spot subnets that are close to exhaustion before scaling fails
+package ipusage
+
+import (
+	"net"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// managedNodeGroupNameTag is the tag EKS sets on the instances of managed nodegroups
+const managedNodeGroupNameTag = "eks:nodegroup-name"
+
+// reservedIPsPerSubnet are the IPs AWS reserves in every subnet
+const reservedIPsPerSubnet = 5
+
+// ipsPerPrefix is the number of IPs of the /28 prefixes assigned with prefix delegation
+const ipsPerPrefix = 16
+
+// the maximum number of pods per node recommended with prefix delegation, for instance types with
+// less than 30 vCPUs and the others
+const (
+	maxPodsLimit                 = 110
+	maxPodsLimitPrefixDelegation = 250
+)
+
+// Inputs is what the report needs to know about the cluster
+type Inputs struct {
+	ClusterName string
+	// Subnets are the subnets of the control plane and the nodegroups
+	Subnets []*ec2.Subnet
+	// Instances are the running instances of the nodegroups
+	Instances []*ec2.Instance
+	// InstanceTypes are the instance types of Instances
+	InstanceTypes []*ec2.InstanceTypeInfo
+	// PrefixDelegation is whether ENABLE_PREFIX_DELEGATION is set in the VPC CNI plugin, nil
+	// when it couldn't be determined
+	PrefixDelegation *bool
+}
+
+// SubnetUsage is the IP usage of a subnet
+type SubnetUsage struct {
+	SubnetID         string `json:"subnetID"`
+	AvailabilityZone string `json:"availabilityZone"`
+	CIDR             string `json:"cidr"`
+	TotalIPs         int64  `json:"totalIPs"`
+	FreeIPs          int64  `json:"freeIPs"`
+	UsedPercent      int64  `json:"usedPercent"`
+	NearExhaustion   bool   `json:"nearExhaustion"`
+}
+
+// NodeGroupUsage is the ENI and IP consumption of the nodes of a nodegroup
+type NodeGroupUsage struct {
+	Name  string `json:"name"`
+	Nodes int    `json:"nodes"`
+	ENIs  int    `json:"enis"`
+	IPs   int    `json:"ips"`
+	// PodCapacity is the number of pods the nodes can run with the IPs they can be assigned
+	PodCapacity int `json:"podCapacity"`
+}
+
+// Report is the IP usage of a cluster
+type Report struct {
+	Cluster          string            `json:"cluster"`
+	PrefixDelegation *bool             `json:"prefixDelegation,omitempty"`
+	Subnets          []*SubnetUsage    `json:"subnets"`
+	NodeGroups       []*NodeGroupUsage `json:"nodeGroups"`
+}
+
+// Compute builds the report; subnets whose used IPs reach warnPercent are near exhaustion
+func Compute(inputs *Inputs, warnPercent int64) *Report {
+	report := &Report{
+		Cluster:          inputs.ClusterName,
+		PrefixDelegation: inputs.PrefixDelegation,
+	}
+
+	for _, subnet := range inputs.Subnets {
+		usage := &SubnetUsage{
+			SubnetID:         aws.StringValue(subnet.SubnetId),
+			AvailabilityZone: aws.StringValue(subnet.AvailabilityZone),
+			CIDR:             aws.StringValue(subnet.CidrBlock),
+			TotalIPs:         subnetSize(aws.StringValue(subnet.CidrBlock)),
+			FreeIPs:          aws.Int64Value(subnet.AvailableIpAddressCount),
+		}
+		if usage.TotalIPs > 0 {
+			usage.UsedPercent = (usage.TotalIPs - usage.FreeIPs) * 100 / usage.TotalIPs
+		}
+		usage.NearExhaustion = usage.UsedPercent >= warnPercent
+		report.Subnets = append(report.Subnets, usage)
+	}
+	sort.Slice(report.Subnets, func(i, j int) bool {
+		return report.Subnets[i].SubnetID < report.Subnets[j].SubnetID
+	})
+
+	instanceTypes := map[string]*ec2.InstanceTypeInfo{}
+	for _, info := range inputs.InstanceTypes {
+		instanceTypes[aws.StringValue(info.InstanceType)] = info
+	}
+	prefixDelegation := inputs.PrefixDelegation != nil && *inputs.PrefixDelegation
+
+	nodeGroups := map[string]*NodeGroupUsage{}
+	for _, instance := range inputs.Instances {
+		name := nodeGroupName(instance)
+		usage, ok := nodeGroups[name]
+		if !ok {
+			usage = &NodeGroupUsage{Name: name}
+			nodeGroups[name] = usage
+			report.NodeGroups = append(report.NodeGroups, usage)
+		}
+		usage.Nodes++
+		usage.ENIs += len(instance.NetworkInterfaces)
+		for _, eni := range instance.NetworkInterfaces {
+			usage.IPs += len(eni.PrivateIpAddresses)
+		}
+		if info, ok := instanceTypes[aws.StringValue(instance.InstanceType)]; ok {
+			usage.PodCapacity += podCapacity(info, prefixDelegation)
+		}
+	}
+	sort.Slice(report.NodeGroups, func(i, j int) bool {
+		return report.NodeGroups[i].Name < report.NodeGroups[j].Name
+	})
+	return report
+}
+
+// subnetSize returns the number of IPs of a subnet that can be assigned
+func subnetSize(cidr string) int64 {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return 0
+	}
+	ones, bits := ipNet.Mask.Size()
+	return int64(1)<<uint(bits-ones) - reservedIPsPerSubnet
+}
+
+func nodeGroupName(instance *ec2.Instance) string {
+	for _, key := range []string{api.NodeGroupNameTag, managedNodeGroupNameTag, api.OldNodeGroupNameTag} {
+		for _, tag := range instance.Tags {
+			if aws.StringValue(tag.Key) == key {
+				return aws.StringValue(tag.Value)
+			}
+		}
+	}
+	return "-"
+}
+
+// podCapacity follows the max pods formula of the VPC CNI plugin: every IP of every ENI but
+// the primary ones is assigned to pods, plus two host network pods; with prefix delegation each
+// secondary IP slot is a /28 prefix
+func podCapacity(info *ec2.InstanceTypeInfo, prefixDelegation bool) int {
+	if info.NetworkInfo == nil {
+		return 0
+	}
+	enis := int(aws.Int64Value(info.NetworkInfo.MaximumNetworkInterfaces))
+	ipsPerENI := int(aws.Int64Value(info.NetworkInfo.Ipv4AddressesPerInterface))
+	if !prefixDelegation {
+		return enis*(ipsPerENI-1) + 2
+	}
+	capacity := enis*(ipsPerENI-1)*ipsPerPrefix + 2
+	limit := maxPodsLimitPrefixDelegation
+	if info.VCpuInfo == nil || aws.Int64Value(info.VCpuInfo.DefaultVCpus) < 30 {
+		limit = maxPodsLimit
+	}
+	if capacity > limit {
+		return limit
+	}
+	return capacity
+}
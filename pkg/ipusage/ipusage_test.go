@@ -0,0 +1,70 @@
+package ipusage_test
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/ipusage"
+)
+
+var _ = Describe("IP usage", func() {
+	var inputs *Inputs
+
+	instance := func(nodeGroup string, ipsPerENI ...int) *ec2.Instance {
+		i := &ec2.Instance{
+			InstanceType: aws.String("m5.large"),
+			Tags:         []*ec2.Tag{{Key: aws.String(api.NodeGroupNameTag), Value: aws.String(nodeGroup)}},
+		}
+		for _, ips := range ipsPerENI {
+			i.NetworkInterfaces = append(i.NetworkInterfaces, &ec2.InstanceNetworkInterface{
+				PrivateIpAddresses: make([]*ec2.InstancePrivateIpAddress, ips),
+			})
+		}
+		return i
+	}
+
+	BeforeEach(func() {
+		inputs = &Inputs{
+			ClusterName: "cluster-1",
+			Subnets: []*ec2.Subnet{
+				{SubnetId: aws.String("subnet-2"), AvailabilityZone: aws.String("az2"), CidrBlock: aws.String("192.168.32.0/24"), AvailableIpAddressCount: aws.Int64(25)},
+				{SubnetId: aws.String("subnet-1"), AvailabilityZone: aws.String("az1"), CidrBlock: aws.String("192.168.0.0/20"), AvailableIpAddressCount: aws.Int64(4000)},
+			},
+			Instances: []*ec2.Instance{instance("ng-1", 10, 10), instance("ng-1", 10), instance("ng-2", 10)},
+			InstanceTypes: []*ec2.InstanceTypeInfo{{
+				InstanceType: aws.String("m5.large"),
+				VCpuInfo:     &ec2.VCpuInfo{DefaultVCpus: aws.Int64(2)},
+				NetworkInfo: &ec2.NetworkInfo{
+					MaximumNetworkInterfaces:  aws.Int64(3),
+					Ipv4AddressesPerInterface: aws.Int64(10),
+				},
+			}},
+		}
+	})
+
+	It("reports the usage of subnets and flags those near exhaustion", func() {
+		report := Compute(inputs, 80)
+		Expect(report.Subnets).To(Equal([]*SubnetUsage{
+			{SubnetID: "subnet-1", AvailabilityZone: "az1", CIDR: "192.168.0.0/20", TotalIPs: 4091, FreeIPs: 4000, UsedPercent: 2},
+			{SubnetID: "subnet-2", AvailabilityZone: "az2", CIDR: "192.168.32.0/24", TotalIPs: 251, FreeIPs: 25, UsedPercent: 90, NearExhaustion: true},
+		}))
+	})
+
+	It("reports the consumption and pod capacity of nodegroups", func() {
+		report := Compute(inputs, 80)
+		Expect(report.NodeGroups).To(Equal([]*NodeGroupUsage{
+			{Name: "ng-1", Nodes: 2, ENIs: 3, IPs: 30, PodCapacity: 58},
+			{Name: "ng-2", Nodes: 1, ENIs: 1, IPs: 10, PodCapacity: 29},
+		}))
+	})
+
+	It("caps the pod capacity with prefix delegation", func() {
+		prefixDelegation := true
+		inputs.PrefixDelegation = &prefixDelegation
+		report := Compute(inputs, 80)
+		Expect(report.NodeGroups[1].PodCapacity).To(Equal(110))
+	})
+})
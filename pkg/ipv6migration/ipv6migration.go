@@ -0,0 +1,250 @@
+// Package ipv6migration previews migrating an IPv4 cluster to IPv6; the IP family of a cluster
+// can't be changed, so it translates the config of the cluster into the config of a new IPv6
+// cluster, and reports what changes and what would stop workloads from working on it
+package ipv6migration
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// NameSuffix is appended to the name of the cluster to name the IPv6 cluster, which runs
+// alongside the IPv4 cluster while workloads are moved to it
+const NameSuffix = "-ipv6"
+
+// Severities of findings
+const (
+	// SeverityChange is a setting the IPv6 config changes to an equivalent one
+	SeverityChange = "change"
+	// SeverityWarning is a setting the IPv6 config drops, or something to act on before
+	// moving workloads
+	SeverityWarning = "warning"
+	// SeverityBlocker is something that doesn't work in an IPv6 cluster
+	SeverityBlocker = "blocker"
+)
+
+// minAddonVersions are the earliest versions of the add-ons that support IPv6
+var minAddonVersions = map[string]string{
+	"vpc-cni":    "1.10.1",
+	"coredns":    "1.8.4",
+	"kube-proxy": "1.21.2",
+}
+
+// Inputs is what is known about the IPv4 cluster besides its config
+type Inputs struct {
+	// Addons are the EKS add-ons installed in the cluster
+	Addons []Addon
+	// AWSNodeVersion is the version of the self-managed VPC CNI, when it isn't an EKS add-on
+	AWSNodeVersion string
+	// LoadBalancerServices are the services the in-tree cloud provider creates load balancers for
+	LoadBalancerServices []string
+	// InTreeStorageClasses are the storage classes provisioning volumes with in-tree plugins
+	InTreeStorageClasses []string
+}
+
+// Addon is an installed EKS add-on
+type Addon struct {
+	Name    string
+	Version string
+}
+
+// Finding is a difference between the IPv4 and the IPv6 cluster
+type Finding struct {
+	Component string `json:"component"`
+	Severity  string `json:"severity"`
+	Message   string `json:"message"`
+}
+
+// Report is the result of previewing the migration
+type Report struct {
+	Cluster string `json:"cluster"`
+	// Ready is true when nothing blocks the migration
+	Ready    bool      `json:"ready"`
+	Findings []Finding `json:"findings"`
+	// Config is the config of the IPv6 cluster
+	Config *api.ClusterConfig `json:"-"`
+}
+
+func (r *Report) add(component, severity, format string, args ...interface{}) {
+	r.Findings = append(r.Findings, Finding{Component: component, Severity: severity, Message: fmt.Sprintf(format, args...)})
+}
+
+// Preview translates the config of an IPv4 cluster into the config of an equivalent IPv6
+// cluster, and reports the settings it changes or drops and the incompatibilities of the
+// workloads of the cluster
+func Preview(cfg *api.ClusterConfig, inputs *Inputs) *Report {
+	report := &Report{Cluster: cfg.Metadata.Name}
+
+	ipv6 := cfg.DeepCopy()
+	ipv6.Status = nil
+	ipv6.Metadata.Name = cfg.Metadata.Name + NameSuffix
+	report.add("metadata.name", SeverityChange, "the IP family of a cluster can't be changed, workloads have to be moved to a new cluster %q", ipv6.Metadata.Name)
+
+	translateCluster(ipv6, report)
+	translateNodeGroups(ipv6, report)
+	checkAddons(inputs, report)
+	checkWorkloads(inputs, report)
+
+	report.Ready = true
+	for _, finding := range report.Findings {
+		if finding.Severity == SeverityBlocker {
+			report.Ready = false
+		}
+	}
+	report.Config = ipv6
+	return report
+}
+
+func translateCluster(cfg *api.ClusterConfig, report *Report) {
+	if ok, err := isMinVersion(api.MinIPv6Version, cfg.Metadata.Version); err != nil || !ok {
+		report.add("metadata.version", SeverityWarning, "Kubernetes %s doesn't support IPv6, the IPv6 cluster runs %s, check the workloads for removed APIs",
+			cfg.Metadata.Version, api.MinIPv6Version)
+		cfg.Metadata.Version = api.MinIPv6Version
+	}
+
+	if cfg.HasServiceIPv4CIDR() {
+		report.add("kubernetesNetworkConfig.serviceIPv4CIDR", SeverityChange, "EKS assigns service IPs from an IPv6 unique local range instead of %s",
+			cfg.KubernetesNetworkConfig.ServiceIPv4CIDR)
+	}
+	cfg.KubernetesNetworkConfig = &api.KubernetesNetworkConfig{IPFamily: api.IPV6Family}
+
+	if !api.IsEnabled(cfg.IAM.WithOIDC) {
+		report.add("iam.withOIDC", SeverityChange, "the VPC CNI assigns IPv6 addresses with an IAM role for its service account, which requires an OIDC provider")
+		cfg.IAM.WithOIDC = api.Enabled()
+	}
+
+	if cfg.VPC.ID != "" || cfg.HasAnySubnets() {
+		report.add("vpc.subnets", SeverityWarning, "the subnets of the existing VPC must have IPv6 CIDR blocks and assign IPv6 addresses on creation")
+	} else if !api.IsEnabled(cfg.VPC.AutoAllocateIPv6) {
+		report.add("vpc.autoAllocateIPv6", SeverityChange, "the subnets get IPv6 CIDR blocks for the addresses of pods")
+		cfg.VPC.AutoAllocateIPv6 = api.Enabled()
+	}
+}
+
+// translateNodeGroups replaces the nodegroups with managed nodegroups, only nodes bootstrapped
+// by EKS are configured with the IPv6 service CIDR
+func translateNodeGroups(cfg *api.ClusterConfig, report *Report) {
+	var managed []*api.ManagedNodeGroup
+	for _, ng := range cfg.NodeGroups {
+		component := fmt.Sprintf("nodeGroups[%s]", ng.Name)
+		if api.IsWindowsImage(ng.AMIFamily) {
+			report.add(component, SeverityBlocker, "Windows nodes don't support IPv6, the nodegroup is left out")
+			continue
+		}
+		report.add(component, SeverityChange, "replaced with a managed nodegroup, unmanaged nodegroups aren't supported with IPv6")
+		if dropped := droppedSettings(ng); len(dropped) > 0 {
+			report.add(component, SeverityWarning, "managed nodegroups don't support %s, the settings are dropped", strings.Join(dropped, ", "))
+		}
+		managed = append(managed, &api.ManagedNodeGroup{
+			Name:             ng.Name,
+			AMIFamily:        api.NodeImageFamilyAmazonLinux2,
+			InstanceType:     ng.InstanceType,
+			InstanceSelector: ng.InstanceSelector,
+			ScalingConfig: &api.ScalingConfig{
+				DesiredCapacity: ng.DesiredCapacity,
+				MinSize:         ng.MinSize,
+				MaxSize:         ng.MaxSize,
+			},
+			VolumeSize:        ng.VolumeSize,
+			AvailabilityZones: ng.AvailabilityZones,
+			SSH:               ng.SSH,
+			Labels:            ng.Labels,
+			PrivateNetworking: ng.PrivateNetworking,
+			Tags:              ng.Tags,
+			IAM:               ng.IAM,
+		})
+	}
+	cfg.NodeGroups = nil
+
+	for _, ng := range cfg.ManagedNodeGroups {
+		if ng.RequiresLaunchTemplate() {
+			report.add(fmt.Sprintf("managedNodeGroups[%s]", ng.Name), SeverityWarning,
+				"maxPodsPerNode and kubeletExtraConfig require a custom bootstrap, which isn't supported with IPv6, the settings are dropped")
+			ng.MaxPodsPerNode = 0
+			ng.KubeletExtraConfig = nil
+		}
+	}
+	cfg.ManagedNodeGroups = append(cfg.ManagedNodeGroups, managed...)
+}
+
+// droppedSettings returns the settings of an unmanaged nodegroup a managed nodegroup doesn't have
+func droppedSettings(ng *api.NodeGroup) []string {
+	var dropped []string
+	set := func(isSet bool, field string) {
+		if isSet {
+			dropped = append(dropped, field)
+		}
+	}
+	set(ng.AMI != "", "ami")
+	set(ng.AMIFamily != "" && ng.AMIFamily != api.NodeImageFamilyAmazonLinux2, "amiFamily")
+	set(ng.InstancesDistribution != nil, "instancesDistribution")
+	set(ng.SecurityGroups != nil && len(ng.SecurityGroups.AttachIDs) > 0, "securityGroups.attachIDs")
+	set(len(ng.Taints) > 0, "taints")
+	set(len(ng.ClassicLoadBalancerNames) > 0, "classicLoadBalancerNames")
+	set(len(ng.TargetGroupARNs) > 0, "targetGroupARNs")
+	set(len(ng.AdditionalVolumes) > 0, "additionalVolumes")
+	set(len(ng.PreBootstrapCommands) > 0, "preBootstrapCommands")
+	set(ng.OverrideBootstrapCommand != nil, "overrideBootstrapCommand")
+	set(ng.ClusterDNS != "", "clusterDNS")
+	set(ng.MaxPodsPerNode != 0, "maxPodsPerNode")
+	set(ng.KubeletExtraConfig != nil, "kubeletExtraConfig")
+	set(ng.InstanceStore != nil, "instanceStore")
+	return dropped
+}
+
+func checkAddons(inputs *Inputs, report *Report) {
+	installed := map[string]string{}
+	for _, addon := range inputs.Addons {
+		installed[addon.Name] = addon.Version
+	}
+	if _, ok := installed["vpc-cni"]; !ok && inputs.AWSNodeVersion != "" {
+		installed["vpc-cni"] = inputs.AWSNodeVersion
+	}
+
+	var names []string
+	for name := range installed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		minVersion, ok := minAddonVersions[name]
+		if !ok {
+			continue
+		}
+		version := installed[name]
+		if ok, err := isMinVersion(minVersion, version); err != nil || !ok {
+			report.add("addon "+name, SeverityWarning, "%s %s doesn't support IPv6, the IPv6 cluster needs %s or later", name, version, minVersion)
+		}
+	}
+}
+
+func checkWorkloads(inputs *Inputs, report *Report) {
+	for _, service := range inputs.LoadBalancerServices {
+		report.add("service "+service, SeverityBlocker, "the in-tree cloud provider can't route load balancers to IPv6 pods, use the AWS Load Balancer Controller with IP targets")
+	}
+	for _, storageClass := range inputs.InTreeStorageClasses {
+		report.add("storageclass "+storageClass, SeverityWarning, "provisions volumes with the in-tree EBS plugin, use the EBS CSI driver in the IPv6 cluster")
+	}
+}
+
+// isMinVersion reports whether version is at least minVersion, ignoring the build suffixes of
+// EKS versions like v1.10.1-eksbuild.1 that semver would take for pre-releases
+func isMinVersion(minVersion, version string) (bool, error) {
+	if i := strings.Index(version, "-"); i > 0 {
+		version = version[:i]
+	}
+	v, err := semver.ParseTolerant(version)
+	if err != nil {
+		return false, err
+	}
+	min, err := semver.ParseTolerant(minVersion)
+	if err != nil {
+		return false, err
+	}
+	return v.GE(min), nil
+}
@@ -0,0 +1,131 @@
+package ipv6migration_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/ipv6migration"
+)
+
+var _ = Describe("IPv6 migration", func() {
+	var cfg *api.ClusterConfig
+
+	BeforeEach(func() {
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "cluster-1"
+		cfg.Metadata.Version = "1.21"
+		cfg.KubernetesNetworkConfig = &api.KubernetesNetworkConfig{ServiceIPv4CIDR: "172.16.0.0/16"}
+	})
+
+	findings := func(report *Report, severity string) []string {
+		var components []string
+		for _, finding := range report.Findings {
+			if finding.Severity == severity {
+				components = append(components, finding.Component)
+			}
+		}
+		return components
+	}
+
+	It("translates the config into a valid IPv6 config", func() {
+		ng := cfg.NewNodeGroup()
+		ng.Name = "ng-1"
+		ng.InstanceType = "m5.large"
+		ng.Taints = map[string]string{"dedicated": "true:NoSchedule"}
+
+		report := Preview(cfg, &Inputs{})
+		Expect(report.Ready).To(BeTrue())
+
+		ipv6 := report.Config
+		Expect(ipv6.Metadata.Name).To(Equal("cluster-1-ipv6"))
+		Expect(ipv6.IPv6Enabled()).To(BeTrue())
+		Expect(ipv6.HasServiceIPv4CIDR()).To(BeFalse())
+		Expect(api.IsEnabled(ipv6.IAM.WithOIDC)).To(BeTrue())
+		Expect(api.IsEnabled(ipv6.VPC.AutoAllocateIPv6)).To(BeTrue())
+		Expect(ipv6.NodeGroups).To(BeEmpty())
+		Expect(ipv6.ManagedNodeGroups).To(HaveLen(1))
+		Expect(ipv6.ManagedNodeGroups[0].Name).To(Equal("ng-1"))
+		Expect(ipv6.ManagedNodeGroups[0].InstanceType).To(Equal("m5.large"))
+		Expect(api.ValidateClusterConfig(ipv6)).To(Succeed())
+
+		Expect(findings(report, SeverityChange)).To(ContainElement("kubernetesNetworkConfig.serviceIPv4CIDR"))
+		Expect(findings(report, SeverityWarning)).To(ConsistOf("nodeGroups[ng-1]"))
+
+		// the IPv4 config is left as it is
+		Expect(cfg.Metadata.Name).To(Equal("cluster-1"))
+		Expect(cfg.NodeGroups).To(HaveLen(1))
+	})
+
+	It("leaves out Windows nodegroups", func() {
+		ng := cfg.NewNodeGroup()
+		ng.Name = "windows"
+		ng.AMIFamily = api.NodeImageFamilyWindowsServer2019CoreContainer
+
+		report := Preview(cfg, &Inputs{})
+		Expect(report.Ready).To(BeFalse())
+		Expect(findings(report, SeverityBlocker)).To(ConsistOf("nodeGroups[windows]"))
+		Expect(report.Config.ManagedNodeGroups).To(BeEmpty())
+	})
+
+	It("reports add-on versions without IPv6 support and in-tree plugins", func() {
+		inputs := &Inputs{
+			Addons: []Addon{
+				{Name: "vpc-cni", Version: "v1.9.0-eksbuild.1"},
+				{Name: "coredns", Version: "v1.8.4-eksbuild.1"},
+			},
+			AWSNodeVersion:       "v1.7.5",
+			LoadBalancerServices: []string{"default/web"},
+			InTreeStorageClasses: []string{"gp2"},
+		}
+		report := Preview(cfg, inputs)
+		Expect(report.Ready).To(BeFalse())
+		Expect(findings(report, SeverityBlocker)).To(ConsistOf("service default/web"))
+		Expect(findings(report, SeverityWarning)).To(ConsistOf("addon vpc-cni", "storageclass gp2"))
+	})
+
+	It("reads the cluster inputs from Kubernetes", func() {
+		clientSet := fake.NewSimpleClientset(
+			&appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "aws-node", Namespace: metav1.NamespaceSystem},
+				Spec: appsv1.DaemonSetSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{Name: "aws-node", Image: "602401143452.dkr.ecr.us-west-2.amazonaws.com/amazon-k8s-cni:v1.6.3"},
+							},
+						},
+					},
+				},
+			},
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+			},
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "api",
+					Namespace:   "default",
+					Annotations: map[string]string{"service.beta.kubernetes.io/aws-load-balancer-type": "external"},
+				},
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+			},
+			&storagev1.StorageClass{
+				ObjectMeta:  metav1.ObjectMeta{Name: "gp2"},
+				Provisioner: "kubernetes.io/aws-ebs",
+			},
+		)
+
+		inputs := &Inputs{}
+		Expect(AddClusterInputs(clientSet, inputs)).To(Succeed())
+		Expect(inputs.AWSNodeVersion).To(Equal("v1.6.3"))
+		Expect(inputs.LoadBalancerServices).To(ConsistOf("default/web"))
+		Expect(inputs.InTreeStorageClasses).To(ConsistOf("gp2"))
+	})
+})
@@ -0,0 +1,69 @@
+package ipv6migration
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+)
+
+const (
+	awsNodeName = "aws-node"
+	// lbTypeAnnotation hands services over to the AWS Load Balancer Controller when set to
+	// external or nlb-ip, the in-tree cloud provider handles all other services
+	lbTypeAnnotation = "service.beta.kubernetes.io/aws-load-balancer-type"
+	inTreeEBSPlugin  = "kubernetes.io/aws-ebs"
+)
+
+// AddClusterInputs adds the version of the self-managed VPC CNI, the load balancers of the
+// in-tree cloud provider and the storage classes of in-tree volume plugins to inputs
+func AddClusterInputs(clientSet kubernetes.Interface, inputs *Inputs) error {
+	awsNode, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(awsNodeName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return errors.Wrapf(err, "getting %q", awsNodeName)
+	default:
+		for _, container := range awsNode.Spec.Template.Spec.Containers {
+			if container.Name == awsNodeName {
+				inputs.AWSNodeVersion = imageTag(container.Image)
+			}
+		}
+	}
+
+	services, err := clientSet.CoreV1().Services(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing services")
+	}
+	for _, service := range services.Items {
+		if service.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			continue
+		}
+		if lbType := service.Annotations[lbTypeAnnotation]; lbType == "external" || lbType == "nlb-ip" {
+			continue
+		}
+		inputs.LoadBalancerServices = append(inputs.LoadBalancerServices, service.Namespace+"/"+service.Name)
+	}
+
+	storageClasses, err := clientSet.StorageV1().StorageClasses().List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing storage classes")
+	}
+	for _, storageClass := range storageClasses.Items {
+		if storageClass.Provisioner == inTreeEBSPlugin {
+			inputs.InTreeStorageClasses = append(inputs.InTreeStorageClasses, storageClass.Name)
+		}
+	}
+	return nil
+}
+
+func imageTag(image string) string {
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return ""
+}
@@ -1,12 +1,12 @@
 package kubernetes
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/blang/semver"
 	jsonpatch "github.com/evanphx/json-patch"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 
 	corev1 "k8s.io/api/core/v1"
@@ -24,6 +24,9 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/utils/retry"
 )
 
 // Interface is an alias to avoid having to import k8s.io/client-go/kubernetes
@@ -441,21 +444,15 @@ func (r *RawResource) waitForDeletion() error {
 	// Wait for the resource's deletion, typically to avoid "races" as much as
 	// possible on eksctl's side, as objects may be still "TERMINATING" while
 	// eksctl then tries to create them again.
-	waitingTime := maxWaitingTime
-	checkInterval := 1 * time.Second
-	for waitingTime > 0 {
+	poller := retry.Poller{Interval: time.Second, Factor: 2}
+	err := poller.PollWithTimeout(maxWaitingTime, func() (bool, error) {
 		_, exists, err := r.Get()
-		if err != nil {
-			return err
-		}
-		if !exists {
-			return nil
-		}
-		time.Sleep(checkInterval)
-		waitingTime = waitingTime - checkInterval
-		checkInterval = 2 * checkInterval
+		return !exists, err
+	})
+	if err == context.DeadlineExceeded {
+		return fmt.Errorf("waited for %v's deletion, but could not confirm it within %v", r, maxWaitingTime)
 	}
-	return fmt.Errorf("waited for %v's deletion, but could not confirm it within %v", r, maxWaitingTime)
+	return err
 }
 
 // Exists checks if this Kubernetes resource exists or not, and returns true if
@@ -0,0 +1,155 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/utils/retry"
+)
+
+const readinessCheckInterval = 5 * time.Second
+
+// ReadinessGates are the conditions a cluster has to meet to be considered ready
+type ReadinessGates struct {
+	// Nodes requires all nodes to be Ready
+	Nodes bool
+	// SystemComponents requires the rollouts of coredns, kube-proxy and aws-node to be complete
+	SystemComponents bool
+	// Deployments must all be available with their latest spec rolled out
+	Deployments []types.NamespacedName
+}
+
+// ParseNamespacedNames parses a list of <namespace>/<name> strings
+func ParseNamespacedNames(names []string) ([]types.NamespacedName, error) {
+	var parsed []types.NamespacedName
+	for _, name := range names {
+		parts := strings.Split(name, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid name %q, expected <namespace>/<name>", name)
+		}
+		parsed = append(parsed, types.NamespacedName{Namespace: parts[0], Name: parts[1]})
+	}
+	return parsed, nil
+}
+
+// IsEmpty returns true if no gates are enabled
+func (g ReadinessGates) IsEmpty() bool {
+	return !g.Nodes && !g.SystemComponents && len(g.Deployments) == 0
+}
+
+// WaitForReadinessGates polls the cluster until all gates pass, returning an error
+// naming the gates that are still pending once timeout expires
+func WaitForReadinessGates(clientSet Interface, gates ReadinessGates, timeout time.Duration) error {
+	logger.Info("waiting up to %s for the cluster to pass the readiness gates", timeout)
+	var pending []string
+	poller := retry.Poller{Interval: readinessCheckInterval}
+	err := poller.PollWithTimeout(timeout, func() (bool, error) {
+		var err error
+		pending, err = pendingReadinessGates(clientSet, gates)
+		if err != nil {
+			return false, err
+		}
+		if len(pending) > 0 {
+			logger.Debug("pending readiness gates: %s", strings.Join(pending, "; "))
+		}
+		return len(pending) == 0, nil
+	})
+	if err == context.DeadlineExceeded {
+		return fmt.Errorf("timed out (after %s) waiting for readiness gates: %s", timeout, strings.Join(pending, "; "))
+	}
+	if err != nil {
+		return err
+	}
+	logger.Info("all readiness gates passed")
+	return nil
+}
+
+// pendingReadinessGates returns a description of each gate that doesn't pass yet
+func pendingReadinessGates(clientSet Interface, gates ReadinessGates) ([]string, error) {
+	var pending []string
+
+	if gates.Nodes {
+		nodes, err := clientSet.CoreV1().Nodes().List(metav1.ListOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "listing nodes")
+		}
+		for _, node := range nodes.Items {
+			if !isNodeReady(&node) {
+				pending = append(pending, fmt.Sprintf("node %q is not ready", node.Name))
+			}
+		}
+	}
+
+	var deployments []types.NamespacedName
+	if gates.SystemComponents {
+		for _, name := range []string{"aws-node", "kube-proxy"} {
+			ds, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceSystem).Get(name, metav1.GetOptions{})
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					pending = append(pending, fmt.Sprintf("daemonset \"%s/%s\" does not exist", metav1.NamespaceSystem, name))
+					continue
+				}
+				return nil, errors.Wrapf(err, "getting daemonset \"%s/%s\"", metav1.NamespaceSystem, name)
+			}
+			if !isDaemonSetRolledOut(ds) {
+				pending = append(pending, fmt.Sprintf("daemonset \"%s/%s\" is not rolled out", metav1.NamespaceSystem, name))
+			}
+		}
+		deployments = append(deployments, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: "coredns"})
+	}
+	deployments = append(deployments, gates.Deployments...)
+
+	for _, name := range deployments {
+		deployment, err := clientSet.AppsV1().Deployments(name.Namespace).Get(name.Name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				pending = append(pending, fmt.Sprintf("deployment %q does not exist", name))
+				continue
+			}
+			return nil, errors.Wrapf(err, "getting deployment %q", name)
+		}
+		if !isDeploymentRolledOut(deployment) {
+			pending = append(pending, fmt.Sprintf("deployment %q is not rolled out", name))
+		}
+	}
+
+	return pending, nil
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func isDeploymentRolledOut(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+	return status.ObservedGeneration >= deployment.Generation &&
+		status.UpdatedReplicas == replicas &&
+		status.Replicas == replicas &&
+		status.AvailableReplicas == replicas
+}
+
+func isDaemonSetRolledOut(ds *appsv1.DaemonSet) bool {
+	status := ds.Status
+	return status.ObservedGeneration >= ds.Generation &&
+		status.UpdatedNumberScheduled == status.DesiredNumberScheduled &&
+		status.NumberAvailable == status.DesiredNumberScheduled
+}
@@ -0,0 +1,103 @@
+package kubernetes
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var _ = Describe("Readiness gates", func() {
+	var (
+		clientSet *fake.Clientset
+		replicas  = int32(2)
+	)
+
+	node := func(name string, status corev1.ConditionStatus) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+			},
+		}
+	}
+
+	deployment := func(namespace, name string, available int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Generation: 1},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: 1,
+				Replicas:           replicas,
+				UpdatedReplicas:    replicas,
+				AvailableReplicas:  available,
+			},
+		}
+	}
+
+	daemonSet := func(name string, available int32) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceSystem, Name: name, Generation: 2},
+			Status: appsv1.DaemonSetStatus{
+				ObservedGeneration:     2,
+				DesiredNumberScheduled: 3,
+				UpdatedNumberScheduled: 3,
+				NumberAvailable:        available,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		clientSet = fake.NewSimpleClientset(
+			node("node-1", corev1.ConditionTrue),
+			node("node-2", corev1.ConditionFalse),
+			daemonSet("aws-node", 3),
+			daemonSet("kube-proxy", 2),
+			deployment(metav1.NamespaceSystem, "coredns", 2),
+			deployment("apps", "web", 1),
+		)
+	})
+
+	It("parses deployment names", func() {
+		names, err := ParseNamespacedNames([]string{"apps/web"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names).To(Equal([]types.NamespacedName{{Namespace: "apps", Name: "web"}}))
+
+		_, err = ParseNamespacedNames([]string{"web"})
+		Expect(err).To(MatchError(`invalid name "web", expected <namespace>/<name>`))
+	})
+
+	It("reports pending gates", func() {
+		pending, err := pendingReadinessGates(clientSet, ReadinessGates{
+			Nodes:            true,
+			SystemComponents: true,
+			Deployments: []types.NamespacedName{
+				{Namespace: "apps", Name: "web"},
+				{Namespace: "apps", Name: "api"},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pending).To(ConsistOf(
+			`node "node-2" is not ready`,
+			`daemonset "kube-system/kube-proxy" is not rolled out`,
+			`deployment "apps/web" is not rolled out`,
+			`deployment "apps/api" does not exist`,
+		))
+	})
+
+	It("passes once everything is ready", func() {
+		pending, err := pendingReadinessGates(clientSet, ReadinessGates{
+			SystemComponents: false,
+			Deployments:      []types.NamespacedName{{Namespace: metav1.NamespaceSystem, Name: "coredns"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pending).To(BeEmpty())
+
+		Expect(WaitForReadinessGates(clientSet, ReadinessGates{Nodes: true}, 0)).To(MatchError(
+			`timed out (after 0s) waiting for readiness gates: node "node-2" is not ready`))
+	})
+})
@@ -1,11 +1,17 @@
 package kubernetes
 
 import (
-	"github.com/kris-nova/logger"
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/utils/retry"
 )
 
 // NewServiceAccount creates a corev1.ServiceAccount object using the provided meta.
@@ -38,6 +44,13 @@ func CheckServiceAccountExists(clientSet Interface, meta metav1.ObjectMeta) (boo
 // labels and annotations will get added, all user-set label and annotation keys that are not set in
 // meta will be retained
 func MaybeCreateServiceAccountOrUpdateMetadata(clientSet Interface, meta metav1.ObjectMeta) error {
+	return MaybeCreateServiceAccountOrUpdate(clientSet, NewServiceAccount(meta))
+}
+
+// MaybeCreateServiceAccountOrUpdate is like MaybeCreateServiceAccountOrUpdateMetadata, it also
+// sets automountServiceAccountToken of an existing serviceaccount when serviceAccount sets it
+func MaybeCreateServiceAccountOrUpdate(clientSet Interface, serviceAccount *corev1.ServiceAccount) error {
+	meta := serviceAccount.ObjectMeta
 	name := meta.Namespace + "/" + meta.Name
 
 	if err := MaybeCreateNamespace(clientSet, meta.Namespace); err != nil {
@@ -48,7 +61,7 @@ func MaybeCreateServiceAccountOrUpdateMetadata(clientSet Interface, meta metav1.
 		return err
 	}
 	if !exists {
-		_, err = clientSet.CoreV1().ServiceAccounts(meta.Namespace).Create(NewServiceAccount(meta))
+		_, err = clientSet.CoreV1().ServiceAccounts(meta.Namespace).Create(serviceAccount)
 		if err != nil {
 			return err
 		}
@@ -67,8 +80,9 @@ func MaybeCreateServiceAccountOrUpdateMetadata(clientSet Interface, meta metav1.
 
 	mergeMetadata := func(src, dst map[string]string) {
 		for key, value := range src {
-			currentValue, ok := dst[key]
-			updateRequired = !ok || ok && currentValue != value
+			if currentValue, ok := dst[key]; !ok || currentValue != value {
+				updateRequired = true
+			}
 			dst[key] = value
 		}
 	}
@@ -83,6 +97,13 @@ func MaybeCreateServiceAccountOrUpdateMetadata(clientSet Interface, meta metav1.
 	}
 	mergeMetadata(meta.Labels, current.Labels)
 
+	if automount := serviceAccount.AutomountServiceAccountToken; automount != nil {
+		if current.AutomountServiceAccountToken == nil || *current.AutomountServiceAccountToken != *automount {
+			updateRequired = true
+		}
+		current.AutomountServiceAccountToken = automount
+	}
+
 	if !updateRequired {
 		logger.Info("serviceaccount %q is already up-to-date", name)
 		return nil
@@ -113,3 +134,42 @@ func MaybeDeleteServiceAccount(clientSet Interface, meta metav1.ObjectMeta) erro
 	logger.Info("deleted serviceaccount %q", name)
 	return nil
 }
+
+// WaitForServiceAccountToken returns the token of the service account token secret that
+// the token controller creates for the given serviceaccount, waiting up to timeout for it
+func WaitForServiceAccountToken(clientSet Interface, meta metav1.ObjectMeta, timeout time.Duration) (string, error) {
+	name := meta.Namespace + "/" + meta.Name
+	var token string
+	err := retry.DefaultPoller.PollWithTimeout(timeout, func() (bool, error) {
+		var err error
+		token, err = getServiceAccountToken(clientSet, meta)
+		if err != nil {
+			return false, errors.Wrapf(err, "getting token of serviceaccount %q", name)
+		}
+		return token != "", nil
+	})
+	if err == context.DeadlineExceeded {
+		return "", fmt.Errorf("timed out waiting for token of serviceaccount %q", name)
+	}
+	return token, err
+}
+
+func getServiceAccountToken(clientSet Interface, meta metav1.ObjectMeta) (string, error) {
+	sa, err := clientSet.CoreV1().ServiceAccounts(meta.Namespace).Get(meta.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, ref := range sa.Secrets {
+		secret, err := clientSet.CoreV1().Secrets(meta.Namespace).Get(ref.Name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return "", err
+		}
+		if secret.Type == corev1.SecretTypeServiceAccountToken && len(secret.Data[corev1.ServiceAccountTokenKey]) > 0 {
+			return string(secret.Data[corev1.ServiceAccountTokenKey]), nil
+		}
+	}
+	return "", nil
+}
@@ -2,10 +2,12 @@ package kubernetes_test
 
 import (
 	"encoding/json"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 
@@ -102,6 +104,32 @@ var _ = Describe("Kubernetes serviceaccount object helpers", func() {
 		}
 	})
 
+	It("can create serviceaccount with automountServiceAccountToken, and update it", func() {
+		automount := false
+		sa := NewServiceAccount(metav1.ObjectMeta{Name: "sa-3", Namespace: "ns-3"})
+		sa.AutomountServiceAccountToken = &automount
+
+		Expect(MaybeCreateServiceAccountOrUpdate(clientSet, sa)).To(Succeed())
+
+		resp, err := clientSet.CoreV1().ServiceAccounts(sa.Namespace).Get(sa.Name, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*resp.AutomountServiceAccountToken).To(BeFalse())
+
+		automount = true
+		Expect(MaybeCreateServiceAccountOrUpdate(clientSet, NewServiceAccount(sa.ObjectMeta))).To(Succeed())
+
+		resp, err = clientSet.CoreV1().ServiceAccounts(sa.Namespace).Get(sa.Name, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*resp.AutomountServiceAccountToken).To(BeFalse())
+
+		sa.AutomountServiceAccountToken = &automount
+		Expect(MaybeCreateServiceAccountOrUpdate(clientSet, sa)).To(Succeed())
+
+		resp, err = clientSet.CoreV1().ServiceAccounts(sa.Namespace).Get(sa.Name, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*resp.AutomountServiceAccountToken).To(BeTrue())
+	})
+
 	It("can delete existsing service account, and doesn't fail if it doesn't exist", func() {
 		sa := metav1.ObjectMeta{Name: "sa-2", Namespace: "ns-2"}
 
@@ -128,4 +156,33 @@ var _ = Describe("Kubernetes serviceaccount object helpers", func() {
 		Expect(err).ToNot(HaveOccurred())
 		Expect(ok).To(BeFalse())
 	})
+
+	It("can get the token of a serviceaccount", func() {
+		sa := metav1.ObjectMeta{Name: "ci", Namespace: "ns-1"}
+
+		_, err = WaitForServiceAccountToken(clientSet, sa, 0)
+		Expect(err).To(HaveOccurred())
+
+		Expect(MaybeCreateServiceAccountOrUpdateMetadata(clientSet, sa)).To(Succeed())
+
+		_, err = WaitForServiceAccountToken(clientSet, sa, 0)
+		Expect(err).To(MatchError(`timed out waiting for token of serviceaccount "ns-1/ci"`))
+
+		_, err = clientSet.CoreV1().Secrets(sa.Namespace).Create(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ci-token-abcde", Namespace: sa.Namespace},
+			Type:       corev1.SecretTypeServiceAccountToken,
+			Data:       map[string][]byte{corev1.ServiceAccountTokenKey: []byte("secret-token")},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		current, err := clientSet.CoreV1().ServiceAccounts(sa.Namespace).Get(sa.Name, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		current.Secrets = []corev1.ObjectReference{{Name: "missing"}, {Name: "ci-token-abcde"}}
+		_, err = clientSet.CoreV1().ServiceAccounts(sa.Namespace).Update(current)
+		Expect(err).ToNot(HaveOccurred())
+
+		token, err := WaitForServiceAccountToken(clientSet, sa, time.Minute)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token).To(Equal("secret-token"))
+	})
 })
@@ -0,0 +1,230 @@
+// Package logger writes the logs of eksctl, either as human readable text or as
+// JSON records that can be ingested by log processors, e.g. in CI pipelines
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	lol "github.com/kris-nova/lolgopher"
+)
+
+// Logger logs a formatted message
+type Logger func(format string, a ...interface{})
+
+// Labels prefixed to messages in the text format
+const (
+	AlwaysLabel   = "✿"
+	CriticalLabel = "✖"
+	DebugLabel    = "▶"
+	InfoLabel     = "ℹ"
+	SuccessLabel  = "✔"
+	WarningLabel  = "!"
+)
+
+// Levels messages are logged at; a message is logged if Level is at least its level
+const (
+	CriticalLevel = 1
+	WarningLevel  = 2
+	InfoLevel     = 3
+	DebugLevel    = 4
+)
+
+// Formats of the logs
+const (
+	TextFormat = "text"
+	JSONFormat = "json"
+)
+
+var (
+	// Level is the maximum level of messages to log
+	Level = WarningLevel
+	// Color enables colorized text logs
+	Color = true
+	// Fabulous enables rainbow text logs
+	Fabulous = false
+	// FabulousWriter is the writer of rainbow text logs
+	FabulousWriter io.Writer = lol.NewLolWriter()
+	// Timestamps prefixes text logs with the time
+	Timestamps = true
+	// Format is the format of the logs, either "text" or "json"
+	Format = TextFormat
+	// Redact replaces AWS account IDs, including those in ARNs, with a placeholder
+	Redact = false
+	// DebugScopes limits debug messages to the given subsystems, unless empty
+	DebugScopes []string
+
+	mu sync.Mutex
+)
+
+// levelNames are the names of the levels in JSON records
+var levelNames = map[int]string{
+	CriticalLevel: "error",
+	WarningLevel:  "warning",
+	InfoLevel:     "info",
+	DebugLevel:    "debug",
+}
+
+// ParseLevel parses the name of a level, as used in JSON records
+func ParseLevel(name string) (int, error) {
+	for level, levelName := range levelNames {
+		if name == levelName {
+			return level, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q, valid levels are: debug, info, warning, error", name)
+}
+
+// accountIDPattern matches AWS account IDs, which are 12 digits
+var accountIDPattern = regexp.MustCompile(`\b\d{12}\b`)
+
+const redactedAccountID = "<redacted>"
+
+// Scoped logs messages of a subsystem, e.g. "cfn" or "nodegroup"
+type Scoped struct {
+	scope string
+}
+
+// WithScope returns a logger for the subsystem
+func WithScope(scope string) Scoped {
+	return Scoped{scope: scope}
+}
+
+// Always logs a message regardless of Level
+func (s Scoped) Always(format string, a ...interface{}) {
+	s.log(0, AlwaysLabel, color.GreenString, format, a...)
+}
+
+// Critical logs an error
+func (s Scoped) Critical(format string, a ...interface{}) {
+	s.log(CriticalLevel, CriticalLabel, color.RedString, format, a...)
+}
+
+// Warning logs a warning
+func (s Scoped) Warning(format string, a ...interface{}) {
+	s.log(WarningLevel, WarningLabel, color.GreenString, format, a...)
+}
+
+// Info logs an informational message
+func (s Scoped) Info(format string, a ...interface{}) {
+	s.log(InfoLevel, InfoLabel, color.CyanString, format, a...)
+}
+
+// Success logs the successful completion of an operation, at the info level
+func (s Scoped) Success(format string, a ...interface{}) {
+	s.log(InfoLevel, SuccessLabel, color.CyanString, format, a...)
+}
+
+// Debug logs a debug message
+func (s Scoped) Debug(format string, a ...interface{}) {
+	if len(DebugScopes) > 0 && !s.inDebugScopes() {
+		return
+	}
+	s.log(DebugLevel, DebugLabel, color.GreenString, format, a...)
+}
+
+func (s Scoped) inDebugScopes() bool {
+	for _, scope := range DebugScopes {
+		if scope == s.scope {
+			return true
+		}
+	}
+	return false
+}
+
+// log writes the message to os.Stdout, or to the io.Writer passed as the last argument
+func (s Scoped) log(level int, label string, colorize func(string, ...interface{}) string, format string, a ...interface{}) {
+	if Level < level {
+		return
+	}
+	a, w := extractWriter(a)
+	msg := fmt.Sprintf(format, a...)
+	if Redact {
+		msg = accountIDPattern.ReplaceAllString(msg, redactedAccountID)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if Format == JSONFormat {
+		writeJSON(w, level, s.scope, msg)
+		return
+	}
+
+	if !strings.Contains(msg, "\n") {
+		msg += "\n"
+	}
+	msg = fmt.Sprintf("[%s]  %s", label, msg)
+	if Timestamps {
+		msg = fmt.Sprintf("%s %s", time.Now().Format(time.RFC3339), msg)
+	}
+	if w == os.Stdout {
+		if Color {
+			w = color.Output
+			msg = colorize(msg)
+		} else if Fabulous {
+			w = FabulousWriter
+		}
+	}
+	fmt.Fprint(w, msg)
+}
+
+type record struct {
+	Time    string `json:"time"`
+	Level   string `json:"level"`
+	Scope   string `json:"scope,omitempty"`
+	Message string `json:"msg"`
+}
+
+func writeJSON(w io.Writer, level int, scope, msg string) {
+	levelName, ok := levelNames[level]
+	if !ok {
+		levelName = levelNames[InfoLevel]
+	}
+	data, err := json.Marshal(record{
+		Time:    time.Now().Format(time.RFC3339),
+		Level:   levelName,
+		Scope:   scope,
+		Message: strings.TrimRight(msg, "\n"),
+	})
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(w, string(data))
+}
+
+func extractWriter(a []interface{}) ([]interface{}, io.Writer) {
+	if n := len(a); n > 0 {
+		if w, ok := a[n-1].(io.Writer); ok {
+			return a[:n-1], w
+		}
+	}
+	return a, os.Stdout
+}
+
+var global = Scoped{}
+
+// Always logs a message regardless of Level
+func Always(format string, a ...interface{}) { global.Always(format, a...) }
+
+// Critical logs an error
+func Critical(format string, a ...interface{}) { global.Critical(format, a...) }
+
+// Warning logs a warning
+func Warning(format string, a ...interface{}) { global.Warning(format, a...) }
+
+// Info logs an informational message
+func Info(format string, a ...interface{}) { global.Info(format, a...) }
+
+// Success logs the successful completion of an operation, at the info level
+func Success(format string, a ...interface{}) { global.Success(format, a...) }
+
+// Debug logs a debug message
+func Debug(format string, a ...interface{}) { global.Debug(format, a...) }
@@ -0,0 +1,105 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+var _ = Describe("logger", func() {
+	var (
+		buf *bytes.Buffer
+
+		level      int
+		format     string
+		redact     bool
+		timestamps bool
+	)
+
+	type record struct {
+		Time    string `json:"time"`
+		Level   string `json:"level"`
+		Scope   string `json:"scope"`
+		Message string `json:"msg"`
+	}
+
+	records := func() []record {
+		var records []record
+		decoder := json.NewDecoder(buf)
+		for decoder.More() {
+			var r record
+			Expect(decoder.Decode(&r)).To(Succeed())
+			Expect(r.Time).NotTo(BeEmpty())
+			r.Time = ""
+			records = append(records, r)
+		}
+		return records
+	}
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		level, format, redact, timestamps = logger.Level, logger.Format, logger.Redact, logger.Timestamps
+		logger.Level = logger.InfoLevel
+		logger.Timestamps = false
+	})
+
+	AfterEach(func() {
+		logger.Level, logger.Format, logger.Redact, logger.Timestamps = level, format, redact, timestamps
+		logger.DebugScopes = nil
+	})
+
+	It("writes labelled text", func() {
+		logger.Info("creating cluster %q", "cluster-1", buf)
+		logger.Critical("%s\n", "failed", buf)
+		logger.Debug("not logged", buf)
+
+		Expect(buf.String()).To(Equal("[ℹ]  creating cluster \"cluster-1\"\n[✖]  failed\n"))
+	})
+
+	It("writes JSON records with consistent levels", func() {
+		logger.Format = logger.JSONFormat
+		logger.Level = logger.DebugLevel
+
+		logger.Success("created cluster", buf)
+		logger.Critical("%s\n", "failed", buf)
+		logger.WithScope("cfn").Debug("waiting for stack", buf)
+
+		Expect(records()).To(Equal([]record{
+			{Level: "info", Message: "created cluster"},
+			{Level: "error", Message: "failed"},
+			{Level: "debug", Scope: "cfn", Message: "waiting for stack"},
+		}))
+	})
+
+	It("limits debug messages to scopes", func() {
+		logger.Format = logger.JSONFormat
+		logger.Level = logger.DebugLevel
+		logger.DebugScopes = []string{"nodegroup"}
+
+		logger.WithScope("cfn").Debug("waiting for stack", buf)
+		logger.WithScope("cfn").Info("created stack", buf)
+		logger.WithScope("nodegroup").Debug("bootstrapping", buf)
+
+		Expect(records()).To(Equal([]record{
+			{Level: "info", Scope: "cfn", Message: "created stack"},
+			{Level: "debug", Scope: "nodegroup", Message: "bootstrapping"},
+		}))
+	})
+
+	It("redacts account IDs", func() {
+		logger.Redact = true
+		logger.Info("using role %q", "arn:aws:iam::123456789012:role/eksctl", buf)
+
+		Expect(buf.String()).To(Equal("[ℹ]  using role \"arn:aws:iam::<redacted>:role/eksctl\"\n"))
+	})
+
+	It("parses levels", func() {
+		Expect(logger.ParseLevel("warning")).To(Equal(logger.WarningLevel))
+		_, err := logger.ParseLevel("trace")
+		Expect(err).To(MatchError(ContainSubstring(`unknown log level "trace"`)))
+	})
+})
@@ -0,0 +1,112 @@
+package managed
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+const (
+	launchTemplateVersionPath = "Resources.ManagedNodeGroup.Properties.LaunchTemplate.Version"
+	forceUpdateEnabledPath    = "Resources.ManagedNodeGroup.Properties.ForceUpdateEnabled"
+)
+
+// releaseVersionRegex matches release versions like 1.14.8-20191213
+var releaseVersionRegex = regexp.MustCompile(`^(\d+\.\d+)\.\d+-(\d{8})$`)
+
+// RollbackOptions selects what a managed nodegroup is rolled back to; exactly one of
+// ReleaseVersion and LaunchTemplateVersion is set
+type RollbackOptions struct {
+	// ReleaseVersion is the AMI release version to roll back to, e.g. 1.14.8-20191213
+	ReleaseVersion string
+	// LaunchTemplateVersion is the version of the launch template of the nodegroup to roll back to
+	LaunchTemplateVersion string
+	// ForceUpdate replaces the nodes even when their pods can't be evicted because of a
+	// PodDisruptionBudget
+	ForceUpdate bool
+}
+
+// RollbackNodeGroup rolls a nodegroup back to an earlier AMI release version or launch template
+// version; EKS replaces the nodes one at a time, draining each of them before terminating it
+func (m *Service) RollbackNodeGroup(nodeGroupName string, options RollbackOptions) error {
+	output, err := m.provider.EKS().DescribeNodegroup(&eks.DescribeNodegroupInput{
+		ClusterName:   &m.clusterName,
+		NodegroupName: &nodeGroupName,
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return fmt.Errorf("rollback is only supported for managed nodegroups; could not find one with name %q", nodeGroupName)
+		}
+		return err
+	}
+	nodeGroup := output.Nodegroup
+
+	template, err := m.stackCollection.GetManagedNodeGroupTemplate(nodeGroupName)
+	if err != nil {
+		return err
+	}
+
+	if options.ReleaseVersion != "" {
+		currentReleaseVersion := aws.StringValue(nodeGroup.ReleaseVersion)
+		if options.ReleaseVersion == currentReleaseVersion {
+			logger.Info("nodegroup %q is already at release version %s", nodeGroupName, currentReleaseVersion)
+			return nil
+		}
+		if err := validateRollbackReleaseVersion(options.ReleaseVersion, currentReleaseVersion, aws.StringValue(nodeGroup.Version)); err != nil {
+			return err
+		}
+		template, err = sjson.Set(template, releaseVersionPath, options.ReleaseVersion)
+		if err != nil {
+			return err
+		}
+		logger.Info("rolling back nodegroup %q from release version %s to %s", nodeGroupName, currentReleaseVersion, options.ReleaseVersion)
+	} else {
+		if !gjson.Get(template, launchTemplateVersionPath).Exists() {
+			return fmt.Errorf("nodegroup %q doesn't use a launch template", nodeGroupName)
+		}
+		if version, err := strconv.Atoi(options.LaunchTemplateVersion); err != nil || version < 1 {
+			return fmt.Errorf("invalid launch template version %q", options.LaunchTemplateVersion)
+		}
+		template, err = sjson.Set(template, launchTemplateVersionPath, options.LaunchTemplateVersion)
+		if err != nil {
+			return err
+		}
+		logger.Info("rolling back nodegroup %q to version %s of its launch template", nodeGroupName, options.LaunchTemplateVersion)
+	}
+
+	if options.ForceUpdate {
+		template, err = sjson.Set(template, forceUpdateEnabledPath, true)
+	} else {
+		template, err = sjson.Delete(template, forceUpdateEnabledPath)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := m.stackCollection.UpdateNodeGroupStack(nodeGroupName, template); err != nil {
+		return errors.Wrapf(err, "rolling back nodegroup %q", nodeGroupName)
+	}
+	return nil
+}
+
+// validateRollbackReleaseVersion checks that releaseVersion is an earlier release of the
+// Kubernetes version the nodegroup runs
+func validateRollbackReleaseVersion(releaseVersion, currentReleaseVersion, kubernetesVersion string) error {
+	match := releaseVersionRegex.FindStringSubmatch(releaseVersion)
+	if match == nil {
+		return fmt.Errorf("invalid release version %q, expected a version like 1.14.8-20191213", releaseVersion)
+	}
+	if match[1] != kubernetesVersion {
+		return fmt.Errorf("release version %s is for Kubernetes %s, the nodegroup runs Kubernetes %s", releaseVersion, match[1], kubernetesVersion)
+	}
+	if current := releaseVersionRegex.FindStringSubmatch(currentReleaseVersion); current != nil && match[2] > current[2] {
+		return fmt.Errorf("release version %s is later than the current one, %s; use 'eksctl upgrade nodegroup' to upgrade nodegroups", releaseVersion, currentReleaseVersion)
+	}
+	return nil
+}
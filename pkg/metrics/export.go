@@ -0,0 +1,197 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// WriteSummaryFile writes the summary in the Prometheus text format, as read by
+// the node_exporter textfile collector, if the path ends with ".prom", or as JSON otherwise
+func (r *Recorder) WriteSummaryFile(path string) error {
+	summary := r.Summary()
+
+	var buf bytes.Buffer
+	if strings.HasSuffix(path, ".prom") {
+		writePrometheus(&buf, summary)
+	} else {
+		data, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	}
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return errors.Wrapf(err, "writing metrics summary to %q", path)
+	}
+	return nil
+}
+
+func writePrometheus(w io.Writer, summary Summary) {
+	metric := func(name, help string) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	}
+	sample := func(name string, labels map[string]string, value float64) {
+		var pairs []string
+		for k, v := range labels {
+			pairs = append(pairs, fmt.Sprintf("%s=%q", k, v))
+		}
+		sort.Strings(pairs)
+		fmt.Fprintf(w, "%s{%s} %s\n", name, strings.Join(pairs, ","), strconv.FormatFloat(value, 'f', -1, 64))
+	}
+
+	metric("eksctl_command_duration_seconds", "Duration of the eksctl command.")
+	failed := 0.0
+	if summary.Error != "" {
+		failed = 1
+	}
+	sample("eksctl_command_duration_seconds", map[string]string{"command": summary.Command}, summary.DurationSeconds)
+	metric("eksctl_command_failed", "Whether the eksctl command failed.")
+	sample("eksctl_command_failed", map[string]string{"command": summary.Command}, failed)
+
+	if len(summary.Operations) > 0 {
+		metric("eksctl_operation_duration_seconds", "Duration of operations, e.g. waiting for CloudFormation stacks.")
+		for _, op := range summary.Operations {
+			labels := map[string]string{"operation": op.Name}
+			for k, v := range op.Attributes {
+				labels[k] = v
+			}
+			sample("eksctl_operation_duration_seconds", labels, op.DurationSeconds)
+		}
+	}
+
+	if len(summary.APICalls) > 0 {
+		for _, m := range []struct {
+			name, help string
+			value      func(APICallStats) float64
+		}{
+			{"eksctl_aws_api_calls", "Number of AWS API calls.", func(s APICallStats) float64 { return float64(s.Calls) }},
+			{"eksctl_aws_api_errors", "Number of AWS API calls that failed.", func(s APICallStats) float64 { return float64(s.Errors) }},
+			{"eksctl_aws_api_retries", "Number of retries of AWS API calls.", func(s APICallStats) float64 { return float64(s.Retries) }},
+			{"eksctl_aws_api_latency_seconds", "Total latency of AWS API calls, including retries.", func(s APICallStats) float64 { return s.LatencySeconds }},
+		} {
+			metric(m.name, m.help)
+			for _, stats := range summary.APICalls {
+				sample(m.name, map[string]string{"service": stats.Service, "api_operation": stats.Operation}, m.value(stats))
+			}
+		}
+	}
+}
+
+// ExportOTLP exports the spans that have ended as a trace to an OTLP/HTTP endpoint,
+// e.g. http://localhost:4318, using the JSON encoding
+func (r *Recorder) ExportOTLP(endpoint string) error {
+	data, err := json.Marshal(r.otlpTraces())
+	if err != nil {
+		return err
+	}
+	url := strings.TrimSuffix(endpoint, "/") + "/v1/traces"
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrapf(err, "exporting traces to %q", url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("exporting traces to %q: unexpected status %q", url, resp.Status)
+	}
+	return nil
+}
+
+type otlpKeyValue struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue string `json:"stringValue"`
+	} `json:"value"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            struct {
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	} `json:"status"`
+}
+
+type otlpScopeSpans struct {
+	Scope struct {
+		Name string `json:"name"`
+	} `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResourceSpans struct {
+	Resource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	} `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpTraces struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+const (
+	otlpSpanKindInternal = 1
+	otlpStatusCodeError  = 2
+)
+
+func otlpAttributes(attributes map[string]string) []otlpKeyValue {
+	var keyValues []otlpKeyValue
+	for k, v := range attributes {
+		kv := otlpKeyValue{Key: k}
+		kv.Value.StringValue = v
+		keyValues = append(keyValues, kv)
+	}
+	sort.Slice(keyValues, func(i, j int) bool { return keyValues[i].Key < keyValues[j].Key })
+	return keyValues
+}
+
+func (r *Recorder) otlpTraces() otlpTraces {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var spans []otlpSpan
+	for _, span := range r.spans {
+		if span.end.IsZero() {
+			continue
+		}
+		s := otlpSpan{
+			TraceID:           r.traceID,
+			SpanID:            span.id,
+			ParentSpanID:      span.parentID,
+			Name:              span.name,
+			Kind:              otlpSpanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(span.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.end.UnixNano(), 10),
+			Attributes:        otlpAttributes(span.attributes),
+		}
+		if span.err != "" {
+			s.Status.Code = otlpStatusCodeError
+			s.Status.Message = span.err
+		}
+		spans = append(spans, s)
+	}
+
+	scopeSpans := otlpScopeSpans{Spans: spans}
+	scopeSpans.Scope.Name = "eksctl"
+	resourceSpans := otlpResourceSpans{ScopeSpans: []otlpScopeSpans{scopeSpans}}
+	resourceSpans.Resource.Attributes = otlpAttributes(map[string]string{"service.name": "eksctl"})
+	return otlpTraces{ResourceSpans: []otlpResourceSpans{resourceSpans}}
+}
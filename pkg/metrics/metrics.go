@@ -0,0 +1,274 @@
+// Package metrics records the durations of eksctl operations, e.g. waiting for
+// CloudFormation stacks, along with statistics of AWS API calls, so that they
+// can be written to a summary file or exported as OTLP traces
+package metrics
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// Default is the recorder of the running command
+var Default = NewRecorder()
+
+// StartSpan starts a span on the default recorder
+func StartSpan(name string, attributes map[string]string) *Span {
+	return Default.StartSpan(name, attributes)
+}
+
+// InstrumentHandlers records the AWS API calls made with the handlers on the default recorder
+func InstrumentHandlers(handlers *request.Handlers) {
+	Default.InstrumentHandlers(handlers)
+}
+
+// Recorder collects the spans and AWS API call statistics of a command
+type Recorder struct {
+	mu       sync.Mutex
+	traceID  string
+	root     *Span
+	spans    []*Span
+	apiCalls map[apiCallKey]*APICallStats
+	now      func() time.Time
+
+	observers      map[int]Observer
+	nextObserverID int
+}
+
+// SpanEvent is sent to observers when a span starts or ends
+type SpanEvent struct {
+	Name       string
+	Attributes map[string]string
+	// Ended is false when the span starts
+	Ended    bool
+	Duration time.Duration
+	Err      error
+}
+
+// Observer is notified of spans starting and ending
+type Observer func(SpanEvent)
+
+// Span is a timed operation
+type Span struct {
+	recorder   *Recorder
+	id         string
+	parentID   string
+	name       string
+	attributes map[string]string
+	start      time.Time
+	end        time.Time
+	err        string
+}
+
+type apiCallKey struct {
+	service, operation string
+}
+
+// APICallStats summarises the calls made to an AWS API operation
+type APICallStats struct {
+	Service        string  `json:"service"`
+	Operation      string  `json:"operation"`
+	Calls          int     `json:"calls"`
+	Errors         int     `json:"errors"`
+	Retries        int     `json:"retries"`
+	LatencySeconds float64 `json:"latencySeconds"`
+}
+
+// NewRecorder creates a recorder
+func NewRecorder() *Recorder {
+	return &Recorder{
+		traceID:   newID(16),
+		apiCalls:  map[apiCallKey]*APICallStats{},
+		now:       time.Now,
+		observers: map[int]Observer{},
+	}
+}
+
+// AddObserver adds an observer of spans, returning a function that removes it
+func (r *Recorder) AddObserver(observer Observer) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := r.nextObserverID
+	r.nextObserverID++
+	r.observers[id] = observer
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.observers, id)
+	}
+}
+
+// notify must be called without holding the lock, so that observers can use the recorder
+func (r *Recorder) notify(event SpanEvent) {
+	r.mu.Lock()
+	var observers []Observer
+	for _, observer := range r.observers {
+		observers = append(observers, observer)
+	}
+	r.mu.Unlock()
+
+	for _, observer := range observers {
+		observer(event)
+	}
+}
+
+// Start starts the root span, which all other spans are children of
+func (r *Recorder) Start(name string) {
+	r.root = r.StartSpan(name, nil)
+}
+
+// Finish ends the root span, naming it after the command that was run
+func (r *Recorder) Finish(name string, err error) {
+	if r.root == nil {
+		return
+	}
+	r.mu.Lock()
+	r.root.name = name
+	r.mu.Unlock()
+	r.root.End(err)
+}
+
+// StartSpan starts a span, which has to be ended by calling End
+func (r *Recorder) StartSpan(name string, attributes map[string]string) *Span {
+	r.mu.Lock()
+	span := &Span{
+		recorder:   r,
+		id:         newID(8),
+		name:       name,
+		attributes: attributes,
+		start:      r.now(),
+	}
+	if r.root != nil {
+		span.parentID = r.root.id
+	}
+	r.spans = append(r.spans, span)
+	r.mu.Unlock()
+
+	r.notify(SpanEvent{Name: name, Attributes: attributes})
+	return span
+}
+
+// End ends the span, recording the error the operation failed with, if any
+func (s *Span) End(err error) {
+	s.recorder.mu.Lock()
+	s.end = s.recorder.now()
+	if err != nil {
+		s.err = err.Error()
+	}
+	event := SpanEvent{
+		Name:       s.name,
+		Attributes: s.attributes,
+		Ended:      true,
+		Duration:   s.end.Sub(s.start),
+		Err:        err,
+	}
+	s.recorder.mu.Unlock()
+
+	s.recorder.notify(event)
+}
+
+// InstrumentHandlers records the AWS API calls made with the handlers, once each
+// call completes, including all of its retries
+func (r *Recorder) InstrumentHandlers(handlers *request.Handlers) {
+	handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "eksctlMetrics",
+		Fn:   r.recordAPICall,
+	})
+}
+
+func (r *Recorder) recordAPICall(req *request.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := apiCallKey{service: req.ClientInfo.ServiceName}
+	if req.Operation != nil {
+		key.operation = req.Operation.Name
+	}
+	stats, ok := r.apiCalls[key]
+	if !ok {
+		stats = &APICallStats{Service: key.service, Operation: key.operation}
+		r.apiCalls[key] = stats
+	}
+	stats.Calls++
+	stats.Retries += req.RetryCount
+	if req.Error != nil {
+		stats.Errors++
+	}
+	stats.LatencySeconds += r.now().Sub(req.Time).Seconds()
+}
+
+// Summary is a summary of the command and the operations it performed
+type Summary struct {
+	Command         string         `json:"command"`
+	StartTime       time.Time      `json:"startTime"`
+	DurationSeconds float64        `json:"durationSeconds"`
+	Error           string         `json:"error,omitempty"`
+	Operations      []Operation    `json:"operations,omitempty"`
+	APICalls        []APICallStats `json:"awsAPICalls,omitempty"`
+}
+
+// Operation is a summary of a completed span
+type Operation struct {
+	Name            string            `json:"name"`
+	Attributes      map[string]string `json:"attributes,omitempty"`
+	StartTime       time.Time         `json:"startTime"`
+	DurationSeconds float64           `json:"durationSeconds"`
+	Error           string            `json:"error,omitempty"`
+}
+
+// Summary summarises the spans that have ended and all AWS API calls
+func (r *Recorder) Summary() Summary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	summary := Summary{}
+	if r.root != nil {
+		summary.Command = r.root.name
+		summary.StartTime = r.root.start
+		summary.DurationSeconds = r.root.duration(r.now()).Seconds()
+		summary.Error = r.root.err
+	}
+
+	for _, span := range r.spans {
+		if span == r.root || span.end.IsZero() {
+			continue
+		}
+		summary.Operations = append(summary.Operations, Operation{
+			Name:            span.name,
+			Attributes:      span.attributes,
+			StartTime:       span.start,
+			DurationSeconds: span.duration(r.now()).Seconds(),
+			Error:           span.err,
+		})
+	}
+
+	for _, stats := range r.apiCalls {
+		summary.APICalls = append(summary.APICalls, *stats)
+	}
+	sort.Slice(summary.APICalls, func(i, j int) bool {
+		a, b := summary.APICalls[i], summary.APICalls[j]
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		return a.Operation < b.Operation
+	})
+	return summary
+}
+
+func (s *Span) duration(now time.Time) time.Duration {
+	if s.end.IsZero() {
+		return now.Sub(s.start)
+	}
+	return s.end.Sub(s.start)
+}
+
+func newID(size int) string {
+	id := make([]byte, size)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
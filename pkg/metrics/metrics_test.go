@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("metrics", func() {
+	var (
+		recorder *Recorder
+		now      time.Time
+		start    = time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+	)
+
+	apiCall := func(service, operation string, retries int, err error) *request.Request {
+		return &request.Request{
+			ClientInfo: metadata.ClientInfo{ServiceName: service},
+			Operation:  &request.Operation{Name: operation},
+			RetryCount: retries,
+			Error:      err,
+			Time:       now.Add(-2 * time.Second),
+		}
+	}
+
+	BeforeEach(func() {
+		now = start
+		recorder = NewRecorder()
+		recorder.now = func() time.Time { return now }
+
+		recorder.Start("eksctl")
+		now = now.Add(time.Minute)
+		span := recorder.StartSpan("cloudformation.stack.create", map[string]string{"stack": "eksctl-cluster-1-cluster"})
+		recorder.StartSpan("cloudformation.stack.create", map[string]string{"stack": "eksctl-cluster-1-nodegroup-ng-1"})
+
+		now = now.Add(10 * time.Minute)
+		recorder.recordAPICall(apiCall("cloudformation", "DescribeStacks", 0, nil))
+		recorder.recordAPICall(apiCall("cloudformation", "DescribeStacks", 2, nil))
+		recorder.recordAPICall(apiCall("ec2", "DescribeSubnets", 1, errors.New("throttled")))
+		span.End(nil)
+
+		now = now.Add(time.Minute)
+		recorder.Finish("eksctl create cluster", errors.New("failed to create cluster"))
+	})
+
+	It("summarises the command", func() {
+		summary := recorder.Summary()
+		Expect(summary.Command).To(Equal("eksctl create cluster"))
+		Expect(summary.StartTime).To(Equal(start))
+		Expect(summary.DurationSeconds).To(Equal(720.0))
+		Expect(summary.Error).To(Equal("failed to create cluster"))
+
+		Expect(summary.Operations).To(Equal([]Operation{{
+			Name:            "cloudformation.stack.create",
+			Attributes:      map[string]string{"stack": "eksctl-cluster-1-cluster"},
+			StartTime:       start.Add(time.Minute),
+			DurationSeconds: 600,
+		}}))
+
+		Expect(summary.APICalls).To(Equal([]APICallStats{
+			{Service: "cloudformation", Operation: "DescribeStacks", Calls: 2, Retries: 2, LatencySeconds: 4},
+			{Service: "ec2", Operation: "DescribeSubnets", Calls: 1, Errors: 1, Retries: 1, LatencySeconds: 2},
+		}))
+	})
+
+	It("writes Prometheus metrics", func() {
+		buf := &bytes.Buffer{}
+		writePrometheus(buf, recorder.Summary())
+
+		Expect(buf.String()).To(ContainSubstring("# TYPE eksctl_command_duration_seconds gauge\n" +
+			`eksctl_command_duration_seconds{command="eksctl create cluster"} 720` + "\n"))
+		Expect(buf.String()).To(ContainSubstring(`eksctl_command_failed{command="eksctl create cluster"} 1`))
+		Expect(buf.String()).To(ContainSubstring(
+			`eksctl_operation_duration_seconds{operation="cloudformation.stack.create",stack="eksctl-cluster-1-cluster"} 600`))
+		Expect(buf.String()).To(ContainSubstring(`eksctl_aws_api_retries{api_operation="DescribeStacks",service="cloudformation"} 2`))
+		Expect(buf.String()).To(ContainSubstring(`eksctl_aws_api_errors{api_operation="DescribeSubnets",service="ec2"} 1`))
+	})
+
+	It("notifies observers", func() {
+		var events []SpanEvent
+		remove := recorder.AddObserver(func(event SpanEvent) {
+			events = append(events, event)
+		})
+
+		span := recorder.StartSpan("cloudformation.stack.delete", map[string]string{"stack": "eksctl-cluster-1-cluster"})
+		now = now.Add(time.Minute)
+		span.End(errors.New("DELETE_FAILED"))
+		remove()
+		recorder.StartSpan("cloudformation.stack.delete", nil)
+
+		attributes := map[string]string{"stack": "eksctl-cluster-1-cluster"}
+		Expect(events).To(Equal([]SpanEvent{
+			{Name: "cloudformation.stack.delete", Attributes: attributes},
+			{Name: "cloudformation.stack.delete", Attributes: attributes, Ended: true, Duration: time.Minute, Err: errors.New("DELETE_FAILED")},
+		}))
+	})
+
+	It("exports traces", func() {
+		var traces otlpTraces
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+			Expect(req.URL.Path).To(Equal("/v1/traces"))
+			Expect(json.NewDecoder(req.Body).Decode(&traces)).To(Succeed())
+		}))
+		defer server.Close()
+
+		Expect(recorder.ExportOTLP(server.URL + "/")).To(Succeed())
+
+		Expect(traces.ResourceSpans).To(HaveLen(1))
+		spans := traces.ResourceSpans[0].ScopeSpans[0].Spans
+		Expect(spans).To(HaveLen(2))
+
+		root, stack := spans[0], spans[1]
+		Expect(root.Name).To(Equal("eksctl create cluster"))
+		Expect(root.ParentSpanID).To(BeEmpty())
+		Expect(root.Status.Code).To(Equal(otlpStatusCodeError))
+		Expect(root.StartTimeUnixNano).To(Equal("1583064000000000000"))
+
+		Expect(stack.TraceID).To(Equal(root.TraceID))
+		Expect(stack.ParentSpanID).To(Equal(root.SpanID))
+		Expect(stack.Attributes).To(HaveLen(1))
+		Expect(stack.Attributes[0].Key).To(Equal("stack"))
+		Expect(stack.Attributes[0].Value.StringValue).To(Equal("eksctl-cluster-1-cluster"))
+	})
+})
@@ -1,8 +1,8 @@
 // Code generated by go-bindata. DO NOT EDIT.
 // sources:
-// assets/10-eksclt.al2.conf (982B)
-// assets/bootstrap.al2.sh (1.2kB)
-// assets/bootstrap.ubuntu.sh (2.022kB)
+// assets/10-eksclt.al2.conf (940B)
+// assets/bootstrap.al2.sh (1.527kB)
+// assets/bootstrap.ubuntu.sh (2.055kB)
 // assets/kubelet.yaml (464B)
 
 package nodebootstrap
@@ -72,7 +72,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var __10EkscltAl2Conf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x52\xdf\x6f\xd3\x30\x10\x7e\xcf\x5f\x61\x69\x7d\x00\xa9\x4e\xc4\x78\x9b\x94\x87\x90\x66\x23\x22\x4b\xa7\xa6\x03\x24\x40\x91\x6b\x5f\xbb\x53\x9d\x73\xe4\x38\xdd\x46\xd5\xff\x1d\xb9\x69\xa0\x13\x13\xe2\xcd\xbe\xef\xee\xfb\xbe\xfb\x71\xc1\x60\xdb\x49\xa7\x79\xd7\x82\xc4\x35\x4a\xd6\x3d\x77\x0e\x1a\xc5\x94\x35\x2d\x47\x62\x3d\xa1\x63\x6b\x63\xd9\xb6\x5f\x81\x06\x37\x3d\x7e\x92\x46\xfc\x34\xc4\x0a\xa4\xfe\x89\x5d\xb2\x37\x49\x71\xf9\x36\x08\xbe\x55\x60\x77\x28\xe1\x47\x70\xc1\x0a\x23\x85\x66\x0d\x38\xa1\x84\x13\xac\x15\x56\x34\xe0\xc0\x76\x57\x6c\x91\xdd\xe4\xf3\x72\xca\x92\x2f\x55\x3d\xcb\xae\x93\xfb\x62\x59\x0f\xb1\x20\xa3\x1d\x5a\x43\x0d\x90\xbb\x46\x0d\x71\x04\x4e\x46\x83\xc5\x68\xe4\x0a\x81\x76\xc1\x05\xbb\xd1\x66\x25\x34\x13\xa4\x58\xe7\x84\x43\xf9\x42\x23\x2d\xee\xab\x65\xb6\xa8\x67\x65\x35\x65\xe5\x7c\x96\xd5\x45\xf2\x21\x2b\xc6\xcf\x32\xc9\xcb\x65\xf5\x4f\xb9\x53\xbf\x27\xb5\xa1\x1d\x32\xc4\x5f\x11\x3b\x52\xe6\x77\x53\x96\x97\xd5\x32\x29\xd3\xac\xce\x67\xff\xc5\xad\x3d\xeb\x51\x21\xc8\x9e\x40\x56\x4e\x58\x17\x9f\x3d\xa3\xbe\xb3\xd1\x0a\x69\x2c\x60\xdf\x03\xc6\x38\x27\xa3\x80\x63\x1b\x4f\xf6\x27\xe5\xc3\x39\xa0\xc5\x0a\x74\x37\x82\x43\xdb\x87\xa9\xd0\xed\x83\x08\x07\xfd\x10\x4d\x84\xd4\x39\x41\x12\x38\xaa\x78\xb2\x3f\x33\x3e\x72\x35\xe2\x89\xb7\x46\x79\xa2\xdb\xe4\x6b\x7d\x37\x9f\x55\x23\x64\x61\x83\x9d\x03\x7b\xd4\x8b\x9d\xed\xe1\x3c\xf8\x88\xee\x81\x3b\x81\xe4\x7e\x9b\x18\xc6\x3d\x96\x4b\x6d\x7a\xc5\x5b\x6b\x76\xa8\xc0\xc6\xe2\xb1\x1b\x01\x43\xbe\x0e\x2c\xb7\x3d\x39\x6c\x20\x56\x46\x6e\xc1\x8e\xdd\x81\x7b\x34\x76\xcb\x5b\xdd\x6f\x90\x62\x49\x38\xd6\x11\xf2\x15\x12\x57\x68\xe3\xc8\xb4\x2e\x92\x84\x7e\x6c\x67\xb0\x34\xb4\x1e\x70\xbf\x06\x8f\x13\xb8\x50\x9d\x32\x5a\xa3\x38\xd2\xda\x8a\x33\x0b\xd8\x88\x0d\xc4\x93\xbd\xbf\xd2\xec\x53\x55\x67\xe9\xa2\x4e\xd2\x74\x7e\x5f\x2e\x0f\xa1\xda\xda\x10\xa4\x0d\x07\xf8\xe5\x11\x1f\x4e\xd1\x2a\x5b\x7c\xce\xd3\xac\xaa\x67\xf3\xdb\x24\x2f\x0f\x7e\xf9\x51\x2b\xfa\x0e\xf8\x64\x9f\x2c\xd2\x8f\x87\xab\xf7\xe1\xbb\x93\x03\xbf\x60\xef\x11\x37\x7f\x1d\xca\x10\x0e\x9f\x45\xa3\xff\xcc\xe9\xb5\x44\x7f\x51\x3e\x2b\xf8\x15\x00\x00\xff\xff\x5b\x08\x1a\xba\xd6\x03\x00\x00")
+var __10EkscltAl2Conf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\x53\xc1\x8a\xdb\x30\x10\xbd\xfb\x2b\x04\xdb\x43\x0b\x56\x0c\x7b\x2c\xf8\xe0\x26\xde\x60\xf0\x3a\x4b\x9c\xa5\x0b\x6d\x31\x8a\x34\xc9\x0e\x91\x25\x23\xcb\x49\xb6\x65\xff\xbd\x52\x1c\xb5\x86\x6e\x4b\x6f\x9e\x79\x33\xef\xcd\x9b\x91\x6f\x08\x1c\x7a\x6e\x25\xed\x3b\xe0\xb8\x43\x4e\xfa\x97\xde\x42\x2b\x88\x30\xba\xa3\xa8\xc8\xa0\xd0\x92\x9d\x36\xe4\x30\x6c\x41\x82\x8d\x2f\x41\xd6\xb2\xef\x5a\x91\x12\xd5\x70\x26\xb7\xe4\x7d\x56\xde\x7e\x88\xa2\x2f\x35\x98\x23\x72\xf8\x16\xdd\x90\x52\x73\x26\x49\x0b\x96\x09\x66\x19\xe9\x98\x61\x2e\x00\xd3\x7f\x24\xeb\x7c\x59\xac\xaa\x98\x64\x9f\xeb\x66\x91\xdf\x65\x8f\xe5\xa6\x19\x73\x51\xae\x8e\x68\xb4\x6a\x41\xd9\x3b\x94\x90\x26\x60\x79\x32\x8e\x98\x04\xae\x19\xa8\xa3\x13\x58\x4a\xbd\x75\x0a\x4c\x09\xd2\x5b\x66\xdd\xe8\x53\x8d\x79\xf9\x58\x6f\xf2\x75\xb3\xa8\xea\x98\x54\xab\x45\xde\x94\xd9\xa7\xbc\x0c\xc1\x26\x2b\xaa\x4d\xfd\x4f\xb9\xab\xdf\xab\xda\x68\x47\x69\x45\xdf\x10\xbb\x50\x16\x0f\x31\x29\xaa\x7a\x93\x55\x73\x17\x2c\x62\xf2\xb0\x5a\x34\x45\x75\xb7\xce\x9a\xf9\xaa\xf2\x82\x6e\x9c\xe2\x3e\x5b\xe6\xff\x25\x2b\xbd\xe0\x45\x3c\xca\xcf\xc0\x6b\xcb\x8c\x4d\x27\x9f\xc9\xd0\x9b\x64\x8b\x2a\x34\x90\xaf\x11\x21\x94\x2a\x2d\x80\x62\x97\xbe\xfb\x71\x1d\xea\x75\x0a\x48\xe6\x6a\xfb\x00\x8e\x1b\x79\x8d\x99\xec\x9e\xdd\x56\x2f\xfa\x33\xd4\x09\x2a\xe7\x51\x71\xc7\x23\x5c\xe9\xc4\x53\xe0\x6a\xd9\x99\x76\x5a\x78\xa2\xfb\xec\xa9\x71\x46\xeb\x00\x19\xd8\xa3\x7b\x40\xe6\xa2\x97\x5a\x33\xc0\x34\x79\x42\xfb\x4c\x2d\x43\x65\x7f\x0d\x31\x5e\x22\xb4\x73\xa9\x07\x41\x3b\xa3\x8f\x28\xc0\xa4\xec\xd4\x07\x40\x2b\xdf\xe7\x38\xcc\xa0\x2c\xb6\x90\x0a\xcd\x0f\x60\x82\x3b\xb0\x27\x6d\x0e\xb4\x93\xc3\x1e\x55\xca\x15\x86\x3e\x85\xd4\x6d\x89\x0a\x34\x69\xa2\x3b\x9b\xb8\x84\x5f\xdb\x04\x76\xd4\xbb\x11\xf7\x67\xf0\xb8\x63\x9b\x89\x6b\x85\xf3\xe9\x7e\x83\x9d\x61\x93\x11\xb0\x65\x7b\x70\x06\xfe\x7a\xe1\x60\xc7\xdf\xc6\xd3\xe3\xfe\x8f\x1b\x8f\xe9\xd9\x0b\x6b\xe5\x6f\x8b\x6f\x15\xfa\xc7\xe0\xab\xa2\x9f\xbd\xed\x06\xf8\xac\x03\x00\x00")
 
 func _10EkscltAl2ConfBytes() ([]byte, error) {
 	return bindataRead(
@@ -88,11 +88,11 @@ func _10EkscltAl2Conf() (*asset, error) {
 	}
 
 	info := bindataFileInfo{name: "10-eksclt.al2.conf", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
-	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x2a, 0x99, 0xdb, 0x6d, 0x46, 0x69, 0x8a, 0xf5, 0x84, 0xe3, 0x6a, 0xe7, 0xca, 0x7b, 0x3f, 0xc5, 0xd3, 0xea, 0xd8, 0xe2, 0xc2, 0xf2, 0x9, 0x14, 0x55, 0x10, 0x30, 0x2e, 0xca, 0x69, 0x53, 0x21}}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x55, 0x0, 0xd4, 0x80, 0xa5, 0x5e, 0x30, 0xf0, 0xc1, 0x62, 0x0, 0x10, 0xd7, 0x69, 0xd1, 0x93, 0x5, 0x5c, 0x65, 0xe3, 0xa1, 0x13, 0x37, 0xe6, 0x2e, 0x2, 0x6c, 0xbc, 0x57, 0xf6, 0x5d, 0x3b}}
 	return a, nil
 }
 
-var _bootstrapAl2Sh = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x53\x5d\x6f\xe2\x3a\x10\x7d\xf7\xaf\x98\x9b\x46\x2d\xe8\x2a\xe4\xb6\xe2\xa2\xdb\x0f\x2a\x21\xe0\xaa\x3c\x14\xaa\x52\xed\x87\x2a\x36\x32\xce\x64\x63\xd5\xb1\x23\x7b\xc2\xb6\x8a\xb2\xbf\x7d\x15\x1a\xaa\xb0\xcb\xbe\x54\xfb\x14\xcf\x9c\x39\x67\x9c\x99\xe3\xa3\xbf\xc2\xb5\xd4\xe1\x9a\xbb\x94\x31\x87\x04\x81\x01\xb4\x16\x9f\x25\xed\xc2\x5c\xe6\x98\x70\xa9\x76\xb1\x36\x85\x76\x48\x8c\x25\x85\x16\x24\x8d\x86\xaf\x48\x51\xc6\x9f\xa3\xdc\xc4\xae\xd3\x85\x92\x01\x7c\x4b\xa5\x42\xb0\xc8\x63\x90\xda\x11\xd7\x02\x23\x7a\xc9\x11\xea\x9a\x4b\x88\x0d\x03\x00\x90\x09\xc0\xe3\x23\x78\x7e\xb9\x57\x54\x79\x30\x1c\xd6\xd9\xd3\xca\x83\xd5\x0a\x8e\x8f\x9b\xaa\x9a\x5c\x83\xdf\xe1\xcb\xe3\x3f\xc1\xf9\xea\x6f\xbf\x86\x2f\x81\x52\xd4\x5b\x41\x00\x14\xa9\x81\xa6\xb2\x49\x59\xa4\xc2\xbe\xe2\x89\x64\x00\xb1\xd1\x08\x57\x10\x22\x89\x10\x9f\x9c\x20\x15\xee\x6e\xdf\xcb\x78\xce\x2a\xc6\xe6\x8b\xc9\x34\x9a\xdd\x0d\x3d\xbf\x23\x0a\xab\x20\x08\x9c\x54\xa8\x09\x52\xa2\xfc\x22\x0c\x4f\x07\xe7\xbd\xb3\x7f\xfb\xbd\xe6\x1b\x2a\x4e\xe8\x28\xcc\x90\x78\x10\x73\xe2\xa1\x32\x82\xab\x40\xe6\x9b\x7e\xd7\x63\xb3\xf9\xf2\x61\x34\x1f\x4f\xa3\xd9\xe4\xfd\x8a\xbb\x09\x05\x32\x6e\x4b\x3e\x7c\xbe\x9b\xfe\x01\xd1\x7a\xec\x5d\x8f\xdd\x8e\xc6\x37\xb3\xf9\x74\xe8\x77\x0a\xcd\x33\x84\x20\xeb\xb2\xd1\xc7\x65\xb4\x9c\xde\x7f\x98\x8d\xa7\xcb\x68\xb2\xb8\x1d\xcd\xe6\xef\x6f\xe8\xd0\x6e\xa4\x40\x17\xc6\x26\xe3\x52\x77\x3d\xc6\x64\x02\xf5\x72\x9b\xd6\xaf\xab\x7f\xfe\x6f\x10\x0d\xfa\x1e\xac\x5a\xbb\x1d\xdd\x8f\x6f\x86\x1e\xcf\xe2\x41\xdf\x63\xa8\x0e\xd1\x38\xb7\x22\xfd\x0d\xcf\x66\xaf\x3c\x87\xbf\xaa\xbd\x19\xc7\x5b\x22\x41\x8c\x09\x2f\x14\x41\x2d\x26\x09\x05\x15\x16\x81\x0c\x9c\xf8\x35\xe9\xc4\x83\xeb\xe3\x33\x96\x48\xc6\x9c\x29\xac\xc0\x3d\x23\x3d\x15\x6b\x54\x48\x3d\xd4\x1b\x38\x02\x4a\xa5\x03\xc1\x35\x98\x0d\x5a\x2b\x63\x84\xdb\xd1\xa7\xe8\x6e\x31\x59\x32\x26\x38\xc1\xf5\x41\xee\xd6\x3b\x5b\x85\xab\xab\xe9\xe2\xff\x37\x33\xfa\x65\x73\xaa\xf6\x1c\xe5\x97\xad\xa8\xfa\xc9\x19\x2d\xb0\x8e\xab\x83\xdb\xf4\xcb\x03\xd9\x8a\xed\xee\x3a\xf4\xcb\xdd\xf1\x22\xf0\x3b\xed\xd7\x5e\x3f\xca\xfd\x06\x5e\xb7\x62\xdb\xd9\xfa\x65\xfd\xa9\x58\xfd\x07\xcc\xbd\x38\xc2\x4c\x90\x82\x98\x63\x66\x74\x60\x51\x19\x1e\xb7\xf2\xa8\xf9\x5a\x21\x34\x33\x68\x01\x8e\xb8\xa5\xb7\xfc\x8f\x00\x00\x00\xff\xff\x18\xaa\xe1\x83\xb0\x04\x00\x00")
+var _bootstrapAl2Sh = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xad\x54\x5d\x6f\x9b\x30\x14\x7d\xf7\xaf\xb8\xa3\xa8\x4d\x34\x11\xd6\xae\xab\xd6\x8f\x54\x42\x84\xb6\x68\x0b\x54\x24\xdd\x56\x55\x1d\x72\xe0\x66\xb1\x0a\x06\x81\x49\x1b\x45\xec\xb7\xcf\x4e\x48\x95\x74\x59\x1f\xaa\x3d\x20\xdb\xf7\xe3\x5c\x73\xee\xb9\xde\x79\x67\x8e\x18\x37\x47\xb4\x9c\x10\x52\xa2\x00\x23\x03\x2c\x0a\x7c\x62\x62\x75\xcc\x59\x8e\x63\xca\x92\xd5\x99\x67\x15\x97\x5b\x42\xc6\x15\x8f\x04\xcb\x38\xfc\x42\x11\xa6\xf4\x29\xcc\xb3\xb8\x6c\xb5\x61\x4e\x00\x1e\x27\x2c\x41\x28\x90\xc6\xc0\x78\x29\x28\x8f\x30\x14\xb3\x1c\x41\xc5\x9c\x42\x9c\xc9\x18\x00\x36\x06\xb8\xbb\x03\x4d\x9f\x6f\x04\xd5\x1a\x74\xbb\xca\xba\x2f\x77\xf7\xf7\xb0\xbb\xdb\x44\xa9\x64\xe5\xfc\x0d\x3f\xef\x3e\x18\xc7\xf7\xef\x75\xe5\x3e\x05\x31\x41\xbe\x00\x04\xc0\x68\x92\x41\x13\xd9\x98\x0a\x14\x55\xb1\xf4\x8f\x99\x5c\xe2\x8c\x23\x9c\x81\x89\x22\x32\xf1\xa1\x8c\x44\x62\xae\x6e\xdf\x49\x69\x4e\x6a\x42\x3c\xbf\xe7\x84\xee\x75\x57\xd3\x5b\x51\x55\x24\x60\x18\xa5\xfc\x1f\x2e\x60\x22\x44\x7e\x62\x9a\xfb\x47\xc7\x9d\x83\x4f\x87\x9d\x66\x35\x13\x2a\xb0\x14\x66\x8a\x82\x1a\x31\x15\xd4\x4c\xb2\x88\x26\x06\xcb\xa7\x87\x6d\x8d\xb8\xde\x60\x68\x79\xb6\x44\xec\xbd\x1d\x71\xc5\x90\xc1\xe2\x75\xc8\xe1\xed\xb5\xf3\x1f\x40\x15\xed\x12\xb6\x6f\xd9\x57\xae\xe7\x74\xf5\x56\xc5\x69\x8a\x60\xa4\x6d\x62\x7d\x1f\x84\x03\x27\xf8\xe6\xda\xce\x20\xec\xf9\x7d\xcb\xf5\xde\x5e\xb0\xc4\x62\xca\x22\x2c\xcd\x38\x4b\x29\xe3\xb2\x24\x91\x22\x50\xcd\x6d\x4a\x2f\x5b\xff\xf4\xf9\x28\x3c\x3a\x94\xcd\x5f\xeb\xad\x15\xd8\x57\x5d\x8d\xa6\xb1\x74\x10\x4c\xb6\xa5\x51\x5a\x44\x93\x7f\xe4\x15\xe9\x32\xaf\xc4\xbf\xd1\x9e\x85\xa3\x0d\xa4\xc6\x63\x29\xf7\x2a\x11\xa0\xc0\x98\xc0\x48\xaa\x07\x41\x64\xb0\xa7\xab\xa4\x3d\x0d\xce\x77\x0f\x88\x54\x12\x29\xb3\xaa\x88\x70\x43\x48\x0f\xd5\x08\x13\x14\x1d\xe4\x53\xd8\x91\x77\x60\x25\x44\x94\x43\x36\x95\x43\xc5\x62\x84\xbe\xf5\x23\xbc\xf6\x7b\x83\x6d\xb9\x8a\x25\x45\x52\x93\x2c\xc3\x42\xd7\xbb\x08\xac\xd0\xf6\xbd\xa1\x64\xdd\x09\xc2\xc0\xb9\x74\x07\xc3\xe0\x16\x24\x6e\xc6\x93\x19\xa8\x99\x7c\x64\x62\x02\x51\xc6\x85\x24\x14\x8b\x00\x7f\xb1\x52\x14\x33\xbf\x29\x49\xc8\x6b\x40\xb2\x93\xf3\xd7\xfc\x27\x86\x3e\x57\x02\x70\xbe\xc8\xcf\x0e\x42\xcb\xb6\xfd\x1b\x6f\x58\x77\xe2\x87\xa2\x83\x51\xd1\x59\xba\x7b\xce\x85\x75\xf3\x75\xb8\x48\xf3\xbd\xba\xb1\xbe\x50\x4d\x5d\xcb\x6e\x47\x54\xc0\xf9\x56\xca\x16\x23\xb3\xf8\xf7\xb3\x33\xc7\xbf\x78\x9e\x41\x7d\xde\xec\xea\x8d\x41\xd2\xe7\x6b\xa7\xfa\xc5\x40\xac\x39\xd5\xb9\xde\x2a\xe2\xed\x97\x24\xab\x16\x49\xff\x6a\x2b\x59\x68\xad\x3f\x72\xea\x2d\xda\x2c\xa0\xb5\x65\x0d\x25\x29\x09\x2a\x97\x7a\x2b\xe9\x6e\xdf\xba\x54\x77\x7b\x8d\xf0\x5a\xd1\x62\xe6\xb4\x2a\xd1\x68\xb0\x4e\x3e\x76\xf6\x89\x62\x84\x94\xb3\x52\x60\x2a\x49\x83\x98\x62\x9a\x71\xa3\xc0\x24\xa3\xf1\x9a\x1d\x39\x1d\xc9\x87\xb7\xe1\x74\xcd\x21\xe7\xbc\x10\xcf\xf6\x3f\x91\x11\x13\xc5\xf7\x05\x00\x00")
 
 func bootstrapAl2ShBytes() ([]byte, error) {
 	return bindataRead(
@@ -108,11 +108,11 @@ func bootstrapAl2Sh() (*asset, error) {
 	}
 
 	info := bindataFileInfo{name: "bootstrap.al2.sh", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
-	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x5, 0xad, 0x5d, 0xd8, 0xcb, 0x57, 0x86, 0xff, 0xb1, 0xae, 0x2f, 0xdc, 0x4a, 0x43, 0xaf, 0xb7, 0xb2, 0xa3, 0x97, 0xd9, 0xc5, 0xc0, 0xc6, 0xbe, 0xe6, 0x83, 0x41, 0xf5, 0xf2, 0xec, 0x65, 0x33}}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf2, 0xa2, 0xb2, 0xb6, 0x89, 0xcf, 0xd0, 0x45, 0xcd, 0xcb, 0x50, 0x3b, 0xd3, 0x79, 0x55, 0x18, 0xfa, 0xfa, 0x97, 0xef, 0x5b, 0x5b, 0xb2, 0x34, 0x47, 0xfc, 0x12, 0xf9, 0xa, 0x94, 0x80, 0x5}}
 	return a, nil
 }
 
-var _bootstrapUbuntuSh = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x55\xef\x6f\xe2\x46\x10\xfd\xbe\x7f\xc5\x94\x43\x57\x50\xcf\x76\x73\x4d\x4f\xba\x24\xae\x4a\x03\x57\xa1\xe6\x20\x0a\x5c\x7f\x28\x4a\xd1\xb2\x3b\xe0\x15\xeb\x5d\x6b\x77\x0c\x89\x90\xfb\xb7\x57\x0b\x36\x21\xe8\x7a\x1f\x4e\xfd\x84\x77\xde\x9b\xe7\xf1\xec\x9b\xe1\xd5\x37\xc9\x5c\x99\x64\xce\x7d\xc6\x98\x47\x82\xc8\x02\x3a\x87\x8f\x8a\x9a\x63\xa1\x0a\x5c\x70\xa5\x9b\xb3\xb1\xa5\xf1\x48\x8c\x2d\x4a\x23\x48\x59\x03\x4b\xa4\x59\xce\x1f\x67\x85\x95\xbe\xd3\x85\x2d\x03\xd8\x64\x4a\x23\x38\xe4\x12\x94\xf1\xc4\x8d\xc0\x19\x3d\x15\x08\x81\x73\x09\xd2\x32\x00\x00\xb5\x00\xb8\xbf\x87\x56\x7b\xfb\x82\x54\xb5\x20\x4d\x43\xf4\xac\x6a\xc1\xc3\x03\xbc\x7e\x5d\xb3\x42\x72\x00\xff\x81\xbf\xef\xbf\x8f\xde\x3f\x7c\xd7\x0e\xf0\x25\x50\x86\x66\x27\x08\x80\x22\xb3\x50\x33\x2f\xeb\x98\x43\x2a\xdd\x9e\xb0\x50\x0c\x40\x5a\x83\x70\x05\x09\x92\x48\x70\xe5\x05\xe9\xa4\x29\x3f\xce\x79\xc1\x2a\xc6\x46\xe3\xfe\x60\x36\xbc\x4d\x5b\xed\x8e\x28\x9d\x86\x28\xf2\x4a\xa3\x21\xc8\x88\x8a\x8b\x24\x39\x7b\xf7\x3e\x7e\xfb\xe3\x79\x5c\xff\x26\x9a\x13\x7a\x4a\x72\x24\x1e\x49\x4e\x3c\xd1\x56\x70\x1d\xa9\x62\x7d\xde\x6d\xb1\xe1\x68\x32\xed\x8d\xae\x07\xb3\x61\xff\xeb\x15\x9b\x16\x45\x4a\x1e\x4b\x4e\xff\xba\x1d\xfc\x0f\xa2\xa1\xef\xdd\x16\xeb\xfd\x31\x99\x4d\x06\x77\xbf\x0f\xaf\x07\x93\x59\x7f\xfc\xb1\x37\x1c\x7d\xbd\xb8\x47\xb7\x56\x02\x7d\x22\x6d\xce\x95\xe9\xb6\x18\xf3\xb6\x74\x02\x5f\xb4\x7e\x55\xce\x51\x23\xc5\x68\xd6\xf0\x0a\x28\x53\x1e\x04\x37\x60\xd7\xe8\x9c\x92\x08\x1f\x7b\x7f\xce\x6e\xc7\xfd\x09\x63\x82\x13\xfc\xf4\xd9\xdc\x5d\xb7\x77\x0a\x57\x57\x83\xf1\x87\xc3\xf5\xb5\xb7\xf5\x53\xf5\xe2\x0e\xda\xdb\xa3\x53\x75\xd2\xcb\x23\x30\x9c\x2b\xd6\x14\x90\xb6\xb7\xcd\xe3\x45\xd4\xee\x1c\xbb\x3e\x98\xf3\x65\x56\xab\x5b\xb1\x50\x09\xf3\x86\x17\xc0\xb5\xe2\x1e\xea\x6a\x23\x5c\xf9\xb8\x7e\x6e\x62\xa7\x34\x41\xfa\x40\x13\xa4\x9b\xd8\x9e\xe6\xc9\x16\xc7\x62\xcc\x3f\x79\xc2\x3c\xf0\x1c\x7a\xa4\x28\x8c\x2b\x4a\xc6\x3a\x0c\xe0\x15\x4c\xc7\xfd\xf1\x45\x98\x11\x8f\xe0\x33\x5b\x6a\x09\x73\x04\x6d\xed\x0a\x25\x70\x02\x5c\xa3\x7b\x02\x52\x39\x36\xa2\xe0\x89\x3b\xf2\x50\x16\x6f\x76\x0a\x9b\x4c\x89\x0c\x94\x87\x4d\xc6\x09\x36\x08\xd2\x82\x32\xd0\xbb\x79\x0b\x9d\x03\x36\xe7\x1e\x25\x58\x03\x85\xe6\xca\xc0\xbe\x26\xb9\x17\xe0\x46\x42\x8e\xdc\x10\x90\x0d\x2f\x2f\xac\x23\x3e\xd7\x18\x8e\xb9\xf5\xd4\xb0\x41\x2a\x4f\xce\xfa\xee\x1b\x98\x97\x04\x8a\xbe\xf5\xbb\x7c\x63\x09\x84\x46\xee\x20\xb3\x9b\x90\xa4\x2d\x97\xf5\x27\x2d\x9c\xcd\x9f\x0b\x0f\xfd\xd9\x28\xca\x6c\x49\x90\xf1\xb5\x32\xcb\x9d\x00\x59\x10\xa5\x27\x9b\x2b\x8f\x21\x6f\x4f\x54\xe4\x51\x2f\x18\xc0\x17\x6c\x79\xb0\xd6\x97\x69\xff\x49\x08\xe3\x10\xa6\x61\xc7\x60\x00\x0b\xcd\x97\x3e\xed\xec\xb6\x51\xcb\x58\x89\x91\x2a\x8e\x7c\xda\xda\x03\x39\x7f\x8c\x82\xb1\x8e\x3c\xd7\x40\xbb\x1c\xcd\xe7\xa8\x7d\x93\x77\xd3\xfb\x65\x70\x33\xa9\xde\x70\x5d\x64\x3c\xde\xbf\x38\x56\xf6\x78\x65\x9c\x78\xbe\xd6\x2a\xac\x8c\x94\x59\x38\x1e\x09\x6b\x88\x2b\x83\x2e\x52\x39\x5f\x62\xda\xde\x86\x55\x30\xf8\x6d\x32\x1b\x5c\xdf\xcd\x7a\xd7\xd7\xe3\x4f\xa3\x69\x15\xcb\x95\x8b\x51\xb8\x78\x0f\xf7\x07\x1f\x7a\x9f\x6e\xa6\xb3\xbb\xc1\xaf\xc3\xf1\xa8\xaa\xa3\x27\xfb\xa3\x0a\x9d\x48\x0a\x5e\x7a\x8c\x78\x2e\xdf\x9d\x5f\xfc\x10\x9f\xd5\xef\x17\xda\x96\x32\x2a\x9c\x5d\x2b\x89\x2e\xe5\x1b\xdf\x00\x46\x45\x73\x65\x22\xa9\x5c\x9a\xd8\x82\x12\x61\x54\xf8\xa7\x3a\x82\x85\x35\x8b\x3d\x1e\xba\x1d\x70\x83\x14\xcb\x86\x71\xf8\x1e\x57\x9a\xe0\xed\x54\x5a\xb1\x42\xd7\x34\x11\x69\x63\xdd\x2a\x2a\x74\xb9\x54\x26\x15\x46\xd5\x80\xc3\xa5\xf2\x84\x2e\x0a\x6d\x4e\xc9\x95\x78\x0a\x04\x77\x45\x41\x9b\x0e\xfd\x9f\xf6\x86\xa3\xe9\xe1\x82\x76\xb3\x6a\xcd\x42\x2d\xd3\x53\xa7\xec\xc3\xf1\x13\xcf\xf5\x73\x9d\x9f\x23\x06\x4b\x35\xac\x6e\xb0\xcd\x7e\xf0\x9f\x17\x46\x98\xfb\xb0\x75\x76\x76\xba\xff\xf9\xa1\x6a\xb1\x2e\x6b\xd6\x03\x77\x2f\x78\xec\xdf\x00\x00\x00\xff\xff\xd8\xea\x02\x37\xe6\x07\x00\x00")
+var _bootstrapUbuntuSh = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xad\x55\x6d\x6f\x22\x37\x10\xfe\xbe\xbf\x62\x4a\xd0\x15\xd4\xec\xee\xe5\x9a\x9e\x74\xc9\x51\x95\x02\xa9\x50\x73\x10\x01\xd7\xf6\x14\xa5\x2b\xb3\x1e\xc0\x62\xd7\x5e\xd9\x5e\xb8\x28\xda\xfe\xf6\x8e\xf7\x85\x10\x74\xbd\x0f\xa7\x7e\x40\xd8\x9e\x67\x1e\x8f\x67\x9e\x99\x3d\xfb\x2e\x5c\x0a\x19\x2e\x99\xd9\x78\x9e\x41\x0b\xbe\x02\xd4\x1a\x3f\x0b\xdb\x6c\x33\x91\xe1\x8a\x89\xa4\xd9\x4b\x95\x4b\x5a\x7a\xde\x2a\x97\xb1\x15\x4a\xc2\x1a\x6d\x94\xb2\xcf\x51\xa6\xb8\xe9\x74\xe1\xc9\x03\xd8\x6f\x44\x82\xa0\x91\x71\x10\xd2\x58\x26\x63\x8c\xec\x63\x86\xe0\x30\xd7\xc0\x15\x61\x00\xc4\x0a\xe0\xfe\x1e\x5a\xed\xa7\x17\xa0\xa2\x05\xbd\x9e\x3b\xbd\xa0\xd5\xc3\x03\xbc\x7a\x55\xa3\x9c\xb3\x33\xfe\x03\x7f\xdf\xbf\xf6\xdf\x3d\xfc\xd0\x76\xe6\x6b\xb0\x1b\x94\x25\x21\x00\xc6\x1b\x05\x35\xf2\xba\x3e\xd3\x68\x73\x5d\x01\x56\x82\xfe\xb8\x92\x08\xef\x21\x44\x1b\x87\xb8\x35\xb1\x4d\xc2\x26\xfc\x20\x65\x99\x57\x78\xde\x64\x3a\x1c\x45\xe3\xbb\x5e\xab\xdd\x89\x73\x9d\x80\xef\x1b\x7a\x90\xb4\xb0\xb1\x36\xbb\x0a\xc3\x8b\xb7\xef\x82\x37\x3f\x5d\x06\xf5\x7f\x98\x30\x8b\xc6\x86\x29\x5a\xe6\x73\x66\x59\x98\xa8\x98\x25\xbe\xc8\x76\x97\xdd\x96\x37\x9e\xcc\x17\xfd\xc9\x80\x18\x87\xdf\xce\xd8\xa4\xc8\x17\xfc\x98\x72\xf1\xe9\x6e\xf4\x3f\x90\xba\xbc\x13\x6d\xff\xcf\x79\x34\x1f\xcd\xfe\x18\x0f\x46\xf3\x68\x38\xfd\xd0\x1f\x4f\xbe\x9d\xdc\xa0\xde\x89\x18\x4d\xc8\x55\xca\x84\x24\x7a\xcf\xa8\x5c\xc7\xf8\x22\xf5\xdb\x7c\x89\x09\xda\x00\xe5\x0e\xce\xa8\x94\xc2\x40\xcc\x24\xa8\x1d\xe9\x50\x70\x84\x0f\xfd\xbf\xa2\xbb\xe9\x70\xee\x79\x31\xb3\xf0\xf3\x17\x7d\xcb\x6c\x97\x0c\xef\xdf\x8f\xa6\x37\x87\xf2\xb5\x9f\xea\x55\xf1\xa2\x06\xed\xa7\xa3\x5d\x71\x92\xcb\x23\xa3\xdb\x17\x5e\x13\x00\x59\x9a\xe5\x95\xdf\xee\x1c\xab\xde\x89\xf3\xa5\x57\xab\x5b\x78\x2e\x12\xcf\x48\x96\x01\x4b\x04\x33\x50\x47\xeb\x53\xf0\x41\xbd\x6e\xce\x4e\x61\xf4\xb8\x03\x8c\xd6\xcd\x59\x05\x33\x56\x65\xc7\x64\x9e\x79\x34\x16\x53\x87\xd3\x48\xad\xe9\xbb\x76\x45\xee\x79\x1d\x12\xfb\x19\x2c\xa6\xc3\xe9\x95\xeb\x11\x83\x60\x36\x2a\x4f\x38\x2c\x11\x12\xa5\xb6\xc8\x81\x52\x8a\x94\xe9\x47\xb0\x22\xc5\x86\x94\x6e\x60\xda\x1a\xc8\xb3\xf3\x92\x81\xba\x39\xde\x00\x15\x66\xbf\x21\xfc\x1e\xa9\x83\xa8\xad\xa1\x7f\xfb\x06\x3a\x07\x1b\xcd\x10\xe2\xa3\x71\x90\x25\x54\x6c\xa8\x62\xe2\x15\x01\x93\x1c\x52\x64\xa4\x1d\xab\xdc\xe5\x99\xd2\x96\x2d\x69\x42\xd0\x36\x55\xc6\x36\x68\xe0\xc2\x58\xad\x4c\xf7\x1c\x96\xb9\x05\x61\xbf\x37\xa5\xbf\x54\x16\xe2\x04\x99\x86\x8d\xda\x3b\xa7\x44\xd1\x64\xa9\x9e\xb4\xd2\x2a\x7d\x0e\xdc\xe5\x67\x2f\x2c\x3d\x93\x74\xca\x76\x42\xae\x4b\x02\x72\x89\x73\xca\x5b\x2a\xc8\x83\xfc\x2a\xa0\xb0\x06\x93\x15\x01\xbe\x22\xcb\x83\xb4\xbe\x0e\xfb\x4f\x80\x6b\x07\xd7\x0d\x25\x82\x20\xab\x84\xad\x4d\xaf\x53\x4e\xa3\x96\x54\x9c\xfa\x39\x3b\xd2\x69\xab\x32\x90\xb0\x7c\x27\xac\x23\xcd\x35\xa6\xd2\x27\x61\x74\xad\x69\xfc\x6e\xfb\xbf\x8e\x6e\xe7\xc5\x39\x4b\xb2\x0d\x5d\x54\x5e\x1c\x08\x75\x3c\x32\x4e\x34\x5f\x73\xd1\x15\xbe\x90\x2b\xcd\xfc\x58\x49\x4b\x65\x43\xed\x8b\x94\xad\x91\xe0\x74\x67\x34\x9e\xdc\xcc\xfa\xd1\x60\x3a\x59\xd0\x20\x18\xcd\xa2\xd9\xe8\xb7\xf1\x7c\x31\xfb\x44\xfa\x7f\x72\xa3\x62\xf4\x3b\xfd\x06\xb3\xa8\x3f\x18\x4c\x3f\x4e\x16\x45\xc0\xb7\x3a\xc0\x58\x07\x95\x79\x38\xba\xe9\x7f\xbc\x5d\x94\x6e\xd3\x49\x51\x9f\x9e\xcc\x97\xa2\x70\xa9\x0a\x33\x96\x1b\xf4\x59\xca\xdf\x5e\x5e\xfd\x18\x5c\xd4\x01\xc6\x89\xca\xb9\x9f\x69\xb5\xa3\x51\xa0\x7b\x6c\x6f\x1a\x83\x14\x3e\x7d\xb9\x7c\x2e\x74\x2f\x54\x99\x0d\xe9\xc0\x7d\xca\x8e\xcc\xf4\xa4\x55\x65\x77\xe5\x70\x76\x49\x85\xe2\x0d\xe2\xf0\x60\x9d\x4b\x27\xfe\x1e\x57\xf1\x16\x75\x93\x65\xb4\x7b\xa5\xb7\x7e\x96\xe4\x6b\x21\x7b\xe4\x5d\x1b\x34\xae\x49\xa3\xe4\xe6\xea\xd0\xb3\x3a\xc7\x53\x83\x93\x9f\xef\xb8\xed\xa1\x40\x2e\x7d\x8b\x43\x05\xcb\x66\xa6\xe0\xc4\xba\x77\x2a\xa5\xea\x38\x78\x64\x69\xf2\x1c\xe7\x97\x80\x4e\x73\x0d\xaa\xeb\x74\x55\x4d\x86\xe7\x89\xe2\x06\x83\x1b\x4b\xa5\xde\xee\x7f\x79\xa0\xcb\xbb\x5e\x33\x3f\xa8\xbb\x5f\x0c\x90\x7f\x01\x97\x42\x2f\xa5\x07\x08\x00\x00")
 
 func bootstrapUbuntuShBytes() ([]byte, error) {
 	return bindataRead(
@@ -128,7 +128,7 @@ func bootstrapUbuntuSh() (*asset, error) {
 	}
 
 	info := bindataFileInfo{name: "bootstrap.ubuntu.sh", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
-	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xdd, 0x32, 0xe5, 0x6b, 0xba, 0xaf, 0x62, 0x53, 0x86, 0xf7, 0x69, 0x69, 0x6a, 0x2f, 0x2c, 0x18, 0xb2, 0xe2, 0x2b, 0xbd, 0xc5, 0x85, 0x74, 0xe7, 0x50, 0x2f, 0xa9, 0xc, 0x6a, 0xb4, 0xa0, 0x1}}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd5, 0xba, 0x2e, 0x62, 0x8f, 0x8e, 0xfe, 0x63, 0x20, 0xf0, 0xdd, 0x5c, 0x68, 0xfb, 0xe5, 0xea, 0xc0, 0x2f, 0x2a, 0xc4, 0xd0, 0xdb, 0x3b, 0xb5, 0x32, 0xc0, 0x6, 0xdd, 0x9c, 0xf2, 0xfe, 0x10}}
 	return a, nil
 }
 
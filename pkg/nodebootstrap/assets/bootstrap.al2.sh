@@ -29,6 +29,9 @@ else
 fi
 
 source /etc/eksctl/kubelet.env # this can override MAX_PODS
+source /etc/eksctl/metadata.env # POD_INFRA_CONTAINER_REGISTRY is only set with containerRegistryOverride
+
+POD_INFRA_CONTAINER_REGISTRY="${POD_INFRA_CONTAINER_REGISTRY:-${AWS_EKS_ECR_ACCOUNT}.dkr.ecr.${AWS_DEFAULT_REGION}.${AWS_SERVICES_DOMAIN}}"
 
 cat > /etc/eksctl/kubelet.local.env <<EOF
 NODE_IP=${NODE_IP}
@@ -37,6 +40,7 @@ INSTANCE_TYPE=${INSTANCE_TYPE}
 AWS_SERVICES_DOMAIN=${AWS_SERVICES_DOMAIN}
 MAX_PODS=${MAX_PODS:-$(get_max_pods "${INSTANCE_TYPE}")}
 ARCH=${ARCH}
+POD_INFRA_CONTAINER_IMAGE=${POD_INFRA_CONTAINER_REGISTRY}/eks/pause-${ARCH}:3.1
 EOF
 
 systemctl daemon-reload
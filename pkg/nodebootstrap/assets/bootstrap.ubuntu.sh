@@ -46,7 +46,7 @@ systemctl reset-failed
     "node-ip=${NODE_IP}"
     "max-pods=${MAX_PODS}"
     "node-labels=${NODE_LABELS},alpha.eksctl.io/instance-id=${INSTANCE_ID}"
-    "pod-infra-container-image=${AWS_EKS_ECR_ACCOUNT}.dkr.ecr.${AWS_DEFAULT_REGION}.${AWS_SERVICES_DOMAIN}/eks/pause-amd64:3.1"
+    "pod-infra-container-image=${POD_INFRA_CONTAINER_REGISTRY:-${AWS_EKS_ECR_ACCOUNT}.dkr.ecr.${AWS_DEFAULT_REGION}.${AWS_SERVICES_DOMAIN}}/eks/pause-amd64:3.1"
     "cloud-provider=aws"
     "cni-bin-dir=/opt/cni/bin"
     "cni-conf-dir=/etc/cni/net.d"
@@ -0,0 +1,69 @@
+package nodebootstrap
+
+import (
+	"fmt"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cloudconfig"
+)
+
+const instanceStoreScriptName = "setup-instance-store.sh"
+
+// instanceStoreScriptTemplate formats and mounts the NVMe instance store volumes;
+// when more than one volume is present and RAID0 is enabled, the volumes are
+// combined into a single striped array first
+const instanceStoreScriptTemplate = `#!/bin/bash
+
+set -o errexit
+set -o pipefail
+set -o nounset
+
+MOUNT_PATH=%q
+RAID0=%t
+
+devices=($(lsblk --nodeps --noheadings --output NAME,MODEL | awk '/Amazon EC2 NVMe Instance Storage/ {print "/dev/"$1}'))
+
+if [ "${#devices[@]}" -eq 0 ] ; then
+  echo "no NVMe instance store volumes found, skipping setup of ${MOUNT_PATH}"
+  exit 0
+fi
+
+if [ "${#devices[@]}" -gt 1 ] && [ "${RAID0}" = "true" ] ; then
+  device=/dev/md0
+  mdadm --create --force --verbose "${device}" --level=0 --raid-devices="${#devices[@]}" "${devices[@]}"
+else
+  device="${devices[0]}"
+fi
+
+mkfs.ext4 -F "${device}"
+
+stopped=()
+for service in docker containerd ; do
+  if systemctl is-active --quiet "${service}" ; then
+    systemctl stop "${service}"
+    stopped+=("${service}")
+  fi
+done
+
+mkdir -p "${MOUNT_PATH}"
+mount -o defaults,noatime "${device}" "${MOUNT_PATH}"
+echo "${device} ${MOUNT_PATH} ext4 defaults,noatime,nofail 0 2" >> /etc/fstab
+
+for service in ${stopped[@]+"${stopped[@]}"} ; do
+  systemctl start "${service}"
+done
+`
+
+func makeInstanceStoreScript(instanceStore *api.NodeGroupInstanceStore) string {
+	return fmt.Sprintf(instanceStoreScriptTemplate, instanceStore.MountPath, api.IsEnabled(instanceStore.RAID0))
+}
+
+// addInstanceStoreScript adds the instance store setup script, it must be
+// called before the bootstrap script is added, so that the volumes are
+// mounted before the kubelet and the container runtime use them
+func addInstanceStoreScript(config *cloudconfig.CloudConfig, ng *api.NodeGroup) {
+	if ng.InstanceStore == nil {
+		return
+	}
+	config.RunScript(instanceStoreScriptName, makeInstanceStoreScript(ng.InstanceStore))
+}
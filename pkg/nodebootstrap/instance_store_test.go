@@ -0,0 +1,48 @@
+package nodebootstrap
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cloudconfig"
+)
+
+var _ = Describe("Instance store", func() {
+	var (
+		config *cloudconfig.CloudConfig
+		ng     *api.NodeGroup
+	)
+
+	BeforeEach(func() {
+		config = cloudconfig.New()
+		ng = api.NewNodeGroup()
+	})
+
+	It("does nothing when instanceStore is not set", func() {
+		addInstanceStoreScript(config, ng)
+		Expect(config.WriteFiles).To(BeEmpty())
+		Expect(config.Commands).To(BeEmpty())
+	})
+
+	It("adds a script that mounts a RAID0 array", func() {
+		ng.InstanceStore = &api.NodeGroupInstanceStore{
+			RAID0:     api.Enabled(),
+			MountPath: "/var/lib/docker",
+		}
+		addInstanceStoreScript(config, ng)
+
+		Expect(config.WriteFiles).To(HaveLen(1))
+		Expect(config.WriteFiles[0].Path).To(HaveSuffix(instanceStoreScriptName))
+		Expect(config.WriteFiles[0].Content).To(ContainSubstring(`MOUNT_PATH="/var/lib/docker"`))
+		Expect(config.WriteFiles[0].Content).To(ContainSubstring("RAID0=true"))
+		Expect(config.Commands).To(HaveLen(1))
+	})
+
+	It("disables RAID0 by default", func() {
+		ng.InstanceStore = &api.NodeGroupInstanceStore{
+			MountPath: "/mnt/data",
+		}
+		Expect(makeInstanceStoreScript(ng.InstanceStore)).To(ContainSubstring("RAID0=false"))
+	})
+})
@@ -0,0 +1,6 @@
+package nodebootstrap
+
+import log "github.com/weaveworks/eksctl/pkg/logger"
+
+// logger logs messages of the nodegroup subsystem
+var logger = log.WithScope("nodegroup")
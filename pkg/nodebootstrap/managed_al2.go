@@ -0,0 +1,95 @@
+package nodebootstrap
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+const (
+	managedKubeletConfigFile     = "/etc/kubernetes/kubelet/kubelet-config.json"
+	managedKubeletExtraConfigDir = "/etc/eksctl/"
+	managedBootstrapScript       = "/etc/eks/bootstrap.sh"
+)
+
+// NewUserDataForManagedNodeGroup creates MIME multi-part user data for the launch template
+// of a managed nodegroup; EKS merges it with its own bootstrap script, so the generated
+// script only adjusts the kubelet configuration before the node joins the cluster
+func NewUserDataForManagedNodeGroup(ng *api.ManagedNodeGroup) (string, error) {
+	script, err := makeManagedKubeletConfigScript(ng)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		buf  bytes.Buffer
+		body = multipart.NewWriter(&buf)
+	)
+
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\nContent-Type: multipart/mixed; boundary=%q\r\n\r\n", body.Boundary())
+
+	part, err := body.CreatePart(textproto.MIMEHeader{
+		"Content-Type": []string{"text/x-shellscript; charset=\"us-ascii\""},
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "creating user data part")
+	}
+	if _, err := part.Write([]byte(script)); err != nil {
+		return "", errors.Wrap(err, "writing user data part")
+	}
+	if err := body.Close(); err != nil {
+		return "", errors.Wrap(err, "encoding user data")
+	}
+
+	logger.Debug("user-data = %s", buf.String())
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func makeManagedKubeletConfigScript(ng *api.ManagedNodeGroup) (string, error) {
+	extraConfig := api.InlineDocument{}
+	if ng.KubeletExtraConfig != nil {
+		for k, v := range *ng.KubeletExtraConfig {
+			extraConfig[k] = v
+		}
+	}
+	if ng.MaxPodsPerNode != 0 {
+		extraConfig["maxPods"] = ng.MaxPodsPerNode
+	}
+
+	data, err := json.MarshalIndent(extraConfig, "", "  ")
+	if err != nil {
+		return "", errors.Wrap(err, "serialising kubelet extra config")
+	}
+
+	extraConfigFile := managedKubeletExtraConfigDir + "kubelet-extra.json"
+
+	lines := []string{
+		"#!/bin/bash",
+		"set -o errexit",
+		"set -o pipefail",
+		"set -o nounset",
+		"",
+		fmt.Sprintf("mkdir -p %s", managedKubeletExtraConfigDir),
+		fmt.Sprintf("cat > %s <<'EOF'", extraConfigFile),
+		string(data),
+		"EOF",
+		"",
+		fmt.Sprintf("KUBELET_CONFIG=%s", managedKubeletConfigFile),
+		fmt.Sprintf(`echo "$(jq -s '.[0] * .[1]' "${KUBELET_CONFIG}" %s)" > "${KUBELET_CONFIG}"`, extraConfigFile),
+	}
+
+	if ng.MaxPodsPerNode != 0 {
+		// prevent the EKS bootstrap script from overriding maxPods with the value derived from the instance type
+		lines = append(lines, fmt.Sprintf("sed -i 's/^USE_MAX_PODS=.*/USE_MAX_PODS=false/' %s", managedBootstrapScript))
+	}
+
+	return strings.Join(lines, "\n") + "\n", nil
+}
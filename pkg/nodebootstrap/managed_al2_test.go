@@ -0,0 +1,70 @@
+package nodebootstrap
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+var _ = Describe("Managed AL2", func() {
+	decodeScript := func(userData string) string {
+		data, err := base64.StdEncoding.DecodeString(userData)
+		Expect(err).ToNot(HaveOccurred())
+
+		msg, err := mail.ReadMessage(strings.NewReader(string(data)))
+		Expect(err).ToNot(HaveOccurred())
+
+		mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mediaType).To(Equal("multipart/mixed"))
+
+		part, err := multipart.NewReader(msg.Body, params["boundary"]).NextPart()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(part.Header.Get("Content-Type")).To(HavePrefix("text/x-shellscript"))
+
+		script, err := ioutil.ReadAll(part)
+		Expect(err).ToNot(HaveOccurred())
+		return string(script)
+	}
+
+	It("merges kubeletExtraConfig into the kubelet config", func() {
+		ng := api.NewManagedNodeGroup()
+		ng.KubeletExtraConfig = &api.InlineDocument{
+			"kubeReserved": map[string]interface{}{
+				"cpu": "300m",
+			},
+			"featureGates": map[string]bool{
+				"RotateKubeletServerCertificate": true,
+			},
+		}
+
+		userData, err := NewUserDataForManagedNodeGroup(ng)
+		Expect(err).ToNot(HaveOccurred())
+
+		script := decodeScript(userData)
+		Expect(script).To(ContainSubstring(`"kubeReserved": {`))
+		Expect(script).To(ContainSubstring(`"RotateKubeletServerCertificate": true`))
+		Expect(script).To(ContainSubstring(managedKubeletConfigFile))
+		Expect(script).ToNot(ContainSubstring("USE_MAX_PODS"))
+	})
+
+	It("sets maxPods and disables the default max pods calculation", func() {
+		ng := api.NewManagedNodeGroup()
+		ng.MaxPodsPerNode = 42
+
+		userData, err := NewUserDataForManagedNodeGroup(ng)
+		Expect(err).ToNot(HaveOccurred())
+
+		script := decodeScript(userData)
+		Expect(script).To(ContainSubstring(`"maxPods": 42`))
+		Expect(script).To(ContainSubstring("USE_MAX_PODS=false"))
+	})
+})
@@ -81,8 +81,8 @@ func makeClientConfigData(spec *api.ClusterConfig, ng *api.NodeGroup) ([]byte, e
 }
 
 func clusterDNS(spec *api.ClusterConfig, ng *api.NodeGroup) string {
-	if ng.ClusterDNS != "" {
-		return ng.ClusterDNS
+	if dnsIP := explicitClusterDNS(spec, ng); dnsIP != "" {
+		return dnsIP
 	}
 	// Default service network is 10.100.0.0, but it gets set 172.20.0.0 automatically when pod network
 	// is anywhere within 10.0.0.0/8
@@ -92,6 +92,15 @@ func clusterDNS(spec *api.ClusterConfig, ng *api.NodeGroup) string {
 	return "10.100.0.10"
 }
 
+// explicitClusterDNS returns the cluster DNS IP set on the nodegroup or derived from the service
+// CIDR of the cluster, or an empty string when neither is set and EKS picks the service CIDR
+func explicitClusterDNS(spec *api.ClusterConfig, ng *api.NodeGroup) string {
+	if ng.ClusterDNS != "" {
+		return ng.ClusterDNS
+	}
+	return spec.ClusterDNSIP()
+}
+
 func makeKubeletConfigYAML(spec *api.ClusterConfig, ng *api.NodeGroup) ([]byte, error) {
 	data, err := Asset("kubelet.yaml")
 	if err != nil {
@@ -167,13 +176,21 @@ func makeCommonKubeletEnvParams(spec *api.ClusterConfig, ng *api.NodeGroup) []st
 	return variables
 }
 
-func makeMetadata(spec *api.ClusterConfig) []string {
-	return []string{
+// makeMetadata also exposes CLUSTER_DNS, so that an overrideBootstrapCommand of a custom AMI
+// can pass it to its own bootstrap script
+func makeMetadata(spec *api.ClusterConfig, ng *api.NodeGroup) []string {
+	metadata := []string{
 		fmt.Sprintf("AWS_DEFAULT_REGION=%s", spec.Metadata.Region),
 		fmt.Sprintf("AWS_EKS_CLUSTER_NAME=%s", spec.Metadata.Name),
 		fmt.Sprintf("AWS_EKS_ENDPOINT=%s", spec.Status.Endpoint),
 		fmt.Sprintf("AWS_EKS_ECR_ACCOUNT=%s", api.EKSResourceAccountID(spec.Metadata.Region)),
+		fmt.Sprintf("CLUSTER_DNS=%s", clusterDNS(spec, ng)),
+	}
+	if spec.HasContainerRegistryOverride() {
+		// the bootstrap scripts pull the pause image from the EKS registry of the region otherwise
+		metadata = append(metadata, fmt.Sprintf("POD_INFRA_CONTAINER_REGISTRY=%s", spec.ContainerRegistryOverride))
 	}
+	return metadata
 }
 
 func makeMaxPodsMapping() string {
@@ -3,7 +3,6 @@ package nodebootstrap
 import (
 	"strings"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"github.com/weaveworks/eksctl/pkg/cloudconfig"
@@ -29,7 +28,7 @@ func makeAmazonLinux2Config(spec *api.ClusterConfig, ng *api.NodeGroup) (configF
 			"10-eksclt.al2.conf": {isAsset: true},
 		},
 		configDir: {
-			"metadata.env": {content: strings.Join(makeMetadata(spec), "\n")},
+			"metadata.env": {content: strings.Join(makeMetadata(spec, ng), "\n")},
 			"kubelet.env":  {content: strings.Join(makeCommonKubeletEnvParams(spec, ng), "\n")},
 			"kubelet.yaml": {content: string(kubeletConfigData)},
 			// TODO: https://github.com/weaveworks/eksctl/issues/161
@@ -53,6 +52,9 @@ func NewUserDataForAmazonLinux2(spec *api.ClusterConfig, ng *api.NodeGroup) (str
 
 	var scripts []string
 
+	addInstanceStoreScript(config, ng)
+	addAdditionalVolumesScript(config, ng)
+
 	for _, command := range ng.PreBootstrapCommands {
 		config.AddShellCommand(command)
 	}
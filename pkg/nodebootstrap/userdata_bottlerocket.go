@@ -19,7 +19,7 @@ func NewUserDataForBottlerocket(spec *api.ClusterConfig, ng *api.NodeGroup) (str
 	// Update settings based on NodeGroup configuration. Values set here are not
 	// allowed to be set by the user - the values are owned by the NodeGroup and
 	// expressly written into settings.
-	if err := setDerivedBottlerocketSettings(ng); err != nil {
+	if err := setDerivedBottlerocketSettings(spec, ng); err != nil {
 		return "", err
 	}
 
@@ -43,7 +43,7 @@ func NewUserDataForBottlerocket(spec *api.ClusterConfig, ng *api.NodeGroup) (str
 	return base64.StdEncoding.EncodeToString([]byte(data)), nil
 }
 
-func setDerivedBottlerocketSettings(ng *api.NodeGroup) error {
+func setDerivedBottlerocketSettings(spec *api.ClusterConfig, ng *api.NodeGroup) error {
 	settings := *ng.Bottlerocket.Settings
 
 	var kubernetesSettings map[string]interface{}
@@ -67,8 +67,8 @@ func setDerivedBottlerocketSettings(ng *api.NodeGroup) error {
 	if ng.MaxPodsPerNode != 0 {
 		kubernetesSettings["max-pods"] = ng.MaxPodsPerNode
 	}
-	if ng.ClusterDNS != "" {
-		kubernetesSettings["cluster-dns-ip"] = ng.ClusterDNS
+	if dnsIP := explicitClusterDNS(spec, ng); dnsIP != "" {
+		kubernetesSettings["cluster-dns-ip"] = dnsIP
 	}
 	return nil
 }
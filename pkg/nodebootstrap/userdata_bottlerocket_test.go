@@ -203,6 +203,18 @@ var _ = Describe("Bottlerocket", func() {
 				Expect(tree.GetPath(clusterDNSIPPath)).To(Equal(ng.ClusterDNS))
 			})
 
+			It("uses the cluster DNS IP of the service CIDR", func() {
+				clusterConfig.KubernetesNetworkConfig = &api.KubernetesNetworkConfig{ServiceIPv4CIDR: "172.16.0.0/16"}
+
+				userdata, err := NewUserDataForBottlerocket(clusterConfig, ng)
+				Expect(err).ToNot(HaveOccurred())
+
+				tree, parseErr := userdataTOML(userdata)
+				Expect(parseErr).ToNot(HaveOccurred())
+
+				Expect(tree.GetPath(clusterDNSIPPath)).To(Equal("172.16.0.10"))
+			})
+
 			It("uses Taints", func() {
 				taintName := "mytaint.example.com"
 				taintVal := "00.00001"
@@ -90,4 +90,41 @@ var _ = Describe("User data", func() {
 			Expect(kubelet.FeatureGates["RotateKubeletServerCertificate"]).To(Equal(false))
 		})
 	})
+
+	Describe("cluster DNS", func() {
+		var (
+			clusterConfig *api.ClusterConfig
+			ng            *api.NodeGroup
+		)
+		BeforeEach(func() {
+			clusterConfig = api.NewClusterConfig()
+			ng = &api.NodeGroup{}
+		})
+
+		clusterDNSOf := func() []string {
+			data, err := makeKubeletConfigYAML(clusterConfig, ng)
+			Expect(err).ToNot(HaveOccurred())
+			kubelet := kubeletapi.KubeletConfiguration{}
+			Expect(yaml.UnmarshalStrict(data, &kubelet)).To(Succeed())
+			return kubelet.ClusterDNS
+		}
+
+		It("defaults to the service CIDR EKS picks for the VPC", func() {
+			Expect(clusterDNSOf()).To(Equal([]string{"10.100.0.10"}))
+		})
+
+		It("is derived from the service CIDR of the cluster", func() {
+			clusterConfig.KubernetesNetworkConfig = &api.KubernetesNetworkConfig{ServiceIPv4CIDR: "172.16.0.0/16"}
+			Expect(clusterDNSOf()).To(Equal([]string{"172.16.0.10"}))
+
+			clusterConfig.Status = &api.ClusterStatus{}
+			Expect(makeMetadata(clusterConfig, ng)).To(ContainElement("CLUSTER_DNS=172.16.0.10"))
+		})
+
+		It("uses the clusterDNS of the nodegroup", func() {
+			clusterConfig.KubernetesNetworkConfig = &api.KubernetesNetworkConfig{ServiceIPv4CIDR: "172.16.0.0/16"}
+			ng.ClusterDNS = "172.16.0.53"
+			Expect(clusterDNSOf()).To(Equal([]string{"172.16.0.53"}))
+		})
+	})
 })
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"strconv"
 
-	"github.com/kris-nova/logger"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 )
 
@@ -23,7 +22,11 @@ func newUserDataForWindows(spec *api.ClusterConfig, ng *api.NodeGroup) (string,
 	}
 
 	kubeletArgs := toCLIArgs(kubeletOptions)
-	bootstrapScript += fmt.Sprintf("& $EKSBootstrapScriptFile -EKSClusterName %q -KubeletExtraArgs %q 3>&1 4>&1 5>&1 6>&1\n</powershell>", spec.Metadata.Name, kubeletArgs)
+	bootstrapArgs := fmt.Sprintf("-EKSClusterName %q -KubeletExtraArgs %q", spec.Metadata.Name, kubeletArgs)
+	if dnsIP := explicitClusterDNS(spec, ng); dnsIP != "" {
+		bootstrapArgs += fmt.Sprintf(" -DNSClusterIP %q", dnsIP)
+	}
+	bootstrapScript += fmt.Sprintf("& $EKSBootstrapScriptFile %s 3>&1 4>&1 5>&1 6>&1\n</powershell>", bootstrapArgs)
 
 	userData := base64.StdEncoding.EncodeToString([]byte(bootstrapScript))
 
@@ -0,0 +1,88 @@
+package nodebootstrap
+
+import (
+	"fmt"
+	"strings"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cloudconfig"
+)
+
+const additionalVolumesScriptName = "setup-additional-volumes.sh"
+
+// additionalVolumesScriptTemplate formats (unless a filesystem already exists) and mounts
+// the additional EBS volumes; on Nitro instances EBS volumes are exposed as NVMe devices,
+// so the requested device name is looked up in the vendor specific controller data
+const additionalVolumesScriptTemplate = `#!/bin/bash
+
+set -o errexit
+set -o pipefail
+set -o nounset
+
+short_name() {
+  local name="${1#/dev/}"
+  name="${name#xvd}"
+  echo "${name#sd}"
+}
+
+find_device() {
+  local device="${1}"
+  if [ -b "${device}" ] ; then
+    echo "${device}"
+    return
+  fi
+  for nvme in /dev/nvme*n1 ; do
+    [ -b "${nvme}" ] || continue
+    name="$(nvme id-ctrl --raw-binary "${nvme}" 2> /dev/null | cut -c3073-3104 | tr -d ' \0' || true)"
+    if [ -n "${name}" ] && [ "$(short_name "${name}")" = "$(short_name "${device}")" ] ; then
+      echo "${nvme}"
+      return
+    fi
+  done
+}
+
+setup_volume() {
+  local requested="${1}" mount_path="${2}" device=""
+  for _ in $(seq 60) ; do
+    device="$(find_device "${requested}")"
+    [ -n "${device}" ] && break
+    sleep 1
+  done
+  if [ -z "${device}" ] ; then
+    echo "volume ${requested} not found"
+    exit 1
+  fi
+
+  if ! blkid "${device}" > /dev/null ; then
+    mkfs.ext4 "${device}"
+  fi
+
+  mkdir -p "${mount_path}"
+  mount -o defaults,noatime "${device}" "${mount_path}"
+  echo "UUID=$(blkid -s UUID -o value "${device}") ${mount_path} ext4 defaults,noatime,nofail 0 2" >> /etc/fstab
+}
+
+%s
+`
+
+func makeAdditionalVolumesScript(volumes []*api.VolumeMapping) string {
+	var calls []string
+	for _, volume := range volumes {
+		if volume.MountPath == "" {
+			continue
+		}
+		calls = append(calls, fmt.Sprintf("setup_volume %q %q", volume.Name, volume.MountPath))
+	}
+	if len(calls) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(additionalVolumesScriptTemplate, strings.Join(calls, "\n"))
+}
+
+// addAdditionalVolumesScript adds the script that mounts the additional volumes which
+// have a mount path, it must be called before the bootstrap script is added
+func addAdditionalVolumesScript(config *cloudconfig.CloudConfig, ng *api.NodeGroup) {
+	if script := makeAdditionalVolumesScript(ng.AdditionalVolumes); script != "" {
+		config.RunScript(additionalVolumesScriptName, script)
+	}
+}
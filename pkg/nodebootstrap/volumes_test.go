@@ -0,0 +1,60 @@
+package nodebootstrap
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/cloudconfig"
+)
+
+var _ = Describe("Additional volumes", func() {
+	var (
+		config *cloudconfig.CloudConfig
+		ng     *api.NodeGroup
+		size   = 100
+	)
+
+	BeforeEach(func() {
+		config = cloudconfig.New()
+		ng = api.NewNodeGroup()
+	})
+
+	It("does nothing when no volume has a mount path", func() {
+		ng.AdditionalVolumes = []*api.VolumeMapping{{
+			Name: "/dev/xvdb",
+			Size: &size,
+		}}
+		addAdditionalVolumesScript(config, ng)
+		Expect(config.WriteFiles).To(BeEmpty())
+		Expect(config.Commands).To(BeEmpty())
+	})
+
+	It("adds a script that mounts the volumes with a mount path", func() {
+		ng.AdditionalVolumes = []*api.VolumeMapping{
+			{
+				Name:      "/dev/xvdb",
+				Size:      &size,
+				MountPath: "/mnt/data",
+			},
+			{
+				Name: "/dev/xvdc",
+				Size: &size,
+			},
+			{
+				Name:      "/dev/xvdd",
+				Size:      &size,
+				MountPath: "/var/lib/docker",
+			},
+		}
+		addAdditionalVolumesScript(config, ng)
+
+		Expect(config.WriteFiles).To(HaveLen(1))
+		Expect(config.WriteFiles[0].Path).To(HaveSuffix(additionalVolumesScriptName))
+		script := config.WriteFiles[0].Content
+		Expect(script).To(ContainSubstring(`setup_volume "/dev/xvdb" "/mnt/data"`))
+		Expect(script).To(ContainSubstring(`setup_volume "/dev/xvdd" "/var/lib/docker"`))
+		Expect(script).ToNot(ContainSubstring(`"/dev/xvdc"`))
+		Expect(config.Commands).To(HaveLen(1))
+	})
+})
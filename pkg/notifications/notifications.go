@@ -0,0 +1,172 @@
+// Package notifications publishes structured events about operations on
+// a cluster to SNS, EventBridge and webhooks
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// Event types
+const (
+	ClusterCreated         = "ClusterCreated"
+	ClusterCreateFailed    = "ClusterCreateFailed"
+	ClusterUpgradeStarted  = "ClusterUpgradeStarted"
+	ClusterUpgradeFinished = "ClusterUpgradeFinished"
+	ClusterUpgradeFailed   = "ClusterUpgradeFailed"
+	ClusterDeleted         = "ClusterDeleted"
+	ClusterDeleteFailed    = "ClusterDeleteFailed"
+)
+
+// eventSource is the source of EventBridge events
+const eventSource = "eksctl"
+
+// Event is a structured event about an operation on a cluster
+type Event struct {
+	Type    string    `json:"type"`
+	Cluster string    `json:"cluster"`
+	Region  string    `json:"region"`
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+	// Details hold event specific information, e.g. the target
+	// Kubernetes version or the resources that failed to delete
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// Notifier publishes events to the destinations configured in the notifications section
+type Notifier struct {
+	config      *api.Notifications
+	meta        *api.ClusterMeta
+	sns         snsiface.SNSAPI
+	eventBridge eventbridgeiface.EventBridgeAPI
+	httpClient  *http.Client
+	now         func() time.Time
+}
+
+// NewNotifier creates a notifier for the cluster, which does nothing
+// if no notifications are configured
+func NewNotifier(cfg *api.ClusterConfig, provider api.ClusterProvider) *Notifier {
+	return &Notifier{
+		config:      cfg.Notifications,
+		meta:        cfg.Metadata,
+		sns:         provider.SNS(),
+		eventBridge: provider.EventBridge(),
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		now:         time.Now,
+	}
+}
+
+// Enabled returns true if any notifications are configured
+func (n *Notifier) Enabled() bool {
+	return n.config != nil
+}
+
+// Notify publishes an event to all destinations; notifications are best-effort,
+// so failures are logged as warnings rather than failing the operation
+func (n *Notifier) Notify(eventType, message string, details map[string]interface{}) {
+	if !n.Enabled() {
+		return
+	}
+	event := Event{
+		Type:    eventType,
+		Cluster: n.meta.Name,
+		Region:  n.meta.Region,
+		Time:    n.now().UTC(),
+		Message: message,
+		Details: details,
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		logger.Warning("unable to serialise %s event: %v", eventType, err)
+		return
+	}
+
+	if n.config.SNSTopicARN != "" {
+		if err := n.publishSNS(event, data); err != nil {
+			logger.Warning("unable to publish %s event to SNS topic %q: %v", eventType, n.config.SNSTopicARN, err)
+		}
+	}
+	if n.config.EventBusName != "" {
+		if err := n.putEvent(event, data); err != nil {
+			logger.Warning("unable to put %s event on event bus %q: %v", eventType, n.config.EventBusName, err)
+		}
+	}
+	if n.config.WebhookURL != "" {
+		if err := n.postWebhook(event); err != nil {
+			logger.Warning("unable to post %s event to webhook: %v", eventType, err)
+		}
+	}
+}
+
+func (n *Notifier) publishSNS(event Event, data []byte) error {
+	_, err := n.sns.Publish(&sns.PublishInput{
+		TopicArn: aws.String(n.config.SNSTopicARN),
+		Subject:  aws.String(fmt.Sprintf("eksctl: %s %s", event.Type, event.Cluster)),
+		Message:  aws.String(string(data)),
+		MessageAttributes: map[string]*sns.MessageAttributeValue{
+			"type": {
+				DataType:    aws.String("String"),
+				StringValue: aws.String(event.Type),
+			},
+		},
+	})
+	return err
+}
+
+func (n *Notifier) putEvent(event Event, data []byte) error {
+	output, err := n.eventBridge.PutEvents(&eventbridge.PutEventsInput{
+		Entries: []*eventbridge.PutEventsRequestEntry{{
+			EventBusName: aws.String(n.config.EventBusName),
+			Source:       aws.String(eventSource),
+			DetailType:   aws.String(event.Type),
+			Detail:       aws.String(string(data)),
+			Time:         aws.Time(event.Time),
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if aws.Int64Value(output.FailedEntryCount) > 0 && len(output.Entries) > 0 {
+		return fmt.Errorf("%s: %s", aws.StringValue(output.Entries[0].ErrorCode), aws.StringValue(output.Entries[0].ErrorMessage))
+	}
+	return nil
+}
+
+// webhookPayload is accepted by Slack incoming webhooks, which display text
+// and ignore the event, while other receivers can process the event
+type webhookPayload struct {
+	Text  string `json:"text"`
+	Event Event  `json:"event"`
+}
+
+func (n *Notifier) postWebhook(event Event) error {
+	payload, err := json.Marshal(webhookPayload{
+		Text:  fmt.Sprintf("[eksctl] %s: cluster %q in %q: %s", event.Type, event.Cluster, event.Region, event.Message),
+		Event: event,
+	})
+	if err != nil {
+		return err
+	}
+	resp, err := n.httpClient.Post(n.config.WebhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return errors.Wrap(err, "posting event")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %q", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/sns"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("notifications", func() {
+	var (
+		cfg       *api.ClusterConfig
+		published []*sns.PublishInput
+		putEvents []*eventbridge.PutEventsInput
+		server    *httptest.Server
+		payloads  []webhookPayload
+		notifier  *Notifier
+		now       = time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+	)
+
+	BeforeEach(func() {
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "cluster-1"
+		cfg.Metadata.Region = "eu-north-1"
+
+		published, putEvents, payloads = nil, nil, nil
+		p := mockprovider.NewMockProvider()
+		p.MockSNS().On("Publish", mock.Anything).Return(&sns.PublishOutput{}, nil).Run(func(args mock.Arguments) {
+			published = append(published, args.Get(0).(*sns.PublishInput))
+		})
+		p.MockEventBridge().On("PutEvents", mock.Anything).Return(&eventbridge.PutEventsOutput{FailedEntryCount: aws.Int64(0)}, nil).Run(func(args mock.Arguments) {
+			putEvents = append(putEvents, args.Get(0).(*eventbridge.PutEventsInput))
+		})
+
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			var payload webhookPayload
+			if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			payloads = append(payloads, payload)
+		}))
+
+		cfg.Notifications = &api.Notifications{
+			SNSTopicARN:  "arn:aws:sns:eu-north-1:123456789012:clusters",
+			EventBusName: "fleet",
+			WebhookURL:   server.URL,
+		}
+		notifier = NewNotifier(cfg, p)
+		notifier.httpClient = server.Client()
+		notifier.now = func() time.Time { return now }
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("publishes events to all destinations", func() {
+		notifier.Notify(ClusterDeleteFailed, "failed to delete cluster", map[string]interface{}{
+			"failedResources": []string{"eksctl-cluster-1-cluster/VPC"},
+		})
+
+		expected := Event{
+			Type:    ClusterDeleteFailed,
+			Cluster: "cluster-1",
+			Region:  "eu-north-1",
+			Time:    now,
+			Message: "failed to delete cluster",
+			Details: map[string]interface{}{"failedResources": []interface{}{"eksctl-cluster-1-cluster/VPC"}},
+		}
+
+		Expect(published).To(HaveLen(1))
+		Expect(*published[0].TopicArn).To(Equal("arn:aws:sns:eu-north-1:123456789012:clusters"))
+		Expect(*published[0].MessageAttributes["type"].StringValue).To(Equal(ClusterDeleteFailed))
+		var event Event
+		Expect(json.Unmarshal([]byte(*published[0].Message), &event)).To(Succeed())
+		Expect(event).To(Equal(expected))
+
+		Expect(putEvents).To(HaveLen(1))
+		entry := putEvents[0].Entries[0]
+		Expect(*entry.EventBusName).To(Equal("fleet"))
+		Expect(*entry.Source).To(Equal("eksctl"))
+		Expect(*entry.DetailType).To(Equal(ClusterDeleteFailed))
+		Expect(*entry.Detail).To(Equal(*published[0].Message))
+
+		Expect(payloads).To(HaveLen(1))
+		Expect(payloads[0].Text).To(Equal(`[eksctl] ClusterDeleteFailed: cluster "cluster-1" in "eu-north-1": failed to delete cluster`))
+		Expect(payloads[0].Event).To(Equal(expected))
+	})
+
+	It("does nothing without notifications", func() {
+		notifier.config = nil
+		notifier.Notify(ClusterCreated, "created cluster", nil)
+
+		Expect(published).To(BeEmpty())
+		Expect(putEvents).To(BeEmpty())
+		Expect(payloads).To(BeEmpty())
+	})
+})
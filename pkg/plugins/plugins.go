@@ -0,0 +1,104 @@
+// Package plugins finds and runs eksctl plugins, i.e. executables on PATH named
+// eksctl-<name>, which are run as `eksctl <name>`
+package plugins
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Prefix is the prefix of the names of plugin executables
+const Prefix = "eksctl-"
+
+// Plugin is an executable found on PATH
+type Plugin struct {
+	// Name is the name of the command, e.g. "foo-bar" for eksctl-foo-bar,
+	// which can be run as either `eksctl foo-bar` or `eksctl foo bar`
+	Name string
+	Path string
+	// Shadowed lists the paths of executables with the same name further down PATH
+	Shadowed []string
+}
+
+// Find finds the plugin for the longest prefix of args that doesn't contain flags,
+// returning the plugin along with the remaining args; e.g. for ["foo", "bar", "--baz"]
+// eksctl-foo-bar is tried before eksctl-foo
+func Find(args []string) (*Plugin, []string, bool) {
+	var names []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		names = append(names, arg)
+	}
+
+	for n := len(names); n > 0; n-- {
+		name := strings.Join(names[:n], "-")
+		if path, err := exec.LookPath(Prefix + name); err == nil {
+			return &Plugin{Name: name, Path: path}, args[n:], true
+		}
+	}
+	return nil, nil, false
+}
+
+// List lists all plugins on PATH
+func List() []*Plugin {
+	plugins := map[string]*Plugin{}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			if file.IsDir() || !strings.HasPrefix(file.Name(), Prefix) || !isExecutable(file) {
+				continue
+			}
+			name := strings.TrimPrefix(file.Name(), Prefix)
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+			path := filepath.Join(dir, file.Name())
+			if plugin, ok := plugins[name]; ok {
+				plugin.Shadowed = append(plugin.Shadowed, path)
+				continue
+			}
+			plugins[name] = &Plugin{Name: name, Path: path}
+		}
+	}
+
+	var list []*Plugin
+	for _, plugin := range plugins {
+		list = append(list, plugin)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func isExecutable(file os.FileInfo) bool {
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(file.Name()))
+		return ext == ".exe" || ext == ".bat" || ext == ".cmd"
+	}
+	return file.Mode()&0111 != 0
+}
+
+// Run runs the plugin with the standard streams of eksctl, returning the exit code
+// of the plugin, or an error if it couldn't be run
+func (p *Plugin) Run(args []string) (int, error) {
+	cmd := exec.Command(p.Path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return exitErr.ExitCode(), nil
+		}
+		return 1, err
+	}
+	return 0, nil
+}
@@ -0,0 +1,74 @@
+package plugins
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("plugins", func() {
+	var (
+		dirs []string
+		path string
+	)
+
+	plugin := func(dir, name string, mode os.FileMode) string {
+		p := filepath.Join(dir, name)
+		Expect(ioutil.WriteFile(p, []byte("#!/bin/sh\nexit 3\n"), mode)).To(Succeed())
+		return p
+	}
+
+	BeforeEach(func() {
+		dirs = nil
+		for i := 0; i < 2; i++ {
+			dir, err := ioutil.TempDir("", "plugins")
+			Expect(err).NotTo(HaveOccurred())
+			dirs = append(dirs, dir)
+		}
+		path = os.Getenv("PATH")
+		Expect(os.Setenv("PATH", strings.Join(dirs, string(os.PathListSeparator)))).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Setenv("PATH", path)).To(Succeed())
+		for _, dir := range dirs {
+			os.RemoveAll(dir)
+		}
+	})
+
+	It("finds the plugin for the longest prefix of args", func() {
+		foo := plugin(dirs[0], "eksctl-foo", 0755)
+		fooBar := plugin(dirs[1], "eksctl-foo-bar", 0755)
+
+		p, args, ok := Find([]string{"foo", "bar", "baz", "--qux", "quux"})
+		Expect(ok).To(BeTrue())
+		Expect(p.Path).To(Equal(fooBar))
+		Expect(args).To(Equal([]string{"baz", "--qux", "quux"}))
+
+		p, args, ok = Find([]string{"foo", "--bar"})
+		Expect(ok).To(BeTrue())
+		Expect(p.Path).To(Equal(foo))
+		Expect(args).To(Equal([]string{"--bar"}))
+
+		_, _, ok = Find([]string{"bar"})
+		Expect(ok).To(BeFalse())
+	})
+
+	It("lists plugins", func() {
+		first := plugin(dirs[0], "eksctl-foo", 0755)
+		second := plugin(dirs[1], "eksctl-foo", 0755)
+		plugin(dirs[1], "eksctl-bar", 0644)
+		plugin(dirs[1], "kubectl-baz", 0755)
+
+		Expect(List()).To(Equal([]*Plugin{{Name: "foo", Path: first, Shadowed: []string{second}}}))
+	})
+
+	It("returns the exit code of plugins", func() {
+		p := &Plugin{Name: "foo", Path: plugin(dirs[0], "eksctl-foo", 0755)}
+		Expect(p.Run(nil)).To(Equal(3))
+	})
+})
@@ -0,0 +1,154 @@
+// Package posture checks the security posture of a cluster against a baseline, e.g. in
+// policy pipelines
+package posture
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+)
+
+// Check names
+const (
+	CheckSecretsEncryption   = "secrets-encryption"
+	CheckEndpointAccess      = "endpoint-public-access"
+	CheckControlPlaneLogging = "control-plane-logging"
+	CheckIMDSv2              = "imdsv2"
+	CheckOIDCProvider        = "oidc-provider"
+)
+
+// Statuses of checks
+const (
+	StatusPass    = "pass"
+	StatusFail    = "fail"
+	StatusUnknown = "unknown"
+)
+
+// requiredLogTypes are the control plane log types needed to audit access to the cluster
+var requiredLogTypes = []string{"api", "audit", "authenticator"}
+
+// Inputs is what the checks need to know about the cluster
+type Inputs struct {
+	Cluster *awseks.Cluster
+	// SecretsEncryption is nil when it couldn't be determined
+	SecretsEncryption *bool
+	// Instances are the running instances of the nodegroups of the cluster
+	Instances []*ec2.Instance
+	// OIDCProviderExists is nil when it couldn't be determined
+	OIDCProviderExists *bool
+}
+
+// Result is the result of one check
+type Result struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// Report holds the results of all checks
+type Report struct {
+	Cluster string   `json:"cluster"`
+	Passed  bool     `json:"passed"`
+	Results []Result `json:"results"`
+}
+
+// Check runs all checks; the report passes when every check passes, checks whose
+// status couldn't be determined fail the report
+func Check(inputs *Inputs) *Report {
+	report := &Report{
+		Cluster: aws.StringValue(inputs.Cluster.Name),
+		Results: []Result{
+			checkSecretsEncryption(inputs),
+			checkEndpointAccess(inputs.Cluster),
+			checkControlPlaneLogging(inputs.Cluster),
+			checkIMDSv2(inputs.Instances),
+			checkOIDCProvider(inputs),
+		},
+	}
+	report.Passed = true
+	for _, result := range report.Results {
+		if result.Status != StatusPass {
+			report.Passed = false
+		}
+	}
+	return report
+}
+
+func result(name string, passed bool, passMessage, failMessage string) Result {
+	if passed {
+		return Result{Name: name, Status: StatusPass, Message: passMessage}
+	}
+	return Result{Name: name, Status: StatusFail, Message: failMessage}
+}
+
+func checkSecretsEncryption(inputs *Inputs) Result {
+	if inputs.SecretsEncryption == nil {
+		return Result{Name: CheckSecretsEncryption, Status: StatusUnknown, Message: "unable to determine whether secrets are encrypted with a KMS key"}
+	}
+	return result(CheckSecretsEncryption, *inputs.SecretsEncryption,
+		"secrets are encrypted with a KMS key",
+		"secrets aren't encrypted with a KMS key, set secretsEncryption.keyARN when creating the cluster")
+}
+
+func checkEndpointAccess(cluster *awseks.Cluster) Result {
+	vpcConfig := cluster.ResourcesVpcConfig
+	if vpcConfig == nil {
+		return Result{Name: CheckEndpointAccess, Status: StatusUnknown, Message: "the VPC configuration of the cluster is unknown"}
+	}
+	if !aws.BoolValue(vpcConfig.EndpointPublicAccess) {
+		return result(CheckEndpointAccess, true, "the public endpoint is disabled", "")
+	}
+	cidrs := aws.StringValueSlice(vpcConfig.PublicAccessCidrs)
+	for _, cidr := range cidrs {
+		if cidr == "0.0.0.0/0" {
+			return result(CheckEndpointAccess, false, "", "the public endpoint is accessible from 0.0.0.0/0, restrict vpc.publicAccessCIDRs or disable public access")
+		}
+	}
+	return result(CheckEndpointAccess, true, fmt.Sprintf("the public endpoint is restricted to %s", strings.Join(cidrs, ", ")), "")
+}
+
+func checkControlPlaneLogging(cluster *awseks.Cluster) Result {
+	enabled := map[string]bool{}
+	if cluster.Logging != nil {
+		for _, logSetup := range cluster.Logging.ClusterLogging {
+			if aws.BoolValue(logSetup.Enabled) {
+				for _, logType := range logSetup.Types {
+					enabled[aws.StringValue(logType)] = true
+				}
+			}
+		}
+	}
+	var missing []string
+	for _, logType := range requiredLogTypes {
+		if !enabled[logType] {
+			missing = append(missing, logType)
+		}
+	}
+	return result(CheckControlPlaneLogging, len(missing) == 0,
+		fmt.Sprintf("the %s logs are enabled", strings.Join(requiredLogTypes, ", ")),
+		fmt.Sprintf("the %s logs aren't enabled, add them to cloudWatch.clusterLogging.enableTypes", strings.Join(missing, ", ")))
+}
+
+func checkIMDSv2(instances []*ec2.Instance) Result {
+	var withIMDSv1 []string
+	for _, instance := range instances {
+		if instance.MetadataOptions == nil || aws.StringValue(instance.MetadataOptions.HttpTokens) != ec2.HttpTokensStateRequired {
+			withIMDSv1 = append(withIMDSv1, aws.StringValue(instance.InstanceId))
+		}
+	}
+	return result(CheckIMDSv2, len(withIMDSv1) == 0,
+		fmt.Sprintf("all %d nodes require IMDSv2", len(instances)),
+		fmt.Sprintf("%d of %d nodes allow IMDSv1: %s", len(withIMDSv1), len(instances), strings.Join(withIMDSv1, ", ")))
+}
+
+func checkOIDCProvider(inputs *Inputs) Result {
+	if inputs.OIDCProviderExists == nil {
+		return Result{Name: CheckOIDCProvider, Status: StatusUnknown, Message: "unable to determine whether the IAM OIDC provider exists"}
+	}
+	return result(CheckOIDCProvider, *inputs.OIDCProviderExists,
+		"the IAM OIDC provider exists, pods can use IAM roles for service accounts",
+		"the IAM OIDC provider doesn't exist, create it with 'eksctl utils associate-iam-oidc-provider'")
+}
@@ -0,0 +1,96 @@
+package posture_test
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	awseks "github.com/aws/aws-sdk-go/service/eks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/weaveworks/eksctl/pkg/posture"
+)
+
+var _ = Describe("Security posture", func() {
+	var inputs *posture.Inputs
+
+	statuses := func(report *posture.Report) map[string]string {
+		s := map[string]string{}
+		for _, result := range report.Results {
+			s[result.Name] = result.Status
+		}
+		return s
+	}
+
+	BeforeEach(func() {
+		inputs = &posture.Inputs{
+			Cluster: &awseks.Cluster{
+				Name: aws.String("cluster-1"),
+				ResourcesVpcConfig: &awseks.VpcConfigResponse{
+					EndpointPublicAccess: aws.Bool(true),
+					PublicAccessCidrs:    aws.StringSlice([]string{"192.0.2.0/24"}),
+				},
+				Logging: &awseks.Logging{
+					ClusterLogging: []*awseks.LogSetup{{
+						Enabled: aws.Bool(true),
+						Types:   aws.StringSlice([]string{"api", "audit", "authenticator"}),
+					}},
+				},
+			},
+			SecretsEncryption: aws.Bool(true),
+			Instances: []*ec2.Instance{{
+				InstanceId:      aws.String("i-1"),
+				MetadataOptions: &ec2.InstanceMetadataOptionsResponse{HttpTokens: aws.String(ec2.HttpTokensStateRequired)},
+			}},
+			OIDCProviderExists: aws.Bool(true),
+		}
+	})
+
+	It("passes when all checks pass", func() {
+		report := posture.Check(inputs)
+		Expect(report.Cluster).To(Equal("cluster-1"))
+		Expect(report.Passed).To(BeTrue())
+		Expect(report.Results).To(HaveLen(5))
+		for _, result := range report.Results {
+			Expect(result.Status).To(Equal(posture.StatusPass), result.Name)
+		}
+	})
+
+	It("passes the endpoint check when public access is disabled", func() {
+		inputs.Cluster.ResourcesVpcConfig.EndpointPublicAccess = aws.Bool(false)
+		inputs.Cluster.ResourcesVpcConfig.PublicAccessCidrs = aws.StringSlice([]string{"0.0.0.0/0"})
+		Expect(statuses(posture.Check(inputs))).To(HaveKeyWithValue(posture.CheckEndpointAccess, posture.StatusPass))
+	})
+
+	It("fails each check that isn't met", func() {
+		inputs.SecretsEncryption = aws.Bool(false)
+		inputs.Cluster.ResourcesVpcConfig.PublicAccessCidrs = aws.StringSlice([]string{"0.0.0.0/0"})
+		inputs.Cluster.Logging.ClusterLogging[0].Types = aws.StringSlice([]string{"api"})
+		inputs.Instances = append(inputs.Instances, &ec2.Instance{
+			InstanceId:      aws.String("i-2"),
+			MetadataOptions: &ec2.InstanceMetadataOptionsResponse{HttpTokens: aws.String(ec2.HttpTokensStateOptional)},
+		})
+		inputs.OIDCProviderExists = aws.Bool(false)
+
+		report := posture.Check(inputs)
+		Expect(report.Passed).To(BeFalse())
+		Expect(statuses(report)).To(Equal(map[string]string{
+			posture.CheckSecretsEncryption:   posture.StatusFail,
+			posture.CheckEndpointAccess:      posture.StatusFail,
+			posture.CheckControlPlaneLogging: posture.StatusFail,
+			posture.CheckIMDSv2:              posture.StatusFail,
+			posture.CheckOIDCProvider:        posture.StatusFail,
+		}))
+		Expect(report.Results[2].Message).To(ContainSubstring("audit, authenticator"))
+		Expect(report.Results[3].Message).To(ContainSubstring("i-2"))
+	})
+
+	It("fails the report when checks can't be determined", func() {
+		inputs.SecretsEncryption = nil
+		inputs.OIDCProviderExists = nil
+
+		report := posture.Check(inputs)
+		Expect(report.Passed).To(BeFalse())
+		Expect(statuses(report)).To(HaveKeyWithValue(posture.CheckSecretsEncryption, posture.StatusUnknown))
+		Expect(statuses(report)).To(HaveKeyWithValue(posture.CheckOIDCProvider, posture.StatusUnknown))
+	})
+})
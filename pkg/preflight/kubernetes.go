@@ -0,0 +1,170 @@
+package preflight
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+)
+
+const (
+	lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+	managedNodeGroupLabel       = "eks.amazonaws.com/nodegroup"
+	ebsCSIDriverName            = "ebs.csi.aws.com"
+)
+
+// AddClusterInputs adds the nodes, the pod disruption budgets, the volumes of the in-tree EBS
+// plugin and the objects that were applied with kubectl to inputs; the API server converts objects to the version they're requested in,
+// so the API version the manifests use is only known from their last applied configuration
+func AddClusterInputs(clientSet kubernetes.Interface, inputs *Inputs) error {
+	nodes, err := clientSet.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing nodes")
+	}
+	for _, node := range nodes.Items {
+		nodeGroup := node.Labels[api.NodeGroupNameLabel]
+		if nodeGroup == "" {
+			nodeGroup = node.Labels[managedNodeGroupLabel]
+		}
+		inputs.Nodes = append(inputs.Nodes, Node{
+			Name:           node.Name,
+			NodeGroup:      nodeGroup,
+			KubeletVersion: node.Status.NodeInfo.KubeletVersion,
+		})
+	}
+
+	pdbs, err := clientSet.PolicyV1beta1().PodDisruptionBudgets(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing pod disruption budgets")
+	}
+	for i := range pdbs.Items {
+		pdb := &pdbs.Items[i]
+		inputs.PodDisruptionBudgets = append(inputs.PodDisruptionBudgets, PodDisruptionBudget{
+			Namespace:          pdb.Namespace,
+			Name:               pdb.Name,
+			DisruptionsAllowed: pdb.Status.PodDisruptionsAllowed,
+			ExpectedPods:       pdb.Status.ExpectedPods,
+		})
+		inputs.addAppliedObject("PodDisruptionBudget", &pdb.ObjectMeta)
+	}
+
+	if err := inputs.addEBSVolumes(clientSet); err != nil {
+		return err
+	}
+	return inputs.addAppliedObjects(clientSet)
+}
+
+// addEBSVolumes adds the PersistentVolumes of the in-tree EBS plugin, and whether the EBS CSI
+// driver is installed, as an EKS add-on or otherwise
+func (inputs *Inputs) addEBSVolumes(clientSet kubernetes.Interface) error {
+	volumes, err := clientSet.CoreV1().PersistentVolumes().List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing persistent volumes")
+	}
+	for _, volume := range volumes.Items {
+		if volume.Spec.AWSElasticBlockStore != nil {
+			inputs.InTreeEBSVolumes = append(inputs.InTreeEBSVolumes, volume.Name)
+		}
+	}
+
+	_, err = clientSet.StorageV1beta1().CSIDrivers().Get(ebsCSIDriverName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return errors.Wrapf(err, "getting CSIDriver %q", ebsCSIDriverName)
+	default:
+		inputs.EBSCSIDriverInstalled = true
+	}
+	return nil
+}
+
+// addAppliedObjects adds the objects of the kinds that have API versions Kubernetes stops serving
+func (inputs *Inputs) addAppliedObjects(clientSet kubernetes.Interface) error {
+	deployments, err := clientSet.AppsV1().Deployments(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing deployments")
+	}
+	for i := range deployments.Items {
+		inputs.addAppliedObject("Deployment", &deployments.Items[i].ObjectMeta)
+	}
+
+	daemonSets, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing daemonsets")
+	}
+	for i := range daemonSets.Items {
+		inputs.addAppliedObject("DaemonSet", &daemonSets.Items[i].ObjectMeta)
+	}
+
+	statefulSets, err := clientSet.AppsV1().StatefulSets(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing statefulsets")
+	}
+	for i := range statefulSets.Items {
+		inputs.addAppliedObject("StatefulSet", &statefulSets.Items[i].ObjectMeta)
+	}
+
+	// the replicasets of deployments don't get the annotation, only the ones applied directly do
+	replicaSets, err := clientSet.AppsV1().ReplicaSets(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing replicasets")
+	}
+	for i := range replicaSets.Items {
+		inputs.addAppliedObject("ReplicaSet", &replicaSets.Items[i].ObjectMeta)
+	}
+
+	networkPolicies, err := clientSet.NetworkingV1().NetworkPolicies(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing network policies")
+	}
+	for i := range networkPolicies.Items {
+		inputs.addAppliedObject("NetworkPolicy", &networkPolicies.Items[i].ObjectMeta)
+	}
+
+	podSecurityPolicies, err := clientSet.PolicyV1beta1().PodSecurityPolicies().List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing pod security policies")
+	}
+	for i := range podSecurityPolicies.Items {
+		inputs.addAppliedObject("PodSecurityPolicy", &podSecurityPolicies.Items[i].ObjectMeta)
+	}
+
+	ingresses, err := clientSet.ExtensionsV1beta1().Ingresses(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing ingresses")
+	}
+	for i := range ingresses.Items {
+		inputs.addAppliedObject("Ingress", &ingresses.Items[i].ObjectMeta)
+	}
+
+	cronJobs, err := clientSet.BatchV1beta1().CronJobs(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing cronjobs")
+	}
+	for i := range cronJobs.Items {
+		inputs.addAppliedObject("CronJob", &cronJobs.Items[i].ObjectMeta)
+	}
+	return nil
+}
+
+// addAppliedObject adds the object when it has a last applied configuration
+func (inputs *Inputs) addAppliedObject(kind string, meta *metav1.ObjectMeta) {
+	lastApplied, ok := meta.Annotations[lastAppliedConfigAnnotation]
+	if !ok {
+		return
+	}
+	var typeMeta metav1.TypeMeta
+	if err := json.Unmarshal([]byte(lastApplied), &typeMeta); err != nil || typeMeta.APIVersion == "" {
+		return
+	}
+	inputs.AppliedObjects = append(inputs.AppliedObjects, AppliedObject{
+		Kind:       kind,
+		Namespace:  meta.Namespace,
+		Name:       meta.Name,
+		APIVersion: typeMeta.APIVersion,
+	})
+}
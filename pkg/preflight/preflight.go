@@ -0,0 +1,362 @@
+// Package preflight checks whether a cluster is ready to have its control plane upgraded to
+// the next Kubernetes version, and renders the checks as a go/no-go report
+package preflight
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// Check names
+const (
+	CheckAddonCompatibility   = "addon-compatibility"
+	CheckUpgradeInsights      = "upgrade-insights"
+	CheckDeprecatedAPIs       = "deprecated-apis"
+	CheckNodeGroupVersionSkew = "nodegroup-version-skew"
+	CheckPodDisruptionBudgets = "pod-disruption-budgets"
+	CheckEBSCSIMigration      = "ebs-csi-migration"
+)
+
+// Statuses of checks
+const (
+	StatusPass    = "pass"
+	StatusWarn    = "warn"
+	StatusFail    = "fail"
+	StatusUnknown = "unknown"
+)
+
+// Statuses of EKS upgrade insights
+const (
+	InsightStatusPassing = "PASSING"
+	InsightStatusWarning = "WARNING"
+	InsightStatusError   = "ERROR"
+)
+
+// EBSCSIMigrationVersion is the Kubernetes version from which EKS enables the CSIMigration and
+// CSIMigrationAWS feature gates, the volumes of the in-tree EBS plugin are then handled by the
+// EBS CSI driver
+const EBSCSIMigrationVersion = "1.23"
+
+// maxKubeletMinorVersionSkew is how many minor versions kubelets can be older than the control plane
+const maxKubeletMinorVersionSkew = 2
+
+// Inputs is what the checks need to know about the cluster
+type Inputs struct {
+	Cluster        string
+	CurrentVersion string
+	TargetVersion  string
+	// Addons are the EKS add-ons installed in the cluster
+	Addons []Addon
+	// Insights are the upgrade readiness insights EKS has for the target version
+	Insights []Insight
+	// AppliedObjects are the objects whose last applied configuration is known, which is
+	// how the API version the manifests use is found
+	AppliedObjects []AppliedObject
+	Nodes          []Node
+	// PodDisruptionBudgets are the budgets that apply to pods
+	PodDisruptionBudgets []PodDisruptionBudget
+	// InTreeEBSVolumes are the PersistentVolumes of the in-tree EBS plugin
+	InTreeEBSVolumes []string
+	// EBSCSIDriverInstalled is true when the EBS CSI driver is installed other than as an EKS add-on
+	EBSCSIDriverInstalled bool
+	// Unavailable maps the checks whose inputs couldn't be determined to the reason why
+	Unavailable map[string]string
+}
+
+// Addon is an installed EKS add-on
+type Addon struct {
+	Name    string
+	Version string
+	// CompatibleVersions are the versions of the add-on that support the target version
+	CompatibleVersions []string
+}
+
+// Insight is an upgrade readiness insight of EKS
+type Insight struct {
+	Name        string
+	Status      string
+	Reason      string
+	Description string
+}
+
+// AppliedObject is an object along with the API version of its last applied configuration
+type AppliedObject struct {
+	Kind       string
+	Namespace  string
+	Name       string
+	APIVersion string
+}
+
+// Node is a node along with the nodegroup it belongs to, if any
+type Node struct {
+	Name           string
+	NodeGroup      string
+	KubeletVersion string
+}
+
+// PodDisruptionBudget is a budget along with its status
+type PodDisruptionBudget struct {
+	Namespace          string
+	Name               string
+	DisruptionsAllowed int32
+	ExpectedPods       int32
+}
+
+// Result is the result of one check
+type Result struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// Report holds the results of all checks
+type Report struct {
+	Cluster        string   `json:"cluster"`
+	CurrentVersion string   `json:"currentVersion"`
+	TargetVersion  string   `json:"targetVersion"`
+	Go             bool     `json:"go"`
+	Results        []Result `json:"results"`
+}
+
+// removedAPI is an API version of kinds that Kubernetes stops serving in a given version
+type removedAPI struct {
+	removedIn  string
+	apiVersion string
+	kinds      []string
+}
+
+var removedAPIs = []removedAPI{
+	{removedIn: "1.16", apiVersion: "extensions/v1beta1", kinds: []string{"DaemonSet", "Deployment", "NetworkPolicy", "PodSecurityPolicy", "ReplicaSet"}},
+	{removedIn: "1.16", apiVersion: "apps/v1beta1", kinds: []string{"Deployment", "ReplicaSet", "StatefulSet"}},
+	{removedIn: "1.16", apiVersion: "apps/v1beta2", kinds: []string{"DaemonSet", "Deployment", "ReplicaSet", "StatefulSet"}},
+	{removedIn: "1.22", apiVersion: "extensions/v1beta1", kinds: []string{"Ingress"}},
+	{removedIn: "1.22", apiVersion: "networking.k8s.io/v1beta1", kinds: []string{"Ingress"}},
+	{removedIn: "1.25", apiVersion: "batch/v1beta1", kinds: []string{"CronJob"}},
+	{removedIn: "1.25", apiVersion: "policy/v1beta1", kinds: []string{"PodDisruptionBudget", "PodSecurityPolicy"}},
+}
+
+// Check runs all checks; the report is a go unless a check fails, checks that warn or whose
+// status couldn't be determined are left to the judgement of the user
+func Check(inputs *Inputs) *Report {
+	report := &Report{
+		Cluster:        inputs.Cluster,
+		CurrentVersion: inputs.CurrentVersion,
+		TargetVersion:  inputs.TargetVersion,
+		Results: []Result{
+			checkOrUnknown(inputs, CheckAddonCompatibility, checkAddonCompatibility),
+			checkOrUnknown(inputs, CheckUpgradeInsights, checkUpgradeInsights),
+			checkOrUnknown(inputs, CheckDeprecatedAPIs, checkDeprecatedAPIs),
+			checkOrUnknown(inputs, CheckNodeGroupVersionSkew, checkNodeGroupVersionSkew),
+			checkOrUnknown(inputs, CheckPodDisruptionBudgets, checkPodDisruptionBudgets),
+			checkOrUnknown(inputs, CheckEBSCSIMigration, checkEBSCSIMigration),
+		},
+	}
+	report.Go = true
+	for _, result := range report.Results {
+		if result.Status == StatusFail {
+			report.Go = false
+		}
+	}
+	return report
+}
+
+func checkOrUnknown(inputs *Inputs, name string, check func(*Inputs) Result) Result {
+	if reason, ok := inputs.Unavailable[name]; ok {
+		return Result{Name: name, Status: StatusUnknown, Message: reason}
+	}
+	result := check(inputs)
+	result.Name = name
+	return result
+}
+
+func checkAddonCompatibility(inputs *Inputs) Result {
+	var incompatible []string
+	for _, addon := range inputs.Addons {
+		if !contains(addon.CompatibleVersions, addon.Version) {
+			incompatible = append(incompatible, fmt.Sprintf("%s %s", addon.Name, addon.Version))
+		}
+	}
+	if len(incompatible) > 0 {
+		return Result{Status: StatusFail, Message: fmt.Sprintf("EKS add-ons %s don't support Kubernetes %s, update them with 'eksctl update addon' first",
+			strings.Join(incompatible, ", "), inputs.TargetVersion)}
+	}
+	return Result{Status: StatusPass, Message: fmt.Sprintf("all %d EKS add-ons support Kubernetes %s", len(inputs.Addons), inputs.TargetVersion)}
+}
+
+func checkUpgradeInsights(inputs *Inputs) Result {
+	var errored, warned []string
+	for _, insight := range inputs.Insights {
+		describe := insight.Name
+		if insight.Reason != "" {
+			describe = fmt.Sprintf("%s (%s)", insight.Name, insight.Reason)
+		}
+		switch insight.Status {
+		case InsightStatusError:
+			errored = append(errored, describe)
+		case InsightStatusWarning:
+			warned = append(warned, describe)
+		}
+	}
+	switch {
+	case len(errored) > 0:
+		return Result{Status: StatusFail, Message: "EKS upgrade insights report errors: " + strings.Join(append(errored, warned...), "; ")}
+	case len(warned) > 0:
+		return Result{Status: StatusWarn, Message: "EKS upgrade insights report warnings: " + strings.Join(warned, "; ")}
+	}
+	return Result{Status: StatusPass, Message: fmt.Sprintf("all %d EKS upgrade insights are passing", len(inputs.Insights))}
+}
+
+func checkDeprecatedAPIs(inputs *Inputs) Result {
+	current, err := minorVersion(inputs.CurrentVersion)
+	if err != nil {
+		return Result{Status: StatusUnknown, Message: err.Error()}
+	}
+	target, err := minorVersion(inputs.TargetVersion)
+	if err != nil {
+		return Result{Status: StatusUnknown, Message: err.Error()}
+	}
+
+	var removed []string
+	for _, object := range inputs.AppliedObjects {
+		for _, api := range removedAPIs {
+			removedIn, _ := minorVersion(api.removedIn)
+			if removedIn <= current || removedIn > target || api.apiVersion != object.APIVersion || !contains(api.kinds, object.Kind) {
+				continue
+			}
+			name := object.Name
+			if object.Namespace != "" {
+				name = object.Namespace + "/" + name
+			}
+			removed = append(removed, fmt.Sprintf("%s %s (%s)", object.Kind, name, object.APIVersion))
+		}
+	}
+	if len(removed) > 0 {
+		sort.Strings(removed)
+		return Result{Status: StatusFail, Message: fmt.Sprintf("objects were last applied with API versions Kubernetes %s no longer serves, update their manifests: %s",
+			inputs.TargetVersion, strings.Join(removed, ", "))}
+	}
+	return Result{Status: StatusPass, Message: fmt.Sprintf("no objects were last applied with API versions Kubernetes %s no longer serves", inputs.TargetVersion)}
+}
+
+func checkNodeGroupVersionSkew(inputs *Inputs) Result {
+	target, err := minorVersion(inputs.TargetVersion)
+	if err != nil {
+		return Result{Status: StatusUnknown, Message: err.Error()}
+	}
+
+	oldest := map[string]string{}
+	for _, node := range inputs.Nodes {
+		nodeGroup := node.NodeGroup
+		if nodeGroup == "" {
+			nodeGroup = "node " + node.Name
+		}
+		minor, err := minorVersion(node.KubeletVersion)
+		if err != nil {
+			return Result{Status: StatusUnknown, Message: fmt.Sprintf("unable to parse the kubelet version of node %q: %s", node.Name, err.Error())}
+		}
+		if int(target)-int(minor) > maxKubeletMinorVersionSkew {
+			if version, ok := oldest[nodeGroup]; !ok || compareVersions(node.KubeletVersion, version) < 0 {
+				oldest[nodeGroup] = node.KubeletVersion
+			}
+		}
+	}
+	if len(oldest) > 0 {
+		var behind []string
+		for nodeGroup, version := range oldest {
+			behind = append(behind, fmt.Sprintf("%s (%s)", nodeGroup, version))
+		}
+		sort.Strings(behind)
+		return Result{Status: StatusFail, Message: fmt.Sprintf("kubelets can be at most %d minor versions older than the control plane, upgrade these nodegroups first: %s",
+			maxKubeletMinorVersionSkew, strings.Join(behind, ", "))}
+	}
+	return Result{Status: StatusPass, Message: fmt.Sprintf("the kubelets of all %d nodes are within %d minor versions of Kubernetes %s", len(inputs.Nodes), maxKubeletMinorVersionSkew, inputs.TargetVersion)}
+}
+
+func checkPodDisruptionBudgets(inputs *Inputs) Result {
+	var blocking []string
+	for _, pdb := range inputs.PodDisruptionBudgets {
+		if pdb.ExpectedPods > 0 && pdb.DisruptionsAllowed == 0 {
+			blocking = append(blocking, pdb.Namespace+"/"+pdb.Name)
+		}
+	}
+	if len(blocking) > 0 {
+		sort.Strings(blocking)
+		return Result{Status: StatusWarn, Message: fmt.Sprintf("pod disruption budgets allow no disruptions and would block draining nodes when upgrading nodegroups: %s",
+			strings.Join(blocking, ", "))}
+	}
+	return Result{Status: StatusPass, Message: fmt.Sprintf("none of %d pod disruption budgets would block draining nodes", len(inputs.PodDisruptionBudgets))}
+}
+
+// NeedsEBSCSIDriver reports whether PersistentVolumes of the in-tree EBS plugin require the EBS
+// CSI driver to be installed
+func (inputs *Inputs) NeedsEBSCSIDriver() bool {
+	return len(inputs.InTreeEBSVolumes) > 0 && !inputs.hasEBSCSIDriver()
+}
+
+func (inputs *Inputs) hasEBSCSIDriver() bool {
+	if inputs.EBSCSIDriverInstalled {
+		return true
+	}
+	for _, addon := range inputs.Addons {
+		if addon.Name == api.EBSCSIDriverAddon {
+			return true
+		}
+	}
+	return false
+}
+
+func checkEBSCSIMigration(inputs *Inputs) Result {
+	if len(inputs.InTreeEBSVolumes) == 0 {
+		return Result{Status: StatusPass, Message: "no PersistentVolumes use the in-tree EBS plugin"}
+	}
+	target, err := minorVersion(inputs.TargetVersion)
+	if err != nil {
+		return Result{Status: StatusUnknown, Message: err.Error()}
+	}
+	migrated, _ := minorVersion(EBSCSIMigrationVersion)
+
+	volumes := append([]string{}, inputs.InTreeEBSVolumes...)
+	sort.Strings(volumes)
+	switch {
+	case inputs.hasEBSCSIDriver():
+		return Result{Status: StatusPass, Message: fmt.Sprintf("%d PersistentVolumes of the in-tree EBS plugin are handled by the EBS CSI driver from Kubernetes %s",
+			len(volumes), EBSCSIMigrationVersion)}
+	case target >= migrated:
+		return Result{Status: StatusFail, Message: fmt.Sprintf("Kubernetes %s migrates the PersistentVolumes of the in-tree EBS plugin to the EBS CSI driver, which isn't installed, "+
+			"install the %s add-on first or use --install-ebs-csi-driver: %s", inputs.TargetVersion, api.EBSCSIDriverAddon, strings.Join(volumes, ", "))}
+	}
+	return Result{Status: StatusWarn, Message: fmt.Sprintf("PersistentVolumes use the in-tree EBS plugin, install the %s add-on before upgrading to Kubernetes %s: %s",
+		api.EBSCSIDriverAddon, EBSCSIMigrationVersion, strings.Join(volumes, ", "))}
+}
+
+// minorVersion returns the minor version of a Kubernetes version like 1.15 or v1.14.9-eks-1f0ca9
+func minorVersion(version string) (uint64, error) {
+	v, err := semver.ParseTolerant(version)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse Kubernetes version %q", version)
+	}
+	return v.Minor, nil
+}
+
+func compareVersions(a, b string) int {
+	va, errA := semver.ParseTolerant(a)
+	vb, errB := semver.ParseTolerant(b)
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+	return va.Compare(vb)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
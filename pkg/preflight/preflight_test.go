@@ -0,0 +1,189 @@
+package preflight_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/weaveworks/eksctl/pkg/preflight"
+)
+
+var _ = Describe("Pre-flight checks", func() {
+	var inputs *preflight.Inputs
+
+	results := func(report *preflight.Report) map[string]preflight.Result {
+		r := map[string]preflight.Result{}
+		for _, result := range report.Results {
+			r[result.Name] = result
+		}
+		return r
+	}
+
+	BeforeEach(func() {
+		inputs = &preflight.Inputs{
+			Cluster:        "cluster-1",
+			CurrentVersion: "1.15",
+			TargetVersion:  "1.16",
+			Addons: []preflight.Addon{
+				{Name: "vpc-cni", Version: "v1.6.3-eksbuild.1", CompatibleVersions: []string{"v1.6.3-eksbuild.1", "v1.7.5-eksbuild.1"}},
+			},
+			Insights: []preflight.Insight{
+				{Name: "Kubelet version skew", Status: preflight.InsightStatusPassing},
+			},
+			AppliedObjects: []preflight.AppliedObject{
+				{Kind: "Deployment", Namespace: "default", Name: "app", APIVersion: "apps/v1"},
+				{Kind: "Ingress", Namespace: "default", Name: "app", APIVersion: "extensions/v1beta1"},
+			},
+			Nodes: []preflight.Node{
+				{Name: "node-1", NodeGroup: "ng-1", KubeletVersion: "v1.14.9-eks-1f0ca9"},
+			},
+			PodDisruptionBudgets: []preflight.PodDisruptionBudget{
+				{Namespace: "default", Name: "app", DisruptionsAllowed: 1, ExpectedPods: 2},
+			},
+		}
+	})
+
+	It("is a go when all checks pass", func() {
+		report := preflight.Check(inputs)
+		Expect(report.Go).To(BeTrue())
+		for _, result := range report.Results {
+			Expect(result.Status).To(Equal(preflight.StatusPass), result.Name)
+		}
+	})
+
+	It("is a no-go when an add-on doesn't support the target version", func() {
+		inputs.Addons[0].Version = "v1.5.7"
+		report := preflight.Check(inputs)
+		Expect(report.Go).To(BeFalse())
+		Expect(results(report)[preflight.CheckAddonCompatibility].Status).To(Equal(preflight.StatusFail))
+		Expect(results(report)[preflight.CheckAddonCompatibility].Message).To(ContainSubstring("vpc-cni v1.5.7"))
+	})
+
+	It("fails on insights with errors and warns on insights with warnings", func() {
+		inputs.Insights = append(inputs.Insights, preflight.Insight{Name: "Deprecated APIs", Status: preflight.InsightStatusWarning})
+		report := preflight.Check(inputs)
+		Expect(report.Go).To(BeTrue())
+		Expect(results(report)[preflight.CheckUpgradeInsights].Status).To(Equal(preflight.StatusWarn))
+
+		inputs.Insights[0].Status = preflight.InsightStatusError
+		inputs.Insights[0].Reason = "nodes are 3 minor versions behind"
+		report = preflight.Check(inputs)
+		Expect(report.Go).To(BeFalse())
+		Expect(results(report)[preflight.CheckUpgradeInsights].Message).To(ContainSubstring("Kubelet version skew (nodes are 3 minor versions behind)"))
+	})
+
+	It("fails on objects last applied with API versions the target version removes", func() {
+		inputs.AppliedObjects = append(inputs.AppliedObjects,
+			preflight.AppliedObject{Kind: "DaemonSet", Namespace: "kube-system", Name: "agent", APIVersion: "extensions/v1beta1"},
+			preflight.AppliedObject{Kind: "CronJob", Namespace: "default", Name: "backup", APIVersion: "batch/v1beta1"},
+		)
+		report := preflight.Check(inputs)
+		Expect(report.Go).To(BeFalse())
+		result := results(report)[preflight.CheckDeprecatedAPIs]
+		Expect(result.Status).To(Equal(preflight.StatusFail))
+		Expect(result.Message).To(ContainSubstring("DaemonSet kube-system/agent (extensions/v1beta1)"))
+		Expect(result.Message).NotTo(ContainSubstring("Ingress"))
+		Expect(result.Message).NotTo(ContainSubstring("CronJob"))
+	})
+
+	It("fails on nodegroups more than 2 minor versions behind the target version", func() {
+		inputs.TargetVersion = "1.17"
+		inputs.Nodes = append(inputs.Nodes,
+			preflight.Node{Name: "node-2", NodeGroup: "ng-2", KubeletVersion: "v1.16.8-eks-e16311"},
+			preflight.Node{Name: "node-3", KubeletVersion: "v1.13.12-eks-c500e1"},
+		)
+		report := preflight.Check(inputs)
+		Expect(report.Go).To(BeFalse())
+		result := results(report)[preflight.CheckNodeGroupVersionSkew]
+		Expect(result.Status).To(Equal(preflight.StatusFail))
+		Expect(result.Message).To(HaveSuffix("ng-1 (v1.14.9-eks-1f0ca9), node node-3 (v1.13.12-eks-c500e1)"))
+	})
+
+	It("warns on pod disruption budgets that allow no disruptions", func() {
+		inputs.PodDisruptionBudgets = append(inputs.PodDisruptionBudgets,
+			preflight.PodDisruptionBudget{Namespace: "default", Name: "db", ExpectedPods: 1},
+			preflight.PodDisruptionBudget{Namespace: "default", Name: "unused"},
+		)
+		report := preflight.Check(inputs)
+		Expect(report.Go).To(BeTrue())
+		result := results(report)[preflight.CheckPodDisruptionBudgets]
+		Expect(result.Status).To(Equal(preflight.StatusWarn))
+		Expect(result.Message).To(HaveSuffix(": default/db"))
+	})
+
+	It("fails on in-tree EBS volumes without the EBS CSI driver when the target version migrates them", func() {
+		inputs.InTreeEBSVolumes = []string{"pv-2", "pv-1"}
+		result := results(preflight.Check(inputs))[preflight.CheckEBSCSIMigration]
+		Expect(result.Status).To(Equal(preflight.StatusWarn))
+		Expect(inputs.NeedsEBSCSIDriver()).To(BeTrue())
+
+		inputs.CurrentVersion, inputs.TargetVersion = "1.22", "1.23"
+		report := preflight.Check(inputs)
+		Expect(report.Go).To(BeFalse())
+		result = results(report)[preflight.CheckEBSCSIMigration]
+		Expect(result.Status).To(Equal(preflight.StatusFail))
+		Expect(result.Message).To(HaveSuffix("pv-1, pv-2"))
+
+		inputs.Addons = append(inputs.Addons, preflight.Addon{Name: "aws-ebs-csi-driver"})
+		Expect(inputs.NeedsEBSCSIDriver()).To(BeFalse())
+		Expect(results(preflight.Check(inputs))[preflight.CheckEBSCSIMigration].Status).To(Equal(preflight.StatusPass))
+	})
+
+	It("reports the checks whose inputs are unavailable as unknown", func() {
+		inputs.Unavailable = map[string]string{preflight.CheckUpgradeInsights: "EKS upgrade insights aren't available for the cluster"}
+		report := preflight.Check(inputs)
+		Expect(report.Go).To(BeTrue())
+		Expect(results(report)[preflight.CheckUpgradeInsights].Status).To(Equal(preflight.StatusUnknown))
+	})
+
+	It("adds the nodes, pod disruption budgets, EBS volumes and applied objects of the cluster", func() {
+		clientSet := fake.NewSimpleClientset(
+			&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"eks.amazonaws.com/nodegroup": "managed-1"}},
+				Status:     corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{KubeletVersion: "v1.15.11-eks-af3caf"}},
+			},
+			&policyv1beta1.PodDisruptionBudget{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+				Status:     policyv1beta1.PodDisruptionBudgetStatus{ExpectedPods: 2},
+			},
+			&extensionsv1beta1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app", Annotations: map[string]string{
+					"kubectl.kubernetes.io/last-applied-configuration": `{"apiVersion":"extensions/v1beta1","kind":"Ingress"}`,
+				}},
+			},
+			&extensionsv1beta1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "created"},
+			},
+			&corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: "in-tree"},
+				Spec: corev1.PersistentVolumeSpec{PersistentVolumeSource: corev1.PersistentVolumeSource{
+					AWSElasticBlockStore: &corev1.AWSElasticBlockStoreVolumeSource{VolumeID: "vol-1"},
+				}},
+			},
+			&corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: "csi"},
+				Spec: corev1.PersistentVolumeSpec{PersistentVolumeSource: corev1.PersistentVolumeSource{
+					CSI: &corev1.CSIPersistentVolumeSource{Driver: "ebs.csi.aws.com", VolumeHandle: "vol-2"},
+				}},
+			},
+		)
+
+		clusterInputs := &preflight.Inputs{}
+		Expect(preflight.AddClusterInputs(clientSet, clusterInputs)).To(Succeed())
+		Expect(clusterInputs.Nodes).To(Equal([]preflight.Node{
+			{Name: "node-1", NodeGroup: "managed-1", KubeletVersion: "v1.15.11-eks-af3caf"},
+		}))
+		Expect(clusterInputs.PodDisruptionBudgets).To(Equal([]preflight.PodDisruptionBudget{
+			{Namespace: "default", Name: "app", ExpectedPods: 2},
+		}))
+		Expect(clusterInputs.AppliedObjects).To(Equal([]preflight.AppliedObject{
+			{Kind: "Ingress", Namespace: "default", Name: "app", APIVersion: "extensions/v1beta1"},
+		}))
+		Expect(clusterInputs.InTreeEBSVolumes).To(Equal([]string{"in-tree"}))
+		Expect(clusterInputs.EBSCSIDriverInstalled).To(BeFalse())
+	})
+})
@@ -6,10 +6,10 @@ import (
 	"io"
 	"strings"
 
-	"github.com/kris-nova/logger"
-
 	"k8s.io/apimachinery/pkg/runtime"
 	cliruntime "k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 // JSONPrinter is a printer that outputs an object formatted
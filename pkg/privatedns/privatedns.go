@@ -0,0 +1,239 @@
+// Package privatedns manages a Route 53 private hosted zone that resolves the API server
+// endpoint of a cluster to its private IPs in VPCs other than the cluster VPC, e.g. peered
+// VPCs; EKS only resolves it privately in the cluster VPC, with a hosted zone it manages
+// and that can't be associated with other VPCs
+package privatedns
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// recordTTL is short, as the IPs of the API server endpoint change when EKS replaces the
+// network interfaces of the control plane
+const recordTTL = 60
+
+// Comment returns the comment of the hosted zone of a cluster, which tells it apart from
+// hosted zones eksctl didn't create
+func Comment(clusterName string) string {
+	return fmt.Sprintf("eksctl: API server endpoint of cluster %q", clusterName)
+}
+
+// EnableVPCDNS enables DNS resolution and DNS hostnames in a VPC, which private hosted zones
+// and private endpoint access require
+func EnableVPCDNS(ec2API ec2iface.EC2API, vpcID string) error {
+	for _, attribute := range []string{ec2.VpcAttributeNameEnableDnsSupport, ec2.VpcAttributeNameEnableDnsHostnames} {
+		output, err := ec2API.DescribeVpcAttribute(&ec2.DescribeVpcAttributeInput{
+			VpcId:     &vpcID,
+			Attribute: aws.String(attribute),
+		})
+		if err != nil {
+			return errors.Wrapf(err, "describing attribute %q of VPC %q", attribute, vpcID)
+		}
+
+		input := &ec2.ModifyVpcAttributeInput{VpcId: &vpcID}
+		enabled := &ec2.AttributeBooleanValue{Value: aws.Bool(true)}
+		if attribute == ec2.VpcAttributeNameEnableDnsSupport {
+			if output.EnableDnsSupport != nil && aws.BoolValue(output.EnableDnsSupport.Value) {
+				continue
+			}
+			input.EnableDnsSupport = enabled
+		} else {
+			if output.EnableDnsHostnames != nil && aws.BoolValue(output.EnableDnsHostnames.Value) {
+				continue
+			}
+			input.EnableDnsHostnames = enabled
+		}
+
+		if _, err := ec2API.ModifyVpcAttribute(input); err != nil {
+			return errors.Wrapf(err, "enabling %q in VPC %q", attribute, vpcID)
+		}
+		logger.Info("enabled %q in VPC %q", attribute, vpcID)
+	}
+	return nil
+}
+
+// EndpointIPs returns the private IPs of the network interfaces EKS creates in the cluster
+// VPC for the API server endpoint
+func EndpointIPs(ec2API ec2iface.EC2API, clusterName, vpcID string) ([]string, error) {
+	var ips []string
+	if err := ec2API.DescribeNetworkInterfacesPages(&ec2.DescribeNetworkInterfacesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: aws.StringSlice([]string{vpcID}),
+			},
+			{
+				Name:   aws.String("description"),
+				Values: aws.StringSlice([]string{"Amazon EKS " + clusterName}),
+			},
+		},
+	}, func(page *ec2.DescribeNetworkInterfacesOutput, _ bool) bool {
+		for _, eni := range page.NetworkInterfaces {
+			if ip := aws.StringValue(eni.PrivateIpAddress); ip != "" {
+				ips = append(ips, ip)
+			}
+		}
+		return true
+	}); err != nil {
+		return nil, errors.Wrapf(err, "describing the network interfaces of cluster %q", clusterName)
+	}
+	sort.Strings(ips)
+	return ips, nil
+}
+
+// Sync creates the hosted zone of hostname if it doesn't exist, associates it with the VPCs
+// and points hostname to ips
+func Sync(route53API route53iface.Route53API, clusterName, hostname, region string, vpcIDs, ips []string) error {
+	if len(vpcIDs) == 0 {
+		return fmt.Errorf("a private hosted zone must be associated with at least one VPC")
+	}
+
+	zone, err := findHostedZone(route53API, clusterName, hostname)
+	if err != nil {
+		return err
+	}
+
+	var zoneID string
+	associated := map[string]bool{}
+	if zone == nil {
+		output, err := route53API.CreateHostedZone(&route53.CreateHostedZoneInput{
+			Name:            &hostname,
+			CallerReference: aws.String(fmt.Sprintf("eksctl-%s-%d", clusterName, time.Now().Unix())),
+			VPC:             &route53.VPC{VPCId: &vpcIDs[0], VPCRegion: &region},
+			HostedZoneConfig: &route53.HostedZoneConfig{
+				Comment:     aws.String(Comment(clusterName)),
+				PrivateZone: aws.Bool(true),
+			},
+		})
+		if err != nil {
+			return errors.Wrapf(err, "creating private hosted zone %q", hostname)
+		}
+		zoneID = aws.StringValue(output.HostedZone.Id)
+		associated[vpcIDs[0]] = true
+		logger.Info("created private hosted zone %q for the API server endpoint of cluster %q", zoneID, clusterName)
+	} else {
+		zoneID = aws.StringValue(zone.Id)
+		output, err := route53API.GetHostedZone(&route53.GetHostedZoneInput{Id: &zoneID})
+		if err != nil {
+			return errors.Wrapf(err, "getting private hosted zone %q", zoneID)
+		}
+		for _, vpc := range output.VPCs {
+			associated[aws.StringValue(vpc.VPCId)] = true
+		}
+	}
+
+	for i := range vpcIDs {
+		if associated[vpcIDs[i]] {
+			continue
+		}
+		if _, err := route53API.AssociateVPCWithHostedZone(&route53.AssociateVPCWithHostedZoneInput{
+			HostedZoneId: &zoneID,
+			VPC:          &route53.VPC{VPCId: &vpcIDs[i], VPCRegion: &region},
+		}); err != nil {
+			return errors.Wrapf(err, "associating VPC %q with private hosted zone %q", vpcIDs[i], zoneID)
+		}
+		logger.Info("associated VPC %q with private hosted zone %q", vpcIDs[i], zoneID)
+	}
+
+	var records []*route53.ResourceRecord
+	for _, ip := range ips {
+		records = append(records, &route53.ResourceRecord{Value: aws.String(ip)})
+	}
+	if _, err := route53API.ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: &zoneID,
+		ChangeBatch: &route53.ChangeBatch{
+			Changes: []*route53.Change{{
+				Action: aws.String(route53.ChangeActionUpsert),
+				ResourceRecordSet: &route53.ResourceRecordSet{
+					Name:            &hostname,
+					Type:            aws.String(route53.RRTypeA),
+					TTL:             aws.Int64(recordTTL),
+					ResourceRecords: records,
+				},
+			}},
+		},
+	}); err != nil {
+		return errors.Wrapf(err, "pointing %q to %s", hostname, strings.Join(ips, ", "))
+	}
+	logger.Info("pointed %q to %s in private hosted zone %q", hostname, strings.Join(ips, ", "), zoneID)
+	return nil
+}
+
+// Delete deletes the hosted zone of hostname created by Sync, if any
+func Delete(route53API route53iface.Route53API, clusterName, hostname string) error {
+	zone, err := findHostedZone(route53API, clusterName, hostname)
+	if err != nil {
+		return err
+	}
+	if zone == nil {
+		return nil
+	}
+	zoneID := aws.StringValue(zone.Id)
+
+	// a hosted zone can only be deleted once it has no other records than SOA and NS
+	output, err := route53API.ListResourceRecordSets(&route53.ListResourceRecordSetsInput{
+		HostedZoneId: &zoneID,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "listing the records of private hosted zone %q", zoneID)
+	}
+	var changes []*route53.Change
+	for _, record := range output.ResourceRecordSets {
+		switch aws.StringValue(record.Type) {
+		case route53.RRTypeSoa, route53.RRTypeNs:
+			continue
+		}
+		changes = append(changes, &route53.Change{
+			Action:            aws.String(route53.ChangeActionDelete),
+			ResourceRecordSet: record,
+		})
+	}
+	if len(changes) > 0 {
+		if _, err := route53API.ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
+			HostedZoneId: &zoneID,
+			ChangeBatch:  &route53.ChangeBatch{Changes: changes},
+		}); err != nil {
+			return errors.Wrapf(err, "deleting the records of private hosted zone %q", zoneID)
+		}
+	}
+
+	if _, err := route53API.DeleteHostedZone(&route53.DeleteHostedZoneInput{Id: &zoneID}); err != nil {
+		return errors.Wrapf(err, "deleting private hosted zone %q", zoneID)
+	}
+	logger.Info("deleted private hosted zone %q", zoneID)
+	return nil
+}
+
+// findHostedZone returns the private hosted zone of hostname that eksctl created for the
+// cluster, if any
+func findHostedZone(route53API route53iface.Route53API, clusterName, hostname string) (*route53.HostedZone, error) {
+	output, err := route53API.ListHostedZonesByName(&route53.ListHostedZonesByNameInput{
+		DNSName: &hostname,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing hosted zones named %q", hostname)
+	}
+	for _, zone := range output.HostedZones {
+		// Route 53 stores names in lowercase, and sorts zones by name starting from hostname
+		if !strings.EqualFold(strings.TrimSuffix(aws.StringValue(zone.Name), "."), strings.TrimSuffix(hostname, ".")) {
+			break
+		}
+		if zone.Config == nil || !aws.BoolValue(zone.Config.PrivateZone) || aws.StringValue(zone.Config.Comment) != Comment(clusterName) {
+			continue
+		}
+		return zone, nil
+	}
+	return nil, nil
+}
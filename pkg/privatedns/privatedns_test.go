@@ -0,0 +1,148 @@
+package privatedns_test
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	. "github.com/weaveworks/eksctl/pkg/privatedns"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+const hostname = "ABCDEF.gr7.us-west-2.eks.amazonaws.com"
+
+type fakeRoute53 struct {
+	route53iface.Route53API
+
+	zones   []*route53.HostedZone
+	vpcs    map[string][]string
+	records map[string][]*route53.ResourceRecordSet
+	deleted []string
+}
+
+func (f *fakeRoute53) ListHostedZonesByName(*route53.ListHostedZonesByNameInput) (*route53.ListHostedZonesByNameOutput, error) {
+	return &route53.ListHostedZonesByNameOutput{HostedZones: f.zones}, nil
+}
+
+func (f *fakeRoute53) CreateHostedZone(input *route53.CreateHostedZoneInput) (*route53.CreateHostedZoneOutput, error) {
+	zone := &route53.HostedZone{
+		Id:     aws.String("/hostedzone/Z1"),
+		Name:   aws.String(*input.Name + "."),
+		Config: input.HostedZoneConfig,
+	}
+	f.zones = append(f.zones, zone)
+	f.vpcs[*zone.Id] = []string{*input.VPC.VPCId}
+	return &route53.CreateHostedZoneOutput{HostedZone: zone}, nil
+}
+
+func (f *fakeRoute53) GetHostedZone(input *route53.GetHostedZoneInput) (*route53.GetHostedZoneOutput, error) {
+	output := &route53.GetHostedZoneOutput{}
+	for _, vpcID := range f.vpcs[*input.Id] {
+		output.VPCs = append(output.VPCs, &route53.VPC{VPCId: aws.String(vpcID)})
+	}
+	return output, nil
+}
+
+func (f *fakeRoute53) AssociateVPCWithHostedZone(input *route53.AssociateVPCWithHostedZoneInput) (*route53.AssociateVPCWithHostedZoneOutput, error) {
+	f.vpcs[*input.HostedZoneId] = append(f.vpcs[*input.HostedZoneId], *input.VPC.VPCId)
+	return &route53.AssociateVPCWithHostedZoneOutput{}, nil
+}
+
+func (f *fakeRoute53) ChangeResourceRecordSets(input *route53.ChangeResourceRecordSetsInput) (*route53.ChangeResourceRecordSetsOutput, error) {
+	for _, change := range input.ChangeBatch.Changes {
+		if *change.Action == route53.ChangeActionDelete {
+			f.records[*input.HostedZoneId] = nil
+		} else {
+			f.records[*input.HostedZoneId] = []*route53.ResourceRecordSet{change.ResourceRecordSet}
+		}
+	}
+	return &route53.ChangeResourceRecordSetsOutput{}, nil
+}
+
+func (f *fakeRoute53) ListResourceRecordSets(input *route53.ListResourceRecordSetsInput) (*route53.ListResourceRecordSetsOutput, error) {
+	return &route53.ListResourceRecordSetsOutput{
+		ResourceRecordSets: append([]*route53.ResourceRecordSet{{Type: aws.String(route53.RRTypeSoa)}}, f.records[*input.HostedZoneId]...),
+	}, nil
+}
+
+func (f *fakeRoute53) DeleteHostedZone(input *route53.DeleteHostedZoneInput) (*route53.DeleteHostedZoneOutput, error) {
+	f.deleted = append(f.deleted, *input.Id)
+	return &route53.DeleteHostedZoneOutput{}, nil
+}
+
+var _ = Describe("private hosted zone", func() {
+	var route53API *fakeRoute53
+
+	BeforeEach(func() {
+		route53API = &fakeRoute53{
+			vpcs:    map[string][]string{},
+			records: map[string][]*route53.ResourceRecordSet{},
+		}
+	})
+
+	recordValues := func() []string {
+		var values []string
+		for _, record := range route53API.records["/hostedzone/Z1"] {
+			for _, r := range record.ResourceRecords {
+				values = append(values, *r.Value)
+			}
+		}
+		return values
+	}
+
+	It("creates the hosted zone and associates it with the VPCs", func() {
+		Expect(Sync(route53API, "cluster-1", hostname, "us-west-2", []string{"vpc-1", "vpc-2"}, []string{"10.0.1.10", "10.0.2.10"})).To(Succeed())
+
+		Expect(route53API.zones).To(HaveLen(1))
+		Expect(*route53API.zones[0].Config.PrivateZone).To(BeTrue())
+		Expect(route53API.vpcs["/hostedzone/Z1"]).To(Equal([]string{"vpc-1", "vpc-2"}))
+		Expect(recordValues()).To(Equal([]string{"10.0.1.10", "10.0.2.10"}))
+	})
+
+	It("updates the records of the existing hosted zone", func() {
+		Expect(Sync(route53API, "cluster-1", hostname, "us-west-2", []string{"vpc-1"}, []string{"10.0.1.10"})).To(Succeed())
+		Expect(Sync(route53API, "cluster-1", hostname, "us-west-2", []string{"vpc-1", "vpc-3"}, []string{"10.0.1.20"})).To(Succeed())
+
+		Expect(route53API.zones).To(HaveLen(1))
+		Expect(route53API.vpcs["/hostedzone/Z1"]).To(Equal([]string{"vpc-1", "vpc-3"}))
+		Expect(recordValues()).To(Equal([]string{"10.0.1.20"}))
+	})
+
+	It("deletes only the hosted zone eksctl created for the cluster", func() {
+		route53API.zones = []*route53.HostedZone{{
+			Id:     aws.String("/hostedzone/Z0"),
+			Name:   aws.String("abcdef.gr7.us-west-2.eks.amazonaws.com."),
+			Config: &route53.HostedZoneConfig{PrivateZone: aws.Bool(true), Comment: aws.String("managed elsewhere")},
+		}}
+		Expect(Delete(route53API, "cluster-1", hostname)).To(Succeed())
+		Expect(route53API.deleted).To(BeEmpty())
+
+		Expect(Sync(route53API, "cluster-1", hostname, "us-west-2", []string{"vpc-1"}, []string{"10.0.1.10"})).To(Succeed())
+		Expect(Delete(route53API, "cluster-1", hostname)).To(Succeed())
+		Expect(route53API.deleted).To(Equal([]string{"/hostedzone/Z1"}))
+		Expect(route53API.records["/hostedzone/Z1"]).To(BeEmpty())
+	})
+
+	It("enables DNS support and hostnames in a VPC", func() {
+		p := mockprovider.NewMockProvider()
+		p.MockEC2().On("DescribeVpcAttribute", &ec2.DescribeVpcAttributeInput{
+			VpcId:     aws.String("vpc-1"),
+			Attribute: aws.String(ec2.VpcAttributeNameEnableDnsSupport),
+		}).Return(&ec2.DescribeVpcAttributeOutput{EnableDnsSupport: &ec2.AttributeBooleanValue{Value: aws.Bool(true)}}, nil)
+		p.MockEC2().On("DescribeVpcAttribute", &ec2.DescribeVpcAttributeInput{
+			VpcId:     aws.String("vpc-1"),
+			Attribute: aws.String(ec2.VpcAttributeNameEnableDnsHostnames),
+		}).Return(&ec2.DescribeVpcAttributeOutput{EnableDnsHostnames: &ec2.AttributeBooleanValue{Value: aws.Bool(false)}}, nil)
+		p.MockEC2().On("ModifyVpcAttribute", mock.Anything).Return(&ec2.ModifyVpcAttributeOutput{}, nil)
+
+		Expect(EnableVPCDNS(p.EC2(), "vpc-1")).To(Succeed())
+		p.MockEC2().AssertNumberOfCalls(GinkgoT(), "ModifyVpcAttribute", 1)
+		input := p.MockEC2().Calls[2].Arguments.Get(0).(*ec2.ModifyVpcAttributeInput)
+		Expect(input.EnableDnsSupport).To(BeNil())
+		Expect(*input.EnableDnsHostnames.Value).To(BeTrue())
+	})
+})
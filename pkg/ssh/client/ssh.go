@@ -7,9 +7,9 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/utils/file"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -6,11 +6,17 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
 	"github.com/aws/aws-sdk-go/service/cloudtrail/cloudtrailiface"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/eks/eksiface"
 	"github.com/aws/aws-sdk-go/service/elb/elbiface"
 	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/aws/aws-sdk-go/service/pricing/pricingiface"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
 	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 
@@ -22,7 +28,10 @@ import (
 	//"github.com/aws/aws-sdk-go/awstesting/unit"
 
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/billing"
 	"github.com/weaveworks/eksctl/pkg/eks/mocks"
+	"github.com/weaveworks/eksctl/pkg/eksaddons"
+	"github.com/weaveworks/eksctl/pkg/inspector"
 )
 
 // ProviderConfig holds current global config
@@ -58,6 +67,16 @@ type MockProvider struct {
 	ssm        *mocks.SSMAPI
 	iam        *mocks.IAMAPI
 	cloudtrail *mocks.CloudTrailAPI
+	kms        *mocks.KMSAPI
+	sns        *mocks.SNSAPI
+	route53    *mocks.Route53API
+	pricing    *mocks.PricingAPI
+	inspector  *mocks.InspectorAPI
+	addons     *mocks.AddonsAPI
+
+	eventBridge        *mocks.EventBridgeAPI
+	cloudWatchLogs     *mocks.CloudWatchLogsAPI
+	costAllocationTags *mocks.CostAllocationTagsAPI
 }
 
 // NewMockProvider returns a new MockProvider
@@ -74,6 +93,16 @@ func NewMockProvider() *MockProvider {
 		ssm:        &mocks.SSMAPI{},
 		iam:        &mocks.IAMAPI{},
 		cloudtrail: &mocks.CloudTrailAPI{},
+		kms:        &mocks.KMSAPI{},
+		sns:        &mocks.SNSAPI{},
+		route53:    &mocks.Route53API{},
+		pricing:    &mocks.PricingAPI{},
+		inspector:  &mocks.InspectorAPI{},
+		addons:     &mocks.AddonsAPI{},
+
+		eventBridge:        &mocks.EventBridgeAPI{},
+		cloudWatchLogs:     &mocks.CloudWatchLogsAPI{},
+		costAllocationTags: &mocks.CostAllocationTagsAPI{},
 	}
 }
 
@@ -100,9 +129,15 @@ func (m MockProvider) EC2() ec2iface.EC2API { return m.ec2 }
 // ELB returns a representation of the ELB API
 func (m MockProvider) ELB() elbiface.ELBAPI { return m.elb }
 
+// MockELB returns a mocked ELB API
+func (m MockProvider) MockELB() *mocks.ELBAPI { return m.ELB().(*mocks.ELBAPI) }
+
 // ELBV2 returns a representation of the ELBV2 API
 func (m MockProvider) ELBV2() elbv2iface.ELBV2API { return m.elbv2 }
 
+// MockELBV2 returns a mocked ELBV2 API
+func (m MockProvider) MockELBV2() *mocks.ELBV2API { return m.ELBV2().(*mocks.ELBV2API) }
+
 // MockEC2 returns a mocked EC2 API
 func (m MockProvider) MockEC2() *mocks.EC2API { return m.EC2().(*mocks.EC2API) }
 
@@ -132,6 +167,66 @@ func (m MockProvider) MockCloudTrail() *mocks.CloudTrailAPI {
 	return m.CloudTrail().(*mocks.CloudTrailAPI)
 }
 
+// KMS returns a representation of the KMS API
+func (m MockProvider) KMS() kmsiface.KMSAPI { return m.kms }
+
+// MockKMS returns a mocked KMS API
+func (m MockProvider) MockKMS() *mocks.KMSAPI { return m.KMS().(*mocks.KMSAPI) }
+
+// SNS returns a representation of the SNS API
+func (m MockProvider) SNS() snsiface.SNSAPI { return m.sns }
+
+// MockSNS returns a mocked SNS API
+func (m MockProvider) MockSNS() *mocks.SNSAPI { return m.SNS().(*mocks.SNSAPI) }
+
+// EventBridge returns a representation of the EventBridge API
+func (m MockProvider) EventBridge() eventbridgeiface.EventBridgeAPI { return m.eventBridge }
+
+// MockEventBridge returns a mocked EventBridge API
+func (m MockProvider) MockEventBridge() *mocks.EventBridgeAPI {
+	return m.EventBridge().(*mocks.EventBridgeAPI)
+}
+
+// Route53 returns a representation of the Route 53 API
+func (m MockProvider) Route53() route53iface.Route53API { return m.route53 }
+
+// MockRoute53 returns a mocked Route 53 API
+func (m MockProvider) MockRoute53() *mocks.Route53API { return m.Route53().(*mocks.Route53API) }
+
+// CloudWatchLogs returns a representation of the CloudWatch Logs API
+func (m MockProvider) CloudWatchLogs() cloudwatchlogsiface.CloudWatchLogsAPI { return m.cloudWatchLogs }
+
+// MockCloudWatchLogs returns a mocked CloudWatch Logs API
+func (m MockProvider) MockCloudWatchLogs() *mocks.CloudWatchLogsAPI {
+	return m.CloudWatchLogs().(*mocks.CloudWatchLogsAPI)
+}
+
+// Pricing returns a representation of the Price List API
+func (m MockProvider) Pricing() pricingiface.PricingAPI { return m.pricing }
+
+// MockPricing returns a mocked Price List API
+func (m MockProvider) MockPricing() *mocks.PricingAPI { return m.Pricing().(*mocks.PricingAPI) }
+
+// CostAllocationTags returns a representation of the cost allocation tags operation of the Cost Explorer API
+func (m MockProvider) CostAllocationTags() billing.CostAllocationTagsAPI { return m.costAllocationTags }
+
+// MockCostAllocationTags returns a mocked cost allocation tags operation of the Cost Explorer API
+func (m MockProvider) MockCostAllocationTags() *mocks.CostAllocationTagsAPI {
+	return m.CostAllocationTags().(*mocks.CostAllocationTagsAPI)
+}
+
+// Inspector returns a representation of the Amazon Inspector API
+func (m MockProvider) Inspector() inspector.API { return m.inspector }
+
+// MockInspector returns a mocked Amazon Inspector API
+func (m MockProvider) MockInspector() *mocks.InspectorAPI { return m.Inspector().(*mocks.InspectorAPI) }
+
+// Addons returns a representation of the EKS add-ons API
+func (m MockProvider) Addons() eksaddons.API { return m.addons }
+
+// MockAddons returns a mocked EKS add-ons API
+func (m MockProvider) MockAddons() *mocks.AddonsAPI { return m.Addons().(*mocks.AddonsAPI) }
+
 // Profile returns current profile setting
 func (m MockProvider) Profile() string { return ProviderConfig.Profile }
 
@@ -141,6 +236,11 @@ func (m MockProvider) Region() string { return ProviderConfig.Region }
 // WaitTimeout returns current timeout setting
 func (m MockProvider) WaitTimeout() time.Duration { return ProviderConfig.WaitTimeout }
 
+// Timeout returns current timeout setting of a phase
+func (m MockProvider) Timeout(phase api.TimeoutPhase) time.Duration {
+	return ProviderConfig.Timeout(phase)
+}
+
 func NewMockAWSClient() *MockAWSClient {
 	m := &MockAWSClient{
 		Client: awstesting.NewClient(&aws.Config{
@@ -0,0 +1,332 @@
+// Package utilization summarizes how the nodegroups of a cluster are used, combining the requests
+// and limits of the pods, the usage reported by metrics-server and the instance types of the
+// nodegroups, and suggests instance types fitting the demand better, as a capacity planning aid
+package utilization
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	corev1 "k8s.io/api/core/v1"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+// managedNodeGroupLabel is the label EKS sets on the nodes of managed nodegroups
+const managedNodeGroupLabel = "eks.amazonaws.com/nodegroup"
+
+// the labels of the instance type of nodes, the beta one is set by older kubelets only
+var instanceTypeLabels = []string{"node.kubernetes.io/instance-type", "beta.kubernetes.io/instance-type"}
+
+// the demand of a nodegroup, as a percentage of what its nodes can allocate, below which smaller
+// instance types are suggested, and above which larger ones are; suggested instance types bring
+// the demand back to targetPercent
+const (
+	lowPercent    = 40
+	highPercent   = 85
+	targetPercent = 70
+)
+
+// NodeGroup is what the summary needs to know about a nodegroup
+type NodeGroup struct {
+	Name            string
+	InstanceType    string
+	DesiredCapacity int
+	MinSize         int
+	MaxSize         int
+}
+
+// Inputs is what the summary needs to know about the cluster
+type Inputs struct {
+	ClusterName string
+	NodeGroups  []NodeGroup
+	Nodes       []corev1.Node
+	// Pods are the pods that aren't terminated
+	Pods []corev1.Pod
+	// Usage is the usage of each node reported by metrics-server, nil when it isn't available
+	Usage map[string]corev1.ResourceList
+	// InstanceTypes are the instance types of the families of the nodegroups
+	InstanceTypes []*ec2.InstanceTypeInfo
+}
+
+// InstanceTypeFamilies returns the families of the instance types of the nodegroups and of the
+// nodes, which are where instance types are suggested from
+func (i *Inputs) InstanceTypeFamilies() []string {
+	families := map[string]struct{}{}
+	for _, ng := range i.NodeGroups {
+		if ng.InstanceType != "" {
+			families[family(ng.InstanceType)] = struct{}{}
+		}
+	}
+	for _, node := range i.Nodes {
+		if instanceType := instanceType(node); instanceType != "" {
+			families[family(instanceType)] = struct{}{}
+		}
+	}
+	var sorted []string
+	for family := range families {
+		sorted = append(sorted, family)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// Resource is the utilization of a resource by the pods of a nodegroup, in millicores for CPU
+// and bytes for memory
+type Resource struct {
+	Allocatable int64 `json:"allocatable"`
+	Requests    int64 `json:"requests"`
+	Limits      int64 `json:"limits"`
+	// Usage is nil when metrics-server isn't available
+	Usage *int64 `json:"usage,omitempty"`
+	// Headroom is what the nodes can still allocate to the requests of new pods
+	Headroom int64 `json:"headroom"`
+}
+
+// RequestsPercent returns the requests as a percentage of the allocatable resources
+func (r Resource) RequestsPercent() int64 {
+	return percent(r.Requests, r.Allocatable)
+}
+
+// UsagePercent returns the usage as a percentage of the allocatable resources, or -1 when the
+// usage is unknown
+func (r Resource) UsagePercent() int64 {
+	if r.Usage == nil {
+		return -1
+	}
+	return percent(*r.Usage, r.Allocatable)
+}
+
+// demand is the largest of the requests and the usage
+func (r Resource) demand() int64 {
+	if r.Usage != nil && *r.Usage > r.Requests {
+		return *r.Usage
+	}
+	return r.Requests
+}
+
+// NodeGroupUtilization is the utilization of a nodegroup
+type NodeGroupUtilization struct {
+	Name            string   `json:"name"`
+	InstanceType    string   `json:"instanceType"`
+	Nodes           int      `json:"nodes"`
+	DesiredCapacity int      `json:"desiredCapacity"`
+	MinSize         int      `json:"minSize"`
+	MaxSize         int      `json:"maxSize"`
+	CPU             Resource `json:"cpu"`
+	Memory          Resource `json:"memory"`
+	// SuggestedInstanceType is a smaller or larger instance type of the same family fitting the
+	// demand of the nodegroup better, if any
+	SuggestedInstanceType string `json:"suggestedInstanceType,omitempty"`
+	Suggestion            string `json:"suggestion,omitempty"`
+}
+
+// Report is the utilization of the nodegroups of a cluster
+type Report struct {
+	Cluster string `json:"cluster"`
+	// UsageAvailable is whether the usage was reported by metrics-server
+	UsageAvailable bool                    `json:"usageAvailable"`
+	NodeGroups     []*NodeGroupUtilization `json:"nodeGroups"`
+}
+
+// Compute builds the report
+func Compute(inputs *Inputs) *Report {
+	report := &Report{
+		Cluster:        inputs.ClusterName,
+		UsageAvailable: inputs.Usage != nil,
+	}
+
+	nodeGroups := map[string]*NodeGroupUtilization{}
+	for _, ng := range inputs.NodeGroups {
+		utilization := &NodeGroupUtilization{
+			Name:            ng.Name,
+			InstanceType:    ng.InstanceType,
+			DesiredCapacity: ng.DesiredCapacity,
+			MinSize:         ng.MinSize,
+			MaxSize:         ng.MaxSize,
+		}
+		if report.UsageAvailable {
+			utilization.CPU.Usage = new(int64)
+			utilization.Memory.Usage = new(int64)
+		}
+		nodeGroups[ng.Name] = utilization
+		report.NodeGroups = append(report.NodeGroups, utilization)
+	}
+
+	nodeGroupOfNode := map[string]*NodeGroupUtilization{}
+	for _, node := range inputs.Nodes {
+		utilization, ok := nodeGroups[nodeGroupName(node)]
+		if !ok {
+			continue
+		}
+		nodeGroupOfNode[node.Name] = utilization
+		utilization.Nodes++
+		if utilization.InstanceType == "" {
+			utilization.InstanceType = instanceType(node)
+		}
+		utilization.CPU.Allocatable += node.Status.Allocatable.Cpu().MilliValue()
+		utilization.Memory.Allocatable += node.Status.Allocatable.Memory().Value()
+		if usage, ok := inputs.Usage[node.Name]; ok {
+			*utilization.CPU.Usage += usage.Cpu().MilliValue()
+			*utilization.Memory.Usage += usage.Memory().Value()
+		}
+	}
+
+	for _, pod := range inputs.Pods {
+		utilization, ok := nodeGroupOfNode[pod.Spec.NodeName]
+		if !ok {
+			continue
+		}
+		requests, limits := podResources(pod)
+		utilization.CPU.Requests += requests.Cpu().MilliValue()
+		utilization.Memory.Requests += requests.Memory().Value()
+		utilization.CPU.Limits += limits.Cpu().MilliValue()
+		utilization.Memory.Limits += limits.Memory().Value()
+	}
+
+	instanceTypes := map[string]*ec2.InstanceTypeInfo{}
+	for _, info := range inputs.InstanceTypes {
+		instanceTypes[aws.StringValue(info.InstanceType)] = info
+	}
+
+	for _, utilization := range report.NodeGroups {
+		utilization.CPU.Headroom = utilization.CPU.Allocatable - utilization.CPU.Requests
+		utilization.Memory.Headroom = utilization.Memory.Allocatable - utilization.Memory.Requests
+		suggest(utilization, instanceTypes)
+	}
+
+	sort.Slice(report.NodeGroups, func(i, j int) bool {
+		return report.NodeGroups[i].Name < report.NodeGroups[j].Name
+	})
+	return report
+}
+
+// suggest looks for the smallest instance type of the family of the nodegroup that runs its
+// demand at targetPercent, when the nodegroup is under- or over-utilized
+func suggest(utilization *NodeGroupUtilization, instanceTypes map[string]*ec2.InstanceTypeInfo) {
+	current, ok := instanceTypes[utilization.InstanceType]
+	if !ok || utilization.Nodes == 0 || utilization.CPU.Allocatable == 0 || utilization.Memory.Allocatable == 0 {
+		return
+	}
+
+	demandPercent := percent(utilization.CPU.demand(), utilization.CPU.Allocatable)
+	if memoryPercent := percent(utilization.Memory.demand(), utilization.Memory.Allocatable); memoryPercent > demandPercent {
+		demandPercent = memoryPercent
+	}
+	if demandPercent >= lowPercent && demandPercent <= highPercent {
+		return
+	}
+
+	// the capacity each node needs, in proportion to the capacity of the current instance type
+	cpuNeeded := float64(utilization.CPU.demand()) / float64(utilization.CPU.Allocatable) * float64(vCPUs(current)) * 100 / targetPercent
+	memoryNeeded := float64(utilization.Memory.demand()) / float64(utilization.Memory.Allocatable) * float64(memoryMiB(current)) * 100 / targetPercent
+
+	var fitting []*ec2.InstanceTypeInfo
+	for _, info := range instanceTypes {
+		if family(aws.StringValue(info.InstanceType)) == family(utilization.InstanceType) &&
+			float64(vCPUs(info)) >= cpuNeeded && float64(memoryMiB(info)) >= memoryNeeded {
+			fitting = append(fitting, info)
+		}
+	}
+	sort.Slice(fitting, func(i, j int) bool {
+		if vCPUs(fitting[i]) != vCPUs(fitting[j]) {
+			return vCPUs(fitting[i]) < vCPUs(fitting[j])
+		}
+		return memoryMiB(fitting[i]) < memoryMiB(fitting[j])
+	})
+
+	switch {
+	case demandPercent < lowPercent:
+		if len(fitting) > 0 && vCPUs(fitting[0]) < vCPUs(current) {
+			utilization.SuggestedInstanceType = aws.StringValue(fitting[0].InstanceType)
+			utilization.Suggestion = "downsize to " + utilization.SuggestedInstanceType
+		} else {
+			utilization.Suggestion = "reduce the number of nodes"
+		}
+	case len(fitting) > 0 && vCPUs(fitting[0]) > vCPUs(current):
+		utilization.SuggestedInstanceType = aws.StringValue(fitting[0].InstanceType)
+		utilization.Suggestion = "upsize to " + utilization.SuggestedInstanceType
+	default:
+		utilization.Suggestion = "increase the number of nodes"
+	}
+}
+
+// podResources returns the requests and limits of a pod, which are the largest of those of its
+// containers and of each of its init containers, as the scheduler sees them
+func podResources(pod corev1.Pod) (requests, limits corev1.ResourceList) {
+	requests, limits = corev1.ResourceList{}, corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		addResources(requests, container.Resources.Requests)
+		addResources(limits, container.Resources.Limits)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		maxResources(requests, container.Resources.Requests)
+		maxResources(limits, container.Resources.Limits)
+	}
+	return requests, limits
+}
+
+func addResources(total, resources corev1.ResourceList) {
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if quantity, ok := resources[name]; ok {
+			sum := total[name]
+			sum.Add(quantity)
+			total[name] = sum
+		}
+	}
+}
+
+func maxResources(total, resources corev1.ResourceList) {
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if quantity, ok := resources[name]; ok {
+			if current, ok := total[name]; !ok || quantity.Cmp(current) > 0 {
+				total[name] = quantity.DeepCopy()
+			}
+		}
+	}
+}
+
+func nodeGroupName(node corev1.Node) string {
+	for _, label := range []string{api.NodeGroupNameLabel, managedNodeGroupLabel} {
+		if name, ok := node.Labels[label]; ok {
+			return name
+		}
+	}
+	return ""
+}
+
+func instanceType(node corev1.Node) string {
+	for _, label := range instanceTypeLabels {
+		if instanceType, ok := node.Labels[label]; ok {
+			return instanceType
+		}
+	}
+	return ""
+}
+
+func family(instanceType string) string {
+	return strings.SplitN(instanceType, ".", 2)[0]
+}
+
+func vCPUs(info *ec2.InstanceTypeInfo) int64 {
+	if info.VCpuInfo == nil {
+		return 0
+	}
+	return aws.Int64Value(info.VCpuInfo.DefaultVCpus)
+}
+
+func memoryMiB(info *ec2.InstanceTypeInfo) int64 {
+	if info.MemoryInfo == nil {
+		return 0
+	}
+	return aws.Int64Value(info.MemoryInfo.SizeInMiB)
+}
+
+func percent(value, total int64) int64 {
+	if total == 0 {
+		return 0
+	}
+	return value * 100 / total
+}
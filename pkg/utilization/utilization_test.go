@@ -0,0 +1,168 @@
+package utilization_test
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/utilization"
+)
+
+var _ = Describe("Nodegroup utilization", func() {
+	var inputs *Inputs
+
+	resources := func(cpu, memory string) corev1.ResourceList {
+		list := corev1.ResourceList{}
+		if cpu != "" {
+			list[corev1.ResourceCPU] = resource.MustParse(cpu)
+		}
+		if memory != "" {
+			list[corev1.ResourceMemory] = resource.MustParse(memory)
+		}
+		return list
+	}
+
+	node := func(name, nodeGroup, instanceType, cpu, memory string) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					api.NodeGroupNameLabel:             nodeGroup,
+					"node.kubernetes.io/instance-type": instanceType,
+				},
+			},
+			Status: corev1.NodeStatus{Allocatable: resources(cpu, memory)},
+		}
+	}
+
+	container := func(requests, limits corev1.ResourceList) corev1.Container {
+		return corev1.Container{Resources: corev1.ResourceRequirements{Requests: requests, Limits: limits}}
+	}
+
+	instanceType := func(name string, vCPUs, memoryMiB int64) *ec2.InstanceTypeInfo {
+		return &ec2.InstanceTypeInfo{
+			InstanceType: aws.String(name),
+			VCpuInfo:     &ec2.VCpuInfo{DefaultVCpus: aws.Int64(vCPUs)},
+			MemoryInfo:   &ec2.MemoryInfo{SizeInMiB: aws.Int64(memoryMiB)},
+		}
+	}
+
+	BeforeEach(func() {
+		inputs = &Inputs{
+			ClusterName: "cluster-1",
+			NodeGroups: []NodeGroup{
+				{Name: "ng-2", DesiredCapacity: 1, MinSize: 1, MaxSize: 3},
+				{Name: "ng-1", InstanceType: "m5.xlarge", DesiredCapacity: 2, MinSize: 1, MaxSize: 4},
+			},
+			Nodes: []corev1.Node{
+				node("node-1", "ng-1", "m5.xlarge", "4", "16Gi"),
+				node("node-2", "ng-1", "m5.xlarge", "4", "16Gi"),
+				node("node-3", "ng-2", "m5.large", "2", "8Gi"),
+				node("node-4", "", "m5.large", "2", "8Gi"),
+			},
+			Pods: []corev1.Pod{
+				{Spec: corev1.PodSpec{
+					NodeName:   "node-1",
+					Containers: []corev1.Container{container(resources("500m", "1Gi"), resources("1", "2Gi"))},
+				}},
+				{Spec: corev1.PodSpec{
+					NodeName:   "node-2",
+					Containers: []corev1.Container{container(resources("500m", "1Gi"), nil)},
+				}},
+				{Spec: corev1.PodSpec{
+					NodeName: "node-3",
+					Containers: []corev1.Container{
+						container(resources("1", "1Gi"), resources("2", "")),
+						container(resources("400m", ""), nil),
+					},
+					InitContainers: []corev1.Container{container(resources("500m", ""), nil)},
+				}},
+				{Spec: corev1.PodSpec{
+					NodeName:       "node-3",
+					Containers:     []corev1.Container{container(resources("100m", ""), nil)},
+					InitContainers: []corev1.Container{container(resources("500m", ""), nil)},
+				}},
+				{Spec: corev1.PodSpec{
+					NodeName:   "node-4",
+					Containers: []corev1.Container{container(resources("1", "1Gi"), nil)},
+				}},
+			},
+			Usage: map[string]corev1.ResourceList{
+				"node-1": resources("700m", "2Gi"),
+				"node-2": resources("500m", "2Gi"),
+				"node-4": resources("1", "1Gi"),
+			},
+			InstanceTypes: []*ec2.InstanceTypeInfo{
+				instanceType("m5.large", 2, 8192),
+				instanceType("m5.xlarge", 4, 16384),
+				instanceType("m5.2xlarge", 8, 32768),
+			},
+		}
+	})
+
+	It("lists the instance type families of the nodegroups", func() {
+		Expect(inputs.InstanceTypeFamilies()).To(Equal([]string{"m5"}))
+	})
+
+	It("sums the requests, limits and usage of the pods of each nodegroup", func() {
+		report := Compute(inputs)
+		Expect(report.Cluster).To(Equal("cluster-1"))
+		Expect(report.UsageAvailable).To(BeTrue())
+		Expect(report.NodeGroups).To(HaveLen(2))
+
+		ng1 := report.NodeGroups[0]
+		Expect(ng1.Name).To(Equal("ng-1"))
+		Expect(ng1.Nodes).To(Equal(2))
+		Expect(ng1.CPU.Allocatable).To(Equal(int64(8000)))
+		Expect(ng1.CPU.Requests).To(Equal(int64(1000)))
+		Expect(ng1.CPU.Limits).To(Equal(int64(1000)))
+		Expect(*ng1.CPU.Usage).To(Equal(int64(1200)))
+		Expect(ng1.CPU.Headroom).To(Equal(int64(7000)))
+		Expect(ng1.CPU.RequestsPercent()).To(Equal(int64(12)))
+		Expect(ng1.CPU.UsagePercent()).To(Equal(int64(15)))
+		Expect(ng1.Memory.Headroom).To(Equal(int64(30 << 30)))
+
+		ng2 := report.NodeGroups[1]
+		Expect(ng2.Name).To(Equal("ng-2"))
+		Expect(ng2.InstanceType).To(Equal("m5.large"))
+		Expect(ng2.Nodes).To(Equal(1))
+		Expect(ng2.CPU.Requests).To(Equal(int64(1900)))
+		Expect(ng2.CPU.Limits).To(Equal(int64(2000)))
+		Expect(*ng2.CPU.Usage).To(BeZero())
+		Expect(ng2.Memory.Requests).To(Equal(int64(1 << 30)))
+	})
+
+	It("suggests smaller instance types for under-utilized nodegroups and larger ones for over-utilized ones", func() {
+		report := Compute(inputs)
+		Expect(report.NodeGroups[0].SuggestedInstanceType).To(Equal("m5.large"))
+		Expect(report.NodeGroups[0].Suggestion).To(Equal("downsize to m5.large"))
+		Expect(report.NodeGroups[1].SuggestedInstanceType).To(Equal("m5.xlarge"))
+		Expect(report.NodeGroups[1].Suggestion).To(Equal("upsize to m5.xlarge"))
+	})
+
+	It("suggests changing the number of nodes when no instance type of the family fits", func() {
+		inputs.InstanceTypes = inputs.InstanceTypes[1:]
+		report := Compute(inputs)
+		Expect(report.NodeGroups[0].Suggestion).To(Equal("reduce the number of nodes"))
+		Expect(report.NodeGroups[1].Suggestion).To(BeEmpty())
+
+		inputs.InstanceTypes = []*ec2.InstanceTypeInfo{instanceType("m5.large", 2, 8192)}
+		report = Compute(inputs)
+		Expect(report.NodeGroups[1].SuggestedInstanceType).To(BeEmpty())
+		Expect(report.NodeGroups[1].Suggestion).To(Equal("increase the number of nodes"))
+	})
+
+	It("relies on the requests without metrics-server", func() {
+		inputs.Usage = nil
+		report := Compute(inputs)
+		Expect(report.UsageAvailable).To(BeFalse())
+		Expect(report.NodeGroups[0].CPU.Usage).To(BeNil())
+		Expect(report.NodeGroups[0].CPU.UsagePercent()).To(Equal(int64(-1)))
+		Expect(report.NodeGroups[0].Suggestion).To(Equal("downsize to m5.large"))
+	})
+})
@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Cache stores JSON-encoded values in files of a directory, which expire after a TTL;
+// it's safe to use from concurrent processes, as entries are replaced atomically
+type Cache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// New creates a cache in dir, whose entries expire after ttl
+func New(dir string, ttl time.Duration) *Cache {
+	return &Cache{
+		dir: dir,
+		ttl: ttl,
+		now: time.Now,
+	}
+}
+
+// DefaultDir returns the directory of the cache of eksctl in the cache directory of the user
+func DefaultDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "eksctl")
+}
+
+// Key returns a key made of the JSON encoding of the parts, e.g. of the name of an
+// operation and its input
+func Key(parts ...interface{}) (string, error) {
+	data, err := json.Marshal(parts)
+	if err != nil {
+		return "", errors.Wrap(err, "encoding cache key")
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Get decodes the entry of the key into value, and returns false if there's no entry or it expired
+func (c *Cache) Get(key string, value interface{}) bool {
+	path := c.path(key)
+	info, err := os.Stat(path)
+	if err != nil || c.now().Sub(info.ModTime()) > c.ttl {
+		return false
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, value) == nil
+}
+
+// Set stores the JSON encoding of value as the entry of the key
+func (c *Cache) Set(key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return errors.Wrap(err, "encoding cache entry")
+	}
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return errors.Wrapf(err, "creating cache directory %q", c.dir)
+	}
+	f, err := ioutil.TempFile(c.dir, key+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "creating cache entry")
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return errors.Wrap(err, "writing cache entry")
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, "writing cache entry")
+	}
+	return os.Rename(f.Name(), c.path(key))
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/weaveworks/eksctl/pkg/testutils"
+)
+
+func TestSuite(t *testing.T) {
+	testutils.RegisterAndRun(t)
+}
+
+var _ = Describe("Cache", func() {
+	var (
+		dir   string
+		cache *Cache
+		now   time.Time
+	)
+
+	type entry struct {
+		Zones []string
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "eksctl-cache")
+		Expect(err).NotTo(HaveOccurred())
+
+		now = time.Now()
+		cache = New(dir, time.Hour)
+		cache.now = func() time.Time { return now }
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("returns entries until they expire", func() {
+		key, err := Key("ec2.DescribeAvailabilityZones", "us-west-2")
+		Expect(err).NotTo(HaveOccurred())
+
+		var value entry
+		Expect(cache.Get(key, &value)).To(BeFalse())
+
+		Expect(cache.Set(key, entry{Zones: []string{"us-west-2a", "us-west-2b"}})).To(Succeed())
+		Expect(cache.Get(key, &value)).To(BeTrue())
+		Expect(value.Zones).To(Equal([]string{"us-west-2a", "us-west-2b"}))
+
+		now = now.Add(2 * time.Hour)
+		Expect(cache.Get(key, &value)).To(BeFalse())
+	})
+
+	It("derives distinct keys from the parts", func() {
+		key1, _ := Key("ec2.DescribeAvailabilityZones", "us-west-2")
+		key2, _ := Key("ec2.DescribeAvailabilityZones", "eu-west-1")
+		Expect(key1).NotTo(Equal(key2))
+	})
+})
@@ -0,0 +1,133 @@
+// Package configsource reads config files from local files, stdin and remote sources,
+// i.e. S3 objects, HTTPS URLs and OCI artifacts
+package configsource
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	schemeS3    = "s3://"
+	schemeHTTPS = "https://"
+	schemeHTTP  = "http://"
+	schemeOCI   = "oci://"
+
+	// maxSize limits the size of remote config files
+	maxSize = 10 << 20
+
+	checksumPrefix = "sha256:"
+)
+
+// reader reads config files, remote sources are fetched with httpClient,
+// except for S3 objects, which are fetched with the client newS3Client creates
+type reader struct {
+	httpClient  *http.Client
+	newS3Client func() (s3Client, error)
+}
+
+var defaultReader = &reader{
+	httpClient:  &http.Client{Timeout: 30 * time.Second},
+	newS3Client: newSessionS3Client,
+}
+
+// IsRemote returns true when source is a URL of a remote config file
+func IsRemote(source string) bool {
+	for _, scheme := range []string{schemeS3, schemeHTTPS, schemeHTTP, schemeOCI} {
+		if strings.HasPrefix(source, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// Read reads a config file from source, which is either a local path, "-" for stdin,
+// or an s3://bucket/key, https:// or oci://registry/repository[:tag|@digest] URL;
+// when checksum is set, the content must have the given SHA-256 digest
+func Read(source, checksum string) ([]byte, error) {
+	return defaultReader.read(source, checksum)
+}
+
+func (r *reader) read(source, checksum string) ([]byte, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	switch {
+	case source == "-":
+		data, err = ioutil.ReadAll(os.Stdin)
+	case strings.HasPrefix(source, schemeS3):
+		data, err = r.readS3(strings.TrimPrefix(source, schemeS3))
+	case strings.HasPrefix(source, schemeHTTPS):
+		data, err = r.readHTTPS(source)
+	case strings.HasPrefix(source, schemeHTTP):
+		err = fmt.Errorf("config files can only be fetched over HTTPS")
+	case strings.HasPrefix(source, schemeOCI):
+		data, err = r.readOCI(strings.TrimPrefix(source, schemeOCI))
+	default:
+		data, err = ioutil.ReadFile(source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if checksum != "" {
+		if err := VerifyChecksum(data, checksum); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// VerifyChecksum checks that data has the given SHA-256 digest, the
+// checksum is hex-encoded and may optionally be prefixed with "sha256:"
+func VerifyChecksum(data []byte, checksum string) error {
+	expected := strings.ToLower(strings.TrimPrefix(checksum, checksumPrefix))
+	if _, err := hex.DecodeString(expected); err != nil || len(expected) != sha256.Size*2 {
+		return fmt.Errorf("invalid checksum %q, expected a hex-encoded SHA-256 digest", checksum)
+	}
+	actual := sha256Hex(data)
+	if actual != expected {
+		return fmt.Errorf("checksum mismatch: expected %s%s, got %s%s", checksumPrefix, expected, checksumPrefix, actual)
+	}
+	return nil
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (r *reader) readHTTPS(url string) ([]byte, error) {
+	resp, err := r.httpClient.Get(url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching %s", url)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: unexpected status %q", url, resp.Status)
+	}
+	return readLimited(resp.Body)
+}
+
+func readLimited(body io.Reader) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxSize {
+		return nil, fmt.Errorf("config file exceeds the maximum size of %d bytes", maxSize)
+	}
+	return data, nil
+}
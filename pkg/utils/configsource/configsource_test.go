@@ -0,0 +1,231 @@
+package configsource
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/weaveworks/eksctl/pkg/eks/mocks"
+	"github.com/weaveworks/eksctl/pkg/testutils"
+)
+
+func TestSuite(t *testing.T) {
+	testutils.RegisterAndRun(t)
+}
+
+type fakeS3Client struct {
+	regions map[string]string
+	api     *mocks.S3API
+	region  string
+}
+
+func (c *fakeS3Client) BucketRegion(bucket string) (string, error) {
+	region, ok := c.regions[bucket]
+	if !ok {
+		return "", fmt.Errorf("bucket %q not found", bucket)
+	}
+	return region, nil
+}
+
+func (c *fakeS3Client) API(region string) s3iface.S3API {
+	c.region = region
+	return c.api
+}
+
+const config = `apiVersion: eksctl.io/v1alpha5
+kind: ClusterConfig
+metadata:
+  name: cluster-1
+  region: eu-north-1
+`
+
+var _ = Describe("configsource", func() {
+	var (
+		server *httptest.Server
+		mux    *http.ServeMux
+		r      *reader
+	)
+
+	BeforeEach(func() {
+		mux = http.NewServeMux()
+		server = httptest.NewTLSServer(mux)
+		r = &reader{httpClient: server.Client()}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("VerifyChecksum", func() {
+		It("accepts a matching digest with or without prefix", func() {
+			digest := sha256Hex([]byte(config))
+			Expect(VerifyChecksum([]byte(config), digest)).To(Succeed())
+			Expect(VerifyChecksum([]byte(config), "sha256:"+strings.ToUpper(digest))).To(Succeed())
+		})
+
+		It("rejects a different digest", func() {
+			err := VerifyChecksum([]byte(config), "sha256:"+sha256Hex([]byte("other")))
+			Expect(err).To(MatchError(HavePrefix("checksum mismatch")))
+		})
+
+		It("rejects an invalid checksum", func() {
+			Expect(VerifyChecksum([]byte(config), "md5:abc")).To(MatchError(HavePrefix("invalid checksum")))
+		})
+	})
+
+	Describe("HTTPS", func() {
+		BeforeEach(func() {
+			mux.HandleFunc("/cluster.yaml", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, config)
+			})
+		})
+
+		It("fetches the config file", func() {
+			data, err := r.read(server.URL+"/cluster.yaml", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal(config))
+		})
+
+		It("verifies the checksum", func() {
+			_, err := r.read(server.URL+"/cluster.yaml", sha256Hex([]byte("other")))
+			Expect(err).To(MatchError(HavePrefix("checksum mismatch")))
+		})
+
+		It("fails on a missing file", func() {
+			_, err := r.read(server.URL+"/missing.yaml", "")
+			Expect(err).To(MatchError(ContainSubstring(`unexpected status "404 Not Found"`)))
+		})
+
+		It("refuses plain HTTP", func() {
+			_, err := r.read("http://example.com/cluster.yaml", "")
+			Expect(err).To(MatchError("config files can only be fetched over HTTPS"))
+		})
+	})
+
+	Describe("S3", func() {
+		var client *fakeS3Client
+
+		BeforeEach(func() {
+			client = &fakeS3Client{
+				regions: map[string]string{"configs": "eu-north-1"},
+				api:     &mocks.S3API{},
+			}
+			r.newS3Client = func() (s3Client, error) { return client, nil }
+
+			client.api.On("GetObject", &s3.GetObjectInput{
+				Bucket: aws.String("configs"),
+				Key:    aws.String("team/cluster.yaml"),
+			}).Return(&s3.GetObjectOutput{
+				Body: ioutil.NopCloser(strings.NewReader(config)),
+			}, nil)
+		})
+
+		It("fetches the object from the region of the bucket", func() {
+			data, err := r.read("s3://configs/team/cluster.yaml", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal(config))
+			Expect(client.region).To(Equal("eu-north-1"))
+		})
+
+		It("fails on a missing bucket", func() {
+			_, err := r.read("s3://missing/cluster.yaml", "")
+			Expect(err).To(MatchError(`finding the region of bucket "missing": bucket "missing" not found`))
+		})
+
+		It("rejects URLs without a key", func() {
+			_, err := r.read("s3://configs", "")
+			Expect(err).To(MatchError(HavePrefix("invalid S3 URL")))
+		})
+	})
+
+	Describe("OCI", func() {
+		var (
+			registry     string
+			layerDigest  string
+			manifestData []byte
+		)
+
+		BeforeEach(func() {
+			registry = strings.TrimPrefix(server.URL, "https://")
+			layerDigest = "sha256:" + sha256Hex([]byte(config))
+
+			manifest := map[string]interface{}{
+				"schemaVersion": 2,
+				"layers": []map[string]interface{}{
+					{
+						"mediaType":   "application/vnd.oci.image.layer.v1.tar",
+						"digest":      "sha256:" + sha256Hex([]byte("README")),
+						"annotations": map[string]string{ociTitleAnnotation: "README.md"},
+					},
+					{
+						"mediaType":   "application/vnd.oci.image.layer.v1.tar",
+						"digest":      layerDigest,
+						"annotations": map[string]string{ociTitleAnnotation: "cluster.yaml"},
+					},
+				},
+			}
+			var err error
+			manifestData, err = json.Marshal(manifest)
+			Expect(err).NotTo(HaveOccurred())
+
+			mux.HandleFunc("/token", func(w http.ResponseWriter, req *http.Request) {
+				if req.URL.Query().Get("scope") != "repository:team/clusters:pull" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				fmt.Fprint(w, `{"token": "secret"}`)
+			})
+			mux.HandleFunc("/v2/team/clusters/", func(w http.ResponseWriter, req *http.Request) {
+				if req.Header.Get("Authorization") != "Bearer secret" {
+					w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:team/clusters:pull"`, server.URL))
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				switch req.URL.Path {
+				case "/v2/team/clusters/manifests/v1", "/v2/team/clusters/manifests/sha256:" + sha256Hex(manifestData):
+					w.Write(manifestData)
+				case "/v2/team/clusters/blobs/" + layerDigest:
+					fmt.Fprint(w, config)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+		})
+
+		It("pulls the config file layer by tag", func() {
+			data, err := r.read(fmt.Sprintf("oci://%s/team/clusters:v1", registry), "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal(config))
+		})
+
+		It("pulls the config file layer by digest", func() {
+			data, err := r.read(fmt.Sprintf("oci://%s/team/clusters@sha256:%s", registry, sha256Hex(manifestData)), "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal(config))
+		})
+
+		It("fails on a missing tag", func() {
+			_, err := r.read(fmt.Sprintf("oci://%s/team/clusters", registry), "")
+			Expect(err).To(MatchError(ContainSubstring(`unexpected status "404 Not Found"`)))
+		})
+	})
+
+	It("parses OCI references", func() {
+		o, err := parseOCIReference("registry.example.com:5000/team/clusters:v1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*o).To(Equal(ociReference{registry: "registry.example.com:5000", repository: "team/clusters", reference: "v1"}))
+
+		_, err = parseOCIReference("registry.example.com")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,211 @@
+package configsource
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	ociManifestMediaType    = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestMediaType = "application/vnd.docker.distribution.manifest.v2+json"
+
+	ociTitleAnnotation = "org.opencontainers.image.title"
+)
+
+// ociReference is a parsed registry/repository[:tag|@digest] reference
+type ociReference struct {
+	registry, repository, reference string
+}
+
+func parseOCIReference(ref string) (*ociReference, error) {
+	parts := strings.SplitN(ref, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid OCI reference %q, expected oci://<registry>/<repository>[:<tag>|@<digest>]", schemeOCI+ref)
+	}
+	o := &ociReference{registry: parts[0], repository: parts[1], reference: "latest"}
+
+	if i := strings.Index(o.repository, "@"); i >= 0 {
+		o.repository, o.reference = o.repository[:i], o.repository[i+1:]
+	} else if i := strings.LastIndex(o.repository, ":"); i >= 0 {
+		o.repository, o.reference = o.repository[:i], o.repository[i+1:]
+	}
+	if o.repository == "" || o.reference == "" {
+		return nil, fmt.Errorf("invalid OCI reference %q", schemeOCI+ref)
+	}
+	return o, nil
+}
+
+func (o *ociReference) isDigest() bool {
+	return strings.HasPrefix(o.reference, checksumPrefix)
+}
+
+func (o *ociReference) url(kind, reference string) string {
+	return fmt.Sprintf("https://%s/v2/%s/%s/%s", o.registry, o.repository, kind, reference)
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ociManifest struct {
+	Layers []ociDescriptor `json:"layers"`
+}
+
+// readOCI pulls a config file stored as an OCI artifact, the artifact must either have a
+// single layer or a layer titled with a .yaml, .yml or .json file name; the digests of the
+// manifest, when referenced by digest, and of the layer are always verified
+func (r *reader) readOCI(ref string) ([]byte, error) {
+	o, err := parseOCIReference(ref)
+	if err != nil {
+		return nil, err
+	}
+	client := &ociClient{reader: r}
+
+	manifestData, err := client.get(o.url("manifests", o.reference), strings.Join([]string{ociManifestMediaType, dockerManifestMediaType}, ", "))
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching manifest of %s%s", schemeOCI, ref)
+	}
+	if o.isDigest() {
+		if err := VerifyChecksum(manifestData, o.reference); err != nil {
+			return nil, errors.Wrapf(err, "verifying manifest of %s%s", schemeOCI, ref)
+		}
+	}
+
+	var manifest ociManifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, errors.Wrapf(err, "parsing manifest of %s%s", schemeOCI, ref)
+	}
+	layer, err := findConfigLayer(manifest.Layers)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s%s", schemeOCI, ref)
+	}
+
+	data, err := client.get(o.url("blobs", layer.Digest), "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching layer %s of %s%s", layer.Digest, schemeOCI, ref)
+	}
+	if err := VerifyChecksum(data, layer.Digest); err != nil {
+		return nil, errors.Wrapf(err, "verifying layer %s of %s%s", layer.Digest, schemeOCI, ref)
+	}
+	return data, nil
+}
+
+func findConfigLayer(layers []ociDescriptor) (*ociDescriptor, error) {
+	if len(layers) == 1 {
+		return &layers[0], nil
+	}
+	for i, layer := range layers {
+		switch path.Ext(layer.Annotations[ociTitleAnnotation]) {
+		case ".yaml", ".yml", ".json":
+			return &layers[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no config file found in %d layers", len(layers))
+}
+
+// ociClient fetches content from a registry, requesting an anonymous
+// bearer token when the registry asks for one
+type ociClient struct {
+	*reader
+	token string
+}
+
+func (c *ociClient) get(rawURL, accept string) ([]byte, error) {
+	resp, err := c.do(rawURL, accept)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && c.token == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if c.token, err = c.fetchToken(challenge); err != nil {
+			return nil, err
+		}
+		if resp, err = c.do(rawURL, accept); err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %q", resp.Status)
+	}
+	return readLimited(resp.Body)
+}
+
+func (c *ociClient) do(rawURL, accept string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	return c.httpClient.Do(req)
+}
+
+var challengeParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+func (c *ociClient) fetchToken(challenge string) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("registry requires unsupported authentication %q", challenge)
+	}
+	params := map[string]string{}
+	for _, match := range challengeParamRegex.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+	realm, ok := params["realm"]
+	if !ok {
+		return "", fmt.Errorf("no realm in authentication challenge %q", challenge)
+	}
+
+	query := url.Values{}
+	for _, key := range []string{"service", "scope"} {
+		if value, ok := params[key]; ok {
+			query.Set(key, value)
+		}
+	}
+	tokenURL := realm
+	if len(query) > 0 {
+		tokenURL += "?" + query.Encode()
+	}
+
+	resp, err := c.httpClient.Get(tokenURL)
+	if err != nil {
+		return "", errors.Wrap(err, "requesting registry token")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("requesting registry token: unexpected status %q", resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", errors.Wrap(err, "parsing registry token")
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
@@ -0,0 +1,95 @@
+package configsource
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/pkg/errors"
+)
+
+// bucketRegionHint is used to find the region of the bucket
+// when no region is configured in the environment
+const bucketRegionHint = "us-east-1"
+
+// s3Client creates clients of the S3 API and finds the regions of buckets,
+// S3 objects are read with the default credential chain
+type s3Client interface {
+	BucketRegion(bucket string) (string, error)
+	API(region string) s3iface.S3API
+}
+
+type sessionS3Client struct {
+	sess *session.Session
+}
+
+func newSessionS3Client() (s3Client, error) {
+	config := aws.NewConfig()
+	if endpoint := s3Endpoint(); endpoint != "" {
+		// emulators don't serve buckets as subdomains of their endpoint
+		config = config.WithEndpoint(endpoint).WithS3ForcePathStyle(true)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *config,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "creating AWS session")
+	}
+	return &sessionS3Client{sess: sess}, nil
+}
+
+// s3Endpoint returns the endpoint of S3 overridden in the environment, like
+// the endpoints of the clients created by eks.New
+func s3Endpoint() string {
+	for _, envVar := range []string{"AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"} {
+		if endpoint := os.Getenv(envVar); endpoint != "" {
+			return endpoint
+		}
+	}
+	return ""
+}
+
+func (c *sessionS3Client) BucketRegion(bucket string) (string, error) {
+	return s3manager.GetBucketRegion(context.Background(), c.sess, bucket, bucketRegionHint)
+}
+
+func (c *sessionS3Client) API(region string) s3iface.S3API {
+	return s3.New(c.sess, aws.NewConfig().WithRegion(region))
+}
+
+// readS3 reads an object given as bucket/key
+func (r *reader) readS3(path string) ([]byte, error) {
+	parts := strings.SplitN(path, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid S3 URL %q, expected s3://<bucket>/<key>", schemeS3+path)
+	}
+	bucket, key := parts[0], parts[1]
+
+	client, err := r.newS3Client()
+	if err != nil {
+		return nil, err
+	}
+
+	region, err := client.BucketRegion(bucket)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding the region of bucket %q", bucket)
+	}
+
+	output, err := client.API(region).GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching %s%s", schemeS3, path)
+	}
+	defer output.Body.Close()
+
+	return readLimited(output.Body)
+}
@@ -1,16 +1,19 @@
 package kubeconfig
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"strings"
+	"text/template"
 
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/utils/file"
 
 	"os/exec"
 
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
 	"k8s.io/client-go/tools/clientcmd"
@@ -74,16 +77,79 @@ func New(spec *api.ClusterConfig, username, certificateAuthorityPath string) (*c
 
 // NewForKubectl creates configuration for kubectl using a suitable authenticator
 func NewForKubectl(spec *api.ClusterConfig, username, roleARN, profile string) *clientcmdapi.Config {
+	return NewForAuthenticator(spec, username, "", roleARN, profile)
+}
+
+// NewForAuthenticator creates configuration for kubectl using the given
+// authenticator, or a suitable one if authenticator is empty
+func NewForAuthenticator(spec *api.ClusterConfig, username, authenticator, roleARN, profile string) *clientcmdapi.Config {
 	config, _, _ := New(spec, username, "")
-	authenticator, found := LookupAuthenticator()
-	if !found {
-		// fall back to aws-iam-authenticator
-		authenticator = AWSIAMAuthenticator
+	if authenticator == "" {
+		var found bool
+		authenticator, found = LookupAuthenticator()
+		if !found {
+			// fall back to aws-iam-authenticator
+			authenticator = AWSIAMAuthenticator
+		}
 	}
 	AppendAuthenticator(config, spec, authenticator, roleARN, profile)
 	return config
 }
 
+// NewForServiceAccountToken creates configuration for kubectl that authenticates
+// with the token of a service account, e.g. for use in CI systems
+func NewForServiceAccountToken(spec *api.ClusterConfig, serviceAccountName, token string) *clientcmdapi.Config {
+	config, _, _ := New(spec, serviceAccountName, "")
+	config.AuthInfos[config.CurrentContext] = &clientcmdapi.AuthInfo{
+		Token: token,
+	}
+	return config
+}
+
+// ContextNameData is the data available to context name templates
+type ContextNameData struct {
+	ClusterName, Region, Username string
+}
+
+// RenameContext renames the current context of config, along with its user, to the
+// name rendered from nameTemplate, e.g. "{{ .ClusterName }}-{{ .Region }}"
+func RenameContext(config *clientcmdapi.Config, nameTemplate string, data ContextNameData) error {
+	tmpl, err := template.New("context").Option("missingkey=error").Parse(nameTemplate)
+	if err != nil {
+		return errors.Wrapf(err, "parsing context name template %q", nameTemplate)
+	}
+	var name bytes.Buffer
+	if err := tmpl.Execute(&name, data); err != nil {
+		return errors.Wrapf(err, "rendering context name template %q", nameTemplate)
+	}
+	if name.Len() == 0 {
+		return fmt.Errorf("context name template %q rendered an empty name", nameTemplate)
+	}
+
+	oldName, newName := config.CurrentContext, name.String()
+	if oldName == newName {
+		return nil
+	}
+	context := config.Contexts[oldName]
+	context.AuthInfo = newName
+	config.Contexts[newName] = context
+	config.AuthInfos[newName] = config.AuthInfos[oldName]
+	delete(config.Contexts, oldName)
+	delete(config.AuthInfos, oldName)
+	config.CurrentContext = newName
+	return nil
+}
+
+// WriteTo writes Kubernetes client configuration to w, without merging it with any existing configuration
+func WriteTo(w io.Writer, config clientcmdapi.Config) error {
+	data, err := clientcmd.Write(config)
+	if err != nil {
+		return errors.Wrap(err, "serialising kubeconfig")
+	}
+	_, err = w.Write(data)
+	return err
+}
+
 // AppendAuthenticator appends the AWS IAM  authenticator, and
 // if profile is non-empty string it sets AWS_PROFILE environment
 // variable also
@@ -1,6 +1,7 @@
 package kubeconfig_test
 
 import (
+	"bytes"
 	"io/ioutil"
 	"os"
 
@@ -297,4 +298,65 @@ var _ = Describe("Kubeconfig", func() {
 			Expect(configFileAsBytes).To(MatchYAML(twoClustersAsBytes), "Should not change")
 		})
 	})
+
+	Context("new config for kubectl", func() {
+		var cfg *eksctlapi.ClusterConfig
+
+		BeforeEach(func() {
+			cfg = eksctlapi.NewClusterConfig()
+			cfg.Metadata.Name = "foo"
+			cfg.Metadata.Region = "us-west-2"
+			cfg.Status = &eksctlapi.ClusterStatus{
+				Endpoint:                 "https://foo.eks.amazonaws.com",
+				CertificateAuthorityData: []byte("ca"),
+			}
+		})
+
+		It("pins the authenticator, role and profile", func() {
+			config := kubeconfig.NewForAuthenticator(cfg, "admin", kubeconfig.AWSEKSAuthenticator, "arn:aws:iam::123:role/admin", "prod")
+
+			exec := config.AuthInfos["admin@foo.us-west-2.eksctl.io"].Exec
+			Expect(exec.Command).To(Equal("aws"))
+			Expect(exec.Args).To(Equal([]string{"eks", "get-token", "--cluster-name", "foo", "--region", "us-west-2", "--role-arn", "arn:aws:iam::123:role/admin"}))
+			Expect(exec.Env).To(ConsistOf(api.ExecEnvVar{Name: "AWS_PROFILE", Value: "prod"}))
+		})
+
+		It("uses the token of a serviceaccount", func() {
+			config := kubeconfig.NewForServiceAccountToken(cfg, "ci/deployer", "secret-token")
+
+			Expect(config.CurrentContext).To(Equal("ci/deployer@foo.us-west-2.eksctl.io"))
+			authInfo := config.AuthInfos[config.CurrentContext]
+			Expect(authInfo.Token).To(Equal("secret-token"))
+			Expect(authInfo.Exec).To(BeNil())
+		})
+
+		It("renames the context from a template", func() {
+			config := kubeconfig.NewForKubectl(cfg, "admin", "", "")
+
+			data := kubeconfig.ContextNameData{ClusterName: "foo", Region: "us-west-2", Username: "admin"}
+			Expect(kubeconfig.RenameContext(config, "{{ .ClusterName }}-{{ .Region }}", data)).To(Succeed())
+
+			Expect(config.CurrentContext).To(Equal("foo-us-west-2"))
+			Expect(config.Contexts).To(HaveLen(1))
+			Expect(config.Contexts["foo-us-west-2"].AuthInfo).To(Equal("foo-us-west-2"))
+			Expect(config.Contexts["foo-us-west-2"].Cluster).To(Equal("foo.us-west-2.eksctl.io"))
+			Expect(config.AuthInfos).To(HaveLen(1))
+			Expect(config.AuthInfos["foo-us-west-2"].Exec).NotTo(BeNil())
+
+			Expect(kubeconfig.RenameContext(config, "{{ .Namespace }}", data)).NotTo(Succeed())
+			Expect(kubeconfig.RenameContext(config, "", data)).To(MatchError(`context name template "" rendered an empty name`))
+		})
+
+		It("writes the config without merging", func() {
+			config := kubeconfig.NewForServiceAccountToken(cfg, "ci/deployer", "secret-token")
+
+			var out bytes.Buffer
+			Expect(kubeconfig.WriteTo(&out, *config)).To(Succeed())
+
+			readConfig, err := clientcmd.Load(out.Bytes())
+			Expect(err).To(BeNil())
+			Expect(readConfig.CurrentContext).To(Equal(config.CurrentContext))
+			Expect(readConfig.Clusters["foo.us-west-2.eksctl.io"].Server).To(Equal("https://foo.eks.amazonaws.com"))
+		})
+	})
 })
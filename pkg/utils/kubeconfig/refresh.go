@@ -0,0 +1,112 @@
+package kubeconfig
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ClusterEntry is a cluster in a kubeconfig file that refers to an EKS cluster
+type ClusterEntry struct {
+	// Name is the name of the EKS cluster
+	Name string
+	// Key is the name of the cluster in the kubeconfig file
+	Key string
+}
+
+// Read reads the Kubernetes client configuration from the file at path,
+// or the files determined by client-go if path isn't specified
+func Read(path string) (*clientcmdapi.Config, error) {
+	config, err := getConfigAccess(path).GetStartingConfig()
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read existing kubeconfig file %q", path)
+	}
+	return config, nil
+}
+
+// Save replaces the Kubernetes client configuration read with Read
+func Save(path string, config *clientcmdapi.Config) error {
+	if err := clientcmd.ModifyConfig(getConfigAccess(path), *config, true); err != nil {
+		return errors.Wrapf(err, "unable to modify kubeconfig %s", path)
+	}
+	return nil
+}
+
+// ClustersInRegion returns the clusters of config that refer to EKS clusters in region,
+// i.e. entries written by eksctl and entries named by cluster ARN, as written by
+// `aws eks update-kubeconfig`
+func ClustersInRegion(config *clientcmdapi.Config, region string) []ClusterEntry {
+	var entries []ClusterEntry
+	for key := range config.Clusters {
+		if name, ok := clusterNameInRegion(key, region); ok {
+			entries = append(entries, ClusterEntry{Name: name, Key: key})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries
+}
+
+func clusterNameInRegion(key, region string) (string, bool) {
+	if suffix := fmt.Sprintf(".%s.eksctl.io", region); strings.HasSuffix(key, suffix) {
+		name := strings.TrimSuffix(key, suffix)
+		return name, name != ""
+	}
+	// arn:<partition>:eks:<region>:<account>:cluster/<name>
+	parts := strings.SplitN(key, ":", 6)
+	if len(parts) == 6 && parts[0] == "arn" && parts[2] == "eks" && parts[3] == region && strings.HasPrefix(parts[5], "cluster/") {
+		name := strings.TrimPrefix(parts[5], "cluster/")
+		return name, name != ""
+	}
+	return "", false
+}
+
+// UpdateClusterInfo sets the endpoint and certificate authority data of the cluster
+// stored under key, returns 'true' if the existing config has changes and 'false' otherwise
+func UpdateClusterInfo(config *clientcmdapi.Config, key, endpoint string, certificateAuthorityData []byte) bool {
+	cluster, ok := config.Clusters[key]
+	if !ok {
+		return false
+	}
+	if cluster.Server == endpoint && bytes.Equal(cluster.CertificateAuthorityData, certificateAuthorityData) {
+		return false
+	}
+	cluster.Server = endpoint
+	cluster.CertificateAuthorityData = certificateAuthorityData
+	// an explicit certificate authority file takes precedence over the data
+	cluster.CertificateAuthority = ""
+	return true
+}
+
+// DeleteClusterInfo removes the cluster stored under key along with all contexts
+// that use it and their users, unless those users are used by other contexts;
+// the current context is reset if it is removed
+func DeleteClusterInfo(config *clientcmdapi.Config, key string) {
+	delete(config.Clusters, key)
+
+	for name, context := range config.Contexts {
+		if context.Cluster != key {
+			continue
+		}
+		delete(config.Contexts, name)
+		if config.CurrentContext == name {
+			config.CurrentContext = ""
+		}
+		if !isAuthInfoUsed(config, context.AuthInfo) {
+			delete(config.AuthInfos, context.AuthInfo)
+		}
+	}
+}
+
+func isAuthInfoUsed(config *clientcmdapi.Config, authInfo string) bool {
+	for _, context := range config.Contexts {
+		if context.AuthInfo == authInfo {
+			return true
+		}
+	}
+	return false
+}
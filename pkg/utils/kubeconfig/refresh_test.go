@@ -0,0 +1,74 @@
+package kubeconfig_test
+
+import (
+	"github.com/weaveworks/eksctl/pkg/utils/kubeconfig"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Kubeconfig refresh", func() {
+	const arn = "arn:aws:eks:us-west-2:123456789012:cluster/bar"
+
+	var config *api.Config
+
+	BeforeEach(func() {
+		config = &api.Config{
+			Clusters: map[string]*api.Cluster{
+				"foo.us-west-2.eksctl.io": {Server: "https://old.example.com", CertificateAuthorityData: []byte("old")},
+				"baz.eu-west-1.eksctl.io": {Server: "https://baz.example.com"},
+				arn:                       {Server: "https://bar.example.com", CertificateAuthorityData: []byte("bar")},
+				"minikube":                {Server: "https://192.168.99.100:8443"},
+			},
+			Contexts: map[string]*api.Context{
+				"admin@foo.us-west-2.eksctl.io": {Cluster: "foo.us-west-2.eksctl.io", AuthInfo: "admin@foo.us-west-2.eksctl.io"},
+				"foo-readonly":                  {Cluster: "foo.us-west-2.eksctl.io", AuthInfo: "shared"},
+				arn:                             {Cluster: arn, AuthInfo: "shared"},
+				"minikube":                      {Cluster: "minikube", AuthInfo: "minikube"},
+			},
+			AuthInfos: map[string]*api.AuthInfo{
+				"admin@foo.us-west-2.eksctl.io": {},
+				"shared":                        {},
+				"minikube":                      {},
+			},
+			CurrentContext: "admin@foo.us-west-2.eksctl.io",
+		}
+	})
+
+	It("finds the EKS clusters of a region", func() {
+		Expect(kubeconfig.ClustersInRegion(config, "us-west-2")).To(Equal([]kubeconfig.ClusterEntry{
+			{Name: "bar", Key: arn},
+			{Name: "foo", Key: "foo.us-west-2.eksctl.io"},
+		}))
+		Expect(kubeconfig.ClustersInRegion(config, "eu-west-1")).To(Equal([]kubeconfig.ClusterEntry{
+			{Name: "baz", Key: "baz.eu-west-1.eksctl.io"},
+		}))
+		Expect(kubeconfig.ClustersInRegion(config, "us-east-1")).To(BeEmpty())
+	})
+
+	It("updates the endpoint and CA data", func() {
+		Expect(kubeconfig.UpdateClusterInfo(config, "foo.us-west-2.eksctl.io", "https://new.example.com", []byte("new"))).To(BeTrue())
+		Expect(config.Clusters["foo.us-west-2.eksctl.io"].Server).To(Equal("https://new.example.com"))
+		Expect(config.Clusters["foo.us-west-2.eksctl.io"].CertificateAuthorityData).To(Equal([]byte("new")))
+
+		Expect(kubeconfig.UpdateClusterInfo(config, arn, "https://bar.example.com", []byte("bar"))).To(BeFalse())
+		Expect(kubeconfig.UpdateClusterInfo(config, "missing", "https://new.example.com", nil)).To(BeFalse())
+	})
+
+	It("deletes a cluster with its contexts and unused users", func() {
+		kubeconfig.DeleteClusterInfo(config, "foo.us-west-2.eksctl.io")
+
+		Expect(config.Clusters).NotTo(HaveKey("foo.us-west-2.eksctl.io"))
+		Expect(config.Contexts).To(HaveLen(2))
+		Expect(config.Contexts).To(HaveKey(arn))
+		Expect(config.AuthInfos).NotTo(HaveKey("admin@foo.us-west-2.eksctl.io"))
+		Expect(config.AuthInfos).To(HaveKey("shared"))
+		Expect(config.CurrentContext).To(BeEmpty())
+
+		kubeconfig.DeleteClusterInfo(config, arn)
+		Expect(config.Contexts).To(HaveLen(1))
+		Expect(config.AuthInfos).To(HaveLen(1))
+		Expect(config.AuthInfos).To(HaveKey("minikube"))
+	})
+})
@@ -1,14 +1,16 @@
 package kubectl
 
 import (
+	"encoding/json"
 	"fmt"
+	"os/exec"
 	"strings"
 
 	"github.com/blang/semver"
 	"github.com/kballard/go-shellquote"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
 
+	"github.com/weaveworks/eksctl/pkg/logger"
 	"github.com/weaveworks/eksctl/pkg/utils/kubeconfig"
 	"github.com/weaveworks/launcher/pkg/kubectl"
 
@@ -99,3 +101,57 @@ func CheckAllCommands(kubeconfigPath string, isContextSet bool, contextName stri
 
 	return nil
 }
+
+// ClientVersion returns the version of the kubectl client, without contacting any cluster
+func ClientVersion(env []string) (string, error) {
+	ktl := &kubectl.LocalClient{Env: env}
+	kubectlPath, err := ktl.LookPath()
+	if err != nil {
+		return "", fmt.Errorf("kubectl not found")
+	}
+	cmd := exec.Command(kubectlPath, "version", "--client", "--output=json")
+	cmd.Env = env
+	output, err := cmd.Output()
+	if err != nil {
+		return "", errors.Wrapf(err, "running %q", kubectlPath+" version --client")
+	}
+	var versionInfo struct {
+		ClientVersion struct {
+			GitVersion string `json:"gitVersion"`
+		} `json:"clientVersion"`
+	}
+	if err := json.Unmarshal(output, &versionInfo); err != nil {
+		return "", errors.Wrap(err, "parsing kubectl version")
+	}
+	return versionInfo.ClientVersion.GitVersion, nil
+}
+
+// VersionSkew returns how many minor versions the kubectl client is ahead of the cluster,
+// negative when it's behind
+func VersionSkew(clientVersion, serverVersion string) (int, error) {
+	client, err := semver.ParseTolerant(clientVersion)
+	if err != nil {
+		return 0, errors.Wrapf(err, "parsing kubectl version %q", clientVersion)
+	}
+	server, err := semver.ParseTolerant(serverVersion)
+	if err != nil {
+		return 0, errors.Wrapf(err, "parsing Kubernetes version %q", serverVersion)
+	}
+	if client.Major != server.Major {
+		return 0, fmt.Errorf("kubectl %s and Kubernetes %s have different major versions", clientVersion, serverVersion)
+	}
+	return int(client.Minor) - int(server.Minor), nil
+}
+
+// CheckVersionSkew warns when the kubectl client is more than one minor version older or newer
+// than the cluster, which kubectl doesn't support
+func CheckVersionSkew(clientVersion, serverVersion string) {
+	skew, err := VersionSkew(clientVersion, serverVersion)
+	if err != nil {
+		logger.Debug("skipping the kubectl version skew check: %s", err)
+		return
+	}
+	if skew > 1 || skew < -1 {
+		logger.Warning("kubectl %s is only supported with Kubernetes versions within one minor version of it, the cluster runs %s; install a matching version of kubectl", clientVersion, serverVersion)
+	}
+}
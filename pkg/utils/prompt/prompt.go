@@ -0,0 +1,119 @@
+// Package prompt asks the user questions, which are identified by an ID so that
+// they can be answered up front and commands can run unattended
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
+)
+
+// Prompter asks questions on a terminal
+type Prompter struct {
+	// AssumeYes answers all confirmations with yes
+	AssumeYes bool
+	// NonInteractive fails questions that aren't answered up front, instead of asking them
+	NonInteractive bool
+	// Answers are the answers to questions by ID
+	Answers map[string]string
+
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// Default is the prompter of the running command
+var Default = New(os.Stdin, os.Stderr)
+
+// New creates a prompter reading answers from in and writing questions to out
+func New(in io.Reader, out io.Writer) *Prompter {
+	return &Prompter{
+		Answers: map[string]string{},
+		in:      bufio.NewReader(in),
+		out:     out,
+	}
+}
+
+// NotAnsweredError is returned for questions without an answer when running non-interactively
+type NotAnsweredError struct {
+	ID       string
+	Question string
+}
+
+func (e *NotAnsweredError) Error() string {
+	return fmt.Sprintf("question %q (%s) can't be asked when running non-interactively, answer it with --answer=%s=<answer>", e.ID, e.Question, e.ID)
+}
+
+// Ask returns the answer to the question, asking it unless it was answered up front
+func (p *Prompter) Ask(id, question string) (string, error) {
+	if answer, ok := p.Answers[id]; ok {
+		logger.Debug("using the given answer to question %q", id)
+		return answer, nil
+	}
+	if p.NonInteractive {
+		return "", &NotAnsweredError{ID: id, Question: question}
+	}
+
+	fmt.Fprintf(p.out, "%s: ", question)
+	answer, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || answer == "") {
+		return "", errors.Wrapf(err, "reading answer to question %q", id)
+	}
+	return strings.TrimSpace(answer), nil
+}
+
+// Confirm asks a yes/no question, which is answered with yes when AssumeYes is set
+// and with no when the answer is empty
+func (p *Prompter) Confirm(id, question string) (bool, error) {
+	if _, ok := p.Answers[id]; !ok && p.AssumeYes {
+		return true, nil
+	}
+	answer, err := p.Ask(id, question+" [y/N]")
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(answer) {
+	case "y", "yes", "true":
+		return true, nil
+	case "", "n", "no", "false":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid answer %q to question %q, expected yes or no", answer, id)
+	}
+}
+
+// LoadAnswers reads answers to questions by ID from a YAML or JSON file,
+// without overriding answers that were already given
+func (p *Prompter) LoadAnswers(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "reading answers file %q", path)
+	}
+	answers := map[string]string{}
+	if err := yaml.Unmarshal(data, &answers); err != nil {
+		return errors.Wrapf(err, "parsing answers file %q", path)
+	}
+	for id, answer := range answers {
+		if _, ok := p.Answers[id]; !ok {
+			p.Answers[id] = answer
+		}
+	}
+	return nil
+}
+
+// Ask asks the question with the default prompter
+func Ask(id, question string) (string, error) {
+	return Default.Ask(id, question)
+}
+
+// Confirm asks the yes/no question with the default prompter
+func Confirm(id, question string) (bool, error) {
+	return Default.Confirm(id, question)
+}
@@ -0,0 +1,84 @@
+package prompt
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/weaveworks/eksctl/pkg/testutils"
+)
+
+func TestSuite(t *testing.T) {
+	testutils.RegisterAndRun(t)
+}
+
+var _ = Describe("prompt", func() {
+	var out *bytes.Buffer
+
+	newPrompter := func(input string) *Prompter {
+		out = &bytes.Buffer{}
+		return New(strings.NewReader(input), out)
+	}
+
+	It("asks questions", func() {
+		p := newPrompter("123456\n")
+		answer, err := p.Ask("mfa-token", "Assume Role MFA token code")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(answer).To(Equal("123456"))
+		Expect(out.String()).To(Equal("Assume Role MFA token code: "))
+	})
+
+	It("uses answers given up front", func() {
+		p := newPrompter("")
+		p.NonInteractive = true
+		p.Answers["mfa-token"] = "654321"
+
+		Expect(p.Ask("mfa-token", "Assume Role MFA token code")).To(Equal("654321"))
+		Expect(out.Len()).To(BeZero())
+	})
+
+	It("fails unanswered questions when non-interactive", func() {
+		p := newPrompter("123456\n")
+		p.NonInteractive = true
+
+		_, err := p.Ask("mfa-token", "Assume Role MFA token code")
+		Expect(err).To(MatchError(ContainSubstring("answer it with --answer=mfa-token=<answer>")))
+		Expect(out.Len()).To(BeZero())
+	})
+
+	It("confirms", func() {
+		Expect(newPrompter("yes\n").Confirm("delete", "Delete?")).To(BeTrue())
+		Expect(out.String()).To(Equal("Delete? [y/N]: "))
+		Expect(newPrompter("\n").Confirm("delete", "Delete?")).To(BeFalse())
+
+		_, err := newPrompter("maybe\n").Confirm("delete", "Delete?")
+		Expect(err).To(MatchError(`invalid answer "maybe" to question "delete", expected yes or no`))
+
+		p := newPrompter("")
+		p.AssumeYes, p.NonInteractive = true, true
+		Expect(p.Confirm("delete", "Delete?")).To(BeTrue())
+
+		p.Answers["delete"] = "no"
+		Expect(p.Confirm("delete", "Delete?")).To(BeFalse())
+	})
+
+	It("loads answers from a file", func() {
+		dir, err := ioutil.TempDir("", "prompt")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "answers.yaml")
+		Expect(ioutil.WriteFile(path, []byte("mfa-token: \"000000\"\ndelete: \"yes\"\n"), 0600)).To(Succeed())
+
+		p := newPrompter("")
+		p.Answers["mfa-token"] = "123456"
+		Expect(p.LoadAnswers(path)).To(Succeed())
+		Expect(p.Answers).To(Equal(map[string]string{"mfa-token": "123456", "delete": "yes"}))
+	})
+})
@@ -0,0 +1,122 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Media types of manifests
+const (
+	MediaTypeOCIManifest        = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeOCIIndex           = "application/vnd.oci.image.index.v1+json"
+	MediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	MediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+)
+
+const (
+	dockerHubRegistry    = "docker.io"
+	dockerHubAPIRegistry = "registry-1.docker.io"
+)
+
+// Image is a parsed container image reference
+type Image struct {
+	Registry   string
+	Repository string
+	// Reference is a tag or a digest
+	Reference string
+}
+
+// ParseImage parses a container image reference the way the container runtime does,
+// i.e. images without a registry are pulled from Docker Hub, and official Docker Hub
+// images are in the library namespace
+func ParseImage(image string) (*Image, error) {
+	i := &Image{Registry: dockerHubRegistry, Repository: image, Reference: "latest"}
+
+	if parts := strings.SplitN(image, "/", 2); len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		i.Registry, i.Repository = parts[0], parts[1]
+	}
+	if at := strings.Index(i.Repository, "@"); at >= 0 {
+		i.Repository, i.Reference = i.Repository[:at], i.Repository[at+1:]
+	} else if colon := strings.LastIndex(i.Repository, ":"); colon >= 0 {
+		i.Repository, i.Reference = i.Repository[:colon], i.Repository[colon+1:]
+	}
+	if i.Repository == "" || i.Reference == "" {
+		return nil, fmt.Errorf("invalid image %q", image)
+	}
+	if i.Registry == dockerHubRegistry && !strings.Contains(i.Repository, "/") {
+		i.Repository = "library/" + i.Repository
+	}
+	return i, nil
+}
+
+// URL returns the URL of a manifest or a blob of the image
+func (i *Image) URL(kind, reference string) string {
+	registry := i.Registry
+	if registry == dockerHubRegistry {
+		registry = dockerHubAPIRegistry
+	}
+	return fmt.Sprintf("https://%s/v2/%s/%s/%s", registry, i.Repository, kind, reference)
+}
+
+type platformManifest struct {
+	MediaType string `json:"mediaType"`
+	Config    struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+	Manifests []struct {
+		Platform struct {
+			Architecture string `json:"architecture"`
+			OS           string `json:"os"`
+		} `json:"platform"`
+	} `json:"manifests"`
+}
+
+// Architectures returns the architectures the image is built for, i.e. the architectures of
+// a multi-arch image index, or the architecture in the config of a single image
+func (c *Client) Architectures(image *Image) ([]string, error) {
+	accept := strings.Join([]string{MediaTypeOCIIndex, MediaTypeDockerManifestList, MediaTypeOCIManifest, MediaTypeDockerManifest}, ", ")
+	data, err := c.Get(image.URL("manifests", image.Reference), accept)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching manifest")
+	}
+	var manifest platformManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, errors.Wrap(err, "parsing manifest")
+	}
+
+	architectures := map[string]bool{}
+	if len(manifest.Manifests) > 0 {
+		for _, m := range manifest.Manifests {
+			// attestation manifests have an unknown platform
+			if m.Platform.Architecture != "" && m.Platform.Architecture != "unknown" {
+				architectures[m.Platform.Architecture] = true
+			}
+		}
+	} else {
+		if manifest.Config.Digest == "" {
+			return nil, fmt.Errorf("unsupported manifest media type %q", manifest.MediaType)
+		}
+		data, err := c.Get(image.URL("blobs", manifest.Config.Digest), "")
+		if err != nil {
+			return nil, errors.Wrap(err, "fetching image config")
+		}
+		var config struct {
+			Architecture string `json:"architecture"`
+		}
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, errors.Wrap(err, "parsing image config")
+		}
+		architectures[config.Architecture] = true
+	}
+
+	var result []string
+	for architecture := range architectures {
+		result = append(result, architecture)
+	}
+	sort.Strings(result)
+	return result, nil
+}
@@ -0,0 +1,101 @@
+package registry_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/weaveworks/eksctl/pkg/utils/registry"
+)
+
+var _ = Describe("Images", func() {
+	It("parses images like the container runtime", func() {
+		for image, expected := range map[string]Image{
+			"nginx":                          {Registry: "docker.io", Repository: "library/nginx", Reference: "latest"},
+			"weaveworks/eksctl:0.20.0":       {Registry: "docker.io", Repository: "weaveworks/eksctl", Reference: "0.20.0"},
+			"localhost:5000/app@sha256:abcd": {Registry: "localhost:5000", Repository: "app", Reference: "sha256:abcd"},
+			"quay.io/coreos/etcd:v3.4.9":     {Registry: "quay.io", Repository: "coreos/etcd", Reference: "v3.4.9"},
+			"602401143452.dkr.ecr.us-west-2.amazonaws.com/amazon-k8s-cni:v1.6.1": {
+				Registry: "602401143452.dkr.ecr.us-west-2.amazonaws.com", Repository: "amazon-k8s-cni", Reference: "v1.6.1",
+			},
+		} {
+			parsed, err := ParseImage(image)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*parsed).To(Equal(expected))
+		}
+
+		parsed, _ := ParseImage("nginx")
+		Expect(parsed.URL("manifests", "latest")).To(Equal("https://registry-1.docker.io/v2/library/nginx/manifests/latest"))
+	})
+
+	Describe("Architectures", func() {
+		var (
+			server *httptest.Server
+			client *Client
+			image  *Image
+		)
+
+		BeforeEach(func() {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/v2/team/multi/manifests/v1", func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.Header.Get("Accept")).To(ContainSubstring(MediaTypeDockerManifestList))
+				fmt.Fprint(w, `{"mediaType": "application/vnd.docker.distribution.manifest.list.v2+json", "manifests": [
+					{"platform": {"architecture": "amd64", "os": "linux"}},
+					{"platform": {"architecture": "arm64", "os": "linux"}},
+					{"platform": {"architecture": "unknown", "os": "unknown"}}
+				]}`)
+			})
+			mux.HandleFunc("/v2/team/single/manifests/v1", func(w http.ResponseWriter, req *http.Request) {
+				fmt.Fprint(w, `{"mediaType": "application/vnd.docker.distribution.manifest.v2+json", "config": {"digest": "sha256:1234"}}`)
+			})
+			mux.HandleFunc("/v2/team/single/blobs/sha256:1234", func(w http.ResponseWriter, req *http.Request) {
+				fmt.Fprint(w, `{"architecture": "amd64", "os": "linux"}`)
+			})
+			mux.HandleFunc("/v2/team/private/manifests/v1", func(w http.ResponseWriter, req *http.Request) {
+				if req.Header.Get("Authorization") != "Basic QVdTOnBhc3N3b3Jk" {
+					w.Header().Set("WWW-Authenticate", `Basic realm="https://123456789012.dkr.ecr.us-west-2.amazonaws.com/",service="ecr.amazonaws.com"`)
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				fmt.Fprint(w, `{"manifests": [{"platform": {"architecture": "arm64", "os": "linux"}}]}`)
+			})
+			server = httptest.NewTLSServer(mux)
+			client = NewClient(server.Client(), 1<<20)
+			image = &Image{Registry: strings.TrimPrefix(server.URL, "https://"), Reference: "v1"}
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("returns the architectures of a multi-arch image", func() {
+			image.Repository = "team/multi"
+			Expect(client.Architectures(image)).To(Equal([]string{"amd64", "arm64"}))
+		})
+
+		It("returns the architecture of a single image", func() {
+			image.Repository = "team/single"
+			Expect(client.Architectures(image)).To(Equal([]string{"amd64"}))
+		})
+
+		It("authenticates with basic credentials", func() {
+			image.Repository = "team/private"
+			_, err := client.Architectures(image)
+			Expect(err).To(MatchError("fetching manifest: registry requires credentials"))
+
+			client = NewClient(server.Client(), 1<<20)
+			client.SetBasicAuth("QVdTOnBhc3N3b3Jk")
+			Expect(client.Architectures(image)).To(Equal([]string{"arm64"}))
+		})
+
+		It("fails on missing images", func() {
+			image.Repository = "team/missing"
+			_, err := client.Architectures(image)
+			Expect(err).To(MatchError(`fetching manifest: unexpected status "404 Not Found"`))
+		})
+	})
+})
@@ -0,0 +1,143 @@
+// Package registry fetches manifests and blobs from OCI and Docker registries
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Client fetches content from a registry, with basic authentication when credentials are
+// set, or requesting an anonymous bearer token when the registry asks for one; as tokens are
+// scoped to a repository, a Client should only be used for a single repository
+type Client struct {
+	httpClient *http.Client
+	maxSize    int64
+	token      string
+	basicAuth  string
+}
+
+// NewClient returns a Client that fetches content of up to maxSize bytes with httpClient
+func NewClient(httpClient *http.Client, maxSize int64) *Client {
+	return &Client{
+		httpClient: httpClient,
+		maxSize:    maxSize,
+	}
+}
+
+// SetBasicAuth makes the client authenticate with base64-encoded user:password credentials,
+// e.g. an ECR authorization token
+func (c *Client) SetBasicAuth(credentials string) {
+	c.basicAuth = credentials
+}
+
+// Get fetches rawURL, accepting the given media types when accept is set
+func (c *Client) Get(rawURL, accept string) ([]byte, error) {
+	resp, err := c.do(rawURL, accept)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && c.token == "" && c.basicAuth == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if c.token, err = c.fetchToken(challenge); err != nil {
+			return nil, err
+		}
+		if resp, err = c.do(rawURL, accept); err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %q", resp.Status)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, c.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > c.maxSize {
+		return nil, fmt.Errorf("content exceeds the maximum size of %d bytes", c.maxSize)
+	}
+	return data, nil
+}
+
+func (c *Client) do(rawURL, accept string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	switch {
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	case c.basicAuth != "":
+		req.Header.Set("Authorization", "Basic "+c.basicAuth)
+	}
+	return c.httpClient.Do(req)
+}
+
+var challengeParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+func (c *Client) fetchToken(challenge string) (string, error) {
+	if strings.HasPrefix(challenge, "Basic ") {
+		return "", errors.New("registry requires credentials")
+	}
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("registry requires unsupported authentication %q", challenge)
+	}
+	params := map[string]string{}
+	for _, match := range challengeParamRegex.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+	realm, ok := params["realm"]
+	if !ok {
+		return "", fmt.Errorf("no realm in authentication challenge %q", challenge)
+	}
+
+	query := url.Values{}
+	for _, key := range []string{"service", "scope"} {
+		if value, ok := params[key]; ok {
+			query.Set(key, value)
+		}
+	}
+	tokenURL := realm
+	if len(query) > 0 {
+		tokenURL += "?" + query.Encode()
+	}
+
+	resp, err := c.httpClient.Get(tokenURL)
+	if err != nil {
+		return "", errors.Wrap(err, "requesting registry token")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("requesting registry token: unexpected status %q", resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", errors.Wrap(err, "parsing registry token")
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
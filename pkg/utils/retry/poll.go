@@ -0,0 +1,76 @@
+package retry
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Poller polls a condition, waiting an exponentially growing interval with jitter between
+// polls, until the condition is met or the context is done
+type Poller struct {
+	// Interval is the wait after the first poll
+	Interval time.Duration
+	// MaxInterval caps the wait between polls, it isn't capped if zero
+	MaxInterval time.Duration
+	// Factor multiplies the wait after each poll, values below 1 keep it constant
+	Factor float64
+	// Jitter adds up to this fraction of the wait at random, so that clients polling the same
+	// API don't do it in lockstep
+	Jitter float64
+}
+
+// DefaultPoller is the Poller of waits on AWS and Kubernetes resources
+var DefaultPoller = Poller{
+	Interval:    2 * time.Second,
+	MaxInterval: 30 * time.Second,
+	Factor:      1.5,
+	Jitter:      0.2,
+}
+
+// ConditionFunc reports whether polling is done, returning an error stops polling
+type ConditionFunc func() (done bool, err error)
+
+// Poll calls condition until it is done or fails, returning the error of condition or, if
+// ctx is done first, the error of ctx
+func (p Poller) Poll(ctx context.Context, condition ConditionFunc) error {
+	interval := p.Interval
+	for {
+		done, err := condition()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		timer := time.NewTimer(p.jitter(interval))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		if p.Factor > 1 {
+			interval = time.Duration(float64(interval) * p.Factor)
+		}
+		if p.MaxInterval > 0 && interval > p.MaxInterval {
+			interval = p.MaxInterval
+		}
+	}
+}
+
+// PollWithTimeout is Poll with a context that times out after timeout
+func (p Poller) PollWithTimeout(timeout time.Duration, condition ConditionFunc) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return p.Poll(ctx, condition)
+}
+
+func (p Poller) jitter(interval time.Duration) time.Duration {
+	if p.Jitter <= 0 {
+		return interval
+	}
+	return interval + time.Duration(rand.Float64()*p.Jitter*float64(interval))
+}
@@ -0,0 +1,61 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/weaveworks/eksctl/pkg/utils/retry"
+)
+
+var _ = Describe("Poller", func() {
+	poller := retry.Poller{
+		Interval:    time.Millisecond,
+		MaxInterval: 4 * time.Millisecond,
+		Factor:      2,
+		Jitter:      0.5,
+	}
+
+	It("polls until the condition is met", func() {
+		var polls int
+		err := poller.Poll(context.Background(), func() (bool, error) {
+			polls++
+			return polls == 5, nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(polls).To(Equal(5))
+	})
+
+	It("stops at the first error of the condition", func() {
+		var polls int
+		err := poller.Poll(context.Background(), func() (bool, error) {
+			polls++
+			return false, errors.New("access denied")
+		})
+		Expect(err).To(MatchError("access denied"))
+		Expect(polls).To(Equal(1))
+	})
+
+	It("returns as soon as the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		slowPoller := retry.Poller{Interval: time.Hour}
+
+		start := time.Now()
+		err := slowPoller.Poll(ctx, func() (bool, error) {
+			cancel()
+			return false, nil
+		})
+		Expect(err).To(Equal(context.Canceled))
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+
+	It("times out", func() {
+		err := poller.PollWithTimeout(20*time.Millisecond, func() (bool, error) {
+			return false, nil
+		})
+		Expect(err).To(Equal(context.DeadlineExceeded))
+	})
+})
@@ -14,6 +14,14 @@ func IsGPUInstanceType(instanceType string) bool {
 	return strings.HasPrefix(instanceType, "p2") || strings.HasPrefix(instanceType, "p3") || strings.HasPrefix(instanceType, "g3") || strings.HasPrefix(instanceType, "g4")
 }
 
+var armInstanceTypeRegex = regexp.MustCompile(`^(a1|[a-z]+[0-9]+g[a-z]*)\.`)
+
+// IsARMInstanceType returns true if the instance type has an ARM processor, i.e. A1 and Graviton
+// instance types, e.g. m6g.large or c6gd.xlarge
+func IsARMInstanceType(instanceType string) bool {
+	return armInstanceTypeRegex.MatchString(instanceType)
+}
+
 // HasGPUInstanceType returns true if it finds a gpu instance among the mixed instances
 func HasGPUInstanceType(instanceTypes []string) bool {
 	for _, instanceType := range instanceTypes {
@@ -8,8 +8,9 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws/request"
-	"github.com/kris-nova/logger"
 	"github.com/pkg/errors"
+
+	"github.com/weaveworks/eksctl/pkg/logger"
 )
 
 // Wait for something with a name to reach status that is expressed by acceptors using newRequest
@@ -30,12 +31,33 @@ func Wait(name, msg string, acceptors []request.WaiterAcceptor, newRequest func(
 				return wrappedErr
 			}
 		}
+		if ctx.Err() == context.DeadlineExceeded {
+			return NewTimeoutError(errors.Wrap(waitErr, msg))
+		}
 		return errors.Wrap(waitErr, msg)
 	}
 	logger.Debug("done after %s of %s", time.Since(startTime), msg)
 	return nil
 }
 
+// TimeoutError is returned when waiting timed out
+type TimeoutError struct {
+	err error
+}
+
+// NewTimeoutError marks err as a timeout
+func NewTimeoutError(err error) error {
+	return &TimeoutError{err: err}
+}
+
+func (e *TimeoutError) Error() string { return e.err.Error() }
+
+// IsTimeout returns whether waiting timed out
+func IsTimeout(err error) bool {
+	_, ok := errors.Cause(err).(*TimeoutError)
+	return ok
+}
+
 func makeWaiter(ctx context.Context, name, msg string, acceptors []request.WaiterAcceptor, newRequest func() *request.Request) request.Waiter {
 	return request.Waiter{
 		Name:        name,
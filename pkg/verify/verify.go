@@ -0,0 +1,428 @@
+// Package verify smoke tests a cluster after it's created, by running a short-lived test
+// workload and checking that it gets scheduled, resolves DNS, pulls images from ECR and
+// gets volumes provisioned
+package verify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/kubernetes"
+	"github.com/weaveworks/eksctl/pkg/logger"
+	"github.com/weaveworks/eksctl/pkg/utils/retry"
+)
+
+// Check names
+const (
+	CheckScheduling         = "scheduling"
+	CheckDNS                = "dns"
+	CheckECRPull            = "ecr-pull"
+	CheckVolumeProvisioning = "volume-provisioning"
+)
+
+// Statuses of checks
+const (
+	StatusPass = "pass"
+	StatusFail = "fail"
+	StatusSkip = "skip"
+)
+
+const (
+	// DefaultNamespace is the namespace the test workload runs in, it's deleted afterwards
+	DefaultNamespace = "eksctl-verify"
+	// DefaultImage is the image of the pods testing scheduling, DNS and volumes
+	DefaultImage = "public.ecr.aws/docker/library/busybox:1.36"
+
+	pollInterval = 5 * time.Second
+
+	managedNodeGroupLabel = "eks.amazonaws.com/nodegroup"
+	computeTypeLabel      = "eks.amazonaws.com/compute-type"
+	dnsLookupName         = "kubernetes.default.svc.cluster.local"
+	volumeClaimName       = "verify"
+)
+
+var (
+	zoneLabels               = []string{"topology.kubernetes.io/zone", "failure-domain.beta.kubernetes.io/zone"}
+	defaultStorageClassAnnos = []string{"storageclass.kubernetes.io/is-default-class", "storageclass.beta.kubernetes.io/is-default-class"}
+)
+
+// Options configures the test workload
+type Options struct {
+	Namespace string
+	Image     string
+	// ECRImage is pulled to check that nodes can pull images from ECR
+	ECRImage string
+	Timeout  time.Duration
+}
+
+// Result is the result of one check
+type Result struct {
+	Name string `json:"name"`
+	// Target is the nodegroup and availability zone a check ran in, if any
+	Target  string `json:"target,omitempty"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// Report holds the results of all checks
+type Report struct {
+	Cluster string   `json:"cluster"`
+	Passed  bool     `json:"passed"`
+	Results []Result `json:"results"`
+}
+
+// Verifier runs the test workload in a cluster
+type Verifier struct {
+	clientSet kubernetes.Interface
+	options   Options
+}
+
+// NewVerifier creates a new Verifier
+func NewVerifier(clientSet kubernetes.Interface, options Options) *Verifier {
+	if options.Namespace == "" {
+		options.Namespace = DefaultNamespace
+	}
+	if options.Image == "" {
+		options.Image = DefaultImage
+	}
+	return &Verifier{clientSet: clientSet, options: options}
+}
+
+// A probe creates the objects of a check and evaluates them until it's done
+type probe struct {
+	name   string
+	target string
+	create func() error
+	// evaluate returns nil while the check is pending
+	evaluate func() (*Result, error)
+}
+
+// Verify runs the checks in a new namespace, which is deleted once they're done; the report
+// passes when no check fails, checks that are skipped don't fail it
+func (v *Verifier) Verify(cluster string) (*Report, error) {
+	namespaces := v.clientSet.CoreV1().Namespaces()
+	if _, err := namespaces.Create(kubernetes.NewNamespace(v.options.Namespace)); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("namespace %q exists, a previous verification may still be cleaning up", v.options.Namespace)
+		}
+		return nil, errors.Wrapf(err, "creating namespace %q", v.options.Namespace)
+	}
+	defer func() {
+		if err := namespaces.Delete(v.options.Namespace, &metav1.DeleteOptions{}); err != nil {
+			logger.Warning("unable to delete namespace %q of the test workload: %s", v.options.Namespace, err.Error())
+		}
+	}()
+
+	probes, skipped, err := v.probes()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range probes {
+		if err := p.create(); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &Report{Cluster: cluster, Results: skipped}
+	logger.Info("waiting up to %s for %d checks of the test workload", v.options.Timeout, len(probes))
+	poller := retry.Poller{Interval: pollInterval}
+	err = poller.PollWithTimeout(v.options.Timeout, func() (bool, error) {
+		var pending []probe
+		for _, p := range probes {
+			result, err := p.evaluate()
+			if err != nil {
+				return false, err
+			}
+			if result == nil {
+				pending = append(pending, p)
+				continue
+			}
+			result.Name, result.Target = p.name, p.target
+			report.Results = append(report.Results, *result)
+		}
+		probes = pending
+		return len(probes) == 0, nil
+	})
+	if err == context.DeadlineExceeded {
+		for _, p := range probes {
+			report.Results = append(report.Results, Result{Name: p.name, Target: p.target, Status: StatusFail,
+				Message: fmt.Sprintf("timed out after %s", v.options.Timeout)})
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(report.Results, func(i, j int) bool {
+		return report.Results[i].Target < report.Results[j].Target
+	})
+	report.Passed = true
+	for _, result := range report.Results {
+		if result.Status == StatusFail {
+			report.Passed = false
+		}
+	}
+	return report, nil
+}
+
+// probes returns a probe per nodegroup and availability zone, of pulling from ECR and of
+// provisioning volumes, and the results of the checks that can't run
+func (v *Verifier) probes() ([]probe, []Result, error) {
+	nodes, err := v.clientSet.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "listing nodes")
+	}
+
+	type placement struct {
+		nodeSelector map[string]string
+		target       string
+	}
+	placements := map[string]placement{}
+	for _, node := range nodes.Items {
+		if node.Labels[computeTypeLabel] == "fargate" {
+			continue
+		}
+		selector := map[string]string{}
+		var target []string
+		for _, label := range []string{api.NodeGroupNameLabel, managedNodeGroupLabel} {
+			if nodeGroup, ok := node.Labels[label]; ok {
+				selector[label] = nodeGroup
+				target = append(target, nodeGroup)
+				break
+			}
+		}
+		for _, label := range zoneLabels {
+			if zone, ok := node.Labels[label]; ok {
+				selector[label] = zone
+				target = append(target, zone)
+				break
+			}
+		}
+		if len(target) == 0 {
+			continue
+		}
+		placements[strings.Join(target, "/")] = placement{nodeSelector: selector, target: strings.Join(target, "/")}
+	}
+
+	var targets []string
+	for target := range placements {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	var probes []probe
+	var skipped []Result
+	if len(targets) == 0 {
+		skipped = append(skipped, Result{Name: CheckScheduling, Status: StatusSkip, Message: "the cluster has no nodegroups"})
+	}
+	for i, target := range targets {
+		probes = append(probes, v.schedulingProbes(fmt.Sprintf("dns-%d", i), placements[target].nodeSelector, target)...)
+	}
+
+	if v.options.ECRImage != "" {
+		probes = append(probes, v.ecrPullProbe())
+	}
+
+	volumeProbe, ok, err := v.volumeProvisioningProbe()
+	if err != nil {
+		return nil, nil, err
+	}
+	if ok {
+		probes = append(probes, volumeProbe)
+	} else {
+		skipped = append(skipped, Result{Name: CheckVolumeProvisioning, Status: StatusSkip, Message: "the cluster has no default storage class"})
+	}
+	return probes, skipped, nil
+}
+
+func (v *Verifier) pod(name string, container corev1.Container) *corev1.Pod {
+	container.Name = name
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: v.options.Namespace,
+		},
+		Spec: corev1.PodSpec{
+			Containers:    []corev1.Container{container},
+			RestartPolicy: corev1.RestartPolicyNever,
+			// nodegroups are tested regardless of their taints
+			Tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+		},
+	}
+}
+
+func (v *Verifier) createPod(pod *corev1.Pod) func() error {
+	return func() error {
+		if _, err := v.clientSet.CoreV1().Pods(v.options.Namespace).Create(pod); err != nil {
+			return errors.Wrapf(err, "creating pod %q", pod.Name)
+		}
+		return nil
+	}
+}
+
+func (v *Verifier) getPod(name string) (*corev1.Pod, error) {
+	pod, err := v.clientSet.CoreV1().Pods(v.options.Namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting pod %q", name)
+	}
+	return pod, nil
+}
+
+// schedulingProbes probe that a pod pinned to the nodes of a nodegroup in an availability
+// zone gets scheduled, and that it resolves the name of the API server service
+func (v *Verifier) schedulingProbes(name string, nodeSelector map[string]string, target string) []probe {
+	pod := v.pod(name, corev1.Container{
+		Image:   v.options.Image,
+		Command: []string{"nslookup", dnsLookupName},
+	})
+	pod.Spec.NodeSelector = nodeSelector
+
+	scheduling := probe{
+		name:   CheckScheduling,
+		target: target,
+		create: v.createPod(pod),
+		evaluate: func() (*Result, error) {
+			pod, err := v.getPod(name)
+			if err != nil {
+				return nil, err
+			}
+			if pod.Spec.NodeName == "" {
+				return nil, nil
+			}
+			return &Result{Status: StatusPass, Message: fmt.Sprintf("scheduled on node %q", pod.Spec.NodeName)}, nil
+		},
+	}
+	dns := probe{
+		name:   CheckDNS,
+		target: target,
+		create: func() error { return nil },
+		evaluate: func() (*Result, error) {
+			pod, err := v.getPod(name)
+			if err != nil {
+				return nil, err
+			}
+			switch pod.Status.Phase {
+			case corev1.PodSucceeded:
+				return &Result{Status: StatusPass, Message: fmt.Sprintf("resolved %s", dnsLookupName)}, nil
+			case corev1.PodFailed:
+				return &Result{Status: StatusFail, Message: fmt.Sprintf("unable to resolve %s", dnsLookupName)}, nil
+			}
+			if reason := imagePullFailure(pod); reason != "" {
+				return &Result{Status: StatusFail, Message: reason}, nil
+			}
+			return nil, nil
+		},
+	}
+	return []probe{scheduling, dns}
+}
+
+// ecrPullProbe probes that the nodes can pull an image from ECR
+func (v *Verifier) ecrPullProbe() probe {
+	const name = "ecr-pull"
+	return probe{
+		name:   CheckECRPull,
+		create: v.createPod(v.pod(name, corev1.Container{Image: v.options.ECRImage})),
+		evaluate: func() (*Result, error) {
+			pod, err := v.getPod(name)
+			if err != nil {
+				return nil, err
+			}
+			if reason := imagePullFailure(pod); reason != "" {
+				return &Result{Status: StatusFail, Message: reason}, nil
+			}
+			if pod.Status.Phase == corev1.PodRunning || pod.Status.Phase == corev1.PodSucceeded {
+				return &Result{Status: StatusPass, Message: fmt.Sprintf("pulled %s", v.options.ECRImage)}, nil
+			}
+			return nil, nil
+		},
+	}
+}
+
+// volumeProvisioningProbe probes that the default storage class provisions a volume a pod
+// can write to
+func (v *Verifier) volumeProvisioningProbe() (probe, bool, error) {
+	storageClasses, err := v.clientSet.StorageV1().StorageClasses().List(metav1.ListOptions{})
+	if err != nil {
+		return probe{}, false, errors.Wrap(err, "listing storage classes")
+	}
+	var defaultClass string
+	for _, storageClass := range storageClasses.Items {
+		for _, annotation := range defaultStorageClassAnnos {
+			if storageClass.Annotations[annotation] == "true" {
+				defaultClass = storageClass.Name
+			}
+		}
+	}
+	if defaultClass == "" {
+		return probe{}, false, nil
+	}
+
+	const name = "volume"
+	claim := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      volumeClaimName,
+			Namespace: v.options.Namespace,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+			},
+		},
+	}
+	pod := v.pod(name, corev1.Container{
+		Image:        v.options.Image,
+		Command:      []string{"sh", "-c", "echo ok > /data/ok"},
+		VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data"}},
+	})
+	pod.Spec.Volumes = []corev1.Volume{
+		{
+			Name: "data",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: volumeClaimName},
+			},
+		},
+	}
+
+	return probe{
+		name: CheckVolumeProvisioning,
+		create: func() error {
+			if _, err := v.clientSet.CoreV1().PersistentVolumeClaims(v.options.Namespace).Create(claim); err != nil {
+				return errors.Wrapf(err, "creating persistent volume claim %q", claim.Name)
+			}
+			return v.createPod(pod)()
+		},
+		evaluate: func() (*Result, error) {
+			pod, err := v.getPod(name)
+			if err != nil {
+				return nil, err
+			}
+			switch pod.Status.Phase {
+			case corev1.PodSucceeded:
+				return &Result{Status: StatusPass, Message: fmt.Sprintf("storage class %q provisioned a volume", defaultClass)}, nil
+			case corev1.PodFailed:
+				return &Result{Status: StatusFail, Message: fmt.Sprintf("unable to write to the volume storage class %q provisioned", defaultClass)}, nil
+			}
+			return nil, nil
+		},
+	}, true, nil
+}
+
+// imagePullFailure returns why the image of a pod can't be pulled, if it can't
+func imagePullFailure(pod *corev1.Pod) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if waiting := status.State.Waiting; waiting != nil && (waiting.Reason == "ErrImagePull" || waiting.Reason == "ImagePullBackOff") {
+			return fmt.Sprintf("unable to pull %s: %s", status.Image, waiting.Message)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,135 @@
+package verify_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	. "github.com/weaveworks/eksctl/pkg/verify"
+)
+
+var _ = Describe("Verify", func() {
+	const ecrImage = "602401143452.dkr.ecr.us-west-2.amazonaws.com/eks/pause:3.1-eksbuild.1"
+
+	var clientSet *fake.Clientset
+
+	node := func(name, nodeGroup, zone string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					api.NodeGroupNameLabel:                   nodeGroup,
+					"failure-domain.beta.kubernetes.io/zone": zone,
+				},
+			},
+		}
+	}
+
+	// completePods makes the pods created afterwards run to completion, or fail to pull their
+	// images when pullFails
+	completePods := func(pullFails bool) {
+		clientSet.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			pod := action.(k8stesting.CreateAction).GetObject().(*corev1.Pod)
+			pod.Spec.NodeName = "node-1"
+			if pullFails && pod.Spec.Containers[0].Image == ecrImage {
+				pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+					Image: ecrImage,
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "access denied"}},
+				}}
+				return false, nil, nil
+			}
+			pod.Status.Phase = corev1.PodSucceeded
+			return false, nil, nil
+		})
+	}
+
+	BeforeEach(func() {
+		clientSet = fake.NewSimpleClientset(
+			node("node-1", "ng-1", "us-west-2a"),
+			node("node-2", "ng-1", "us-west-2b"),
+			node("node-3", "ng-1", "us-west-2b"),
+			&storagev1.StorageClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "gp2",
+					Annotations: map[string]string{"storageclass.kubernetes.io/is-default-class": "true"},
+				},
+				Provisioner: "kubernetes.io/aws-ebs",
+			},
+		)
+	})
+
+	It("runs the checks in each nodegroup and availability zone and cleans up", func() {
+		completePods(false)
+		report, err := NewVerifier(clientSet, Options{ECRImage: ecrImage}).Verify("cluster-1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Passed).To(BeTrue())
+
+		var checks []string
+		for _, result := range report.Results {
+			Expect(result.Status).To(Equal(StatusPass), result.Name)
+			checks = append(checks, result.Name+" "+result.Target)
+		}
+		Expect(checks).To(ConsistOf(
+			"scheduling ng-1/us-west-2a", "dns ng-1/us-west-2a",
+			"scheduling ng-1/us-west-2b", "dns ng-1/us-west-2b",
+			"ecr-pull ", "volume-provisioning ",
+		))
+
+		_, err = clientSet.CoreV1().Namespaces().Get(DefaultNamespace, metav1.GetOptions{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("pins the test pods to the nodes of a nodegroup in an availability zone", func() {
+		completePods(false)
+		_, err := NewVerifier(clientSet, Options{}).Verify("cluster-1")
+		Expect(err).ToNot(HaveOccurred())
+
+		var selectors []map[string]string
+		for _, action := range clientSet.Actions() {
+			if create, ok := action.(k8stesting.CreateAction); ok && action.GetResource().Resource == "pods" {
+				pod := create.GetObject().(*corev1.Pod)
+				Expect(pod.Spec.Containers[0].Image).To(Equal(DefaultImage))
+				if pod.Spec.NodeSelector != nil {
+					selectors = append(selectors, pod.Spec.NodeSelector)
+				}
+			}
+		}
+		Expect(selectors).To(ConsistOf(
+			map[string]string{api.NodeGroupNameLabel: "ng-1", "failure-domain.beta.kubernetes.io/zone": "us-west-2a"},
+			map[string]string{api.NodeGroupNameLabel: "ng-1", "failure-domain.beta.kubernetes.io/zone": "us-west-2b"},
+		))
+	})
+
+	It("fails when the nodes can't pull from ECR", func() {
+		completePods(true)
+		report, err := NewVerifier(clientSet, Options{ECRImage: ecrImage}).Verify("cluster-1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Passed).To(BeFalse())
+		for _, result := range report.Results {
+			if result.Name == CheckECRPull {
+				Expect(result.Status).To(Equal(StatusFail))
+				Expect(result.Message).To(ContainSubstring("access denied"))
+			}
+		}
+	})
+
+	It("skips volume provisioning without a default storage class", func() {
+		Expect(clientSet.StorageV1().StorageClasses().Delete("gp2", &metav1.DeleteOptions{})).To(Succeed())
+		completePods(false)
+		report, err := NewVerifier(clientSet, Options{}).Verify("cluster-1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Passed).To(BeTrue())
+		Expect(report.Results).To(ContainElement(Result{
+			Name:    CheckVolumeProvisioning,
+			Status:  StatusSkip,
+			Message: "the cluster has no default storage class",
+		}))
+	})
+})
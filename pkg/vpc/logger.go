@@ -0,0 +1,6 @@
+package vpc
+
+import log "github.com/weaveworks/eksctl/pkg/logger"
+
+// logger logs messages of the vpc subsystem
+var logger = log.WithScope("vpc")
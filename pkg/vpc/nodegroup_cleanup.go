@@ -0,0 +1,183 @@
+package vpc
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+const errCodeLaunchTemplateNameNotFound = "InvalidLaunchTemplateName.NotFoundException"
+
+func nodeGroupStackName(clusterName, nodeGroupName string) string {
+	return fmt.Sprintf("eksctl-%s-nodegroup-%s", clusterName, nodeGroupName)
+}
+
+// CleanupNodeGroupResources releases the resources that prevent the stack of a nodegroup from
+// being deleted, or that are left behind by it: rules referencing the security group of the
+// nodegroup in other security groups, dangling ENIs in that security group and the launch template
+func CleanupNodeGroupResources(ec2API ec2iface.EC2API, spec *api.ClusterConfig, nodeGroupName string) error {
+	securityGroupIDs, err := findNodeGroupSecurityGroups(ec2API, spec, nodeGroupName)
+	if err != nil {
+		return err
+	}
+	for _, sgID := range securityGroupIDs {
+		if err := revokeRulesReferencingSecurityGroup(ec2API, spec, sgID); err != nil {
+			return err
+		}
+		if err := deleteDanglingENIsInSecurityGroup(ec2API, spec, sgID); err != nil {
+			return err
+		}
+	}
+	return DeleteNodeGroupLaunchTemplate(ec2API, spec, nodeGroupName)
+}
+
+// DeleteNodeGroupLaunchTemplate deletes the launch template of a nodegroup, along with all of
+// its versions, if it still exists
+func DeleteNodeGroupLaunchTemplate(ec2API ec2iface.EC2API, spec *api.ClusterConfig, nodeGroupName string) error {
+	name := nodeGroupStackName(spec.Metadata.Name, nodeGroupName)
+	_, err := ec2API.DeleteLaunchTemplate(&ec2.DeleteLaunchTemplateInput{
+		LaunchTemplateName: &name,
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == errCodeLaunchTemplateNameNotFound {
+			return nil
+		}
+		return errors.Wrapf(err, "unable to delete launch template %q", name)
+	}
+	logger.Info("deleted leftover launch template %q of nodegroup %q", name, nodeGroupName)
+	return nil
+}
+
+func findNodeGroupSecurityGroups(ec2API ec2iface.EC2API, spec *api.ClusterConfig, nodeGroupName string) ([]string, error) {
+	input := &ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: []*string{&spec.VPC.ID},
+			},
+			{
+				Name:   aws.String("tag:aws:cloudformation:stack-name"),
+				Values: []*string{aws.String(nodeGroupStackName(spec.Metadata.Name, nodeGroupName))},
+			},
+		},
+	}
+	output, err := ec2API.DescribeSecurityGroups(input)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the security groups of nodegroup %q", nodeGroupName)
+	}
+	var securityGroupIDs []string
+	for _, sg := range output.SecurityGroups {
+		securityGroupIDs = append(securityGroupIDs, *sg.GroupId)
+	}
+	return securityGroupIDs, nil
+}
+
+// revokeRulesReferencingSecurityGroup revokes the ingress and egress rules of other security
+// groups that allow traffic from or to sgID, as they prevent sgID from being deleted
+func revokeRulesReferencingSecurityGroup(ec2API ec2iface.EC2API, spec *api.ClusterConfig, sgID string) error {
+	for _, filterName := range []string{"ip-permission.group-id", "egress.ip-permission.group-id"} {
+		input := &ec2.DescribeSecurityGroupsInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String("vpc-id"),
+					Values: []*string{&spec.VPC.ID},
+				},
+				{
+					Name:   aws.String(filterName),
+					Values: []*string{&sgID},
+				},
+			},
+		}
+		output, err := ec2API.DescribeSecurityGroups(input)
+		if err != nil {
+			return errors.Wrapf(err, "unable to list security groups referencing %q", sgID)
+		}
+		for _, sg := range output.SecurityGroups {
+			if *sg.GroupId == sgID {
+				continue
+			}
+			if ingress := permissionsReferencing(sg.IpPermissions, sgID); len(ingress) > 0 {
+				if _, err := ec2API.RevokeSecurityGroupIngress(&ec2.RevokeSecurityGroupIngressInput{
+					GroupId:       sg.GroupId,
+					IpPermissions: ingress,
+				}); err != nil {
+					return errors.Wrapf(err, "unable to revoke ingress rules referencing %q from security group %q", sgID, *sg.GroupId)
+				}
+				logger.Info("revoked %d ingress rule(s) referencing %q from security group %q", len(ingress), sgID, *sg.GroupId)
+			}
+			if egress := permissionsReferencing(sg.IpPermissionsEgress, sgID); len(egress) > 0 {
+				if _, err := ec2API.RevokeSecurityGroupEgress(&ec2.RevokeSecurityGroupEgressInput{
+					GroupId:       sg.GroupId,
+					IpPermissions: egress,
+				}); err != nil {
+					return errors.Wrapf(err, "unable to revoke egress rules referencing %q from security group %q", sgID, *sg.GroupId)
+				}
+				logger.Info("revoked %d egress rule(s) referencing %q from security group %q", len(egress), sgID, *sg.GroupId)
+			}
+		}
+	}
+	return nil
+}
+
+// permissionsReferencing returns the parts of permissions that reference sgID
+func permissionsReferencing(permissions []*ec2.IpPermission, sgID string) []*ec2.IpPermission {
+	var matching []*ec2.IpPermission
+	for _, permission := range permissions {
+		for _, pair := range permission.UserIdGroupPairs {
+			if aws.StringValue(pair.GroupId) != sgID {
+				continue
+			}
+			matching = append(matching, &ec2.IpPermission{
+				IpProtocol:       permission.IpProtocol,
+				FromPort:         permission.FromPort,
+				ToPort:           permission.ToPort,
+				UserIdGroupPairs: []*ec2.UserIdGroupPair{{GroupId: pair.GroupId, UserId: pair.UserId}},
+			})
+		}
+	}
+	return matching
+}
+
+func deleteDanglingENIsInSecurityGroup(ec2API ec2iface.EC2API, spec *api.ClusterConfig, sgID string) error {
+	input := &ec2.DescribeNetworkInterfacesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: []*string{&spec.VPC.ID},
+			},
+			{
+				Name:   aws.String("group-id"),
+				Values: []*string{&sgID},
+			},
+			{
+				Name:   aws.String("status"),
+				Values: []*string{aws.String("available")},
+			},
+		},
+	}
+	var eniIDs []string
+	err := ec2API.DescribeNetworkInterfacesPages(input, func(output *ec2.DescribeNetworkInterfacesOutput, lastPage bool) bool {
+		for _, eni := range output.NetworkInterfaces {
+			eniIDs = append(eniIDs, *eni.NetworkInterfaceId)
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return errors.Wrapf(err, "unable to list dangling network interfaces in security group %q", sgID)
+	}
+	for _, eniID := range eniIDs {
+		if _, err := ec2API.DeleteNetworkInterface(&ec2.DeleteNetworkInterfaceInput{
+			NetworkInterfaceId: aws.String(eniID),
+		}); err != nil {
+			return errors.Wrapf(err, "unable to delete network interface %q", eniID)
+		}
+		logger.Info("deleted dangling network interface %q", eniID)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package vpc
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	"github.com/weaveworks/eksctl/pkg/testutils/mockprovider"
+)
+
+var _ = Describe("VPC - Clean up nodegroup resources", func() {
+	var (
+		p   *mockprovider.MockProvider
+		cfg *api.ClusterConfig
+	)
+
+	describeSecurityGroupsWithFilter := func(name, value string) interface{} {
+		return mock.MatchedBy(func(input *ec2.DescribeSecurityGroupsInput) bool {
+			return *input.Filters[1].Name == name && *input.Filters[1].Values[0] == value
+		})
+	}
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg = api.NewClusterConfig()
+		cfg.Metadata.Name = "cluster-1"
+		cfg.VPC.ID = "vpc-1"
+	})
+
+	It("revokes rules referencing the nodegroup, deletes its dangling ENIs and its launch template", func() {
+		p.MockEC2().On("DescribeSecurityGroups", describeSecurityGroupsWithFilter("tag:aws:cloudformation:stack-name", "eksctl-cluster-1-nodegroup-ng-1")).
+			Return(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: []*ec2.SecurityGroup{{GroupId: aws.String("sg-ng")}}}, nil)
+		p.MockEC2().On("DescribeSecurityGroups", describeSecurityGroupsWithFilter("ip-permission.group-id", "sg-ng")).
+			Return(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: []*ec2.SecurityGroup{{
+				GroupId: aws.String("sg-shared"),
+				IpPermissions: []*ec2.IpPermission{
+					{
+						IpProtocol:       aws.String("tcp"),
+						FromPort:         aws.Int64(443),
+						ToPort:           aws.Int64(443),
+						UserIdGroupPairs: []*ec2.UserIdGroupPair{{GroupId: aws.String("sg-other")}, {GroupId: aws.String("sg-ng")}},
+					},
+					{
+						IpProtocol: aws.String("-1"),
+						IpRanges:   []*ec2.IpRange{{CidrIp: aws.String("10.0.0.0/8")}},
+					},
+				},
+			}}}, nil)
+		p.MockEC2().On("DescribeSecurityGroups", describeSecurityGroupsWithFilter("egress.ip-permission.group-id", "sg-ng")).
+			Return(&ec2.DescribeSecurityGroupsOutput{}, nil)
+		p.MockEC2().On("RevokeSecurityGroupIngress", mock.Anything).Return(&ec2.RevokeSecurityGroupIngressOutput{}, nil)
+		p.MockEC2().On("DescribeNetworkInterfacesPages", mock.MatchedBy(func(input *ec2.DescribeNetworkInterfacesInput) bool {
+			return *input.Filters[1].Values[0] == "sg-ng"
+		}), mock.Anything).Run(func(args mock.Arguments) {
+			pager := args.Get(1).(func(*ec2.DescribeNetworkInterfacesOutput, bool) bool)
+			pager(&ec2.DescribeNetworkInterfacesOutput{NetworkInterfaces: []*ec2.NetworkInterface{{NetworkInterfaceId: aws.String("eni-1")}}}, true)
+		}).Return(nil)
+		p.MockEC2().On("DeleteNetworkInterface", mock.Anything).Return(&ec2.DeleteNetworkInterfaceOutput{}, nil)
+		p.MockEC2().On("DeleteLaunchTemplate", mock.Anything).Return(&ec2.DeleteLaunchTemplateOutput{}, nil)
+
+		Expect(CleanupNodeGroupResources(p.EC2(), cfg, "ng-1")).To(Succeed())
+
+		p.MockEC2().AssertCalled(GinkgoT(), "RevokeSecurityGroupIngress", &ec2.RevokeSecurityGroupIngressInput{
+			GroupId: aws.String("sg-shared"),
+			IpPermissions: []*ec2.IpPermission{{
+				IpProtocol:       aws.String("tcp"),
+				FromPort:         aws.Int64(443),
+				ToPort:           aws.Int64(443),
+				UserIdGroupPairs: []*ec2.UserIdGroupPair{{GroupId: aws.String("sg-ng")}},
+			}},
+		})
+		p.MockEC2().AssertNotCalled(GinkgoT(), "RevokeSecurityGroupEgress", mock.Anything)
+		p.MockEC2().AssertCalled(GinkgoT(), "DeleteNetworkInterface", &ec2.DeleteNetworkInterfaceInput{NetworkInterfaceId: aws.String("eni-1")})
+		p.MockEC2().AssertCalled(GinkgoT(), "DeleteLaunchTemplate", &ec2.DeleteLaunchTemplateInput{LaunchTemplateName: aws.String("eksctl-cluster-1-nodegroup-ng-1")})
+	})
+
+	It("ignores launch templates that were already deleted", func() {
+		p.MockEC2().On("DeleteLaunchTemplate", mock.Anything).Return(nil, awserr.New(errCodeLaunchTemplateNameNotFound, "not found", nil))
+
+		Expect(DeleteNodeGroupLaunchTemplate(p.EC2(), cfg, "ng-1")).To(Succeed())
+	})
+})
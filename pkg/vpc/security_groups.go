@@ -0,0 +1,170 @@
+package vpc
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+)
+
+const (
+	kubeletPort   = 10250
+	apiServerPort = 443
+)
+
+// securityGroupFlow is traffic between two sets of security groups that a rule of the
+// destination groups must allow in, and a rule of the source groups must allow out
+type securityGroupFlow struct {
+	description string
+	// port is a TCP port, 0 is all traffic
+	port         int64
+	sources      []string
+	destinations []string
+}
+
+// ValidateSecurityGroups checks that the security groups of spec.VPC.SecurityGroupIDs belong to
+// the VPC of the cluster and, for the unmanaged nodegroups that have no security group eksctl
+// adds rules to, that they and the security groups of the nodegroup allow the traffic between
+// the control plane and the nodes, and between the nodes
+func ValidateSecurityGroups(provider api.ClusterProvider, spec *api.ClusterConfig, nodeGroups []*api.NodeGroup) error {
+	controlPlaneSGs := spec.VPC.SecurityGroupIDs
+	if len(controlPlaneSGs) == 0 {
+		return nil
+	}
+	if spec.VPC.ID == "" {
+		return errors.New("vpc.securityGroupIDs can only be used with an existing VPC")
+	}
+
+	ids := append([]string{}, controlPlaneSGs...)
+	var unmanagedNodeGroups []*api.NodeGroup
+	for _, ng := range nodeGroups {
+		// the shared node security group allows all traffic with the cluster security group,
+		// which EKS attaches to the control plane as well
+		if ng.SecurityGroups == nil || !api.IsDisabled(ng.SecurityGroups.WithLocal) || !api.IsDisabled(ng.SecurityGroups.WithShared) {
+			continue
+		}
+		if len(ng.SecurityGroups.AttachIDs) == 0 {
+			return fmt.Errorf("nodegroup %q has no security groups, set securityGroups.attachIDs or enable securityGroups.withLocal", ng.Name)
+		}
+		unmanagedNodeGroups = append(unmanagedNodeGroups, ng)
+		ids = append(ids, ng.SecurityGroups.AttachIDs...)
+	}
+
+	output, err := provider.EC2().DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+		GroupIds: aws.StringSlice(ids),
+	})
+	if err != nil {
+		return errors.Wrap(err, "describing the security groups of the control plane and of nodegroups")
+	}
+	securityGroups := map[string]*ec2.SecurityGroup{}
+	for _, sg := range output.SecurityGroups {
+		groupID := aws.StringValue(sg.GroupId)
+		if vpcID := aws.StringValue(sg.VpcId); vpcID != spec.VPC.ID {
+			return fmt.Errorf("security group %q belongs to VPC %q, not to the VPC of the cluster %q", groupID, vpcID, spec.VPC.ID)
+		}
+		securityGroups[groupID] = sg
+	}
+	for _, id := range ids {
+		if _, ok := securityGroups[id]; !ok {
+			return fmt.Errorf("security group %q was not found", id)
+		}
+	}
+
+	var vpcCIDR *net.IPNet
+	if spec.VPC.CIDR != nil {
+		vpcCIDR = &spec.VPC.CIDR.IPNet
+	}
+	for _, ng := range unmanagedNodeGroups {
+		nodeSGs := ng.SecurityGroups.AttachIDs
+		var missing []string
+		for _, flow := range []securityGroupFlow{
+			{description: "control plane to kubelet", port: kubeletPort, sources: controlPlaneSGs, destinations: nodeSGs},
+			{description: "nodes to API server", port: apiServerPort, sources: nodeSGs, destinations: controlPlaneSGs},
+			{description: "between nodes", sources: nodeSGs, destinations: nodeSGs},
+		} {
+			missing = append(missing, flow.missingRules(securityGroups, vpcCIDR)...)
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("the security groups of nodegroup %q and vpc.securityGroupIDs are missing rules, eksctl adds no rules to them when securityGroups.withLocal and securityGroups.withShared are disabled:\n- %s",
+				ng.Name, strings.Join(missing, "\n- "))
+		}
+	}
+	return nil
+}
+
+// missingRules describes the ingress and egress rules the flow lacks, with a command that adds them
+func (f securityGroupFlow) missingRules(securityGroups map[string]*ec2.SecurityGroup, vpcCIDR *net.IPNet) []string {
+	var missing []string
+	traffic := "all traffic"
+	protocol := "IpProtocol=-1"
+	if f.port != 0 {
+		traffic = fmt.Sprintf("TCP port %d", f.port)
+		protocol = fmt.Sprintf("IpProtocol=tcp,FromPort=%d,ToPort=%d", f.port, f.port)
+	}
+
+	allowed := func(groups []string, peers []string, permissions func(*ec2.SecurityGroup) []*ec2.IpPermission) bool {
+		for _, id := range groups {
+			for _, permission := range permissions(securityGroups[id]) {
+				if permissionAllows(permission, f.port, peers, vpcCIDR) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	if !allowed(f.destinations, f.sources, func(sg *ec2.SecurityGroup) []*ec2.IpPermission { return sg.IpPermissions }) {
+		missing = append(missing, fmt.Sprintf("ingress of %s to one of %s from one of %s (%s), e.g. aws ec2 authorize-security-group-ingress --group-id %s --ip-permissions '%s,UserIdGroupPairs=[{GroupId=%s}]'",
+			traffic, strings.Join(f.destinations, ", "), strings.Join(f.sources, ", "), f.description, f.destinations[0], protocol, f.sources[0]))
+	}
+	if !allowed(f.sources, f.destinations, func(sg *ec2.SecurityGroup) []*ec2.IpPermission { return sg.IpPermissionsEgress }) {
+		missing = append(missing, fmt.Sprintf("egress of %s from one of %s to one of %s (%s), e.g. aws ec2 authorize-security-group-egress --group-id %s --ip-permissions '%s,UserIdGroupPairs=[{GroupId=%s}]'",
+			traffic, strings.Join(f.sources, ", "), strings.Join(f.destinations, ", "), f.description, f.sources[0], protocol, f.destinations[0]))
+	}
+	return missing
+}
+
+// permissionAllows reports whether the rule allows the TCP port, or all traffic when port is 0,
+// from or to one of peers or a CIDR that includes the VPC
+func permissionAllows(permission *ec2.IpPermission, port int64, peers []string, vpcCIDR *net.IPNet) bool {
+	switch protocol := aws.StringValue(permission.IpProtocol); {
+	case protocol == "-1":
+	case port == 0:
+		return false
+	case protocol == "tcp" || protocol == "6":
+		if aws.Int64Value(permission.FromPort) > port || aws.Int64Value(permission.ToPort) < port {
+			return false
+		}
+	default:
+		return false
+	}
+
+	for _, pair := range permission.UserIdGroupPairs {
+		for _, peer := range peers {
+			if aws.StringValue(pair.GroupId) == peer {
+				return true
+			}
+		}
+	}
+	for _, ipRange := range permission.IpRanges {
+		_, cidr, err := net.ParseCIDR(aws.StringValue(ipRange.CidrIp))
+		if err != nil {
+			continue
+		}
+		ones, _ := cidr.Mask.Size()
+		if ones == 0 || (vpcCIDR != nil && cidr.Contains(vpcCIDR.IP) && ones <= maskSize(vpcCIDR)) {
+			return true
+		}
+	}
+	return false
+}
+
+func maskSize(cidr *net.IPNet) int {
+	ones, _ := cidr.Mask.Size()
+	return ones
+}
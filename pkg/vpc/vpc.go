@@ -8,8 +8,6 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/kops/util/pkg/slice"
 
-	"github.com/kris-nova/logger"
-
 	"github.com/aws/aws-sdk-go/aws"
 	cfn "github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -295,6 +293,84 @@ func ValidateExistingPublicSubnets(provider api.ClusterProvider, subnetIDs []str
 	return nil
 }
 
+// ValidateControlPlaneSubnets checks that the subnets of spec.VPC.ControlPlaneSubnetIDs belong to
+// the VPC of the cluster and span at least MinRequiredSubnets availability zones, as EKS requires
+func ValidateControlPlaneSubnets(provider api.ClusterProvider, spec *api.ClusterConfig) error {
+	subnetIDs := spec.VPC.ControlPlaneSubnetIDs
+	if len(subnetIDs) == 0 {
+		return nil
+	}
+	if spec.VPC.ID == "" {
+		return errors.New("vpc.controlPlaneSubnetIDs can only be used with an existing VPC")
+	}
+	subnets, err := describeSubnets(provider, subnetIDs...)
+	if err != nil {
+		return errors.Wrap(err, "describing vpc.controlPlaneSubnetIDs")
+	}
+	zones := map[string]struct{}{}
+	for _, subnet := range subnets {
+		if vpcID := aws.StringValue(subnet.VpcId); vpcID != spec.VPC.ID {
+			return fmt.Errorf("control plane subnet %q belongs to VPC %q, not to the VPC of the cluster %q", aws.StringValue(subnet.SubnetId), vpcID, spec.VPC.ID)
+		}
+		zones[aws.StringValue(subnet.AvailabilityZone)] = struct{}{}
+	}
+	if len(zones) < api.MinRequiredSubnets {
+		return fmt.Errorf("vpc.controlPlaneSubnetIDs must span at least %d availability zones", api.MinRequiredSubnets)
+	}
+	return nil
+}
+
+// ValidateClusterVPCConfigUpdate checks the subnets and security groups the control plane of an
+// existing cluster is moved to: they must belong to the VPC of the cluster, and the subnets must
+// span at least MinRequiredSubnets availability zones, all among the zones of the current subnets,
+// as EKS doesn't move the control plane to other zones
+func ValidateClusterVPCConfigUpdate(provider api.ClusterProvider, vpcID string, currentSubnetIDs, subnetIDs, securityGroupIDs []string) error {
+	if len(subnetIDs) > 0 {
+		currentSubnets, err := describeSubnets(provider, currentSubnetIDs...)
+		if err != nil {
+			return errors.Wrap(err, "describing the current subnets of the cluster")
+		}
+		currentZones := sets.NewString()
+		for _, subnet := range currentSubnets {
+			currentZones.Insert(aws.StringValue(subnet.AvailabilityZone))
+		}
+
+		subnets, err := describeSubnets(provider, subnetIDs...)
+		if err != nil {
+			return errors.Wrap(err, "describing the subnets")
+		}
+		zones := sets.NewString()
+		for _, subnet := range subnets {
+			subnetID, zone := aws.StringValue(subnet.SubnetId), aws.StringValue(subnet.AvailabilityZone)
+			if subnetVPCID := aws.StringValue(subnet.VpcId); subnetVPCID != vpcID {
+				return fmt.Errorf("subnet %q belongs to VPC %q, not to the VPC of the cluster %q", subnetID, subnetVPCID, vpcID)
+			}
+			if !currentZones.Has(zone) {
+				return fmt.Errorf("subnet %q is in availability zone %q, the control plane can only use subnets in %s", subnetID, zone, strings.Join(currentZones.List(), ", "))
+			}
+			zones.Insert(zone)
+		}
+		if zones.Len() < api.MinRequiredSubnets {
+			return fmt.Errorf("the subnets must span at least %d availability zones", api.MinRequiredSubnets)
+		}
+	}
+
+	if len(securityGroupIDs) > 0 {
+		output, err := provider.EC2().DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+			GroupIds: aws.StringSlice(securityGroupIDs),
+		})
+		if err != nil {
+			return errors.Wrap(err, "describing the security groups")
+		}
+		for _, sg := range output.SecurityGroups {
+			if sgVPCID := aws.StringValue(sg.VpcId); sgVPCID != vpcID {
+				return fmt.Errorf("security group %q belongs to VPC %q, not to the VPC of the cluster %q", aws.StringValue(sg.GroupId), sgVPCID, vpcID)
+			}
+		}
+	}
+	return nil
+}
+
 // EnsureMapPublicIPOnLaunchEnabled will enable MapPublicIpOnLaunch in EC2 for all given subnet IDs
 func EnsureMapPublicIPOnLaunchEnabled(provider api.ClusterProvider, subnetIDs []string) error {
 	if len(subnetIDs) == 0 {
@@ -704,3 +704,197 @@ var _ = Describe("VPC - Import all subnets", func() {
 		}),
 	)
 })
+
+var _ = Describe("VPC - Validate control plane subnets", func() {
+	var cfg *api.ClusterConfig
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg = api.NewClusterConfig()
+		cfg.VPC.ID = "vpc1"
+		cfg.VPC.ControlPlaneSubnetIDs = []string{"cp1", "cp2"}
+	})
+
+	mockSubnets := func(subnets ...*ec2.Subnet) {
+		p.MockEC2().On("DescribeSubnets", MatchedBy(func(input *ec2.DescribeSubnetsInput) bool {
+			return len(input.SubnetIds) == 2
+		})).Return(&ec2.DescribeSubnetsOutput{Subnets: subnets}, nil)
+	}
+
+	subnet := func(id, vpcID, az string) *ec2.Subnet {
+		return &ec2.Subnet{
+			SubnetId:         strings.Pointer(id),
+			VpcId:            strings.Pointer(vpcID),
+			AvailabilityZone: strings.Pointer(az),
+		}
+	}
+
+	It("accepts subnets of the VPC in different AZs", func() {
+		mockSubnets(subnet("cp1", "vpc1", "az1"), subnet("cp2", "vpc1", "az2"))
+		Expect(ValidateControlPlaneSubnets(p, cfg)).To(Succeed())
+	})
+
+	It("rejects subnets of another VPC", func() {
+		mockSubnets(subnet("cp1", "vpc1", "az1"), subnet("cp2", "vpc2", "az2"))
+		Expect(ValidateControlPlaneSubnets(p, cfg)).To(MatchError(`control plane subnet "cp2" belongs to VPC "vpc2", not to the VPC of the cluster "vpc1"`))
+	})
+
+	It("rejects subnets in a single AZ", func() {
+		mockSubnets(subnet("cp1", "vpc1", "az1"), subnet("cp2", "vpc1", "az1"))
+		Expect(ValidateControlPlaneSubnets(p, cfg)).To(MatchError("vpc.controlPlaneSubnetIDs must span at least 2 availability zones"))
+	})
+
+	It("requires an existing VPC", func() {
+		cfg.VPC.ID = ""
+		Expect(ValidateControlPlaneSubnets(p, cfg)).To(MatchError("vpc.controlPlaneSubnetIDs can only be used with an existing VPC"))
+	})
+})
+
+var _ = Describe("VPC - Validate security groups", func() {
+	var (
+		cfg *api.ClusterConfig
+		ng  *api.NodeGroup
+	)
+
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		cfg = api.NewClusterConfig()
+		cfg.VPC.ID = "vpc1"
+		cfg.VPC.SecurityGroupIDs = []string{"sg-cp"}
+		ng = cfg.NewNodeGroup()
+		ng.Name = "ng-1"
+		ng.SecurityGroups.AttachIDs = []string{"sg-node"}
+		ng.SecurityGroups.WithLocal = api.Disabled()
+		ng.SecurityGroups.WithShared = api.Disabled()
+	})
+
+	rule := func(protocol string, port int64, groupID string) *ec2.IpPermission {
+		return &ec2.IpPermission{
+			IpProtocol:       strings.Pointer(protocol),
+			FromPort:         &port,
+			ToPort:           &port,
+			UserIdGroupPairs: []*ec2.UserIdGroupPair{{GroupId: strings.Pointer(groupID)}},
+		}
+	}
+
+	allEgress := []*ec2.IpPermission{{
+		IpProtocol: strings.Pointer("-1"),
+		IpRanges:   []*ec2.IpRange{{CidrIp: strings.Pointer("0.0.0.0/0")}},
+	}}
+
+	mockSecurityGroups := func(nodeIngress ...*ec2.IpPermission) {
+		p.MockEC2().On("DescribeSecurityGroups", MatchedBy(func(input *ec2.DescribeSecurityGroupsInput) bool {
+			return len(input.GroupIds) == 2
+		})).Return(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: []*ec2.SecurityGroup{
+			{
+				GroupId:             strings.Pointer("sg-cp"),
+				VpcId:               strings.Pointer("vpc1"),
+				IpPermissions:       []*ec2.IpPermission{rule("tcp", 443, "sg-node")},
+				IpPermissionsEgress: allEgress,
+			},
+			{
+				GroupId:             strings.Pointer("sg-node"),
+				VpcId:               strings.Pointer("vpc1"),
+				IpPermissions:       nodeIngress,
+				IpPermissionsEgress: allEgress,
+			},
+		}}, nil)
+	}
+
+	It("accepts security groups that allow the traffic of the cluster", func() {
+		mockSecurityGroups(rule("tcp", 10250, "sg-cp"), rule("-1", 0, "sg-node"))
+		Expect(ValidateSecurityGroups(p, cfg, []*api.NodeGroup{ng})).To(Succeed())
+	})
+
+	It("reports the missing rules", func() {
+		mockSecurityGroups(rule("-1", 0, "sg-node"))
+		err := ValidateSecurityGroups(p, cfg, []*api.NodeGroup{ng})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`the security groups of nodegroup "ng-1" and vpc.securityGroupIDs are missing rules`))
+		Expect(err.Error()).To(ContainSubstring("ingress of TCP port 10250 to one of sg-node from one of sg-cp (control plane to kubelet), e.g. aws ec2 authorize-security-group-ingress --group-id sg-node"))
+		Expect(err.Error()).NotTo(ContainSubstring("nodes to API server"))
+		Expect(err.Error()).NotTo(ContainSubstring("between nodes"))
+	})
+
+	It("does not check nodegroups that have security groups managed by eksctl", func() {
+		ng.SecurityGroups.WithLocal = api.Enabled()
+		p.MockEC2().On("DescribeSecurityGroups", MatchedBy(func(input *ec2.DescribeSecurityGroupsInput) bool {
+			return len(input.GroupIds) == 1
+		})).Return(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: []*ec2.SecurityGroup{
+			{GroupId: strings.Pointer("sg-cp"), VpcId: strings.Pointer("vpc1")},
+		}}, nil)
+		Expect(ValidateSecurityGroups(p, cfg, []*api.NodeGroup{ng})).To(Succeed())
+	})
+
+	It("rejects security groups of another VPC", func() {
+		p.MockEC2().On("DescribeSecurityGroups", MatchedBy(func(input *ec2.DescribeSecurityGroupsInput) bool {
+			return len(input.GroupIds) == 1
+		})).Return(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: []*ec2.SecurityGroup{
+			{GroupId: strings.Pointer("sg-cp"), VpcId: strings.Pointer("vpc2")},
+		}}, nil)
+		Expect(ValidateSecurityGroups(p, cfg, nil)).To(MatchError(`security group "sg-cp" belongs to VPC "vpc2", not to the VPC of the cluster "vpc1"`))
+	})
+
+	It("requires an existing VPC", func() {
+		cfg.VPC.ID = ""
+		Expect(ValidateSecurityGroups(p, cfg, nil)).To(MatchError("vpc.securityGroupIDs can only be used with an existing VPC"))
+	})
+})
+
+var _ = Describe("VPC - Validate cluster VPC config update", func() {
+	BeforeEach(func() {
+		p = mockprovider.NewMockProvider()
+		subnets := map[string]*ec2.Subnet{}
+		for _, s := range []struct{ id, vpcID, az string }{
+			{"current1", "vpc1", "az1"},
+			{"current2", "vpc1", "az2"},
+			{"new1", "vpc1", "az1"},
+			{"new2", "vpc1", "az2"},
+			{"new3", "vpc1", "az3"},
+			{"new4", "vpc2", "az1"},
+		} {
+			subnets[s.id] = &ec2.Subnet{SubnetId: strings.Pointer(s.id), VpcId: strings.Pointer(s.vpcID), AvailabilityZone: strings.Pointer(s.az)}
+		}
+		p.MockEC2().On("DescribeSubnets", MatchedBy(func(input *ec2.DescribeSubnetsInput) bool {
+			return input != nil
+		})).Return(func(input *ec2.DescribeSubnetsInput) *ec2.DescribeSubnetsOutput {
+			output := &ec2.DescribeSubnetsOutput{}
+			for _, id := range input.SubnetIds {
+				output.Subnets = append(output.Subnets, subnets[*id])
+			}
+			return output
+		}, nil)
+		p.MockEC2().On("DescribeSecurityGroups", MatchedBy(func(input *ec2.DescribeSecurityGroupsInput) bool {
+			return len(input.GroupIds) > 0
+		})).Return(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: []*ec2.SecurityGroup{
+			{GroupId: strings.Pointer("sg-1"), VpcId: strings.Pointer("vpc1")},
+			{GroupId: strings.Pointer("sg-2"), VpcId: strings.Pointer("vpc2")},
+		}}, nil)
+	})
+
+	current := []string{"current1", "current2"}
+
+	It("accepts subnets of the VPC in the zones of the cluster", func() {
+		Expect(ValidateClusterVPCConfigUpdate(p, "vpc1", current, []string{"new1", "new2"}, nil)).To(Succeed())
+	})
+
+	It("rejects subnets of another VPC", func() {
+		Expect(ValidateClusterVPCConfigUpdate(p, "vpc1", current, []string{"new1", "new4"}, nil)).
+			To(MatchError(`subnet "new4" belongs to VPC "vpc2", not to the VPC of the cluster "vpc1"`))
+	})
+
+	It("rejects subnets in other zones", func() {
+		Expect(ValidateClusterVPCConfigUpdate(p, "vpc1", current, []string{"new1", "new3"}, nil)).
+			To(MatchError(`subnet "new3" is in availability zone "az3", the control plane can only use subnets in az1, az2`))
+	})
+
+	It("rejects subnets in a single zone", func() {
+		Expect(ValidateClusterVPCConfigUpdate(p, "vpc1", current, []string{"new1"}, nil)).
+			To(MatchError("the subnets must span at least 2 availability zones"))
+	})
+
+	It("rejects security groups of another VPC", func() {
+		Expect(ValidateClusterVPCConfigUpdate(p, "vpc1", current, nil, []string{"sg-1", "sg-2"})).
+			To(MatchError(`security group "sg-2" belongs to VPC "vpc2", not to the VPC of the cluster "vpc1"`))
+	})
+})